@@ -10,7 +10,13 @@ import (
 	"github.com/netbirdio/netbird/client/proto"
 )
 
-var appendFlag bool
+var (
+	appendFlag           bool
+	splitTunnelMode      string
+	splitTunnelDomains   []string
+	splitTunnelCIDRs     []string
+	splitTunnelClearFlag bool
+)
 
 var networksCMD = &cobra.Command{
 	Use:     "networks",
@@ -46,8 +52,37 @@ var routesDeselectCmd = &cobra.Command{
 	RunE:    networksDeselect,
 }
 
+var routesSplitTunnelCmd = &cobra.Command{
+	Use:   "split-tunnel network",
+	Short: "Configure the split tunnel of an exit node",
+	Long: "Configure which destinations of an exit node network go through the tunnel.\n" +
+		"In include mode only the given domains and CIDRs are routed through the exit node, " +
+		"in exclude mode everything except them is routed through the exit node.",
+	Example: "  netbird networks split-tunnel exit-node --mode include --domains example.com,*.corp.example.com --cidrs 10.0.0.0/8\n" +
+		"  netbird networks split-tunnel exit-node --mode exclude --domains streaming.example.com\n" +
+		"  netbird networks split-tunnel exit-node --clear",
+	Args: cobra.ExactArgs(1),
+	RunE: networksSplitTunnel,
+}
+
+var routesSelectionCmd = &cobra.Command{
+	Use:   "selection [network...]",
+	Short: "Explain the routing peer selection of networks",
+	Long: "Show every routing peer of the networks with its metric, connection type, latency and score, " +
+		"the chosen routing peer and the last routing peer switches with their reasons.",
+	Example: "  netbird networks selection\n  netbird networks selection route1 route2",
+	RunE:    networksSelection,
+}
+
 func init() {
 	routesSelectCmd.PersistentFlags().BoolVarP(&appendFlag, "append", "a", false, "Append to current network selection instead of replacing")
+
+	routesSplitTunnelCmd.Flags().StringVar(&splitTunnelMode, "mode", "include", "Split tunnel mode: include or exclude")
+	routesSplitTunnelCmd.Flags().StringSliceVar(&splitTunnelDomains, "domains", nil, "Domains of the split tunnel, wildcards are supported")
+	routesSplitTunnelCmd.Flags().StringSliceVar(&splitTunnelCIDRs, "cidrs", nil, "CIDRs of the split tunnel")
+	routesSplitTunnelCmd.Flags().BoolVar(&splitTunnelClearFlag, "clear", false, "Remove the split tunnel, the exit node routes all traffic")
+	routesSplitTunnelCmd.MarkFlagsMutuallyExclusive("clear", "domains")
+	routesSplitTunnelCmd.MarkFlagsMutuallyExclusive("clear", "cidrs")
 }
 
 func networksList(cmd *cobra.Command, _ []string) error {
@@ -111,6 +146,19 @@ func printDomainRoute(cmd *cobra.Command, route *proto.Network, domains []string
 
 func printNetworkRoute(cmd *cobra.Command, route *proto.Network, selectedStatus string) {
 	cmd.Printf("\n  - ID: %s\n    Network: %s\n    Status: %s\n", route.GetID(), route.GetRange(), selectedStatus)
+	if splitTunnel := route.GetSplitTunnel(); splitTunnel != nil {
+		printSplitTunnel(cmd, splitTunnel)
+	}
+}
+
+func printSplitTunnel(cmd *cobra.Command, splitTunnel *proto.SplitTunnel) {
+	cmd.Printf("    Split tunnel: %s\n", splitTunnel.GetMode())
+	if domains := splitTunnel.GetDomains(); len(domains) > 0 {
+		cmd.Printf("      Domains: %s\n", strings.Join(domains, ", "))
+	}
+	if prefixes := splitTunnel.GetPrefixes(); len(prefixes) > 0 {
+		cmd.Printf("      CIDRs: %s\n", strings.Join(prefixes, ", "))
+	}
 }
 
 func printResolvedIPs(cmd *cobra.Command, _ []string, resolvedIPs map[string]*proto.IPList) {
@@ -171,3 +219,139 @@ func networksDeselect(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func networksSplitTunnel(cmd *cobra.Command, args []string) error {
+	req := &proto.SetSplitTunnelRequest{
+		NetworkID: args[0],
+	}
+	if !splitTunnelClearFlag {
+		if len(splitTunnelDomains) == 0 && len(splitTunnelCIDRs) == 0 {
+			return fmt.Errorf("at least one of --domains or --cidrs is required, use --clear to remove the split tunnel")
+		}
+		req.SplitTunnel = &proto.SplitTunnel{
+			Mode:     splitTunnelMode,
+			Domains:  splitTunnelDomains,
+			Prefixes: splitTunnelCIDRs,
+		}
+	}
+
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := proto.NewDaemonServiceClient(conn)
+	if _, err := client.SetSplitTunnel(cmd.Context(), req); err != nil {
+		return fmt.Errorf("failed to set split tunnel: %v", status.Convert(err).Message())
+	}
+
+	if splitTunnelClearFlag {
+		cmd.Println("Split tunnel removed successfully.")
+	} else {
+		cmd.Println("Split tunnel configured successfully.")
+	}
+
+	return nil
+}
+
+func networksSelection(cmd *cobra.Command, args []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.GetRouteSelection(cmd.Context(), &proto.GetRouteSelectionRequest{NetworkIDs: args})
+	if err != nil {
+		return fmt.Errorf("failed to get route selection: %v", status.Convert(err).Message())
+	}
+
+	if len(resp.GetSelections()) == 0 {
+		cmd.Println("No active networks.")
+		return nil
+	}
+
+	cmd.Println("Route Selection:")
+	for _, selection := range resp.GetSelections() {
+		printRouteSelection(cmd, selection)
+	}
+
+	return nil
+}
+
+func printRouteSelection(cmd *cobra.Command, selection *proto.RouteSelection) {
+	peerNames := make(map[string]string, len(selection.GetCandidates()))
+	for _, candidate := range selection.GetCandidates() {
+		peerNames[candidate.GetPeer()] = routeCandidateName(candidate)
+	}
+
+	cmd.Printf("\n  - ID: %s\n    Network: %s\n", selection.GetNetworkID(), selection.GetNetwork())
+	switch {
+	case selection.GetLoadBalanced():
+		cmd.Printf("    Chosen: load-balanced over %s\n", strings.Join(selection.GetBalancedRouteIDs(), ", "))
+	case selection.GetChosenRouteID() != "":
+		cmd.Printf("    Chosen: %s\n", selection.GetChosenRouteID())
+	default:
+		cmd.Printf("    Chosen: -\n")
+	}
+
+	cmd.Printf("    Candidates:\n")
+	for _, candidate := range selection.GetCandidates() {
+		marker := " "
+		if candidate.GetChosen() {
+			marker = "*"
+		}
+		cmd.Printf("     %s %s (route %s): metric %d, %s\n", marker, routeCandidateName(candidate), candidate.GetRouteID(), candidate.GetMetric(), routeCandidateState(candidate))
+	}
+
+	if len(selection.GetSwitches()) == 0 {
+		return
+	}
+
+	cmd.Printf("    Recent switches:\n")
+	for _, routeSwitch := range selection.GetSwitches() {
+		cmd.Printf("      %s: %s -> %s, %s\n",
+			routeSwitch.GetTime().AsTime().Local().Format("2006-01-02 15:04:05"),
+			routeSwitchPeerName(peerNames, routeSwitch.GetFromPeer()),
+			routeSwitchPeerName(peerNames, routeSwitch.GetToPeer()),
+			routeSwitch.GetReason(),
+		)
+	}
+}
+
+func routeCandidateName(candidate *proto.RouteCandidate) string {
+	if candidate.GetFqdn() != "" {
+		return candidate.GetFqdn()
+	}
+	return candidate.GetPeer()
+}
+
+func routeCandidateState(candidate *proto.RouteCandidate) string {
+	if !candidate.GetAvailable() {
+		return fmt.Sprintf("unavailable (%s)", candidate.GetConnStatus())
+	}
+
+	connType := "P2P"
+	if candidate.GetRelayed() {
+		connType = "Relayed"
+	}
+
+	health := "healthy"
+	if !candidate.GetHealthy() {
+		health = "unhealthy"
+	}
+
+	return fmt.Sprintf("%s, latency %s, %s, score %.3f", connType, candidate.GetLatency().AsDuration(), health, candidate.GetScore())
+}
+
+func routeSwitchPeerName(peerNames map[string]string, peer string) string {
+	if peer == "" {
+		return "-"
+	}
+	if name, ok := peerNames[peer]; ok {
+		return name
+	}
+	return peer
+}
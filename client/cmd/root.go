@@ -146,7 +146,7 @@ func init() {
 	rootCmd.AddCommand(profileCmd)
 
 	networksCMD.AddCommand(routesListCmd)
-	networksCMD.AddCommand(routesSelectCmd, routesDeselectCmd)
+	networksCMD.AddCommand(routesSelectCmd, routesDeselectCmd, routesSplitTunnelCmd, routesSelectionCmd)
 
 	forwardingRulesCmd.AddCommand(forwardingRulesListCmd)
 
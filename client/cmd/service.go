@@ -14,6 +14,7 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/netbirdio/netbird/client/internal"
+	"github.com/netbirdio/netbird/client/internal/metrics"
 	"github.com/netbirdio/netbird/client/server"
 )
 
@@ -25,6 +26,7 @@ var serviceCmd = &cobra.Command{
 var (
 	serviceName    string
 	serviceEnvVars []string
+	metricsAddr    string
 )
 
 type program struct {
@@ -33,6 +35,7 @@ type program struct {
 	serv             *grpc.Server
 	serverInstance   *server.Server
 	serverInstanceMu sync.Mutex
+	metricsServer    *metrics.Server
 }
 
 func init() {
@@ -44,6 +47,7 @@ func init() {
 	serviceCmd.AddCommand(runCmd, startCmd, stopCmd, restartCmd, svcStatusCmd, installCmd, uninstallCmd, reconfigureCmd)
 	serviceCmd.PersistentFlags().BoolVar(&profilesDisabled, "disable-profiles", false, "Disables profiles feature. If enabled, the client will not be able to change or edit any profile. To persist this setting, use: netbird service install --disable-profiles")
 	serviceCmd.PersistentFlags().BoolVar(&updateSettingsDisabled, "disable-update-settings", false, "Disables update settings feature. If enabled, the client will not be able to change or edit any settings. To persist this setting, use: netbird service install --disable-update-settings")
+	serviceCmd.PersistentFlags().StringVar(&metricsAddr, "metrics-addr", "", "Enables the Prometheus metrics endpoint of the daemon on the given address, e.g. 127.0.0.1:9101. Metrics are accessible under host:port/metrics. To persist this setting, use: netbird service install --metrics-addr 127.0.0.1:9101")
 
 	rootCmd.PersistentFlags().StringVarP(&serviceName, "service", "s", defaultServiceName, "Netbird system service name")
 	serviceEnvDesc := `Sets extra environment variables for the service. ` +
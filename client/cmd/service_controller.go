@@ -16,6 +16,7 @@ import (
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 
+	"github.com/netbirdio/netbird/client/internal/metrics"
 	"github.com/netbirdio/netbird/client/proto"
 	"github.com/netbirdio/netbird/client/server"
 	"github.com/netbirdio/netbird/client/system"
@@ -67,8 +68,11 @@ func (p *program) Start(svc service.Service) error {
 		}
 		proto.RegisterDaemonServiceServer(p.serv, serverInstance)
 
+		metricsServer := p.startMetricsServer(serverInstance)
+
 		p.serverInstanceMu.Lock()
 		p.serverInstance = serverInstance
+		p.metricsServer = metricsServer
 		p.serverInstanceMu.Unlock()
 
 		log.Printf("started daemon server: %v", split[1])
@@ -88,6 +92,11 @@ func (p *program) Stop(srv service.Service) error {
 			log.Errorf("failed to stop daemon: %v", err)
 		}
 	}
+	if p.metricsServer != nil {
+		if err := p.metricsServer.Shutdown(p.ctx); err != nil {
+			log.Errorf("failed to stop metrics server: %v", err)
+		}
+	}
 	p.serverInstanceMu.Unlock()
 
 	p.cancel()
@@ -101,6 +110,22 @@ func (p *program) Stop(srv service.Service) error {
 	return nil
 }
 
+// startMetricsServer starts the metrics endpoint if it has been enabled with the metrics-addr flag
+func (p *program) startMetricsServer(serverInstance *server.Server) *metrics.Server {
+	if metricsAddr == "" {
+		return nil
+	}
+
+	metricsServer, err := metrics.NewServer(metricsAddr, serverInstance.MetricsCollector())
+	if err != nil {
+		log.Errorf("failed to create metrics server: %v", err)
+		return nil
+	}
+	metricsServer.Start()
+
+	return metricsServer
+}
+
 // Common setup for service control commands
 func setupServiceControlCommand(cmd *cobra.Command, ctx context.Context, cancel context.CancelFunc) (service.Service, error) {
 	SetFlagsFromEnvVars(rootCmd)
@@ -59,6 +59,10 @@ func buildServiceArguments() []string {
 		args = append(args, "--disable-update-settings")
 	}
 
+	if metricsAddr != "" {
+		args = append(args, "--metrics-addr", metricsAddr)
+	}
+
 	return args
 }
 
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/netbirdio/netbird/client/internal/speedtest"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var (
+	speedTestDuration time.Duration
+	speedTestUDPMbps  uint64
+)
+
+var speedTestCmd = &cobra.Command{
+	Use:   "speedtest <peer>",
+	Short: "Measure throughput and latency to a peer",
+	Long: "Measure the TCP and UDP throughput, the round trip time and the packet loss to a peer through the NetBird tunnel.\n\n" +
+		fmt.Sprintf("The remote peer answers on TCP and UDP port %d of its NetBird address, the access policies must allow this traffic.", speedtest.Port),
+	Example: `
+  netbird speedtest 100.64.0.2
+  netbird speedtest peer-a.netbird.cloud --duration 10s
+  netbird speedtest peer-a --udp-mbps 50`,
+	Args: cobra.ExactArgs(1),
+	RunE: speedTest,
+}
+
+func init() {
+	rootCmd.AddCommand(speedTestCmd)
+
+	speedTestCmd.Flags().DurationVar(&speedTestDuration, "duration", speedtest.DefaultDuration, "duration of each throughput test")
+	speedTestCmd.Flags().Uint64Var(&speedTestUDPMbps, "udp-mbps", 0, "rate of the UDP tests in Mbit/s. Defaults to the rate measured by the TCP tests")
+}
+
+func speedTest(cmd *cobra.Command, args []string) error {
+	if speedTestDuration <= 0 || speedTestDuration > speedtest.MaxDuration {
+		return fmt.Errorf("duration must be between 0 and %s", speedtest.MaxDuration)
+	}
+
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	cmd.Printf("Running speed test to %s, this takes about %s...\n\n", args[0], (4*speedTestDuration + 2*time.Second).Round(time.Second))
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.SpeedTest(cmd.Context(), &proto.SpeedTestRequest{
+		Peer:       args[0],
+		Duration:   durationpb.New(speedTestDuration),
+		UdpBitrate: speedTestUDPMbps * 1_000_000,
+	})
+	if err != nil {
+		return fmt.Errorf("speed test failed: %v", status.Convert(err).Message())
+	}
+
+	printSpeedTest(cmd, resp)
+	return nil
+}
+
+func printSpeedTest(cmd *cobra.Command, resp *proto.SpeedTestResponse) {
+	peerName := resp.GetPeerIp()
+	if resp.GetPeerFqdn() != "" {
+		peerName = fmt.Sprintf("%s (%s)", resp.GetPeerFqdn(), resp.GetPeerIp())
+	}
+
+	cmd.Printf("Peer:          %s\n", peerName)
+	cmd.Printf("Path:          %s\n", speedTestPath(resp))
+
+	rtt := resp.GetRtt()
+	cmd.Printf("Latency:       %s (jitter %s, loss %.1f%%)\n",
+		rtt.GetLatency().AsDuration().Round(10*time.Microsecond),
+		rtt.GetJitter().AsDuration().Round(10*time.Microsecond),
+		rtt.GetLoss()*100)
+
+	cmd.Printf("TCP upload:    %s\n", formatThroughput(resp.GetTcpUpload(), false))
+	cmd.Printf("TCP download:  %s\n", formatThroughput(resp.GetTcpDownload(), false))
+	cmd.Printf("UDP upload:    %s\n", formatThroughput(resp.GetUdpUpload(), true))
+	cmd.Printf("UDP download:  %s\n", formatThroughput(resp.GetUdpDownload(), true))
+}
+
+func speedTestPath(resp *proto.SpeedTestResponse) string {
+	if !resp.GetRelayed() {
+		return fmt.Sprintf("P2P (local %s %s, remote %s %s)",
+			resp.GetLocalIceCandidateType(), resp.GetLocalIceCandidateEndpoint(),
+			resp.GetRemoteIceCandidateType(), resp.GetRemoteIceCandidateEndpoint())
+	}
+
+	if resp.GetLocalIceCandidateType() == "relay" || resp.GetRemoteIceCandidateType() == "relay" {
+		return fmt.Sprintf("Relayed via TURN (local %s, remote %s)", resp.GetLocalIceCandidateEndpoint(), resp.GetRemoteIceCandidateEndpoint())
+	}
+	return fmt.Sprintf("Relayed via %s", resp.GetRelayServerAddress())
+}
+
+func formatThroughput(t *proto.SpeedTestThroughput, withLoss bool) string {
+	var bps float64
+	if d := t.GetDuration().AsDuration(); d > 0 {
+		bps = float64(t.GetBytes()*8) / d.Seconds()
+	}
+
+	var rate string
+	switch {
+	case bps >= 1e9:
+		rate = fmt.Sprintf("%.2f Gbit/s", bps/1e9)
+	case bps >= 1e6:
+		rate = fmt.Sprintf("%.2f Mbit/s", bps/1e6)
+	default:
+		rate = fmt.Sprintf("%.2f kbit/s", bps/1e3)
+	}
+
+	if withLoss {
+		return fmt.Sprintf("%s (loss %.1f%%)", rate, t.GetLoss()*100)
+	}
+	return rate
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+func TestFormatThroughput(t *testing.T) {
+	tests := []struct {
+		name       string
+		throughput *proto.SpeedTestThroughput
+		withLoss   bool
+		expected   string
+	}{
+		{
+			name:       "gigabit",
+			throughput: &proto.SpeedTestThroughput{Bytes: 250_000_000, Duration: durationpb.New(time.Second)},
+			expected:   "2.00 Gbit/s",
+		},
+		{
+			name:       "megabit with loss",
+			throughput: &proto.SpeedTestThroughput{Bytes: 12_500_000, Duration: durationpb.New(2 * time.Second), Loss: 0.015},
+			withLoss:   true,
+			expected:   "50.00 Mbit/s (loss 1.5%)",
+		},
+		{
+			name:       "no duration",
+			throughput: &proto.SpeedTestThroughput{Bytes: 1000},
+			expected:   "0.00 kbit/s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatThroughput(tt.throughput, tt.withLoss))
+		})
+	}
+}
+
+func TestSpeedTestPath(t *testing.T) {
+	p2p := &proto.SpeedTestResponse{
+		LocalIceCandidateType:      "host",
+		LocalIceCandidateEndpoint:  "192.168.1.2:51820",
+		RemoteIceCandidateType:     "srflx",
+		RemoteIceCandidateEndpoint: "203.0.113.5:51820",
+	}
+	assert.Equal(t, "P2P (local host 192.168.1.2:51820, remote srflx 203.0.113.5:51820)", speedTestPath(p2p))
+
+	relayed := &proto.SpeedTestResponse{Relayed: true, RelayServerAddress: "rels://relay.example.com:443"}
+	assert.Equal(t, "Relayed via rels://relay.example.com:443", speedTestPath(relayed))
+
+	turn := &proto.SpeedTestResponse{
+		Relayed:                    true,
+		LocalIceCandidateType:      "relay",
+		LocalIceCandidateEndpoint:  "198.51.100.1:49160",
+		RemoteIceCandidateType:     "host",
+		RemoteIceCandidateEndpoint: "10.0.0.2:51820",
+	}
+	assert.Equal(t, "Relayed via TURN (local 198.51.100.1:49160, remote 10.0.0.2:51820)", speedTestPath(turn))
+}
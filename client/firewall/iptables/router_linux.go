@@ -259,6 +259,12 @@ func (r *router) AddNatRule(pair firewall.RouterPair) error {
 		}
 	}
 
+	if pair.NetMap != nil {
+		if err := r.addNetMapRules(pair); err != nil {
+			return fmt.Errorf("add netmap rules: %w", err)
+		}
+	}
+
 	if !pair.Masquerade {
 		return nil
 	}
@@ -288,6 +294,10 @@ func (r *router) RemoveNatRule(pair firewall.RouterPair) error {
 		}
 	}
 
+	if err := r.removeNetMapRules(pair); err != nil {
+		return fmt.Errorf("remove netmap rules: %w", err)
+	}
+
 	if err := r.removeLegacyRouteRule(pair); err != nil {
 		return fmt.Errorf("remove legacy routing rule: %w", err)
 	}
@@ -297,6 +307,80 @@ func (r *router) RemoveNatRule(pair firewall.RouterPair) error {
 	return nil
 }
 
+// addNetMapRules adds the 1:1 NAT rules of a netmap.
+// Destinations of traffic coming in from the WireGuard interface are translated from the virtual to the real prefix,
+// sources of connections from the routed network going out to the WireGuard interface from the real to the virtual prefix.
+// Replies are translated by conntrack.
+func (r *router) addNetMapRules(pair firewall.RouterPair) error {
+	if err := r.removeNetMapRules(pair); err != nil {
+		return fmt.Errorf("remove existing netmap rules: %w", err)
+	}
+
+	ruleKey := firewall.GenKey(firewall.NetMapFormat, pair)
+	netMap := pair.NetMap
+
+	rules := map[string]ruleInfo{
+		ruleKey + dnatSuffix: {
+			table: tableNat,
+			chain: chainRTRDR,
+			rule: []string{
+				"-i", r.wgIface.Name(),
+				"-d", netMap.Virtual.String(),
+				"-j", "NETMAP",
+				"--to", netMap.Real.String(),
+			},
+		},
+		ruleKey + snatSuffix: {
+			table: tableNat,
+			chain: chainRTNAT,
+			rule: []string{
+				"-o", r.wgIface.Name(),
+				"-s", netMap.Real.String(),
+				"-j", "NETMAP",
+				"--to", netMap.Virtual.String(),
+			},
+		},
+	}
+
+	for key, ruleInfo := range rules {
+		if err := r.iptablesClient.Append(ruleInfo.table, ruleInfo.chain, ruleInfo.rule...); err != nil {
+			if rollbackErr := r.rollbackRules(rules); rollbackErr != nil {
+				log.Errorf("rollback failed: %v", rollbackErr)
+			}
+			return fmt.Errorf("add rule %s: %w", key, err)
+		}
+		r.rules[key] = ruleInfo.rule
+	}
+
+	r.updateState()
+	return nil
+}
+
+// removeNetMapRules removes the netmap rules of the pair if they exist
+func (r *router) removeNetMapRules(pair firewall.RouterPair) error {
+	ruleKey := firewall.GenKey(firewall.NetMapFormat, pair)
+
+	var merr *multierror.Error
+	if dnatRule, exists := r.rules[ruleKey+dnatSuffix]; exists {
+		if err := r.iptablesClient.DeleteIfExists(tableNat, chainRTRDR, dnatRule...); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("delete netmap DNAT rule: %w", err))
+		} else {
+			delete(r.rules, ruleKey+dnatSuffix)
+		}
+	}
+
+	if snatRule, exists := r.rules[ruleKey+snatSuffix]; exists {
+		if err := r.iptablesClient.DeleteIfExists(tableNat, chainRTNAT, snatRule...); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("delete netmap SNAT rule: %w", err))
+		} else {
+			delete(r.rules, ruleKey+snatSuffix)
+		}
+	}
+
+	r.updateState()
+	return nberrors.FormatErrorOrNil(merr)
+}
+
 // addLegacyRouteRule adds a legacy routing rule for mgmt servers pre route acls
 func (r *router) addLegacyRouteRule(pair firewall.RouterPair) error {
 	ruleKey := firewall.GenKey(firewall.ForwardingFormat, pair)
@@ -759,15 +843,23 @@ func (r *router) AddDNATRule(rule firewall.ForwardRule) (firewall.Rule, error) {
 		"--to-destination", toDestination,
 	}
 	dnatRule = append(dnatRule, applyPort("--dport", &rule.DestinationPort)...)
+	if rule.DestinationAddress.IsValid() {
+		dnatRule = append(dnatRule, "-d", rule.DestinationAddress.String())
+	}
+	if len(rule.SourcePrefixes) > 0 {
+		// iptables expands the list into one rule per source
+		dnatRule = append(dnatRule, "-s", joinPrefixes(rule.SourcePrefixes))
+	}
 	rules[ruleKey+dnatSuffix] = ruleInfo{
 		table: tableNat,
 		chain: chainRTRDR,
 		rule:  dnatRule,
 	}
 
-	// SNAT rule
+	// SNAT rule, matches the DNATed connections independent of the outgoing interface
+	// as the translated address is either a peer or a resource on the local network of a routing peer
 	snatRule := []string{
-		"-o", r.wgIface.Name(),
+		"-m", "conntrack", "--ctstate", "DNAT",
 		"-p", proto,
 		"-d", rule.TranslatedAddress.String(),
 		"-j", "MASQUERADE",
@@ -970,6 +1062,14 @@ func (r *router) RemoveInboundDNAT(localAddr netip.Addr, protocol firewall.Proto
 	return nil
 }
 
+func joinPrefixes(prefixes []netip.Prefix) string {
+	values := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		values = append(values, prefix.String())
+	}
+	return strings.Join(values, ",")
+}
+
 func applyPort(flag string, port *firewall.Port) []string {
 	if port == nil {
 		return nil
@@ -16,6 +16,7 @@ const (
 	ForwardingFormat       = "netbird-fwd-%s-%t"
 	PreroutingFormat       = "netbird-prerouting-%s-%t"
 	NatFormat              = "netbird-nat-%s-%t"
+	NetMapFormat           = "netbird-netmap-%s-%t"
 )
 
 // Rule abstraction should be implemented by each firewall manager
@@ -3,12 +3,17 @@ package manager
 import (
 	"fmt"
 	"net/netip"
+	"strings"
 )
 
 // ForwardRule todo figure out better place to this to avoid circular imports
 type ForwardRule struct {
-	Protocol          Protocol
-	DestinationPort   Port
+	Protocol        Protocol
+	DestinationPort Port
+	// DestinationAddress restricts the rule to a local address of the ingress peer, all addresses if invalid
+	DestinationAddress netip.Addr
+	// SourcePrefixes restricts the rule to traffic from these prefixes, all sources if empty
+	SourcePrefixes    []netip.Prefix
 	TranslatedAddress netip.Addr
 	TranslatedPort    Port
 }
@@ -19,9 +24,34 @@ func (r ForwardRule) ID() string {
 		r.DestinationPort.String(),
 		r.TranslatedAddress.String(),
 		r.TranslatedPort.String())
+
+	// keep the IDs of unrestricted rules stable
+	if r.DestinationAddress.IsValid() || len(r.SourcePrefixes) > 0 {
+		id += fmt.Sprintf(";%s;%s", r.destinationAddressString(), r.sourcePrefixesString())
+	}
 	return id
 }
 
 func (r ForwardRule) String() string {
-	return fmt.Sprintf("protocol: %s, destinationPort: %s, translatedAddress: %s, translatedPort: %s", r.Protocol, r.DestinationPort.String(), r.TranslatedAddress.String(), r.TranslatedPort.String())
+	return fmt.Sprintf("protocol: %s, destinationAddress: %s, destinationPort: %s, sources: %s, translatedAddress: %s, translatedPort: %s",
+		r.Protocol, r.destinationAddressString(), r.DestinationPort.String(), r.sourcePrefixesString(), r.TranslatedAddress.String(), r.TranslatedPort.String())
+}
+
+func (r ForwardRule) destinationAddressString() string {
+	if !r.DestinationAddress.IsValid() {
+		return "any"
+	}
+	return r.DestinationAddress.String()
+}
+
+func (r ForwardRule) sourcePrefixesString() string {
+	if len(r.SourcePrefixes) == 0 {
+		return "any"
+	}
+
+	prefixes := make([]string, 0, len(r.SourcePrefixes))
+	for _, prefix := range r.SourcePrefixes {
+		prefixes = append(prefixes, prefix.String())
+	}
+	return strings.Join(prefixes, ",")
 }
@@ -10,6 +10,8 @@ type RouterPair struct {
 	Destination Network
 	Masquerade  bool
 	Inverse     bool
+	// NetMap translates the virtual destination prefix 1:1 to the real prefix of the routed network
+	NetMap *route.NetMap
 }
 
 func GetInversePair(pair RouterPair) RouterPair {
@@ -46,6 +46,9 @@ const (
 	dnatSuffix = "_dnat"
 	snatSuffix = "_snat"
 
+	// ctStatusDNAT is the IPS_DST_NAT conntrack status bit
+	ctStatusDNAT = 1 << 5
+
 	// ipTCPHeaderMinSize represents minimum IP (20) + TCP (20) header size for MSS calculation
 	ipTCPHeaderMinSize = 40
 
@@ -659,6 +662,12 @@ func (r *router) AddNatRule(pair firewall.RouterPair) error {
 		}
 	}
 
+	if pair.NetMap != nil {
+		if err := r.addNetMapRules(pair); err != nil {
+			return fmt.Errorf("add netmap rules: %w", err)
+		}
+	}
+
 	if err := r.conn.Flush(); err != nil {
 		// TODO: rollback ipset counter
 		return fmt.Errorf("insert rules for %s: %v", pair.Destination, err)
@@ -739,6 +748,104 @@ func (r *router) addNatRule(pair firewall.RouterPair) error {
 	return nil
 }
 
+// addNetMapRules inserts the 1:1 NAT rules of a netmap to the conn client flush queue.
+// Destinations of traffic coming in from the WireGuard interface are translated from the virtual to the real prefix,
+// sources of connections from the routed network going out to the WireGuard interface from the real to the virtual prefix.
+// Replies are translated by conntrack.
+func (r *router) addNetMapRules(pair firewall.RouterPair) error {
+	if err := r.removeNetMapRules(pair); err != nil {
+		return fmt.Errorf("remove existing netmap rules: %w", err)
+	}
+
+	ruleKey := firewall.GenKey(firewall.NetMapFormat, pair)
+	netMap := pair.NetMap
+
+	dnatExprs := []expr.Any{
+		&expr.Meta{Key: expr.MetaKeyIIFNAME, Register: 1},
+		&expr.Cmp{
+			Op:       expr.CmpOpEq,
+			Register: 1,
+			Data:     ifname(r.wgIface.Name()),
+		},
+	}
+	dnatExprs = append(dnatExprs, applyPrefix(netMap.Virtual, false)...)
+	dnatExprs = append(dnatExprs, netMapExprs(expr.NATTypeDestNAT, netMap.Real)...)
+
+	dnatRule := &nftables.Rule{
+		Table:    r.workTable,
+		Chain:    r.chains[chainNameRoutingRdr],
+		Exprs:    dnatExprs,
+		UserData: []byte(ruleKey + dnatSuffix),
+	}
+	r.conn.AddRule(dnatRule)
+	r.rules[ruleKey+dnatSuffix] = dnatRule
+
+	snatExprs := []expr.Any{
+		&expr.Meta{Key: expr.MetaKeyOIFNAME, Register: 1},
+		&expr.Cmp{
+			Op:       expr.CmpOpEq,
+			Register: 1,
+			Data:     ifname(r.wgIface.Name()),
+		},
+	}
+	snatExprs = append(snatExprs, applyPrefix(netMap.Real, true)...)
+	snatExprs = append(snatExprs, netMapExprs(expr.NATTypeSourceNAT, netMap.Virtual)...)
+
+	snatRule := &nftables.Rule{
+		Table:    r.workTable,
+		Chain:    r.chains[chainNameRoutingNat],
+		Exprs:    snatExprs,
+		UserData: []byte(ruleKey + snatSuffix),
+	}
+	r.conn.AddRule(snatRule)
+	r.rules[ruleKey+snatSuffix] = snatRule
+
+	log.Debugf("added netmap rules %s <-> %s", netMap.Virtual, netMap.Real)
+
+	return nil
+}
+
+// removeNetMapRules removes the netmap rules of the pair if they exist
+func (r *router) removeNetMapRules(pair firewall.RouterPair) error {
+	ruleKey := firewall.GenKey(firewall.NetMapFormat, pair)
+
+	var merr *multierror.Error
+	for _, key := range []string{ruleKey + dnatSuffix, ruleKey + snatSuffix} {
+		rule, exists := r.rules[key]
+		if !exists {
+			continue
+		}
+		if err := r.conn.DelRule(rule); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("remove netmap rule %s: %w", key, err))
+			continue
+		}
+		delete(r.rules, key)
+	}
+
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// netMapExprs returns the expressions that translate the address 1:1 to the given prefix, keeping the host bits
+func netMapExprs(natType expr.NATType, to netip.Prefix) []expr.Any {
+	return []expr.Any{
+		&expr.Immediate{
+			Register: 1,
+			Data:     to.Masked().Addr().AsSlice(),
+		},
+		&expr.Immediate{
+			Register: 2,
+			Data:     calculateLastIP(to).AsSlice(),
+		},
+		&expr.NAT{
+			Type:       natType,
+			Family:     uint32(nftables.TableFamilyIPv4),
+			RegAddrMin: 1,
+			RegAddrMax: 2,
+			Prefix:     true,
+		},
+	}
+}
+
 // addPostroutingRules adds the masquerade rules
 func (r *router) addPostroutingRules() {
 	// First masquerade rule for traffic coming in from WireGuard interface
@@ -1339,6 +1446,10 @@ func (r *router) RemoveNatRule(pair firewall.RouterPair) error {
 		}
 	}
 
+	if err := r.removeNetMapRules(pair); err != nil {
+		return fmt.Errorf("remove netmap rules: %w", err)
+	}
+
 	if err := r.removeLegacyRouteRule(pair); err != nil {
 		return fmt.Errorf("remove legacy routing rule: %w", err)
 	}
@@ -1446,14 +1557,28 @@ func (r *router) addDnatRedirect(rule firewall.ForwardRule, protoNum uint8, rule
 	}
 	dnatExprs = append(dnatExprs, applyPort(&rule.DestinationPort, false)...)
 
+	if rule.DestinationAddress.IsValid() {
+		dnatExprs = append(dnatExprs, applyPrefix(netip.PrefixFrom(rule.DestinationAddress, 32), false)...)
+	}
+
 	// shifted translated port is not supported in nftables, so we hand this over to xtables
 	if rule.TranslatedPort.IsRange && len(rule.TranslatedPort.Values) == 2 {
 		if rule.TranslatedPort.Values[0] != rule.DestinationPort.Values[0] ||
 			rule.TranslatedPort.Values[1] != rule.DestinationPort.Values[1] {
-			return r.addXTablesRedirect(dnatExprs, ruleKey, rule)
+			// the xtables rule lives in a different table and can't look up our sets
+			if len(rule.SourcePrefixes) > 1 {
+				return fmt.Errorf("multiple source prefixes are not supported with shifted port ranges")
+			}
+			return r.addXTablesRedirect(append(dnatExprs, r.applyForwardSources(rule.SourcePrefixes)...), ruleKey, rule)
 		}
 	}
 
+	sourceExprs, err := r.getForwardSources(rule.SourcePrefixes)
+	if err != nil {
+		return fmt.Errorf("source prefixes: %w", err)
+	}
+	dnatExprs = append(dnatExprs, sourceExprs...)
+
 	additionalExprs, regProtoMin, regProtoMax, err := r.handleTranslatedPort(rule)
 	if err != nil {
 		return err
@@ -1482,6 +1607,23 @@ func (r *router) addDnatRedirect(rule firewall.ForwardRule, protoNum uint8, rule
 	return nil
 }
 
+// getForwardSources returns the expressions matching the source prefixes of a forward rule.
+// Multiple prefixes are matched with a set.
+func (r *router) getForwardSources(prefixes []netip.Prefix) ([]expr.Any, error) {
+	if len(prefixes) <= 1 {
+		return r.applyForwardSources(prefixes), nil
+	}
+	return r.getIpSet(firewall.NewPrefixSet(prefixes), prefixes, true)
+}
+
+// applyForwardSources returns the expressions matching a single source prefix of a forward rule
+func (r *router) applyForwardSources(prefixes []netip.Prefix) []expr.Any {
+	if len(prefixes) == 0 {
+		return nil
+	}
+	return applyPrefix(prefixes[0], true)
+}
+
 func (r *router) handleTranslatedPort(rule firewall.ForwardRule) ([]expr.Any, uint32, uint32, error) {
 	switch {
 	case rule.TranslatedPort.IsRange && len(rule.TranslatedPort.Values) == 2:
@@ -1578,12 +1720,21 @@ func (r *router) addXTablesRedirect(dnatExprs []expr.Any, ruleKey string, rule f
 }
 
 func (r *router) addDnatMasq(rule firewall.ForwardRule, protoNum uint8, ruleKey string) {
+	// match the DNATed connections independent of the outgoing interface,
+	// the translated address is either a peer or a resource on the local network of a routing peer
 	masqExprs := []expr.Any{
-		&expr.Meta{Key: expr.MetaKeyOIFNAME, Register: 1},
+		&expr.Ct{Key: expr.CtKeySTATUS, Register: 1},
+		&expr.Bitwise{
+			SourceRegister: 1,
+			DestRegister:   1,
+			Len:            4,
+			Mask:           binaryutil.NativeEndian.PutUint32(ctStatusDNAT),
+			Xor:            binaryutil.NativeEndian.PutUint32(0),
+		},
 		&expr.Cmp{
-			Op:       expr.CmpOpEq,
+			Op:       expr.CmpOpNeq,
 			Register: 1,
-			Data:     ifname(r.wgIface.Name()),
+			Data:     []byte{0, 0, 0, 0},
 		},
 		&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: 1},
 		&expr.Cmp{
@@ -1629,7 +1780,8 @@ func (r *router) DeleteDNATRule(rule firewall.Rule) error {
 	}
 
 	var merr *multierror.Error
-	if dnatRule, exists := r.rules[ruleKey+dnatSuffix]; exists {
+	dnatRule, dnatExists := r.rules[ruleKey+dnatSuffix]
+	if dnatExists {
 		if err := r.conn.DelRule(dnatRule); err != nil {
 			merr = multierror.Append(merr, fmt.Errorf("delete dnat rule: %w", err))
 		}
@@ -1646,6 +1798,11 @@ func (r *router) DeleteDNATRule(rule firewall.Rule) error {
 	}
 
 	if merr == nil {
+		if dnatExists {
+			if err := r.decrementSetCounter(dnatRule); err != nil {
+				merr = multierror.Append(merr, fmt.Errorf("decrement set counter: %w", err))
+			}
+		}
 		delete(r.rules, ruleKey+dnatSuffix)
 		delete(r.rules, ruleKey+snatSuffix)
 	}
@@ -27,6 +27,7 @@ import (
 	"github.com/netbirdio/netbird/client/iface/netstack"
 	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
 	"github.com/netbirdio/netbird/client/internal/statemanager"
+	"github.com/netbirdio/netbird/route"
 )
 
 const (
@@ -124,6 +125,9 @@ type Manager struct {
 
 	blockRule firewall.Rule
 
+	// broadcastPrefixes are the routed networks whose directed broadcasts the forwarder sends
+	broadcastPrefixes atomic.Pointer[[]netip.Prefix]
+
 	// Internal 1:1 DNAT
 	dnatEnabled  atomic.Bool
 	dnatMappings map[netip.Addr]netip.Addr
@@ -134,6 +138,11 @@ type Manager struct {
 	portDNATRules   []portDNATRule
 	portDNATMutex   sync.RWMutex
 
+	// 1:1 NAT of routed networks with overlapping subnets
+	netMapEnabled atomic.Bool
+	netMaps       map[route.ID]*route.NetMap
+	netMapMutex   sync.RWMutex
+
 	netstackServices     map[serviceKey]struct{}
 	netstackServiceMutex sync.RWMutex
 
@@ -247,6 +256,7 @@ func create(iface common.IFaceMapper, nativeFirewall firewall.Manager, disableSe
 		netstack:            netstack.IsEnabled(),
 		localForwarding:     enableLocalForwarding,
 		dnatMappings:        make(map[netip.Addr]netip.Addr),
+		netMaps:             make(map[route.ID]*route.NetMap),
 		portDNATRules:       []portDNATRule{},
 		netstackServices:    make(map[serviceKey]struct{}),
 		mtu:                 mtu,
@@ -378,6 +388,10 @@ func (m *Manager) initForwarder() error {
 		return fmt.Errorf("create forwarder: %w", err)
 	}
 
+	forwarder.SetPacketTranslator(m.translateNetMapSource)
+	if prefixes := m.broadcastPrefixes.Load(); prefixes != nil {
+		forwarder.SetBroadcastPrefixes(*prefixes)
+	}
 	m.forwarder.Store(forwarder)
 
 	log.Debug("forwarder initialized")
@@ -402,6 +416,10 @@ func (m *Manager) AddNatRule(pair firewall.RouterPair) error {
 		return m.nativeFirewall.AddNatRule(pair)
 	}
 
+	if pair.NetMap != nil {
+		m.addNetMap(pair.ID, pair.NetMap)
+	}
+
 	// userspace routed packets are always SNATed to the inbound direction
 	// TODO: implement outbound SNAT
 	return nil
@@ -412,6 +430,8 @@ func (m *Manager) RemoveNatRule(pair firewall.RouterPair) error {
 	if m.nativeRouter.Load() && m.nativeFirewall != nil {
 		return m.nativeFirewall.RemoveNatRule(pair)
 	}
+
+	m.removeNetMap(pair.ID)
 	return nil
 }
 
@@ -577,6 +597,14 @@ func (m *Manager) DeletePeerRule(rule firewall.Rule) error {
 	return nil
 }
 
+// SetBroadcastPrefixes sets the routed networks whose directed broadcasts are forwarded into the network
+func (m *Manager) SetBroadcastPrefixes(prefixes []netip.Prefix) {
+	m.broadcastPrefixes.Store(&prefixes)
+	if fwder := m.forwarder.Load(); fwder != nil {
+		fwder.SetBroadcastPrefixes(prefixes)
+	}
+}
+
 // SetLegacyManagement doesn't need to be implemented for this manager
 func (m *Manager) SetLegacyManagement(isLegacy bool) error {
 	if m.nativeFirewall == nil {
@@ -933,6 +961,15 @@ func (m *Manager) filterInbound(packetData []byte, size int) bool {
 		srcIP, dstIP = m.extractIPs(d)
 	}
 
+	if translated := m.translateNetMapDestination(packetData, d, dstIP); translated {
+		// Re-decode after translation to get the real destination
+		if err := d.parser.DecodeLayers(packetData, &d.decoded); err != nil {
+			m.logger.Error1("failed to re-decode packet after netmap: %v", err)
+			return true
+		}
+		srcIP, dstIP = m.extractIPs(d)
+	}
+
 	if m.stateful && m.isValidTrackedConnection(d, srcIP, dstIP, size) {
 		return false
 	}
@@ -0,0 +1,8 @@
+package forwarder
+
+import "syscall"
+
+// broadcastControl is a no-op, sockets can't be configured in the browser
+func broadcastControl(_, _ string, _ syscall.RawConn) error {
+	return nil
+}
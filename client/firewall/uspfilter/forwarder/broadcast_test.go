@@ -0,0 +1,25 @@
+package forwarder
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForwarder_IsBroadcast(t *testing.T) {
+	f := &Forwarder{}
+	assert.False(t, f.isBroadcast(netip.MustParseAddr("192.168.1.255")), "no prefixes set")
+
+	f.SetBroadcastPrefixes([]netip.Prefix{
+		netip.MustParsePrefix("192.168.1.0/24"),
+		netip.MustParsePrefix("10.0.0.0/14"),
+		netip.MustParsePrefix("172.16.0.0/31"),
+	})
+
+	assert.True(t, f.isBroadcast(netip.MustParseAddr("192.168.1.255")))
+	assert.True(t, f.isBroadcast(netip.MustParseAddr("10.3.255.255")))
+	assert.False(t, f.isBroadcast(netip.MustParseAddr("192.168.1.254")))
+	assert.False(t, f.isBroadcast(netip.MustParseAddr("192.168.2.255")))
+	assert.False(t, f.isBroadcast(netip.MustParseAddr("172.16.0.1")), "point-to-point networks have no broadcast address")
+}
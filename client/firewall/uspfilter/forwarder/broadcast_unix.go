@@ -0,0 +1,20 @@
+//go:build !windows && !js
+
+package forwarder
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// broadcastControl allows the socket to send to the directed broadcast address of a routed network
+func broadcastControl(_, _ string, c syscall.RawConn) error {
+	var sockErr error
+	if err := c.Control(func(fd uintptr) {
+		sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_BROADCAST, 1)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
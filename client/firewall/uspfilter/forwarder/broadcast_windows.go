@@ -0,0 +1,18 @@
+package forwarder
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// broadcastControl allows the socket to send to the directed broadcast address of a routed network
+func broadcastControl(_, _ string, c syscall.RawConn) error {
+	var sockErr error
+	if err := c.Control(func(fd uintptr) {
+		sockErr = windows.SetsockoptInt(windows.Handle(fd), windows.SOL_SOCKET, windows.SO_BROADCAST, 1)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
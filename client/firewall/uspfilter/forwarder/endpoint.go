@@ -18,6 +18,8 @@ type endpoint struct {
 	dispatcher stack.NetworkDispatcher
 	device     *wgdevice.Device
 	mtu        atomic.Uint32
+	// translate rewrites packets in place before they are sent through WireGuard
+	translate atomic.Pointer[func(packet []byte)]
 }
 
 func (e *endpoint) Attach(dispatcher stack.NetworkDispatcher) {
@@ -54,9 +56,14 @@ func (e *endpoint) WritePackets(pkts stack.PacketBufferList) (int, tcpip.Error)
 			continue
 		}
 
+		packet := data.AsSlice()
+		if translate := e.translate.Load(); translate != nil {
+			(*translate)(packet)
+		}
+
 		// Send the packet through WireGuard
 		address := netHeader.DestinationAddress()
-		err := e.device.CreateOutboundPacket(data.AsSlice(), address.AsSlice())
+		err := e.device.CreateOutboundPacket(packet, address.AsSlice())
 		if err != nil {
 			e.logger.Error1("CreateOutboundPacket: %v", err)
 			continue
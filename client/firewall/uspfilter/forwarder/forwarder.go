@@ -2,11 +2,13 @@ package forwarder
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
 	"net"
 	"net/netip"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -46,6 +48,8 @@ type Forwarder struct {
 	netstack         bool
 	hasRawICMPAccess bool
 	pingSemaphore    chan struct{}
+	// broadcastPrefixes are the routed networks whose directed broadcasts are forwarded
+	broadcastPrefixes atomic.Pointer[[]netip.Prefix]
 }
 
 func New(iface common.IFaceMapper, logger *nblog.Logger, flowLogger nftypes.FlowLogger, netstack bool, mtu uint16, udpTimeout time.Duration) (*Forwarder, error) {
@@ -155,6 +159,39 @@ func (f *Forwarder) InjectIncomingPacket(payload []byte) error {
 	return nil
 }
 
+// SetPacketTranslator sets a function that rewrites forwarded packets in place before they are sent back through WireGuard
+func (f *Forwarder) SetPacketTranslator(translate func(packet []byte)) {
+	f.endpoint.translate.Store(&translate)
+}
+
+// SetBroadcastPrefixes sets the routed networks whose directed broadcast address is forwarded
+func (f *Forwarder) SetBroadcastPrefixes(prefixes []netip.Prefix) {
+	f.broadcastPrefixes.Store(&prefixes)
+}
+
+// isBroadcast reports whether the address is the directed broadcast address of a network set with SetBroadcastPrefixes
+func (f *Forwarder) isBroadcast(addr netip.Addr) bool {
+	prefixes := f.broadcastPrefixes.Load()
+	if prefixes == nil {
+		return false
+	}
+	for _, prefix := range *prefixes {
+		if prefix.Bits() >= 31 || !prefix.Contains(addr) {
+			continue
+		}
+		if lastAddr(prefix) == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	b := prefix.Masked().Addr().As4()
+	binary.BigEndian.PutUint32(b[:], binary.BigEndian.Uint32(b[:])|(1<<(32-prefix.Bits())-1))
+	return netip.AddrFrom4(b)
+}
+
 // Stop gracefully shuts down the forwarder
 func (f *Forwarder) Stop() {
 	f.cancel()
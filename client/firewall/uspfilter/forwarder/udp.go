@@ -157,7 +157,11 @@ func (f *Forwarder) handleUDP(r *udp.ForwarderRequest) bool {
 	}()
 
 	dstAddr := fmt.Sprintf("%s:%d", f.determineDialAddr(id.LocalAddress), id.LocalPort)
-	outConn, err := (&net.Dialer{}).DialContext(f.ctx, "udp", dstAddr)
+	dialer := &net.Dialer{}
+	if dstIP, ok := netip.AddrFromSlice(id.LocalAddress.AsSlice()); ok && f.isBroadcast(dstIP) {
+		dialer.Control = broadcastControl
+	}
+	outConn, err := dialer.DialContext(f.ctx, "udp", dstAddr)
 	if err != nil {
 		f.logger.Debug2("forwarder: UDP dial error for %v: %v", epID(id), err)
 		// TODO: Send ICMP error message
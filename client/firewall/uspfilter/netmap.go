@@ -0,0 +1,88 @@
+package uspfilter
+
+import (
+	"net/netip"
+
+	"github.com/google/gopacket/layers"
+
+	"github.com/netbirdio/netbird/route"
+)
+
+// addNetMap registers the 1:1 NAT of a routed network
+func (m *Manager) addNetMap(id route.ID, netMap *route.NetMap) {
+	m.netMapMutex.Lock()
+	defer m.netMapMutex.Unlock()
+
+	m.netMaps[id] = netMap.Copy()
+	m.netMapEnabled.Store(true)
+}
+
+// removeNetMap removes the 1:1 NAT of a routed network
+func (m *Manager) removeNetMap(id route.ID) {
+	m.netMapMutex.Lock()
+	defer m.netMapMutex.Unlock()
+
+	delete(m.netMaps, id)
+	m.netMapEnabled.Store(len(m.netMaps) > 0)
+}
+
+// lookupNetMap returns the first translation of addr by any registered netmap
+func (m *Manager) lookupNetMap(addr netip.Addr, translate func(*route.NetMap, netip.Addr) (netip.Addr, bool)) (netip.Addr, bool) {
+	m.netMapMutex.RLock()
+	defer m.netMapMutex.RUnlock()
+
+	for _, netMap := range m.netMaps {
+		if translated, ok := translate(netMap, addr); ok {
+			return translated, true
+		}
+	}
+	return addr, false
+}
+
+// translateNetMapDestination translates the destination of routed packets from the virtual to the real prefix.
+func (m *Manager) translateNetMapDestination(packetData []byte, d *decoder, dstIP netip.Addr) bool {
+	if !m.netMapEnabled.Load() || d.decoded[0] != layers.LayerTypeIPv4 {
+		return false
+	}
+
+	realIP, exists := m.lookupNetMap(dstIP, (*route.NetMap).ToReal)
+	if !exists {
+		return false
+	}
+
+	if err := m.rewritePacketIP(packetData, d, realIP, destinationIPOffset); err != nil {
+		m.logger.Error1("failed to rewrite netmap destination: %v", err)
+		return false
+	}
+
+	m.logger.Trace2("NetMap: %s -> %s", dstIP, realIP)
+	return true
+}
+
+// translateNetMapSource translates the source of forwarded packets sent back to peers from the real to the virtual prefix.
+func (m *Manager) translateNetMapSource(packetData []byte) {
+	if !m.netMapEnabled.Load() || len(packetData) < 20 || packetData[0]>>4 != 4 {
+		return
+	}
+
+	srcIP := netip.AddrFrom4([4]byte(packetData[sourceIPOffset : sourceIPOffset+4]))
+	virtualIP, exists := m.lookupNetMap(srcIP, (*route.NetMap).ToVirtual)
+	if !exists {
+		return
+	}
+
+	d := m.decoders.Get().(*decoder)
+	defer m.decoders.Put(d)
+
+	if err := d.parser.DecodeLayers(packetData, &d.decoded); err != nil {
+		m.logger.Error1("failed to decode packet for netmap: %v", err)
+		return
+	}
+
+	if err := m.rewritePacketIP(packetData, d, virtualIP, sourceIPOffset); err != nil {
+		m.logger.Error1("failed to rewrite netmap source: %v", err)
+		return
+	}
+
+	m.logger.Trace2("Reverse NetMap: %s -> %s", srcIP, virtualIP)
+}
@@ -0,0 +1,46 @@
+package uspfilter
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/google/gopacket/layers"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/iface"
+	"github.com/netbirdio/netbird/client/iface/device"
+	"github.com/netbirdio/netbird/route"
+)
+
+// TestNetMapTranslation verifies that the netmap translates destinations into and sources out of the real prefix
+func TestNetMapTranslation(t *testing.T) {
+	manager, err := Create(&IFaceMock{
+		SetFilterFunc: func(device.PacketFilter) error { return nil },
+	}, false, flowLogger, iface.DefaultMTU)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, manager.Close(nil))
+	}()
+
+	peerIP := netip.MustParseAddr("100.64.0.2")
+	virtualIP := netip.MustParseAddr("10.80.1.20")
+	realIP := netip.MustParseAddr("192.168.1.20")
+
+	manager.addNetMap("route", route.NewNetMap(netip.MustParsePrefix("192.168.0.0/16"), netip.MustParsePrefix("10.80.0.0/16")))
+
+	inbound := generateDNATTestPacket(t, peerIP, virtualIP, layers.IPProtocolTCP, 12345, 443)
+	require.True(t, manager.translateNetMapDestination(inbound, parsePacket(t, inbound), virtualIP), "destination should be translated")
+	require.Equal(t, realIP, netip.AddrFrom4([4]byte(inbound[16:20])), "destination should be the real IP")
+
+	outbound := generateDNATTestPacket(t, realIP, peerIP, layers.IPProtocolTCP, 443, 12345)
+	manager.translateNetMapSource(outbound)
+	require.Equal(t, virtualIP, netip.AddrFrom4([4]byte(outbound[12:16])), "source should be the virtual IP")
+
+	unrelated := generateDNATTestPacket(t, peerIP, realIP, layers.IPProtocolTCP, 12345, 443)
+	require.False(t, manager.translateNetMapDestination(unrelated, parsePacket(t, unrelated), realIP), "real destinations should not be translated")
+
+	manager.removeNetMap("route")
+
+	inbound = generateDNATTestPacket(t, peerIP, virtualIP, layers.IPProtocolTCP, 12345, 443)
+	require.False(t, manager.translateNetMapDestination(inbound, parsePacket(t, inbound), virtualIP), "removed netmap should not translate")
+}
@@ -26,6 +26,7 @@ var ErrSourceRangesEmpty = errors.New("sources range is empty")
 // Manager is a ACL rules manager
 type Manager interface {
 	ApplyFiltering(networkMap *mgmProto.NetworkMap, dnsRouteFeatureFlag bool)
+	RulesCount() (peerRules, routeRules int)
 }
 
 // DefaultManager uses firewall manager to handle
@@ -79,6 +80,17 @@ func (d *DefaultManager) ApplyFiltering(networkMap *mgmProto.NetworkMap, dnsRout
 	}
 }
 
+// RulesCount returns the number of applied peer and route firewall rules
+func (d *DefaultManager) RulesCount() (peerRules, routeRules int) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, pairs := range d.peerRulesPairs {
+		peerRules += len(pairs)
+	}
+	return peerRules, len(d.routeRules)
+}
+
 func (d *DefaultManager) applyPeerACLs(networkMap *mgmProto.NetworkMap) {
 	rules := networkMap.FirewallRules
 
@@ -15,12 +15,13 @@ import (
 )
 
 const (
-	PriorityMgmtCache = 150
-	PriorityDNSRoute  = 100
-	PriorityLocal     = 75
-	PriorityUpstream  = 50
-	PriorityDefault   = 1
-	PriorityFallback  = -100
+	PriorityMgmtCache   = 150
+	PrioritySplitTunnel = 125
+	PriorityDNSRoute    = 100
+	PriorityLocal       = 75
+	PriorityUpstream    = 50
+	PriorityDefault     = 1
+	PriorityFallback    = -100
 )
 
 type SubdomainMatcher interface {
@@ -51,6 +52,7 @@ type ResponseWriterChain struct {
 	shouldContinue bool
 	response       *dns.Msg
 	meta           map[string]string
+	observers      []func(*dns.Msg)
 }
 
 // RequestID returns the request ID for tracing
@@ -66,12 +68,21 @@ func (w *ResponseWriterChain) SetMeta(key, value string) {
 	w.meta[key] = value
 }
 
+// ObserveResponse registers a function that is called with the response of the chain before it is written.
+// Handlers use it together with the continue signal to track the answers of the following handlers.
+func (w *ResponseWriterChain) ObserveResponse(observer func(*dns.Msg)) {
+	w.observers = append(w.observers, observer)
+}
+
 func (w *ResponseWriterChain) WriteMsg(m *dns.Msg) error {
 	// Check if this is a continue signal (NXDOMAIN with Zero bit set)
 	if m.Rcode == dns.RcodeNameError && m.MsgHdr.Zero {
 		w.shouldContinue = true
 		return nil
 	}
+	for _, observer := range w.observers {
+		observer(m)
+	}
 	w.response = m
 	return w.ResponseWriter.WriteMsg(m)
 }
@@ -207,6 +218,9 @@ func (c *HandlerChain) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 	handlers := slices.Clone(c.handlers)
 	c.mu.RUnlock()
 
+	// observers registered by handlers that continue the chain see the response of the following handlers
+	var observers []func(*dns.Msg)
+
 	// Try handlers in priority order
 	for _, entry := range handlers {
 		if !c.isHandlerMatch(qname, entry) {
@@ -226,8 +240,10 @@ func (c *HandlerChain) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 			ResponseWriter: w,
 			origPattern:    entry.OrigPattern,
 			requestID:      requestID,
+			observers:      observers,
 		}
 		entry.Handler.ServeDNS(chainWriter, r)
+		observers = chainWriter.observers
 
 		// If handler wants to continue, try next handler
 		if chainWriter.shouldContinue {
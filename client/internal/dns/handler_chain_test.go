@@ -325,6 +325,45 @@ func TestHandlerChain_ServeDNS_ChainContinuation(t *testing.T) {
 	handler3.AssertExpectations(t)
 }
 
+func TestHandlerChain_ServeDNS_ObserveResponse(t *testing.T) {
+	chain := nbdns.NewHandlerChain()
+
+	observer := &nbdns.MockHandler{}
+	upstream := &nbdns.MockHandler{}
+
+	chain.AddHandler("example.com.", observer, nbdns.PrioritySplitTunnel)
+	chain.AddHandler("example.com.", upstream, nbdns.PriorityUpstream)
+
+	r := new(dns.Msg)
+	r.SetQuestion("example.com.", dns.TypeA)
+
+	var observed *dns.Msg
+	observer.On("ServeDNS", mock.Anything, r).Run(func(args mock.Arguments) {
+		w := args.Get(0).(*nbdns.ResponseWriterChain)
+		w.ObserveResponse(func(m *dns.Msg) {
+			observed = m
+		})
+		resp := new(dns.Msg)
+		resp.SetRcode(r, dns.RcodeNameError)
+		resp.MsgHdr.Zero = true
+		assert.NoError(t, w.WriteMsg(resp))
+	}).Once()
+
+	upstreamResp := new(dns.Msg)
+	upstreamResp.SetReply(r)
+	upstream.On("ServeDNS", mock.Anything, r).Run(func(args mock.Arguments) {
+		w := args.Get(0).(*nbdns.ResponseWriterChain)
+		assert.NoError(t, w.WriteMsg(upstreamResp))
+	}).Once()
+
+	w := &nbdns.ResponseWriterChain{ResponseWriter: &test.MockResponseWriter{}}
+	chain.ServeDNS(w, r)
+
+	observer.AssertExpectations(t)
+	upstream.AssertExpectations(t)
+	assert.Same(t, upstreamResp, observed, "the observer should see the response of the following handler")
+}
+
 func TestHandlerChain_PriorityDeregistration(t *testing.T) {
 	tests := []struct {
 		name string
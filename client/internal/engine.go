@@ -36,6 +36,7 @@ import (
 	dnsconfig "github.com/netbirdio/netbird/client/internal/dns/config"
 	"github.com/netbirdio/netbird/client/internal/dnsfwd"
 	"github.com/netbirdio/netbird/client/internal/ingressgw"
+	"github.com/netbirdio/netbird/client/internal/multicast"
 	"github.com/netbirdio/netbird/client/internal/netflow"
 	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
 	"github.com/netbirdio/netbird/client/internal/networkmonitor"
@@ -47,7 +48,9 @@ import (
 	"github.com/netbirdio/netbird/client/internal/relay"
 	"github.com/netbirdio/netbird/client/internal/rosenpass"
 	"github.com/netbirdio/netbird/client/internal/routemanager"
+	"github.com/netbirdio/netbird/client/internal/routemanager/discovery"
 	"github.com/netbirdio/netbird/client/internal/routemanager/systemops"
+	"github.com/netbirdio/netbird/client/internal/speedtest"
 	"github.com/netbirdio/netbird/client/internal/statemanager"
 	"github.com/netbirdio/netbird/client/internal/updatemanager"
 	"github.com/netbirdio/netbird/client/jobexec"
@@ -186,6 +189,8 @@ type Engine struct {
 
 	sshServer sshServer
 
+	speedTestServer *speedtest.Server
+
 	statusRecorder *peer.Status
 
 	firewall          firewallManager.Manager
@@ -193,11 +198,16 @@ type Engine struct {
 	acl               acl.Manager
 	dnsForwardMgr     *dnsfwd.Manager
 	ingressGatewayMgr *ingressgw.Manager
+	multicastMgr      *multicast.Manager
 
 	dnsServer dns.Server
 
 	// checks are the client-applied posture checks that need to be evaluated on the client
 	checks []*mgmProto.Checks
+	// unhealthyRoutes are the IDs of the served routes whose health check fails, guarded by syncMsgMux
+	unhealthyRoutes []string
+	// discoveredSubnets are the reported routing table entries matching the subnet discovery allowlist, guarded by syncMsgMux
+	discoveredSubnets []string
 
 	relayManager *relayClient.Manager
 	stateManager *statemanager.Manager
@@ -298,6 +308,8 @@ func (e *Engine) Stop() error {
 
 	e.cleanupSSHConfig()
 
+	e.stopSpeedTestResponder()
+
 	if e.ingressGatewayMgr != nil {
 		if err := e.ingressGatewayMgr.Close(); err != nil {
 			log.Warnf("failed to cleanup forward rules: %v", err)
@@ -328,6 +340,11 @@ func (e *Engine) Stop() error {
 
 	e.stopDNSForwarder()
 
+	if e.multicastMgr != nil {
+		e.multicastMgr.Stop()
+		e.multicastMgr = nil
+	}
+
 	// stop/restore DNS after peers are closed but before interface goes down
 	// so dbus and friends don't complain because of a missing interface
 	e.stopDNSServer()
@@ -480,6 +497,7 @@ func (e *Engine) Start(netbirdConfig *mgmProto.NetbirdConfig, mgmtURL *url.URL)
 		PeerStore:           e.peerStore,
 		DisableClientRoutes: e.config.DisableClientRoutes,
 		DisableServerRoutes: e.config.DisableServerRoutes,
+		RouteHealthListener: e.onRouteHealthChange,
 	})
 	if err := e.routeManager.Init(); err != nil {
 		log.Errorf("Failed to initialize route manager: %s", err)
@@ -516,6 +534,8 @@ func (e *Engine) Start(netbirdConfig *mgmProto.NetbirdConfig, mgmtURL *url.URL)
 		return fmt.Errorf("initialize dns server: %w", err)
 	}
 
+	e.startSpeedTestResponder()
+
 	iceCfg := e.createICEConfig()
 
 	e.connMgr = NewConnMgr(e.config, e.statusRecorder, e.peerStore, wgIface)
@@ -939,7 +959,12 @@ func (e *Engine) updateChecksIfNew(checks []*mgmProto.Checks) error {
 	}
 	e.checks = checks
 
-	info, err := system.GetInfoWithChecks(e.ctx, checks)
+	return e.syncMeta()
+}
+
+// syncMeta sends the current system info, posture check results and unhealthy served routes to management
+func (e *Engine) syncMeta() error {
+	info, err := system.GetInfoWithChecks(e.ctx, e.checks)
 	if err != nil {
 		log.Warnf("failed to get system info with checks: %v", err)
 		info = system.GetInfo(e.ctx)
@@ -961,6 +986,8 @@ func (e *Engine) updateChecksIfNew(checks []*mgmProto.Checks) error {
 		e.config.EnableSSHRemotePortForwarding,
 		e.config.DisableSSHAuth,
 	)
+	info.UnhealthyRoutes = e.unhealthyRoutes
+	info.DiscoveredSubnets = e.discoveredSubnets
 
 	if err := e.mgmClient.SyncMeta(info); err != nil {
 		log.Errorf("could not sync meta: error %s", err)
@@ -969,6 +996,60 @@ func (e *Engine) updateChecksIfNew(checks []*mgmProto.Checks) error {
 	return nil
 }
 
+// onRouteHealthChange reports the served routes whose health check fails to management,
+// which deprioritizes this peer for the routes on the other clients
+func (e *Engine) onRouteHealthChange(unhealthy []route.ID) {
+	e.syncMsgMux.Lock()
+	defer e.syncMsgMux.Unlock()
+
+	if e.ctx.Err() != nil {
+		return
+	}
+
+	ids := make([]string, 0, len(unhealthy))
+	for _, id := range unhealthy {
+		ids = append(ids, string(id))
+	}
+	e.unhealthyRoutes = ids
+
+	if err := e.syncMeta(); err != nil {
+		log.Errorf("failed to report route health: %v", err)
+	}
+}
+
+// updateDiscoveredSubnets reports the routing table entries matching the subnet discovery allowlist to management,
+// which suggests them as network resources. The routing table is checked on every network map update.
+func (e *Engine) updateDiscoveredSubnets(conf *mgmProto.SubnetDiscovery) {
+	var allowlist []netip.Prefix
+	for _, entry := range conf.GetAllowlist() {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			log.Warnf("failed to parse subnet discovery allowlist entry %s: %v", entry, err)
+			continue
+		}
+		allowlist = append(allowlist, prefix)
+	}
+
+	subnets, err := discovery.Discover(allowlist, e.wgInterface.Name())
+	if err != nil {
+		log.Errorf("failed to discover subnets: %v", err)
+		return
+	}
+
+	discovered := make([]string, 0, len(subnets))
+	for _, subnet := range subnets {
+		discovered = append(discovered, subnet.String())
+	}
+	if slices.Equal(discovered, e.discoveredSubnets) {
+		return
+	}
+	e.discoveredSubnets = discovered
+
+	if err := e.syncMeta(); err != nil {
+		log.Errorf("failed to report discovered subnets: %v", err)
+	}
+}
+
 func (e *Engine) updateConfig(conf *mgmProto.PeerConfig) error {
 	if e.wgInterface == nil {
 		return errors.New("wireguard interface is not initialized")
@@ -1071,6 +1152,9 @@ func (e *Engine) handleBundle(params *mgmProto.BundleParameters) (*mgmProto.JobR
 // receiveManagementEvents connects to the Management Service event stream to receive updates from the management service
 // E.g. when a new peer has been registered and we are allowed to connect to it.
 func (e *Engine) receiveManagementEvents() {
+	unhealthyRoutes := slices.Clone(e.unhealthyRoutes)
+	discoveredSubnets := slices.Clone(e.discoveredSubnets)
+
 	e.shutdownWg.Add(1)
 	go func() {
 		defer e.shutdownWg.Done()
@@ -1096,6 +1180,8 @@ func (e *Engine) receiveManagementEvents() {
 			e.config.EnableSSHRemotePortForwarding,
 			e.config.DisableSSHAuth,
 		)
+		info.UnhealthyRoutes = unhealthyRoutes
+		info.DiscoveredSubnets = discoveredSubnets
 
 		err = e.mgmClient.Sync(e.ctx, info, e.handleSync)
 		if err != nil {
@@ -1155,6 +1241,7 @@ func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 		if err != nil {
 			return err
 		}
+		e.updateDiscoveredSubnets(networkMap.GetPeerConfig().GetSubnetDiscovery())
 	}
 
 	serial := networkMap.GetSerial()
@@ -1218,6 +1305,8 @@ func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 	fwdEntries := toRouteDomains(e.config.WgPrivateKey.PublicKey().String(), routes)
 	e.updateDNSForwarder(dnsRouteFeatureFlag, fwdEntries)
 
+	e.updateMulticast(serverRoutes, clientRoutes, networkMap)
+
 	// Ingress forward rules
 	forwardingRules, err := e.updateForwardRules(networkMap.GetForwardingRules())
 	if err != nil {
@@ -1318,12 +1407,78 @@ func toRoutes(protoRoutes []*mgmProto.Route) []*route.Route {
 			Masquerade:    protoRoute.Masquerade,
 			KeepRoute:     protoRoute.KeepRoute,
 			SkipAutoApply: protoRoute.SkipAutoApply,
+			HealthCheck:   toRouteHealthCheck(protoRoute.GetHealthCheck()),
+			Unhealthy:     protoRoute.Unhealthy,
+			NetMap:        toRouteNetMap(protoRoute.GetNetMap()),
+			LoadBalance:   protoRoute.LoadBalance,
+			BGP:           toRouteBGP(protoRoute.GetBgp()),
+			Multicast:     toRouteMulticast(protoRoute.GetMulticast()),
 		}
 		routes = append(routes, convertedRoute)
 	}
 	return routes
 }
 
+func toRouteMulticast(multicast *mgmProto.RouteMulticast) *route.Multicast {
+	if multicast == nil {
+		return nil
+	}
+	routeMulticast := &route.Multicast{
+		MDNS:      multicast.GetMdns(),
+		SSDP:      multicast.GetSsdp(),
+		Broadcast: multicast.GetBroadcast(),
+	}
+	for _, group := range multicast.GetGroups() {
+		addrPort, err := netip.ParseAddrPort(group)
+		if err != nil {
+			log.Errorf("Failed to parse multicast group %s: %v", group, err)
+			continue
+		}
+		routeMulticast.Groups = append(routeMulticast.Groups, addrPort)
+	}
+	return routeMulticast
+}
+
+func toRouteHealthCheck(hc *mgmProto.RouteHealthCheck) *route.HealthCheck {
+	if hc == nil {
+		return nil
+	}
+	return route.NewHealthCheck(route.HealthCheckType(hc.GetType()), hc.GetTarget(), hc.GetInterval().AsDuration(), hc.GetTimeout().AsDuration())
+}
+
+func toRouteBGP(bgp *mgmProto.RouteBGP) *route.BGP {
+	if bgp == nil {
+		return nil
+	}
+	routeBGP := &route.BGP{LocalASN: bgp.GetLocalASN()}
+	for _, neighbor := range bgp.GetNeighbors() {
+		addr, err := netip.ParseAddr(neighbor.GetAddress())
+		if err != nil {
+			log.Errorf("Failed to parse BGP neighbor address %s: %v", neighbor.GetAddress(), err)
+			continue
+		}
+		routeBGP.Neighbors = append(routeBGP.Neighbors, route.BGPNeighbor{Address: addr, ASN: neighbor.GetAsn()})
+	}
+	return routeBGP
+}
+
+func toRouteNetMap(nm *mgmProto.RouteNetMap) *route.NetMap {
+	if nm == nil {
+		return nil
+	}
+	realPrefix, err := netip.ParsePrefix(nm.GetReal())
+	if err != nil {
+		log.Errorf("Failed to parse netmap real prefix %s: %v", nm.GetReal(), err)
+		return nil
+	}
+	virtualPrefix, err := netip.ParsePrefix(nm.GetVirtual())
+	if err != nil {
+		log.Errorf("Failed to parse netmap virtual prefix %s: %v", nm.GetVirtual(), err)
+		return nil
+	}
+	return route.NewNetMap(realPrefix, virtualPrefix)
+}
+
 func toRouteDomains(myPubKey string, routes []*route.Route) []*dnsfwd.ForwarderEntry {
 	var entries []*dnsfwd.ForwarderEntry
 	for _, route := range routes {
@@ -1786,6 +1941,18 @@ func (e *Engine) GetFirewallManager() firewallManager.Manager {
 	return e.firewall
 }
 
+// FirewallRuleCounts returns the number of peer and route ACL rules applied to the firewall
+func (e *Engine) FirewallRuleCounts() (peerRules, routeRules int) {
+	e.syncMsgMux.Lock()
+	aclManager := e.acl
+	e.syncMsgMux.Unlock()
+
+	if aclManager == nil {
+		return 0, 0
+	}
+	return aclManager.RulesCount()
+}
+
 func findIPFromInterfaceName(ifaceName string) (net.IP, error) {
 	iface, err := net.InterfaceByName(ifaceName)
 	if err != nil {
@@ -2040,6 +2207,29 @@ func (e *Engine) updateDNSForwarder(
 	}
 }
 
+// updateMulticast relays the multicast traffic of the served routes and of the selected client routes
+func (e *Engine) updateMulticast(serverRoutes map[route.ID]*route.Route, clientRoutes route.HAMap, networkMap *mgmProto.NetworkMap) {
+	if e.config.DisableServerRoutes {
+		serverRoutes = nil
+	}
+	if e.config.DisableClientRoutes {
+		clientRoutes = nil
+	} else if selector := e.routeManager.GetRouteSelector(); selector != nil {
+		clientRoutes = selector.FilterSelected(clientRoutes)
+	}
+
+	if e.multicastMgr == nil {
+		if len(serverRoutes) == 0 && len(clientRoutes) == 0 {
+			return
+		}
+		e.multicastMgr = multicast.NewManager(e.ctx, e.firewall, e.statusRecorder, e.wgInterface)
+	}
+
+	// management servers that don't send route firewall rules allow all traffic to the routed networks
+	allowAll := len(networkMap.GetRoutesFirewallRules()) == 0 && !networkMap.GetRoutesFirewallRulesIsEmpty()
+	e.multicastMgr.Update(serverRoutes, clientRoutes, networkMap.GetRoutesFirewallRules(), allowAll)
+}
+
 func (e *Engine) startDNSForwarder(fwdEntries []*dnsfwd.ForwarderEntry) {
 	e.dnsForwardMgr = dnsfwd.NewManager(e.firewall, e.statusRecorder, e.wgInterface)
 
@@ -2140,11 +2330,28 @@ func (e *Engine) updateForwardRules(rules []*mgmProto.ForwardingRule) ([]firewal
 			continue
 		}
 
+		var dstAddress netip.Addr
+		if len(rule.GetDestinationAddress()) > 0 {
+			dstAddress, err = convertToIP(rule.GetDestinationAddress())
+			if err != nil {
+				merr = multierror.Append(merr, fmt.Errorf("failed to convert destination address '%s': %w", rule.GetDestinationAddress(), err))
+				continue
+			}
+		}
+
+		sourcePrefixes, err := convertSourceRanges(rule.GetSourceRanges())
+		if err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("invalid source ranges '%v': %w", rule.GetSourceRanges(), err))
+			continue
+		}
+
 		forwardRule := firewallManager.ForwardRule{
-			Protocol:          proto,
-			DestinationPort:   *dstPortInfo,
-			TranslatedAddress: translateIP,
-			TranslatedPort:    *translatePort,
+			Protocol:           proto,
+			DestinationPort:    *dstPortInfo,
+			DestinationAddress: dstAddress,
+			SourcePrefixes:     sourcePrefixes,
+			TranslatedAddress:  translateIP,
+			TranslatedPort:     *translatePort,
 		}
 
 		forwardingRules = append(forwardingRules, forwardRule)
@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/speedtest"
+)
+
+// startSpeedTestResponder starts the responder remote peers run speed tests against.
+// It listens on the NetBird address only, so access policies decide which peers can reach it.
+func (e *Engine) startSpeedTestResponder() {
+	if e.config.BlockInbound {
+		log.Info("speed test responder is disabled because inbound connections are blocked")
+		return
+	}
+	if speedtest.IsResponderDisabledByEnv() {
+		log.Infof("speed test responder is disabled by %s", speedtest.EnvDisableResponder)
+		return
+	}
+
+	server := speedtest.NewServer(func(addr netip.Addr) bool {
+		_, ok := e.statusRecorder.PeerByIP(addr.String())
+		return ok
+	})
+
+	netstackNet := e.wgInterface.GetNet()
+	if netstackNet != nil {
+		server.SetNetstackNet(netstackNet)
+	}
+
+	listenAddr := netip.AddrPortFrom(e.wgInterface.Address().IP, speedtest.Port)
+	if err := server.Start(e.ctx, listenAddr); err != nil {
+		log.Warnf("failed to start speed test responder: %v", err)
+		return
+	}
+	e.speedTestServer = server
+
+	if netstackNet != nil {
+		if registrar, ok := e.firewall.(interface {
+			RegisterNetstackService(protocol nftypes.Protocol, port uint16)
+		}); ok {
+			registrar.RegisterNetstackService(nftypes.TCP, speedtest.Port)
+			registrar.RegisterNetstackService(nftypes.UDP, speedtest.Port)
+		}
+	}
+}
+
+func (e *Engine) stopSpeedTestResponder() {
+	if e.speedTestServer == nil {
+		return
+	}
+
+	if netstackNet := e.wgInterface.GetNet(); netstackNet != nil {
+		if registrar, ok := e.firewall.(interface {
+			UnregisterNetstackService(protocol nftypes.Protocol, port uint16)
+		}); ok {
+			registrar.UnregisterNetstackService(nftypes.TCP, speedtest.Port)
+			registrar.UnregisterNetstackService(nftypes.UDP, speedtest.Port)
+		}
+	}
+
+	if err := e.speedTestServer.Stop(); err != nil {
+		log.Warnf("failed to stop speed test responder: %v", err)
+	}
+	e.speedTestServer = nil
+}
+
+// RunSpeedTest measures the connection to the peer with the given NetBird IP, FQDN or hostname.
+// It returns the peer state after the test, which holds the path the traffic took.
+func (e *Engine) RunSpeedTest(ctx context.Context, peerAddress string, opts speedtest.Options) (*speedtest.Result, peer.State, error) {
+	e.syncMsgMux.Lock()
+	wgIface := e.wgInterface
+	e.syncMsgMux.Unlock()
+	if wgIface == nil {
+		return nil, peer.State{}, errors.New("wireguard interface not initialized")
+	}
+
+	state, err := e.findPeerState(peerAddress)
+	if err != nil {
+		return nil, peer.State{}, err
+	}
+	// idle lazy connections are opened by the test traffic
+	if state.ConnStatus == peer.StatusConnecting {
+		return nil, state, fmt.Errorf("peer %s is not connected", peerAddress)
+	}
+
+	addr, err := netip.ParseAddr(state.IP)
+	if err != nil {
+		return nil, state, fmt.Errorf("parse peer IP %s: %w", state.IP, err)
+	}
+
+	var dialer speedtest.Dialer = &net.Dialer{}
+	if netstackNet := wgIface.GetNet(); netstackNet != nil {
+		dialer = netstackNet
+	}
+
+	result, err := speedtest.Run(ctx, dialer, netip.AddrPortFrom(addr, speedtest.Port), opts)
+	if err != nil {
+		return nil, state, err
+	}
+
+	// report the path used at the end of the test
+	if latest, err := e.statusRecorder.GetPeer(state.PubKey); err == nil {
+		state = latest
+	}
+	return result, state, nil
+}
+
+func (e *Engine) findPeerState(peerAddress string) (peer.State, error) {
+	address := strings.TrimSuffix(strings.ToLower(peerAddress), ".")
+	for _, state := range e.statusRecorder.GetFullStatus().Peers {
+		fqdn := strings.TrimSuffix(strings.ToLower(state.FQDN), ".")
+		hostname, _, _ := strings.Cut(fqdn, ".")
+		if state.IP == address || fqdn == address || hostname == address {
+			return state, nil
+		}
+	}
+	return peer.State{}, fmt.Errorf("peer %s not found", peerAddress)
+}
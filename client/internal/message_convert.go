@@ -56,3 +56,15 @@ func convertToIP(rawIP []byte) (netip.Addr, error) {
 
 	return netip.AddrFrom16([16]byte(rawIP)), nil
 }
+
+func convertSourceRanges(ranges []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(ranges))
+	for _, r := range ranges {
+		prefix, err := netip.ParsePrefix(r)
+		if err != nil {
+			return nil, fmt.Errorf("parse prefix '%s': %w", r, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
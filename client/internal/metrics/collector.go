@@ -0,0 +1,236 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/iface/configurer"
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+const namespace = "netbird"
+
+const (
+	connTypeP2P     = "p2p"
+	connTypeRelayed = "relayed"
+)
+
+// RuleCounter reports the number of firewall rules currently applied by the client
+type RuleCounter interface {
+	FirewallRuleCounts() (peerRules, routeRules int)
+}
+
+// Collector exports the state held by the peer status recorder as Prometheus metrics.
+// All values are read on scrape, so the collector does not hold any state on its own.
+type Collector struct {
+	recorder *peer.Status
+	rules    RuleCounter
+
+	peerConnected    *prometheus.Desc
+	peerHandshakeAge *prometheus.Desc
+	peerLatency      *prometheus.Desc
+	peerRxBytes      *prometheus.Desc
+	peerTxBytes      *prometheus.Desc
+	peers            *prometheus.Desc
+	management       *prometheus.Desc
+	signal           *prometheus.Desc
+	relay            *prometheus.Desc
+	dnsUpstream      *prometheus.Desc
+	firewallRules    *prometheus.Desc
+}
+
+// NewCollector returns a collector reading from the given status recorder.
+// The rule counter is optional, firewall metrics are skipped when it is nil.
+func NewCollector(recorder *peer.Status, rules RuleCounter) *Collector {
+	peerLabels := []string{"peer", "fqdn", "ip"}
+
+	return &Collector{
+		recorder: recorder,
+		rules:    rules,
+
+		peerConnected: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "peer", "connected"),
+			"Whether the connection to the peer is established (1) or not (0)",
+			append(peerLabels, "connection_type", "relay_server"), nil,
+		),
+		peerHandshakeAge: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "peer", "handshake_age_seconds"),
+			"Time since the last WireGuard handshake with the peer",
+			peerLabels, nil,
+		),
+		peerLatency: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "peer", "latency_seconds"),
+			"Last measured tunnel latency to the peer",
+			peerLabels, nil,
+		),
+		peerRxBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "peer", "received_bytes_total"),
+			"Total number of bytes received from the peer over WireGuard",
+			peerLabels, nil,
+		),
+		peerTxBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "peer", "sent_bytes_total"),
+			"Total number of bytes sent to the peer over WireGuard",
+			peerLabels, nil,
+		),
+		peers: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "peers"),
+			"Number of known peers by connection status",
+			[]string{"status"}, nil,
+		),
+		management: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "management", "connected"),
+			"Whether the management connection is established (1) or not (0)",
+			[]string{"url"}, nil,
+		),
+		signal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "signal", "connected"),
+			"Whether the signal connection is established (1) or not (0)",
+			[]string{"url"}, nil,
+		),
+		relay: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "relay", "available"),
+			"Whether the last probe of the STUN, TURN or relay server succeeded (1) or not (0)",
+			[]string{"uri"}, nil,
+		),
+		dnsUpstream: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "dns", "upstream_healthy"),
+			"Whether the nameserver group is enabled and its last probe succeeded (1) or not (0)",
+			[]string{"group", "servers"}, nil,
+		),
+		firewallRules: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "firewall", "rules"),
+			"Number of firewall rules applied by the client",
+			[]string{"type"}, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.peerConnected
+	ch <- c.peerHandshakeAge
+	ch <- c.peerLatency
+	ch <- c.peerRxBytes
+	ch <- c.peerTxBytes
+	ch <- c.peers
+	ch <- c.management
+	ch <- c.signal
+	ch <- c.relay
+	ch <- c.dnsUpstream
+	ch <- c.firewallRules
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	fullStatus := c.recorder.GetFullStatus()
+
+	c.collectPeers(ch, fullStatus.Peers)
+
+	ch <- prometheus.MustNewConstMetric(c.management, prometheus.GaugeValue,
+		boolToFloat(fullStatus.ManagementState.Connected), fullStatus.ManagementState.URL)
+	ch <- prometheus.MustNewConstMetric(c.signal, prometheus.GaugeValue,
+		boolToFloat(fullStatus.SignalState.Connected), fullStatus.SignalState.URL)
+
+	for _, relay := range fullStatus.Relays {
+		ch <- prometheus.MustNewConstMetric(c.relay, prometheus.GaugeValue, boolToFloat(relay.Err == nil), relay.URI)
+	}
+
+	for _, ns := range fullStatus.NSGroupStates {
+		servers := make([]string, 0, len(ns.Servers))
+		for _, server := range ns.Servers {
+			servers = append(servers, server.String())
+		}
+		healthy := ns.Enabled && ns.Error == nil
+		ch <- prometheus.MustNewConstMetric(c.dnsUpstream, prometheus.GaugeValue,
+			boolToFloat(healthy), ns.ID, strings.Join(servers, ","))
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.firewallRules, prometheus.GaugeValue,
+		float64(fullStatus.NumOfForwardingRules), "forwarding")
+
+	if c.rules != nil {
+		peerRules, routeRules := c.rules.FirewallRuleCounts()
+		ch <- prometheus.MustNewConstMetric(c.firewallRules, prometheus.GaugeValue, float64(peerRules), "peer")
+		ch <- prometheus.MustNewConstMetric(c.firewallRules, prometheus.GaugeValue, float64(routeRules), "route")
+	}
+}
+
+func (c *Collector) collectPeers(ch chan<- prometheus.Metric, peers []peer.State) {
+	wgStats := c.wireGuardStats()
+	now := time.Now()
+
+	byStatus := map[peer.ConnStatus]int{
+		peer.StatusIdle:       0,
+		peer.StatusConnecting: 0,
+		peer.StatusConnected:  0,
+	}
+
+	for _, state := range peers {
+		byStatus[state.ConnStatus]++
+
+		labels := []string{state.PubKey, state.FQDN, state.IP}
+
+		connected := state.ConnStatus == peer.StatusConnected
+		var connType, relayServer string
+		if connected {
+			connType = connTypeP2P
+			if state.Relayed {
+				connType = connTypeRelayed
+				relayServer = state.RelayServerAddress
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(c.peerConnected, prometheus.GaugeValue,
+			boolToFloat(connected), append(labels, connType, relayServer)...)
+
+		handshake, rx, tx := state.LastWireguardHandshake, state.BytesRx, state.BytesTx
+		if stats, ok := wgStats[state.PubKey]; ok {
+			handshake, rx, tx = stats.LastHandshake, stats.RxBytes, stats.TxBytes
+		}
+
+		if !handshake.IsZero() {
+			ch <- prometheus.MustNewConstMetric(c.peerHandshakeAge, prometheus.GaugeValue,
+				now.Sub(handshake).Seconds(), labels...)
+		}
+		if state.Latency > 0 {
+			ch <- prometheus.MustNewConstMetric(c.peerLatency, prometheus.GaugeValue,
+				state.Latency.Seconds(), labels...)
+		}
+		ch <- prometheus.MustNewConstMetric(c.peerRxBytes, prometheus.CounterValue, float64(rx), labels...)
+		ch <- prometheus.MustNewConstMetric(c.peerTxBytes, prometheus.CounterValue, float64(tx), labels...)
+	}
+
+	for status, count := range byStatus {
+		ch <- prometheus.MustNewConstMetric(c.peers, prometheus.GaugeValue, float64(count), strings.ToLower(status.String()))
+	}
+}
+
+// wireGuardStats reads the current transfer counters from the interface, so scrapes are not
+// limited to the values stored by the last health probe
+func (c *Collector) wireGuardStats() map[string]configurer.WGStats {
+	stats, err := c.recorder.PeersStatus()
+	if err != nil {
+		log.Tracef("failed to read WireGuard stats for metrics: %v", err)
+		return nil
+	}
+
+	result := make(map[string]configurer.WGStats, len(stats.Peers))
+	for _, p := range stats.Peers {
+		result[p.PublicKey] = configurer.WGStats{
+			LastHandshake: p.LastHandshake,
+			TxBytes:       p.TxBytes,
+			RxBytes:       p.RxBytes,
+		}
+	}
+	return result
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+)
+
+type staticRules struct {
+	peer, route int
+}
+
+func (s staticRules) FirewallRuleCounts() (int, int) {
+	return s.peer, s.route
+}
+
+func TestCollector_Collect(t *testing.T) {
+	recorder := peer.NewRecorder("https://mgm.example.com")
+	recorder.MarkManagementConnected()
+	recorder.MarkSignalDisconnected(errors.New("signal down"))
+	recorder.UpdateDNSStates([]peer.NSGroupState{
+		{ID: "ns1", Servers: []netip.AddrPort{netip.MustParseAddrPort("1.1.1.1:53")}, Enabled: true},
+		{ID: "ns2", Servers: []netip.AddrPort{netip.MustParseAddrPort("8.8.8.8:53")}, Enabled: true, Error: errors.New("timeout")},
+	})
+
+	require.NoError(t, recorder.AddPeer("key1", "peer1.netbird.cloud", "100.64.0.1"))
+	require.NoError(t, recorder.AddPeer("key2", "peer2.netbird.cloud", "100.64.0.2"))
+	require.NoError(t, recorder.UpdatePeerRelayedState(peer.State{
+		PubKey:             "key1",
+		ConnStatus:         peer.StatusConnected,
+		Relayed:            true,
+		RelayServerAddress: "rels://relay.example.com:443",
+	}))
+	require.NoError(t, recorder.UpdateLatency("key1", 20*time.Millisecond))
+
+	collector := NewCollector(recorder, staticRules{peer: 4, route: 2})
+
+	expected := `
+# HELP netbird_peer_connected Whether the connection to the peer is established (1) or not (0)
+# TYPE netbird_peer_connected gauge
+netbird_peer_connected{connection_type="relayed",fqdn="peer1.netbird.cloud",ip="100.64.0.1",peer="key1",relay_server="rels://relay.example.com:443"} 1
+netbird_peer_connected{connection_type="",fqdn="peer2.netbird.cloud",ip="100.64.0.2",peer="key2",relay_server=""} 0
+# HELP netbird_peer_latency_seconds Last measured tunnel latency to the peer
+# TYPE netbird_peer_latency_seconds gauge
+netbird_peer_latency_seconds{fqdn="peer1.netbird.cloud",ip="100.64.0.1",peer="key1"} 0.02
+# HELP netbird_peers Number of known peers by connection status
+# TYPE netbird_peers gauge
+netbird_peers{status="connected"} 1
+netbird_peers{status="connecting"} 0
+netbird_peers{status="idle"} 1
+# HELP netbird_management_connected Whether the management connection is established (1) or not (0)
+# TYPE netbird_management_connected gauge
+netbird_management_connected{url="https://mgm.example.com"} 1
+# HELP netbird_signal_connected Whether the signal connection is established (1) or not (0)
+# TYPE netbird_signal_connected gauge
+netbird_signal_connected{url=""} 0
+# HELP netbird_dns_upstream_healthy Whether the nameserver group is enabled and its last probe succeeded (1) or not (0)
+# TYPE netbird_dns_upstream_healthy gauge
+netbird_dns_upstream_healthy{group="ns1",servers="1.1.1.1:53"} 1
+netbird_dns_upstream_healthy{group="ns2",servers="8.8.8.8:53"} 0
+# HELP netbird_firewall_rules Number of firewall rules applied by the client
+# TYPE netbird_firewall_rules gauge
+netbird_firewall_rules{type="forwarding"} 0
+netbird_firewall_rules{type="peer"} 4
+netbird_firewall_rules{type="route"} 2
+`
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"netbird_peer_connected",
+		"netbird_peer_latency_seconds",
+		"netbird_peers",
+		"netbird_management_connected",
+		"netbird_signal_connected",
+		"netbird_dns_upstream_healthy",
+		"netbird_firewall_rules",
+	)
+	assert.NoError(t, err)
+}
+
+func TestCollector_WithoutRuleCounter(t *testing.T) {
+	collector := NewCollector(peer.NewRecorder(""), nil)
+
+	count := testutil.CollectAndCount(collector, "netbird_firewall_rules")
+	assert.Equal(t, 1, count, "only the forwarding rules count should be exported")
+}
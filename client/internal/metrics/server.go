@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Endpoint is the HTTP path the metrics are served under
+	Endpoint = "/metrics"
+
+	readHeaderTimeout = 5 * time.Second
+)
+
+// Server serves the client metrics over HTTP
+type Server struct {
+	server *http.Server
+}
+
+// NewServer creates a metrics server listening on the given address.
+// The collector is registered in a dedicated registry together with the Go runtime and process collectors.
+func NewServer(addr string, collector prometheus.Collector) (*Server, error) {
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(collector); err != nil {
+		return nil, fmt.Errorf("register collector: %w", err)
+	}
+	if err := registry.Register(collectors.NewGoCollector()); err != nil {
+		return nil, fmt.Errorf("register go collector: %w", err)
+	}
+	if err := registry.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})); err != nil {
+		return nil, fmt.Errorf("register process collector: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(Endpoint, promhttp.HandlerFor(registry, promhttp.HandlerOpts{EnableOpenMetrics: true}))
+
+	return &Server{
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: readHeaderTimeout,
+		},
+	}, nil
+}
+
+// Start serves the metrics in the background until Shutdown is called
+func (s *Server) Start() {
+	go func() {
+		log.Infof("serving client metrics on http://%s%s", s.server.Addr, Endpoint)
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("failed to serve client metrics: %v", err)
+		}
+	}()
+}
+
+// Shutdown stops the metrics server
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutdown metrics server: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package multicast
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/ipv4"
+)
+
+const (
+	// linkLocalTTL is required by link-local protocols like mDNS, the datagrams aren't forwarded by routers anyway
+	linkLocalTTL = 255
+	// defaultTTL keeps the relayed datagrams within the site, as recommended for SSDP
+	defaultTTL = 2
+)
+
+var linkLocalMulticast = netip.MustParsePrefix("224.0.0.0/24")
+
+// groupConn receives and sends the datagrams of a multicast group on a set of interfaces
+type groupConn struct {
+	group  netip.AddrPort
+	conn   *ipv4.PacketConn
+	ifaces []net.Interface
+	// mu serializes selecting the outgoing interface and sending
+	mu sync.Mutex
+}
+
+// listenGroup joins the group on the interfaces, loopback delivers the sent datagrams to the listeners of the host
+func listenGroup(ctx context.Context, group netip.AddrPort, ifaces []net.Interface, loopback bool) (*groupConn, error) {
+	lc := net.ListenConfig{Control: reuseControl}
+	c, err := lc.ListenPacket(ctx, "udp4", bindAddr(group))
+	if err != nil {
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+
+	conn := ipv4.NewPacketConn(c)
+	groupAddr := &net.UDPAddr{IP: group.Addr().AsSlice()}
+	var joined []net.Interface
+	for _, iface := range ifaces {
+		if err := conn.JoinGroup(&iface, groupAddr); err != nil {
+			log.Warnf("failed to join multicast group %s on interface %s: %v", group.Addr(), iface.Name, err)
+			continue
+		}
+		joined = append(joined, iface)
+	}
+	if len(joined) == 0 {
+		_ = c.Close()
+		return nil, fmt.Errorf("join group %s on interfaces", group.Addr())
+	}
+
+	// the receiving interface isn't reported on all platforms, the source address is checked by the callers
+	if err := conn.SetControlMessage(ipv4.FlagInterface, true); err != nil {
+		log.Debugf("failed to enable interface control messages for group %s: %v", group, err)
+	}
+	if err := conn.SetMulticastLoopback(loopback); err != nil {
+		log.Debugf("failed to set multicast loopback for group %s: %v", group, err)
+	}
+	ttl := defaultTTL
+	if linkLocalMulticast.Contains(group.Addr()) {
+		ttl = linkLocalTTL
+	}
+	if err := conn.SetMulticastTTL(ttl); err != nil {
+		log.Debugf("failed to set multicast TTL for group %s: %v", group, err)
+	}
+
+	return &groupConn{group: group, conn: conn, ifaces: joined}, nil
+}
+
+// read returns the next datagram received on one of the joined interfaces
+func (g *groupConn) read(buf []byte) ([]byte, netip.Addr, error) {
+	for {
+		n, cm, src, err := g.conn.ReadFrom(buf)
+		if err != nil {
+			return nil, netip.Addr{}, err
+		}
+		if cm != nil && cm.IfIndex != 0 && !slices.ContainsFunc(g.ifaces, func(iface net.Interface) bool {
+			return iface.Index == cm.IfIndex
+		}) {
+			continue
+		}
+		udpAddr, ok := src.(*net.UDPAddr)
+		if !ok {
+			continue
+		}
+		srcAddr, ok := netip.AddrFromSlice(udpAddr.IP)
+		if !ok {
+			continue
+		}
+		return buf[:n], srcAddr.Unmap(), nil
+	}
+}
+
+// send sends the datagram to the group on all joined interfaces
+func (g *groupConn) send(payload []byte) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	dst := net.UDPAddrFromAddrPort(g.group)
+	for _, iface := range g.ifaces {
+		if err := g.conn.SetMulticastInterface(&iface); err != nil {
+			log.Debugf("failed to select interface %s for group %s: %v", iface.Name, g.group, err)
+			continue
+		}
+		if _, err := g.conn.WriteTo(payload, nil, dst); err != nil {
+			log.Debugf("failed to send to group %s on interface %s: %v", g.group, iface.Name, err)
+		}
+	}
+}
+
+func (g *groupConn) close() {
+	if err := g.conn.Close(); err != nil {
+		log.Debugf("failed to close socket of group %s: %v", g.group, err)
+	}
+}
+
+// sameInterfaces reports whether the group is joined on exactly the interfaces
+func (g *groupConn) sameInterfaces(ifaces []net.Interface) bool {
+	return slices.EqualFunc(g.ifaces, ifaces, func(a, b net.Interface) bool {
+		return a.Index == b.Index
+	})
+}
+
+// lanInterfaces returns the multicast capable interfaces with an IPv4 address within one of the networks
+func lanInterfaces(networks []netip.Prefix) ([]net.Interface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("list interfaces: %w", err)
+	}
+
+	var lan []net.Interface
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagMulticast == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			log.Debugf("failed to get addresses of interface %s: %v", iface.Name, err)
+			continue
+		}
+		if slices.ContainsFunc(addrs, func(addr net.Addr) bool {
+			return addrInNetworks(addr, networks)
+		}) {
+			lan = append(lan, iface)
+		}
+	}
+	return lan, nil
+}
+
+func addrInNetworks(addr net.Addr, networks []netip.Prefix) bool {
+	ipNet, ok := addr.(*net.IPNet)
+	if !ok {
+		return false
+	}
+	ip, ok := netip.AddrFromSlice(ipNet.IP)
+	if !ok || !ip.Unmap().Is4() {
+		return false
+	}
+	return slices.ContainsFunc(networks, func(network netip.Prefix) bool {
+		return network.Contains(ip.Unmap())
+	})
+}
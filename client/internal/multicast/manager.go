@@ -0,0 +1,322 @@
+package multicast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.zx2c4.com/wireguard/tun/netstack"
+
+	firewall "github.com/netbirdio/netbird/client/firewall/manager"
+	"github.com/netbirdio/netbird/client/iface/wgaddr"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/route"
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+const (
+	// RelayPort is the UDP port on the NetBird address the peers and the routing peers relay multicast traffic on
+	RelayPort = 22055
+	// refreshInterval is how often the subscriptions are renewed and the routing peers are chosen again
+	refreshInterval = 30 * time.Second
+)
+
+// wgIface defines the interface for WireGuard interface operations needed by the multicast relay
+type wgIface interface {
+	Name() string
+	Address() wgaddr.Address
+	GetNet() *netstack.Net
+}
+
+// broadcastSetter is implemented by firewalls forwarding routed traffic in userspace
+type broadcastSetter interface {
+	SetBroadcastPrefixes(prefixes []netip.Prefix)
+}
+
+// Manager relays the multicast traffic of routed networks. As a routing peer it relays the groups of the served routes
+// between the LAN and the subscribed peers and forwards directed broadcasts into the routed networks, as a peer it
+// subscribes to the groups of the used routes and relays them to and from the local applications.
+type Manager struct {
+	ctx            context.Context
+	cancel         context.CancelFunc
+	firewall       firewall.Manager
+	statusRecorder *peer.Status
+	wgIface        wgIface
+
+	mu         sync.Mutex
+	relay      *relay
+	fwRules    []firewall.Rule
+	router     *router
+	subscriber *subscriber
+	// sysctlKeys are the original values of the sysctl keys changed to forward directed broadcasts
+	sysctlKeys map[string]int
+}
+
+func NewManager(ctx context.Context, fw firewall.Manager, statusRecorder *peer.Status, wgIface wgIface) *Manager {
+	mCtx, cancel := context.WithCancel(ctx)
+	return &Manager{
+		ctx:            mCtx,
+		cancel:         cancel,
+		firewall:       fw,
+		statusRecorder: statusRecorder,
+		wgIface:        wgIface,
+		sysctlKeys:     make(map[string]int),
+	}
+}
+
+// Update applies the multicast configuration of the served and used routes. The route firewall rules decide which peers
+// may exchange the traffic of the groups with the routed networks, allowAll is set for management servers that don't
+// send them.
+func (m *Manager) Update(serverRoutes map[route.ID]*route.Route, clientRoutes route.HAMap, rules []*mgmProto.RouteFirewallRule, allowAll bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.updateBroadcast(broadcastPrefixes(serverRoutes))
+
+	// the peer side needs the NetBird interface of the host to exchange the datagrams with the applications
+	if m.wgIface.GetNet() != nil {
+		clientRoutes = nil
+	}
+	if !relaysMulticast(serverRoutes, clientRoutes) {
+		m.stopRelay()
+		return
+	}
+
+	if m.relay == nil {
+		if err := m.startRelay(); err != nil {
+			log.Errorf("failed to start multicast relay: %v", err)
+			return
+		}
+	}
+	m.router.update(serverRoutes, newPolicy(rules, allowAll))
+	m.subscriber.update(clientRoutes)
+}
+
+// Stop stops relaying and restores the broadcast forwarding settings
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stopRelay()
+	m.updateBroadcast(nil)
+	m.cancel()
+}
+
+func relaysMulticast(serverRoutes map[route.ID]*route.Route, clientRoutes route.HAMap) bool {
+	for _, r := range serverRoutes {
+		if len(r.Multicast.RelayedGroups()) > 0 {
+			return true
+		}
+	}
+	for _, routes := range clientRoutes {
+		for _, r := range routes {
+			if len(r.Multicast.RelayedGroups()) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// broadcastPrefixes returns the served networks whose directed broadcasts are forwarded
+func broadcastPrefixes(serverRoutes map[route.ID]*route.Route) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, r := range serverRoutes {
+		if r.Multicast == nil || !r.Multicast.Broadcast || r.IsDynamic() || !r.Network.Addr().Is4() {
+			continue
+		}
+		prefixes = append(prefixes, r.Network.Masked())
+	}
+	return prefixes
+}
+
+func (m *Manager) updateBroadcast(prefixes []netip.Prefix) {
+	if setter, ok := m.firewall.(broadcastSetter); ok {
+		setter.SetBroadcastPrefixes(prefixes)
+	}
+	if m.wgIface.GetNet() == nil {
+		m.updateBroadcastForwarding(prefixes)
+	}
+}
+
+func (m *Manager) startRelay() error {
+	addr := netip.AddrPortFrom(m.wgIface.Address().IP, RelayPort)
+
+	var conn net.PacketConn
+	var err error
+	if nsNet := m.wgIface.GetNet(); nsNet != nil {
+		conn, err = nsNet.ListenUDPAddrPort(addr)
+	} else {
+		conn, err = net.ListenUDP("udp4", net.UDPAddrFromAddrPort(addr))
+	}
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", addr, err)
+	}
+
+	if err := m.allowRelayFirewall(); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	m.relay = newRelay(conn)
+	m.router = newRouter(m.ctx, m.relay)
+	m.subscriber = newSubscriber(m.ctx, m.relay, m.statusRecorder, m.wgIface.Name(), addr.Addr())
+	if m.wgIface.GetNet() == nil {
+		if err := enableInterfaceMulticast(m.wgIface.Name()); err != nil {
+			log.Warnf("failed to enable multicast on interface %s: %v", m.wgIface.Name(), err)
+		}
+	}
+
+	go m.readLoop(m.relay, m.router, m.subscriber)
+	go m.refreshLoop(m.relay, m.router, m.subscriber)
+
+	log.Infof("started multicast relay on %s", addr)
+	return nil
+}
+
+func (m *Manager) stopRelay() {
+	if m.relay == nil {
+		return
+	}
+
+	m.subscriber.close()
+	m.router.close()
+	m.relay.close()
+	m.dropRelayFirewall()
+
+	m.relay = nil
+	m.router = nil
+	m.subscriber = nil
+	log.Infof("stopped multicast relay")
+}
+
+func (m *Manager) readLoop(relay *relay, router *router, subscriber *subscriber) {
+	buf := make([]byte, 65535)
+	for {
+		n, from, err := relay.conn.ReadFrom(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) && m.ctx.Err() == nil {
+				log.Errorf("failed to read from multicast relay socket: %v", err)
+			}
+			return
+		}
+
+		udpAddr, ok := from.(*net.UDPAddr)
+		if !ok {
+			continue
+		}
+		peerIP, ok := netip.AddrFromSlice(udpAddr.IP)
+		if !ok || !m.wgIface.Address().Network.Contains(peerIP.Unmap()) {
+			continue
+		}
+		peerIP = peerIP.Unmap()
+
+		msg, err := unmarshal(buf[:n])
+		if err != nil {
+			log.Tracef("dropping multicast relay message from %s: %v", peerIP, err)
+			continue
+		}
+
+		switch msg.typ {
+		case msgSubscribe:
+			router.handleSubscribe(peerIP, msg.groups)
+		case msgData:
+			if !router.handleData(peerIP, msg.group, msg.payload) {
+				subscriber.handleData(peerIP, msg.group, msg.payload)
+			}
+		}
+	}
+}
+
+func (m *Manager) refreshLoop(relay *relay, router *router, subscriber *subscriber) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-relay.done:
+			return
+		case <-ticker.C:
+			router.prune()
+			subscriber.refresh()
+		}
+	}
+}
+
+func (m *Manager) allowRelayFirewall() error {
+	if m.firewall == nil {
+		return nil
+	}
+
+	dport := &firewall.Port{Values: []uint16{RelayPort}}
+	rules, err := m.firewall.AddPeerFiltering(nil, net.IP{0, 0, 0, 0}, firewall.ProtocolUDP, nil, dport, firewall.ActionAccept, "")
+	if err != nil {
+		return fmt.Errorf("add multicast relay firewall rule: %w", err)
+	}
+	if err := m.firewall.Flush(); err != nil {
+		return fmt.Errorf("flush: %w", err)
+	}
+	m.fwRules = rules
+
+	if m.wgIface.GetNet() != nil {
+		if registrar, ok := m.firewall.(interface {
+			RegisterNetstackService(protocol nftypes.Protocol, port uint16)
+		}); ok {
+			registrar.RegisterNetstackService(nftypes.UDP, RelayPort)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) dropRelayFirewall() {
+	if m.firewall == nil {
+		return
+	}
+
+	if m.wgIface.GetNet() != nil {
+		if registrar, ok := m.firewall.(interface {
+			UnregisterNetstackService(protocol nftypes.Protocol, port uint16)
+		}); ok {
+			registrar.UnregisterNetstackService(nftypes.UDP, RelayPort)
+		}
+	}
+
+	for _, rule := range m.fwRules {
+		if err := m.firewall.DeletePeerRule(rule); err != nil {
+			log.Errorf("failed to delete multicast relay firewall rule: %v", err)
+		}
+	}
+	m.fwRules = nil
+}
+
+// relay sends the relay messages to the NetBird addresses of the peers
+type relay struct {
+	conn net.PacketConn
+	// done is closed when the relay is stopped
+	done chan struct{}
+}
+
+func newRelay(conn net.PacketConn) *relay {
+	return &relay{conn: conn, done: make(chan struct{})}
+}
+
+func (r *relay) send(peerIP netip.Addr, msg []byte) {
+	if _, err := r.conn.WriteTo(msg, net.UDPAddrFromAddrPort(netip.AddrPortFrom(peerIP, RelayPort))); err != nil {
+		log.Debugf("failed to send multicast relay message to %s: %v", peerIP, err)
+	}
+}
+
+func (r *relay) close() {
+	close(r.done)
+	if err := r.conn.Close(); err != nil {
+		log.Debugf("failed to close multicast relay socket: %v", err)
+	}
+}
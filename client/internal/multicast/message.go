@@ -0,0 +1,124 @@
+package multicast
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/netip"
+)
+
+// The relay messages are exchanged over UDP between the NetBird addresses of the peers and the routing peers:
+//
+//	magic (4) | version (1) | type (1) | body
+//
+// A data message carries a multicast datagram, the body is the IPv4 group (4), the UDP port of the group (2) and the
+// payload. A subscribe message carries the groups a peer wants to receive from a routing peer, the body is the number
+// of groups (1) followed by the groups, each encoded as the IPv4 address (4) and the port (2). A subscribe message
+// without groups cancels the subscription.
+
+const (
+	magic         = "NBMC"
+	version       = 1
+	headerLen     = len(magic) + 2
+	groupLen      = 6
+	maxGroups     = 255
+	maxPayloadLen = 65507 - headerLen - groupLen
+)
+
+type messageType uint8
+
+const (
+	msgData      messageType = 1
+	msgSubscribe messageType = 2
+)
+
+var errInvalidMessage = errors.New("invalid relay message")
+
+type message struct {
+	typ messageType
+	// group is the group of a data message
+	group netip.AddrPort
+	// payload is the multicast datagram of a data message
+	payload []byte
+	// groups are the groups of a subscribe message
+	groups []netip.AddrPort
+}
+
+func marshalData(group netip.AddrPort, payload []byte) ([]byte, error) {
+	if len(payload) > maxPayloadLen {
+		return nil, fmt.Errorf("payload of %d bytes exceeds %d bytes", len(payload), maxPayloadLen)
+	}
+
+	b := make([]byte, 0, headerLen+groupLen+len(payload))
+	b = appendHeader(b, msgData)
+	b, err := appendGroup(b, group)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, payload...), nil
+}
+
+func marshalSubscribe(groups []netip.AddrPort) ([]byte, error) {
+	if len(groups) > maxGroups {
+		return nil, fmt.Errorf("%d groups exceed %d groups", len(groups), maxGroups)
+	}
+
+	b := make([]byte, 0, headerLen+1+len(groups)*groupLen)
+	b = appendHeader(b, msgSubscribe)
+	b = append(b, byte(len(groups)))
+	for _, group := range groups {
+		var err error
+		if b, err = appendGroup(b, group); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func appendHeader(b []byte, typ messageType) []byte {
+	b = append(b, magic...)
+	return append(b, version, byte(typ))
+}
+
+func appendGroup(b []byte, group netip.AddrPort) ([]byte, error) {
+	if !group.Addr().Is4() {
+		return nil, fmt.Errorf("group %s isn't IPv4", group)
+	}
+	addr := group.Addr().As4()
+	b = append(b, addr[:]...)
+	return binary.BigEndian.AppendUint16(b, group.Port()), nil
+}
+
+func unmarshal(b []byte) (*message, error) {
+	if len(b) < headerLen || string(b[:len(magic)]) != magic {
+		return nil, errInvalidMessage
+	}
+	if b[len(magic)] != version {
+		return nil, fmt.Errorf("unsupported relay message version %d", b[len(magic)])
+	}
+
+	msg := &message{typ: messageType(b[len(magic)+1])}
+	body := b[headerLen:]
+	switch msg.typ {
+	case msgData:
+		if len(body) < groupLen {
+			return nil, errInvalidMessage
+		}
+		msg.group = parseGroup(body)
+		msg.payload = body[groupLen:]
+	case msgSubscribe:
+		if len(body) < 1 || len(body) != 1+int(body[0])*groupLen {
+			return nil, errInvalidMessage
+		}
+		for i := 1; i < len(body); i += groupLen {
+			msg.groups = append(msg.groups, parseGroup(body[i:]))
+		}
+	default:
+		return nil, fmt.Errorf("unknown relay message type %d", msg.typ)
+	}
+	return msg, nil
+}
+
+func parseGroup(b []byte) netip.AddrPort {
+	return netip.AddrPortFrom(netip.AddrFrom4([4]byte(b[:4])), binary.BigEndian.Uint16(b[4:groupLen]))
+}
@@ -0,0 +1,68 @@
+package multicast
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessage_Data(t *testing.T) {
+	group := netip.MustParseAddrPort("239.1.2.3:5000")
+	payload := []byte("stream")
+
+	b, err := marshalData(group, payload)
+	require.NoError(t, err)
+
+	msg, err := unmarshal(b)
+	require.NoError(t, err)
+	assert.Equal(t, msgData, msg.typ)
+	assert.Equal(t, group, msg.group)
+	assert.Equal(t, payload, msg.payload)
+
+	_, err = marshalData(netip.MustParseAddrPort("[ff02::fb]:5353"), payload)
+	assert.Error(t, err, "IPv6 groups aren't supported")
+}
+
+func TestMessage_Subscribe(t *testing.T) {
+	groups := []netip.AddrPort{
+		netip.MustParseAddrPort("224.0.0.251:5353"),
+		netip.MustParseAddrPort("239.255.255.250:1900"),
+	}
+
+	b, err := marshalSubscribe(groups)
+	require.NoError(t, err)
+
+	msg, err := unmarshal(b)
+	require.NoError(t, err)
+	assert.Equal(t, msgSubscribe, msg.typ)
+	assert.Equal(t, groups, msg.groups)
+
+	b, err = marshalSubscribe(nil)
+	require.NoError(t, err)
+	msg, err = unmarshal(b)
+	require.NoError(t, err)
+	assert.Empty(t, msg.groups, "an empty subscription cancels the subscription")
+}
+
+func TestMessage_Invalid(t *testing.T) {
+	valid, err := marshalSubscribe([]netip.AddrPort{netip.MustParseAddrPort("224.0.0.251:5353")})
+	require.NoError(t, err)
+
+	tests := map[string][]byte{
+		"empty":              nil,
+		"wrong magic":        append([]byte("XXXX"), valid[4:]...),
+		"wrong version":      append(append([]byte(magic), 2), valid[5:]...),
+		"unknown type":       append([]byte(magic), version, 9),
+		"truncated data":     append([]byte(magic), version, byte(msgData), 224, 0),
+		"truncated groups":   valid[:len(valid)-1],
+		"group count excess": append(valid, 0),
+	}
+	for name, b := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := unmarshal(b)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+//go:build !android
+
+package multicast
+
+import (
+	"fmt"
+	"net/netip"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+
+	"github.com/netbirdio/netbird/client/internal/routemanager/sysctl"
+)
+
+const (
+	bcForwardingPath          = "net.ipv4.conf.all.bc_forwarding"
+	bcForwardingInterfacePath = "net.ipv4.conf.%s.bc_forwarding"
+)
+
+// updateBroadcastForwarding enables the kernel to forward directed broadcasts to the LAN interfaces of the networks and
+// restores the original settings of the interfaces that are no longer needed
+func (m *Manager) updateBroadcastForwarding(prefixes []netip.Prefix) {
+	desired := make(map[string]struct{})
+	if len(prefixes) > 0 {
+		desired[bcForwardingPath] = struct{}{}
+		ifaces, err := lanInterfaces(prefixes)
+		if err != nil {
+			log.Errorf("failed to find the LAN interfaces to forward broadcasts to: %v", err)
+		}
+		for _, iface := range ifaces {
+			desired[fmt.Sprintf(bcForwardingInterfacePath, iface.Name)] = struct{}{}
+		}
+	}
+
+	for key, original := range m.sysctlKeys {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		if _, err := sysctl.Set(key, original, false); err != nil {
+			log.Warnf("failed to restore %s: %v", key, err)
+		}
+		delete(m.sysctlKeys, key)
+	}
+
+	for key := range desired {
+		if _, ok := m.sysctlKeys[key]; ok {
+			continue
+		}
+		original, err := sysctl.Set(key, 1, false)
+		if err != nil {
+			log.Warnf("failed to enable broadcast forwarding, %s: %v", key, err)
+			continue
+		}
+		m.sysctlKeys[key] = original
+	}
+}
+
+// enableInterfaceMulticast sets the multicast flag of the WireGuard interface, which is off by default, so applications
+// and the relay can use multicast on it
+func enableInterfaceMulticast(name string) error {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return fmt.Errorf("get link: %w", err)
+	}
+	if err := netlink.LinkSetMulticastOn(link); err != nil {
+		return fmt.Errorf("set multicast flag: %w", err)
+	}
+	return nil
+}
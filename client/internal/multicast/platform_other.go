@@ -0,0 +1,16 @@
+//go:build !linux || android
+
+package multicast
+
+import "net/netip"
+
+// updateBroadcastForwarding is a no-op, directed broadcasts are only forwarded by the userspace forwarder on this
+// platform
+func (m *Manager) updateBroadcastForwarding([]netip.Prefix) {
+	// nothing to do
+}
+
+// enableInterfaceMulticast is a no-op, the interface is multicast capable on this platform
+func enableInterfaceMulticast(string) error {
+	return nil
+}
@@ -0,0 +1,99 @@
+package multicast
+
+import (
+	"net/netip"
+
+	log "github.com/sirupsen/logrus"
+
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+// routeRule is a route firewall rule reduced to what decides whether a peer may exchange the UDP traffic of a group
+// with a routed network
+type routeRule struct {
+	sources     []netip.Prefix
+	destination netip.Prefix
+	// anyProtocol is set for rules that aren't limited to UDP
+	anyProtocol bool
+	// ports are empty if the rule applies to all ports
+	portStart, portEnd uint16
+	drop               bool
+}
+
+// policy decides which peers may receive and send the multicast traffic of the routed networks
+type policy struct {
+	rules []routeRule
+	// allowAll is set when the management server doesn't send route firewall rules
+	allowAll bool
+}
+
+func newPolicy(rules []*mgmProto.RouteFirewallRule, allowAll bool) *policy {
+	p := &policy{allowAll: allowAll}
+	for _, rule := range rules {
+		if rule.GetIsDynamic() {
+			continue
+		}
+		protocol := rule.GetProtocol()
+		if protocol != mgmProto.RuleProtocol_ALL && protocol != mgmProto.RuleProtocol_UDP {
+			continue
+		}
+		destination, err := netip.ParsePrefix(rule.GetDestination())
+		if err != nil {
+			log.Debugf("skipping route firewall rule with destination %q: %v", rule.GetDestination(), err)
+			continue
+		}
+
+		r := routeRule{
+			destination: destination,
+			anyProtocol: protocol == mgmProto.RuleProtocol_ALL,
+			drop:        rule.GetAction() == mgmProto.RuleAction_DROP,
+		}
+		for _, sourceRange := range rule.GetSourceRanges() {
+			if source, err := netip.ParsePrefix(sourceRange); err == nil {
+				r.sources = append(r.sources, source)
+			}
+		}
+		if portRange := rule.GetPortInfo().GetRange(); portRange != nil {
+			r.portStart, r.portEnd = uint16(portRange.GetStart()), uint16(portRange.GetEnd())
+		} else if port := rule.GetPortInfo().GetPort(); port != 0 {
+			r.portStart, r.portEnd = uint16(port), uint16(port)
+		}
+		p.rules = append(p.rules, r)
+	}
+	return p
+}
+
+// allowed reports whether the peer may exchange the traffic of the group with the network: a rule accepting the UDP
+// port of the group from the peer to the network is required and a matching drop rule takes precedence
+func (p *policy) allowed(peerIP netip.Addr, network netip.Prefix, group netip.AddrPort) bool {
+	if p.allowAll {
+		return true
+	}
+
+	var accepted bool
+	for _, rule := range p.rules {
+		if !rule.matches(peerIP, network, group.Port()) {
+			continue
+		}
+		if rule.drop {
+			return false
+		}
+		accepted = true
+	}
+	return accepted
+}
+
+func (r *routeRule) matches(peerIP netip.Addr, network netip.Prefix, port uint16) bool {
+	if !r.destination.Overlaps(network) {
+		return false
+	}
+	if !r.anyProtocol && r.portEnd != 0 && (port < r.portStart || port > r.portEnd) {
+		return false
+	}
+	for _, source := range r.sources {
+		if source.Contains(peerIP) {
+			return true
+		}
+	}
+	return false
+}
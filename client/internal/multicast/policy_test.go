@@ -0,0 +1,55 @@
+package multicast
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	mgmProto "github.com/netbirdio/netbird/shared/management/proto"
+)
+
+func TestPolicy_Allowed(t *testing.T) {
+	lan := netip.MustParsePrefix("192.168.1.0/24")
+	mdns := netip.MustParseAddrPort("224.0.0.251:5353")
+	ssdp := netip.MustParseAddrPort("239.255.255.250:1900")
+	peerA := netip.MustParseAddr("100.64.0.10")
+	peerB := netip.MustParseAddr("100.64.0.20")
+	peerC := netip.MustParseAddr("100.64.0.30")
+
+	p := newPolicy([]*mgmProto.RouteFirewallRule{
+		{
+			SourceRanges: []string{"100.64.0.10/32", "100.64.0.20/32"},
+			Destination:  "192.168.1.0/24",
+			Protocol:     mgmProto.RuleProtocol_UDP,
+			PortInfo:     &mgmProto.PortInfo{PortSelection: &mgmProto.PortInfo_Port{Port: 5353}},
+		},
+		{
+			SourceRanges: []string{"100.64.0.20/32"},
+			Destination:  "192.168.1.0/24",
+			Protocol:     mgmProto.RuleProtocol_ALL,
+		},
+		{
+			SourceRanges: []string{"100.64.0.20/32"},
+			Destination:  "192.168.1.0/24",
+			Protocol:     mgmProto.RuleProtocol_UDP,
+			PortInfo:     &mgmProto.PortInfo{PortSelection: &mgmProto.PortInfo_Range_{Range: &mgmProto.PortInfo_Range{Start: 1900, End: 1900}}},
+			Action:       mgmProto.RuleAction_DROP,
+		},
+		{
+			SourceRanges: []string{"100.64.0.30/32"},
+			Destination:  "192.168.1.0/24",
+			Protocol:     mgmProto.RuleProtocol_TCP,
+		},
+	}, false)
+
+	assert.True(t, p.allowed(peerA, lan, mdns), "accepted by the UDP port rule")
+	assert.False(t, p.allowed(peerA, lan, ssdp), "port isn't accepted")
+	assert.True(t, p.allowed(peerB, lan, mdns))
+	assert.False(t, p.allowed(peerB, lan, ssdp), "drop rule takes precedence")
+	assert.False(t, p.allowed(peerC, lan, mdns), "TCP rules don't apply")
+	assert.False(t, p.allowed(peerA, netip.MustParsePrefix("10.0.0.0/8"), mdns), "other networks aren't accepted")
+
+	assert.True(t, newPolicy(nil, true).allowed(peerC, lan, ssdp), "legacy management allows all")
+	assert.False(t, newPolicy(nil, false).allowed(peerC, lan, ssdp))
+}
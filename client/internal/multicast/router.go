@@ -0,0 +1,238 @@
+package multicast
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"slices"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/route"
+)
+
+// subscriptionTimeout expires the subscriptions of peers that stopped renewing them
+const subscriptionTimeout = 3 * refreshInterval
+
+type subscriptionKey struct {
+	peer  netip.Addr
+	group netip.AddrPort
+}
+
+// router relays the multicast groups of the routes served by this peer between the LAN and the subscribed peers.
+// Datagrams received on the LAN are sent to the subscribers the policy allows, datagrams of allowed subscribers are
+// sent to the group on the LAN.
+type router struct {
+	ctx   context.Context
+	relay *relay
+
+	mu       sync.Mutex
+	policy   *policy
+	networks map[netip.AddrPort][]netip.Prefix
+	groups   map[netip.AddrPort]*groupConn
+	// subscriptions hold the expiry of the subscriptions
+	subscriptions map[subscriptionKey]time.Time
+}
+
+func newRouter(ctx context.Context, relay *relay) *router {
+	return &router{
+		ctx:           ctx,
+		relay:         relay,
+		policy:        &policy{},
+		networks:      make(map[netip.AddrPort][]netip.Prefix),
+		groups:        make(map[netip.AddrPort]*groupConn),
+		subscriptions: make(map[subscriptionKey]time.Time),
+	}
+}
+
+// update joins the groups of the served routes on the LAN interfaces within the routed networks
+func (r *router) update(serverRoutes map[route.ID]*route.Route, p *policy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.policy = p
+	r.networks = servedGroups(serverRoutes)
+
+	for group, conn := range r.groups {
+		if _, ok := r.networks[group]; ok {
+			continue
+		}
+		log.Infof("stopped relaying multicast group %s", group)
+		conn.close()
+		delete(r.groups, group)
+	}
+	for key := range r.subscriptions {
+		if _, ok := r.networks[key.group]; !ok {
+			delete(r.subscriptions, key)
+		}
+	}
+
+	for group, networks := range r.networks {
+		ifaces, err := lanInterfaces(networks)
+		if err != nil {
+			log.Errorf("failed to find the LAN interfaces of multicast group %s: %v", group, err)
+			continue
+		}
+
+		conn, ok := r.groups[group]
+		if ok && conn.sameInterfaces(ifaces) {
+			continue
+		}
+		if ok {
+			conn.close()
+			delete(r.groups, group)
+		}
+		if len(ifaces) == 0 {
+			log.Warnf("no interface within %v to relay multicast group %s", networks, group)
+			continue
+		}
+
+		conn, err = listenGroup(r.ctx, group, ifaces, false)
+		if err != nil {
+			log.Errorf("failed to relay multicast group %s: %v", group, err)
+			continue
+		}
+		log.Infof("relaying multicast group %s on %d LAN interfaces", group, len(conn.ifaces))
+		r.groups[group] = conn
+		go r.readLoop(conn)
+	}
+}
+
+// servedGroups returns the routed networks per relayed group
+func servedGroups(serverRoutes map[route.ID]*route.Route) map[netip.AddrPort][]netip.Prefix {
+	networks := make(map[netip.AddrPort][]netip.Prefix)
+	for _, r := range serverRoutes {
+		if r.Multicast == nil || r.IsDynamic() || !r.Network.Addr().Is4() {
+			continue
+		}
+		for _, group := range r.Multicast.RelayedGroups() {
+			if !slices.Contains(networks[group], r.Network) {
+				networks[group] = append(networks[group], r.Network)
+			}
+		}
+	}
+	return networks
+}
+
+func (r *router) readLoop(conn *groupConn) {
+	buf := make([]byte, maxPayloadLen)
+	for {
+		payload, src, err := conn.read(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) && r.ctx.Err() == nil {
+				log.Errorf("failed to read from multicast group %s: %v", conn.group, err)
+			}
+			return
+		}
+		r.sendToSubscribers(conn.group, src, payload)
+	}
+}
+
+func networkOf(addr netip.Addr, networks []netip.Prefix) (netip.Prefix, bool) {
+	for _, network := range networks {
+		if network.Contains(addr) {
+			return network, true
+		}
+	}
+	return netip.Prefix{}, false
+}
+
+// sendToSubscribers relays a datagram sent from the routed network to the subscribers of the group allowed to access
+// the network
+func (r *router) sendToSubscribers(group netip.AddrPort, src netip.Addr, payload []byte) {
+	now := time.Now()
+	var peers []netip.Addr
+
+	r.mu.Lock()
+	network, ok := networkOf(src, r.networks[group])
+	if !ok {
+		r.mu.Unlock()
+		return
+	}
+	for key, expiry := range r.subscriptions {
+		if key.group == group && now.Before(expiry) && r.policy.allowed(key.peer, network, group) {
+			peers = append(peers, key.peer)
+		}
+	}
+	r.mu.Unlock()
+
+	if len(peers) == 0 {
+		return
+	}
+	msg, err := marshalData(group, payload)
+	if err != nil {
+		log.Debugf("failed to relay datagram of multicast group %s: %v", group, err)
+		return
+	}
+	for _, peer := range peers {
+		r.relay.send(peer, msg)
+	}
+}
+
+// handleSubscribe replaces the subscription of the peer, groups that aren't relayed are ignored
+func (r *router) handleSubscribe(peer netip.Addr, groups []netip.AddrPort) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.subscriptions {
+		if key.peer == peer {
+			delete(r.subscriptions, key)
+		}
+	}
+
+	expiry := time.Now().Add(subscriptionTimeout)
+	for _, group := range groups {
+		if _, ok := r.networks[group]; ok {
+			r.subscriptions[subscriptionKey{peer: peer, group: group}] = expiry
+		}
+	}
+}
+
+// handleData sends a datagram of a subscribed peer to the group on the LAN if the policy allows it to access one of the
+// networks. It reports whether the peer is subscribed to the group.
+func (r *router) handleData(peer netip.Addr, group netip.AddrPort, payload []byte) bool {
+	r.mu.Lock()
+	expiry, subscribed := r.subscriptions[subscriptionKey{peer: peer, group: group}]
+	conn := r.groups[group]
+	allowed := slices.ContainsFunc(r.networks[group], func(network netip.Prefix) bool {
+		return r.policy.allowed(peer, network, group)
+	})
+	r.mu.Unlock()
+
+	if !subscribed || time.Now().After(expiry) {
+		return false
+	}
+	if conn == nil || !allowed {
+		return true
+	}
+	conn.send(payload)
+	return true
+}
+
+// prune removes the expired subscriptions
+func (r *router) prune() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for key, expiry := range r.subscriptions {
+		if now.After(expiry) {
+			log.Debugf("multicast subscription of peer %s to group %s expired", key.peer, key.group)
+			delete(r.subscriptions, key)
+		}
+	}
+}
+
+func (r *router) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for group, conn := range r.groups {
+		conn.close()
+		delete(r.groups, group)
+	}
+	clear(r.subscriptions)
+}
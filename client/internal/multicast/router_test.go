@@ -0,0 +1,50 @@
+package multicast
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/route"
+)
+
+func TestServedGroups(t *testing.T) {
+	lan := netip.MustParsePrefix("192.168.1.0/24")
+	serverRoutes := map[route.ID]*route.Route{
+		"mdns":    {ID: "mdns", Network: lan, Multicast: &route.Multicast{MDNS: true, Groups: []netip.AddrPort{route.MDNSGroup}}},
+		"ssdp":    {ID: "ssdp", Network: netip.MustParsePrefix("10.0.0.0/24"), Multicast: &route.Multicast{SSDP: true}},
+		"plain":   {ID: "plain", Network: netip.MustParsePrefix("172.16.0.0/16")},
+		"dynamic": {ID: "dynamic", NetworkType: route.DomainNetwork, Multicast: &route.Multicast{MDNS: true}},
+	}
+
+	groups := servedGroups(serverRoutes)
+	assert.Equal(t, map[netip.AddrPort][]netip.Prefix{
+		route.MDNSGroup: {lan},
+		route.SSDPGroup: {netip.MustParsePrefix("10.0.0.0/24")},
+	}, groups)
+}
+
+func TestRouter_Subscriptions(t *testing.T) {
+	r := newRouter(context.Background(), nil)
+	r.networks = map[netip.AddrPort][]netip.Prefix{route.MDNSGroup: {netip.MustParsePrefix("192.168.1.0/24")}}
+	r.policy = &policy{allowAll: true}
+
+	peerIP := netip.MustParseAddr("100.64.0.10")
+	r.handleSubscribe(peerIP, []netip.AddrPort{route.MDNSGroup, route.SSDPGroup})
+	assert.Len(t, r.subscriptions, 1, "groups that aren't relayed are ignored")
+	assert.True(t, r.handleData(peerIP, route.MDNSGroup, []byte("query")))
+	assert.False(t, r.handleData(peerIP, route.SSDPGroup, []byte("search")))
+	assert.False(t, r.handleData(netip.MustParseAddr("100.64.0.20"), route.MDNSGroup, []byte("query")))
+
+	r.subscriptions[subscriptionKey{peer: peerIP, group: route.MDNSGroup}] = time.Now().Add(-time.Second)
+	assert.False(t, r.handleData(peerIP, route.MDNSGroup, []byte("query")), "expired subscription")
+	r.prune()
+	assert.Empty(t, r.subscriptions)
+
+	r.handleSubscribe(peerIP, []netip.AddrPort{route.MDNSGroup})
+	r.handleSubscribe(peerIP, nil)
+	assert.Empty(t, r.subscriptions, "an empty subscription unsubscribes")
+}
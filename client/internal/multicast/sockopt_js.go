@@ -0,0 +1,15 @@
+package multicast
+
+import (
+	"net/netip"
+	"syscall"
+)
+
+// reuseControl is a no-op, sockets can't be configured in the browser
+func reuseControl(_, _ string, _ syscall.RawConn) error {
+	return nil
+}
+
+func bindAddr(group netip.AddrPort) string {
+	return group.String()
+}
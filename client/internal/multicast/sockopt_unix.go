@@ -0,0 +1,29 @@
+//go:build !windows && !js
+
+package multicast
+
+import (
+	"net/netip"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// reuseControl lets the group sockets share the port with other multicast listeners of the host, e.g. an mDNS responder
+func reuseControl(_, _ string, c syscall.RawConn) error {
+	var sockErr error
+	if err := c.Control(func(fd uintptr) {
+		if sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEADDR, 1); sockErr != nil {
+			return
+		}
+		sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
+
+// bindAddr binds the group sockets to the group address, so they only receive the datagrams of the group
+func bindAddr(group netip.AddrPort) string {
+	return group.String()
+}
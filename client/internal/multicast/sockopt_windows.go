@@ -0,0 +1,25 @@
+package multicast
+
+import (
+	"net/netip"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// reuseControl lets the group sockets share the port with other multicast listeners of the host, e.g. an mDNS responder
+func reuseControl(_, _ string, c syscall.RawConn) error {
+	var sockErr error
+	if err := c.Control(func(fd uintptr) {
+		sockErr = windows.SetsockoptInt(windows.Handle(fd), windows.SOL_SOCKET, windows.SO_REUSEADDR, 1)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
+
+// bindAddr binds the group sockets to the wildcard address, Windows doesn't allow binding to multicast addresses
+func bindAddr(group netip.AddrPort) string {
+	return "0.0.0.0:" + strconv.Itoa(int(group.Port()))
+}
@@ -0,0 +1,278 @@
+package multicast
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/route"
+)
+
+// emittedWindow is how long datagrams sent to the NetBird interface are remembered, so they aren't relayed back when
+// they are looped back to the group socket
+const emittedWindow = 2 * time.Second
+
+// subscriber relays the multicast groups of the routes used by this peer between the applications of the host and the
+// routing peers. It subscribes to the groups at one routing peer per route, preferring connected routing peers, and
+// emits the relayed datagrams on the NetBird interface where the applications listening to the group receive them.
+// Datagrams the applications send to the group on the NetBird interface are relayed to the routing peers.
+type subscriber struct {
+	ctx            context.Context
+	relay          *relay
+	statusRecorder *peer.Status
+	ifaceName      string
+	localIP        netip.Addr
+
+	mu           sync.Mutex
+	clientRoutes route.HAMap
+	// routers are the routing peers chosen per group
+	routers map[netip.AddrPort][]netip.Addr
+	// subscribed are the groups subscribed per routing peer, routing peers no longer chosen are unsubscribed
+	subscribed map[netip.Addr][]netip.AddrPort
+	groups     map[netip.AddrPort]*groupConn
+	emitted    map[uint64]time.Time
+}
+
+func newSubscriber(ctx context.Context, relay *relay, statusRecorder *peer.Status, ifaceName string, localIP netip.Addr) *subscriber {
+	return &subscriber{
+		ctx:            ctx,
+		relay:          relay,
+		statusRecorder: statusRecorder,
+		ifaceName:      ifaceName,
+		localIP:        localIP,
+		routers:        make(map[netip.AddrPort][]netip.Addr),
+		subscribed:     make(map[netip.Addr][]netip.AddrPort),
+		groups:         make(map[netip.AddrPort]*groupConn),
+		emitted:        make(map[uint64]time.Time),
+	}
+}
+
+// update chooses the routing peers of the routes relaying multicast and subscribes to their groups
+func (s *subscriber) update(clientRoutes route.HAMap) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clientRoutes = clientRoutes
+	s.refreshLocked()
+}
+
+// refresh renews the subscriptions and switches to other routing peers if the chosen ones disconnected
+func (s *subscriber) refresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.refreshLocked()
+
+	now := time.Now()
+	for hash, at := range s.emitted {
+		if now.Sub(at) > emittedWindow {
+			delete(s.emitted, hash)
+		}
+	}
+}
+
+func (s *subscriber) refreshLocked() {
+	s.routers = make(map[netip.AddrPort][]netip.Addr)
+	for _, routes := range s.clientRoutes {
+		r, routerIP, ok := s.chooseRoute(routes)
+		if !ok {
+			continue
+		}
+		for _, group := range r.Multicast.RelayedGroups() {
+			if !slices.Contains(s.routers[group], routerIP) {
+				s.routers[group] = append(s.routers[group], routerIP)
+			}
+		}
+	}
+
+	s.updateGroupsLocked()
+	s.subscribeLocked()
+}
+
+// chooseRoute returns the route of the HA group with the lowest metric whose routing peer is connected, if none is
+// connected the route with the lowest metric is used which opens the connection on demand
+func (s *subscriber) chooseRoute(routes []*route.Route) (*route.Route, netip.Addr, bool) {
+	var candidates []*route.Route
+	for _, r := range routes {
+		if r.Multicast != nil && !r.IsDynamic() {
+			candidates = append(candidates, r)
+		}
+	}
+	slices.SortFunc(candidates, func(a, b *route.Route) int {
+		if a.Metric != b.Metric {
+			return a.Metric - b.Metric
+		}
+		return strings.Compare(a.Peer, b.Peer)
+	})
+
+	var fallback *route.Route
+	var fallbackIP netip.Addr
+	for _, r := range candidates {
+		state, err := s.statusRecorder.GetPeer(r.Peer)
+		if err != nil {
+			continue
+		}
+		routerIP, err := netip.ParseAddr(state.IP)
+		if err != nil {
+			continue
+		}
+		if state.ConnStatus == peer.StatusConnected {
+			return r, routerIP, true
+		}
+		if fallback == nil {
+			fallback, fallbackIP = r, routerIP
+		}
+	}
+	return fallback, fallbackIP, fallback != nil
+}
+
+// updateGroupsLocked joins the chosen groups on the NetBird interface and leaves the others
+func (s *subscriber) updateGroupsLocked() {
+	for group, conn := range s.groups {
+		if _, ok := s.routers[group]; ok {
+			continue
+		}
+		conn.close()
+		delete(s.groups, group)
+	}
+
+	var iface *net.Interface
+	for group := range s.routers {
+		if _, ok := s.groups[group]; ok {
+			continue
+		}
+		if iface == nil {
+			var err error
+			if iface, err = net.InterfaceByName(s.ifaceName); err != nil {
+				log.Errorf("failed to get interface %s to receive multicast groups: %v", s.ifaceName, err)
+				return
+			}
+		}
+
+		conn, err := listenGroup(s.ctx, group, []net.Interface{*iface}, true)
+		if err != nil {
+			log.Errorf("failed to receive multicast group %s: %v", group, err)
+			continue
+		}
+		log.Infof("receiving multicast group %s through routing peers %v", group, s.routers[group])
+		s.groups[group] = conn
+		go s.readLoop(conn)
+	}
+}
+
+// subscribeLocked sends the subscriptions to the chosen routing peers and unsubscribes from the others
+func (s *subscriber) subscribeLocked() {
+	subscribed := make(map[netip.Addr][]netip.AddrPort)
+	for group, routers := range s.routers {
+		for _, routerIP := range routers {
+			subscribed[routerIP] = append(subscribed[routerIP], group)
+		}
+	}
+
+	for routerIP := range s.subscribed {
+		if _, ok := subscribed[routerIP]; !ok {
+			s.sendSubscribe(routerIP, nil)
+		}
+	}
+	for routerIP, groups := range subscribed {
+		s.sendSubscribe(routerIP, groups)
+	}
+	s.subscribed = subscribed
+}
+
+func (s *subscriber) sendSubscribe(routerIP netip.Addr, groups []netip.AddrPort) {
+	msg, err := marshalSubscribe(groups)
+	if err != nil {
+		log.Errorf("failed to subscribe to multicast groups at %s: %v", routerIP, err)
+		return
+	}
+	s.relay.send(routerIP, msg)
+}
+
+func (s *subscriber) readLoop(conn *groupConn) {
+	buf := make([]byte, maxPayloadLen)
+	for {
+		payload, src, err := conn.read(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) && s.ctx.Err() == nil {
+				log.Errorf("failed to read from multicast group %s: %v", conn.group, err)
+			}
+			return
+		}
+		// only datagrams of the local applications are relayed
+		if src != s.localIP {
+			continue
+		}
+		s.sendToRouters(conn.group, payload)
+	}
+}
+
+func (s *subscriber) sendToRouters(group netip.AddrPort, payload []byte) {
+	s.mu.Lock()
+	if at, ok := s.emitted[datagramHash(group, payload)]; ok && time.Since(at) < emittedWindow {
+		s.mu.Unlock()
+		return
+	}
+	routers := slices.Clone(s.routers[group])
+	s.mu.Unlock()
+
+	if len(routers) == 0 {
+		return
+	}
+	msg, err := marshalData(group, payload)
+	if err != nil {
+		log.Debugf("failed to relay datagram of multicast group %s: %v", group, err)
+		return
+	}
+	for _, routerIP := range routers {
+		s.relay.send(routerIP, msg)
+	}
+}
+
+// handleData emits a datagram relayed by a chosen routing peer of the group on the NetBird interface
+func (s *subscriber) handleData(routerIP netip.Addr, group netip.AddrPort, payload []byte) {
+	s.mu.Lock()
+	conn := s.groups[group]
+	if conn == nil || !slices.Contains(s.routers[group], routerIP) {
+		s.mu.Unlock()
+		return
+	}
+	s.emitted[datagramHash(group, payload)] = time.Now()
+	s.mu.Unlock()
+
+	conn.send(payload)
+}
+
+func datagramHash(group netip.AddrPort, payload []byte) uint64 {
+	h := fnv.New64a()
+	b, _ := group.MarshalBinary()
+	_, _ = h.Write(b)
+	_, _ = h.Write(payload)
+	return h.Sum64()
+}
+
+// close unsubscribes from the routing peers and leaves the groups
+func (s *subscriber) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for routerIP := range s.subscribed {
+		s.sendSubscribe(routerIP, nil)
+	}
+	clear(s.subscribed)
+	clear(s.routers)
+
+	for group, conn := range s.groups {
+		conn.close()
+		delete(s.groups, group)
+	}
+}
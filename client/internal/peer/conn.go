@@ -21,6 +21,7 @@ import (
 	"github.com/netbirdio/netbird/client/internal/peer/guard"
 	icemaker "github.com/netbirdio/netbird/client/internal/peer/ice"
 	"github.com/netbirdio/netbird/client/internal/peer/id"
+	"github.com/netbirdio/netbird/client/internal/peer/quality"
 	"github.com/netbirdio/netbird/client/internal/peer/worker"
 	"github.com/netbirdio/netbird/client/internal/stdnet"
 	"github.com/netbirdio/netbird/route"
@@ -102,6 +103,9 @@ type Conn struct {
 
 	// used to store the remote Rosenpass key for Relayed connection in case of connection update from ice
 	rosenpassRemoteKey []byte
+	relayServerAddr    string
+	// lastICEConn is the established ICE connection, used to switch back from relay based on the path quality
+	lastICEConn *iceConnState
 
 	wgProxyICE   wgproxy.Proxy
 	wgProxyRelay wgproxy.Proxy
@@ -187,6 +191,7 @@ func (conn *Conn) Open(engineCtx context.Context) error {
 		conn.handshaker.Listen(conn.ctx)
 	}()
 	go conn.dumpState.Start(conn.ctx)
+	go conn.monitorPathQuality(conn.ctx, conn.workerICE, conn.workerRelay)
 
 	peerState := State{
 		PubKey:           conn.config.Key,
@@ -250,6 +255,7 @@ func (conn *Conn) Close(signalToRemote bool) {
 		}
 		conn.wgProxyICE = nil
 	}
+	conn.lastICEConn = nil
 
 	if err := conn.endpointUpdater.RemoveWgPeer(); err != nil {
 		conn.Log.Errorf("failed to remove wg endpoint: %v", err)
@@ -391,6 +397,11 @@ func (conn *Conn) onICEConnectionIsReady(priority conntype.ConnPriority, iceConn
 	}
 
 	conn.currentConnPriority = priority
+	conn.lastICEConn = &iceConnState{
+		info:     iceConnInfo,
+		endpoint: ep,
+		priority: priority,
+	}
 	conn.statusICE.SetConnected()
 	conn.updateIceState(iceConnInfo)
 	conn.doOnConnected(iceConnInfo.RosenpassPubKey, iceConnInfo.RosenpassAddr)
@@ -411,9 +422,13 @@ func (conn *Conn) onICEStateDisconnected() {
 			conn.Log.Warnf("failed to close deprecated wg proxy conn: %v", err)
 		}
 	}
+	conn.lastICEConn = nil
 
 	// switch back to relay connection
-	if conn.isReadyToUpgrade() {
+	if conn.currentConnPriority == conntype.Relay {
+		// the relay was already selected because of the better path quality
+		conn.Log.Infof("ICE disconnected, keep Relay as active connection")
+	} else if conn.isReadyToUpgrade() {
 		conn.Log.Infof("ICE disconnected, set Relay to active connection")
 		conn.dumpState.SwitchToRelay()
 		conn.wgProxyRelay.Work()
@@ -476,12 +491,18 @@ func (conn *Conn) onRelayConnectionIsReady(rci RelayConnInfo) {
 		conn.Log.Errorf("failed to add relayed net.Conn to local proxy: %v", err)
 		return
 	}
+	if probeConn, ok := rci.relayedConn.(*quality.Conn); ok {
+		wgProxy = newProbedProxy(wgProxy, probeConn)
+	}
 	wgProxy.SetDisconnectListener(conn.onRelayDisconnected)
 
 	conn.dumpState.NewLocalProxy()
 
 	conn.Log.Infof("created new wgProxy for relay connection: %s", wgProxy.EndpointAddr().String())
 
+	conn.rosenpassRemoteKey = rci.rosenpassPubKey
+	conn.relayServerAddr = rci.relayedConn.RemoteAddr().String()
+
 	if conn.isICEActive() {
 		conn.Log.Debugf("do not switch to relay because current priority is: %s", conn.currentConnPriority.String())
 		conn.setRelayedProxy(wgProxy)
@@ -507,7 +528,6 @@ func (conn *Conn) onRelayConnectionIsReady(rci RelayConnInfo) {
 	}()
 
 	wgConfigWorkaround()
-	conn.rosenpassRemoteKey = rci.rosenpassPubKey
 	conn.currentConnPriority = conntype.Relay
 	conn.statusRelay.SetConnected()
 	conn.setRelayedProxy(wgProxy)
@@ -526,6 +546,14 @@ func (conn *Conn) onRelayDisconnected() {
 
 	conn.Log.Debugf("relay connection is disconnected")
 
+	if conn.currentConnPriority == conntype.Relay && conn.lastICEConn != nil && conn.statusICE.Get() == worker.StatusConnected {
+		// the relay was selected because of the better path quality, the ICE connection is still available
+		conn.Log.Infof("relay disconnected, switch back to ICE connection")
+		if err := conn.switchToICE(); err != nil {
+			conn.Log.Errorf("failed to switch to ICE conn: %v", err)
+		}
+	}
+
 	if conn.currentConnPriority == conntype.Relay {
 		conn.Log.Debugf("clean up WireGuard config")
 		conn.currentConnPriority = conntype.None
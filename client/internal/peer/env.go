@@ -8,6 +8,9 @@ import (
 
 const (
 	EnvKeyNBForceRelay = "NB_FORCE_RELAY"
+	// EnvKeyNBDisablePathSelection disables the switch to the relayed path when the P2P path performs worse.
+	// The path quality is still measured and reported.
+	EnvKeyNBDisablePathSelection = "NB_DISABLE_PATH_SELECTION"
 )
 
 func isForceRelayed() bool {
@@ -16,3 +19,7 @@ func isForceRelayed() bool {
 	}
 	return strings.EqualFold(os.Getenv(EnvKeyNBForceRelay), "true")
 }
+
+func isPathSelectionDisabled() bool {
+	return strings.EqualFold(os.Getenv(EnvKeyNBDisablePathSelection), "true")
+}
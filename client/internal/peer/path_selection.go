@@ -0,0 +1,235 @@
+package peer
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/netbirdio/netbird/client/iface/wgproxy"
+	"github.com/netbirdio/netbird/client/internal/peer/conntype"
+	"github.com/netbirdio/netbird/client/internal/peer/quality"
+	"github.com/netbirdio/netbird/client/internal/peer/worker"
+)
+
+const (
+	pathProbeInterval = 2 * time.Second
+	// pathQualityWindowSize is the number of probes the path quality is calculated from. The ICE keepalive is sent
+	// less frequently than the relay probes, so the ICE window spans a longer period.
+	pathQualityWindowSize = 30
+	// pathMinSamples is the number of probes required on both paths before they are compared
+	pathMinSamples = 5
+	// pathSwitchRounds is the number of consecutive comparisons required to change the preferred path
+	pathSwitchRounds = 3
+	// pathMinScoreMargin is the minimum score difference by which the P2P path must be worse before switching to relay
+	pathMinScoreMargin = 20 * time.Millisecond
+	// pathScoreMarginDivisor sets the score margin relative to the relayed path score (25%)
+	pathScoreMarginDivisor = 4
+)
+
+// iceConnState holds the parameters of the established ICE connection to switch back to it from relay
+type iceConnState struct {
+	info     ICEConnInfo
+	endpoint *net.UDPAddr
+	priority conntype.ConnPriority
+}
+
+// probedProxy pauses the probe connection below a relayed WireGuard proxy together with the proxy. The probe
+// connection keeps reading to answer the probes of the remote peer, so it has to drop the packets the paused proxy
+// doesn't consume instead of waiting for it.
+type probedProxy struct {
+	wgproxy.Proxy
+	probeConn *quality.Conn
+}
+
+// newProbedProxy wraps a proxy that has not been started yet, the probe connection drops packets until it is
+func newProbedProxy(proxy wgproxy.Proxy, probeConn *quality.Conn) *probedProxy {
+	probeConn.Pause()
+	return &probedProxy{Proxy: proxy, probeConn: probeConn}
+}
+
+func (p *probedProxy) Work() {
+	p.probeConn.Resume()
+	p.Proxy.Work()
+}
+
+func (p *probedProxy) Pause() {
+	p.Proxy.Pause()
+	p.probeConn.Pause()
+}
+
+func (p *probedProxy) RedirectAs(endpoint *net.UDPAddr) {
+	p.probeConn.Resume()
+	p.Proxy.RedirectAs(endpoint)
+}
+
+// pathSelector decides whether the relayed path should be preferred over the established P2P path.
+//
+// The relayed path is preferred when the P2P path score is worse by a margin for pathSwitchRounds consecutive
+// rounds. The P2P path is preferred again once it is at least as good as the relayed path for the same number of
+// rounds. Both peers measure the same round trips, so they usually reach the same decision. If they briefly disagree,
+// WireGuard roaming keeps the tunnel working on the path the remote peer sends on.
+type pathSelector struct {
+	preferRelay bool
+	rounds      int
+}
+
+// update evaluates the latest path statistics and returns whether the relayed path is preferred
+func (s *pathSelector) update(iceStats, relayStats quality.Stats) bool {
+	if !iceStats.Measured(pathMinSamples) || !relayStats.Measured(pathMinSamples) {
+		// without measurements on both paths fall back to the default priorities
+		s.preferRelay = false
+		s.rounds = 0
+		return s.preferRelay
+	}
+
+	var switchCandidate bool
+	if s.preferRelay {
+		switchCandidate = iceStats.Score() <= relayStats.Score()
+	} else {
+		switchCandidate = iceStats.Score() > relayStats.Score()+scoreMargin(relayStats)
+	}
+
+	if !switchCandidate {
+		s.rounds = 0
+		return s.preferRelay
+	}
+
+	s.rounds++
+	if s.rounds >= pathSwitchRounds {
+		s.preferRelay = !s.preferRelay
+		s.rounds = 0
+	}
+	return s.preferRelay
+}
+
+func scoreMargin(stats quality.Stats) time.Duration {
+	return max(pathMinScoreMargin, stats.Score()/pathScoreMarginDivisor)
+}
+
+// monitorPathQuality periodically measures the ICE and the relayed path to the peer, reports the results to the
+// status recorder and switches between the paths based on their quality
+func (conn *Conn) monitorPathQuality(ctx context.Context, workerICE *WorkerICE, workerRelay *WorkerRelay) {
+	ticker := time.NewTicker(pathProbeInterval)
+	defer ticker.Stop()
+
+	selectionEnabled := !isPathSelectionDisabled()
+	selector := &pathSelector{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var iceStats, relayStats quality.Stats
+		if conn.statusICE.Get() == worker.StatusConnected {
+			iceStats = workerICE.PathQuality()
+		}
+		if conn.statusRelay.Get() == worker.StatusConnected {
+			relayStats = workerRelay.ProbePath(ctx)
+		}
+
+		if err := conn.statusRecorder.UpdatePeerPathQuality(conn.config.Key, iceStats, relayStats); err != nil {
+			conn.Log.Debugf("failed to update path quality: %v", err)
+		}
+
+		preferRelay := selector.update(iceStats, relayStats)
+		conn.onPathQuality(ctx, iceStats, relayStats, selectionEnabled && preferRelay)
+	}
+}
+
+func (conn *Conn) onPathQuality(ctx context.Context, iceStats, relayStats quality.Stats, preferRelay bool) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	// the connection may have been closed and reopened with a new monitor in the meantime
+	if ctx.Err() != nil {
+		return
+	}
+
+	active := iceStats
+	if conn.currentConnPriority == conntype.Relay {
+		active = relayStats
+	}
+	if active.Received > 0 {
+		if err := conn.statusRecorder.UpdateLatency(conn.config.Key, active.Latency); err != nil {
+			conn.Log.Debugf("failed to update latency: %v", err)
+		}
+	}
+
+	switch {
+	case preferRelay && conn.isICEActive() && conn.wgProxyRelay != nil:
+		conn.Log.Infof("relayed path performs better than the P2P path (P2P: %s, relay: %s), switch to relay", iceStats, relayStats)
+		conn.switchToRelay()
+	case !preferRelay && conn.currentConnPriority == conntype.Relay && conn.lastICEConn != nil && conn.statusICE.Get() == worker.StatusConnected:
+		conn.Log.Infof("P2P path performs well again (P2P: %s, relay: %s), switch to P2P", iceStats, relayStats)
+		if err := conn.switchToICE(); err != nil {
+			conn.Log.Errorf("failed to switch to ICE conn: %v", err)
+			conn.switchToRelay()
+		}
+	}
+}
+
+// switchToRelay configures WireGuard to use the relayed connection while the ICE connection stays open
+func (conn *Conn) switchToRelay() {
+	relayEp := conn.wgProxyRelay.EndpointAddr()
+
+	// forward the packets the remote peer still sends via ICE as if they arrived on the relayed connection
+	if conn.wgProxyICE != nil {
+		conn.wgProxyICE.RedirectAs(relayEp)
+	}
+	conn.wgProxyRelay.Work()
+
+	presharedKey := conn.presharedKey(conn.rosenpassRemoteKey)
+	if err := conn.endpointUpdater.ConfigureWGEndpoint(relayEp, presharedKey); err != nil {
+		conn.Log.Errorf("failed to switch to relay conn: %v", err)
+		if conn.wgProxyICE != nil {
+			conn.wgProxyICE.Work()
+		}
+		if conn.lastICEConn != nil {
+			conn.wgProxyRelay.RedirectAs(conn.lastICEConn.endpoint)
+		}
+		return
+	}
+	wgConfigWorkaround()
+
+	conn.wgWatcherWg.Add(1)
+	go func() {
+		defer conn.wgWatcherWg.Done()
+		conn.workerRelay.EnableWgWatcher(conn.ctx)
+	}()
+
+	conn.dumpState.SwitchToRelay()
+	conn.currentConnPriority = conntype.Relay
+	conn.updateRelayStatus(conn.relayServerAddr, conn.rosenpassRemoteKey)
+}
+
+// switchToICE configures WireGuard to use the still open ICE connection again
+func (conn *Conn) switchToICE() error {
+	iceConn := conn.lastICEConn
+
+	conn.workerRelay.DisableWgWatcher()
+
+	if conn.wgProxyRelay != nil {
+		conn.wgProxyRelay.Pause()
+	}
+	if conn.wgProxyICE != nil {
+		conn.wgProxyICE.Work()
+	}
+
+	presharedKey := conn.presharedKey(iceConn.info.RosenpassPubKey)
+	if err := conn.endpointUpdater.ConfigureWGEndpoint(iceConn.endpoint, presharedKey); err != nil {
+		return err
+	}
+	wgConfigWorkaround()
+
+	if conn.wgProxyRelay != nil {
+		conn.wgProxyRelay.RedirectAs(iceConn.endpoint)
+	}
+
+	conn.dumpState.P2PConnected()
+	conn.currentConnPriority = iceConn.priority
+	conn.updateIceState(iceConn.info)
+	return nil
+}
@@ -0,0 +1,89 @@
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/client/internal/peer/quality"
+)
+
+func pathStats(latency time.Duration, loss float64) quality.Stats {
+	return quality.Stats{
+		Latency:  latency,
+		Loss:     loss,
+		Samples:  10,
+		Received: 10 - int(loss*10),
+	}
+}
+
+func TestPathSelector_SwitchToRelay(t *testing.T) {
+	s := &pathSelector{}
+	congested := pathStats(150*time.Millisecond, 0)
+	relay := pathStats(60*time.Millisecond, 0)
+
+	for i := 1; i < pathSwitchRounds; i++ {
+		assert.False(t, s.update(congested, relay), "round %d must not switch yet", i)
+	}
+	assert.True(t, s.update(congested, relay), "relay must be preferred after %d rounds", pathSwitchRounds)
+}
+
+func TestPathSelector_Margin(t *testing.T) {
+	s := &pathSelector{}
+	// worse, but within the 25% margin of the relay score
+	ice := pathStats(110*time.Millisecond, 0)
+	relay := pathStats(100*time.Millisecond, 0)
+
+	for i := 0; i < pathSwitchRounds*2; i++ {
+		assert.False(t, s.update(ice, relay))
+	}
+}
+
+func TestPathSelector_InterruptedRounds(t *testing.T) {
+	s := &pathSelector{}
+	lossy := pathStats(20*time.Millisecond, 0.2)
+	good := pathStats(20*time.Millisecond, 0)
+	relay := pathStats(50*time.Millisecond, 0)
+
+	for i := 0; i < 3; i++ {
+		assert.False(t, s.update(lossy, relay))
+		assert.False(t, s.update(lossy, relay))
+		assert.False(t, s.update(good, relay), "a good round resets the counter")
+	}
+}
+
+func TestPathSelector_SwitchBack(t *testing.T) {
+	s := &pathSelector{}
+	relay := pathStats(50*time.Millisecond, 0)
+	for i := 0; i < pathSwitchRounds; i++ {
+		s.update(pathStats(200*time.Millisecond, 0), relay)
+	}
+	assert.True(t, s.preferRelay)
+
+	// better than before, but still worse than relay: hysteresis keeps relay
+	for i := 0; i < pathSwitchRounds*2; i++ {
+		assert.True(t, s.update(pathStats(55*time.Millisecond, 0), relay))
+	}
+
+	for i := 1; i < pathSwitchRounds; i++ {
+		assert.True(t, s.update(pathStats(30*time.Millisecond, 0), relay))
+	}
+	assert.False(t, s.update(pathStats(30*time.Millisecond, 0), relay), "P2P must be preferred again")
+}
+
+func TestPathSelector_Unmeasured(t *testing.T) {
+	s := &pathSelector{}
+	relay := pathStats(50*time.Millisecond, 0)
+	for i := 0; i < pathSwitchRounds; i++ {
+		s.update(pathStats(200*time.Millisecond, 0), relay)
+	}
+	assert.True(t, s.preferRelay)
+
+	// the remote peer does not answer the relay probes, e.g. an older client
+	unanswered := quality.Stats{Samples: 10, Loss: 1}
+	assert.False(t, s.update(pathStats(200*time.Millisecond, 0), unanswered), "fall back to the default priority")
+
+	few := quality.Stats{Latency: time.Millisecond, Samples: pathMinSamples - 1, Received: pathMinSamples - 1}
+	assert.False(t, s.update(pathStats(200*time.Millisecond, 0), few))
+}
@@ -0,0 +1,234 @@
+package quality
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	msgTypeRequest byte = 1
+	msgTypeReply   byte = 2
+
+	// probe packet layout: magic(4) | type(1) | sequence(4) | send timestamp(8)
+	probeSize = 4 + 1 + 4 + 8
+
+	readBufferSize = 65535
+	// packetQueueSize is the number of transport packets buffered for the reader (the WireGuard proxy)
+	packetQueueSize = 64
+)
+
+// probeMagic starts every probe packet. WireGuard messages start with a little endian message type in the range
+// of 1-4 followed by three zero bytes, so probes can never be mistaken for WireGuard traffic and vice versa.
+var probeMagic = [4]byte{0xfe, 'n', 'b', 'p'}
+
+var ErrConnClosed = errors.New("probe connection closed")
+
+// Conn wraps a transport connection to a remote peer (e.g. a relayed connection) and handles path probes on it.
+//
+// The connection continuously reads from the underlying transport, answers the probe requests of the remote peer
+// and consumes the replies of its own probes. Every other packet is passed to Read, a slow reader applies
+// backpressure to the transport. While the connection is paused the packets are dropped instead, so probes are
+// answered even while the WireGuard proxy on top of the connection doesn't read.
+type Conn struct {
+	net.Conn
+
+	packets   chan []byte
+	done      chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+	readErr   error
+
+	pauseMu sync.Mutex
+	// paused is closed while the connection is paused
+	paused chan struct{}
+
+	mu      sync.Mutex
+	seq     uint32
+	pending map[uint32]*pendingProbe
+}
+
+type pendingProbe struct {
+	sentAt time.Time
+	reply  chan time.Duration
+}
+
+// NewConn wraps the given connection and starts reading from it
+func NewConn(conn net.Conn) *Conn {
+	c := &Conn{
+		Conn:    conn,
+		packets: make(chan []byte, packetQueueSize),
+		done:    make(chan struct{}),
+		closed:  make(chan struct{}),
+		paused:  make(chan struct{}),
+		pending: make(map[uint32]*pendingProbe),
+	}
+	go c.readLoop()
+	return c
+}
+
+// Read returns the next non-probe packet received from the remote peer
+func (c *Conn) Read(b []byte) (int, error) {
+	select {
+	case pkt := <-c.packets:
+		return copy(b, pkt), nil
+	case <-c.done:
+		// drain the already received packets first
+		select {
+		case pkt := <-c.packets:
+			return copy(b, pkt), nil
+		default:
+		}
+		return 0, c.readErr
+	}
+}
+
+// Close closes the underlying connection and stops reading from it
+func (c *Conn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+	return c.Conn.Close()
+}
+
+// Pause drops the received packets until Resume is called instead of waiting for the reader
+func (c *Conn) Pause() {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+
+	select {
+	case <-c.paused:
+	default:
+		close(c.paused)
+	}
+}
+
+// Resume passes the received packets to the reader again
+func (c *Conn) Resume() {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+
+	select {
+	case <-c.paused:
+		c.paused = make(chan struct{})
+	default:
+	}
+}
+
+func (c *Conn) pausedChan() chan struct{} {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+	return c.paused
+}
+
+// Probe sends a probe request over the connection and waits for the reply of the remote peer.
+// It returns the measured round trip time.
+func (c *Conn) Probe(ctx context.Context) (time.Duration, error) {
+	probe := &pendingProbe{
+		sentAt: time.Now(),
+		reply:  make(chan time.Duration, 1),
+	}
+
+	c.mu.Lock()
+	c.seq++
+	seq := c.seq
+	c.pending[seq] = probe
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, seq)
+		c.mu.Unlock()
+	}()
+
+	if _, err := c.Conn.Write(marshalProbe(msgTypeRequest, seq, probe.sentAt)); err != nil {
+		return 0, err
+	}
+
+	select {
+	case rtt := <-probe.reply:
+		return rtt, nil
+	case <-c.done:
+		return 0, ErrConnClosed
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func (c *Conn) readLoop() {
+	defer close(c.done)
+
+	buf := make([]byte, readBufferSize)
+	for {
+		n, err := c.Conn.Read(buf)
+		if err != nil {
+			c.readErr = err
+			return
+		}
+
+		if isProbe(buf[:n]) {
+			c.handleProbe(buf[:n])
+			continue
+		}
+
+		pkt := make([]byte, n)
+		copy(pkt, buf[:n])
+		select {
+		case c.packets <- pkt:
+		case <-c.pausedChan():
+			log.Tracef("drop packet from %s, reader is paused", c.Conn.RemoteAddr())
+		case <-c.closed:
+			c.readErr = ErrConnClosed
+			return
+		}
+	}
+}
+
+func (c *Conn) handleProbe(pkt []byte) {
+	msgType, seq, sentAt := unmarshalProbe(pkt)
+	switch msgType {
+	case msgTypeRequest:
+		reply := marshalProbe(msgTypeReply, seq, sentAt)
+		if _, err := c.Conn.Write(reply); err != nil {
+			log.Debugf("failed to answer path probe: %v", err)
+		}
+	case msgTypeReply:
+		c.mu.Lock()
+		probe, ok := c.pending[seq]
+		c.mu.Unlock()
+
+		if !ok {
+			// the probe has already timed out
+			return
+		}
+		// measure with the local monotonic clock, the echoed timestamp only identifies the probe for the remote
+		select {
+		case probe.reply <- time.Since(probe.sentAt):
+		default:
+		}
+	}
+}
+
+func isProbe(pkt []byte) bool {
+	return len(pkt) == probeSize && [4]byte(pkt[:4]) == probeMagic
+}
+
+func marshalProbe(msgType byte, seq uint32, sentAt time.Time) []byte {
+	pkt := make([]byte, probeSize)
+	copy(pkt, probeMagic[:])
+	pkt[4] = msgType
+	binary.BigEndian.PutUint32(pkt[5:9], seq)
+	binary.BigEndian.PutUint64(pkt[9:17], uint64(sentAt.UnixNano()))
+	return pkt
+}
+
+func unmarshalProbe(pkt []byte) (byte, uint32, time.Time) {
+	seq := binary.BigEndian.Uint32(pkt[5:9])
+	sentAt := time.Unix(0, int64(binary.BigEndian.Uint64(pkt[9:17])))
+	return pkt[4], seq, sentAt
+}
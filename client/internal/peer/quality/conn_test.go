@@ -0,0 +1,156 @@
+package quality
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConn_Probe(t *testing.T) {
+	c1, c2 := net.Pipe()
+	local := NewConn(c1)
+	remote := NewConn(c2)
+	defer func() {
+		_ = local.Close()
+		_ = remote.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rtt, err := local.Probe(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, rtt, time.Duration(0))
+
+	rtt, err = remote.Probe(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, rtt, time.Duration(0))
+}
+
+func TestConn_PassThrough(t *testing.T) {
+	c1, c2 := net.Pipe()
+	local := NewConn(c1)
+	remote := NewConn(c2)
+	defer func() {
+		_ = local.Close()
+		_ = remote.Close()
+	}()
+
+	payload := []byte{4, 0, 0, 0, 1, 2, 3}
+	go func() {
+		_, _ = remote.Write(payload)
+	}()
+
+	buf := make([]byte, 64)
+	n, err := local.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, payload, buf[:n])
+}
+
+func TestConn_ProbeWithoutRemoteSupport(t *testing.T) {
+	c1, c2 := net.Pipe()
+	local := NewConn(c1)
+	defer func() {
+		_ = local.Close()
+		_ = c2.Close()
+	}()
+
+	// the remote reads the probe like any other packet and never answers
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			if _, err := c2.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := local.Probe(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestConn_Closed(t *testing.T) {
+	c1, c2 := net.Pipe()
+	local := NewConn(c1)
+	_ = c2.Close()
+
+	_, err := local.Read(make([]byte, 64))
+	assert.Error(t, err)
+
+	_, err = local.Probe(context.Background())
+	assert.Error(t, err)
+}
+
+func TestConn_Backpressure(t *testing.T) {
+	c1, c2 := net.Pipe()
+	local := NewConn(c1)
+	defer func() {
+		_ = local.Close()
+		_ = c2.Close()
+	}()
+
+	payload := []byte{4, 0, 0, 0, 1, 2, 3}
+	sent := make(chan struct{})
+	go func() {
+		defer close(sent)
+		for i := 0; i < packetQueueSize+10; i++ {
+			if _, err := c2.Write(payload); err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-sent:
+		t.Fatal("the remote must be blocked while the reader doesn't consume")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	buf := make([]byte, 64)
+	for i := 0; i < packetQueueSize+10; i++ {
+		_, err := local.Read(buf)
+		require.NoError(t, err, "no packet may be dropped")
+	}
+	<-sent
+}
+
+func TestConn_PauseDropsPackets(t *testing.T) {
+	c1, c2 := net.Pipe()
+	local := NewConn(c1)
+	remote := NewConn(c2)
+	defer func() {
+		_ = local.Close()
+		_ = remote.Close()
+	}()
+
+	local.Pause()
+	for i := 0; i < packetQueueSize+10; i++ {
+		_, err := remote.Write([]byte{4, 0, 0, 0, byte(i)})
+		require.NoError(t, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := remote.Probe(ctx)
+	require.NoError(t, err, "probes are answered while paused")
+
+	local.Resume()
+	go func() {
+		_, _ = remote.Write([]byte{4, 0, 0, 0, 0xff})
+	}()
+
+	buf := make([]byte, 64)
+	var last []byte
+	for len(last) == 0 || last[4] != 0xff {
+		n, err := local.Read(buf)
+		require.NoError(t, err)
+		last = buf[:n]
+	}
+}
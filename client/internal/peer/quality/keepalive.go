@@ -0,0 +1,71 @@
+package quality
+
+import (
+	"time"
+)
+
+// KeepaliveSampler converts the cumulative counters of periodic keepalive requests, e.g. the STUN binding requests
+// the ICE agent sends on the selected candidate pair, into window samples.
+// It is not safe for concurrent use.
+type KeepaliveSampler struct {
+	window *Window
+
+	initialized bool
+	requests    uint64
+	responses   uint64
+	totalRTT    time.Duration
+	// baseOutstanding is the number of unanswered requests at the first update, e.g. failed connectivity checks
+	baseOutstanding int64
+	lost            int64
+}
+
+// NewKeepaliveSampler creates a sampler recording into a window of the given size
+func NewKeepaliveSampler(size int) *KeepaliveSampler {
+	return &KeepaliveSampler{
+		window: NewWindow(size),
+	}
+}
+
+// Update records the requests answered and lost since the previous update.
+// The first update, or an update with decreasing counters, only sets the baseline.
+func (s *KeepaliveSampler) Update(requests, responses uint64, totalRTT time.Duration) {
+	if !s.initialized || requests < s.requests || responses < s.responses {
+		s.initialized = true
+		s.requests = requests
+		s.responses = responses
+		s.totalRTT = totalRTT
+		s.baseOutstanding = int64(requests) - int64(responses)
+		s.lost = 0
+		return
+	}
+
+	if answered := responses - s.responses; answered > 0 {
+		// the agent only exposes the sum of the round trip times, use the mean of the new responses
+		rtt := (totalRTT - s.totalRTT) / time.Duration(answered)
+		for i := uint64(0); i < answered; i++ {
+			s.window.Add(rtt)
+		}
+	}
+
+	// one request may still be in flight, everything beyond it is considered lost. A late response for a request
+	// already counted as lost is recorded as well, but it does not undo the loss.
+	outstanding := int64(requests) - int64(responses) - s.baseOutstanding - 1
+	for ; s.lost < outstanding; s.lost++ {
+		s.window.AddLost()
+	}
+
+	s.requests = requests
+	s.responses = responses
+	s.totalRTT = totalRTT
+}
+
+// Reset drops the baseline and the recorded results, e.g. when a new candidate pair has been selected
+func (s *KeepaliveSampler) Reset() {
+	s.initialized = false
+	s.window.Reset()
+}
+
+// Stats returns the statistics of the recorded results
+func (s *KeepaliveSampler) Stats() Stats {
+	return s.window.Stats()
+}
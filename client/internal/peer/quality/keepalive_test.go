@@ -0,0 +1,56 @@
+package quality
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeepaliveSampler(t *testing.T) {
+	s := NewKeepaliveSampler(10)
+
+	// the failed connectivity checks before the selection must not count as loss
+	s.Update(7, 2, 20*time.Millisecond)
+	assert.Equal(t, Stats{}, s.Stats(), "first update only sets the baseline")
+
+	s.Update(9, 4, 60*time.Millisecond)
+	stats := s.Stats()
+	assert.Equal(t, 2, stats.Received)
+	assert.Equal(t, 20*time.Millisecond, stats.Latency)
+	assert.Zero(t, stats.Loss)
+
+	// one request in flight is not lost yet
+	s.Update(10, 4, 60*time.Millisecond)
+	assert.Equal(t, 2, s.Stats().Samples)
+
+	s.Update(12, 4, 60*time.Millisecond)
+	stats = s.Stats()
+	assert.Equal(t, 4, stats.Samples)
+	assert.Equal(t, 2, stats.Received)
+	assert.InDelta(t, 0.5, stats.Loss, 0.0001)
+
+	// a late answer is recorded, but the loss of the next request is not counted twice
+	s.Update(12, 5, 90*time.Millisecond)
+	s.Update(14, 5, 90*time.Millisecond)
+	stats = s.Stats()
+	assert.Equal(t, 3, stats.Received)
+	assert.Equal(t, 6, stats.Samples)
+}
+
+func TestKeepaliveSampler_CounterReset(t *testing.T) {
+	s := NewKeepaliveSampler(10)
+	s.Update(10, 10, 100*time.Millisecond)
+	s.Update(12, 12, 120*time.Millisecond)
+	assert.Equal(t, 2, s.Stats().Samples)
+
+	// a new agent starts from zero, the counters are taken as new baseline
+	s.Update(1, 1, 5*time.Millisecond)
+	assert.Equal(t, 2, s.Stats().Samples)
+
+	s.Update(2, 2, 10*time.Millisecond)
+	assert.Equal(t, 3, s.Stats().Samples)
+
+	s.Reset()
+	assert.Equal(t, Stats{}, s.Stats())
+}
@@ -0,0 +1,152 @@
+package quality
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// lossPenalty is the latency added to the path score for 100% packet loss. Loss costs far more than a few
+// milliseconds of delay for TCP flows, so a few percent of loss outweighs a moderately longer path.
+const lossPenalty = time.Second
+
+// Stats summarizes the recent probe results of a single transport path
+type Stats struct {
+	// Latency is the mean round trip time of the answered probes
+	Latency time.Duration
+	// Jitter is the mean difference between the round trip times of consecutive answered probes
+	Jitter time.Duration
+	// Loss is the ratio of unanswered probes, between 0 and 1
+	Loss float64
+	// Samples is the number of probes in the window, answered or not
+	Samples int
+	// Received is the number of answered probes in the window
+	Received int
+}
+
+// Measured reports whether the window holds at least minSamples probes and the remote answered at least once.
+// A path without any answer is considered unmeasured rather than lossy, since the remote may not support probing.
+func (s Stats) Measured(minSamples int) bool {
+	return s.Samples >= minSamples && s.Received > 0
+}
+
+// Score returns a comparable cost of the path, lower is better
+func (s Stats) Score() time.Duration {
+	return s.Latency + 2*s.Jitter + time.Duration(s.Loss*float64(lossPenalty))
+}
+
+func (s Stats) String() string {
+	if s.Received == 0 {
+		return fmt.Sprintf("no replies, %d probes", s.Samples)
+	}
+	return fmt.Sprintf("latency %s, jitter %s, loss %.1f%%", s.Latency, s.Jitter, s.Loss*100)
+}
+
+type sample struct {
+	rtt  time.Duration
+	lost bool
+}
+
+// Window keeps the last probe results of a path and derives latency, jitter and loss from them
+type Window struct {
+	mu      sync.Mutex
+	samples []sample
+	next    int
+	full    bool
+}
+
+// NewWindow creates a window holding the results of the last size probes
+func NewWindow(size int) *Window {
+	return &Window{
+		samples: make([]sample, size),
+	}
+}
+
+// Add records an answered probe
+func (w *Window) Add(rtt time.Duration) {
+	w.add(sample{rtt: rtt})
+}
+
+// AddLost records an unanswered probe
+func (w *Window) AddLost() {
+	w.add(sample{lost: true})
+}
+
+// Reset drops all recorded results, e.g. when the path has been re-established
+func (w *Window) Reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.next = 0
+	w.full = false
+}
+
+// Stats calculates the statistics of the recorded results
+func (w *Window) Stats() Stats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	count := w.next
+	start := 0
+	if w.full {
+		count = len(w.samples)
+		start = w.next
+	}
+
+	var (
+		stats    Stats
+		total    time.Duration
+		jitter   time.Duration
+		prev     time.Duration
+		havePrev bool
+		lost     int
+	)
+
+	for i := 0; i < count; i++ {
+		s := w.samples[(start+i)%len(w.samples)]
+		if s.lost {
+			lost++
+			continue
+		}
+
+		stats.Received++
+		total += s.rtt
+		if havePrev {
+			jitter += absDuration(s.rtt - prev)
+		}
+		prev = s.rtt
+		havePrev = true
+	}
+
+	stats.Samples = count
+	if count > 0 {
+		stats.Loss = float64(lost) / float64(count)
+	}
+	if stats.Received > 0 {
+		stats.Latency = total / time.Duration(stats.Received)
+	}
+	if stats.Received > 1 {
+		stats.Jitter = jitter / time.Duration(stats.Received-1)
+	}
+
+	return stats
+}
+
+func (w *Window) add(s sample) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.samples[w.next] = s
+	w.next++
+	if w.next == len(w.samples) {
+		w.next = 0
+		w.full = true
+	}
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,59 @@
+package quality
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindow_Stats(t *testing.T) {
+	w := NewWindow(4)
+	assert.Equal(t, Stats{}, w.Stats(), "empty window")
+
+	w.Add(10 * time.Millisecond)
+	w.Add(20 * time.Millisecond)
+	w.AddLost()
+	w.Add(30 * time.Millisecond)
+
+	stats := w.Stats()
+	assert.Equal(t, 4, stats.Samples)
+	assert.Equal(t, 3, stats.Received)
+	assert.Equal(t, 20*time.Millisecond, stats.Latency)
+	assert.Equal(t, 10*time.Millisecond, stats.Jitter)
+	assert.InDelta(t, 0.25, stats.Loss, 0.0001)
+}
+
+func TestWindow_Overwrite(t *testing.T) {
+	w := NewWindow(3)
+	w.AddLost()
+	w.AddLost()
+	for i := 0; i < 3; i++ {
+		w.Add(5 * time.Millisecond)
+	}
+
+	stats := w.Stats()
+	assert.Equal(t, 3, stats.Samples)
+	assert.Equal(t, 3, stats.Received)
+	assert.Equal(t, 5*time.Millisecond, stats.Latency)
+	assert.Zero(t, stats.Jitter)
+	assert.Zero(t, stats.Loss, "lost probes must be dropped from the window")
+
+	w.Reset()
+	assert.Equal(t, Stats{}, w.Stats())
+}
+
+func TestStats_Measured(t *testing.T) {
+	assert.False(t, Stats{Samples: 2, Received: 2}.Measured(3), "not enough samples")
+	assert.False(t, Stats{Samples: 5, Received: 0, Loss: 1}.Measured(3), "no answer from remote")
+	assert.True(t, Stats{Samples: 5, Received: 1, Loss: 0.8}.Measured(3))
+}
+
+func TestStats_Score(t *testing.T) {
+	clean := Stats{Latency: 40 * time.Millisecond, Jitter: 5 * time.Millisecond}
+	assert.Equal(t, 50*time.Millisecond, clean.Score())
+
+	lossy := Stats{Latency: 20 * time.Millisecond, Loss: 0.05}
+	assert.Equal(t, 70*time.Millisecond, lossy.Score())
+	assert.Greater(t, lossy.Score(), clean.Score(), "a few percent of loss outweighs a longer path")
+}
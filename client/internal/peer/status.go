@@ -20,6 +20,7 @@ import (
 	firewall "github.com/netbirdio/netbird/client/firewall/manager"
 	"github.com/netbirdio/netbird/client/iface/configurer"
 	"github.com/netbirdio/netbird/client/internal/ingressgw"
+	"github.com/netbirdio/netbird/client/internal/peer/quality"
 	"github.com/netbirdio/netbird/client/internal/relay"
 	"github.com/netbirdio/netbird/client/proto"
 	"github.com/netbirdio/netbird/route"
@@ -67,6 +68,8 @@ type State struct {
 	BytesTx                    int64
 	BytesRx                    int64
 	Latency                    time.Duration
+	ICEPathQuality             quality.Stats
+	RelayPathQuality           quality.Stats
 	RosenpassEnabled           bool
 	SSHHostKey                 []byte
 	routes                     map[string]struct{}
@@ -339,6 +342,8 @@ func (d *Status) UpdatePeerState(receivedState State) error {
 		peerState.RemoteIceCandidateEndpoint = receivedState.RemoteIceCandidateEndpoint
 		peerState.RelayServerAddress = receivedState.RelayServerAddress
 		peerState.RosenpassEnabled = receivedState.RosenpassEnabled
+		peerState.ICEPathQuality = receivedState.ICEPathQuality
+		peerState.RelayPathQuality = receivedState.RelayPathQuality
 	}
 
 	d.peers[receivedState.PubKey] = peerState
@@ -879,6 +884,20 @@ func (d *Status) UpdateLatency(pubKey string, latency time.Duration) error {
 	return nil
 }
 
+// UpdatePeerPathQuality updates the probe results of the ICE and the relayed path to the peer
+func (d *Status) UpdatePeerPathQuality(pubKey string, iceStats, relayStats quality.Stats) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	peerState, ok := d.peers[pubKey]
+	if !ok {
+		return errors.New("peer doesn't exist")
+	}
+	peerState.ICEPathQuality = iceStats
+	peerState.RelayPathQuality = relayStats
+	d.peers[pubKey] = peerState
+	return nil
+}
+
 // IsLoginRequired determines if a peer's login has expired.
 func (d *Status) IsLoginRequired() bool {
 	d.mux.Lock()
@@ -1239,6 +1258,8 @@ func (fs FullStatus) ToProto() *proto.FullStatus {
 			Networks:                   networks,
 			Latency:                    durationpb.New(peerState.Latency),
 			SshHostKey:                 peerState.SSHHostKey,
+			IcePathQuality:             PathQualityToProto(peerState.ICEPathQuality),
+			RelayPathQuality:           PathQualityToProto(peerState.RelayPathQuality),
 		}
 		pbFullStatus.Peers = append(pbFullStatus.Peers, pbPeerState)
 	}
@@ -1278,3 +1299,16 @@ func (fs FullStatus) ToProto() *proto.FullStatus {
 
 	return &pbFullStatus
 }
+
+// PathQualityToProto converts the path statistics, it returns nil if nothing has been measured
+func PathQualityToProto(stats quality.Stats) *proto.PathQuality {
+	if stats.Samples == 0 {
+		return nil
+	}
+	return &proto.PathQuality{
+		Latency: durationpb.New(stats.Latency),
+		Jitter:  durationpb.New(stats.Jitter),
+		Loss:    stats.Loss,
+		Samples: int32(stats.Samples),
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/client/internal/peer/quality"
 )
 
 func TestAddPeer(t *testing.T) {
@@ -81,6 +83,36 @@ func TestStatus_UpdatePeerFQDN(t *testing.T) {
 	assert.Equal(t, fqdn, state.FQDN, "fqdn should be equal")
 }
 
+func TestStatus_UpdatePeerPathQuality(t *testing.T) {
+	key := "abc"
+	status := NewRecorder("https://mgm")
+	_ = status.AddPeer(key, "peer-a.netbird.local", "10.10.10.10")
+	_ = status.UpdatePeerState(State{PubKey: key, ConnStatus: StatusConnected})
+
+	iceStats := quality.Stats{Latency: 30 * time.Millisecond, Jitter: time.Millisecond, Samples: 10, Received: 10}
+	relayStats := quality.Stats{Samples: 10, Loss: 1}
+	err := status.UpdatePeerPathQuality(key, iceStats, relayStats)
+	assert.NoError(t, err, "shouldn't return error")
+
+	state, err := status.GetPeer(key)
+	assert.NoError(t, err)
+	assert.Equal(t, iceStats, state.ICEPathQuality)
+	assert.Equal(t, relayStats, state.RelayPathQuality)
+
+	pbStatus := status.GetFullStatus().ToProto()
+	assert.Equal(t, int32(10), pbStatus.Peers[0].GetIcePathQuality().GetSamples())
+	assert.Equal(t, float64(1), pbStatus.Peers[0].GetRelayPathQuality().GetLoss())
+
+	// closing the connection resets the path quality
+	err = status.UpdatePeerState(State{PubKey: key, ConnStatus: StatusIdle})
+	assert.NoError(t, err)
+	state, _ = status.GetPeer(key)
+	assert.Equal(t, quality.Stats{}, state.ICEPathQuality)
+
+	err = status.UpdatePeerPathQuality("unknown", iceStats, relayStats)
+	assert.Error(t, err, "unknown peer should return error")
+}
+
 func TestGetPeerStateChangeNotifierLogic(t *testing.T) {
 	key := "abc"
 	ip := "10.10.10.10"
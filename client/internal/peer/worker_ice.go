@@ -15,6 +15,7 @@ import (
 	"github.com/netbirdio/netbird/client/iface/udpmux"
 	"github.com/netbirdio/netbird/client/internal/peer/conntype"
 	icemaker "github.com/netbirdio/netbird/client/internal/peer/ice"
+	"github.com/netbirdio/netbird/client/internal/peer/quality"
 	"github.com/netbirdio/netbird/client/internal/stdnet"
 	"github.com/netbirdio/netbird/route"
 )
@@ -59,6 +60,10 @@ type WorkerICE struct {
 
 	// we record the last known state of the ICE agent to avoid duplicate on disconnected events
 	lastKnownState ice.ConnectionState
+
+	// pathQuality samples the keepalives of the selected candidate pair, accessed only by the path monitor
+	pathQuality       *quality.KeepaliveSampler
+	pathQualityPairID string
 }
 
 func NewWorkerICE(ctx context.Context, log *log.Entry, config ConnConfig, conn *Conn, signaler *Signaler, ifaceDiscover stdnet.ExternalIFaceDiscover, statusRecorder *Status, hasRelayOnLocally bool) (*WorkerICE, error) {
@@ -78,6 +83,7 @@ func NewWorkerICE(ctx context.Context, log *log.Entry, config ConnConfig, conn *
 		hasRelayOnLocally: hasRelayOnLocally,
 		lastKnownState:    ice.ConnectionStateDisconnected,
 		sessionID:         sessionID,
+		pathQuality:       quality.NewKeepaliveSampler(pathQualityWindowSize),
 	}
 
 	localUfrag, localPwd, err := icemaker.GenerateICECredentials()
@@ -118,8 +124,10 @@ func (w *WorkerICE) OnNewOffer(remoteOfferAnswer *OfferAnswer) {
 		w.agent = nil
 	}
 
+	// without a connection to the home relay server, e.g. when its ports are blocked, TURN candidates are gathered
+	// to keep a relayed path available
 	var preferredCandidateTypes []ice.CandidateType
-	if w.hasRelayOnLocally && remoteOfferAnswer.RelaySrvAddress != "" {
+	if w.hasRelayOnLocally && remoteOfferAnswer.RelaySrvAddress != "" && w.conn.workerRelay.RelayIsConnectedLocally() {
 		preferredCandidateTypes = icemaker.CandidateTypesP2P()
 	} else {
 		preferredCandidateTypes = icemaker.CandidateTypes()
@@ -208,6 +216,52 @@ func (w *WorkerICE) Close() {
 	w.agent = nil
 }
 
+// PathQuality samples the keepalive statistics of the selected candidate pair and returns the quality of the ICE path.
+// The agent sends a binding request on the selected pair every keepalive interval, so the path is measured without
+// any additional traffic. It must not be called concurrently.
+func (w *WorkerICE) PathQuality() quality.Stats {
+	stats, ok := w.selectedPairStats()
+	if !ok {
+		w.pathQualityPairID = ""
+		w.pathQuality.Reset()
+		return quality.Stats{}
+	}
+
+	pairID := stats.LocalCandidateID + "/" + stats.RemoteCandidateID
+	if pairID != w.pathQualityPairID {
+		w.pathQualityPairID = pairID
+		w.pathQuality.Reset()
+	}
+
+	totalRTT := time.Duration(stats.TotalRoundTripTime * float64(time.Second))
+	w.pathQuality.Update(stats.RequestsSent, stats.ResponsesReceived, totalRTT)
+	return w.pathQuality.Stats()
+}
+
+// selectedPairStats returns the statistics of the selected candidate pair. The selected pair stats of the agent lack
+// the sent requests, so look it up in the stats of all pairs.
+func (w *WorkerICE) selectedPairStats() (ice.CandidatePairStats, bool) {
+	w.muxAgent.Lock()
+	agent := w.agent
+	w.muxAgent.Unlock()
+
+	if agent == nil {
+		return ice.CandidatePairStats{}, false
+	}
+
+	pair, err := agent.GetSelectedCandidatePair()
+	if err != nil || pair == nil {
+		return ice.CandidatePairStats{}, false
+	}
+
+	for _, stats := range agent.GetCandidatePairsStats() {
+		if stats.LocalCandidateID == pair.Local.ID() && stats.RemoteCandidateID == pair.Remote.ID() {
+			return stats, true
+		}
+	}
+	return ice.CandidatePairStats{}, false
+}
+
 func (w *WorkerICE) reCreateAgent(dialerCancel context.CancelFunc, candidates []ice.CandidateType) (*icemaker.ThreadSafeAgent, error) {
 	agent, err := icemaker.NewAgent(w.ctx, w.iFaceDiscover, w.config.ICEConfig, candidates, w.localUfrag, w.localPwd)
 	if err != nil {
@@ -6,12 +6,18 @@ import (
 	"net"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/netbirdio/netbird/client/internal/peer/quality"
 	relayClient "github.com/netbirdio/netbird/shared/relay/client"
 )
 
+const (
+	relayProbeTimeout = time.Second
+)
+
 type RelayConnInfo struct {
 	relayedConn     net.Conn
 	rosenpassPubKey []byte
@@ -26,8 +32,9 @@ type WorkerRelay struct {
 	conn         *Conn
 	relayManager *relayClient.Manager
 
-	relayedConn net.Conn
+	relayedConn *quality.Conn
 	relayLock   sync.Mutex
+	pathQuality *quality.Window
 
 	relaySupportedOnRemotePeer atomic.Bool
 
@@ -43,6 +50,7 @@ func NewWorkerRelay(ctx context.Context, log *log.Entry, ctrl bool, config ConnC
 		conn:         conn,
 		relayManager: relayManager,
 		wgWatcher:    NewWGWatcher(log, config.WgConfig.WgInterface, config.Key, stateDump),
+		pathQuality:  quality.NewWindow(pathQualityWindowSize),
 	}
 	return r
 }
@@ -74,20 +82,24 @@ func (w *WorkerRelay) OnNewOffer(remoteOfferAnswer *OfferAnswer) {
 		return
 	}
 
+	// the probes of the remote peer are answered by the wrapper, even while the WireGuard proxy is paused
+	probeConn := quality.NewConn(relayedConn)
+
 	w.relayLock.Lock()
-	w.relayedConn = relayedConn
+	w.relayedConn = probeConn
+	w.pathQuality.Reset()
 	w.relayLock.Unlock()
 
 	err = w.relayManager.AddCloseListener(srv, w.onRelayClientDisconnected)
 	if err != nil {
 		log.Errorf("failed to add close listener: %s", err)
-		_ = relayedConn.Close()
+		_ = probeConn.Close()
 		return
 	}
 
 	w.log.Debugf("peer conn opened via Relay: %s", srv)
 	go w.conn.onRelayConnectionIsReady(RelayConnInfo{
-		relayedConn:     relayedConn,
+		relayedConn:     probeConn,
 		rosenpassPubKey: remoteOfferAnswer.RosenpassPubKey,
 		rosenpassAddr:   remoteOfferAnswer.RosenpassAddr,
 	})
@@ -113,6 +125,12 @@ func (w *WorkerRelay) RelayIsSupportedLocally() bool {
 	return w.relayManager.HasRelayAddress()
 }
 
+// RelayIsConnectedLocally returns whether the local peer is connected to its home relay server
+func (w *WorkerRelay) RelayIsConnectedLocally() bool {
+	_, err := w.relayManager.RelayInstanceAddress()
+	return err == nil
+}
+
 func (w *WorkerRelay) CloseConn() {
 	w.relayLock.Lock()
 	defer w.relayLock.Unlock()
@@ -125,6 +143,32 @@ func (w *WorkerRelay) CloseConn() {
 	}
 }
 
+// ProbePath sends a probe over the relayed connection and returns the quality of the relayed path.
+// A probe without a reply within relayProbeTimeout is recorded as lost.
+func (w *WorkerRelay) ProbePath(ctx context.Context) quality.Stats {
+	w.relayLock.Lock()
+	probeConn := w.relayedConn
+	w.relayLock.Unlock()
+
+	if probeConn == nil {
+		return quality.Stats{}
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, relayProbeTimeout)
+	defer cancel()
+
+	rtt, err := probeConn.Probe(probeCtx)
+	switch {
+	case err == nil:
+		w.pathQuality.Add(rtt)
+	case errors.Is(err, context.DeadlineExceeded):
+		w.pathQuality.AddLost()
+	default:
+		w.log.Debugf("failed to probe relayed path: %v", err)
+	}
+	return w.pathQuality.Stats()
+}
+
 func (w *WorkerRelay) onWGDisconnected() {
 	w.relayLock.Lock()
 	_ = w.relayedConn.Close()
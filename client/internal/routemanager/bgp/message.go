@@ -0,0 +1,330 @@
+package bgp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+)
+
+// The subset of BGP-4 (RFC 4271) needed to announce IPv4 unicast prefixes with 4-octet AS number support (RFC 6793)
+
+const (
+	bgpVersion    = 4
+	headerLen     = 19
+	maxMessageLen = 4096
+	// asTrans is sent in the OPEN message instead of AS numbers that don't fit into 2 octets
+	asTrans = 23456
+)
+
+type messageType uint8
+
+const (
+	msgOpen         messageType = 1
+	msgUpdate       messageType = 2
+	msgNotification messageType = 3
+	msgKeepalive    messageType = 4
+)
+
+const (
+	capMultiprotocol = 1
+	capFourOctetAS   = 65
+	optParamCaps     = 2
+)
+
+const (
+	attrFlagTransitive = 0x40
+	attrOrigin         = 1
+	attrASPath         = 2
+	attrNextHop        = 3
+	originIGP          = 0
+	asPathSequence     = 2
+)
+
+// notification error codes and subcodes
+const (
+	errOpenMessage       = 2
+	errHoldTimerExpired  = 4
+	errCease             = 6
+	subcodeBadPeerAS     = 2
+	subcodeUnacceptable  = 6
+	subcodeAdminShutdown = 2
+)
+
+var errNotification = errors.New("notification received")
+
+type openMessage struct {
+	asn      uint32
+	holdTime uint16
+	routerID netip.Addr
+	// fourOctetAS is true if the speaker supports 4-octet AS numbers, asn then holds the value of the capability
+	fourOctetAS bool
+}
+
+func (o openMessage) marshal() []byte {
+	myAS := uint16(asTrans)
+	if o.asn <= 0xffff {
+		myAS = uint16(o.asn)
+	}
+
+	// multiprotocol IPv4 unicast and 4-octet AS number capabilities
+	caps := []byte{
+		capMultiprotocol, 4, 0, 1, 0, 1,
+		capFourOctetAS, 4, 0, 0, 0, 0,
+	}
+	binary.BigEndian.PutUint32(caps[8:], o.asn)
+
+	body := make([]byte, 0, 10+2+len(caps))
+	body = append(body, bgpVersion)
+	body = binary.BigEndian.AppendUint16(body, myAS)
+	body = binary.BigEndian.AppendUint16(body, o.holdTime)
+	routerID := o.routerID.As4()
+	body = append(body, routerID[:]...)
+	body = append(body, byte(2+len(caps)), optParamCaps, byte(len(caps)))
+	return append(body, caps...)
+}
+
+func parseOpen(body []byte) (openMessage, error) {
+	if len(body) < 10 {
+		return openMessage{}, fmt.Errorf("open message too short: %d bytes", len(body))
+	}
+	if body[0] != bgpVersion {
+		return openMessage{}, fmt.Errorf("unsupported BGP version %d", body[0])
+	}
+
+	o := openMessage{
+		asn:      uint32(binary.BigEndian.Uint16(body[1:3])),
+		holdTime: binary.BigEndian.Uint16(body[3:5]),
+		routerID: netip.AddrFrom4([4]byte(body[5:9])),
+	}
+
+	params := body[10:]
+	if len(params) != int(body[9]) {
+		return openMessage{}, fmt.Errorf("invalid optional parameters length %d", body[9])
+	}
+	for len(params) >= 2 {
+		paramType, paramLen := params[0], int(params[1])
+		if len(params) < 2+paramLen {
+			return openMessage{}, errors.New("truncated optional parameter")
+		}
+		if paramType == optParamCaps {
+			o.parseCapabilities(params[2 : 2+paramLen])
+		}
+		params = params[2+paramLen:]
+	}
+	return o, nil
+}
+
+func (o *openMessage) parseCapabilities(caps []byte) {
+	for len(caps) >= 2 {
+		code, capLen := caps[0], int(caps[1])
+		if len(caps) < 2+capLen {
+			return
+		}
+		if code == capFourOctetAS && capLen == 4 {
+			o.fourOctetAS = true
+			o.asn = binary.BigEndian.Uint32(caps[2:6])
+		}
+		caps = caps[2+capLen:]
+	}
+}
+
+type updateMessage struct {
+	withdrawn []netip.Prefix
+	nlri      []netip.Prefix
+	asPath    []uint32
+	nextHop   netip.Addr
+}
+
+// marshal encodes the update, the path attributes are only included if the update announces prefixes.
+// fourOctetAS selects the AS_PATH encoding negotiated with the neighbor.
+func (u updateMessage) marshal(fourOctetAS bool) []byte {
+	withdrawn := marshalPrefixes(u.withdrawn)
+
+	var attrs []byte
+	if len(u.nlri) > 0 {
+		attrs = append(attrs, attrFlagTransitive, attrOrigin, 1, originIGP)
+
+		asSize := 2
+		if fourOctetAS {
+			asSize = 4
+		}
+		asPath := []byte{asPathSequence, byte(len(u.asPath))}
+		for _, asn := range u.asPath {
+			if fourOctetAS {
+				asPath = binary.BigEndian.AppendUint32(asPath, asn)
+			} else {
+				asPath = binary.BigEndian.AppendUint16(asPath, uint16(min(asn, asTrans)))
+			}
+		}
+		attrs = append(attrs, attrFlagTransitive, attrASPath, byte(2+asSize*len(u.asPath)))
+		attrs = append(attrs, asPath...)
+
+		nextHop := u.nextHop.As4()
+		attrs = append(attrs, attrFlagTransitive, attrNextHop, 4)
+		attrs = append(attrs, nextHop[:]...)
+	}
+
+	body := make([]byte, 0, 4+len(withdrawn)+len(attrs))
+	body = binary.BigEndian.AppendUint16(body, uint16(len(withdrawn)))
+	body = append(body, withdrawn...)
+	body = binary.BigEndian.AppendUint16(body, uint16(len(attrs)))
+	body = append(body, attrs...)
+	return append(body, marshalPrefixes(u.nlri)...)
+}
+
+func parseUpdate(body []byte, fourOctetAS bool) (updateMessage, error) {
+	var u updateMessage
+	if len(body) < 4 {
+		return u, errors.New("update message too short")
+	}
+
+	withdrawnLen := int(binary.BigEndian.Uint16(body))
+	if len(body) < 4+withdrawnLen {
+		return u, errors.New("invalid withdrawn routes length")
+	}
+	var err error
+	if u.withdrawn, err = parsePrefixes(body[2 : 2+withdrawnLen]); err != nil {
+		return u, err
+	}
+
+	rest := body[2+withdrawnLen:]
+	attrsLen := int(binary.BigEndian.Uint16(rest))
+	if len(rest) < 2+attrsLen {
+		return u, errors.New("invalid path attributes length")
+	}
+	if err = u.parseAttributes(rest[2:2+attrsLen], fourOctetAS); err != nil {
+		return u, err
+	}
+
+	u.nlri, err = parsePrefixes(rest[2+attrsLen:])
+	return u, err
+}
+
+func (u *updateMessage) parseAttributes(attrs []byte, fourOctetAS bool) error {
+	for len(attrs) >= 3 {
+		flags, code := attrs[0], attrs[1]
+		offset, attrLen := 3, int(attrs[2])
+		// extended length
+		if flags&0x10 != 0 {
+			if len(attrs) < 4 {
+				return errors.New("truncated path attribute")
+			}
+			offset, attrLen = 4, int(binary.BigEndian.Uint16(attrs[2:4]))
+		}
+		if len(attrs) < offset+attrLen {
+			return errors.New("truncated path attribute")
+		}
+		value := attrs[offset : offset+attrLen]
+
+		switch code {
+		case attrNextHop:
+			if attrLen != 4 {
+				return errors.New("invalid next hop")
+			}
+			u.nextHop = netip.AddrFrom4([4]byte(value))
+		case attrASPath:
+			u.asPath = parseASPath(value, fourOctetAS)
+		}
+		attrs = attrs[offset+attrLen:]
+	}
+	return nil
+}
+
+func parseASPath(value []byte, fourOctetAS bool) []uint32 {
+	asSize := 2
+	if fourOctetAS {
+		asSize = 4
+	}
+
+	var path []uint32
+	for len(value) >= 2 {
+		count := int(value[1])
+		value = value[2:]
+		if len(value) < count*asSize {
+			return path
+		}
+		for i := 0; i < count; i++ {
+			if fourOctetAS {
+				path = append(path, binary.BigEndian.Uint32(value[i*4:]))
+			} else {
+				path = append(path, uint32(binary.BigEndian.Uint16(value[i*2:])))
+			}
+		}
+		value = value[count*asSize:]
+	}
+	return path
+}
+
+// marshalPrefixes encodes the prefixes as length followed by the significant octets of the address
+func marshalPrefixes(prefixes []netip.Prefix) []byte {
+	var b []byte
+	for _, prefix := range prefixes {
+		addr := prefix.Masked().Addr().As4()
+		b = append(b, byte(prefix.Bits()))
+		b = append(b, addr[:(prefix.Bits()+7)/8]...)
+	}
+	return b
+}
+
+func parsePrefixes(b []byte) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for len(b) > 0 {
+		bits := int(b[0])
+		octets := (bits + 7) / 8
+		if bits > 32 || len(b) < 1+octets {
+			return nil, errors.New("invalid prefix")
+		}
+		var addr [4]byte
+		copy(addr[:], b[1:1+octets])
+		prefixes = append(prefixes, netip.PrefixFrom(netip.AddrFrom4(addr), bits))
+		b = b[1+octets:]
+	}
+	return prefixes, nil
+}
+
+func notification(code, subcode byte) []byte {
+	return []byte{code, subcode}
+}
+
+func writeMessage(w io.Writer, msgType messageType, body []byte) error {
+	length := headerLen + len(body)
+	if length > maxMessageLen {
+		return fmt.Errorf("message too long: %d bytes", length)
+	}
+
+	msg := make([]byte, 0, length)
+	for i := 0; i < 16; i++ {
+		msg = append(msg, 0xff)
+	}
+	msg = binary.BigEndian.AppendUint16(msg, uint16(length))
+	msg = append(msg, byte(msgType))
+	msg = append(msg, body...)
+
+	_, err := w.Write(msg)
+	return err
+}
+
+func readMessage(r io.Reader) (messageType, []byte, error) {
+	header := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+	for _, b := range header[:16] {
+		if b != 0xff {
+			return 0, nil, errors.New("invalid message marker")
+		}
+	}
+
+	length := int(binary.BigEndian.Uint16(header[16:18]))
+	if length < headerLen || length > maxMessageLen {
+		return 0, nil, fmt.Errorf("invalid message length %d", length)
+	}
+
+	body := make([]byte, length-headerLen)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return messageType(header[18]), body, nil
+}
@@ -0,0 +1,82 @@
+package bgp
+
+import (
+	"bytes"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenMessage(t *testing.T) {
+	testCases := []struct {
+		name         string
+		asn          uint32
+		expectedMyAS uint16
+	}{
+		{name: "2-octet AS number", asn: 65001, expectedMyAS: 65001},
+		{name: "4-octet AS number", asn: 4200000001, expectedMyAS: asTrans},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			open := openMessage{asn: tc.asn, holdTime: 90, routerID: netip.MustParseAddr("192.168.1.10")}
+			body := open.marshal()
+			assert.Equal(t, tc.expectedMyAS, uint16(body[1])<<8|uint16(body[2]))
+
+			parsed, err := parseOpen(body)
+			require.NoError(t, err)
+			assert.Equal(t, tc.asn, parsed.asn)
+			assert.Equal(t, uint16(90), parsed.holdTime)
+			assert.Equal(t, open.routerID, parsed.routerID)
+			assert.True(t, parsed.fourOctetAS)
+		})
+	}
+}
+
+func TestUpdateMessage(t *testing.T) {
+	update := updateMessage{
+		withdrawn: []netip.Prefix{netip.MustParsePrefix("10.3.0.0/16")},
+		nlri: []netip.Prefix{
+			netip.MustParsePrefix("100.64.0.0/10"),
+			netip.MustParsePrefix("10.2.128.0/17"),
+			netip.MustParsePrefix("192.168.7.1/32"),
+		},
+		asPath:  []uint32{4200000001},
+		nextHop: netip.MustParseAddr("192.168.1.10"),
+	}
+
+	for _, fourOctetAS := range []bool{true, false} {
+		parsed, err := parseUpdate(update.marshal(fourOctetAS), fourOctetAS)
+		require.NoError(t, err)
+		assert.Equal(t, update.withdrawn, parsed.withdrawn)
+		assert.Equal(t, update.nlri, parsed.nlri)
+		assert.Equal(t, update.nextHop, parsed.nextHop)
+		if fourOctetAS {
+			assert.Equal(t, update.asPath, parsed.asPath)
+		} else {
+			assert.Equal(t, []uint32{asTrans}, parsed.asPath, "4-octet AS numbers should be sent as AS_TRANS")
+		}
+	}
+
+	withdrawal := updateMessage{withdrawn: []netip.Prefix{netip.MustParsePrefix("10.3.0.0/16")}}
+	body := withdrawal.marshal(true)
+	assert.Equal(t, []byte{0, 3, 16, 10, 3, 0, 0}, body, "withdrawals shouldn't carry path attributes")
+}
+
+func TestReadWriteMessage(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeMessage(&buf, msgKeepalive, nil))
+	assert.Equal(t, headerLen, buf.Len())
+
+	msgType, body, err := readMessage(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, msgKeepalive, msgType)
+	assert.Empty(t, body)
+
+	require.Error(t, writeMessage(&buf, msgUpdate, make([]byte, maxMessageLen)))
+
+	_, _, err = readMessage(bytes.NewReader(make([]byte, headerLen)))
+	require.Error(t, err, "messages without marker should be rejected")
+}
@@ -0,0 +1,351 @@
+package bgp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/route"
+)
+
+const (
+	defaultPort = 179
+	// holdTime is proposed to the neighbors, the negotiated hold time is the lower one of both speakers
+	holdTime = 90 * time.Second
+	// minHoldTime is the lowest hold time accepted from a neighbor, 0 disables keepalives
+	minHoldTime = 3 * time.Second
+	// connectRetryInterval is the time to wait before reconnecting to a neighbor after a failed session
+	connectRetryInterval = 10 * time.Second
+	// dialTimeout limits the TCP connection setup to a neighbor
+	dialTimeout = 10 * time.Second
+	// maxPrefixesPerUpdate keeps the updates below the maximum message size
+	maxPrefixesPerUpdate = 500
+)
+
+// session keeps a BGP session with a neighbor and announces the prefixes to it.
+// Closing the session makes the neighbor withdraw all prefixes learned from it.
+type session struct {
+	localASN uint32
+	neighbor route.BGPNeighbor
+	port     int
+
+	mu       sync.Mutex
+	prefixes map[netip.Prefix]struct{}
+	changed  chan struct{}
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newSession(localASN uint32, neighbor route.BGPNeighbor, port int, prefixes []netip.Prefix) *session {
+	s := &session{
+		localASN: localASN,
+		neighbor: neighbor,
+		port:     port,
+		changed:  make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	s.setPrefixes(prefixes)
+	return s
+}
+
+func (s *session) String() string {
+	return fmt.Sprintf("%s (AS%d)", s.neighbor.Address, s.neighbor.ASN)
+}
+
+// setPrefixes replaces the announced prefixes, the session sends the difference to the neighbor
+func (s *session) setPrefixes(prefixes []netip.Prefix) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prefixes = make(map[netip.Prefix]struct{}, len(prefixes))
+	for _, prefix := range prefixes {
+		s.prefixes[prefix.Masked()] = struct{}{}
+	}
+
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (s *session) getPrefixes() map[netip.Prefix]struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.prefixes)
+}
+
+// start connects to the neighbor and reconnects until the session is stopped
+func (s *session) start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	go func() {
+		defer close(s.done)
+
+		for {
+			err := s.run(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warnf("BGP session with neighbor %s failed, reconnecting in %s: %v", s, connectRetryInterval, err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(connectRetryInterval):
+			}
+		}
+	}()
+}
+
+// stop closes the session administratively and waits until it's down
+func (s *session) stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+func (s *session) run(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp4", net.JoinHostPort(s.neighbor.Address.String(), strconv.Itoa(s.port)))
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Debugf("failed to close BGP connection to %s: %v", s, err)
+		}
+	}()
+
+	// the local address of the session is the next hop of the announced prefixes, it's in the LAN of the neighbor
+	localAddr, ok := conn.LocalAddr().(*net.TCPAddr)
+	if !ok {
+		return fmt.Errorf("unexpected local address %s", conn.LocalAddr())
+	}
+	nextHop, _ := netip.AddrFromSlice(localAddr.IP.To4())
+
+	// the handshake blocks on reads, abort it by closing the connection when the session is stopped
+	stopAbort := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	established, err := s.open(conn, nextHop)
+	if !stopAbort() {
+		return ctx.Err()
+	}
+	if err != nil {
+		return err
+	}
+	log.Infof("BGP session with neighbor %s established", s)
+
+	return s.serve(ctx, conn, established, nextHop)
+}
+
+type establishedSession struct {
+	holdTime    time.Duration
+	fourOctetAS bool
+}
+
+// open exchanges the OPEN messages and waits for the neighbor to confirm the session
+func (s *session) open(conn net.Conn, routerID netip.Addr) (establishedSession, error) {
+	if err := conn.SetDeadline(time.Now().Add(holdTime)); err != nil {
+		return establishedSession{}, err
+	}
+	defer func() {
+		_ = conn.SetDeadline(time.Time{})
+	}()
+
+	open := openMessage{asn: s.localASN, holdTime: uint16(holdTime.Seconds()), routerID: routerID}
+	if err := writeMessage(conn, msgOpen, open.marshal()); err != nil {
+		return establishedSession{}, fmt.Errorf("send open: %w", err)
+	}
+
+	msgType, body, err := readMessage(conn)
+	if err != nil {
+		return establishedSession{}, fmt.Errorf("receive open: %w", err)
+	}
+	if msgType == msgNotification {
+		return establishedSession{}, notificationError(body)
+	}
+	if msgType != msgOpen {
+		return establishedSession{}, fmt.Errorf("expected open message, got type %d", msgType)
+	}
+
+	peerOpen, err := parseOpen(body)
+	if err != nil {
+		return establishedSession{}, fmt.Errorf("parse open: %w", err)
+	}
+	if peerOpen.asn != s.neighbor.ASN {
+		_ = writeMessage(conn, msgNotification, notification(errOpenMessage, subcodeBadPeerAS))
+		return establishedSession{}, fmt.Errorf("neighbor AS number %d doesn't match the configured %d", peerOpen.asn, s.neighbor.ASN)
+	}
+
+	negotiated := min(holdTime, time.Duration(peerOpen.holdTime)*time.Second)
+	if negotiated != 0 && negotiated < minHoldTime {
+		_ = writeMessage(conn, msgNotification, notification(errOpenMessage, subcodeUnacceptable))
+		return establishedSession{}, fmt.Errorf("unacceptable hold time %s", negotiated)
+	}
+
+	if err := writeMessage(conn, msgKeepalive, nil); err != nil {
+		return establishedSession{}, fmt.Errorf("send keepalive: %w", err)
+	}
+
+	msgType, body, err = readMessage(conn)
+	if err != nil {
+		return establishedSession{}, fmt.Errorf("receive keepalive: %w", err)
+	}
+	if msgType == msgNotification {
+		return establishedSession{}, notificationError(body)
+	}
+	if msgType != msgKeepalive {
+		return establishedSession{}, fmt.Errorf("expected keepalive message, got type %d", msgType)
+	}
+
+	return establishedSession{holdTime: negotiated, fourOctetAS: peerOpen.fourOctetAS}, nil
+}
+
+// serve keeps the session alive and sends the prefix changes to the neighbor until the context is canceled
+func (s *session) serve(ctx context.Context, conn net.Conn, established establishedSession, nextHop netip.Addr) error {
+	received := make(chan error, 1)
+	activity := make(chan struct{}, 1)
+	go func() {
+		received <- s.receive(conn, activity)
+	}()
+
+	var keepalive <-chan time.Time
+	var holdTimer *time.Timer
+	var expired <-chan time.Time
+	if established.holdTime > 0 {
+		ticker := time.NewTicker(established.holdTime / 3)
+		defer ticker.Stop()
+		keepalive = ticker.C
+
+		holdTimer = time.NewTimer(established.holdTime)
+		defer holdTimer.Stop()
+		expired = holdTimer.C
+	}
+
+	announced := make(map[netip.Prefix]struct{})
+	if err := s.sync(conn, announced, established.fourOctetAS, nextHop); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Infof("closing BGP session with neighbor %s", s)
+			return writeMessage(conn, msgNotification, notification(errCease, subcodeAdminShutdown))
+		case err := <-received:
+			return err
+		case <-activity:
+			if holdTimer != nil {
+				holdTimer.Reset(established.holdTime)
+			}
+		case <-expired:
+			_ = writeMessage(conn, msgNotification, notification(errHoldTimerExpired, 0))
+			return errors.New("hold timer expired")
+		case <-keepalive:
+			if err := writeMessage(conn, msgKeepalive, nil); err != nil {
+				return fmt.Errorf("send keepalive: %w", err)
+			}
+		case <-s.changed:
+			if err := s.sync(conn, announced, established.fourOctetAS, nextHop); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// receive reads the messages of the neighbor until the connection fails. Updates of the neighbor are ignored,
+// the routing peer only announces prefixes.
+func (s *session) receive(conn net.Conn, activity chan<- struct{}) error {
+	for {
+		msgType, body, err := readMessage(conn)
+		if err != nil {
+			return fmt.Errorf("receive: %w", err)
+		}
+
+		switch msgType {
+		case msgNotification:
+			return notificationError(body)
+		case msgKeepalive, msgUpdate:
+			select {
+			case activity <- struct{}{}:
+			default:
+			}
+		default:
+			return fmt.Errorf("unexpected message type %d", msgType)
+		}
+	}
+}
+
+// sync sends the withdrawn and the new prefixes to the neighbor and updates the announced prefixes
+func (s *session) sync(conn net.Conn, announced map[netip.Prefix]struct{}, fourOctetAS bool, nextHop netip.Addr) error {
+	desired := s.getPrefixes()
+
+	var withdrawn, added []netip.Prefix
+	for prefix := range announced {
+		if _, ok := desired[prefix]; !ok {
+			withdrawn = append(withdrawn, prefix)
+		}
+	}
+	for prefix := range desired {
+		if _, ok := announced[prefix]; !ok {
+			added = append(added, prefix)
+		}
+	}
+	slices.SortFunc(withdrawn, comparePrefixes)
+	slices.SortFunc(added, comparePrefixes)
+
+	for chunk := range slices.Chunk(withdrawn, maxPrefixesPerUpdate) {
+		if err := writeMessage(conn, msgUpdate, updateMessage{withdrawn: chunk}.marshal(fourOctetAS)); err != nil {
+			return fmt.Errorf("send withdrawal: %w", err)
+		}
+		for _, prefix := range chunk {
+			delete(announced, prefix)
+		}
+	}
+
+	for chunk := range slices.Chunk(added, maxPrefixesPerUpdate) {
+		update := updateMessage{
+			nlri:    chunk,
+			asPath:  []uint32{s.localASN},
+			nextHop: nextHop,
+		}
+		if err := writeMessage(conn, msgUpdate, update.marshal(fourOctetAS)); err != nil {
+			return fmt.Errorf("send announcement: %w", err)
+		}
+		for _, prefix := range chunk {
+			announced[prefix] = struct{}{}
+		}
+	}
+
+	if len(withdrawn) > 0 || len(added) > 0 {
+		log.Debugf("BGP neighbor %s: announced %v, withdrew %v", s, added, withdrawn)
+	}
+	return nil
+}
+
+func notificationError(body []byte) error {
+	if len(body) < 2 {
+		return errNotification
+	}
+	return fmt.Errorf("%w: code %d, subcode %d", errNotification, body[0], body[1])
+}
+
+func comparePrefixes(a, b netip.Prefix) int {
+	if c := a.Addr().Compare(b.Addr()); c != 0 {
+		return c
+	}
+	return a.Bits() - b.Bits()
+}
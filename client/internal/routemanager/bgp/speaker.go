@@ -0,0 +1,83 @@
+package bgp
+
+import (
+	"context"
+	"net/netip"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/route"
+)
+
+// sessionKey identifies a session, a neighbor configured with different local AS numbers gets a session per AS number
+type sessionKey struct {
+	localASN uint32
+	neighbor route.BGPNeighbor
+}
+
+// Speaker announces prefixes to the BGP neighbors configured in the routes served by this peer.
+// It keeps a session per neighbor while at least one served route configures it and closes the sessions of neighbors
+// that aren't configured anymore, which withdraws the prefixes.
+type Speaker struct {
+	ctx      context.Context
+	mu       sync.Mutex
+	sessions map[sessionKey]*session
+	port     int
+}
+
+// NewSpeaker returns a speaker whose sessions are closed when the context is canceled
+func NewSpeaker(ctx context.Context) *Speaker {
+	return &Speaker{
+		ctx:      ctx,
+		sessions: make(map[sessionKey]*session),
+		port:     defaultPort,
+	}
+}
+
+// Update opens sessions to the neighbors of the served routes, closes the sessions of neighbors that were removed and
+// announces the prefixes to all neighbors
+func (s *Speaker) Update(serverRoutes map[route.ID]*route.Route, prefixes []netip.Prefix) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	configured := make(map[sessionKey]struct{})
+	for _, r := range serverRoutes {
+		if r.BGP == nil {
+			continue
+		}
+		for _, neighbor := range r.BGP.Neighbors {
+			configured[sessionKey{localASN: r.BGP.LocalASN, neighbor: neighbor}] = struct{}{}
+		}
+	}
+
+	for key, sess := range s.sessions {
+		if _, ok := configured[key]; ok {
+			continue
+		}
+		sess.stop()
+		delete(s.sessions, key)
+	}
+
+	for key := range configured {
+		if sess, ok := s.sessions[key]; ok {
+			sess.setPrefixes(prefixes)
+			continue
+		}
+		log.Infof("starting BGP session with neighbor %s (AS%d) as AS%d", key.neighbor.Address, key.neighbor.ASN, key.localASN)
+		sess := newSession(key.localASN, key.neighbor, s.port, prefixes)
+		sess.start(s.ctx)
+		s.sessions[key] = sess
+	}
+}
+
+// Stop closes all sessions
+func (s *Speaker) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, sess := range s.sessions {
+		sess.stop()
+		delete(s.sessions, key)
+	}
+}
@@ -0,0 +1,272 @@
+//go:build linux
+
+package bgp
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	api "github.com/osrg/gobgp/v3/api"
+	"github.com/osrg/gobgp/v3/pkg/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/netbirdio/netbird/route"
+)
+
+// TestSpeaker_Netns runs a session over a veth pair with a neighbor in another network namespace, like a router in the
+// LAN of the routing peer, and checks that the address of the routing peer in that LAN is announced as next hop
+func TestSpeaker_Netns(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating network namespaces requires root privileges")
+	}
+
+	localAddr := netip.MustParsePrefix("198.18.0.1/24")
+	neighborAddr := netip.MustParsePrefix("198.18.0.2/24")
+	neighbor := newNetnsNeighbor(t, localAddr, neighborAddr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	speaker := NewSpeaker(ctx)
+	speaker.port = neighbor.port()
+	defer speaker.Stop()
+
+	routes := map[route.ID]*route.Route{
+		"route1": {
+			ID: "route1",
+			BGP: &route.BGP{
+				LocalASN:  localASN,
+				Neighbors: []route.BGPNeighbor{{Address: neighborAddr.Addr(), ASN: neighborASN}},
+			},
+		},
+	}
+	netbirdRange := netip.MustParsePrefix("100.64.0.0/10")
+
+	speaker.Update(routes, []netip.Prefix{netbirdRange})
+	neighbor.accept()
+
+	assert.Equal(t, localAddr.Addr(), neighbor.open.routerID)
+	update := neighbor.expectUpdate()
+	assert.Equal(t, []netip.Prefix{netbirdRange}, update.nlri)
+	assert.Equal(t, localAddr.Addr(), update.nextHop, "the address in the LAN of the neighbor should be the next hop")
+
+	delete(routes, "route1")
+	speaker.Update(routes, nil)
+	body := neighbor.expect(msgNotification)
+	assert.Equal(t, notification(errCease, subcodeAdminShutdown), body)
+}
+
+// TestSpeaker_GoBGP checks the speaker against GoBGP as neighbor in another network namespace, so the messages of the
+// speaker are parsed by an independent BGP implementation
+func TestSpeaker_GoBGP(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating network namespaces requires root privileges")
+	}
+
+	localAddr := netip.MustParsePrefix("198.18.1.1/24")
+	neighborAddr := netip.MustParsePrefix("198.18.1.2/24")
+	neighbor := newGoBGPNeighbor(t, localAddr, neighborAddr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	speaker := NewSpeaker(ctx)
+	defer speaker.Stop()
+
+	routes := map[route.ID]*route.Route{
+		"route1": {
+			ID: "route1",
+			BGP: &route.BGP{
+				LocalASN:  localASN,
+				Neighbors: []route.BGPNeighbor{{Address: neighborAddr.Addr(), ASN: neighborASN}},
+			},
+		},
+	}
+	netbirdRange := netip.MustParsePrefix("100.64.0.0/10")
+	remoteSite := netip.MustParsePrefix("10.2.0.0/16")
+
+	speaker.Update(routes, []netip.Prefix{netbirdRange, remoteSite})
+	require.Eventually(t, func() bool {
+		return len(listGoBGPPaths(t, neighbor)) == 2
+	}, 10*time.Second, 100*time.Millisecond, "the neighbor should learn the announced prefixes")
+
+	for prefix, path := range listGoBGPPaths(t, neighbor) {
+		assert.Contains(t, []netip.Prefix{netbirdRange, remoteSite}, prefix)
+		assert.Equal(t, localAddr.Addr().String(), path.nextHop, "the address in the LAN of the neighbor should be the next hop")
+		assert.Equal(t, []uint32{localASN}, path.asPath)
+	}
+
+	speaker.Update(routes, []netip.Prefix{netbirdRange})
+	require.Eventually(t, func() bool {
+		paths := listGoBGPPaths(t, neighbor)
+		_, ok := paths[netbirdRange]
+		return len(paths) == 1 && ok
+	}, 10*time.Second, 100*time.Millisecond, "the neighbor should remove the withdrawn prefix")
+
+	delete(routes, "route1")
+	speaker.Update(routes, nil)
+	require.Eventually(t, func() bool {
+		return len(listGoBGPPaths(t, neighbor)) == 0
+	}, 10*time.Second, 100*time.Millisecond, "the neighbor should drop the routes of the closed session")
+}
+
+type goBGPPath struct {
+	nextHop string
+	asPath  []uint32
+}
+
+// newGoBGPNeighbor runs GoBGP in a new network namespace and configures the speaker as its passive neighbor
+func newGoBGPNeighbor(t *testing.T, localAddr, neighborAddr netip.Prefix) *server.BgpServer {
+	t.Helper()
+
+	runtime.LockOSThread()
+	ns := newNetns(t, localAddr, neighborAddr)
+	runtime.UnlockOSThread()
+
+	bgpServer := server.NewBgpServer()
+	go func() {
+		// GoBGP creates its listener in the goroutine serving its requests, the goroutine stays locked to a thread in
+		// the namespace of the neighbor and ends with the test binary
+		runtime.LockOSThread()
+		if err := netns.Set(ns); err != nil {
+			t.Errorf("enter namespace of the neighbor: %v", err)
+			return
+		}
+		bgpServer.Serve()
+	}()
+
+	ctx := context.Background()
+	require.NoError(t, bgpServer.StartBgp(ctx, &api.StartBgpRequest{
+		Global: &api.Global{
+			Asn:             neighborASN,
+			RouterId:        neighborAddr.Addr().String(),
+			ListenPort:      defaultPort,
+			ListenAddresses: []string{neighborAddr.Addr().String()},
+		},
+	}))
+	t.Cleanup(bgpServer.Stop)
+
+	require.NoError(t, bgpServer.AddPeer(ctx, &api.AddPeerRequest{
+		Peer: &api.Peer{
+			Conf:      &api.PeerConf{NeighborAddress: localAddr.Addr().String(), PeerAsn: localASN},
+			Transport: &api.Transport{PassiveMode: true},
+		},
+	}))
+
+	return bgpServer
+}
+
+// listGoBGPPaths returns the IPv4 unicast paths GoBGP learned by prefix
+func listGoBGPPaths(t *testing.T, bgpServer *server.BgpServer) map[netip.Prefix]goBGPPath {
+	t.Helper()
+
+	paths := make(map[netip.Prefix]goBGPPath)
+	err := bgpServer.ListPath(context.Background(), &api.ListPathRequest{
+		TableType: api.TableType_GLOBAL,
+		Family:    &api.Family{Afi: api.Family_AFI_IP, Safi: api.Family_SAFI_UNICAST},
+	}, func(destination *api.Destination) {
+		prefix, err := netip.ParsePrefix(destination.Prefix)
+		require.NoError(t, err)
+		for _, path := range destination.Paths {
+			var learned goBGPPath
+			for _, attr := range path.Pattrs {
+				nextHop := &api.NextHopAttribute{}
+				asPath := &api.AsPathAttribute{}
+				switch {
+				case attr.MessageIs(nextHop):
+					require.NoError(t, attr.UnmarshalTo(nextHop))
+					learned.nextHop = nextHop.NextHop
+				case attr.MessageIs(asPath):
+					require.NoError(t, attr.UnmarshalTo(asPath))
+					for _, segment := range asPath.Segments {
+						learned.asPath = append(learned.asPath, segment.Numbers...)
+					}
+				}
+			}
+			paths[prefix] = learned
+		}
+	})
+	require.NoError(t, err)
+	return paths
+}
+
+// newNetnsNeighbor connects a new network namespace with a veth pair and listens for the speaker in it
+func newNetnsNeighbor(t *testing.T, localAddr, neighborAddr netip.Prefix) *testNeighbor {
+	t.Helper()
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	origin, err := netns.Get()
+	require.NoError(t, err)
+	defer origin.Close()
+
+	ns := newNetns(t, localAddr, neighborAddr)
+
+	require.NoError(t, netns.Set(ns))
+	listener, err := net.Listen("tcp4", net.JoinHostPort(neighborAddr.Addr().String(), "0"))
+	require.NoError(t, netns.Set(origin))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	return &testNeighbor{t: t, listener: listener}
+}
+
+// newNetns creates a network namespace connected to the current one with a veth pair. The caller has to lock the
+// goroutine to its thread.
+func newNetns(t *testing.T, localAddr, neighborAddr netip.Prefix) netns.NsHandle {
+	t.Helper()
+
+	origin, err := netns.Get()
+	require.NoError(t, err)
+	defer origin.Close()
+
+	ns, err := netns.New()
+	// netns.New moves the thread to the new namespace
+	require.NoError(t, netns.Set(origin))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = ns.Close()
+	})
+
+	veth := &netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: "nbbgp0"}, PeerName: "nbbgp1"}
+	require.NoError(t, netlink.LinkAdd(veth))
+	t.Cleanup(func() {
+		// deleting one end removes the pair
+		_ = netlink.LinkDel(veth)
+	})
+	setupLink(t, nil, veth.Name, localAddr)
+
+	peer, err := netlink.LinkByName(veth.PeerName)
+	require.NoError(t, err)
+	require.NoError(t, netlink.LinkSetNsFd(peer, int(ns)))
+
+	handle, err := netlink.NewHandleAt(ns)
+	require.NoError(t, err)
+	defer handle.Close()
+	setupLink(t, handle, veth.PeerName, neighborAddr)
+
+	return ns
+}
+
+func setupLink(t *testing.T, handle *netlink.Handle, name string, addr netip.Prefix) {
+	t.Helper()
+
+	if handle == nil {
+		handle = &netlink.Handle{}
+	}
+	link, err := handle.LinkByName(name)
+	require.NoError(t, err)
+	nlAddr, err := netlink.ParseAddr(addr.String())
+	require.NoError(t, err)
+	require.NoError(t, handle.AddrAdd(link, nlAddr))
+	require.NoError(t, handle.LinkSetUp(link))
+}
@@ -0,0 +1,166 @@
+package bgp
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/route"
+)
+
+const (
+	localASN    = 4200000001
+	neighborASN = 65000
+)
+
+// testNeighbor is a minimal BGP peer that accepts a single session from the speaker
+type testNeighbor struct {
+	t        *testing.T
+	listener net.Listener
+	conn     net.Conn
+	open     openMessage
+}
+
+func newTestNeighbor(t *testing.T) *testNeighbor {
+	t.Helper()
+
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	return &testNeighbor{t: t, listener: listener}
+}
+
+func (n *testNeighbor) port() int {
+	return n.listener.Addr().(*net.TCPAddr).Port
+}
+
+// accept waits for the speaker and completes the handshake
+func (n *testNeighbor) accept() {
+	n.t.Helper()
+
+	conn, err := n.listener.Accept()
+	require.NoError(n.t, err)
+	n.t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	require.NoError(n.t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+	n.conn = conn
+
+	body := n.expect(msgOpen)
+	n.open, err = parseOpen(body)
+	require.NoError(n.t, err)
+
+	open := openMessage{asn: neighborASN, holdTime: 9, routerID: netip.MustParseAddr("127.0.0.2")}
+	require.NoError(n.t, writeMessage(conn, msgOpen, open.marshal()))
+	require.NoError(n.t, writeMessage(conn, msgKeepalive, nil))
+	n.expect(msgKeepalive)
+}
+
+// expect reads the next message that isn't a keepalive unless a keepalive is expected
+func (n *testNeighbor) expect(expected messageType) []byte {
+	n.t.Helper()
+
+	for {
+		msgType, body, err := readMessage(n.conn)
+		require.NoError(n.t, err)
+		if msgType == msgKeepalive && expected != msgKeepalive {
+			continue
+		}
+		require.Equal(n.t, expected, msgType)
+		return body
+	}
+}
+
+func (n *testNeighbor) expectUpdate() updateMessage {
+	n.t.Helper()
+
+	update, err := parseUpdate(n.expect(msgUpdate), n.open.fourOctetAS)
+	require.NoError(n.t, err)
+	return update
+}
+
+func TestSpeaker(t *testing.T) {
+	neighbor := newTestNeighbor(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	speaker := NewSpeaker(ctx)
+	speaker.port = neighbor.port()
+	defer speaker.Stop()
+
+	routes := map[route.ID]*route.Route{
+		"route1": {
+			ID: "route1",
+			BGP: &route.BGP{
+				LocalASN:  localASN,
+				Neighbors: []route.BGPNeighbor{{Address: netip.MustParseAddr("127.0.0.1"), ASN: neighborASN}},
+			},
+		},
+		"route2": {ID: "route2"},
+	}
+	netbirdRange := netip.MustParsePrefix("100.64.0.0/10")
+	remoteSite := netip.MustParsePrefix("10.2.0.0/16")
+
+	speaker.Update(routes, []netip.Prefix{netbirdRange, remoteSite})
+	neighbor.accept()
+
+	assert.Equal(t, uint32(localASN), neighbor.open.asn)
+	assert.True(t, neighbor.open.fourOctetAS)
+	assert.Equal(t, netip.MustParseAddr("127.0.0.1"), neighbor.open.routerID)
+
+	update := neighbor.expectUpdate()
+	assert.Equal(t, []netip.Prefix{remoteSite, netbirdRange}, update.nlri)
+	assert.Empty(t, update.withdrawn)
+	assert.Equal(t, []uint32{localASN}, update.asPath)
+	assert.Equal(t, netip.MustParseAddr("127.0.0.1"), update.nextHop, "the local address of the session should be the next hop")
+
+	speaker.Update(routes, []netip.Prefix{netbirdRange})
+	update = neighbor.expectUpdate()
+	assert.Equal(t, []netip.Prefix{remoteSite}, update.withdrawn)
+	assert.Empty(t, update.nlri)
+
+	delete(routes, "route1")
+	speaker.Update(routes, []netip.Prefix{netbirdRange})
+	body := neighbor.expect(msgNotification)
+	assert.Equal(t, notification(errCease, subcodeAdminShutdown), body, "the session should be closed when the neighbor isn't configured anymore")
+}
+
+func TestSpeaker_BadPeerAS(t *testing.T) {
+	neighbor := newTestNeighbor(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	speaker := NewSpeaker(ctx)
+	speaker.port = neighbor.port()
+	defer speaker.Stop()
+
+	speaker.Update(map[route.ID]*route.Route{
+		"route1": {
+			ID: "route1",
+			BGP: &route.BGP{
+				LocalASN:  localASN,
+				Neighbors: []route.BGPNeighbor{{Address: netip.MustParseAddr("127.0.0.1"), ASN: neighborASN + 1}},
+			},
+		},
+	}, []netip.Prefix{netip.MustParsePrefix("100.64.0.0/10")})
+
+	conn, err := neighbor.listener.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+	neighbor.conn = conn
+
+	neighbor.expect(msgOpen)
+	open := openMessage{asn: neighborASN, holdTime: 9, routerID: netip.MustParseAddr("127.0.0.2")}
+	require.NoError(t, writeMessage(conn, msgOpen, open.marshal()))
+
+	body := neighbor.expect(msgNotification)
+	assert.Equal(t, notification(errOpenMessage, subcodeBadPeerAS), body)
+}
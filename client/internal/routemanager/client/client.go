@@ -3,7 +3,9 @@ package client
 import (
 	"context"
 	"fmt"
+	"net/netip"
 	"reflect"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -78,8 +80,13 @@ type Watcher struct {
 	routePeersNotifiers map[string]chan struct{} // map of peer key to channel for peer state changes
 	currentChosen       *route.Route
 	currentChosenStatus *routerPeerStatus
+	balancedRoutes      map[netip.Prefix]*route.Route // map of bucket to the route handling it in load-balanced mode
 	handler             RouteHandler
 	updateSerial        uint64
+
+	// selection explains the last routing peer choice, it's read by the daemon while the watcher runs
+	selectionMu sync.Mutex
+	selection   Selection
 }
 
 func NewWatcher(config WatcherConfig) *Watcher {
@@ -92,6 +99,7 @@ func NewWatcher(config WatcherConfig) *Watcher {
 		wgInterface:         config.WGInterface,
 		routes:              make(map[route.ID]*route.Route),
 		routePeersNotifiers: make(map[string]chan struct{}),
+		balancedRoutes:      make(map[netip.Prefix]*route.Route),
 		routeUpdate:         make(chan RoutesUpdate),
 		peerStateUpdate:     make(chan map[string]peer.RouterState),
 		handler:             config.Handler,
@@ -139,6 +147,7 @@ func (w *Watcher) convertRouterPeerStatuses(states map[string]peer.RouterState)
 // preference for non-relayed and direct connections.
 //
 // It follows these prioritization rules:
+// * Health: Routes whose routing peer reports a failing health check are only chosen if no healthy route is available.
 // * Connection status: Both connected and idle peers are considered, but connected peers always take precedence.
 // * Idle peer penalty: Idle peers receive a significant score penalty to ensure any connected peer is preferred.
 // * Metric: Routes with lower metrics (better) are prioritized.
@@ -162,42 +171,13 @@ func (w *Watcher) getBestRouteFromStatuses(routePeerStatuses map[route.ID]router
 	var chosenStatus routerPeerStatus
 
 	for _, r := range w.routes {
-		tempScore := float64(0)
 		peerStatus, found := routePeerStatuses[r.ID]
 		// connecting status equals disconnected: no wireguard endpoint to assign allowed IPs to
 		if !found || peerStatus.status == peer.StatusConnecting {
 			continue
 		}
 
-		if r.Metric < route.MaxMetric {
-			metricDiff := route.MaxMetric - r.Metric
-			tempScore = float64(metricDiff) * 10
-		}
-
-		// in some temporal cases, latency can be 0, so we set it to 999ms to not block but try to avoid this route
-		latency := 999 * time.Millisecond
-		if peerStatus.latency != 0 {
-			latency = peerStatus.latency
-		} else if !peerStatus.relayed && peerStatus.status != peer.StatusIdle {
-			log.Tracef("peer %s has 0 latency: [%v]", r.Peer, w.handler)
-		}
-
-		// avoid negative tempScore on the higher latency calculation
-		if latency > 1*time.Second {
-			latency = 999 * time.Millisecond
-		}
-
-		// higher latency is worse score
-		tempScore += 1 - latency.Seconds()
-
-		// apply significant penalty for idle peers to ensure connected peers always take precedence
-		if peerStatus.status == peer.StatusConnected {
-			tempScore += 100_000
-		}
-
-		if !peerStatus.relayed {
-			tempScore++
-		}
+		tempScore := w.routeScore(r, peerStatus)
 
 		if tempScore > chosenScore || (tempScore == chosenScore && chosen == "") {
 			chosen = r.ID
@@ -252,6 +232,47 @@ func (w *Watcher) getBestRouteFromStatuses(routePeerStatuses map[route.ID]router
 	return chosen, chosenStatus
 }
 
+// routeScore computes the score of an available routing peer, see getBestRouteFromStatuses for the rules
+func (w *Watcher) routeScore(r *route.Route, peerStatus routerPeerStatus) float64 {
+	tempScore := float64(0)
+	if r.Metric < route.MaxMetric {
+		metricDiff := route.MaxMetric - r.Metric
+		tempScore = float64(metricDiff) * 10
+	}
+
+	// in some temporal cases, latency can be 0, so we set it to 999ms to not block but try to avoid this route
+	latency := 999 * time.Millisecond
+	if peerStatus.latency != 0 {
+		latency = peerStatus.latency
+	} else if !peerStatus.relayed && peerStatus.status != peer.StatusIdle {
+		log.Tracef("peer %s has 0 latency: [%v]", r.Peer, w.handler)
+	}
+
+	// avoid negative tempScore on the higher latency calculation
+	if latency > 1*time.Second {
+		latency = 999 * time.Millisecond
+	}
+
+	// higher latency is worse score
+	tempScore += 1 - latency.Seconds()
+
+	// apply significant penalty for idle peers to ensure connected peers always take precedence
+	if peerStatus.status == peer.StatusConnected {
+		tempScore += 100_000
+	}
+
+	if !peerStatus.relayed {
+		tempScore++
+	}
+
+	// a healthy route outweighs any other criteria, an unhealthy one is only a fallback
+	if !r.Unhealthy {
+		tempScore += 1_000_000
+	}
+
+	return tempScore
+}
+
 func (w *Watcher) watchPeerStatusChanges(ctx context.Context, peerKey string, peerStateUpdate chan map[string]peer.RouterState, closer chan struct{}) {
 	subscription := w.statusRecorder.SubscribeToPeerStateChanges(ctx, peerKey)
 	defer w.statusRecorder.UnsubscribePeerStateChanges(subscription)
@@ -328,10 +349,19 @@ func (w *Watcher) shouldSkipRecalculation(newChosenID route.ID, newStatus router
 }
 
 func (w *Watcher) recalculateRoutes(rsn reason, routerPeerStatuses map[route.ID]routerPeerStatus) error {
+	if w.isLoadBalanced() {
+		return w.recalculateBalancedRoutes(rsn, routerPeerStatuses)
+	}
+
 	newChosenID, newStatus := w.getBestRouteFromStatuses(routerPeerStatuses)
+	w.updateSelection(rsn, w.candidatesFromStatuses(routerPeerStatuses), newChosenID)
 
 	// If no route is chosen, remove the route from the peer
 	if newChosenID == "" {
+		if err := w.removeObsoleteBalancedRoutes(); err != nil {
+			return err
+		}
+
 		if w.currentChosen == nil {
 			return nil
 		}
@@ -348,7 +378,7 @@ func (w *Watcher) recalculateRoutes(rsn reason, routerPeerStatuses map[route.ID]
 
 	// If we can skip recalculation for the same route without changes, do nothing
 	if w.shouldSkipRecalculation(newChosenID, newStatus) {
-		return nil
+		return w.removeObsoleteBalancedRoutes()
 	}
 
 	// If the chosen route was assigned to a different peer, remove the allowed IPs first
@@ -369,6 +399,18 @@ func (w *Watcher) recalculateRoutes(rsn reason, routerPeerStatuses map[route.ID]
 	w.currentChosen = newChosenRoute
 	w.currentChosenStatus = &newStatus
 
+	return w.removeObsoleteBalancedRoutes()
+}
+
+// removeObsoleteBalancedRoutes removes the buckets of a network that was load-balanced before. The whole network is
+// routed through the chosen peer first, so the traffic isn't interrupted.
+func (w *Watcher) removeObsoleteBalancedRoutes() error {
+	if len(w.balancedRoutes) == 0 {
+		return nil
+	}
+	if err := w.removeBalancedRoutes(reasonRouteUpdate); err != nil {
+		return fmt.Errorf("remove balanced routes: %w", err)
+	}
 	return nil
 }
 
@@ -543,6 +585,12 @@ func (w *Watcher) Stop() {
 
 	w.cancel()
 
+	if len(w.balancedRoutes) > 0 {
+		if err := w.removeBalancedRoutes(reasonShutdown); err != nil {
+			log.Errorf("Failed to remove balanced routes for [%v]: %v", w.handler, err)
+		}
+	}
+
 	if w.currentChosen == nil {
 		return
 	}
@@ -553,7 +601,7 @@ func (w *Watcher) Stop() {
 }
 
 func HandlerFromRoute(params common.HandlerParams) RouteHandler {
-	switch handlerType(params.Route, params.UseNewDNSRoute) {
+	switch handlerType(params.Route, params.UseNewDNSRoute || params.ResolveLocally) {
 	case handlerTypeDnsInterceptor:
 		return dnsinterceptor.New(params)
 	case handlerTypeDynamic:
@@ -0,0 +1,274 @@
+package client
+
+import (
+	"cmp"
+	"fmt"
+	"hash/fnv"
+	"net/netip"
+	"slices"
+
+	"github.com/hashicorp/go-multierror"
+	log "github.com/sirupsen/logrus"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/route"
+)
+
+// maxBucketBits limits the number of buckets a load-balanced network is split into to 2^maxBucketBits
+const maxBucketBits = 6
+
+// BalancedRouteHandler is implemented by route handlers that can spread their network across multiple routing peers.
+// WireGuard binds every destination address to a single peer, so a load-balanced network is split into buckets of
+// destination addresses and every bucket is assigned to one of the routing peers. The balancing is per destination,
+// not per flow: all connections to an address go through the routing peer of its bucket.
+type BalancedRouteHandler interface {
+	Network() netip.Prefix
+	AddPrefixAllowedIPs(prefix netip.Prefix, peerKey string) error
+	MovePrefixAllowedIPs(prefix netip.Prefix, peerKey string) error
+	RemovePrefixAllowedIPs(prefix netip.Prefix) error
+}
+
+// isLoadBalanced returns true if all routes of the HA group enable load balancing and the handler supports it.
+// Dynamic routes and networks of a single address, which can't be split, are always routed through a single peer.
+func (w *Watcher) isLoadBalanced() bool {
+	if len(w.routes) == 0 {
+		return false
+	}
+	handler, ok := w.handler.(BalancedRouteHandler)
+	if !ok {
+		return false
+	}
+	if network := handler.Network(); network.Bits() == network.Addr().BitLen() {
+		return false
+	}
+	for _, r := range w.routes {
+		if !r.LoadBalance {
+			return false
+		}
+	}
+	return true
+}
+
+// getBalancedRoutesFromStatuses returns the routes that share the traffic of the network: the routes of all available
+// peers with the best health and metric. Connected and idle peers are treated alike, idle peers still receive allowed IPs
+// to enable lazy connection triggering.
+func (w *Watcher) getBalancedRoutesFromStatuses(routePeerStatuses map[route.ID]routerPeerStatus) []*route.Route {
+	var balanced []*route.Route
+	for _, r := range w.routes {
+		peerStatus, found := routePeerStatuses[r.ID]
+		// connecting status equals disconnected: no wireguard endpoint to assign allowed IPs to
+		if !found || peerStatus.status == peer.StatusConnecting {
+			continue
+		}
+
+		if len(balanced) > 0 {
+			if diff := compareBalancedRoutes(r, balanced[0]); diff > 0 {
+				continue
+			} else if diff < 0 {
+				balanced = balanced[:0]
+			}
+		}
+		balanced = append(balanced, r)
+	}
+
+	slices.SortFunc(balanced, func(a, b *route.Route) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return balanced
+}
+
+// compareBalancedRoutes orders routes by health first and metric second
+func compareBalancedRoutes(a, b *route.Route) int {
+	if a.Unhealthy != b.Unhealthy {
+		if a.Unhealthy {
+			return 1
+		}
+		return -1
+	}
+	return cmp.Compare(a.Metric, b.Metric)
+}
+
+// splitPrefix splits the prefix into up to 2^maxBucketBits buckets of equal size
+func splitPrefix(prefix netip.Prefix) []netip.Prefix {
+	prefix = prefix.Masked()
+	bits := min(maxBucketBits, prefix.Addr().BitLen()-prefix.Bits())
+
+	buckets := make([]netip.Prefix, 0, 1<<bits)
+	for i := 0; i < 1<<bits; i++ {
+		addr := prefix.Addr().AsSlice()
+		for b := 0; b < bits; b++ {
+			if i&(1<<(bits-1-b)) == 0 {
+				continue
+			}
+			pos := prefix.Bits() + b
+			addr[pos/8] |= 0x80 >> (pos % 8)
+		}
+		bucketAddr, _ := netip.AddrFromSlice(addr)
+		buckets = append(buckets, netip.PrefixFrom(bucketAddr, prefix.Bits()+bits))
+	}
+	return buckets
+}
+
+// pickBalancedRoute chooses the route of a bucket by rendezvous hashing: the bucket goes to the peer with the highest hash
+// of bucket and peer key. A bucket only moves if its peer becomes unavailable or a peer with a higher hash joins, so
+// the flows to a destination keep their routing peer as long as it stays available.
+func pickBalancedRoute(bucket netip.Prefix, routes []*route.Route) *route.Route {
+	var chosen *route.Route
+	var chosenScore uint64
+	for _, r := range routes {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(bucket.String()))
+		_, _ = h.Write([]byte(r.Peer))
+		// fnv doesn't mix the last bytes well, finalize it to spread the buckets evenly
+		score := mix64(h.Sum64())
+		if chosen == nil || score > chosenScore {
+			chosen = r
+			chosenScore = score
+		}
+	}
+	return chosen
+}
+
+// mix64 is the splitmix64 finalizer
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// recalculateBalancedRoutes assigns the buckets of the network to the balanced routes.
+// Only the buckets whose peer changed are updated. The new allowed IPs are added before the old ones are removed, so
+// the traffic to the network isn't interrupted while buckets move between peers.
+func (w *Watcher) recalculateBalancedRoutes(rsn reason, routerPeerStatuses map[route.ID]routerPeerStatus) error {
+	handler := w.handler.(BalancedRouteHandler)
+
+	balanced := w.getBalancedRoutesFromStatuses(routerPeerStatuses)
+	w.updateBalancedSelection(w.candidatesFromStatuses(routerPeerStatuses), balanced)
+	assignments := make(map[netip.Prefix]*route.Route)
+	if len(balanced) > 0 {
+		for _, bucket := range splitPrefix(handler.Network()) {
+			assignments[bucket] = pickBalancedRoute(bucket, balanced)
+		}
+	}
+
+	if len(balanced) == 0 && len(w.balancedRoutes) > 0 {
+		log.Infof("network [%v] has not been assigned any routing peer as no peers are currently available", w.handler)
+	} else if len(balanced) > 0 {
+		log.Debugf("balancing network [%v] across %d routing peers", w.handler, len(balanced))
+	}
+
+	oldPeers := w.routedPeers()
+
+	var merr *multierror.Error
+	for bucket, r := range assignments {
+		current, ok := w.balancedRoutes[bucket]
+		switch {
+		case !ok:
+			if err := handler.AddPrefixAllowedIPs(bucket, r.Peer); err != nil {
+				merr = multierror.Append(merr, fmt.Errorf("add allowed IPs %s for peer %s: %w", bucket, r.Peer, err))
+				continue
+			}
+		case current.Peer != r.Peer:
+			if err := handler.MovePrefixAllowedIPs(bucket, r.Peer); err != nil {
+				merr = multierror.Append(merr, fmt.Errorf("move allowed IPs %s to peer %s: %w", bucket, r.Peer, err))
+				continue
+			}
+		}
+		w.balancedRoutes[bucket] = r
+	}
+
+	// the network was routed through a single peer before, the buckets take over once all of them are assigned
+	if w.currentChosen != nil && merr == nil {
+		if err := w.handler.RemoveAllowedIPs(); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("remove single peer route: %w", err))
+		} else {
+			w.currentChosen = nil
+			w.currentChosenStatus = nil
+		}
+	}
+
+	for bucket := range w.balancedRoutes {
+		if _, ok := assignments[bucket]; ok {
+			continue
+		}
+		if err := handler.RemovePrefixAllowedIPs(bucket); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("remove allowed IPs %s: %w", bucket, err))
+			continue
+		}
+		delete(w.balancedRoutes, bucket)
+	}
+
+	w.updateBalancedPeerStates(oldPeers, rsn)
+
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// removeBalancedRoutes removes the allowed IPs of all buckets
+func (w *Watcher) removeBalancedRoutes(rsn reason) error {
+	handler, ok := w.handler.(BalancedRouteHandler)
+	if !ok {
+		return nil
+	}
+
+	oldPeers := w.routedPeers()
+
+	var merr *multierror.Error
+	for bucket := range w.balancedRoutes {
+		if err := handler.RemovePrefixAllowedIPs(bucket); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("remove allowed IPs %s: %w", bucket, err))
+			continue
+		}
+		delete(w.balancedRoutes, bucket)
+	}
+
+	w.updateBalancedPeerStates(oldPeers, rsn)
+
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// routedPeers returns a route of every peer the network is routed through: the peers that handle at least one bucket
+// and the chosen peer while the network switches between a single peer and load balancing
+func (w *Watcher) routedPeers() map[string]*route.Route {
+	peers := make(map[string]*route.Route)
+	for _, r := range w.balancedRoutes {
+		peers[r.Peer] = r
+	}
+	if w.currentChosen != nil {
+		peers[w.currentChosen.Peer] = w.currentChosen
+	}
+	return peers
+}
+
+// updateBalancedPeerStates updates the routes of the peers in the status recorder and publishes the exit node events
+func (w *Watcher) updateBalancedPeerStates(oldPeers map[string]*route.Route, rsn reason) {
+	newPeers := w.routedPeers()
+
+	for peerKey := range oldPeers {
+		if _, ok := newPeers[peerKey]; ok {
+			continue
+		}
+		if err := w.statusRecorder.RemovePeerStateRoute(peerKey, w.handler.String()); err != nil {
+			log.Warnf("Failed to update peer state: %v", err)
+		}
+	}
+
+	for peerKey, r := range newPeers {
+		if _, ok := oldPeers[peerKey]; ok {
+			continue
+		}
+		if err := w.statusRecorder.AddPeerStateRoute(peerKey, w.handler.String(), r.GetResourceID()); err != nil {
+			log.Warnf("Failed to update peer state: %v", err)
+		}
+	}
+
+	switch {
+	case len(oldPeers) == 0 && len(newPeers) > 0:
+		w.connectEvent(nil)
+	case len(oldPeers) > 0 && len(newPeers) == 0:
+		w.disconnectEvent(nil, rsn)
+	}
+}
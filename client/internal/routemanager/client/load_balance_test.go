@@ -0,0 +1,237 @@
+package client
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/routemanager/common"
+	"github.com/netbirdio/netbird/client/internal/routemanager/refcounter"
+	"github.com/netbirdio/netbird/client/internal/routemanager/static"
+	"github.com/netbirdio/netbird/route"
+)
+
+func TestSplitPrefix(t *testing.T) {
+	testCases := []struct {
+		name          string
+		prefix        netip.Prefix
+		expectedCount int
+		expectedFirst netip.Prefix
+		expectedLast  netip.Prefix
+	}{
+		{
+			name:          "ipv4 network",
+			prefix:        netip.MustParsePrefix("10.0.0.0/16"),
+			expectedCount: 64,
+			expectedFirst: netip.MustParsePrefix("10.0.0.0/22"),
+			expectedLast:  netip.MustParsePrefix("10.0.252.0/22"),
+		},
+		{
+			name:          "default route",
+			prefix:        netip.MustParsePrefix("0.0.0.0/0"),
+			expectedCount: 64,
+			expectedFirst: netip.MustParsePrefix("0.0.0.0/6"),
+			expectedLast:  netip.MustParsePrefix("252.0.0.0/6"),
+		},
+		{
+			name:          "small network",
+			prefix:        netip.MustParsePrefix("192.168.1.0/30"),
+			expectedCount: 4,
+			expectedFirst: netip.MustParsePrefix("192.168.1.0/32"),
+			expectedLast:  netip.MustParsePrefix("192.168.1.3/32"),
+		},
+		{
+			name:          "single host",
+			prefix:        netip.MustParsePrefix("192.168.1.1/32"),
+			expectedCount: 1,
+			expectedFirst: netip.MustParsePrefix("192.168.1.1/32"),
+			expectedLast:  netip.MustParsePrefix("192.168.1.1/32"),
+		},
+		{
+			name:          "ipv6 network",
+			prefix:        netip.MustParsePrefix("2001:db8::/32"),
+			expectedCount: 64,
+			expectedFirst: netip.MustParsePrefix("2001:db8::/38"),
+			expectedLast:  netip.MustParsePrefix("2001:db8:fc00::/38"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buckets := splitPrefix(tc.prefix)
+			require.Len(t, buckets, tc.expectedCount)
+			assert.Equal(t, tc.expectedFirst, buckets[0])
+			assert.Equal(t, tc.expectedLast, buckets[len(buckets)-1])
+			for _, bucket := range buckets {
+				assert.True(t, tc.prefix.Contains(bucket.Addr()), "bucket %s should be part of %s", bucket, tc.prefix)
+			}
+		})
+	}
+}
+
+func TestGetBalancedRoutesFromStatuses(t *testing.T) {
+	w := &Watcher{
+		routes: map[route.ID]*route.Route{
+			"route1": {ID: "route1", Peer: "peer1", Metric: 100},
+			"route2": {ID: "route2", Peer: "peer2", Metric: 100},
+			"route3": {ID: "route3", Peer: "peer3", Metric: 200},
+			"route4": {ID: "route4", Peer: "peer4", Metric: 100},
+			"route5": {ID: "route5", Peer: "peer5", Metric: 50, Unhealthy: true},
+		},
+	}
+
+	statuses := map[route.ID]routerPeerStatus{
+		"route1": {status: peer.StatusConnected},
+		"route2": {status: peer.StatusIdle},
+		"route3": {status: peer.StatusConnected},
+		"route4": {status: peer.StatusConnecting},
+		"route5": {status: peer.StatusConnected},
+	}
+
+	balanced := w.getBalancedRoutesFromStatuses(statuses)
+	assert.Equal(t, []*route.Route{w.routes["route1"], w.routes["route2"]}, balanced,
+		"healthy available routes with the lowest metric should share the traffic")
+
+	delete(statuses, "route1")
+	delete(statuses, "route2")
+	balanced = w.getBalancedRoutesFromStatuses(statuses)
+	assert.Equal(t, []*route.Route{w.routes["route3"]}, balanced, "routes with a higher metric should take over")
+
+	delete(statuses, "route3")
+	balanced = w.getBalancedRoutesFromStatuses(statuses)
+	assert.Equal(t, []*route.Route{w.routes["route5"]}, balanced, "unhealthy routes should only be a fallback")
+}
+
+func TestRecalculateBalancedRoutes(t *testing.T) {
+	network := netip.MustParsePrefix("10.0.0.0/16")
+	probes := splitPrefix(network)
+
+	// allowedIPs behaves like WireGuard: adding an allowed IP to a peer moves it from its previous peer
+	allowedIPs := make(map[netip.Prefix]string)
+	var checkGaps bool
+	var gaps []netip.Prefix
+	allowedIPsRefCounter := refcounter.New(
+		func(prefix netip.Prefix, peerKey string) (string, error) {
+			allowedIPs[prefix] = peerKey
+			return peerKey, nil
+		},
+		func(prefix netip.Prefix, peerKey string) error {
+			if allowedIPs[prefix] == peerKey {
+				delete(allowedIPs, prefix)
+			}
+			if !checkGaps {
+				return nil
+			}
+			for _, probe := range probes {
+				covered := false
+				for allowedIP := range allowedIPs {
+					covered = covered || allowedIP.Contains(probe.Addr())
+				}
+				if !covered {
+					gaps = append(gaps, probe)
+				}
+			}
+			return nil
+		},
+	)
+	routes := map[route.ID]*route.Route{
+		"route1": {ID: "route1", Peer: "peer1", Network: network, Metric: 100, LoadBalance: true},
+		"route2": {ID: "route2", Peer: "peer2", Network: network, Metric: 100, LoadBalance: true},
+		"route3": {ID: "route3", Peer: "peer3", Network: network, Metric: 100, LoadBalance: true},
+	}
+
+	statusRecorder := peer.NewRecorder("test-mgm")
+	for _, r := range routes {
+		require.NoError(t, statusRecorder.AddPeer(r.Peer, r.Peer+".netbird.cloud", ""))
+	}
+
+	w := NewWatcher(WatcherConfig{
+		Context:        context.Background(),
+		StatusRecorder: statusRecorder,
+		Handler: static.NewRoute(common.HandlerParams{
+			Route:                routes["route1"],
+			AllowedIPsRefCounter: allowedIPsRefCounter,
+		}),
+	})
+	w.routes = routes
+	require.True(t, w.isLoadBalanced())
+
+	statuses := map[route.ID]routerPeerStatus{
+		"route1": {status: peer.StatusConnected},
+		"route2": {status: peer.StatusConnected},
+		"route3": {status: peer.StatusConnected},
+	}
+	require.NoError(t, w.recalculateRoutes(reasonRouteUpdate, statuses))
+	require.Len(t, allowedIPs, 64)
+
+	perPeer := make(map[string]int)
+	for _, peerKey := range allowedIPs {
+		perPeer[peerKey]++
+	}
+	for _, peerKey := range []string{"peer1", "peer2", "peer3"} {
+		assert.Greater(t, perPeer[peerKey], 10, "buckets should be spread across all peers: %v", perPeer)
+	}
+
+	before := make(map[netip.Prefix]string, len(allowedIPs))
+	for bucket, peerKey := range allowedIPs {
+		before[bucket] = peerKey
+	}
+	checkGaps = true
+
+	require.NoError(t, w.recalculateRoutes(reasonPeerUpdate, statuses))
+	assert.Equal(t, before, allowedIPs, "buckets should stick to their peers")
+
+	delete(statuses, "route2")
+	require.NoError(t, w.recalculateRoutes(reasonPeerUpdate, statuses))
+	require.Len(t, allowedIPs, 64)
+	for bucket, peerKey := range allowedIPs {
+		assert.NotEqual(t, "peer2", peerKey)
+		if before[bucket] != "peer2" {
+			assert.Equal(t, before[bucket], peerKey, "buckets of available peers shouldn't move")
+		}
+	}
+
+	statuses["route2"] = routerPeerStatus{status: peer.StatusConnected}
+	require.NoError(t, w.recalculateRoutes(reasonPeerUpdate, statuses))
+	assert.Equal(t, before, allowedIPs, "buckets should return to a recovered peer")
+
+	for _, r := range routes {
+		r.LoadBalance = false
+	}
+	require.NoError(t, w.recalculateRoutes(reasonRouteUpdate, statuses))
+	require.Len(t, allowedIPs, 1, "the network should be routed through a single peer")
+	assert.Contains(t, allowedIPs, network)
+	assert.Empty(t, w.balancedRoutes)
+
+	for _, r := range routes {
+		r.LoadBalance = true
+	}
+	require.NoError(t, w.recalculateRoutes(reasonRouteUpdate, statuses))
+	assert.Equal(t, before, allowedIPs)
+	assert.Nil(t, w.currentChosen)
+	assert.Empty(t, gaps, "the new allowed IPs should be added before the old ones are removed")
+
+	checkGaps = false
+	w.Stop()
+	assert.Empty(t, allowedIPs)
+}
+
+func TestIsLoadBalanced_SingleAddress(t *testing.T) {
+	host := netip.MustParsePrefix("10.0.0.1/32")
+	routes := map[route.ID]*route.Route{
+		"route1": {ID: "route1", Peer: "peer1", Network: host, LoadBalance: true},
+		"route2": {ID: "route2", Peer: "peer2", Network: host, LoadBalance: true},
+	}
+
+	w := NewWatcher(WatcherConfig{
+		Context:        context.Background(),
+		StatusRecorder: peer.NewRecorder("test-mgm"),
+		Handler:        static.NewRoute(common.HandlerParams{Route: routes["route1"]}),
+	})
+	w.routes = routes
+	assert.False(t, w.isLoadBalanced(), "a single address can't be split across routing peers")
+}
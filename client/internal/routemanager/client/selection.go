@@ -0,0 +1,205 @@
+package client
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/route"
+)
+
+// maxRouteSwitches is the number of routing peer switches kept per network
+const maxRouteSwitches = 10
+
+// Candidate is a routing peer of a network with the inputs and the result of its score
+type Candidate struct {
+	RouteID route.ID
+	Peer    string
+	Metric  int
+	// Available is false if the routing peer isn't considered, as it's unknown or still connecting
+	Available bool
+	Status    peer.ConnStatus
+	Relayed   bool
+	Latency   time.Duration
+	Healthy   bool
+	// Score is the score of the routing peer as computed by getBestRouteFromStatuses, the highest score wins
+	Score float64
+}
+
+// RouteSwitch is a change of the routing peer of a network
+type RouteSwitch struct {
+	Time time.Time
+	// From and To are the keys of the routing peers, empty if the network had or has no routing peer
+	From   string
+	To     string
+	Reason string
+}
+
+// Selection explains the choice of the routing peer of a network
+type Selection struct {
+	NetID   route.NetID
+	Network string
+	// Chosen is the route of the chosen routing peer, empty if no routing peer is available or the network is load-balanced
+	Chosen       route.ID
+	LoadBalanced bool
+	// Balanced are the routes sharing the traffic of a load-balanced network
+	Balanced   []route.ID
+	Candidates []Candidate
+	// Switches are the last routing peer switches of the network, oldest first
+	Switches []RouteSwitch
+}
+
+// Selection returns the last routing peer selection of the network. Thread-safe.
+func (w *Watcher) Selection() Selection {
+	w.selectionMu.Lock()
+	defer w.selectionMu.Unlock()
+
+	selection := w.selection
+	selection.Network = w.handler.String()
+	selection.Balanced = slices.Clone(selection.Balanced)
+	selection.Candidates = slices.Clone(selection.Candidates)
+	selection.Switches = slices.Clone(selection.Switches)
+	return selection
+}
+
+// candidatesFromStatuses returns the routing peers of the network with their scores, sorted by score
+func (w *Watcher) candidatesFromStatuses(routePeerStatuses map[route.ID]routerPeerStatus) []Candidate {
+	candidates := make([]Candidate, 0, len(w.routes))
+	for _, r := range w.routes {
+		candidate := Candidate{
+			RouteID: r.ID,
+			Peer:    r.Peer,
+			Metric:  r.Metric,
+			Healthy: !r.Unhealthy,
+		}
+		if peerStatus, found := routePeerStatuses[r.ID]; found {
+			candidate.Status = peerStatus.status
+			candidate.Relayed = peerStatus.relayed
+			candidate.Latency = peerStatus.latency
+			if peerStatus.status != peer.StatusConnecting {
+				candidate.Available = true
+				candidate.Score = w.routeScore(r, peerStatus)
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	slices.SortFunc(candidates, func(a, b Candidate) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.RouteID, b.RouteID)
+	})
+	return candidates
+}
+
+// updateSelection stores the candidates and the chosen route of the network and records a switch if the routing peer
+// changed
+func (w *Watcher) updateSelection(rsn reason, candidates []Candidate, chosen route.ID) {
+	w.selectionMu.Lock()
+	defer w.selectionMu.Unlock()
+
+	for _, r := range w.routes {
+		w.selection.NetID = r.NetID
+		break
+	}
+	w.selection.LoadBalanced = false
+	w.selection.Balanced = nil
+
+	previous := w.selection.Chosen
+	previousCandidates := w.selection.Candidates
+	w.selection.Chosen = chosen
+	w.selection.Candidates = candidates
+
+	if previous == chosen {
+		return
+	}
+
+	var from, to string
+	if c, ok := findCandidate(previousCandidates, previous); ok {
+		from = c.Peer
+	}
+	if c, ok := findCandidate(candidates, chosen); ok {
+		to = c.Peer
+	}
+	if from == to {
+		return
+	}
+
+	w.selection.Switches = append(w.selection.Switches, RouteSwitch{
+		Time:   time.Now(),
+		From:   from,
+		To:     to,
+		Reason: switchReason(rsn, candidates, previous, chosen),
+	})
+	if len(w.selection.Switches) > maxRouteSwitches {
+		w.selection.Switches = slices.Delete(w.selection.Switches, 0, len(w.selection.Switches)-maxRouteSwitches)
+	}
+}
+
+// updateBalancedSelection stores the candidates and the routes sharing the traffic of a load-balanced network
+func (w *Watcher) updateBalancedSelection(candidates []Candidate, balanced []*route.Route) {
+	w.selectionMu.Lock()
+	defer w.selectionMu.Unlock()
+
+	for _, r := range w.routes {
+		w.selection.NetID = r.NetID
+		break
+	}
+	w.selection.LoadBalanced = true
+	w.selection.Chosen = ""
+	w.selection.Candidates = candidates
+	w.selection.Balanced = make([]route.ID, 0, len(balanced))
+	for _, r := range balanced {
+		w.selection.Balanced = append(w.selection.Balanced, r.ID)
+	}
+}
+
+// switchReason describes why the routing peer of the network changed from the previous to the chosen route
+func switchReason(rsn reason, candidates []Candidate, previous, chosen route.ID) string {
+	trigger := "unknown"
+	switch rsn {
+	case reasonRouteUpdate:
+		trigger = "route update"
+	case reasonPeerUpdate:
+		trigger = "peer status change"
+	case reasonShutdown:
+		trigger = "shutdown"
+	case reasonHA:
+		trigger = "high availability change"
+	}
+
+	if previous == "" {
+		return fmt.Sprintf("%s: routing peer selected", trigger)
+	}
+	if chosen == "" {
+		return fmt.Sprintf("%s: no routing peer available", trigger)
+	}
+
+	prev, found := findCandidate(candidates, previous)
+	next, _ := findCandidate(candidates, chosen)
+	switch {
+	case !found:
+		return fmt.Sprintf("%s: previous route removed", trigger)
+	case !prev.Available:
+		return fmt.Sprintf("%s: previous routing peer unavailable", trigger)
+	case !prev.Healthy && next.Healthy:
+		return fmt.Sprintf("%s: previous route unhealthy", trigger)
+	default:
+		return fmt.Sprintf("%s: better score %.3f over %.3f", trigger, next.Score, prev.Score)
+	}
+}
+
+func findCandidate(candidates []Candidate, id route.ID) (Candidate, bool) {
+	if id == "" {
+		return Candidate{}, false
+	}
+	for _, c := range candidates {
+		if c.RouteID == id {
+			return c, true
+		}
+	}
+	return Candidate{}, false
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/routemanager/common"
+	"github.com/netbirdio/netbird/client/internal/routemanager/static"
+	"github.com/netbirdio/netbird/route"
+)
+
+func newSelectionWatcher() *Watcher {
+	network := netip.MustParsePrefix("192.168.0.0/24")
+	return &Watcher{
+		handler: static.NewRoute(common.HandlerParams{Route: &route.Route{Network: network}}),
+		routes: map[route.ID]*route.Route{
+			"route1": {ID: "route1", NetID: "office", Network: network, Peer: "peer1", Metric: route.MaxMetric},
+			"route2": {ID: "route2", NetID: "office", Network: network, Peer: "peer2", Metric: route.MaxMetric},
+			"route3": {ID: "route3", NetID: "office", Network: network, Peer: "peer3", Metric: route.MaxMetric},
+		},
+	}
+}
+
+func TestWatcher_CandidatesFromStatuses(t *testing.T) {
+	w := newSelectionWatcher()
+	w.routes["route2"].Unhealthy = true
+
+	candidates := w.candidatesFromStatuses(map[route.ID]routerPeerStatus{
+		"route1": {status: peer.StatusConnected, latency: 15 * time.Millisecond},
+		"route2": {status: peer.StatusConnected, latency: 10 * time.Millisecond},
+		"route3": {status: peer.StatusConnecting},
+	})
+
+	require.Len(t, candidates, 3)
+	assert.Equal(t, []route.ID{"route1", "route2", "route3"}, []route.ID{candidates[0].RouteID, candidates[1].RouteID, candidates[2].RouteID}, "candidates are sorted by score")
+	assert.InDelta(t, 1_100_001.985, candidates[0].Score, 0.0001)
+	assert.False(t, candidates[1].Healthy)
+	assert.InDelta(t, 100_001.99, candidates[1].Score, 0.0001)
+	assert.False(t, candidates[2].Available, "connecting peers aren't considered")
+	assert.Zero(t, candidates[2].Score)
+
+	chosen, _ := w.getBestRouteFromStatuses(map[route.ID]routerPeerStatus{
+		"route1": {status: peer.StatusConnected, latency: 15 * time.Millisecond},
+		"route2": {status: peer.StatusConnected, latency: 10 * time.Millisecond},
+	})
+	assert.Equal(t, candidates[0].RouteID, chosen, "the best candidate is the chosen route")
+}
+
+func TestWatcher_UpdateSelection(t *testing.T) {
+	w := newSelectionWatcher()
+
+	statuses := map[route.ID]routerPeerStatus{
+		"route1": {status: peer.StatusConnected, latency: 10 * time.Millisecond},
+		"route2": {status: peer.StatusConnected, latency: 50 * time.Millisecond},
+	}
+	w.updateSelection(reasonRouteUpdate, w.candidatesFromStatuses(statuses), "route1")
+	w.updateSelection(reasonPeerUpdate, w.candidatesFromStatuses(statuses), "route1")
+
+	selection := w.Selection()
+	assert.Equal(t, route.NetID("office"), selection.NetID)
+	assert.Equal(t, "192.168.0.0/24", selection.Network)
+	assert.Equal(t, route.ID("route1"), selection.Chosen)
+	require.Len(t, selection.Switches, 1, "keeping the routing peer isn't a switch")
+	assert.Equal(t, RouteSwitch{Time: selection.Switches[0].Time, To: "peer1", Reason: "route update: routing peer selected"}, selection.Switches[0])
+
+	statuses["route1"] = routerPeerStatus{status: peer.StatusConnecting}
+	w.updateSelection(reasonPeerUpdate, w.candidatesFromStatuses(statuses), "route2")
+
+	statuses["route1"] = routerPeerStatus{status: peer.StatusConnected, latency: 10 * time.Millisecond}
+	w.updateSelection(reasonPeerUpdate, w.candidatesFromStatuses(statuses), "route1")
+
+	selection = w.Selection()
+	require.Len(t, selection.Switches, 3)
+	assert.Equal(t, "peer1", selection.Switches[1].From)
+	assert.Equal(t, "peer2", selection.Switches[1].To)
+	assert.Equal(t, "peer status change: previous routing peer unavailable", selection.Switches[1].Reason)
+	assert.Equal(t, "peer status change: better score 1100001.990 over 1100001.950", selection.Switches[2].Reason)
+
+	w.updateSelection(reasonPeerUpdate, w.candidatesFromStatuses(nil), "")
+	selection = w.Selection()
+	assert.Empty(t, selection.Chosen)
+	assert.Equal(t, "peer status change: no routing peer available", selection.Switches[3].Reason)
+}
+
+func TestWatcher_UpdateSelectionKeepsLastSwitches(t *testing.T) {
+	w := newSelectionWatcher()
+	statuses := map[route.ID]routerPeerStatus{
+		"route1": {status: peer.StatusConnected},
+		"route2": {status: peer.StatusConnected},
+	}
+
+	for i := 0; i < maxRouteSwitches+5; i++ {
+		chosen := route.ID("route1")
+		if i%2 == 1 {
+			chosen = "route2"
+		}
+		w.updateSelection(reasonPeerUpdate, w.candidatesFromStatuses(statuses), chosen)
+	}
+
+	selection := w.Selection()
+	require.Len(t, selection.Switches, maxRouteSwitches)
+	assert.Equal(t, "peer2", selection.Switches[maxRouteSwitches-1].From, "the last switch is kept")
+	assert.Equal(t, "peer1", selection.Switches[maxRouteSwitches-1].To)
+}
@@ -27,4 +27,7 @@ type HandlerParams struct {
 	Firewall             manager.Manager
 	FakeIPManager        *fakeip.Manager
 	ForwarderPort        *atomic.Uint32
+	// ResolveLocally makes domain routes track the answers of the local upstreams instead of resolving the domains
+	// on the routing peer, used by split tunnels of exit nodes
+	ResolveLocally bool
 }
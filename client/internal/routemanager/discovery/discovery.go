@@ -0,0 +1,73 @@
+// Package discovery finds the subnets reachable through the local routing table of a routing peer.
+package discovery
+
+import (
+	"net/netip"
+	"slices"
+
+	"github.com/netbirdio/netbird/client/internal/routemanager/systemops"
+)
+
+// mainTable is the name of the main routing table on all platforms
+const mainTable = "main"
+
+// Discover returns the subnets of the main routing table that are part of the allowlist.
+// Routes through the interface with the given name are skipped, so routes added by the client are not reported back.
+func Discover(allowlist []netip.Prefix, excludeIface string) ([]netip.Prefix, error) {
+	if len(allowlist) == 0 {
+		return nil, nil
+	}
+
+	routes, err := getRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	return filterRoutes(routes, allowlist, excludeIface), nil
+}
+
+// filterRoutes returns the sorted and deduplicated destinations of the routes that are part of the allowlist
+func filterRoutes(routes []systemops.DetailedRoute, allowlist []netip.Prefix, excludeIface string) []netip.Prefix {
+	var subnets []netip.Prefix
+	for _, r := range routes {
+		if r.Table != mainTable || !isCandidate(r.Dst) {
+			continue
+		}
+		if r.Interface != nil && r.Interface.Name == excludeIface {
+			continue
+		}
+
+		dst := r.Dst.Masked()
+		if !isAllowed(dst, allowlist) || slices.Contains(subnets, dst) {
+			continue
+		}
+		subnets = append(subnets, dst)
+	}
+
+	slices.SortFunc(subnets, func(a, b netip.Prefix) int {
+		if c := a.Addr().Compare(b.Addr()); c != 0 {
+			return c
+		}
+		return a.Bits() - b.Bits()
+	})
+	return subnets
+}
+
+// isCandidate filters default, loopback, link-local and multicast destinations
+func isCandidate(dst netip.Prefix) bool {
+	if !dst.IsValid() || dst.Bits() == 0 {
+		return false
+	}
+	addr := dst.Addr()
+	return !addr.IsLoopback() && !addr.IsLinkLocalUnicast() && !addr.IsMulticast()
+}
+
+// isAllowed returns true if the subnet is fully contained in one of the allowlist prefixes
+func isAllowed(subnet netip.Prefix, allowlist []netip.Prefix) bool {
+	for _, allowed := range allowlist {
+		if allowed.Bits() <= subnet.Bits() && allowed.Contains(subnet.Addr()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package discovery
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/client/internal/routemanager/systemops"
+)
+
+func TestFilterRoutes(t *testing.T) {
+	lan := &net.Interface{Index: 2, Name: "eth0"}
+	wg := &net.Interface{Index: 3, Name: "wt0"}
+
+	route := func(dst, table string, intf *net.Interface) systemops.DetailedRoute {
+		return systemops.DetailedRoute{
+			Route: systemops.Route{Dst: netip.MustParsePrefix(dst), Interface: intf},
+			Table: table,
+		}
+	}
+
+	routes := []systemops.DetailedRoute{
+		route("0.0.0.0/0", "main", lan),
+		route("192.168.1.0/24", "main", lan),
+		route("10.10.5.7/16", "main", lan),
+		route("10.10.0.0/16", "main", lan),
+		route("127.0.0.0/8", "main", lan),
+		route("169.254.0.0/16", "main", lan),
+		route("224.0.0.0/4", "main", lan),
+		route("172.16.0.0/12", "local", lan),
+		route("172.20.0.0/16", "main", wg),
+		route("8.8.8.0/24", "main", lan),
+		route("10.0.0.0/8", "main", lan),
+	}
+	allowlist := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("172.16.0.0/12"),
+		netip.MustParsePrefix("192.168.0.0/16"),
+	}
+
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("10.10.0.0/16"),
+		netip.MustParsePrefix("192.168.1.0/24"),
+	}, filterRoutes(routes, allowlist, wg.Name))
+
+	assert.Empty(t, filterRoutes(routes, nil, wg.Name), "nothing should be reported without an allowlist")
+}
@@ -0,0 +1,10 @@
+//go:build ios || android
+
+package discovery
+
+import "github.com/netbirdio/netbird/client/internal/routemanager/systemops"
+
+// getRoutes returns no routes, mobile peers don't act as routing peers
+func getRoutes() ([]systemops.DetailedRoute, error) {
+	return nil, nil
+}
@@ -0,0 +1,9 @@
+//go:build !ios && !android
+
+package discovery
+
+import "github.com/netbirdio/netbird/client/internal/routemanager/systemops"
+
+func getRoutes() ([]systemops.DetailedRoute, error) {
+	return systemops.GetDetailedRoutesFromTable()
+}
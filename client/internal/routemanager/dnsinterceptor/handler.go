@@ -52,6 +52,7 @@ type DnsInterceptor struct {
 	firewall             firewall.Manager
 	fakeIPManager        *fakeip.Manager
 	forwarderPort        *atomic.Uint32
+	resolveLocally       bool
 }
 
 func New(params common.HandlerParams) *DnsInterceptor {
@@ -67,6 +68,7 @@ func New(params common.HandlerParams) *DnsInterceptor {
 		fakeIPManager:        params.FakeIPManager,
 		interceptedDomains:   make(domainMap),
 		forwarderPort:        params.ForwarderPort,
+		resolveLocally:       params.ResolveLocally,
 	}
 }
 
@@ -75,10 +77,19 @@ func (d *DnsInterceptor) String() string {
 }
 
 func (d *DnsInterceptor) AddRoute(context.Context) error {
-	d.dnsServer.RegisterHandler(d.route.Domains, d, nbdns.PriorityDNSRoute)
+	d.dnsServer.RegisterHandler(d.route.Domains, d, d.priority())
 	return nil
 }
 
+// priority returns the handler priority, interceptors resolving locally run before the DNS routes so that they see
+// the answers of DNS routes for the same domains as well
+func (d *DnsInterceptor) priority() int {
+	if d.resolveLocally {
+		return nbdns.PrioritySplitTunnel
+	}
+	return nbdns.PriorityDNSRoute
+}
+
 func (d *DnsInterceptor) RemoveRoute() error {
 	d.mu.Lock()
 
@@ -110,7 +121,7 @@ func (d *DnsInterceptor) RemoveRoute() error {
 	clear(d.interceptedDomains)
 	d.mu.Unlock()
 
-	d.dnsServer.DeregisterHandler(d.route.Domains, nbdns.PriorityDNSRoute)
+	d.dnsServer.DeregisterHandler(d.route.Domains, d.priority())
 
 	return nberrors.FormatErrorOrNil(merr)
 }
@@ -230,6 +241,11 @@ func (d *DnsInterceptor) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		return
 	}
 
+	if d.resolveLocally {
+		d.observeResponse(w, r, logger)
+		return
+	}
+
 	d.mu.RLock()
 	peerKey := d.currentPeerKey
 	d.mu.RUnlock()
@@ -266,6 +282,40 @@ func (d *DnsInterceptor) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 	}
 }
 
+// observeResponse passes the query to the next handlers of the chain and tracks the IPs of their answer
+// before it is returned to the client
+func (d *DnsInterceptor) observeResponse(w dns.ResponseWriter, r *dns.Msg, logger *log.Entry) {
+	writer, ok := w.(*nbdns.ResponseWriterChain)
+	if !ok {
+		d.writeDNSError(w, r, logger, "response writer doesn't support observing responses")
+		return
+	}
+
+	origPattern := domain.Domain(writer.GetOrigPattern())
+	writer.ObserveResponse(func(reply *dns.Msg) {
+		if len(reply.Answer) == 0 || len(reply.Question) == 0 {
+			return
+		}
+
+		resolvedDomain := domain.Domain(strings.ToLower(reply.Question[0].Name))
+		originalDomain := origPattern
+		if originalDomain == "" {
+			originalDomain = resolvedDomain
+		}
+
+		prefixes := answerPrefixes(reply, resolvedDomain, logger)
+		if len(prefixes) == 0 {
+			return
+		}
+		if err := d.updateDomainPrefixes(resolvedDomain, originalDomain, prefixes, logger); err != nil {
+			logger.Errorf("failed to update domain prefixes: %v", err)
+		}
+		d.replaceIPsInDNSResponse(reply, prefixes, logger)
+	})
+
+	d.continueToNextHandler(w, r, logger, "resolved by local upstream")
+}
+
 func (d *DnsInterceptor) writeDNSError(w dns.ResponseWriter, r *dns.Msg, logger *log.Entry, reason string) {
 	logger.Warnf("failed to query upstream for domain=%s: %s", r.Question[0].Name, reason)
 
@@ -314,38 +364,16 @@ func (d *DnsInterceptor) writeMsg(w dns.ResponseWriter, r *dns.Msg, logger *log.
 
 		resolvedDomain := domain.Domain(strings.ToLower(r.Question[0].Name))
 
+		// the routing peer resolves real IPs, clients address them through the virtual prefix
+		d.applyNetMap(r, logger)
+
 		// already punycode via RegisterHandler()
 		originalDomain := domain.Domain(origPattern)
 		if originalDomain == "" {
 			originalDomain = resolvedDomain
 		}
 
-		var newPrefixes []netip.Prefix
-		for _, answer := range r.Answer {
-			var ip netip.Addr
-			switch rr := answer.(type) {
-			case *dns.A:
-				addr, ok := netip.AddrFromSlice(rr.A)
-				if !ok {
-					logger.Tracef("failed to convert A record for domain=%s ip=%v", resolvedDomain, rr.A)
-					continue
-				}
-				ip = addr
-			case *dns.AAAA:
-				addr, ok := netip.AddrFromSlice(rr.AAAA)
-				if !ok {
-					logger.Tracef("failed to convert AAAA record for domain=%s ip=%v", resolvedDomain, rr.AAAA)
-					continue
-				}
-				ip = addr
-			default:
-				continue
-			}
-
-			prefix := netip.PrefixFrom(ip.Unmap(), ip.BitLen())
-			newPrefixes = append(newPrefixes, prefix)
-		}
-
+		newPrefixes := answerPrefixes(r, resolvedDomain, logger)
 		if len(newPrefixes) > 0 {
 			if err := d.updateDomainPrefixes(resolvedDomain, originalDomain, newPrefixes, logger); err != nil {
 				logger.Errorf("failed to update domain prefixes: %v", err)
@@ -362,6 +390,35 @@ func (d *DnsInterceptor) writeMsg(w dns.ResponseWriter, r *dns.Msg, logger *log.
 	return nil
 }
 
+// answerPrefixes returns the host prefixes of the A and AAAA records of the answer
+func answerPrefixes(r *dns.Msg, resolvedDomain domain.Domain, logger *log.Entry) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, answer := range r.Answer {
+		var ip netip.Addr
+		switch rr := answer.(type) {
+		case *dns.A:
+			addr, ok := netip.AddrFromSlice(rr.A)
+			if !ok {
+				logger.Tracef("failed to convert A record for domain=%s ip=%v", resolvedDomain, rr.A)
+				continue
+			}
+			ip = addr
+		case *dns.AAAA:
+			addr, ok := netip.AddrFromSlice(rr.AAAA)
+			if !ok {
+				logger.Tracef("failed to convert AAAA record for domain=%s ip=%v", resolvedDomain, rr.AAAA)
+				continue
+			}
+			ip = addr
+		default:
+			continue
+		}
+
+		prefixes = append(prefixes, netip.PrefixFrom(ip.Unmap(), ip.BitLen()))
+	}
+	return prefixes
+}
+
 // logPrefixChanges handles the logging for prefix changes
 func (d *DnsInterceptor) logPrefixChanges(resolvedDomain, originalDomain domain.Domain, toAdd, toRemove []netip.Prefix, logger *log.Entry) {
 	if len(toAdd) > 0 {
@@ -543,6 +600,32 @@ func (d *DnsInterceptor) replaceIPsInDNSResponse(reply *dns.Msg, realPrefixes []
 	}
 }
 
+// applyNetMap replaces IPs of the real prefix with the IPs of the virtual prefix of the route netmap in the DNS response
+func (d *DnsInterceptor) applyNetMap(reply *dns.Msg, logger *log.Entry) {
+	netMap := d.route.NetMap
+	if netMap == nil {
+		return
+	}
+
+	// netmaps only support IPv4
+	for _, answer := range reply.Answer {
+		rr, ok := answer.(*dns.A)
+		if !ok {
+			continue
+		}
+
+		realIP, ok := netip.AddrFromSlice(rr.A)
+		if !ok {
+			continue
+		}
+
+		if virtualIP, ok := netMap.ToVirtual(realIP); ok {
+			rr.A = virtualIP.AsSlice()
+			logger.Tracef("replaced real IP %s with virtual IP %s in DNS response", realIP, virtualIP)
+		}
+	}
+}
+
 func determinePrefixChanges(oldPrefixes, newPrefixes []netip.Prefix) (toAdd, toRemove []netip.Prefix) {
 	prefixSet := make(map[netip.Prefix]bool)
 	for _, prefix := range oldPrefixes {
@@ -0,0 +1,187 @@
+package healthcheck
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/route"
+)
+
+const (
+	// unhealthyThreshold is the number of consecutive failed probes after which a route is reported unhealthy
+	unhealthyThreshold = 3
+	// healthyThreshold is the number of consecutive successful probes after which an unhealthy route is reported healthy again
+	healthyThreshold = 2
+)
+
+// Monitor runs the health checks of the routes served by this peer and reports the unhealthy ones
+type Monitor struct {
+	ctx       context.Context
+	mu        sync.Mutex
+	checks    map[route.ID]*check
+	unhealthy map[route.ID]struct{}
+	changed   chan struct{}
+	onChange  func(unhealthy []route.ID)
+	probe     func(ctx context.Context, hc route.HealthCheck) error
+}
+
+type check struct {
+	config route.HealthCheck
+	cancel context.CancelFunc
+}
+
+// NewMonitor returns a monitor that calls onChange with the unhealthy route IDs every time the set changes.
+// The monitor stops when the context is canceled.
+func NewMonitor(ctx context.Context, onChange func(unhealthy []route.ID)) *Monitor {
+	m := &Monitor{
+		ctx:       ctx,
+		checks:    make(map[route.ID]*check),
+		unhealthy: make(map[route.ID]struct{}),
+		changed:   make(chan struct{}, 1),
+		onChange:  onChange,
+		probe:     probe,
+	}
+	go m.notifyLoop()
+	return m
+}
+
+// Update starts the checks of new routes, restarts the checks that changed and stops the checks of removed routes
+func (m *Monitor) Update(routes map[route.ID]*route.Route) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	changed := false
+	for id, c := range m.checks {
+		if r, ok := routes[id]; ok && r.HealthCheck != nil && *r.HealthCheck == c.config {
+			continue
+		}
+		c.cancel()
+		delete(m.checks, id)
+		if _, ok := m.unhealthy[id]; ok {
+			delete(m.unhealthy, id)
+			changed = true
+		}
+	}
+
+	for id, r := range routes {
+		if r.HealthCheck == nil {
+			continue
+		}
+		if _, ok := m.checks[id]; ok {
+			continue
+		}
+		ctx, cancel := context.WithCancel(m.ctx)
+		c := &check{config: *r.HealthCheck, cancel: cancel}
+		m.checks[id] = c
+		go m.run(ctx, id, c)
+	}
+
+	if changed {
+		m.notify()
+	}
+}
+
+// Unhealthy returns the sorted IDs of the routes whose check currently fails
+func (m *Monitor) Unhealthy() []route.ID {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]route.ID, 0, len(m.unhealthy))
+	for id := range m.unhealthy {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Stop stops all checks
+func (m *Monitor) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, c := range m.checks {
+		c.cancel()
+		delete(m.checks, id)
+	}
+}
+
+func (m *Monitor) run(ctx context.Context, id route.ID, c *check) {
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	healthy := true
+	var failures, successes int
+	for {
+		err := m.probe(ctx, c.config)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			successes = 0
+			failures++
+			log.Debugf("health check %s %s of route %s failed: %v", c.config.Type, c.config.Target, id, err)
+			if healthy && failures >= unhealthyThreshold {
+				log.Warnf("route %s is unhealthy, health check %s %s failed %d times: %v", id, c.config.Type, c.config.Target, failures, err)
+				healthy = false
+				m.setHealthy(id, c, false)
+			}
+		} else {
+			failures = 0
+			successes++
+			if !healthy && successes >= healthyThreshold {
+				log.Infof("route %s is healthy again", id)
+				healthy = true
+				m.setHealthy(id, c, true)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Monitor) setHealthy(id route.ID, c *check, healthy bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// the check may have been replaced or removed while probing
+	if m.checks[id] != c {
+		return
+	}
+
+	if healthy {
+		delete(m.unhealthy, id)
+	} else {
+		m.unhealthy[id] = struct{}{}
+	}
+	m.notify()
+}
+
+// notify signals the notify loop without blocking, pending signals are coalesced
+func (m *Monitor) notify() {
+	select {
+	case m.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Monitor) notifyLoop() {
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-m.changed:
+			if m.onChange != nil {
+				m.onChange(m.Unhealthy())
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/route"
+)
+
+func TestProbeTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+
+	hc := route.HealthCheck{Type: route.HealthCheckTCP, Target: addr, Timeout: time.Second}
+	assert.NoError(t, probe(context.Background(), hc))
+
+	require.NoError(t, listener.Close())
+	assert.Error(t, probe(context.Background(), hc))
+}
+
+func TestProbeHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		case "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		path    string
+		healthy bool
+	}{
+		{path: "/", healthy: true},
+		{path: "/redirect", healthy: true},
+		{path: "/broken", healthy: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			hc := route.HealthCheck{Type: route.HealthCheckHTTP, Target: server.URL + tt.path, Timeout: time.Second}
+			err := probe(context.Background(), hc)
+			if tt.healthy {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestMonitorReportsUnhealthyRoutes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan []route.ID, 10)
+	m := NewMonitor(ctx, func(unhealthy []route.ID) {
+		changes <- unhealthy
+	})
+
+	var failing atomic.Bool
+	failing.Store(true)
+	m.probe = func(context.Context, route.HealthCheck) error {
+		if failing.Load() {
+			return errors.New("unreachable")
+		}
+		return nil
+	}
+
+	hc := &route.HealthCheck{Type: route.HealthCheckTCP, Target: "10.0.0.1:80", Interval: 10 * time.Millisecond, Timeout: 10 * time.Millisecond}
+	m.Update(map[route.ID]*route.Route{
+		"route1": {ID: "route1", HealthCheck: hc},
+		"route2": {ID: "route2"},
+	})
+
+	assert.Equal(t, []route.ID{"route1"}, waitForChange(t, changes))
+	assert.Equal(t, []route.ID{"route1"}, m.Unhealthy())
+
+	failing.Store(false)
+	assert.Empty(t, waitForChange(t, changes))
+
+	failing.Store(true)
+	assert.Equal(t, []route.ID{"route1"}, waitForChange(t, changes))
+
+	// removing the check drops the route from the unhealthy set
+	m.Update(map[route.ID]*route.Route{"route1": {ID: "route1"}})
+	assert.Empty(t, waitForChange(t, changes))
+
+	m.Stop()
+	assert.Empty(t, m.Unhealthy())
+}
+
+func waitForChange(t *testing.T, changes <-chan []route.ID) []route.ID {
+	t.Helper()
+	select {
+	case unhealthy := <-changes:
+		return unhealthy
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for health change")
+		return nil
+	}
+}
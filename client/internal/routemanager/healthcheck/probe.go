@@ -0,0 +1,154 @@
+package healthcheck
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+
+	nbnet "github.com/netbirdio/netbird/client/net"
+	"github.com/netbirdio/netbird/route"
+)
+
+const (
+	protocolICMP   = 1
+	protocolICMPv6 = 58
+)
+
+// probe runs a single check against the target, a nil error means the target is reachable
+func probe(ctx context.Context, hc route.HealthCheck) error {
+	ctx, cancel := context.WithTimeout(ctx, hc.Timeout)
+	defer cancel()
+
+	switch hc.Type {
+	case route.HealthCheckTCP:
+		return probeTCP(ctx, hc.Target)
+	case route.HealthCheckHTTP:
+		return probeHTTP(ctx, hc.Target)
+	case route.HealthCheckICMP:
+		return probeICMP(ctx, hc.Target)
+	default:
+		return fmt.Errorf("unknown health check type %q", hc.Type)
+	}
+}
+
+func probeTCP(ctx context.Context, target string) error {
+	conn, err := nbnet.NewDialer().DialContext(ctx, "tcp", target)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	return conn.Close()
+}
+
+func probeHTTP(ctx context.Context, target string) error {
+	transport := &http.Transport{
+		DialContext:       nbnet.NewDialer().DialContext,
+		DisableKeepAlives: true,
+	}
+	defer transport.CloseIdleConnections()
+
+	client := &http.Client{
+		Transport: transport,
+		// a redirect already proves that the target answers
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// probeICMP sends an echo request to the target and waits for the matching reply
+func probeICMP(ctx context.Context, target string) error {
+	addr, err := netip.ParseAddr(target)
+	if err != nil {
+		return fmt.Errorf("parse target: %w", err)
+	}
+	addr = addr.Unmap()
+
+	network, listenAddr, protocol := "ip4:icmp", "0.0.0.0", protocolICMP
+	var requestType, replyType icmp.Type = ipv4.ICMPTypeEcho, ipv4.ICMPTypeEchoReply
+	if addr.Is6() {
+		network, listenAddr, protocol = "ip6:ipv6-icmp", "::", protocolICMPv6
+		requestType, replyType = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply
+	}
+
+	conn, err := nbnet.NewListener().ListenPacket(ctx, network, listenAddr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("set deadline: %w", err)
+		}
+	}
+
+	var idBytes [4]byte
+	if _, err := rand.Read(idBytes[:]); err != nil {
+		return fmt.Errorf("generate id: %w", err)
+	}
+	id := int(binary.BigEndian.Uint16(idBytes[:2]))
+	seq := int(binary.BigEndian.Uint16(idBytes[2:]))
+
+	request := icmp.Message{
+		Type: requestType,
+		Body: &icmp.Echo{ID: id, Seq: seq, Data: []byte("netbird-route-health")},
+	}
+	payload, err := request.Marshal(nil)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	dst := &net.IPAddr{IP: addr.AsSlice(), Zone: addr.Zone()}
+	if _, err := conn.WriteTo(payload, dst); err != nil {
+		return fmt.Errorf("send echo request: %w", err)
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			return fmt.Errorf("wait for echo reply: %w", err)
+		}
+
+		fromAddr, ok := from.(*net.IPAddr)
+		if !ok || !fromAddr.IP.Equal(dst.IP) {
+			continue
+		}
+
+		reply, err := icmp.ParseMessage(protocol, buf[:n])
+		if err != nil || reply.Type != replyType {
+			continue
+		}
+		echo, ok := reply.Body.(*icmp.Echo)
+		if !ok || echo.ID != id || echo.Seq != seq {
+			continue
+		}
+		return nil
+	}
+}
@@ -1,6 +1,7 @@
 package routemanager
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -26,9 +27,11 @@ import (
 	"github.com/netbirdio/netbird/client/internal/listener"
 	"github.com/netbirdio/netbird/client/internal/peer"
 	"github.com/netbirdio/netbird/client/internal/peerstore"
+	"github.com/netbirdio/netbird/client/internal/routemanager/bgp"
 	"github.com/netbirdio/netbird/client/internal/routemanager/client"
 	"github.com/netbirdio/netbird/client/internal/routemanager/common"
 	"github.com/netbirdio/netbird/client/internal/routemanager/fakeip"
+	"github.com/netbirdio/netbird/client/internal/routemanager/healthcheck"
 	"github.com/netbirdio/netbird/client/internal/routemanager/iface"
 	"github.com/netbirdio/netbird/client/internal/routemanager/notifier"
 	"github.com/netbirdio/netbird/client/internal/routemanager/refcounter"
@@ -53,6 +56,7 @@ type Manager interface {
 	GetRouteSelector() *routeselector.RouteSelector
 	GetClientRoutes() route.HAMap
 	GetClientRoutesWithNetID() map[route.NetID][]*route.Route
+	GetRouteSelections() []client.Selection
 	SetRouteChangeListener(listener listener.NetworkChangeListener)
 	InitialRouteRange() []string
 	SetFirewall(firewall.Manager) error
@@ -74,6 +78,8 @@ type ManagerConfig struct {
 	PeerStore           *peerstore.Store
 	DisableClientRoutes bool
 	DisableServerRoutes bool
+	// RouteHealthListener is called with the IDs of the served routes whose health check fails
+	RouteHealthListener func(unhealthy []route.ID)
 }
 
 // DefaultManager is the default instance of a route manager
@@ -106,6 +112,13 @@ type DefaultManager struct {
 	activeRoutes        map[route.HAUniqueID]client.RouteHandler
 	fakeIPManager       *fakeip.Manager
 	dnsForwarderPort    atomic.Uint32
+	healthMonitor       *healthcheck.Monitor
+	bgpSpeaker          *bgp.Speaker
+	// bypassRefCounter routes prefixes outside of the VPN, used by exit node split tunnels in exclude mode
+	bypassRefCounter    *refcounter.RouteRefCounter
+	splitTunnelBypasses map[route.NetID]*splitTunnelBypass
+	// splitTunnelRoutes holds the domain routes of exit node split tunnels in include mode, they are resolved locally
+	splitTunnelRoutes map[route.HAUniqueID]struct{}
 }
 
 func NewManager(config ManagerConfig) *DefaultManager {
@@ -134,6 +147,10 @@ func NewManager(config ManagerConfig) *DefaultManager {
 		disableClientRoutes: config.DisableClientRoutes,
 		disableServerRoutes: config.DisableServerRoutes,
 		activeRoutes:        make(map[route.HAUniqueID]client.RouteHandler),
+		healthMonitor:       healthcheck.NewMonitor(mCTX, config.RouteHealthListener),
+		bgpSpeaker:          bgp.NewSpeaker(mCTX),
+		splitTunnelBypasses: make(map[route.NetID]*splitTunnelBypass),
+		splitTunnelRoutes:   make(map[route.HAUniqueID]struct{}),
 	}
 	dm.dnsForwarderPort.Store(uint32(nbdns.ForwarderClientPort))
 
@@ -182,6 +199,15 @@ func (m *DefaultManager) setupRefCounters(useNoop bool) {
 		},
 	)
 
+	m.bypassRefCounter = refcounter.New(
+		func(prefix netip.Prefix, _ struct{}) (struct{}, error) {
+			return struct{}{}, m.sysOps.AddBypassRoute(prefix)
+		},
+		func(prefix netip.Prefix, _ struct{}) error {
+			return m.sysOps.RemoveBypassRoute(prefix)
+		},
+	)
+
 	if useNoop {
 		m.routeRefCounter = refcounter.New(
 			func(netip.Prefix, struct{}) (struct{}, error) {
@@ -191,6 +217,14 @@ func (m *DefaultManager) setupRefCounters(useNoop bool) {
 				return nil
 			},
 		)
+		m.bypassRefCounter = refcounter.New(
+			func(netip.Prefix, struct{}) (struct{}, error) {
+				return struct{}{}, refcounter.ErrIgnore
+			},
+			func(netip.Prefix, struct{}) error {
+				return nil
+			},
+		)
 	}
 
 	m.allowedIPsRefCounter = refcounter.New(
@@ -285,10 +319,21 @@ func (m *DefaultManager) SetDNSForwarderPort(port uint16) {
 func (m *DefaultManager) Stop(stateManager *statemanager.Manager) {
 	m.stop()
 	m.shutdownWg.Wait()
+	m.healthMonitor.Stop()
+	m.bgpSpeaker.Stop()
 	if m.serverRouter != nil {
 		m.serverRouter.CleanUp()
 	}
 
+	m.mux.Lock()
+	m.removeSplitTunnelBypasses()
+	m.mux.Unlock()
+	if m.bypassRefCounter != nil {
+		if err := m.bypassRefCounter.Flush(); err != nil {
+			log.Errorf("Error flushing bypass ref counter: %v", err)
+		}
+	}
+
 	if m.routeRefCounter != nil {
 		if err := m.routeRefCounter.Flush(); err != nil {
 			log.Errorf("Error flushing route ref counter: %v", err)
@@ -359,6 +404,9 @@ func (m *DefaultManager) updateSystemRoutes(newRoutes route.HAMap) error {
 			FakeIPManager:        m.fakeIPManager,
 			ForwarderPort:        &m.dnsForwarderPort,
 		}
+		if _, ok := m.splitTunnelRoutes[id]; ok {
+			params.ResolveLocally = true
+		}
 		handler := client.HandlerFromRoute(params)
 		if err := handler.AddRoute(m.ctx); err != nil {
 			merr = multierror.Append(merr, fmt.Errorf("add route %s: %w", handler.String(), err))
@@ -388,12 +436,15 @@ func (m *DefaultManager) UpdateRoutes(
 	m.useNewDNSRoute = useNewDNSRoute
 
 	var merr *multierror.Error
+	var filteredClientRoutes route.HAMap
 	if !m.disableClientRoutes {
 
 		// Update route selector based on management server's isSelected status
 		m.updateRouteSelectorFromManagement(clientRoutes)
 
-		filteredClientRoutes := m.routeSelector.FilterSelectedExitNodes(clientRoutes)
+		filteredClientRoutes = m.routeSelector.FilterSelectedExitNodes(clientRoutes)
+		m.updateSplitTunnelBypasses(filteredClientRoutes)
+		filteredClientRoutes = m.splitExitNodes(filteredClientRoutes)
 
 		if err := m.updateSystemRoutes(filteredClientRoutes); err != nil {
 			merr = multierror.Append(merr, fmt.Errorf("update system routes: %w", err))
@@ -411,10 +462,57 @@ func (m *DefaultManager) UpdateRoutes(
 	if err := m.serverRouter.UpdateRoutes(serverRoutes, useNewDNSRoute); err != nil {
 		merr = multierror.Append(merr, fmt.Errorf("update server routes: %w", err))
 	}
+	m.healthMonitor.Update(serverRoutes)
+	m.bgpSpeaker.Update(serverRoutes, m.bgpPrefixes(serverRoutes, filteredClientRoutes))
 
 	return nberrors.FormatErrorOrNil(merr)
 }
 
+// bgpPrefixes returns the prefixes announced to the BGP neighbors of the served routes: the NetBird network range and,
+// if a served route with BGP doesn't masquerade, the networks this peer routes to other routing peers. These are the
+// return paths of site-to-site traffic that keeps its source address, other client routes aren't announced.
+// Default routes, IPv6 and dynamic routes and networks overlapping the served ones are left out.
+func (m *DefaultManager) bgpPrefixes(serverRoutes map[route.ID]*route.Route, clientRoutes route.HAMap) []netip.Prefix {
+	var prefixes []netip.Prefix
+	if network := m.wgInterface.Address().Network; network.Addr().Is4() {
+		prefixes = append(prefixes, network)
+	}
+
+	if !hasUnmasqueradedBGPRoute(serverRoutes) {
+		return prefixes
+	}
+
+	for _, routes := range clientRoutes {
+		if len(routes) == 0 {
+			continue
+		}
+		r := routes[0]
+		if r.IsDynamic() || !r.Network.Addr().Is4() || r.Network.Bits() == 0 || overlapsServerRoutes(r.Network, serverRoutes) {
+			continue
+		}
+		prefixes = append(prefixes, r.Network.Masked())
+	}
+	return prefixes
+}
+
+func hasUnmasqueradedBGPRoute(serverRoutes map[route.ID]*route.Route) bool {
+	for _, r := range serverRoutes {
+		if r.BGP != nil && !r.Masquerade {
+			return true
+		}
+	}
+	return false
+}
+
+func overlapsServerRoutes(prefix netip.Prefix, serverRoutes map[route.ID]*route.Route) bool {
+	for _, r := range serverRoutes {
+		if !r.IsDynamic() && r.Network.Overlaps(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // SetRouteChangeListener set RouteListener for route change Notifier
 func (m *DefaultManager) SetRouteChangeListener(listener listener.NetworkChangeListener) {
 	m.notifier.SetListener(listener)
@@ -450,12 +548,33 @@ func (m *DefaultManager) GetClientRoutesWithNetID() map[route.NetID][]*route.Rou
 	return routes
 }
 
+// GetRouteSelections returns the routing peer selection of every active client network, sorted by network ID
+func (m *DefaultManager) GetRouteSelections() []client.Selection {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	selections := make([]client.Selection, 0, len(m.clientNetworks))
+	for _, watcher := range m.clientNetworks {
+		selections = append(selections, watcher.Selection())
+	}
+
+	slices.SortFunc(selections, func(a, b client.Selection) int {
+		if c := cmp.Compare(a.NetID, b.NetID); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Network, b.Network)
+	})
+	return selections
+}
+
 // TriggerSelection triggers the selection of routes, stopping deselected watchers and starting newly selected ones
 func (m *DefaultManager) TriggerSelection(networks route.HAMap) {
 	m.mux.Lock()
 	defer m.mux.Unlock()
 
 	networks = m.routeSelector.FilterSelectedExitNodes(networks)
+	m.updateSplitTunnelBypasses(networks)
+	networks = m.splitExitNodes(networks)
 
 	m.notifier.OnNewRoutes(networks)
 
@@ -566,13 +685,26 @@ func (m *DefaultManager) ClassifyRoutes(newRoutes []*route.Route) (map[route.ID]
 			if !isRouteSupported(newRoute) {
 				continue
 			}
-			newClientRoutesIDMap[haID] = append(newClientRoutesIDMap[haID], newRoute)
+			clientRoute := toClientRoute(newRoute)
+			clientHAID := clientRoute.GetHAUniqueID()
+			newClientRoutesIDMap[clientHAID] = append(newClientRoutesIDMap[clientHAID], clientRoute)
 		}
 	}
 
 	return newServerRoutesMap, newClientRoutesIDMap
 }
 
+// toClientRoute returns the route as it is applied by clients.
+// Network routes with a netmap are reached through the virtual prefix, the routing peer translates it to the real one.
+func toClientRoute(r *route.Route) *route.Route {
+	if r.NetMap == nil || r.IsDynamic() {
+		return r
+	}
+	clientRoute := r.Copy()
+	clientRoute.Network = r.NetMap.Virtual
+	return clientRoute
+}
+
 func (m *DefaultManager) initialClientRoutes(initialRoutes []*route.Route) []*route.Route {
 	_, crMap := m.ClassifyRoutes(initialRoutes)
 	rs := make([]*route.Route, 0, len(crMap))
@@ -12,8 +12,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/netbirdio/netbird/client/iface"
+	"github.com/netbirdio/netbird/client/iface/wgaddr"
 	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/routeselector"
 	"github.com/netbirdio/netbird/route"
+	"github.com/netbirdio/netbird/shared/management/domain"
 )
 
 // send 5 routes, one for server and 4 for clients, one normal and 2 HA and one small
@@ -462,3 +465,155 @@ func TestManagerUpdateRoutes(t *testing.T) {
 		})
 	}
 }
+
+func TestClassifyRoutesNetMap(t *testing.T) {
+	routeManager := &DefaultManager{pubKey: localPeerKey}
+	netMap := route.NewNetMap(netip.MustParsePrefix("192.168.0.0/24"), netip.MustParsePrefix("10.80.0.0/24"))
+
+	inputRoutes := []*route.Route{
+		{
+			ID:          "a",
+			NetID:       "siteA",
+			Peer:        localPeerKey,
+			Network:     netip.MustParsePrefix("192.168.0.0/24"),
+			NetworkType: route.IPv4Network,
+			Metric:      9999,
+			Enabled:     true,
+			NetMap:      netMap,
+		},
+		{
+			ID:          "b",
+			NetID:       "siteB",
+			Peer:        remotePeerKey1,
+			Network:     netip.MustParsePrefix("192.168.0.0/24"),
+			NetworkType: route.IPv4Network,
+			Metric:      9999,
+			Enabled:     true,
+			NetMap:      netMap,
+		},
+	}
+
+	serverRoutes, clientRoutes := routeManager.ClassifyRoutes(inputRoutes)
+
+	require.Len(t, serverRoutes, 1, "should have one server route")
+	require.Equal(t, netip.MustParsePrefix("192.168.0.0/24"), serverRoutes["a"].Network, "server route should keep the real prefix")
+
+	require.Len(t, clientRoutes, 1, "should have one client network")
+	clientRoute := clientRoutes["siteB|10.80.0.0/24"]
+	require.Len(t, clientRoute, 1, "client network should be keyed by the virtual prefix")
+	require.Equal(t, netip.MustParsePrefix("10.80.0.0/24"), clientRoute[0].Network, "client route should use the virtual prefix")
+	require.Equal(t, netip.MustParsePrefix("192.168.0.0/24"), inputRoutes[1].Network, "input route should not be modified")
+}
+
+func TestSplitExitNodes(t *testing.T) {
+	exitNode := func(id route.ID, peer string) *route.Route {
+		return &route.Route{
+			ID:          id,
+			NetID:       "exit",
+			Peer:        peer,
+			Network:     netip.MustParsePrefix("0.0.0.0/0"),
+			NetworkType: route.IPv4Network,
+			Metric:      9999,
+			Enabled:     true,
+		}
+	}
+	site := &route.Route{
+		ID:          "site",
+		NetID:       "site",
+		Peer:        remotePeerKey1,
+		Network:     netip.MustParsePrefix("10.0.0.0/16"),
+		NetworkType: route.IPv4Network,
+	}
+	routes := route.HAMap{
+		"exit|0.0.0.0/0":   {exitNode("a", remotePeerKey1), exitNode("b", remotePeerKey2)},
+		"site|10.0.0.0/16": {site},
+	}
+
+	routeManager := &DefaultManager{routeSelector: routeselector.NewRouteSelector()}
+
+	split := routeManager.splitExitNodes(routes)
+	require.Equal(t, routes, split, "exit nodes without split tunnel should be kept")
+
+	require.NoError(t, routeManager.routeSelector.SetSplitTunnel("exit", routeselector.SplitTunnel{
+		Mode:     routeselector.SplitTunnelExclude,
+		Prefixes: []netip.Prefix{netip.MustParsePrefix("13.107.64.0/18")},
+	}, []route.NetID{"exit"}))
+	split = routeManager.splitExitNodes(routes)
+	require.Equal(t, routes, split, "exit nodes in exclude mode should be kept")
+
+	require.NoError(t, routeManager.routeSelector.SetSplitTunnel("exit", routeselector.SplitTunnel{
+		Mode:     routeselector.SplitTunnelInclude,
+		Domains:  domain.List{"admin.example.com", "*.saas.example"},
+		Prefixes: []netip.Prefix{netip.MustParsePrefix("198.51.100.0/24")},
+	}, []route.NetID{"exit"}))
+	split = routeManager.splitExitNodes(routes)
+
+	require.Len(t, split, 3)
+	require.NotContains(t, split, route.HAUniqueID("exit|0.0.0.0/0"), "the default route should be replaced")
+	require.Equal(t, routes["site|10.0.0.0/16"], split["site|10.0.0.0/16"])
+
+	prefixRoutes := split["exit|198.51.100.0/24"]
+	require.Len(t, prefixRoutes, 2, "the split routes should keep all routing peers of the exit node")
+	for _, r := range prefixRoutes {
+		require.Equal(t, netip.MustParsePrefix("198.51.100.0/24"), r.Network)
+		require.Equal(t, route.IPv4Network, r.NetworkType)
+	}
+
+	domainRoutes := split["exit|admin.example.com, *.saas.example"]
+	require.Len(t, domainRoutes, 2)
+	for _, r := range domainRoutes {
+		require.True(t, r.IsDynamic())
+		require.Equal(t, domain.List{"admin.example.com", "*.saas.example"}, r.Domains)
+	}
+	require.Equal(t, []string{remotePeerKey1, remotePeerKey2}, []string{domainRoutes[0].Peer, domainRoutes[1].Peer})
+
+	require.Contains(t, routeManager.splitTunnelRoutes, route.HAUniqueID("exit|admin.example.com, *.saas.example"),
+		"the domain route should be resolved locally")
+	require.NotContains(t, routeManager.splitTunnelRoutes, route.HAUniqueID("exit|198.51.100.0/24"))
+
+	require.Equal(t, netip.MustParsePrefix("0.0.0.0/0"), routes["exit|0.0.0.0/0"][0].Network, "input routes should not be modified")
+}
+
+// addressWGIface is a WireGuard interface that only knows its address
+type addressWGIface struct {
+	iface.WGIface
+	address wgaddr.Address
+}
+
+func (w *addressWGIface) Address() wgaddr.Address {
+	return w.address
+}
+
+func TestBGPPrefixes(t *testing.T) {
+	netbirdNetwork := netip.MustParsePrefix("100.64.0.0/10")
+	routeManager := &DefaultManager{
+		wgInterface: &addressWGIface{address: wgaddr.Address{IP: netip.MustParseAddr("100.64.0.1"), Network: netbirdNetwork}},
+	}
+
+	served := &route.Route{
+		ID:          "served",
+		NetID:       "office",
+		Peer:        localPeerKey,
+		Network:     netip.MustParsePrefix("192.168.0.0/24"),
+		NetworkType: route.IPv4Network,
+		Masquerade:  true,
+		BGP:         &route.BGP{LocalASN: 65001, Neighbors: []route.BGPNeighbor{{Address: netip.MustParseAddr("192.168.0.1"), ASN: 65000}}},
+	}
+	serverRoutes := map[route.ID]*route.Route{served.ID: served}
+	clientRoutes := route.HAMap{
+		"site|10.0.0.0/16": {{
+			ID:          "site",
+			NetID:       "site",
+			Peer:        remotePeerKey1,
+			Network:     netip.MustParsePrefix("10.0.0.0/16"),
+			NetworkType: route.IPv4Network,
+		}},
+	}
+
+	require.Equal(t, []netip.Prefix{netbirdNetwork}, routeManager.bgpPrefixes(serverRoutes, clientRoutes),
+		"client routes aren't return paths of masqueraded traffic")
+
+	served.Masquerade = false
+	require.Equal(t, []netip.Prefix{netbirdNetwork, netip.MustParsePrefix("10.0.0.0/16")}, routeManager.bgpPrefixes(serverRoutes, clientRoutes),
+		"client routes are return paths of traffic that isn't masqueraded")
+}
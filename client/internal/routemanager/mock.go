@@ -6,6 +6,7 @@ import (
 	firewall "github.com/netbirdio/netbird/client/firewall/manager"
 	"github.com/netbirdio/netbird/client/iface"
 	"github.com/netbirdio/netbird/client/internal/listener"
+	"github.com/netbirdio/netbird/client/internal/routemanager/client"
 	"github.com/netbirdio/netbird/client/internal/routeselector"
 	"github.com/netbirdio/netbird/client/internal/statemanager"
 	"github.com/netbirdio/netbird/route"
@@ -19,6 +20,7 @@ type MockManager struct {
 	GetRouteSelectorFunc         func() *routeselector.RouteSelector
 	GetClientRoutesFunc          func() route.HAMap
 	GetClientRoutesWithNetIDFunc func() map[route.NetID][]*route.Route
+	GetRouteSelectionsFunc       func() []client.Selection
 	StopFunc                     func(manager *statemanager.Manager)
 }
 
@@ -77,6 +79,14 @@ func (m *MockManager) GetClientRoutesWithNetID() map[route.NetID][]*route.Route
 	return nil
 }
 
+// GetRouteSelections mock implementation of GetRouteSelections from Manager interface
+func (m *MockManager) GetRouteSelections() []client.Selection {
+	if m.GetRouteSelectionsFunc != nil {
+		return m.GetRouteSelectionsFunc()
+	}
+	return nil
+}
+
 // Start mock implementation of Start from Manager interface
 func (m *MockManager) Start(ctx context.Context, iface *iface.WGIface) {
 }
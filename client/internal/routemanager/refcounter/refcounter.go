@@ -127,6 +127,35 @@ func (rm *Counter[Key, I, O]) increment(key Key, in I) (Ref[O], error) {
 	return ref, nil
 }
 
+// Replace calls the AddFunc for an existing key with new input before the RemoveFunc for its previous output and keeps
+// the reference count, e.g., to move an allowed IP to another peer without a gap. A new key is added like Increment.
+func (rm *Counter[Key, I, O]) Replace(key Key, in I) (Ref[O], error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	ref, ok := rm.refCountMap[key]
+	if !ok {
+		return rm.increment(key, in)
+	}
+
+	logCallerF("Replacing key %v with In [%v] Out [%v]", key, in, ref.Out)
+	out, err := rm.add(key, in)
+	if errors.Is(err, ErrIgnore) {
+		return ref, nil
+	}
+	if err != nil {
+		return ref, fmt.Errorf("failed to add for key %v: %w", key, err)
+	}
+
+	if err := rm.remove(key, ref.Out); err != nil {
+		log.Warnf("failed to remove previous output [%v] for key %v: %v", ref.Out, key, err)
+	}
+	ref.Out = out
+	rm.refCountMap[key] = ref
+
+	return ref, nil
+}
+
 // IncrementWithID increments the reference count for the given key and groups it under the given ID.
 // If this is the first reference to the key, the AddFunc is called.
 func (rm *Counter[Key, I, O]) IncrementWithID(id string, key Key, in I) (Ref[O], error) {
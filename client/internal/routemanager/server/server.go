@@ -153,11 +153,17 @@ func routeToRouterPair(route *route.Route, useNewDNSRoute bool) firewall.RouterP
 		destination.Prefix = route.Network.Masked()
 	}
 
+	// peers address the virtual prefix, the routing peer translates it to the real prefix
+	if route.NetMap != nil {
+		destination = firewall.Network{Prefix: route.NetMap.Virtual}
+	}
+
 	return firewall.RouterPair{
 		ID:          route.ID,
 		Source:      source,
 		Destination: destination,
 		Masquerade:  route.Masquerade,
+		NetMap:      route.NetMap,
 	}
 }
 
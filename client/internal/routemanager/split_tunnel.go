@@ -0,0 +1,183 @@
+package routemanager
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/hashicorp/go-multierror"
+	log "github.com/sirupsen/logrus"
+
+	nberrors "github.com/netbirdio/netbird/client/errors"
+	"github.com/netbirdio/netbird/client/internal/routemanager/common"
+	"github.com/netbirdio/netbird/client/internal/routemanager/dnsinterceptor"
+	"github.com/netbirdio/netbird/client/internal/routeselector"
+	"github.com/netbirdio/netbird/route"
+)
+
+// splitTunnelBypass routes the destinations of an exit node split tunnel in exclude mode around the VPN
+type splitTunnelBypass struct {
+	splitTunnel routeselector.SplitTunnel
+	// interceptor tracks the IPs of the domains, nil if the split tunnel has no domains
+	interceptor *dnsinterceptor.DnsInterceptor
+}
+
+// splitExitNodes replaces the selected exit nodes that have a split tunnel in include mode with routes to the
+// prefixes and domains of the split tunnel through the same routing peers.
+// The domains are resolved by the local upstreams, the exit node doesn't know about them.
+func (m *DefaultManager) splitExitNodes(routes route.HAMap) route.HAMap {
+	m.splitTunnelRoutes = make(map[route.HAUniqueID]struct{})
+
+	split := make(route.HAMap, len(routes))
+	for id, rt := range routes {
+		splitTunnel, ok := m.routeSelector.GetSplitTunnel(id.NetID())
+		if !ok || splitTunnel.Mode != routeselector.SplitTunnelInclude || !m.isExitNodeRoute(rt) {
+			split[id] = rt
+			continue
+		}
+
+		for _, splitRoutes := range splitTunnelRoutes(rt, splitTunnel) {
+			splitID := splitRoutes[0].GetHAUniqueID()
+			split[splitID] = splitRoutes
+			if splitRoutes[0].IsDynamic() {
+				m.splitTunnelRoutes[splitID] = struct{}{}
+			}
+		}
+	}
+	return split
+}
+
+// splitTunnelRoutes returns the HA routes to the prefixes and the domains of a split tunnel for the routes of an exit node
+func splitTunnelRoutes(exitNode []*route.Route, splitTunnel routeselector.SplitTunnel) [][]*route.Route {
+	var split [][]*route.Route
+	for _, prefix := range splitTunnel.Prefixes {
+		networkType := route.IPv4Network
+		if prefix.Addr().Is6() {
+			networkType = route.IPv6Network
+		}
+
+		routes := make([]*route.Route, 0, len(exitNode))
+		for _, r := range exitNode {
+			prefixRoute := r.Copy()
+			prefixRoute.Network = prefix.Masked()
+			prefixRoute.NetworkType = networkType
+			routes = append(routes, prefixRoute)
+		}
+		split = append(split, routes)
+	}
+
+	if len(splitTunnel.Domains) > 0 {
+		routes := make([]*route.Route, 0, len(exitNode))
+		for _, r := range exitNode {
+			domainRoute := r.Copy()
+			domainRoute.Network = netip.Prefix{}
+			domainRoute.NetworkType = route.DomainNetwork
+			domainRoute.Domains = splitTunnel.Domains
+			// CDNs rotate their IPs, established connections keep their path
+			domainRoute.KeepRoute = true
+			routes = append(routes, domainRoute)
+		}
+		split = append(split, routes)
+	}
+	return split
+}
+
+// updateSplitTunnelBypasses adds the bypass routes of the selected exit nodes that have a split tunnel in exclude mode
+// and removes the ones of exit nodes that aren't selected anymore or whose split tunnel changed
+func (m *DefaultManager) updateSplitTunnelBypasses(routes route.HAMap) {
+	desired := make(map[route.NetID]routeselector.SplitTunnel)
+	exitNodes := make(map[route.NetID]*route.Route)
+	for id, rt := range routes {
+		if !m.isExitNodeRoute(rt) {
+			continue
+		}
+		splitTunnel, ok := m.routeSelector.GetSplitTunnel(id.NetID())
+		if !ok || splitTunnel.Mode != routeselector.SplitTunnelExclude {
+			continue
+		}
+		desired[id.NetID()] = splitTunnel
+		exitNodes[id.NetID()] = rt[0]
+	}
+
+	for netID, bypass := range m.splitTunnelBypasses {
+		if splitTunnel, ok := desired[netID]; ok && splitTunnel.Equal(bypass.splitTunnel) {
+			delete(desired, netID)
+			continue
+		}
+		if err := m.removeSplitTunnelBypass(bypass); err != nil {
+			log.Errorf("failed to remove split tunnel bypass of exit node %s: %v", netID, err)
+		}
+		delete(m.splitTunnelBypasses, netID)
+	}
+
+	for netID, splitTunnel := range desired {
+		bypass, err := m.addSplitTunnelBypass(exitNodes[netID], splitTunnel)
+		if err != nil {
+			log.Errorf("failed to add split tunnel bypass of exit node %s: %v", netID, err)
+		}
+		m.splitTunnelBypasses[netID] = bypass
+	}
+}
+
+func (m *DefaultManager) addSplitTunnelBypass(exitNode *route.Route, splitTunnel routeselector.SplitTunnel) (*splitTunnelBypass, error) {
+	bypass := &splitTunnelBypass{splitTunnel: splitTunnel}
+
+	var merr *multierror.Error
+	for _, prefix := range splitTunnel.Prefixes {
+		if _, err := m.bypassRefCounter.Increment(prefix.Masked(), struct{}{}); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("add bypass route for %s: %w", prefix, err))
+		}
+	}
+
+	if len(splitTunnel.Domains) > 0 {
+		params := common.HandlerParams{
+			Route: &route.Route{
+				ID:          exitNode.ID,
+				NetID:       exitNode.NetID,
+				NetworkType: route.DomainNetwork,
+				Domains:     splitTunnel.Domains,
+				KeepRoute:   true,
+			},
+			RouteRefCounter:      m.bypassRefCounter,
+			AllowedIPsRefCounter: m.allowedIPsRefCounter,
+			StatusRecorder:       m.statusRecorder,
+			WgInterface:          m.wgInterface,
+			DnsServer:            m.dnsServer,
+			PeerStore:            m.peerStore,
+			ForwarderPort:        &m.dnsForwarderPort,
+			ResolveLocally:       true,
+		}
+		bypass.interceptor = dnsinterceptor.New(params)
+		if err := bypass.interceptor.AddRoute(m.ctx); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("add domain bypass %s: %w", splitTunnel.Domains.SafeString(), err))
+		}
+	}
+
+	return bypass, nberrors.FormatErrorOrNil(merr)
+}
+
+func (m *DefaultManager) removeSplitTunnelBypass(bypass *splitTunnelBypass) error {
+	var merr *multierror.Error
+	for _, prefix := range bypass.splitTunnel.Prefixes {
+		if _, err := m.bypassRefCounter.Decrement(prefix.Masked()); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("remove bypass route for %s: %w", prefix, err))
+		}
+	}
+
+	if bypass.interceptor != nil {
+		if err := bypass.interceptor.RemoveRoute(); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("remove domain bypass: %w", err))
+		}
+	}
+
+	return nberrors.FormatErrorOrNil(merr)
+}
+
+// removeSplitTunnelBypasses removes the bypass routes of all exit nodes
+func (m *DefaultManager) removeSplitTunnelBypasses() {
+	for netID, bypass := range m.splitTunnelBypasses {
+		if err := m.removeSplitTunnelBypass(bypass); err != nil {
+			log.Errorf("failed to remove split tunnel bypass of exit node %s: %v", netID, err)
+		}
+		delete(m.splitTunnelBypasses, netID)
+	}
+}
@@ -3,6 +3,7 @@ package static
 import (
 	"context"
 	"fmt"
+	"net/netip"
 
 	log "github.com/sirupsen/logrus"
 
@@ -61,3 +62,35 @@ func (r *Route) RemoveAllowedIPs() error {
 	}
 	return nil
 }
+
+// Network returns the network of the route
+func (r *Route) Network() netip.Prefix {
+	return r.route.Network
+}
+
+// AddPrefixAllowedIPs routes a part of the network through the peer, used to spread the network across routing peers
+func (r *Route) AddPrefixAllowedIPs(prefix netip.Prefix, peerKey string) error {
+	if ref, err := r.allowedIPsRefcounter.Increment(prefix, peerKey); err != nil {
+		return fmt.Errorf("add allowed IP %s: %w", prefix, err)
+	} else if ref.Count > 1 && ref.Out != peerKey {
+		log.Warnf("Prefix [%s] is already routed by peer [%s]", prefix, ref.Out)
+	}
+	return nil
+}
+
+// MovePrefixAllowedIPs routes a part of the network added by AddPrefixAllowedIPs through another peer. WireGuard moves
+// an allowed IP added to another peer, so the traffic isn't interrupted.
+func (r *Route) MovePrefixAllowedIPs(prefix netip.Prefix, peerKey string) error {
+	if _, err := r.allowedIPsRefcounter.Replace(prefix, peerKey); err != nil {
+		return fmt.Errorf("move allowed IP %s: %w", prefix, err)
+	}
+	return nil
+}
+
+// RemovePrefixAllowedIPs removes a part of the network added by AddPrefixAllowedIPs
+func (r *Route) RemovePrefixAllowedIPs(prefix netip.Prefix) error {
+	if _, err := r.allowedIPsRefcounter.Decrement(prefix); err != nil {
+		return err
+	}
+	return nil
+}
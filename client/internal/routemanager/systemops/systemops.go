@@ -12,6 +12,7 @@ import (
 	"github.com/netbirdio/netbird/client/internal/routemanager/notifier"
 	"github.com/netbirdio/netbird/client/internal/routemanager/refcounter"
 	"github.com/netbirdio/netbird/client/internal/routemanager/vars"
+	"github.com/netbirdio/netbird/client/internal/statemanager"
 )
 
 type Nexthop struct {
@@ -81,6 +82,15 @@ type SysOps struct {
 	localSubnetsCache     []*net.IPNet
 	localSubnetsCacheMu   sync.RWMutex
 	localSubnetsCacheTime time.Time
+
+	// initialNextHopV4 and initialNextHopV6 are the default next hops before the VPN routes were added
+	//nolint:unused // not used on mobile systems
+	initialNextHopV4 Nexthop
+	//nolint:unused // not used on mobile systems
+	initialNextHopV6 Nexthop
+	// stateManager persists the routes of the ref counter
+	//nolint:unused // not used on mobile systems
+	stateManager *statemanager.Manager
 }
 
 func New(wgInterface wgIface, notifier *notifier.Notifier) *SysOps {
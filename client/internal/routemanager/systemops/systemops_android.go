@@ -3,6 +3,8 @@
 package systemops
 
 import (
+	"errors"
+	"fmt"
 	"net"
 	"net/netip"
 	"runtime"
@@ -32,6 +34,14 @@ func (r *SysOps) removeFromRouteTable(netip.Prefix, Nexthop) error {
 	return nil
 }
 
+func (r *SysOps) AddBypassRoute(netip.Prefix) error {
+	return fmt.Errorf("bypass routes are not supported on %s: %w", runtime.GOOS, errors.ErrUnsupported)
+}
+
+func (r *SysOps) RemoveBypassRoute(netip.Prefix) error {
+	return nil
+}
+
 func EnableIPForwarding() error {
 	log.Infof("Enable IP forwarding is not implemented on %s", runtime.GOOS)
 	return nil
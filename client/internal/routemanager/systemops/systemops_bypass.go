@@ -0,0 +1,38 @@
+//go:build !android && !ios
+
+package systemops
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+)
+
+// AddBypassRoute routes the prefix through the next hop outside of the VPN interface.
+// The route is more specific than the default route of an exit node and takes precedence over it.
+// Prefixes in local subnets are reached directly already and are ignored.
+// The route shares the ref counter of the routes excluded from the VPN, so it's persisted in the shutdown state.
+func (r *SysOps) AddBypassRoute(prefix netip.Prefix) error {
+	if r.refCounter == nil {
+		return errors.New("routing is not set up")
+	}
+
+	if _, err := r.refCounter.Increment(prefix, struct{}{}); err != nil {
+		return fmt.Errorf("add bypass route: %w", err)
+	}
+	r.updateState(r.stateManager)
+	return nil
+}
+
+// RemoveBypassRoute removes a route added by AddBypassRoute
+func (r *SysOps) RemoveBypassRoute(prefix netip.Prefix) error {
+	if r.refCounter == nil {
+		return nil
+	}
+
+	if _, err := r.refCounter.Decrement(prefix); err != nil {
+		return fmt.Errorf("remove bypass route: %w", err)
+	}
+	r.updateState(r.stateManager)
+	return nil
+}
@@ -0,0 +1,54 @@
+//go:build !android && !ios
+
+package systemops
+
+import (
+	"context"
+	"net/netip"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/routemanager/refcounter"
+	"github.com/netbirdio/netbird/client/internal/statemanager"
+)
+
+func TestBypassRoutesArePersisted(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	stateManager := statemanager.New(stateFile)
+	stateManager.RegisterState(&ShutdownState{})
+
+	nexthop := Nexthop{IP: netip.MustParseAddr("192.168.1.1")}
+	var removed []netip.Prefix
+	r := New(nil, nil)
+	r.stateManager = stateManager
+	r.refCounter = refcounter.New(
+		func(netip.Prefix, struct{}) (Nexthop, error) {
+			return nexthop, nil
+		},
+		func(prefix netip.Prefix, _ Nexthop) error {
+			removed = append(removed, prefix)
+			return nil
+		},
+	)
+
+	prefix := netip.MustParsePrefix("203.0.113.0/24")
+	require.NoError(t, r.AddBypassRoute(prefix))
+	require.NoError(t, stateManager.PersistState(context.Background()))
+
+	loaded := statemanager.New(stateFile)
+	loaded.RegisterState(&ShutdownState{})
+	require.NoError(t, loaded.LoadState(&ShutdownState{}))
+	state, ok := loaded.GetState(&ShutdownState{}).(*ShutdownState)
+	require.True(t, ok, "the bypass route should be persisted")
+	ref, found := (*ExclusionCounter)(state).Get(prefix)
+	require.True(t, found)
+	assert.Equal(t, nexthop.IP, ref.Out.IP)
+
+	require.NoError(t, r.RemoveBypassRoute(prefix))
+	assert.Equal(t, []netip.Prefix{prefix}, removed)
+	_, found = r.refCounter.Get(prefix)
+	assert.False(t, found)
+}
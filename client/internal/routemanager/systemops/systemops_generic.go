@@ -34,16 +34,21 @@ var splitDefaultv6_2 = netip.PrefixFrom(netip.AddrFrom16([16]byte{0x80}), 1)
 var ErrRoutingIsSeparate = errors.New("routing is separate")
 
 func (r *SysOps) setupRefCounter(initAddresses []net.IP, stateManager *statemanager.Manager) error {
-	stateManager.RegisterState(&ShutdownState{})
+	r.initRefCounter(stateManager)
 
-	initialNextHopV4, err := GetNextHop(netip.IPv4Unspecified())
-	if err != nil && !errors.Is(err, vars.ErrRouteNotFound) {
-		log.Errorf("Unable to get initial v4 default next hop: %v", err)
-	}
-	initialNextHopV6, err := GetNextHop(netip.IPv6Unspecified())
-	if err != nil && !errors.Is(err, vars.ErrRouteNotFound) {
-		log.Errorf("Unable to get initial v6 default next hop: %v", err)
+	if err := r.setupHooks(initAddresses, stateManager); err != nil {
+		return fmt.Errorf("setup hooks: %w", err)
 	}
+	return nil
+}
+
+// initRefCounter creates the counter of the routes that bypass the VPN interface. The routes are persisted in the
+// shutdown state, so they are removed after a crash.
+func (r *SysOps) initRefCounter(stateManager *statemanager.Manager) {
+	stateManager.RegisterState(&ShutdownState{})
+	r.stateManager = stateManager
+
+	initialNextHopV4, initialNextHopV6 := r.setInitialNextHops()
 
 	refCounter := refcounter.New(
 		func(prefix netip.Prefix, _ struct{}) (Nexthop, error) {
@@ -76,11 +81,23 @@ func (r *SysOps) setupRefCounter(initAddresses []net.IP, stateManager *statemana
 	}
 
 	r.refCounter = refCounter
+}
 
-	if err := r.setupHooks(initAddresses, stateManager); err != nil {
-		return fmt.Errorf("setup hooks: %w", err)
+// setInitialNextHops stores the default next hops before any VPN routes are added.
+// Routes that bypass the VPN fall back to them if the current next hop is the VPN interface.
+func (r *SysOps) setInitialNextHops() (Nexthop, Nexthop) {
+	initialNextHopV4, err := GetNextHop(netip.IPv4Unspecified())
+	if err != nil && !errors.Is(err, vars.ErrRouteNotFound) {
+		log.Errorf("Unable to get initial v4 default next hop: %v", err)
 	}
-	return nil
+	initialNextHopV6, err := GetNextHop(netip.IPv6Unspecified())
+	if err != nil && !errors.Is(err, vars.ErrRouteNotFound) {
+		log.Errorf("Unable to get initial v6 default next hop: %v", err)
+	}
+
+	r.initialNextHopV4 = initialNextHopV4
+	r.initialNextHopV6 = initialNextHopV6
+	return initialNextHopV4, initialNextHopV6
 }
 
 // updateState updates state on every change so it will be persisted regularly
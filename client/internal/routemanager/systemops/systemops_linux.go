@@ -108,6 +108,9 @@ func (r *SysOps) SetupRouting(initAddresses []net.IP, stateManager *statemanager
 		}
 	}()
 
+	// the counter only tracks the routes that bypass an exit node, other routes are excluded by the routing rules
+	r.initRefCounter(stateManager)
+
 	rules := getSetupRules()
 	for _, rule := range rules {
 		if err := addRule(rule); err != nil {
@@ -139,6 +142,10 @@ func (r *SysOps) CleanupRouting(stateManager *statemanager.Manager, advancedRout
 
 	var result *multierror.Error
 
+	if err := r.cleanupRefCounter(stateManager); err != nil {
+		result = multierror.Append(result, fmt.Errorf("cleanup bypass routes: %w", err))
+	}
+
 	if err := flushRoutes(NetbirdVPNTableID, netlink.FAMILY_V4); err != nil {
 		result = multierror.Append(result, fmt.Errorf("flush routes v4: %w", err))
 	}
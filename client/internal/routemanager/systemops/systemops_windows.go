@@ -195,6 +195,7 @@ const (
 
 func (r *SysOps) SetupRouting(initAddresses []net.IP, stateManager *statemanager.Manager, advancedRouting bool) error {
 	if advancedRouting {
+		r.setInitialNextHops()
 		return nil
 	}
 
@@ -22,6 +22,8 @@ type RouteSelector struct {
 	deselectedRoutes map[route.NetID]struct{}
 	selectedRoutes   map[route.NetID]struct{}
 	deselectAll      bool
+	// splitTunnels holds the split tunnels of exit nodes, they are kept independently of the selection
+	splitTunnels map[route.NetID]SplitTunnel
 }
 
 func NewRouteSelector() *RouteSelector {
@@ -29,6 +31,7 @@ func NewRouteSelector() *RouteSelector {
 		deselectedRoutes: map[route.NetID]struct{}{},
 		selectedRoutes:   map[route.NetID]struct{}{},
 		deselectAll:      false,
+		splitTunnels:     map[route.NetID]SplitTunnel{},
 	}
 }
 
@@ -241,13 +244,15 @@ func (rs *RouteSelector) MarshalJSON() ([]byte, error) {
 	defer rs.mu.RUnlock()
 
 	return json.Marshal(struct {
-		SelectedRoutes   map[route.NetID]struct{} `json:"selected_routes"`
-		DeselectedRoutes map[route.NetID]struct{} `json:"deselected_routes"`
-		DeselectAll      bool                     `json:"deselect_all"`
+		SelectedRoutes   map[route.NetID]struct{}    `json:"selected_routes"`
+		DeselectedRoutes map[route.NetID]struct{}    `json:"deselected_routes"`
+		DeselectAll      bool                        `json:"deselect_all"`
+		SplitTunnels     map[route.NetID]SplitTunnel `json:"split_tunnels,omitempty"`
 	}{
 		SelectedRoutes:   rs.selectedRoutes,
 		DeselectedRoutes: rs.deselectedRoutes,
 		DeselectAll:      rs.deselectAll,
+		SplitTunnels:     rs.splitTunnels,
 	})
 }
 
@@ -262,13 +267,15 @@ func (rs *RouteSelector) UnmarshalJSON(data []byte) error {
 		rs.deselectedRoutes = map[route.NetID]struct{}{}
 		rs.selectedRoutes = map[route.NetID]struct{}{}
 		rs.deselectAll = false
+		rs.splitTunnels = map[route.NetID]SplitTunnel{}
 		return nil
 	}
 
 	var temp struct {
-		SelectedRoutes   map[route.NetID]struct{} `json:"selected_routes"`
-		DeselectedRoutes map[route.NetID]struct{} `json:"deselected_routes"`
-		DeselectAll      bool                     `json:"deselect_all"`
+		SelectedRoutes   map[route.NetID]struct{}    `json:"selected_routes"`
+		DeselectedRoutes map[route.NetID]struct{}    `json:"deselected_routes"`
+		DeselectAll      bool                        `json:"deselect_all"`
+		SplitTunnels     map[route.NetID]SplitTunnel `json:"split_tunnels"`
 	}
 
 	if err := json.Unmarshal(data, &temp); err != nil {
@@ -278,6 +285,7 @@ func (rs *RouteSelector) UnmarshalJSON(data []byte) error {
 	rs.selectedRoutes = temp.SelectedRoutes
 	rs.deselectedRoutes = temp.DeselectedRoutes
 	rs.deselectAll = temp.DeselectAll
+	rs.splitTunnels = temp.SplitTunnels
 
 	if rs.deselectedRoutes == nil {
 		rs.deselectedRoutes = map[route.NetID]struct{}{}
@@ -285,6 +293,9 @@ func (rs *RouteSelector) UnmarshalJSON(data []byte) error {
 	if rs.selectedRoutes == nil {
 		rs.selectedRoutes = map[route.NetID]struct{}{}
 	}
+	if rs.splitTunnels == nil {
+		rs.splitTunnels = map[route.NetID]SplitTunnel{}
+	}
 
 	return nil
 }
@@ -1,6 +1,7 @@
 package routeselector_test
 
 import (
+	"encoding/json"
 	"net/netip"
 	"slices"
 	"testing"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/netbirdio/netbird/client/internal/routeselector"
 	"github.com/netbirdio/netbird/route"
+	"github.com/netbirdio/netbird/shared/management/domain"
 )
 
 func TestRouteSelector_SelectRoutes(t *testing.T) {
@@ -694,3 +696,42 @@ func TestRouteSelector_ComplexScenarios(t *testing.T) {
 		})
 	}
 }
+
+func TestRouteSelector_SplitTunnel(t *testing.T) {
+	exitNodes := []route.NetID{"exit1", "exit2"}
+	splitTunnel := routeselector.SplitTunnel{
+		Mode:     routeselector.SplitTunnelExclude,
+		Domains:  domain.List{"*.zoom.us", "teams.microsoft.com"},
+		Prefixes: []netip.Prefix{netip.MustParsePrefix("13.107.64.0/18")},
+	}
+
+	rs := routeselector.NewRouteSelector()
+	require.NoError(t, rs.SetSplitTunnel("exit1", splitTunnel, exitNodes))
+	require.Error(t, rs.SetSplitTunnel("route1", splitTunnel, exitNodes), "only exit nodes can be split")
+	require.Error(t, rs.SetSplitTunnel("exit2", routeselector.SplitTunnel{Mode: "other", Domains: splitTunnel.Domains}, exitNodes))
+	require.Error(t, rs.SetSplitTunnel("exit2", routeselector.SplitTunnel{Mode: routeselector.SplitTunnelInclude}, exitNodes))
+	require.Error(t, rs.SetSplitTunnel("exit2", routeselector.SplitTunnel{
+		Mode:     routeselector.SplitTunnelInclude,
+		Prefixes: []netip.Prefix{netip.MustParsePrefix("0.0.0.0/0")},
+	}, exitNodes))
+
+	// the split tunnel is configuration, not a selection
+	rs.DeselectAllRoutes()
+	rs.SelectAllRoutes()
+
+	data, err := json.Marshal(rs)
+	require.NoError(t, err)
+
+	restored := routeselector.NewRouteSelector()
+	require.NoError(t, json.Unmarshal(data, restored))
+
+	got, ok := restored.GetSplitTunnel("exit1")
+	require.True(t, ok)
+	assert.True(t, splitTunnel.Equal(got))
+	_, ok = restored.GetSplitTunnel("exit2")
+	assert.False(t, ok)
+
+	restored.RemoveSplitTunnel("exit1")
+	_, ok = restored.GetSplitTunnel("exit1")
+	assert.False(t, ok)
+}
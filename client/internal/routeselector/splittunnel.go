@@ -0,0 +1,100 @@
+package routeselector
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+
+	"github.com/netbirdio/netbird/route"
+	"github.com/netbirdio/netbird/shared/management/domain"
+)
+
+// SplitTunnelMode defines what happens to the destinations of a split tunnel
+type SplitTunnelMode string
+
+const (
+	// SplitTunnelInclude routes only the destinations of the split tunnel through the exit node
+	SplitTunnelInclude SplitTunnelMode = "include"
+	// SplitTunnelExclude routes everything through the exit node except the destinations of the split tunnel
+	SplitTunnelExclude SplitTunnelMode = "exclude"
+)
+
+// SplitTunnel selects the destinations of an exit node by domain and prefix.
+// The IPs of the domains are learned from the DNS answers for them.
+type SplitTunnel struct {
+	Mode     SplitTunnelMode `json:"mode"`
+	Domains  domain.List     `json:"domains,omitempty"`
+	Prefixes []netip.Prefix  `json:"prefixes,omitempty"`
+}
+
+// Validate checks the mode and that the split tunnel has destinations
+func (s SplitTunnel) Validate() error {
+	if s.Mode != SplitTunnelInclude && s.Mode != SplitTunnelExclude {
+		return fmt.Errorf("invalid split tunnel mode %q, must be %q or %q", s.Mode, SplitTunnelInclude, SplitTunnelExclude)
+	}
+	if len(s.Domains) == 0 && len(s.Prefixes) == 0 {
+		return errors.New("split tunnel requires at least one domain or prefix")
+	}
+	for _, prefix := range s.Prefixes {
+		if !prefix.IsValid() || prefix.Bits() == 0 {
+			return fmt.Errorf("invalid split tunnel prefix %s", prefix)
+		}
+	}
+	return nil
+}
+
+// Equal compares two split tunnels
+func (s SplitTunnel) Equal(other SplitTunnel) bool {
+	return s.Mode == other.Mode &&
+		s.Domains.Equal(other.Domains) &&
+		slices.Equal(s.Prefixes, other.Prefixes)
+}
+
+// Copy returns a deep copy of the split tunnel
+func (s SplitTunnel) Copy() SplitTunnel {
+	return SplitTunnel{
+		Mode:     s.Mode,
+		Domains:  slices.Clone(s.Domains),
+		Prefixes: slices.Clone(s.Prefixes),
+	}
+}
+
+// SetSplitTunnel configures the split tunnel of an exit node, replacing an existing one
+func (rs *RouteSelector) SetSplitTunnel(netID route.NetID, splitTunnel SplitTunnel, exitNodes []route.NetID) error {
+	if !slices.Contains(exitNodes, netID) {
+		return fmt.Errorf("exit node '%s' is not available", netID)
+	}
+	if err := splitTunnel.Validate(); err != nil {
+		return err
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.splitTunnels == nil {
+		rs.splitTunnels = map[route.NetID]SplitTunnel{}
+	}
+	rs.splitTunnels[netID] = splitTunnel.Copy()
+	return nil
+}
+
+// RemoveSplitTunnel removes the split tunnel of an exit node, the exit node routes all traffic again
+func (rs *RouteSelector) RemoveSplitTunnel(netID route.NetID) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	delete(rs.splitTunnels, netID)
+}
+
+// GetSplitTunnel returns the split tunnel of an exit node
+func (rs *RouteSelector) GetSplitTunnel(netID route.NetID) (SplitTunnel, bool) {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	splitTunnel, ok := rs.splitTunnels[netID]
+	if !ok {
+		return SplitTunnel{}, false
+	}
+	return splitTunnel.Copy(), true
+}
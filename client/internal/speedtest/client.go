@@ -0,0 +1,428 @@
+package speedtest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/netbirdio/netbird/client/internal/peer/quality"
+)
+
+const (
+	// DefaultDuration is the default duration of each throughput test
+	DefaultDuration = 5 * time.Second
+	// DefaultEchoCount is the default number of round trip probes
+	DefaultEchoCount = 20
+
+	echoInterval = 100 * time.Millisecond
+	echoTimeout  = time.Second
+	// minBitrate is the lowest rate of the UDP tests if the rate is derived from the TCP tests
+	minBitrate = 1_000_000
+)
+
+// Dialer opens the connections of a speed test, e.g. through the userspace network stack
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// Options configure a speed test run
+type Options struct {
+	// Duration of each throughput test
+	Duration time.Duration
+	// UDPBitrate is the rate of the UDP tests in bits per second. If zero, the rate measured by the TCP test in
+	// the same direction is used.
+	UDPBitrate uint64
+	// EchoCount is the number of round trip probes
+	EchoCount int
+}
+
+// Throughput is the result of a throughput test
+type Throughput struct {
+	Bytes    uint64
+	Duration time.Duration
+	// Loss is the share of lost packets of a UDP test
+	Loss float64
+}
+
+// BitsPerSecond returns the measured throughput
+func (t Throughput) BitsPerSecond() float64 {
+	if t.Duration <= 0 {
+		return 0
+	}
+	return float64(t.Bytes*8) / t.Duration.Seconds()
+}
+
+// Result holds the results of a speed test run. Upload is the direction from the local to the remote peer.
+type Result struct {
+	RTT         quality.Stats
+	TCPUpload   Throughput
+	TCPDownload Throughput
+	UDPUpload   Throughput
+	UDPDownload Throughput
+}
+
+// Run measures the round trip time and the throughput to the speed test responder at the given address
+func Run(ctx context.Context, dialer Dialer, addr netip.AddrPort, opts Options) (*Result, error) {
+	if opts.Duration <= 0 {
+		opts.Duration = DefaultDuration
+	}
+	if opts.Duration > MaxDuration {
+		return nil, fmt.Errorf("duration exceeds the maximum of %s", MaxDuration)
+	}
+	if opts.UDPBitrate > MaxBitrate {
+		return nil, fmt.Errorf("UDP bitrate exceeds the maximum of %d bit/s", uint64(MaxBitrate))
+	}
+	if opts.EchoCount <= 0 {
+		opts.EchoCount = DefaultEchoCount
+	}
+
+	c := &client{
+		dialer: dialer,
+		addr:   addr.String(),
+		opts:   opts,
+	}
+	if err := binary.Read(rand.Reader, binary.BigEndian, &c.session); err != nil {
+		return nil, fmt.Errorf("generate session id: %w", err)
+	}
+
+	udpConn, err := dialer.DialContext(ctx, "udp", c.addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial udp: %w", err)
+	}
+	defer func() {
+		_ = udpConn.Close()
+	}()
+	c.udpConn = udpConn
+
+	result := &Result{}
+	if result.RTT, err = c.echo(ctx); err != nil {
+		return nil, fmt.Errorf("round trip test: %w", err)
+	}
+	if result.RTT.Received == 0 {
+		return nil, errors.New("no response from the speed test responder of the peer")
+	}
+
+	if result.TCPUpload, err = c.tcpUpload(ctx); err != nil {
+		return nil, fmt.Errorf("%s test: %w", testTCPUpload, err)
+	}
+	if result.TCPDownload, err = c.tcpDownload(ctx); err != nil {
+		return nil, fmt.Errorf("%s test: %w", testTCPDownload, err)
+	}
+	if result.UDPUpload, err = c.udpUpload(ctx, c.bitrate(result.TCPUpload)); err != nil {
+		return nil, fmt.Errorf("%s test: %w", testUDPUpload, err)
+	}
+	if result.UDPDownload, err = c.udpDownload(ctx, c.bitrate(result.TCPDownload)); err != nil {
+		return nil, fmt.Errorf("%s test: %w", testUDPDownload, err)
+	}
+
+	return result, nil
+}
+
+type client struct {
+	dialer  Dialer
+	addr    string
+	opts    Options
+	session uint64
+	udpConn net.Conn
+}
+
+// bitrate returns the rate of a UDP test, derived from the TCP test in the same direction if not configured
+func (c *client) bitrate(tcp Throughput) uint64 {
+	if c.opts.UDPBitrate > 0 {
+		return c.opts.UDPBitrate
+	}
+	return min(max(uint64(tcp.BitsPerSecond()), minBitrate), MaxBitrate)
+}
+
+func (c *client) echo(ctx context.Context) (quality.Stats, error) {
+	window := quality.NewWindow(c.opts.EchoCount)
+	packet := make([]byte, udpHeaderSize)
+	buf := make([]byte, packetSize)
+
+	for seq := 0; seq < c.opts.EchoCount; seq++ {
+		if seq > 0 {
+			select {
+			case <-ctx.Done():
+				return quality.Stats{}, ctx.Err()
+			case <-time.After(echoInterval):
+			}
+		}
+
+		udpHeader{typ: packetEcho, session: c.session, seq: uint64(seq)}.marshalTo(packet)
+		sentAt := time.Now()
+		if _, err := c.udpConn.Write(packet); err != nil {
+			return quality.Stats{}, fmt.Errorf("send probe: %w", err)
+		}
+
+		if c.waitEchoReply(buf, uint64(seq), sentAt) {
+			window.Add(time.Since(sentAt))
+		} else {
+			window.AddLost()
+		}
+	}
+	return window.Stats(), nil
+}
+
+// waitEchoReply reads until the reply of the probe with the given sequence number arrives or the probe times out
+func (c *client) waitEchoReply(buf []byte, seq uint64, sentAt time.Time) bool {
+	_ = c.udpConn.SetReadDeadline(sentAt.Add(echoTimeout))
+	defer func() {
+		_ = c.udpConn.SetReadDeadline(time.Time{})
+	}()
+
+	for {
+		n, err := c.udpConn.Read(buf)
+		if err != nil {
+			return false
+		}
+		header, ok := parseUDPHeader(buf[:n])
+		if ok && header.typ == packetEchoReply && header.session == c.session && header.seq == seq {
+			return true
+		}
+	}
+}
+
+// start opens a TCP connection for a throughput test and sends the request
+func (c *client) start(ctx context.Context, req request) (net.Conn, error) {
+	conn, err := c.dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial tcp: %w", err)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+
+	if err := c.sendRequest(conn, req); err != nil {
+		stop()
+		_ = conn.Close()
+		return nil, err
+	}
+	return &ctxConn{Conn: conn, stop: stop}, nil
+}
+
+func (c *client) sendRequest(conn net.Conn, req request) error {
+	_ = conn.SetDeadline(time.Now().Add(req.duration + testTimeoutMargin))
+
+	if _, err := conn.Write(req.marshal()); err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+
+	resp := make([]byte, 1)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	switch status(resp[0]) {
+	case statusOK:
+		return nil
+	case statusBusy:
+		return ErrBusy
+	default:
+		return errors.New("request rejected by the speed test responder")
+	}
+}
+
+func (c *client) tcpUpload(ctx context.Context) (Throughput, error) {
+	conn, err := c.start(ctx, request{test: testTCPUpload, duration: c.opts.Duration})
+	if err != nil {
+		return Throughput{}, err
+	}
+	defer conn.Close()
+
+	buf := make([]byte, 128*1024)
+	deadline := time.Now().Add(c.opts.Duration)
+	for time.Now().Before(deadline) {
+		if _, err := conn.Write(buf); err != nil {
+			return Throughput{}, fmt.Errorf("send: %w", err)
+		}
+	}
+	if err := closeWrite(conn); err != nil {
+		return Throughput{}, err
+	}
+
+	result, err := readCounters(conn)
+	if err != nil {
+		return Throughput{}, err
+	}
+	return Throughput{Bytes: result.bytes, Duration: result.elapsed}, nil
+}
+
+func (c *client) tcpDownload(ctx context.Context) (Throughput, error) {
+	conn, err := c.start(ctx, request{test: testTCPDownload, duration: c.opts.Duration})
+	if err != nil {
+		return Throughput{}, err
+	}
+	defer conn.Close()
+
+	buf := make([]byte, 128*1024)
+	var result Throughput
+	var start time.Time
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			if start.IsZero() {
+				start = time.Now()
+			}
+			result.Bytes += uint64(n)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Throughput{}, fmt.Errorf("receive: %w", err)
+		}
+	}
+	if !start.IsZero() {
+		result.Duration = time.Since(start)
+	}
+	return result, nil
+}
+
+func (c *client) udpUpload(ctx context.Context, bitrate uint64) (Throughput, error) {
+	conn, err := c.start(ctx, request{test: testUDPUpload, duration: c.opts.Duration, bitrate: bitrate, session: c.session})
+	if err != nil {
+		return Throughput{}, err
+	}
+	defer conn.Close()
+
+	buf := make([]byte, packetSize)
+	p := &pacer{start: time.Now(), bitrate: bitrate}
+	var sent uint64
+	for time.Since(p.start) < c.opts.Duration {
+		if err := ctx.Err(); err != nil {
+			return Throughput{}, err
+		}
+		p.wait(packetSize)
+		udpHeader{typ: packetData, session: c.session, seq: sent}.marshalTo(buf)
+		if _, err := c.udpConn.Write(buf); err != nil {
+			// the send buffer may be full at high rates, the packet counts as lost
+			sent++
+			continue
+		}
+		sent++
+	}
+
+	// signal the end of the test
+	if _, err := conn.Write([]byte{0}); err != nil {
+		return Throughput{}, fmt.Errorf("send end of test: %w", err)
+	}
+
+	result, err := readCounters(conn)
+	if err != nil {
+		return Throughput{}, err
+	}
+	return Throughput{
+		Bytes:    result.bytes,
+		Duration: result.elapsed,
+		Loss:     lossRatio(sent, result.packets),
+	}, nil
+}
+
+func (c *client) udpDownload(ctx context.Context, bitrate uint64) (Throughput, error) {
+	localAddr, err := netip.ParseAddrPort(c.udpConn.LocalAddr().String())
+	if err != nil {
+		return Throughput{}, fmt.Errorf("parse local address: %w", err)
+	}
+
+	received := make(chan counters, 1)
+	go func() {
+		received <- c.receiveUDP()
+	}()
+
+	conn, err := c.start(ctx, request{
+		test:     testUDPDownload,
+		udpPort:  localAddr.Port(),
+		duration: c.opts.Duration,
+		bitrate:  bitrate,
+		session:  c.session,
+	})
+	if err != nil {
+		_ = c.udpConn.SetReadDeadline(time.Now())
+		<-received
+		return Throughput{}, err
+	}
+	defer conn.Close()
+
+	sent, err := readCounters(conn)
+	// stop receiving once the packets still in flight had time to arrive
+	_ = c.udpConn.SetReadDeadline(time.Now().Add(udpDrainTimeout))
+	result := <-received
+	_ = c.udpConn.SetReadDeadline(time.Time{})
+	if err != nil {
+		return Throughput{}, err
+	}
+
+	return Throughput{
+		Bytes:    result.bytes,
+		Duration: result.elapsed,
+		Loss:     lossRatio(sent.packets, result.packets),
+	}, nil
+}
+
+// receiveUDP counts the data packets of the session until the read deadline of the UDP connection is reached
+func (c *client) receiveUDP() counters {
+	buf := make([]byte, 65535)
+	var result counters
+	var first, last time.Time
+	for {
+		n, err := c.udpConn.Read(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() || errors.Is(err, net.ErrClosed) {
+				break
+			}
+			continue
+		}
+
+		header, ok := parseUDPHeader(buf[:n])
+		if !ok || header.typ != packetData || header.session != c.session {
+			continue
+		}
+		now := time.Now()
+		if first.IsZero() {
+			first = now
+		}
+		last = now
+		result.packets++
+		result.bytes += uint64(n)
+	}
+	result.elapsed = last.Sub(first)
+	return result
+}
+
+func lossRatio(sent, received uint64) float64 {
+	if sent == 0 || received >= sent {
+		return 0
+	}
+	return float64(sent-received) / float64(sent)
+}
+
+func closeWrite(conn net.Conn) error {
+	if cc, ok := conn.(*ctxConn); ok {
+		conn = cc.Conn
+	}
+	cw, ok := conn.(interface{ CloseWrite() error })
+	if !ok {
+		return errors.New("connection does not support half-close")
+	}
+	if err := cw.CloseWrite(); err != nil {
+		return fmt.Errorf("close write: %w", err)
+	}
+	return nil
+}
+
+// ctxConn releases the context watch of a test connection on close
+type ctxConn struct {
+	net.Conn
+	stop func() bool
+}
+
+func (c *ctxConn) Close() error {
+	c.stop()
+	return c.Conn.Close()
+}
@@ -0,0 +1,23 @@
+package speedtest
+
+import (
+	"os"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const EnvDisableResponder = "NB_DISABLE_SPEEDTEST_RESPONDER"
+
+func IsResponderDisabledByEnv() bool {
+	val := os.Getenv(EnvDisableResponder)
+	if val == "" {
+		return false
+	}
+	disabled, err := strconv.ParseBool(val)
+	if err != nil {
+		log.Warnf("failed to parse %s: %v", EnvDisableResponder, err)
+		return false
+	}
+	return disabled
+}
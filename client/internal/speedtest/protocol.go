@@ -0,0 +1,206 @@
+package speedtest
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Port is the TCP and UDP port the speed test responder listens on the NetBird address.
+// Peers can only reach it if an access policy allows the traffic.
+const Port = 22023
+
+const (
+	// MaxDuration limits the duration of a single throughput test
+	MaxDuration = 30 * time.Second
+	// MaxBitrate limits the rate of the UDP throughput tests in bits per second
+	MaxBitrate = 10_000_000_000
+
+	protocolVersion = 1
+
+	// packetSize is the UDP payload size, it fits into the default tunnel MTU
+	packetSize = 1200
+	// udpHeaderSize is the size of the header at the start of each UDP packet
+	udpHeaderSize = 24
+	// requestSize is the size of the request a client sends at the start of each TCP connection
+	requestSize = 28
+	// udpDrainTimeout is the time waited for UDP packets still in flight after a test ended
+	udpDrainTimeout = 500 * time.Millisecond
+)
+
+var magic = [4]byte{'N', 'B', 'S', 'T'}
+
+// ErrBusy is returned when the responder is already running a throughput test
+var ErrBusy = errors.New("speed test responder is busy")
+
+type testType uint8
+
+const (
+	testTCPUpload testType = iota + 1
+	testTCPDownload
+	testUDPUpload
+	testUDPDownload
+)
+
+func (t testType) String() string {
+	switch t {
+	case testTCPUpload:
+		return "TCP upload"
+	case testTCPDownload:
+		return "TCP download"
+	case testUDPUpload:
+		return "UDP upload"
+	case testUDPDownload:
+		return "UDP download"
+	default:
+		return fmt.Sprintf("unknown(%d)", t)
+	}
+}
+
+type status uint8
+
+const (
+	statusOK status = iota
+	statusBusy
+	statusInvalid
+)
+
+type packetType uint8
+
+const (
+	packetEcho packetType = iota + 1
+	packetEchoReply
+	packetData
+)
+
+// request starts a throughput test on a TCP connection
+type request struct {
+	test     testType
+	udpPort  uint16
+	duration time.Duration
+	bitrate  uint64
+	session  uint64
+}
+
+func (r request) marshal() []byte {
+	buf := make([]byte, requestSize)
+	copy(buf[0:4], magic[:])
+	buf[4] = protocolVersion
+	buf[5] = byte(r.test)
+	binary.BigEndian.PutUint16(buf[6:8], r.udpPort)
+	binary.BigEndian.PutUint32(buf[8:12], uint32(r.duration.Milliseconds()))
+	binary.BigEndian.PutUint64(buf[12:20], r.bitrate)
+	binary.BigEndian.PutUint64(buf[20:28], r.session)
+	return buf
+}
+
+func readRequest(r io.Reader) (request, error) {
+	buf := make([]byte, requestSize)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return request{}, fmt.Errorf("read request: %w", err)
+	}
+	if [4]byte(buf[0:4]) != magic {
+		return request{}, errors.New("invalid request magic")
+	}
+	if buf[4] != protocolVersion {
+		return request{}, fmt.Errorf("unsupported protocol version %d", buf[4])
+	}
+
+	req := request{
+		test:     testType(buf[5]),
+		udpPort:  binary.BigEndian.Uint16(buf[6:8]),
+		duration: time.Duration(binary.BigEndian.Uint32(buf[8:12])) * time.Millisecond,
+		bitrate:  binary.BigEndian.Uint64(buf[12:20]),
+		session:  binary.BigEndian.Uint64(buf[20:28]),
+	}
+	return req, req.validate()
+}
+
+func (r request) validate() error {
+	if r.test < testTCPUpload || r.test > testUDPDownload {
+		return fmt.Errorf("unknown test %s", r.test)
+	}
+	if r.duration <= 0 || r.duration > MaxDuration {
+		return fmt.Errorf("invalid duration %s", r.duration)
+	}
+	if r.test == testUDPUpload || r.test == testUDPDownload {
+		if r.bitrate == 0 || r.bitrate > MaxBitrate {
+			return fmt.Errorf("invalid bitrate %d", r.bitrate)
+		}
+	}
+	if r.test == testUDPDownload && r.udpPort == 0 {
+		return errors.New("missing UDP port")
+	}
+	return nil
+}
+
+// udpHeader is the header at the start of each UDP packet
+type udpHeader struct {
+	typ     packetType
+	session uint64
+	seq     uint64
+}
+
+func (h udpHeader) marshalTo(buf []byte) {
+	copy(buf[0:4], magic[:])
+	buf[4] = byte(h.typ)
+	buf[5], buf[6], buf[7] = 0, 0, 0
+	binary.BigEndian.PutUint64(buf[8:16], h.session)
+	binary.BigEndian.PutUint64(buf[16:24], h.seq)
+}
+
+func parseUDPHeader(buf []byte) (udpHeader, bool) {
+	if len(buf) < udpHeaderSize || [4]byte(buf[0:4]) != magic {
+		return udpHeader{}, false
+	}
+	return udpHeader{
+		typ:     packetType(buf[4]),
+		session: binary.BigEndian.Uint64(buf[8:16]),
+		seq:     binary.BigEndian.Uint64(buf[16:24]),
+	}, true
+}
+
+// counters are the results a responder reports at the end of a test
+type counters struct {
+	packets uint64
+	bytes   uint64
+	elapsed time.Duration
+}
+
+func (c counters) marshal() []byte {
+	buf := make([]byte, 24)
+	binary.BigEndian.PutUint64(buf[0:8], c.packets)
+	binary.BigEndian.PutUint64(buf[8:16], c.bytes)
+	binary.BigEndian.PutUint64(buf[16:24], uint64(c.elapsed))
+	return buf
+}
+
+func readCounters(r io.Reader) (counters, error) {
+	buf := make([]byte, 24)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return counters{}, fmt.Errorf("read counters: %w", err)
+	}
+	return counters{
+		packets: binary.BigEndian.Uint64(buf[0:8]),
+		bytes:   binary.BigEndian.Uint64(buf[8:16]),
+		elapsed: time.Duration(binary.BigEndian.Uint64(buf[16:24])),
+	}, nil
+}
+
+// pacer spreads the packets of a UDP test over its duration to match the requested bitrate
+type pacer struct {
+	start   time.Time
+	bitrate uint64
+	sent    uint64
+}
+
+// wait blocks until the next packet of the given size may be sent
+func (p *pacer) wait(size int) {
+	due := time.Duration(float64(p.sent*8) / float64(p.bitrate) * float64(time.Second))
+	if ahead := due - time.Since(p.start); ahead > time.Millisecond {
+		time.Sleep(ahead)
+	}
+	p.sent += uint64(size)
+}
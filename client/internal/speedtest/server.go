@@ -0,0 +1,362 @@
+package speedtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.zx2c4.com/wireguard/tun/netstack"
+)
+
+const (
+	requestTimeout = 5 * time.Second
+	// testTimeoutMargin is added to the test duration for the deadline of the whole test connection
+	testTimeoutMargin = 10 * time.Second
+)
+
+// udpSession counts the packets of a running UDP upload test
+type udpSession struct {
+	ip       netip.Addr
+	counters counters
+	first    time.Time
+	last     time.Time
+}
+
+// Server is the speed test responder. It answers round trip probes and runs one throughput test at a time.
+type Server struct {
+	// allowed reports whether the source address is a peer of the local network
+	allowed     func(netip.Addr) bool
+	netstackNet *netstack.Net
+
+	mu       sync.Mutex
+	listener net.Listener
+	udpConn  net.PacketConn
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	busy       atomic.Bool
+	sessionsMu sync.Mutex
+	sessions   map[uint64]*udpSession
+}
+
+// NewServer creates a responder that accepts connections only from addresses the allowed function approves
+func NewServer(allowed func(netip.Addr) bool) *Server {
+	return &Server{
+		allowed:  allowed,
+		sessions: make(map[uint64]*udpSession),
+	}
+}
+
+// SetNetstackNet makes the responder listen on the userspace network stack
+func (s *Server) SetNetstackNet(net *netstack.Net) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.netstackNet = net
+}
+
+// Start listens on the given address for TCP and UDP
+func (s *Server) Start(ctx context.Context, addr netip.AddrPort) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener != nil {
+		return errors.New("speed test responder already running")
+	}
+
+	listener, udpConn, err := s.listen(ctx, addr)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.listener = listener
+	s.udpConn = udpConn
+	s.cancel = cancel
+
+	s.wg.Add(2)
+	go func() {
+		defer s.wg.Done()
+		s.acceptLoop(ctx, listener)
+	}()
+	go func() {
+		defer s.wg.Done()
+		s.readLoop(udpConn)
+	}()
+
+	log.Infof("speed test responder listening on %s", addr)
+	return nil
+}
+
+func (s *Server) listen(ctx context.Context, addr netip.AddrPort) (net.Listener, net.PacketConn, error) {
+	if s.netstackNet != nil {
+		listener, err := s.netstackNet.ListenTCPAddrPort(addr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("listen tcp on netstack: %w", err)
+		}
+		udpConn, err := s.netstackNet.ListenUDPAddrPort(addr)
+		if err != nil {
+			_ = listener.Close()
+			return nil, nil, fmt.Errorf("listen udp on netstack: %w", err)
+		}
+		return listener, udpConn, nil
+	}
+
+	lc := net.ListenConfig{}
+	listener, err := lc.Listen(ctx, "tcp", addr.String())
+	if err != nil {
+		return nil, nil, fmt.Errorf("listen tcp: %w", err)
+	}
+	udpConn, err := lc.ListenPacket(ctx, "udp", addr.String())
+	if err != nil {
+		_ = listener.Close()
+		return nil, nil, fmt.Errorf("listen udp: %w", err)
+	}
+	return listener, udpConn, nil
+}
+
+// Stop closes the listeners and the running tests
+func (s *Server) Stop() error {
+	s.mu.Lock()
+	if s.listener == nil {
+		s.mu.Unlock()
+		return nil
+	}
+
+	s.cancel()
+	var merr error
+	if err := s.listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		merr = errors.Join(merr, fmt.Errorf("close tcp listener: %w", err))
+	}
+	if err := s.udpConn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		merr = errors.Join(merr, fmt.Errorf("close udp conn: %w", err))
+	}
+	s.listener = nil
+	s.udpConn = nil
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return merr
+}
+
+func (s *Server) acceptLoop(ctx context.Context, listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+				log.Errorf("speed test responder: accept: %v", err)
+			}
+			return
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handleConn(ctx, conn)
+		}()
+	}
+}
+
+func (s *Server) handleConn(ctx context.Context, conn net.Conn) {
+	defer func() {
+		if err := conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			log.Debugf("speed test responder: close conn: %v", err)
+		}
+	}()
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stop()
+
+	remote, err := netip.ParseAddrPort(conn.RemoteAddr().String())
+	if err != nil || !s.allowed(remote.Addr().Unmap()) {
+		log.Debugf("speed test responder: rejected connection from %s", conn.RemoteAddr())
+		return
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(requestTimeout))
+	req, err := readRequest(conn)
+	if err != nil {
+		log.Debugf("speed test responder: invalid request from %s: %v", remote, err)
+		_, _ = conn.Write([]byte{byte(statusInvalid)})
+		return
+	}
+
+	if !s.busy.CompareAndSwap(false, true) {
+		_, _ = conn.Write([]byte{byte(statusBusy)})
+		return
+	}
+	defer s.busy.Store(false)
+
+	_ = conn.SetDeadline(time.Now().Add(req.duration + testTimeoutMargin))
+	if _, err := conn.Write([]byte{byte(statusOK)}); err != nil {
+		return
+	}
+
+	log.Debugf("speed test responder: running %s test for %s", req.test, remote)
+	switch req.test {
+	case testTCPUpload:
+		err = s.receiveTCP(conn)
+	case testTCPDownload:
+		err = sendTCP(conn, req.duration)
+	case testUDPUpload:
+		err = s.receiveUDP(conn, remote.Addr().Unmap(), req)
+	case testUDPDownload:
+		err = s.sendUDP(conn, netip.AddrPortFrom(remote.Addr().Unmap(), req.udpPort), req)
+	}
+	if err != nil {
+		log.Debugf("speed test responder: %s test for %s: %v", req.test, remote, err)
+	}
+}
+
+func (s *Server) receiveTCP(conn net.Conn) error {
+	buf := make([]byte, 128*1024)
+	var c counters
+	var start time.Time
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			if start.IsZero() {
+				start = time.Now()
+			}
+			c.bytes += uint64(n)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("receive: %w", err)
+		}
+	}
+	if !start.IsZero() {
+		c.elapsed = time.Since(start)
+	}
+
+	_, err := conn.Write(c.marshal())
+	return err
+}
+
+func sendTCP(conn net.Conn, duration time.Duration) error {
+	buf := make([]byte, 128*1024)
+	deadline := time.Now().Add(duration)
+	for time.Now().Before(deadline) {
+		if _, err := conn.Write(buf); err != nil {
+			return fmt.Errorf("send: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Server) receiveUDP(conn net.Conn, ip netip.Addr, req request) error {
+	s.sessionsMu.Lock()
+	s.sessions[req.session] = &udpSession{ip: ip}
+	s.sessionsMu.Unlock()
+
+	defer func() {
+		s.sessionsMu.Lock()
+		delete(s.sessions, req.session)
+		s.sessionsMu.Unlock()
+	}()
+
+	// the client signals the end of the test
+	if _, err := io.ReadFull(conn, make([]byte, 1)); err != nil {
+		return fmt.Errorf("wait for end of test: %w", err)
+	}
+	time.Sleep(udpDrainTimeout)
+
+	s.sessionsMu.Lock()
+	session := s.sessions[req.session]
+	c := session.counters
+	c.elapsed = session.last.Sub(session.first)
+	s.sessionsMu.Unlock()
+
+	_, err := conn.Write(c.marshal())
+	return err
+}
+
+func (s *Server) sendUDP(conn net.Conn, dst netip.AddrPort, req request) error {
+	s.mu.Lock()
+	udpConn := s.udpConn
+	s.mu.Unlock()
+	if udpConn == nil {
+		return net.ErrClosed
+	}
+
+	buf := make([]byte, packetSize)
+	dstAddr := net.UDPAddrFromAddrPort(dst)
+	p := &pacer{start: time.Now(), bitrate: req.bitrate}
+
+	var c counters
+	for time.Since(p.start) < req.duration {
+		p.wait(packetSize)
+		udpHeader{typ: packetData, session: req.session, seq: c.packets}.marshalTo(buf)
+		if _, err := udpConn.WriteTo(buf, dstAddr); err != nil {
+			return fmt.Errorf("send: %w", err)
+		}
+		c.packets++
+		c.bytes += packetSize
+	}
+	c.elapsed = time.Since(p.start)
+
+	_, err := conn.Write(c.marshal())
+	return err
+}
+
+func (s *Server) readLoop(conn net.PacketConn) {
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Debugf("speed test responder: read udp: %v", err)
+			}
+			return
+		}
+
+		header, ok := parseUDPHeader(buf[:n])
+		if !ok {
+			continue
+		}
+		src, err := netip.ParseAddrPort(addr.String())
+		if err != nil {
+			continue
+		}
+
+		switch header.typ {
+		case packetEcho:
+			if !s.allowed(src.Addr().Unmap()) {
+				continue
+			}
+			buf[4] = byte(packetEchoReply)
+			if _, err := conn.WriteTo(buf[:n], addr); err != nil {
+				log.Debugf("speed test responder: echo reply to %s: %v", addr, err)
+			}
+		case packetData:
+			s.countPacket(header.session, src.Addr().Unmap(), n)
+		}
+	}
+}
+
+func (s *Server) countPacket(sessionID uint64, ip netip.Addr, size int) {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+
+	session, ok := s.sessions[sessionID]
+	if !ok || session.ip != ip {
+		return
+	}
+
+	now := time.Now()
+	if session.first.IsZero() {
+		session.first = now
+	}
+	session.last = now
+	session.counters.packets++
+	session.counters.bytes += uint64(size)
+}
@@ -0,0 +1,155 @@
+package speedtest
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startTestServer starts a responder on a free loopback port for TCP and UDP
+func startTestServer(t *testing.T, allowed func(netip.Addr) bool) netip.AddrPort {
+	t.Helper()
+
+	var lastErr error
+	for i := 0; i < 10; i++ {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := netip.MustParseAddrPort(l.Addr().String())
+		require.NoError(t, l.Close())
+
+		server := NewServer(allowed)
+		if lastErr = server.Start(context.Background(), addr); lastErr != nil {
+			continue
+		}
+		t.Cleanup(func() {
+			require.NoError(t, server.Stop())
+		})
+		return addr
+	}
+	t.Fatalf("start speed test responder: %v", lastErr)
+	return netip.AddrPort{}
+}
+
+func allowAll(netip.Addr) bool { return true }
+
+func TestRun(t *testing.T) {
+	addr := startTestServer(t, allowAll)
+
+	result, err := Run(context.Background(), &net.Dialer{}, addr, Options{
+		Duration:   300 * time.Millisecond,
+		UDPBitrate: 10_000_000,
+		EchoCount:  3,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.RTT.Samples)
+	assert.Equal(t, 3, result.RTT.Received)
+	assert.Zero(t, result.RTT.Loss)
+
+	for name, throughput := range map[string]Throughput{
+		"tcp upload":   result.TCPUpload,
+		"tcp download": result.TCPDownload,
+		"udp upload":   result.UDPUpload,
+		"udp download": result.UDPDownload,
+	} {
+		assert.NotZero(t, throughput.Bytes, name)
+		assert.Greater(t, throughput.BitsPerSecond(), float64(0), name)
+	}
+
+	// loopback does not drop packets at this rate, but leave room for slow test machines
+	assert.Less(t, result.UDPUpload.Loss, 0.5)
+	assert.Less(t, result.UDPDownload.Loss, 0.5)
+}
+
+func TestRun_NotAllowed(t *testing.T) {
+	addr := startTestServer(t, func(netip.Addr) bool { return false })
+
+	_, err := Run(context.Background(), &net.Dialer{}, addr, Options{
+		Duration:  100 * time.Millisecond,
+		EchoCount: 1,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no response")
+}
+
+func TestRun_InvalidOptions(t *testing.T) {
+	addr := netip.MustParseAddrPort("127.0.0.1:1")
+
+	_, err := Run(context.Background(), &net.Dialer{}, addr, Options{Duration: MaxDuration + time.Second})
+	assert.Error(t, err)
+
+	_, err = Run(context.Background(), &net.Dialer{}, addr, Options{UDPBitrate: MaxBitrate + 1})
+	assert.Error(t, err)
+}
+
+func TestServer_Busy(t *testing.T) {
+	addr := startTestServer(t, allowAll)
+
+	c := &client{dialer: &net.Dialer{}, addr: addr.String(), opts: Options{Duration: time.Second}}
+	first, err := c.start(context.Background(), request{test: testTCPDownload, duration: time.Second})
+	require.NoError(t, err)
+	defer first.Close()
+
+	_, err = c.start(context.Background(), request{test: testTCPDownload, duration: time.Second})
+	assert.ErrorIs(t, err, ErrBusy)
+}
+
+func TestRequest_Marshal(t *testing.T) {
+	req := request{
+		test:     testUDPDownload,
+		udpPort:  51820,
+		duration: 5 * time.Second,
+		bitrate:  100_000_000,
+		session:  42,
+	}
+
+	parsed, err := readRequest(bytes.NewReader(req.marshal()))
+	require.NoError(t, err)
+	assert.Equal(t, req, parsed)
+}
+
+func TestRequest_Validate(t *testing.T) {
+	valid := request{test: testUDPDownload, udpPort: 51820, duration: time.Second, bitrate: 1_000_000}
+	require.NoError(t, valid.validate())
+
+	tests := []struct {
+		name   string
+		modify func(r *request)
+	}{
+		{name: "unknown test", modify: func(r *request) { r.test = 0 }},
+		{name: "zero duration", modify: func(r *request) { r.duration = 0 }},
+		{name: "duration too long", modify: func(r *request) { r.duration = MaxDuration + time.Second }},
+		{name: "zero bitrate", modify: func(r *request) { r.bitrate = 0 }},
+		{name: "bitrate too high", modify: func(r *request) { r.bitrate = MaxBitrate + 1 }},
+		{name: "missing UDP port", modify: func(r *request) { r.udpPort = 0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.modify(&req)
+			assert.Error(t, req.validate())
+		})
+	}
+}
+
+func TestReadRequest_InvalidMagic(t *testing.T) {
+	buf := request{test: testTCPUpload, duration: time.Second}.marshal()
+	buf[0] = 'X'
+
+	_, err := readRequest(bytes.NewReader(buf))
+	assert.Error(t, err)
+}
+
+func TestLossRatio(t *testing.T) {
+	assert.Zero(t, lossRatio(0, 0))
+	assert.Zero(t, lossRatio(10, 10))
+	assert.Zero(t, lossRatio(10, 12))
+	assert.InDelta(t, 0.25, lossRatio(100, 75), 0.0001)
+}
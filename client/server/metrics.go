@@ -0,0 +1,24 @@
+package server
+
+import (
+	"github.com/netbirdio/netbird/client/internal/metrics"
+)
+
+// MetricsCollector returns a Prometheus collector exporting the daemon connection state
+func (s *Server) MetricsCollector() *metrics.Collector {
+	return metrics.NewCollector(s.statusRecorder, s)
+}
+
+// FirewallRuleCounts returns the number of peer and route ACL rules applied by the running engine
+func (s *Server) FirewallRuleCounts() (peerRules, routeRules int) {
+	s.mutex.Lock()
+	connectClient := s.connectClient
+	s.mutex.Unlock()
+
+	engine := connectClient.Engine()
+	if engine == nil {
+		return 0, 0
+	}
+
+	return engine.FirewallRuleCounts()
+}
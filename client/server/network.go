@@ -9,17 +9,23 @@ import (
 	"strings"
 
 	"golang.org/x/exp/maps"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/netbirdio/netbird/client/internal/routemanager/client"
+	"github.com/netbirdio/netbird/client/internal/routemanager/vars"
+	"github.com/netbirdio/netbird/client/internal/routeselector"
 	"github.com/netbirdio/netbird/client/proto"
 	"github.com/netbirdio/netbird/route"
 	"github.com/netbirdio/netbird/shared/management/domain"
 )
 
 type selectRoute struct {
-	NetID    route.NetID
-	Network  netip.Prefix
-	Domains  domain.List
-	Selected bool
+	NetID       route.NetID
+	Network     netip.Prefix
+	Domains     domain.List
+	Selected    bool
+	SplitTunnel *routeselector.SplitTunnel
 }
 
 // ListNetworks returns a list of all available networks.
@@ -55,6 +61,9 @@ func (s *Server) ListNetworks(context.Context, *proto.ListNetworksRequest) (*pro
 			Domains:  rt[0].Domains,
 			Selected: routeSelector.IsSelected(id),
 		}
+		if splitTunnel, ok := routeSelector.GetSplitTunnel(id); ok {
+			route.SplitTunnel = &splitTunnel
+		}
 		routes = append(routes, route)
 	}
 
@@ -82,6 +91,7 @@ func (s *Server) ListNetworks(context.Context, *proto.ListNetworksRequest) (*pro
 			Domains:     route.Domains.ToSafeStringList(),
 			ResolvedIPs: map[string]*proto.IPList{},
 			Selected:    route.Selected,
+			SplitTunnel: toProtoSplitTunnel(route.SplitTunnel),
 		}
 
 		// Group resolved IPs by their parent domain
@@ -205,6 +215,176 @@ func (s *Server) DeselectNetworks(_ context.Context, req *proto.SelectNetworksRe
 	return &proto.SelectNetworksResponse{}, nil
 }
 
+// SetSplitTunnel configures the split tunnel of an exit node network or removes it.
+func (s *Server) SetSplitTunnel(_ context.Context, req *proto.SetSplitTunnelRequest) (*proto.SetSplitTunnelResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.connectClient == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	engine := s.connectClient.Engine()
+	if engine == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	routeManager := engine.GetRouteManager()
+	if routeManager == nil {
+		return nil, fmt.Errorf("no route manager")
+	}
+
+	netID := route.NetID(req.GetNetworkID())
+	routeSelector := routeManager.GetRouteSelector()
+	if req.GetSplitTunnel() == nil {
+		routeSelector.RemoveSplitTunnel(netID)
+	} else {
+		splitTunnel, err := toSplitTunnel(req.GetSplitTunnel())
+		if err != nil {
+			return nil, err
+		}
+		if err := routeSelector.SetSplitTunnel(netID, splitTunnel, exitNodeIDs(routeManager.GetClientRoutesWithNetID())); err != nil {
+			return nil, fmt.Errorf("set split tunnel: %w", err)
+		}
+	}
+	routeManager.TriggerSelection(routeManager.GetClientRoutes())
+
+	s.statusRecorder.PublishEvent(
+		proto.SystemEvent_INFO,
+		proto.SystemEvent_SYSTEM,
+		"Exit node split tunnel changed",
+		"",
+		map[string]string{
+			"network": req.GetNetworkID(),
+			"mode":    req.GetSplitTunnel().GetMode(),
+		},
+	)
+
+	return &proto.SetSplitTunnelResponse{}, nil
+}
+
+// GetRouteSelection explains the routing peer selection of the networks.
+func (s *Server) GetRouteSelection(_ context.Context, req *proto.GetRouteSelectionRequest) (*proto.GetRouteSelectionResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.connectClient == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	engine := s.connectClient.Engine()
+	if engine == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	routeManager := engine.GetRouteManager()
+	if routeManager == nil {
+		return nil, fmt.Errorf("no route manager")
+	}
+
+	netIDs := toNetIDs(req.GetNetworkIDs())
+	resp := &proto.GetRouteSelectionResponse{}
+	for _, selection := range routeManager.GetRouteSelections() {
+		if len(netIDs) > 0 && !slices.Contains(netIDs, selection.NetID) {
+			continue
+		}
+		resp.Selections = append(resp.Selections, s.toProtoRouteSelection(selection))
+	}
+
+	return resp, nil
+}
+
+func (s *Server) toProtoRouteSelection(selection client.Selection) *proto.RouteSelection {
+	pbSelection := &proto.RouteSelection{
+		NetworkID:     string(selection.NetID),
+		Network:       selection.Network,
+		ChosenRouteID: string(selection.Chosen),
+		LoadBalanced:  selection.LoadBalanced,
+	}
+
+	for _, id := range selection.Balanced {
+		pbSelection.BalancedRouteIDs = append(pbSelection.BalancedRouteIDs, string(id))
+	}
+
+	for _, candidate := range selection.Candidates {
+		pbCandidate := &proto.RouteCandidate{
+			RouteID:    string(candidate.RouteID),
+			Peer:       candidate.Peer,
+			Metric:     int32(candidate.Metric),
+			ConnStatus: candidate.Status.String(),
+			Relayed:    candidate.Relayed,
+			Latency:    durationpb.New(candidate.Latency),
+			Healthy:    candidate.Healthy,
+			Score:      candidate.Score,
+			Available:  candidate.Available,
+			Chosen:     candidate.RouteID == selection.Chosen || slices.Contains(selection.Balanced, candidate.RouteID),
+		}
+		if state, err := s.statusRecorder.GetPeer(candidate.Peer); err == nil {
+			pbCandidate.Fqdn = state.FQDN
+		}
+		pbSelection.Candidates = append(pbSelection.Candidates, pbCandidate)
+	}
+
+	for _, routeSwitch := range selection.Switches {
+		pbSelection.Switches = append(pbSelection.Switches, &proto.RouteSwitch{
+			Time:     timestamppb.New(routeSwitch.Time),
+			FromPeer: routeSwitch.From,
+			ToPeer:   routeSwitch.To,
+			Reason:   routeSwitch.Reason,
+		})
+	}
+
+	return pbSelection
+}
+
+func toSplitTunnel(splitTunnel *proto.SplitTunnel) (routeselector.SplitTunnel, error) {
+	result := routeselector.SplitTunnel{Mode: routeselector.SplitTunnelMode(splitTunnel.GetMode())}
+
+	if len(splitTunnel.GetDomains()) > 0 {
+		domains, err := domain.ValidateDomains(splitTunnel.GetDomains())
+		if err != nil {
+			return result, fmt.Errorf("invalid domains: %w", err)
+		}
+		result.Domains = domains
+	}
+
+	for _, p := range splitTunnel.GetPrefixes() {
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			return result, fmt.Errorf("invalid prefix %s: %w", p, err)
+		}
+		result.Prefixes = append(result.Prefixes, prefix.Masked())
+	}
+	return result, nil
+}
+
+func toProtoSplitTunnel(splitTunnel *routeselector.SplitTunnel) *proto.SplitTunnel {
+	if splitTunnel == nil {
+		return nil
+	}
+
+	prefixes := make([]string, 0, len(splitTunnel.Prefixes))
+	for _, prefix := range splitTunnel.Prefixes {
+		prefixes = append(prefixes, prefix.String())
+	}
+	return &proto.SplitTunnel{
+		Mode:     string(splitTunnel.Mode),
+		Domains:  splitTunnel.Domains.ToSafeStringList(),
+		Prefixes: prefixes,
+	}
+}
+
+// exitNodeIDs returns the IDs of the networks routing the default route
+func exitNodeIDs(routes map[route.NetID][]*route.Route) []route.NetID {
+	var ids []route.NetID
+	for id, rt := range routes {
+		if len(rt) > 0 && rt[0].Network == vars.Defaultv4 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 func toNetIDs(routes []string) []route.NetID {
 	var netIDs []route.NetID
 	for _, rt := range routes {
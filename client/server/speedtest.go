@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/speedtest"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// SpeedTest measures the throughput and round trip time to a peer through the tunnel
+func (s *Server) SpeedTest(ctx context.Context, req *proto.SpeedTestRequest) (*proto.SpeedTestResponse, error) {
+	// the test takes several seconds, don't block other requests while it runs
+	s.mutex.Lock()
+	connectClient := s.connectClient
+	s.mutex.Unlock()
+
+	if connectClient == nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "connect client not initialized")
+	}
+	engine := connectClient.Engine()
+	if engine == nil {
+		return nil, gstatus.Errorf(codes.FailedPrecondition, "engine not initialized")
+	}
+
+	if req.GetPeer() == "" {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "peer is required")
+	}
+
+	opts := speedtest.Options{
+		Duration:   req.GetDuration().AsDuration(),
+		UDPBitrate: req.GetUdpBitrate(),
+	}
+
+	result, state, err := engine.RunSpeedTest(ctx, req.GetPeer(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("speed test: %w", err)
+	}
+
+	return speedTestResponse(result, state), nil
+}
+
+func speedTestResponse(result *speedtest.Result, state peer.State) *proto.SpeedTestResponse {
+	return &proto.SpeedTestResponse{
+		PeerIp:                     state.IP,
+		PeerFqdn:                   state.FQDN,
+		Rtt:                        peer.PathQualityToProto(result.RTT),
+		TcpUpload:                  throughputToProto(result.TCPUpload),
+		TcpDownload:                throughputToProto(result.TCPDownload),
+		UdpUpload:                  throughputToProto(result.UDPUpload),
+		UdpDownload:                throughputToProto(result.UDPDownload),
+		Relayed:                    state.Relayed,
+		RelayServerAddress:         state.RelayServerAddress,
+		LocalIceCandidateType:      state.LocalIceCandidateType,
+		RemoteIceCandidateType:     state.RemoteIceCandidateType,
+		LocalIceCandidateEndpoint:  state.LocalIceCandidateEndpoint,
+		RemoteIceCandidateEndpoint: state.RemoteIceCandidateEndpoint,
+	}
+}
+
+func throughputToProto(t speedtest.Throughput) *proto.SpeedTestThroughput {
+	return &proto.SpeedTestThroughput{
+		Bytes:    t.Bytes,
+		Duration: durationpb.New(t.Duration),
+		Loss:     t.Loss,
+	}
+}
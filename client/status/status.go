@@ -19,6 +19,7 @@ import (
 
 	"github.com/netbirdio/netbird/client/anonymize"
 	"github.com/netbirdio/netbird/client/internal/peer"
+	"github.com/netbirdio/netbird/client/internal/peer/quality"
 	probeRelay "github.com/netbirdio/netbird/client/internal/relay"
 	"github.com/netbirdio/netbird/client/proto"
 	"github.com/netbirdio/netbird/shared/management/domain"
@@ -26,21 +27,30 @@ import (
 )
 
 type PeerStateDetailOutput struct {
-	FQDN                   string           `json:"fqdn" yaml:"fqdn"`
-	IP                     string           `json:"netbirdIp" yaml:"netbirdIp"`
-	PubKey                 string           `json:"publicKey" yaml:"publicKey"`
-	Status                 string           `json:"status" yaml:"status"`
-	LastStatusUpdate       time.Time        `json:"lastStatusUpdate" yaml:"lastStatusUpdate"`
-	ConnType               string           `json:"connectionType" yaml:"connectionType"`
-	IceCandidateType       IceCandidateType `json:"iceCandidateType" yaml:"iceCandidateType"`
-	IceCandidateEndpoint   IceCandidateType `json:"iceCandidateEndpoint" yaml:"iceCandidateEndpoint"`
-	RelayAddress           string           `json:"relayAddress" yaml:"relayAddress"`
-	LastWireguardHandshake time.Time        `json:"lastWireguardHandshake" yaml:"lastWireguardHandshake"`
-	TransferReceived       int64            `json:"transferReceived" yaml:"transferReceived"`
-	TransferSent           int64            `json:"transferSent" yaml:"transferSent"`
-	Latency                time.Duration    `json:"latency" yaml:"latency"`
-	RosenpassEnabled       bool             `json:"quantumResistance" yaml:"quantumResistance"`
-	Networks               []string         `json:"networks" yaml:"networks"`
+	FQDN                   string             `json:"fqdn" yaml:"fqdn"`
+	IP                     string             `json:"netbirdIp" yaml:"netbirdIp"`
+	PubKey                 string             `json:"publicKey" yaml:"publicKey"`
+	Status                 string             `json:"status" yaml:"status"`
+	LastStatusUpdate       time.Time          `json:"lastStatusUpdate" yaml:"lastStatusUpdate"`
+	ConnType               string             `json:"connectionType" yaml:"connectionType"`
+	IceCandidateType       IceCandidateType   `json:"iceCandidateType" yaml:"iceCandidateType"`
+	IceCandidateEndpoint   IceCandidateType   `json:"iceCandidateEndpoint" yaml:"iceCandidateEndpoint"`
+	RelayAddress           string             `json:"relayAddress" yaml:"relayAddress"`
+	LastWireguardHandshake time.Time          `json:"lastWireguardHandshake" yaml:"lastWireguardHandshake"`
+	TransferReceived       int64              `json:"transferReceived" yaml:"transferReceived"`
+	TransferSent           int64              `json:"transferSent" yaml:"transferSent"`
+	Latency                time.Duration      `json:"latency" yaml:"latency"`
+	IcePathQuality         *PathQualityOutput `json:"icePathQuality,omitempty" yaml:"icePathQuality,omitempty"`
+	RelayPathQuality       *PathQualityOutput `json:"relayPathQuality,omitempty" yaml:"relayPathQuality,omitempty"`
+	RosenpassEnabled       bool               `json:"quantumResistance" yaml:"quantumResistance"`
+	Networks               []string           `json:"networks" yaml:"networks"`
+}
+
+type PathQualityOutput struct {
+	Latency time.Duration `json:"latency" yaml:"latency"`
+	Jitter  time.Duration `json:"jitter" yaml:"jitter"`
+	Loss    float64       `json:"loss" yaml:"loss"`
+	Samples int           `json:"samples" yaml:"samples"`
 }
 
 type PeersStateOutput struct {
@@ -300,6 +310,8 @@ func mapPeers(
 			TransferReceived:       transferReceived,
 			TransferSent:           transferSent,
 			Latency:                pbPeerState.GetLatency().AsDuration(),
+			IcePathQuality:         mapPathQuality(pbPeerState.GetIcePathQuality()),
+			RelayPathQuality:       mapPathQuality(pbPeerState.GetRelayPathQuality()),
 			RosenpassEnabled:       pbPeerState.GetRosenpassEnabled(),
 			Networks:               pbPeerState.GetNetworks(),
 		}
@@ -317,6 +329,18 @@ func mapPeers(
 	return peersOverview
 }
 
+func mapPathQuality(pathQuality *proto.PathQuality) *PathQualityOutput {
+	if pathQuality == nil {
+		return nil
+	}
+	return &PathQualityOutput{
+		Latency: pathQuality.GetLatency().AsDuration(),
+		Jitter:  pathQuality.GetJitter().AsDuration(),
+		Loss:    pathQuality.GetLoss(),
+		Samples: int(pathQuality.GetSamples()),
+	}
+}
+
 func sortPeersByIP(peersStateDetail []PeerStateDetailOutput) {
 	if len(peersStateDetail) > 0 {
 		sort.SliceStable(peersStateDetail, func(i, j int) bool {
@@ -605,6 +629,8 @@ func ToProtoFullStatus(fullStatus peer.FullStatus) *proto.FullStatus {
 			Networks:                   maps.Keys(peerState.GetRoutes()),
 			Latency:                    durationpb.New(peerState.Latency),
 			SshHostKey:                 peerState.SSHHostKey,
+			IcePathQuality:             toProtoPathQuality(peerState.ICEPathQuality),
+			RelayPathQuality:           toProtoPathQuality(peerState.RelayPathQuality),
 		}
 		pbFullStatus.Peers = append(pbFullStatus.Peers, pbPeerState)
 	}
@@ -708,7 +734,9 @@ func parsePeers(peers PeersStateOutput, rosenpassEnabled, rosenpassPermissive bo
 				"  Transfer status (received/sent) %s/%s\n"+
 				"  Quantum resistance: %s\n"+
 				"  Networks: %s\n"+
-				"  Latency: %s\n",
+				"  Latency: %s\n"+
+				"  P2P path quality: %s\n"+
+				"  Relayed path quality: %s\n",
 			domain.Domain(peerState.FQDN).SafeString(),
 			peerState.IP,
 			peerState.PubKey,
@@ -726,6 +754,8 @@ func parsePeers(peers PeersStateOutput, rosenpassEnabled, rosenpassPermissive bo
 			rosenpassEnabledStatus,
 			networks,
 			peerState.Latency.String(),
+			pathQualityString(peerState.IcePathQuality),
+			pathQualityString(peerState.RelayPathQuality),
 		)
 
 		peersString += peerString
@@ -733,6 +763,25 @@ func parsePeers(peers PeersStateOutput, rosenpassEnabled, rosenpassPermissive bo
 	return peersString
 }
 
+func toProtoPathQuality(stats quality.Stats) *proto.PathQuality {
+	if stats.Samples == 0 {
+		return nil
+	}
+	return &proto.PathQuality{
+		Latency: durationpb.New(stats.Latency),
+		Jitter:  durationpb.New(stats.Jitter),
+		Loss:    stats.Loss,
+		Samples: int32(stats.Samples),
+	}
+}
+
+func pathQualityString(pathQuality *PathQualityOutput) string {
+	if pathQuality == nil {
+		return "-"
+	}
+	return fmt.Sprintf("latency %s, jitter %s, loss %.1f%% (%d probes)", pathQuality.Latency, pathQuality.Jitter, pathQuality.Loss*100, pathQuality.Samples)
+}
+
 func skipDetailByFilters(peerState *proto.PeerState, peerStatus string, statusFilter string, prefixNamesFilter []string, prefixNamesFilterMap map[string]struct{}, ipsFilter map[string]struct{}, connectionTypeFilter, connType string) bool {
 	statusEval := false
 	ipEval := false
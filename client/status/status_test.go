@@ -48,6 +48,12 @@ var resp = &proto.StatusResponse{
 					"10.1.0.0/24",
 				},
 				Latency: durationpb.New(time.Duration(10000000)),
+				IcePathQuality: &proto.PathQuality{
+					Latency: durationpb.New(10 * time.Millisecond),
+					Jitter:  durationpb.New(2 * time.Millisecond),
+					Loss:    0.1,
+					Samples: 20,
+				},
 			},
 			{
 				IP:                         "192.168.178.102",
@@ -150,6 +156,12 @@ var overview = OutputOverview{
 					"10.1.0.0/24",
 				},
 				Latency: time.Duration(10000000),
+				IcePathQuality: &PathQualityOutput{
+					Latency: 10 * time.Millisecond,
+					Jitter:  2 * time.Millisecond,
+					Loss:    0.1,
+					Samples: 20,
+				},
 			},
 			{
 				IP:               "192.168.178.102",
@@ -297,6 +309,12 @@ func TestParsingToJSON(t *testing.T) {
                 "transferReceived": 200,
                 "transferSent": 100,
 				"latency": 10000000,
+                "icePathQuality": {
+                  "latency": 10000000,
+                  "jitter": 2000000,
+                  "loss": 0.1,
+                  "samples": 20
+                },
                 "quantumResistance": false,
                 "networks": [
                   "10.1.0.0/24"
@@ -428,6 +446,11 @@ func TestParsingToYAML(t *testing.T) {
           transferReceived: 200
           transferSent: 100
           latency: 10ms
+          icePathQuality:
+            latency: 10ms
+            jitter: 2ms
+            loss: 0.1
+            samples: 20
           quantumResistance: false
           networks:
             - 10.1.0.0/24
@@ -530,6 +553,8 @@ func TestParsingToDetail(t *testing.T) {
   Quantum resistance: false
   Networks: 10.1.0.0/24
   Latency: 10ms
+  P2P path quality: latency 10ms, jitter 2ms, loss 10.0%% (20 probes)
+  Relayed path quality: -
 
  peer-2.awesome-domain.com:
   NetBird IP: 192.168.178.102
@@ -546,6 +571,8 @@ func TestParsingToDetail(t *testing.T) {
   Quantum resistance: false
   Networks: -
   Latency: 10ms
+  P2P path quality: -
+  Relayed path quality: -
 
 Events: No events recorded
 OS: %s/%s
@@ -78,6 +78,12 @@ type Info struct {
 	EnableSSHLocalPortForwarding  bool
 	EnableSSHRemotePortForwarding bool
 	DisableSSHAuth                bool
+
+	// UnhealthyRoutes are the IDs of the routes served by this peer whose health checks fail
+	UnhealthyRoutes []string
+
+	// DiscoveredSubnets are the routing table entries matching the subnet discovery allowlist of the account
+	DiscoveredSubnets []string
 }
 
 func (i *Info) SetFlags(
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netbirdio/netbird/shared/management/client/rest"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+var (
+	accessManagementURL string
+	accessToken         string
+	accessTargetType    string
+	accessReason        string
+	accessDuration      string
+	accessNote          string
+
+	accessCmd = &cobra.Command{
+		Use:   "access",
+		Short: "Request and approve temporary access to network resources and groups",
+		Long: "Users request temporary access from their peers to a network resource or to the peers of a group. " +
+			"Approvers grant it for a bounded duration with a temporary policy that is removed when the access expires.",
+	}
+
+	accessRequestCmd = &cobra.Command{
+		Use:   "request <target-id> --duration <duration>",
+		Short: "Request temporary access to a network resource or group",
+		Example: `
+  netbird-mgmt access request chacdk86lnnboviihd7g --duration 2h --reason "debug production database" --management-url https://api.netbird.io --token nbp_...`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         createAccessRequest,
+	}
+
+	accessListCmd = &cobra.Command{
+		Use:          "list",
+		Short:        "List access requests, approvers see the requests of all users",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         listAccessRequests,
+	}
+
+	accessApproveCmd = &cobra.Command{
+		Use:          "approve <request-id>",
+		Short:        "Approve an access request, optionally for a shorter duration than requested",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         approveAccessRequest,
+	}
+
+	accessRejectCmd = &cobra.Command{
+		Use:          "reject <request-id>",
+		Short:        "Reject an access request",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         rejectAccessRequest,
+	}
+
+	accessRevokeCmd = &cobra.Command{
+		Use:          "revoke <request-id>",
+		Short:        "Cancel a pending access request or remove the granted access",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         revokeAccessRequest,
+	}
+)
+
+func init() {
+	accessCmd.PersistentFlags().StringVar(&accessManagementURL, "management-url", "", "URL of the management API, e.g. https://api.netbird.io")
+	accessCmd.PersistentFlags().StringVar(&accessToken, "token", "", "personal access token used to authenticate against the management API")
+	accessCmd.MarkPersistentFlagRequired("management-url") //nolint
+	accessCmd.MarkPersistentFlagRequired("token")          //nolint
+
+	accessRequestCmd.Flags().StringVar(&accessTargetType, "type", string(api.AccessRequestRequestTargetTypeResource), "type of the target, resource or group")
+	accessRequestCmd.Flags().StringVar(&accessReason, "reason", "", "why the access is needed")
+	accessRequestCmd.Flags().StringVar(&accessDuration, "duration", "", "how long the access is needed, e.g. 30m or 2h")
+	accessRequestCmd.MarkFlagRequired("duration") //nolint
+
+	accessApproveCmd.Flags().StringVar(&accessDuration, "duration", "", "duration of the granted access. Defaults to the requested duration")
+	accessRejectCmd.Flags().StringVar(&accessNote, "note", "", "note for the requesting user")
+
+	accessCmd.AddCommand(accessRequestCmd, accessListCmd, accessApproveCmd, accessRejectCmd, accessRevokeCmd)
+	rootCmd.AddCommand(accessCmd)
+}
+
+func accessClient() *rest.Client {
+	return rest.New(strings.TrimSuffix(accessManagementURL, "/"), accessToken)
+}
+
+func createAccessRequest(cmd *cobra.Command, args []string) error {
+	if _, err := time.ParseDuration(accessDuration); err != nil {
+		return fmt.Errorf("invalid duration %q: %w", accessDuration, err)
+	}
+
+	req := api.AccessRequestRequest{
+		TargetType: api.AccessRequestRequestTargetType(accessTargetType),
+		TargetId:   args[0],
+		Duration:   accessDuration,
+	}
+	if accessReason != "" {
+		req.Reason = &accessReason
+	}
+
+	request, err := accessClient().AccessRequests.Create(cmd.Context(), req)
+	if err != nil {
+		return fmt.Errorf("request access: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "access request %s is %s\n", request.Id, request.Status)
+	return nil
+}
+
+func listAccessRequests(cmd *cobra.Command, _ []string) error {
+	requests, err := accessClient().AccessRequests.List(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("list access requests: %w", err)
+	}
+	printAccessRequests(cmd.OutOrStdout(), requests)
+	return nil
+}
+
+func approveAccessRequest(cmd *cobra.Command, args []string) error {
+	var req api.AccessRequestDecision
+	if accessDuration != "" {
+		if _, err := time.ParseDuration(accessDuration); err != nil {
+			return fmt.Errorf("invalid duration %q: %w", accessDuration, err)
+		}
+		req.Duration = &accessDuration
+	}
+
+	request, err := accessClient().AccessRequests.Approve(cmd.Context(), args[0], req)
+	if err != nil {
+		return fmt.Errorf("approve access request: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "access request %s is approved until %s\n", request.Id, request.ExpiresAt.Format(time.RFC3339))
+	return nil
+}
+
+func rejectAccessRequest(cmd *cobra.Command, args []string) error {
+	var req api.AccessRequestDecision
+	if accessNote != "" {
+		req.Note = &accessNote
+	}
+
+	request, err := accessClient().AccessRequests.Reject(cmd.Context(), args[0], req)
+	if err != nil {
+		return fmt.Errorf("reject access request: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "access request %s is %s\n", request.Id, request.Status)
+	return nil
+}
+
+func revokeAccessRequest(cmd *cobra.Command, args []string) error {
+	request, err := accessClient().AccessRequests.Revoke(cmd.Context(), args[0])
+	if err != nil {
+		return fmt.Errorf("revoke access request: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "access request %s is %s\n", request.Id, request.Status)
+	return nil
+}
+
+func printAccessRequests(out io.Writer, requests []api.AccessRequest) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSER\tTARGET\tDURATION\tSTATUS\tEXPIRES\tREASON")
+	for _, request := range requests {
+		expires := "-"
+		if request.ExpiresAt != nil {
+			expires = request.ExpiresAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s %s\t%s\t%s\t%s\t%s\n", request.Id, request.UserId, request.TargetType, request.TargetId,
+			request.Duration, request.Status, expires, request.Reason)
+	}
+	w.Flush() //nolint
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netbirdio/netbird/formatter/hook"
+	nbconfig "github.com/netbirdio/netbird/management/internals/server/config"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/store/archive"
+	"github.com/netbirdio/netbird/util"
+	"github.com/netbirdio/netbird/util/crypt"
+)
+
+var (
+	accountConfigPath string
+	accountDataDir    string
+	accountID         string
+	accountOutput     string
+	accountRemapIDs   bool
+	accountDryRun     bool
+
+	accountCmd = &cobra.Command{
+		Use:          "account",
+		Short:        "Contains sub-commands to export an account from the store and import it into another one",
+		Long:         "",
+		SilenceUsage: true,
+	}
+
+	accountExportCmd = &cobra.Command{
+		Use:   "export --account id [--output file]",
+		Short: "Export an account with everything it owns as a JSON archive",
+		Long: "Reads an account from the store configured in the management config file and writes it as a versioned JSON archive. " +
+			"The archive contains settings, users, groups, policies, posture checks, routes, networks, DNS settings, zones, " +
+			"hashed setup keys and peers.\n\n" +
+			"Stop the management server or make sure the account isn't modified while exporting.",
+		Example: `
+  netbird-mgmt account export --config /etc/netbird/management.json --account cqbn2s7d0ok7h6cbi9ng -o account.json`,
+		Args: cobra.NoArgs,
+		RunE: exportAccount,
+	}
+
+	accountImportCmd = &cobra.Command{
+		Use:   "import <archive.json> [--remap-ids] [--dry-run]",
+		Short: "Import an account archive into the store",
+		Long: "Recreates the account of an archive written by the export command in the store configured in the management config file. " +
+			"Nothing is written if users, peers, setup keys or the account itself already exist in the store.\n\n" +
+			"Use --remap-ids to assign new IDs to the account and the objects it owns. User IDs are kept because they are issued by the identity provider.",
+		Example: `
+  netbird-mgmt account import --config /etc/netbird/management.json account.json --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: importAccount,
+	}
+)
+
+func init() {
+	accountCmd.PersistentFlags().StringVar(&accountConfigPath, "config", defaultMgmtConfig, "Netbird config file location")
+	accountCmd.PersistentFlags().StringVar(&accountDataDir, "datadir", "", "server data directory location. Overrides the data directory of the config file")
+
+	accountExportCmd.Flags().StringVar(&accountID, "account", "", "ID of the account to export")
+	accountExportCmd.Flags().StringVarP(&accountOutput, "output", "o", "", "file to write the archive to. Writes to stdout if not set")
+	accountExportCmd.MarkFlagRequired("account") //nolint
+
+	accountImportCmd.Flags().BoolVar(&accountRemapIDs, "remap-ids", false, "assign new IDs to the account and all objects it owns")
+	accountImportCmd.Flags().BoolVar(&accountDryRun, "dry-run", false, "only check the archive for conflicts with the store")
+
+	accountCmd.AddCommand(accountExportCmd)
+	accountCmd.AddCommand(accountImportCmd)
+
+	rootCmd.AddCommand(accountCmd)
+}
+
+func exportAccount(cmd *cobra.Command, _ []string) error {
+	//nolint
+	ctx := context.WithValue(cmd.Context(), hook.ExecutionContextKey, hook.SystemSource)
+
+	s, err := openAccountStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Close(ctx) //nolint
+
+	exported, err := archive.Export(ctx, s, accountID)
+	if err != nil {
+		return fmt.Errorf("export account: %w", err)
+	}
+
+	if accountOutput == "" {
+		return exported.Write(cmd.OutOrStdout())
+	}
+
+	f, err := os.OpenFile(accountOutput, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("create archive: %w", err)
+	}
+	defer f.Close()
+
+	return exported.Write(f)
+}
+
+func importAccount(cmd *cobra.Command, args []string) error {
+	//nolint
+	ctx := context.WithValue(cmd.Context(), hook.ExecutionContextKey, hook.SystemSource)
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("open archive: %w", err)
+	}
+	defer f.Close()
+
+	imported, err := archive.Read(f)
+	if err != nil {
+		return err
+	}
+
+	s, err := openAccountStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Close(ctx) //nolint
+
+	result, err := archive.Import(ctx, s, imported, archive.Options{RemapIDs: accountRemapIDs, DryRun: accountDryRun})
+	if err != nil {
+		var conflictErr *archive.ConflictError
+		if errors.As(err, &conflictErr) {
+			for _, conflict := range conflictErr.Conflicts {
+				cmd.PrintErrf("conflict: %s %s: %s\n", conflict.Kind, conflict.ID, conflict.Reason)
+			}
+			return fmt.Errorf("archive conflicts with %d objects of the store", len(conflictErr.Conflicts))
+		}
+		return fmt.Errorf("import account: %w", err)
+	}
+
+	if accountDryRun {
+		cmd.Printf("Account %s can be imported without conflicts\n", result.AccountID)
+		return nil
+	}
+	cmd.Printf("Account imported with ID %s\n", result.AccountID)
+	return nil
+}
+
+// openAccountStore opens the store configured in the management config file
+func openAccountStore(ctx context.Context) (store.Store, error) {
+	if err := util.InitLog(logLevel, logFile); err != nil {
+		return nil, fmt.Errorf("failed initializing log %v", err)
+	}
+
+	config := &nbconfig.Config{}
+	if _, err := util.ReadJsonWithEnvSub(accountConfigPath, config); err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	dataDir := config.Datadir
+	if accountDataDir != "" {
+		dataDir = accountDataDir
+	}
+
+	s, err := store.NewStore(ctx, config.StoreConfig.Engine, dataDir, nil, false)
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+
+	if config.DataStoreEncryptionKey != "" {
+		fieldEncrypt, err := crypt.NewFieldEncrypt(config.DataStoreEncryptionKey)
+		if err != nil {
+			s.Close(ctx) //nolint
+			return nil, fmt.Errorf("create field encryptor: %w", err)
+		}
+		s.SetFieldEncrypt(fieldEncrypt)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netbirdio/netbird/management/internals/modules/declarative"
+	"github.com/netbirdio/netbird/shared/management/client/rest"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+var (
+	applyManagementURL string
+	applyToken         string
+	applyFile          string
+	applyDryRun        bool
+
+	applyCmd = &cobra.Command{
+		Use:   "apply -f <config.yaml> [--dry-run]",
+		Short: "Apply a declarative configuration of groups, policies, networks and DNS to an account",
+		Long: "Reads a YAML file describing groups, posture checks, policies, networks with their resources and routers, " +
+			"nameserver groups and DNS zones, prints the changes needed to make the account match it and applies them.\n\n" +
+			"Objects are matched by name. Objects missing from the file are deleted only if they were created or updated by a previous apply. " +
+			"Managed objects that have been changed outside of the file since they were applied are reported as drift.\n\n" +
+			"Lines starting with + are created, ~ are updated and - are deleted.",
+		Example: `
+  netbird-mgmt apply --management-url https://api.netbird.io --token nbp_... -f netbird.yaml --dry-run`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         applyDeclarativeConfig,
+	}
+)
+
+func init() {
+	applyCmd.Flags().StringVar(&applyManagementURL, "management-url", "", "URL of the management API, e.g. https://api.netbird.io")
+	applyCmd.Flags().StringVar(&applyToken, "token", "", "personal access token used to authenticate against the management API")
+	applyCmd.Flags().StringVarP(&applyFile, "file", "f", "", "declarative configuration file. Reads from stdin if set to -")
+	applyCmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "only print the changes without applying them")
+	applyCmd.MarkFlagRequired("management-url") //nolint
+	applyCmd.MarkFlagRequired("token")          //nolint
+	applyCmd.MarkFlagRequired("file")           //nolint
+
+	rootCmd.AddCommand(applyCmd)
+}
+
+func applyDeclarativeConfig(cmd *cobra.Command, _ []string) error {
+	config, err := readDeclarativeConfig(applyFile)
+	if err != nil {
+		return err
+	}
+
+	client := rest.New(strings.TrimSuffix(applyManagementURL, "/"), applyToken)
+
+	plan, err := client.Declarative.Plan(cmd.Context(), config)
+	if err != nil {
+		return fmt.Errorf("plan: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	printDeclarativePlan(out, plan)
+
+	if applyDryRun || len(plan.Changes) == 0 {
+		return nil
+	}
+
+	applied, err := client.Declarative.Apply(cmd.Context(), config)
+	if err != nil {
+		return fmt.Errorf("apply: %w", err)
+	}
+	fmt.Fprintf(out, "\napplied %d changes\n", len(applied.Changes))
+	return nil
+}
+
+// readDeclarativeConfig validates the YAML file locally and converts it to the JSON form sent to the API
+func readDeclarativeConfig(path string) (api.DeclarativeConfig, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	spec, err := declarative.ParseSpec(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration %s: %w", path, err)
+	}
+
+	jsonData, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var config api.DeclarativeConfig
+	if err = json.Unmarshal(jsonData, &config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func printDeclarativePlan(out io.Writer, plan *api.DeclarativePlan) {
+	for _, drift := range plan.Drift {
+		fmt.Fprintf(out, "warning: %s %s was %s outside of the configuration\n", drift.Kind, drift.Name, drift.Reason)
+	}
+	if len(plan.Drift) > 0 {
+		fmt.Fprintln(out)
+	}
+
+	if len(plan.Changes) == 0 {
+		fmt.Fprintln(out, "no changes, the account matches the configuration")
+		return
+	}
+
+	for _, change := range plan.Changes {
+		switch change.Action {
+		case string(declarative.ActionCreate):
+			fmt.Fprintf(out, "+ %s %s\n", change.Kind, change.Name)
+		case string(declarative.ActionUpdate):
+			fields := ""
+			if change.Fields != nil {
+				fields = " (" + strings.Join(*change.Fields, ", ") + ")"
+			}
+			fmt.Fprintf(out, "~ %s %s%s\n", change.Kind, change.Name, fields)
+		case string(declarative.ActionDelete):
+			fmt.Fprintf(out, "- %s %s\n", change.Kind, change.Name)
+		}
+	}
+}
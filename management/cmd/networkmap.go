@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netbirdio/netbird/management/server/http/handlers/networkmaps"
+	"github.com/netbirdio/netbird/shared/management/client/rest"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+var (
+	networkMapManagementURL string
+	networkMapToken         string
+	networkMapPeer          string
+	networkMapOutput        string
+
+	networkMapCmd = &cobra.Command{
+		Use:          "network-map",
+		Short:        "Contains sub-commands to export and compare snapshots of the network maps computed for peers",
+		Long:         "",
+		SilenceUsage: true,
+	}
+
+	networkMapDumpCmd = &cobra.Command{
+		Use:   "dump [--peer id] [--output file]",
+		Short: "Dump the network maps computed by a management server as JSON",
+		Long: "Fetches the network map of a peer, or of all peers of the account, from the management API and writes it as JSON.\n\n" +
+			"The token is a personal access token of a user allowed to read peers and policies.",
+		Example: `
+  netbird-mgmt network-map dump --management-url https://api.netbird.io --token nbp_... > before.json
+  netbird-mgmt network-map dump --management-url https://api.netbird.io --token nbp_... --peer cqbn2s7d0ok7h6cbi9ng`,
+		Args: cobra.NoArgs,
+		RunE: dumpNetworkMaps,
+	}
+
+	networkMapDiffCmd = &cobra.Command{
+		Use:   "diff <old.json> <new.json>",
+		Short: "Compare two network map snapshots",
+		Long: "Compares two files written by the dump command and prints per peer which peers, firewall rules, routes and DNS records " +
+			"were gained or lost. Lines starting with + were added, - were removed and ~ were changed.",
+		Args: cobra.ExactArgs(2),
+		RunE: diffNetworkMaps,
+	}
+)
+
+func init() {
+	networkMapDumpCmd.Flags().StringVar(&networkMapManagementURL, "management-url", "", "URL of the management API, e.g. https://api.netbird.io")
+	networkMapDumpCmd.Flags().StringVar(&networkMapToken, "token", "", "personal access token used to authenticate against the management API")
+	networkMapDumpCmd.Flags().StringVar(&networkMapPeer, "peer", "", "ID of the peer to dump. Dumps all peers of the account if not set")
+	networkMapDumpCmd.Flags().StringVarP(&networkMapOutput, "output", "o", "", "file to write the snapshot to. Writes to stdout if not set")
+	networkMapDumpCmd.MarkFlagRequired("management-url") //nolint
+	networkMapDumpCmd.MarkFlagRequired("token")          //nolint
+
+	networkMapCmd.AddCommand(networkMapDumpCmd)
+	networkMapCmd.AddCommand(networkMapDiffCmd)
+
+	rootCmd.AddCommand(networkMapCmd)
+}
+
+func dumpNetworkMaps(cmd *cobra.Command, _ []string) error {
+	client := rest.New(strings.TrimSuffix(networkMapManagementURL, "/"), networkMapToken)
+
+	var snapshot any
+	var err error
+	if networkMapPeer != "" {
+		snapshot, err = client.Peers.GetNetworkMap(cmd.Context(), networkMapPeer)
+	} else {
+		snapshot, err = client.Peers.ListNetworkMaps(cmd.Context())
+	}
+	if err != nil {
+		return fmt.Errorf("get network map: %w", err)
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal network map: %w", err)
+	}
+	data = append(data, '\n')
+
+	if networkMapOutput == "" {
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	}
+	if err := os.WriteFile(networkMapOutput, data, 0600); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	return nil
+}
+
+func diffNetworkMaps(cmd *cobra.Command, args []string) error {
+	oldSnapshots, err := readNetworkMapSnapshots(args[0])
+	if err != nil {
+		return err
+	}
+	newSnapshots, err := readNetworkMapSnapshots(args[1])
+	if err != nil {
+		return err
+	}
+
+	writeNetworkMapDiff(cmd.OutOrStdout(), oldSnapshots, newSnapshots)
+	return nil
+}
+
+// readNetworkMapSnapshots reads a file holding a single snapshot or a list of snapshots, keyed by the peer ID
+func readNetworkMapSnapshots(path string) (map[string]api.NetworkMapSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot: %w", err)
+	}
+
+	var snapshots []api.NetworkMapSnapshot
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &snapshots)
+	} else {
+		var snapshot api.NetworkMapSnapshot
+		err = json.Unmarshal(trimmed, &snapshot)
+		snapshots = append(snapshots, snapshot)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse snapshot %s: %w", path, err)
+	}
+
+	result := make(map[string]api.NetworkMapSnapshot, len(snapshots))
+	for _, snapshot := range snapshots {
+		if snapshot.Peer.Id == "" {
+			return nil, fmt.Errorf("parse snapshot %s: missing peer ID", path)
+		}
+		result[snapshot.Peer.Id] = snapshot
+	}
+	return result, nil
+}
+
+func writeNetworkMapDiff(w io.Writer, oldSnapshots, newSnapshots map[string]api.NetworkMapSnapshot) {
+	peerIDs := make(map[string]struct{}, len(oldSnapshots)+len(newSnapshots))
+	for id := range oldSnapshots {
+		peerIDs[id] = struct{}{}
+	}
+	for id := range newSnapshots {
+		peerIDs[id] = struct{}{}
+	}
+	sortedIDs := make([]string, 0, len(peerIDs))
+	for id := range peerIDs {
+		sortedIDs = append(sortedIDs, id)
+	}
+	sort.Strings(sortedIDs)
+
+	var changed bool
+	for _, id := range sortedIDs {
+		oldSnapshot, inOld := oldSnapshots[id]
+		newSnapshot, inNew := newSnapshots[id]
+		switch {
+		case !inOld:
+			fmt.Fprintf(w, "+ peer %s: only in the new snapshot\n", formatSnapshotPeer(newSnapshot.Peer))
+			changed = true
+		case !inNew:
+			fmt.Fprintf(w, "- peer %s: only in the old snapshot\n", formatSnapshotPeer(oldSnapshot.Peer))
+			changed = true
+		default:
+			lines := diffNetworkMapSnapshot(oldSnapshot, newSnapshot)
+			if len(lines) == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "peer %s:\n", formatSnapshotPeer(newSnapshot.Peer))
+			for _, line := range lines {
+				fmt.Fprintf(w, "  %s\n", line)
+			}
+			changed = true
+		}
+	}
+
+	if !changed {
+		fmt.Fprintln(w, "no changes")
+	}
+}
+
+// diffNetworkMapSnapshot returns the differences between two snapshots of the same peer
+func diffNetworkMapSnapshot(oldSnapshot, newSnapshot api.NetworkMapSnapshot) []string {
+	var lines []string
+
+	if oldSnapshot.SshEnabled != newSnapshot.SshEnabled {
+		lines = append(lines, fmt.Sprintf("~ ssh enabled: %t -> %t", oldSnapshot.SshEnabled, newSnapshot.SshEnabled))
+	}
+
+	lines = append(lines, diffKeys("peer",
+		mapSlice(oldSnapshot.Peers, formatSnapshotPeer),
+		mapSlice(newSnapshot.Peers, formatSnapshotPeer))...)
+	lines = append(lines, diffKeys("offline peer",
+		mapSlice(oldSnapshot.OfflinePeers, formatSnapshotPeer),
+		mapSlice(newSnapshot.OfflinePeers, formatSnapshotPeer))...)
+	lines = append(lines, diffKeys("firewall rule",
+		mapSlice(oldSnapshot.FirewallRules, networkmaps.FirewallRuleKey),
+		mapSlice(newSnapshot.FirewallRules, networkmaps.FirewallRuleKey))...)
+	lines = append(lines, diffRoutes(oldSnapshot.Routes, newSnapshot.Routes)...)
+	lines = append(lines, diffKeys("route firewall rule",
+		mapSlice(oldSnapshot.RouteFirewallRules, networkmaps.RouteFirewallRuleKey),
+		mapSlice(newSnapshot.RouteFirewallRules, networkmaps.RouteFirewallRuleKey))...)
+	lines = append(lines, diffKeys("dns record",
+		mapSlice(oldSnapshot.DnsRecords, networkmaps.DNSRecordKey),
+		mapSlice(newSnapshot.DnsRecords, networkmaps.DNSRecordKey))...)
+	lines = append(lines, diffKeys("nameserver group",
+		mapSlice(oldSnapshot.NameserverGroups, formatNameserverGroup),
+		mapSlice(newSnapshot.NameserverGroups, formatNameserverGroup))...)
+
+	return lines
+}
+
+// diffRoutes matches routes by ID to report changed routes in addition to added and removed ones
+func diffRoutes(oldRoutes, newRoutes []api.NetworkMapRoute) []string {
+	oldByID := make(map[string]api.NetworkMapRoute, len(oldRoutes))
+	for _, r := range oldRoutes {
+		oldByID[r.Id] = r
+	}
+
+	var lines []string
+	seen := make(map[string]struct{}, len(newRoutes))
+	for _, r := range newRoutes {
+		seen[r.Id] = struct{}{}
+		old, ok := oldByID[r.Id]
+		switch {
+		case !ok:
+			lines = append(lines, "+ route "+formatRoute(r))
+		case formatRoute(old) != formatRoute(r):
+			lines = append(lines, fmt.Sprintf("~ route %s -> %s", formatRoute(old), formatRoute(r)))
+		}
+	}
+	for _, r := range oldRoutes {
+		if _, ok := seen[r.Id]; !ok {
+			lines = append(lines, "- route "+formatRoute(r))
+		}
+	}
+	// added before removed before changed, like the other lists
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i][0] < lines[j][0]
+	})
+	return lines
+}
+
+// diffKeys reports the keys removed from and added to a list
+func diffKeys(kind string, oldKeys, newKeys []string) []string {
+	oldSet := make(map[string]struct{}, len(oldKeys))
+	for _, k := range oldKeys {
+		oldSet[k] = struct{}{}
+	}
+	newSet := make(map[string]struct{}, len(newKeys))
+	for _, k := range newKeys {
+		newSet[k] = struct{}{}
+	}
+
+	var lines []string
+	for _, k := range newKeys {
+		if _, ok := oldSet[k]; !ok {
+			lines = append(lines, fmt.Sprintf("+ %s %s", kind, k))
+		}
+	}
+	for _, k := range oldKeys {
+		if _, ok := newSet[k]; !ok {
+			lines = append(lines, fmt.Sprintf("- %s %s", kind, k))
+		}
+	}
+	return lines
+}
+
+func mapSlice[T any](items []T, f func(T) string) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, f(item))
+	}
+	sort.Strings(result)
+	return result
+}
+
+func formatSnapshotPeer(peer api.NetworkMapPeer) string {
+	return fmt.Sprintf("%s (%s, %s)", peer.Name, peer.Ip, peer.Id)
+}
+
+func formatRoute(r api.NetworkMapRoute) string {
+	destination := r.Network
+	if len(r.Domains) > 0 {
+		destination = strings.Join(r.Domains, ",")
+	}
+	s := fmt.Sprintf("%s [%s] %s via %s metric %d", r.NetworkId, r.Id, destination, r.Peer, r.Metric)
+	if r.Masquerade {
+		s += " masquerade"
+	}
+	if r.KeepRoute {
+		s += " keep-route"
+	}
+	return s
+}
+
+func formatNameserverGroup(group api.NetworkMapNameserverGroup) string {
+	s := fmt.Sprintf("%s [%s] %s", group.Name, group.Id, strings.Join(group.Nameservers, ","))
+	if group.Primary {
+		return s + " primary"
+	}
+	return s + " domains " + strings.Join(group.Domains, ",")
+}
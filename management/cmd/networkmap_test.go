@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+func testSnapshot() api.NetworkMapSnapshot {
+	return api.NetworkMapSnapshot{
+		Peer: api.NetworkMapPeer{Id: "a", Name: "peer-a", Ip: "100.64.0.1"},
+		Peers: []api.NetworkMapPeer{
+			{Id: "b", Name: "peer-b", Ip: "100.64.0.2"},
+		},
+		FirewallRules: []api.NetworkMapFirewallRule{
+			{PolicyId: "p", PeerIp: "100.64.0.2", Direction: api.NetworkMapFirewallRuleDirectionIn, Action: "accept", Protocol: "all"},
+		},
+		Routes: []api.NetworkMapRoute{
+			{Id: "r", NetworkId: "office", Network: "10.0.0.0/24", Peer: "b", Metric: 9999},
+		},
+		DnsRecords: []api.NetworkMapDNSRecord{
+			{Name: "peer-b.netbird.cloud.", Type: "A", Ttl: 300, Rdata: "100.64.0.2"},
+		},
+	}
+}
+
+func TestDiffNetworkMapSnapshot(t *testing.T) {
+	oldSnapshot := testSnapshot()
+	newSnapshot := testSnapshot()
+
+	assert.Empty(t, diffNetworkMapSnapshot(oldSnapshot, newSnapshot))
+
+	newSnapshot.Peers = []api.NetworkMapPeer{{Id: "c", Name: "peer-c", Ip: "100.64.0.3"}}
+	newSnapshot.FirewallRules = append(newSnapshot.FirewallRules, api.NetworkMapFirewallRule{
+		PolicyId: "p", PeerIp: "100.64.0.3", Direction: api.NetworkMapFirewallRuleDirectionIn, Action: "accept", Protocol: "tcp", Ports: "22",
+	})
+	newSnapshot.Routes = []api.NetworkMapRoute{
+		{Id: "r", NetworkId: "office", Network: "10.0.0.0/24", Peer: "c", Metric: 9999},
+		{Id: "r2", NetworkId: "lab", Domains: []string{"example.com"}, Peer: "c", Metric: 100},
+	}
+	newSnapshot.DnsRecords = nil
+
+	assert.Equal(t, []string{
+		"+ peer peer-c (100.64.0.3, c)",
+		"- peer peer-b (100.64.0.2, b)",
+		"+ firewall rule in 100.64.0.3 tcp 22 accept p",
+		"+ route lab [r2] example.com via c metric 100",
+		"~ route office [r] 10.0.0.0/24 via b metric 9999 -> office [r] 10.0.0.0/24 via c metric 9999",
+		"- dns record peer-b.netbird.cloud. A 100.64.0.2",
+	}, diffNetworkMapSnapshot(oldSnapshot, newSnapshot))
+}
+
+func TestWriteNetworkMapDiff(t *testing.T) {
+	peerB := api.NetworkMapSnapshot{Peer: api.NetworkMapPeer{Id: "b", Name: "peer-b", Ip: "100.64.0.2"}}
+	changed := testSnapshot()
+	changed.SshEnabled = true
+
+	var out bytes.Buffer
+	writeNetworkMapDiff(&out,
+		map[string]api.NetworkMapSnapshot{"a": testSnapshot(), "b": peerB},
+		map[string]api.NetworkMapSnapshot{"a": changed},
+	)
+	assert.Equal(t, "peer peer-a (100.64.0.1, a):\n"+
+		"  ~ ssh enabled: false -> true\n"+
+		"- peer peer-b (100.64.0.2, b): only in the old snapshot\n", out.String())
+
+	out.Reset()
+	writeNetworkMapDiff(&out,
+		map[string]api.NetworkMapSnapshot{"a": testSnapshot()},
+		map[string]api.NetworkMapSnapshot{"a": testSnapshot()},
+	)
+	assert.Equal(t, "no changes\n", out.String())
+}
+
+func TestReadNetworkMapSnapshots(t *testing.T) {
+	dir := t.TempDir()
+
+	single, err := json.Marshal(testSnapshot())
+	require.NoError(t, err)
+	singlePath := filepath.Join(dir, "single.json")
+	require.NoError(t, os.WriteFile(singlePath, single, 0600))
+
+	list, err := json.Marshal([]api.NetworkMapSnapshot{testSnapshot(), {Peer: api.NetworkMapPeer{Id: "b"}}})
+	require.NoError(t, err)
+	listPath := filepath.Join(dir, "list.json")
+	require.NoError(t, os.WriteFile(listPath, list, 0600))
+
+	snapshots, err := readNetworkMapSnapshots(singlePath)
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 1)
+	assert.Equal(t, testSnapshot(), snapshots["a"])
+
+	snapshots, err = readNetworkMapSnapshots(listPath)
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 2)
+	assert.Contains(t, snapshots, "b")
+
+	invalidPath := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalidPath, []byte(`{"peers": []}`), 0600))
+	_, err = readNetworkMapSnapshots(invalidPath)
+	assert.Error(t, err)
+}
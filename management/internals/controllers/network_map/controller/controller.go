@@ -20,12 +20,14 @@ import (
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map"
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map/controller/cache"
 	"github.com/netbirdio/netbird/management/internals/modules/peers/ephemeral"
+	"github.com/netbirdio/netbird/management/internals/modules/portforwards"
 	"github.com/netbirdio/netbird/management/internals/modules/zones"
 	"github.com/netbirdio/netbird/management/internals/server/config"
 	"github.com/netbirdio/netbird/management/internals/shared/grpc"
 	"github.com/netbirdio/netbird/management/server/account"
 	"github.com/netbirdio/netbird/management/server/integrations/integrated_validator"
 	"github.com/netbirdio/netbird/management/server/integrations/port_forwarding"
+	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
 	"github.com/netbirdio/netbird/management/server/posture"
 	"github.com/netbirdio/netbird/management/server/settings"
@@ -204,6 +206,11 @@ func (c *Controller) sendUpdateAccountPeers(ctx context.Context, accountID strin
 		return fmt.Errorf("failed to get account zones: %v", err)
 	}
 
+	portForwardNetworkMaps, err := c.getPortForwardNetworkMaps(ctx, account, approvedPeersMap)
+	if err != nil {
+		return err
+	}
+
 	for _, peer := range account.Peers {
 		if !c.peersUpdateManager.HasChannel(peer.ID) {
 			log.WithContext(ctx).Tracef("peer %s doesn't have a channel, skipping network map update", peer.ID)
@@ -242,9 +249,15 @@ func (c *Controller) sendUpdateAccountPeers(ctx context.Context, accountID strin
 				remotePeerNetworkMap.Merge(proxyNetworkMap)
 			}
 
+			if portForwardNetworkMap, ok := portForwardNetworkMaps[p.ID]; ok {
+				remotePeerNetworkMap.Merge(portForwardNetworkMap)
+			}
+
 			peerGroups := account.GetPeerGroups(p.ID)
 			start = time.Now()
-			update := grpc.ToSyncResponse(ctx, nil, c.config.HttpConfig, c.config.DeviceAuthorizationFlow, p, nil, nil, remotePeerNetworkMap, dnsDomain, postureChecks, dnsCache, account.Settings, extraSetting, maps.Keys(peerGroups), dnsFwdPort)
+			peerGroupIDs := maps.Keys(peerGroups)
+			routingPeer := routerTypes.IsRoutingPeer(account.NetworkRouters, p.ID, peerGroupIDs)
+			update := grpc.ToSyncResponse(ctx, nil, c.config.HttpConfig, c.config.DeviceAuthorizationFlow, p, nil, nil, remotePeerNetworkMap, dnsDomain, postureChecks, dnsCache, account.Settings, extraSetting, peerGroupIDs, routingPeer, dnsFwdPort)
 			c.metrics.CountToSyncResponseDuration(time.Since(start))
 
 			c.peersUpdateManager.SendUpdate(ctx, p.ID, &network_map.UpdateMessage{Update: update})
@@ -348,6 +361,11 @@ func (c *Controller) UpdateAccountPeer(ctx context.Context, accountId string, pe
 		return err
 	}
 
+	portForwardNetworkMaps, err := c.getPortForwardNetworkMaps(ctx, account, approvedPeersMap)
+	if err != nil {
+		return err
+	}
+
 	var remotePeerNetworkMap *types.NetworkMap
 
 	if c.experimentalNetworkMap(accountId) {
@@ -361,6 +379,10 @@ func (c *Controller) UpdateAccountPeer(ctx context.Context, accountId string, pe
 		remotePeerNetworkMap.Merge(proxyNetworkMap)
 	}
 
+	if portForwardNetworkMap, ok := portForwardNetworkMaps[peer.ID]; ok {
+		remotePeerNetworkMap.Merge(portForwardNetworkMap)
+	}
+
 	extraSettings, err := c.settingsManager.GetExtraSettings(ctx, peer.AccountID)
 	if err != nil {
 		return fmt.Errorf("failed to get extra settings: %v", err)
@@ -369,7 +391,9 @@ func (c *Controller) UpdateAccountPeer(ctx context.Context, accountId string, pe
 	peerGroups := account.GetPeerGroups(peerId)
 	dnsFwdPort := computeForwarderPort(maps.Values(account.Peers), network_map.DnsForwarderPortMinVersion)
 
-	update := grpc.ToSyncResponse(ctx, nil, c.config.HttpConfig, c.config.DeviceAuthorizationFlow, peer, nil, nil, remotePeerNetworkMap, dnsDomain, postureChecks, dnsCache, account.Settings, extraSettings, maps.Keys(peerGroups), dnsFwdPort)
+	peerGroupIDs := maps.Keys(peerGroups)
+	routingPeer := routerTypes.IsRoutingPeer(account.NetworkRouters, peer.ID, peerGroupIDs)
+	update := grpc.ToSyncResponse(ctx, nil, c.config.HttpConfig, c.config.DeviceAuthorizationFlow, peer, nil, nil, remotePeerNetworkMap, dnsDomain, postureChecks, dnsCache, account.Settings, extraSettings, peerGroupIDs, routingPeer, dnsFwdPort)
 	c.peersUpdateManager.SendUpdate(ctx, peer.ID, &network_map.UpdateMessage{Update: update})
 
 	return nil
@@ -453,6 +477,11 @@ func (c *Controller) GetValidatedPeerWithMap(ctx context.Context, isRequiresAppr
 		return nil, nil, nil, 0, err
 	}
 
+	portForwardNetworkMaps, err := c.getPortForwardNetworkMaps(ctx, account, approvedPeersMap)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
 	dnsDomain := c.GetDNSDomain(account.Settings)
 	peersCustomZone := account.GetPeersCustomZone(ctx, dnsDomain)
 
@@ -477,6 +506,10 @@ func (c *Controller) GetValidatedPeerWithMap(ctx context.Context, isRequiresAppr
 		networkMap.Merge(proxyNetworkMap)
 	}
 
+	if portForwardNetworkMap, ok := portForwardNetworkMaps[peer.ID]; ok {
+		networkMap.Merge(portForwardNetworkMap)
+	}
+
 	dnsFwdPort := computeForwarderPort(maps.Values(account.Peers), network_map.DnsForwarderPortMinVersion)
 
 	return peer, networkMap, postureChecks, dnsFwdPort, nil
@@ -487,6 +520,16 @@ func (c *Controller) initNetworkMapBuilderIfNeeded(account *types.Account, valid
 	account.InitNetworkMapBuilderIfNeeded(validatedPeers)
 }
 
+// getPortForwardNetworkMaps returns the parts of the network maps of the peers added by the port forwards of the account
+func (c *Controller) getPortForwardNetworkMaps(ctx context.Context, account *types.Account, validatedPeers map[string]struct{}) (map[string]*types.NetworkMap, error) {
+	forwards, err := c.repo.GetAccountPortForwards(ctx, account.Id)
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to get account port forwards: %v", err)
+		return nil, fmt.Errorf("failed to get account port forwards: %v", err)
+	}
+	return portforwards.NetworkMaps(account, forwards, validatedPeers), nil
+}
+
 func (c *Controller) getPeerNetworkMapExp(
 	ctx context.Context,
 	accountId string,
@@ -805,16 +848,28 @@ func (c *Controller) GetNetworkMap(ctx context.Context, peerID string) (*types.N
 		return nil, err
 	}
 
-	peer := account.GetPeer(peerID)
-	if peer == nil {
+	if account.GetPeer(peerID) == nil {
 		return nil, status.Errorf(status.NotFound, "peer with ID %s not found", peerID)
 	}
 
-	groups := make(map[string][]string)
-	for groupID, group := range account.Groups {
-		groups[groupID] = group.Peers
+	networkMaps, err := c.getNetworkMaps(ctx, account, []string{peerID})
+	if err != nil {
+		return nil, err
+	}
+	return networkMaps[peerID], nil
+}
+
+// GetAccountNetworkMaps returns the network maps of all peers of the account, keyed by peer ID
+func (c *Controller) GetAccountNetworkMaps(ctx context.Context, accountID string) (map[string]*types.NetworkMap, error) {
+	account, err := c.requestBuffer.GetAccountWithBackpressure(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
 	}
 
+	return c.getNetworkMaps(ctx, account, maps.Keys(account.Peers))
+}
+
+func (c *Controller) getNetworkMaps(ctx context.Context, account *types.Account, peerIDs []string) (map[string]*types.NetworkMap, error) {
 	validatedPeers, err := c.integratedPeerValidator.GetValidatedPeers(ctx, account.Id, maps.Values(account.Groups), maps.Values(account.Peers), account.Settings.Extra)
 	if err != nil {
 		return nil, err
@@ -826,31 +881,43 @@ func (c *Controller) GetNetworkMap(ctx context.Context, peerID string) (*types.N
 		return nil, err
 	}
 
-	dnsDomain := c.GetDNSDomain(account.Settings)
-	peersCustomZone := account.GetPeersCustomZone(ctx, dnsDomain)
-
-	proxyNetworkMaps, err := c.proxyController.GetProxyNetworkMaps(ctx, account.Id, peerID, account.Peers)
+	portForwardNetworkMaps, err := c.getPortForwardNetworkMaps(ctx, account, validatedPeers)
 	if err != nil {
-		log.WithContext(ctx).Errorf("failed to get proxy network maps: %v", err)
 		return nil, err
 	}
 
-	var networkMap *types.NetworkMap
+	dnsDomain := c.GetDNSDomain(account.Settings)
+	peersCustomZone := account.GetPeersCustomZone(ctx, dnsDomain)
 
-	if c.experimentalNetworkMap(peer.AccountID) {
-		networkMap = c.getPeerNetworkMapExp(ctx, peer.AccountID, peerID, validatedPeers, peersCustomZone, accountZones, nil)
-	} else {
-		resourcePolicies := account.GetResourcePoliciesMap()
-		routers := account.GetResourceRoutersMap()
-		networkMap = account.GetPeerNetworkMap(ctx, peer.ID, peersCustomZone, accountZones, validatedPeers, resourcePolicies, routers, nil, account.GetActiveGroupUsers())
-	}
+	resourcePolicies := account.GetResourcePoliciesMap()
+	routers := account.GetResourceRoutersMap()
+	groupUsers := account.GetActiveGroupUsers()
 
-	proxyNetworkMap, ok := proxyNetworkMaps[peer.ID]
-	if ok {
-		networkMap.Merge(proxyNetworkMap)
+	networkMaps := make(map[string]*types.NetworkMap, len(peerIDs))
+	for _, peerID := range peerIDs {
+		proxyNetworkMaps, err := c.proxyController.GetProxyNetworkMaps(ctx, account.Id, peerID, account.Peers)
+		if err != nil {
+			log.WithContext(ctx).Errorf("failed to get proxy network maps: %v", err)
+			return nil, err
+		}
+
+		var networkMap *types.NetworkMap
+		if c.experimentalNetworkMap(account.Id) {
+			networkMap = c.getPeerNetworkMapExp(ctx, account.Id, peerID, validatedPeers, peersCustomZone, accountZones, nil)
+		} else {
+			networkMap = account.GetPeerNetworkMap(ctx, peerID, peersCustomZone, accountZones, validatedPeers, resourcePolicies, routers, nil, groupUsers)
+		}
+
+		if proxyNetworkMap, ok := proxyNetworkMaps[peerID]; ok {
+			networkMap.Merge(proxyNetworkMap)
+		}
+		if portForwardNetworkMap, ok := portForwardNetworkMaps[peerID]; ok {
+			networkMap.Merge(portForwardNetworkMap)
+		}
+		networkMaps[peerID] = networkMap
 	}
 
-	return networkMap, nil
+	return networkMaps, nil
 }
 
 func (c *Controller) DisconnectPeers(ctx context.Context, accountId string, peerIDs []string) {
@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 
+	"github.com/netbirdio/netbird/management/internals/modules/portforwards"
 	"github.com/netbirdio/netbird/management/internals/modules/zones"
 	"github.com/netbirdio/netbird/management/server/peer"
 	"github.com/netbirdio/netbird/management/server/store"
@@ -16,6 +17,7 @@ type Repository interface {
 	GetPeersByIDs(ctx context.Context, accountID string, peerIDs []string) (map[string]*peer.Peer, error)
 	GetPeerByID(ctx context.Context, accountID string, peerID string) (*peer.Peer, error)
 	GetAccountZones(ctx context.Context, accountID string) ([]*zones.Zone, error)
+	GetAccountPortForwards(ctx context.Context, accountID string) ([]*portforwards.PortForward, error)
 }
 
 type repository struct {
@@ -53,3 +55,7 @@ func (r *repository) GetPeerByID(ctx context.Context, accountID string, peerID s
 func (r *repository) GetAccountZones(ctx context.Context, accountID string) ([]*zones.Zone, error) {
 	return r.store.GetAccountZones(ctx, store.LockingStrengthNone, accountID)
 }
+
+func (r *repository) GetAccountPortForwards(ctx context.Context, accountID string) ([]*portforwards.PortForward, error) {
+	return r.store.GetAccountPortForwards(ctx, store.LockingStrengthNone, accountID)
+}
@@ -28,6 +28,7 @@ type Controller interface {
 	GetDNSDomain(settings *types.Settings) string
 	StartWarmup(context.Context)
 	GetNetworkMap(ctx context.Context, peerID string) (*types.NetworkMap, error)
+	GetAccountNetworkMaps(ctx context.Context, accountID string) (map[string]*types.NetworkMap, error)
 	CountStreams() int
 
 	OnPeersUpdated(ctx context.Context, accountId string, peerIDs []string) error
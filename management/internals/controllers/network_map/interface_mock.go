@@ -83,6 +83,21 @@ func (mr *MockControllerMockRecorder) DisconnectPeers(ctx, accountId, peerIDs an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisconnectPeers", reflect.TypeOf((*MockController)(nil).DisconnectPeers), ctx, accountId, peerIDs)
 }
 
+// GetAccountNetworkMaps mocks base method.
+func (m *MockController) GetAccountNetworkMaps(ctx context.Context, accountID string) (map[string]*types.NetworkMap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountNetworkMaps", ctx, accountID)
+	ret0, _ := ret[0].(map[string]*types.NetworkMap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountNetworkMaps indicates an expected call of GetAccountNetworkMaps.
+func (mr *MockControllerMockRecorder) GetAccountNetworkMaps(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountNetworkMaps", reflect.TypeOf((*MockController)(nil).GetAccountNetworkMaps), ctx, accountID)
+}
+
 // GetDNSDomain mocks base method.
 func (m *MockController) GetDNSDomain(settings *types.Settings) string {
 	m.ctrl.T.Helper()
@@ -0,0 +1,25 @@
+package accessrequests
+
+import (
+	"context"
+	"time"
+)
+
+type Manager interface {
+	CreateRequest(ctx context.Context, accountID, userID string, request *AccessRequest) (*AccessRequest, error)
+	ListRequests(ctx context.Context, accountID, userID string) ([]*AccessRequest, error)
+	GetRequest(ctx context.Context, accountID, userID, requestID string) (*AccessRequest, error)
+	// ApproveRequest grants a pending request. A zero duration grants the requested duration.
+	ApproveRequest(ctx context.Context, accountID, userID, requestID string, duration time.Duration) (*AccessRequest, error)
+	RejectRequest(ctx context.Context, accountID, userID, requestID, note string) (*AccessRequest, error)
+	// RevokeRequest cancels a pending request or ends the access of an approved one before it expires
+	RevokeRequest(ctx context.Context, accountID, userID, requestID string) (*AccessRequest, error)
+
+	GetSettings(ctx context.Context, accountID, userID string) (*Settings, error)
+	UpdateSettings(ctx context.Context, accountID, userID string, settings *Settings) (*Settings, error)
+
+	// ExpireRequests removes the access of all approved requests that have expired
+	ExpireRequests(ctx context.Context) error
+	// Start expires approved requests periodically until the context is done
+	Start(ctx context.Context)
+}
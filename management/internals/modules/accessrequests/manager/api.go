@@ -0,0 +1,241 @@
+package manager
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/http/util"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type handler struct {
+	manager accessrequests.Manager
+}
+
+func RegisterEndpoints(router *mux.Router, manager accessrequests.Manager) {
+	h := &handler{
+		manager: manager,
+	}
+
+	router.HandleFunc("/access-requests", h.listRequests).Methods("GET", "OPTIONS")
+	router.HandleFunc("/access-requests", h.createRequest).Methods("POST", "OPTIONS")
+	router.HandleFunc("/access-requests/settings", h.getSettings).Methods("GET", "OPTIONS")
+	router.HandleFunc("/access-requests/settings", h.updateSettings).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/access-requests/{requestId}", h.getRequest).Methods("GET", "OPTIONS")
+	router.HandleFunc("/access-requests/{requestId}/approve", h.approveRequest).Methods("POST", "OPTIONS")
+	router.HandleFunc("/access-requests/{requestId}/reject", h.rejectRequest).Methods("POST", "OPTIONS")
+	router.HandleFunc("/access-requests/{requestId}/revoke", h.revokeRequest).Methods("POST", "OPTIONS")
+}
+
+func (h *handler) listRequests(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	requests, err := h.manager.ListRequests(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	apiRequests := make([]*api.AccessRequest, 0, len(requests))
+	for _, request := range requests {
+		apiRequests = append(apiRequests, request.ToAPIResponse())
+	}
+
+	util.WriteJSONObject(r.Context(), w, apiRequests)
+}
+
+func (h *handler) createRequest(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var req api.PostApiAccessRequestsJSONRequestBody
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	request, err := accessrequests.FromAPIRequest(&req)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	request, err = h.manager.CreateRequest(r.Context(), userAuth.AccountId, userAuth.UserId, request)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, request.ToAPIResponse())
+}
+
+func (h *handler) getRequest(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	requestID := mux.Vars(r)["requestId"]
+	if requestID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "request ID is required"), w)
+		return
+	}
+
+	request, err := h.manager.GetRequest(r.Context(), userAuth.AccountId, userAuth.UserId, requestID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, request.ToAPIResponse())
+}
+
+func (h *handler) approveRequest(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	requestID := mux.Vars(r)["requestId"]
+	if requestID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "request ID is required"), w)
+		return
+	}
+
+	var req api.PostApiAccessRequestsRequestIdApproveJSONRequestBody
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	var duration time.Duration
+	if req.Duration != nil {
+		duration, err = time.ParseDuration(*req.Duration)
+		if err != nil {
+			util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid duration %q: %s", *req.Duration, err), w)
+			return
+		}
+	}
+
+	request, err := h.manager.ApproveRequest(r.Context(), userAuth.AccountId, userAuth.UserId, requestID, duration)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, request.ToAPIResponse())
+}
+
+func (h *handler) rejectRequest(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	requestID := mux.Vars(r)["requestId"]
+	if requestID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "request ID is required"), w)
+		return
+	}
+
+	var req api.PostApiAccessRequestsRequestIdRejectJSONRequestBody
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	var note string
+	if req.Note != nil {
+		note = *req.Note
+	}
+
+	request, err := h.manager.RejectRequest(r.Context(), userAuth.AccountId, userAuth.UserId, requestID, note)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, request.ToAPIResponse())
+}
+
+func (h *handler) revokeRequest(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	requestID := mux.Vars(r)["requestId"]
+	if requestID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "request ID is required"), w)
+		return
+	}
+
+	request, err := h.manager.RevokeRequest(r.Context(), userAuth.AccountId, userAuth.UserId, requestID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, request.ToAPIResponse())
+}
+
+func (h *handler) getSettings(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	settings, err := h.manager.GetSettings(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, settings.ToAPIResponse())
+}
+
+func (h *handler) updateSettings(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var req api.PutApiAccessRequestsSettingsJSONRequestBody
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	settings, err := accessrequests.SettingsFromAPIRequest(&req)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	settings, err = h.manager.UpdateSettings(r.Context(), userAuth.AccountId, userAuth.UserId, settings)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, settings.ToAPIResponse())
+}
@@ -0,0 +1,540 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/xid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// expirationInterval is how often the approved requests are checked for expiration
+const expirationInterval = time.Minute
+
+type managerImpl struct {
+	store              store.Store
+	accountManager     account.Manager
+	permissionsManager permissions.Manager
+}
+
+func NewManager(store store.Store, accountManager account.Manager, permissionsManager permissions.Manager) accessrequests.Manager {
+	return &managerImpl{
+		store:              store,
+		accountManager:     accountManager,
+		permissionsManager: permissionsManager,
+	}
+}
+
+// CreateRequest stores a pending request of the user. Any user of the account with peers can request access.
+func (m *managerImpl) CreateRequest(ctx context.Context, accountID, userID string, request *accessrequests.AccessRequest) (*accessrequests.AccessRequest, error) {
+	if _, err := m.getAccountUser(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+
+	settings, err := getSettings(ctx, m.store, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Duration <= 0 || request.Duration > settings.MaxDuration {
+		return nil, status.Errorf(status.InvalidArgument, "duration must be positive and at most %s", settings.MaxDuration)
+	}
+
+	if err = m.validateTarget(ctx, accountID, request.TargetType, request.TargetID); err != nil {
+		return nil, err
+	}
+
+	peers, err := m.store.GetUserPeers(ctx, store.LockingStrengthNone, accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(peers) == 0 {
+		return nil, status.Errorf(status.PreconditionFailed, "user has no peers to grant access to")
+	}
+
+	request = accessrequests.NewAccessRequest(accountID, userID, request.TargetType, request.TargetID, request.Reason, request.Duration)
+	if err = m.store.SaveAccessRequest(ctx, request); err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, request.ID, accountID, activity.AccessRequestCreated, request.EventMeta())
+
+	return request, nil
+}
+
+// ListRequests returns all requests of the account to approvers and users allowed to read policies, other users
+// only get their own requests
+func (m *managerImpl) ListRequests(ctx context.Context, accountID, userID string) ([]*accessrequests.AccessRequest, error) {
+	canViewAll, err := m.canViewAll(ctx, accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := m.store.GetAccessRequests(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if canViewAll {
+		return requests, nil
+	}
+
+	own := make([]*accessrequests.AccessRequest, 0)
+	for _, request := range requests {
+		if request.UserID == userID {
+			own = append(own, request)
+		}
+	}
+	return own, nil
+}
+
+func (m *managerImpl) GetRequest(ctx context.Context, accountID, userID, requestID string) (*accessrequests.AccessRequest, error) {
+	canViewAll, err := m.canViewAll(ctx, accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := m.store.GetAccessRequestByID(ctx, store.LockingStrengthNone, accountID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if !canViewAll && request.UserID != userID {
+		return nil, status.NewAccessRequestNotFoundError(requestID)
+	}
+	return request, nil
+}
+
+// ApproveRequest grants the access with a group of the current peers of the requesting user and a policy from that
+// group to the requested resource or group. The access lasts for the given duration, which can't exceed the
+// requested one.
+func (m *managerImpl) ApproveRequest(ctx context.Context, accountID, userID, requestID string, duration time.Duration) (*accessrequests.AccessRequest, error) {
+	request, err := m.getRequestForDecision(ctx, accountID, userID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if duration == 0 {
+		duration = request.Duration
+	}
+	if duration < 0 || duration > request.Duration {
+		return nil, status.Errorf(status.InvalidArgument, "duration must be positive and at most the requested %s", request.Duration)
+	}
+
+	if err = m.validateTarget(ctx, accountID, request.TargetType, request.TargetID); err != nil {
+		return nil, err
+	}
+
+	peers, err := m.store.GetUserPeers(ctx, store.LockingStrengthNone, accountID, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(peers) == 0 {
+		return nil, status.Errorf(status.PreconditionFailed, "user %s has no peers to grant access to", request.UserID)
+	}
+
+	target, err := m.policyTarget(ctx, accountID, request)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		request, err = getPendingRequest(ctx, transaction, accountID, requestID)
+		if err != nil {
+			return err
+		}
+
+		group := &types.Group{
+			ID:        xid.New().String(),
+			AccountID: accountID,
+			Name:      request.PolicyName(),
+			Issued:    types.GroupIssuedAPI,
+		}
+		if err = transaction.CreateGroup(ctx, group); err != nil {
+			return err
+		}
+		for _, peer := range peers {
+			if err = transaction.AddPeerToGroup(ctx, accountID, peer.ID, group.ID); err != nil {
+				return err
+			}
+		}
+
+		policy := &types.Policy{
+			ID:          xid.New().String(),
+			AccountID:   accountID,
+			Name:        request.PolicyName(),
+			Description: request.Reason,
+			Enabled:     true,
+		}
+		rule := target
+		rule.ID = xid.New().String()
+		rule.PolicyID = policy.ID
+		rule.Name = policy.Name
+		rule.Sources = []string{group.ID}
+		policy.Rules = []*types.PolicyRule{&rule}
+		if err = transaction.CreatePolicy(ctx, policy); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		request.Status = accessrequests.StatusApproved
+		request.DecidedBy = userID
+		request.DecidedAt = now
+		request.ExpiresAt = now.Add(duration)
+		request.GroupID = group.ID
+		request.PolicyID = policy.ID
+		if err = transaction.SaveAccessRequest(ctx, request); err != nil {
+			return err
+		}
+
+		return transaction.IncrementNetworkSerial(ctx, accountID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, request.ID, accountID, activity.AccessRequestApproved, request.EventMeta())
+	m.accountManager.UpdateAccountPeers(ctx, accountID)
+
+	return request, nil
+}
+
+func (m *managerImpl) RejectRequest(ctx context.Context, accountID, userID, requestID, note string) (*accessrequests.AccessRequest, error) {
+	request, err := m.getRequestForDecision(ctx, accountID, userID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		request, err = getPendingRequest(ctx, transaction, accountID, requestID)
+		if err != nil {
+			return err
+		}
+
+		request.Status = accessrequests.StatusRejected
+		request.DecidedBy = userID
+		request.DecidedAt = time.Now().UTC()
+		request.DecisionNote = note
+		return transaction.SaveAccessRequest(ctx, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, request.ID, accountID, activity.AccessRequestRejected, request.EventMeta())
+
+	return request, nil
+}
+
+// RevokeRequest can be called by the requesting user or an approver. Pending requests are cancelled, the access of
+// approved requests is removed.
+func (m *managerImpl) RevokeRequest(ctx context.Context, accountID, userID, requestID string) (*accessrequests.AccessRequest, error) {
+	user, err := m.getAccountUser(ctx, accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := m.store.GetAccessRequestByID(ctx, store.LockingStrengthNone, accountID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.UserID != userID {
+		settings, err := getSettings(ctx, m.store, accountID)
+		if err != nil {
+			return nil, err
+		}
+		if !settings.IsApprover(user) {
+			return nil, status.NewPermissionDeniedError()
+		}
+	}
+
+	switch request.Status {
+	case accessrequests.StatusPending:
+		err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+			request, err = getPendingRequest(ctx, transaction, accountID, requestID)
+			if err != nil {
+				return err
+			}
+
+			request.Status = accessrequests.StatusCancelled
+			request.DecidedBy = userID
+			request.DecidedAt = time.Now().UTC()
+			return transaction.SaveAccessRequest(ctx, request)
+		})
+		if err != nil {
+			return nil, err
+		}
+	case accessrequests.StatusApproved:
+		request, err = m.removeAccess(ctx, request, accessrequests.StatusRevoked, userID)
+		if err != nil {
+			return nil, err
+		}
+		m.accountManager.UpdateAccountPeers(ctx, accountID)
+	default:
+		return nil, status.Errorf(status.PreconditionFailed, "access request %s is %s", requestID, request.Status)
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, request.ID, accountID, activity.AccessRequestRevoked, request.EventMeta())
+
+	return request, nil
+}
+
+func (m *managerImpl) GetSettings(ctx context.Context, accountID, userID string) (*accessrequests.Settings, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, modules.Settings, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return getSettings(ctx, m.store, accountID)
+}
+
+func (m *managerImpl) UpdateSettings(ctx context.Context, accountID, userID string, settings *accessrequests.Settings) (*accessrequests.Settings, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, modules.Settings, operations.Update); err != nil {
+		return nil, err
+	}
+
+	for _, groupID := range settings.ApproverGroups {
+		if _, err := m.store.GetGroupByID(ctx, store.LockingStrengthNone, accountID, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	settings.AccountID = accountID
+	settings.UpdatedAt = time.Now().UTC()
+	if err := m.store.SaveAccessRequestSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, accountID, accountID, activity.AccessRequestSettingsUpdated, settings.EventMeta())
+
+	return settings, nil
+}
+
+// ExpireRequests removes the temporary group and policy of all expired requests and updates the peers of the
+// affected accounts
+func (m *managerImpl) ExpireRequests(ctx context.Context) error {
+	expired, err := m.store.GetExpiredAccessRequests(ctx, store.LockingStrengthNone, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	accounts := make(map[string]struct{})
+	for _, request := range expired {
+		updated, err := m.removeAccess(ctx, request, accessrequests.StatusExpired, "")
+		if err != nil {
+			log.WithContext(ctx).Errorf("failed to expire access request %s of account %s: %v", request.ID, request.AccountID, err)
+			continue
+		}
+		accounts[updated.AccountID] = struct{}{}
+		m.accountManager.StoreEvent(ctx, activity.SystemInitiator, updated.ID, updated.AccountID, activity.AccessRequestExpired, updated.EventMeta())
+	}
+
+	for accountID := range accounts {
+		m.accountManager.UpdateAccountPeers(ctx, accountID)
+	}
+
+	return nil
+}
+
+func (m *managerImpl) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(expirationInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := m.ExpireRequests(ctx); err != nil {
+				log.WithContext(ctx).Errorf("failed to expire access requests: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// removeAccess deletes the temporary group and policy of an approved request and moves it to the given status
+func (m *managerImpl) removeAccess(ctx context.Context, request *accessrequests.AccessRequest, newStatus accessrequests.Status, userID string) (*accessrequests.AccessRequest, error) {
+	accountID := request.AccountID
+	requestID := request.ID
+
+	err := m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		var err error
+		request, err = transaction.GetAccessRequestByID(ctx, store.LockingStrengthUpdate, accountID, requestID)
+		if err != nil {
+			return err
+		}
+		if request.Status != accessrequests.StatusApproved {
+			return status.Errorf(status.PreconditionFailed, "access request %s is %s", requestID, request.Status)
+		}
+
+		// the policy and group could have been removed manually in the meantime
+		if err = transaction.DeletePolicy(ctx, accountID, request.PolicyID); err != nil && !isNotFound(err) {
+			return err
+		}
+		if err = transaction.DeleteGroup(ctx, accountID, request.GroupID); err != nil && !isNotFound(err) {
+			return err
+		}
+
+		request.Status = newStatus
+		if userID != "" {
+			request.DecidedBy = userID
+			request.DecidedAt = time.Now().UTC()
+		}
+		if err = transaction.SaveAccessRequest(ctx, request); err != nil {
+			return err
+		}
+
+		return transaction.IncrementNetworkSerial(ctx, accountID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// getPendingRequest locks the request for the decision and checks that it hasn't been decided in the meantime
+func getPendingRequest(ctx context.Context, transaction store.Store, accountID, requestID string) (*accessrequests.AccessRequest, error) {
+	request, err := transaction.GetAccessRequestByID(ctx, store.LockingStrengthUpdate, accountID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != accessrequests.StatusPending {
+		return nil, status.Errorf(status.PreconditionFailed, "access request %s is %s", requestID, request.Status)
+	}
+	return request, nil
+}
+
+// getRequestForDecision returns a pending request that the user is allowed to approve or reject. Approvers can't
+// decide on their own requests.
+func (m *managerImpl) getRequestForDecision(ctx context.Context, accountID, userID, requestID string) (*accessrequests.AccessRequest, error) {
+	user, err := m.getAccountUser(ctx, accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := getSettings(ctx, m.store, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.IsApprover(user) {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	request, err := m.store.GetAccessRequestByID(ctx, store.LockingStrengthNone, accountID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.UserID == userID {
+		return nil, status.Errorf(status.PermissionDenied, "users can't decide on their own access requests")
+	}
+	if request.Status != accessrequests.StatusPending {
+		return nil, status.Errorf(status.PreconditionFailed, "access request %s is %s", requestID, request.Status)
+	}
+	return request, nil
+}
+
+func (m *managerImpl) validateTarget(ctx context.Context, accountID string, targetType accessrequests.TargetType, targetID string) error {
+	switch targetType {
+	case accessrequests.TargetResource:
+		_, err := m.store.GetNetworkResourceByID(ctx, store.LockingStrengthNone, accountID, targetID)
+		return err
+	case accessrequests.TargetGroup:
+		_, err := m.store.GetGroupByID(ctx, store.LockingStrengthNone, accountID, targetID)
+		return err
+	default:
+		return status.Errorf(status.InvalidArgument, "unknown target type %s", targetType)
+	}
+}
+
+// policyTarget returns a rule allowing all traffic to the target of the request
+func (m *managerImpl) policyTarget(ctx context.Context, accountID string, request *accessrequests.AccessRequest) (types.PolicyRule, error) {
+	rule := types.PolicyRule{
+		Enabled:  true,
+		Action:   types.PolicyTrafficActionAccept,
+		Protocol: types.PolicyRuleProtocolALL,
+	}
+
+	if request.TargetType == accessrequests.TargetGroup {
+		rule.Destinations = []string{request.TargetID}
+		return rule, nil
+	}
+
+	resource, err := m.store.GetNetworkResourceByID(ctx, store.LockingStrengthNone, accountID, request.TargetID)
+	if err != nil {
+		return rule, err
+	}
+	rule.DestinationResource = types.Resource{ID: resource.ID, Type: types.ResourceType(resource.Type)}
+	return rule, nil
+}
+
+func (m *managerImpl) canViewAll(ctx context.Context, accountID, userID string) (bool, error) {
+	user, err := m.getAccountUser(ctx, accountID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	settings, err := getSettings(ctx, m.store, accountID)
+	if err != nil {
+		return false, err
+	}
+	if settings.IsApprover(user) {
+		return true, nil
+	}
+
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Policies, operations.Read)
+	if err != nil {
+		return false, status.NewPermissionValidationError(err)
+	}
+	return ok, nil
+}
+
+// getAccountUser returns the user if it belongs to the account and isn't blocked
+func (m *managerImpl) getAccountUser(ctx context.Context, accountID, userID string) (*types.User, error) {
+	user, err := m.store.GetUserByUserID(ctx, store.LockingStrengthNone, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.AccountID != accountID || user.IsBlocked() {
+		return nil, status.NewPermissionDeniedError()
+	}
+	return user, nil
+}
+
+func (m *managerImpl) validatePermissions(ctx context.Context, accountID, userID string, module modules.Module, operation operations.Operation) error {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, module, operation)
+	if err != nil {
+		return status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return status.NewPermissionDeniedError()
+	}
+	return nil
+}
+
+// getSettings returns the stored settings of the account or the default ones if the account has none
+func getSettings(ctx context.Context, s store.Store, accountID string) (*accessrequests.Settings, error) {
+	settings, err := s.GetAccessRequestSettings(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		if isNotFound(err) {
+			return accessrequests.NewSettings(accountID), nil
+		}
+		return nil, err
+	}
+	return settings, nil
+}
+
+func isNotFound(err error) bool {
+	sErr, ok := status.FromError(err)
+	return ok && sErr.Type() == status.NotFound
+}
@@ -0,0 +1,338 @@
+package manager
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	networkTypes "github.com/netbirdio/netbird/management/server/networks/types"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	testAccountID  = "account-id"
+	adminID        = "admin-id"
+	requesterID    = "requester-id"
+	noPeersUserID  = "no-peers-user-id"
+	approverID     = "approver-id"
+	approversGroup = "approvers-group-id"
+	serversGroup   = "servers-group-id"
+	requesterPeer  = "requester-peer-id"
+	serverPeer     = "server-peer-id"
+	databaseID     = "database-resource-id"
+)
+
+func setupTest(t *testing.T) (*managerImpl, store.Store, *mock_server.MockAccountManager, func()) {
+	t.Helper()
+
+	ctx := context.Background()
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+
+	err = testStore.SaveAccount(ctx, &types.Account{
+		Id:      testAccountID,
+		Network: types.NewNetwork(),
+		Users: map[string]*types.User{
+			adminID:       {Id: adminID, AccountID: testAccountID, Role: types.UserRoleAdmin},
+			requesterID:   {Id: requesterID, AccountID: testAccountID, Role: types.UserRoleUser},
+			noPeersUserID: {Id: noPeersUserID, AccountID: testAccountID, Role: types.UserRoleUser},
+			approverID:    {Id: approverID, AccountID: testAccountID, Role: types.UserRoleUser, AutoGroups: []string{approversGroup}},
+		},
+		Peers: map[string]*nbpeer.Peer{
+			requesterPeer: {ID: requesterPeer, AccountID: testAccountID, Key: "requester-key", IP: net.IP{100, 64, 0, 1}, DNSLabel: "requester", UserID: requesterID, Status: &nbpeer.PeerStatus{}},
+			serverPeer:    {ID: serverPeer, AccountID: testAccountID, Key: "server-key", IP: net.IP{100, 64, 0, 2}, DNSLabel: "server", Status: &nbpeer.PeerStatus{}},
+		},
+		Groups: map[string]*types.Group{
+			approversGroup: {ID: approversGroup, AccountID: testAccountID, Name: "approvers", Peers: []string{}},
+			serversGroup:   {ID: serversGroup, AccountID: testAccountID, Name: "servers", Peers: []string{serverPeer}},
+		},
+		Networks: []*networkTypes.Network{
+			{ID: "network-id", AccountID: testAccountID, Name: "office"},
+		},
+		NetworkResources: []*resourceTypes.NetworkResource{
+			{ID: databaseID, NetworkID: "network-id", AccountID: testAccountID, Name: "database", Type: resourceTypes.Host, Address: "10.0.0.10/32", Enabled: true},
+		},
+		Settings:    &types.Settings{},
+		DNSSettings: types.DNSSettings{DisabledManagementGroups: []string{}},
+	})
+	require.NoError(t, err)
+
+	settings := accessrequests.NewSettings(testAccountID)
+	settings.ApproverGroups = []string{approversGroup}
+	err = testStore.SaveAccessRequestSettings(ctx, settings)
+	require.NoError(t, err)
+
+	mockAccountManager := &mock_server.MockAccountManager{}
+
+	manager := &managerImpl{
+		store:              testStore,
+		accountManager:     mockAccountManager,
+		permissionsManager: permissions.NewManager(testStore),
+	}
+
+	return manager, testStore, mockAccountManager, cleanup
+}
+
+func createRequest(t *testing.T, manager *managerImpl, targetType accessrequests.TargetType, targetID string, duration time.Duration) *accessrequests.AccessRequest {
+	t.Helper()
+	request, err := manager.CreateRequest(context.Background(), testAccountID, requesterID, &accessrequests.AccessRequest{
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reason:     "debugging",
+		Duration:   duration,
+	})
+	require.NoError(t, err)
+	return request
+}
+
+func TestManagerImpl_CreateRequest(t *testing.T) {
+	ctx := context.Background()
+	manager, _, mockAccountManager, cleanup := setupTest(t)
+	defer cleanup()
+
+	var events []activity.ActivityDescriber
+	mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+		events = append(events, activityID)
+	}
+
+	request := createRequest(t, manager, accessrequests.TargetResource, databaseID, time.Hour)
+	assert.Equal(t, accessrequests.StatusPending, request.Status)
+	assert.Contains(t, events, activity.AccessRequestCreated)
+
+	tests := []struct {
+		name     string
+		userID   string
+		request  *accessrequests.AccessRequest
+		expected status.Type
+	}{
+		{"unknown resource", requesterID, &accessrequests.AccessRequest{TargetType: accessrequests.TargetResource, TargetID: "unknown", Duration: time.Hour}, status.NotFound},
+		{"unknown group", requesterID, &accessrequests.AccessRequest{TargetType: accessrequests.TargetGroup, TargetID: "unknown", Duration: time.Hour}, status.NotFound},
+		{"too long", requesterID, &accessrequests.AccessRequest{TargetType: accessrequests.TargetGroup, TargetID: serversGroup, Duration: 24 * time.Hour}, status.InvalidArgument},
+		{"user without peers", noPeersUserID, &accessrequests.AccessRequest{TargetType: accessrequests.TargetGroup, TargetID: serversGroup, Duration: time.Hour}, status.PreconditionFailed},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := manager.CreateRequest(ctx, testAccountID, tc.userID, tc.request)
+			require.Error(t, err)
+			s, ok := status.FromError(err)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, s.Type())
+		})
+	}
+
+	own, err := manager.ListRequests(ctx, testAccountID, noPeersUserID)
+	require.NoError(t, err)
+	assert.Empty(t, own, "users should only see their own requests")
+
+	all, err := manager.ListRequests(ctx, testAccountID, approverID)
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+
+	_, err = manager.GetRequest(ctx, testAccountID, noPeersUserID, request.ID)
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.NotFound, s.Type())
+}
+
+func TestManagerImpl_ApproveAndExpire(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, mockAccountManager, cleanup := setupTest(t)
+	defer cleanup()
+
+	var events []activity.ActivityDescriber
+	mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+		events = append(events, activityID)
+	}
+
+	var updated []string
+	mockAccountManager.UpdateAccountPeersFunc = func(_ context.Context, accountID string) {
+		updated = append(updated, accountID)
+	}
+
+	request := createRequest(t, manager, accessrequests.TargetResource, databaseID, time.Hour)
+
+	_, err := manager.ApproveRequest(ctx, testAccountID, noPeersUserID, request.ID, 0)
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+
+	_, err = manager.ApproveRequest(ctx, testAccountID, approverID, request.ID, 2*time.Hour)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.InvalidArgument, s.Type())
+
+	approved, err := manager.ApproveRequest(ctx, testAccountID, approverID, request.ID, 30*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, accessrequests.StatusApproved, approved.Status)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), approved.ExpiresAt, time.Minute)
+	assert.Contains(t, events, activity.AccessRequestApproved)
+	assert.Equal(t, []string{testAccountID}, updated)
+
+	group, err := testStore.GetGroupByID(ctx, store.LockingStrengthNone, testAccountID, approved.GroupID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{requesterPeer}, group.Peers)
+
+	policy, err := testStore.GetPolicyByID(ctx, store.LockingStrengthNone, testAccountID, approved.PolicyID)
+	require.NoError(t, err)
+	require.Len(t, policy.Rules, 1)
+	assert.Equal(t, []string{group.ID}, policy.Rules[0].Sources)
+	assert.Equal(t, types.Resource{ID: databaseID, Type: types.ResourceTypeHost}, policy.Rules[0].DestinationResource)
+
+	_, err = manager.ApproveRequest(ctx, testAccountID, approverID, request.ID, 0)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PreconditionFailed, s.Type())
+
+	require.NoError(t, manager.ExpireRequests(ctx))
+	stored, err := testStore.GetAccessRequestByID(ctx, store.LockingStrengthNone, testAccountID, request.ID)
+	require.NoError(t, err)
+	assert.Equal(t, accessrequests.StatusApproved, stored.Status, "the access shouldn't expire early")
+
+	stored.ExpiresAt = time.Now().UTC().Add(-time.Minute)
+	require.NoError(t, testStore.SaveAccessRequest(ctx, stored))
+	require.NoError(t, manager.ExpireRequests(ctx))
+
+	stored, err = testStore.GetAccessRequestByID(ctx, store.LockingStrengthNone, testAccountID, request.ID)
+	require.NoError(t, err)
+	assert.Equal(t, accessrequests.StatusExpired, stored.Status)
+	assert.Contains(t, events, activity.AccessRequestExpired)
+
+	_, err = testStore.GetPolicyByID(ctx, store.LockingStrengthNone, testAccountID, approved.PolicyID)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.NotFound, s.Type())
+	_, err = testStore.GetGroupByID(ctx, store.LockingStrengthNone, testAccountID, approved.GroupID)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.NotFound, s.Type())
+}
+
+func TestManagerImpl_RejectAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, mockAccountManager, cleanup := setupTest(t)
+	defer cleanup()
+
+	var events []activity.ActivityDescriber
+	mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+		events = append(events, activityID)
+	}
+
+	rejected := createRequest(t, manager, accessrequests.TargetGroup, serversGroup, time.Hour)
+	_, err := manager.RejectRequest(ctx, testAccountID, requesterID, rejected.ID, "")
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+
+	rejected, err = manager.RejectRequest(ctx, testAccountID, adminID, rejected.ID, "use staging")
+	require.NoError(t, err)
+	assert.Equal(t, accessrequests.StatusRejected, rejected.Status)
+	assert.Equal(t, "use staging", rejected.DecisionNote)
+
+	cancelled := createRequest(t, manager, accessrequests.TargetGroup, serversGroup, time.Hour)
+	_, err = manager.RevokeRequest(ctx, testAccountID, noPeersUserID, cancelled.ID)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+	cancelled, err = manager.RevokeRequest(ctx, testAccountID, requesterID, cancelled.ID)
+	require.NoError(t, err)
+	assert.Equal(t, accessrequests.StatusCancelled, cancelled.Status)
+
+	revoked := createRequest(t, manager, accessrequests.TargetGroup, serversGroup, time.Hour)
+	revoked, err = manager.ApproveRequest(ctx, testAccountID, adminID, revoked.ID, 0)
+	require.NoError(t, err)
+	policy, err := testStore.GetPolicyByID(ctx, store.LockingStrengthNone, testAccountID, revoked.PolicyID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{serversGroup}, policy.Rules[0].Destinations)
+
+	revoked, err = manager.RevokeRequest(ctx, testAccountID, adminID, revoked.ID)
+	require.NoError(t, err)
+	assert.Equal(t, accessrequests.StatusRevoked, revoked.Status)
+	_, err = testStore.GetPolicyByID(ctx, store.LockingStrengthNone, testAccountID, revoked.PolicyID)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.NotFound, s.Type())
+	assert.Contains(t, events, activity.AccessRequestRevoked)
+
+	_, err = manager.RevokeRequest(ctx, testAccountID, adminID, revoked.ID)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PreconditionFailed, s.Type())
+}
+
+func TestManagerImpl_Settings(t *testing.T) {
+	ctx := context.Background()
+	manager, _, mockAccountManager, cleanup := setupTest(t)
+	defer cleanup()
+
+	var events []activity.ActivityDescriber
+	mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+		events = append(events, activityID)
+	}
+
+	settings, err := manager.GetSettings(ctx, testAccountID, adminID)
+	require.NoError(t, err)
+	assert.Equal(t, accessrequests.DefaultMaxDuration, settings.MaxDuration)
+
+	_, err = manager.UpdateSettings(ctx, testAccountID, requesterID, &accessrequests.Settings{MaxDuration: time.Hour})
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+
+	_, err = manager.UpdateSettings(ctx, testAccountID, adminID, &accessrequests.Settings{ApproverGroups: []string{"unknown"}, MaxDuration: time.Hour})
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.NotFound, s.Type())
+
+	_, err = manager.UpdateSettings(ctx, testAccountID, adminID, &accessrequests.Settings{
+		ApproverRoles: []types.UserRole{types.UserRoleOwner},
+		MaxDuration:   time.Hour,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, events, activity.AccessRequestSettingsUpdated)
+
+	request := createRequest(t, manager, accessrequests.TargetGroup, serversGroup, time.Hour)
+	_, err = manager.ApproveRequest(ctx, testAccountID, adminID, request.ID, 0)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+	_, err = manager.ApproveRequest(ctx, testAccountID, approverID, request.ID, 0)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+
+	_, err = manager.CreateRequest(ctx, testAccountID, requesterID, &accessrequests.AccessRequest{
+		TargetType: accessrequests.TargetGroup,
+		TargetID:   serversGroup,
+		Duration:   2 * time.Hour,
+	})
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.InvalidArgument, s.Type())
+}
@@ -0,0 +1,258 @@
+package accessrequests
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// Status is the state of an access request
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusApproved  Status = "approved"
+	StatusRejected  Status = "rejected"
+	StatusCancelled Status = "cancelled"
+	StatusRevoked   Status = "revoked"
+	StatusExpired   Status = "expired"
+)
+
+// TargetType is the type of object access is requested to
+type TargetType string
+
+const (
+	TargetResource TargetType = "resource"
+	TargetGroup    TargetType = "group"
+)
+
+// AccessRequest is a request of a user for temporary access from its peers to a network resource or to the peers
+// of a group. Approving the request creates a group with the peers of the user and a policy that grants the access.
+// Both are removed when the access expires or is revoked.
+type AccessRequest struct {
+	ID         string `gorm:"primaryKey"`
+	AccountID  string `gorm:"index"`
+	UserID     string
+	TargetType TargetType
+	TargetID   string
+	Reason     string
+	// Duration is the requested duration of the access
+	Duration time.Duration
+	Status   Status `gorm:"index"`
+	// DecidedBy is the ID of the user that approved, rejected or revoked the request
+	DecidedBy    string
+	DecisionNote string
+	// GroupID and PolicyID are the temporary objects granting the access of an approved request
+	GroupID   string
+	PolicyID  string
+	CreatedAt time.Time
+	DecidedAt time.Time
+	// ExpiresAt is set when the request is approved
+	ExpiresAt time.Time `gorm:"index"`
+}
+
+// TableName returns the name of the table for the AccessRequest model in the database.
+func (*AccessRequest) TableName() string {
+	return "access_requests"
+}
+
+// NewAccessRequest returns a pending request of the user
+func NewAccessRequest(accountID, userID string, targetType TargetType, targetID, reason string, duration time.Duration) *AccessRequest {
+	return &AccessRequest{
+		ID:         xid.New().String(),
+		AccountID:  accountID,
+		UserID:     userID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reason:     reason,
+		Duration:   duration,
+		Status:     StatusPending,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+// FromAPIRequest parses a new access request
+func FromAPIRequest(req *api.AccessRequestRequest) (*AccessRequest, error) {
+	targetType := TargetType(req.TargetType)
+	if targetType != TargetResource && targetType != TargetGroup {
+		return nil, status.Errorf(status.InvalidArgument, "target type must be %s or %s", TargetResource, TargetGroup)
+	}
+	if req.TargetId == "" {
+		return nil, status.Errorf(status.InvalidArgument, "target ID is required")
+	}
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "invalid duration %q: %s", req.Duration, err)
+	}
+
+	var reason string
+	if req.Reason != nil {
+		reason = strings.TrimSpace(*req.Reason)
+	}
+	return &AccessRequest{TargetType: targetType, TargetID: req.TargetId, Reason: reason, Duration: duration}, nil
+}
+
+// IsActive returns true if the access of an approved request hasn't expired yet
+func (r *AccessRequest) IsActive(now time.Time) bool {
+	return r.Status == StatusApproved && now.Before(r.ExpiresAt)
+}
+
+// PolicyName returns the name of the temporary policy granting the access
+func (r *AccessRequest) PolicyName() string {
+	return fmt.Sprintf("Temporary access %s", r.ID)
+}
+
+// EventMeta returns activity event meta related to the request
+func (r *AccessRequest) EventMeta() map[string]any {
+	meta := map[string]any{
+		"user_id":     r.UserID,
+		"target_type": r.TargetType,
+		"target_id":   r.TargetID,
+		"duration":    r.Duration.String(),
+		"status":      r.Status,
+	}
+	if r.Reason != "" {
+		meta["reason"] = r.Reason
+	}
+	if !r.ExpiresAt.IsZero() {
+		meta["expires_at"] = r.ExpiresAt
+	}
+	if r.PolicyID != "" {
+		meta["policy_id"] = r.PolicyID
+	}
+	if r.DecisionNote != "" {
+		meta["note"] = r.DecisionNote
+	}
+	return meta
+}
+
+// ToAPIResponse converts the request to its API representation
+func (r *AccessRequest) ToAPIResponse() *api.AccessRequest {
+	resp := &api.AccessRequest{
+		Id:         r.ID,
+		UserId:     r.UserID,
+		TargetType: api.AccessRequestTargetType(r.TargetType),
+		TargetId:   r.TargetID,
+		Reason:     r.Reason,
+		Duration:   r.Duration.String(),
+		Status:     api.AccessRequestStatus(r.Status),
+		CreatedAt:  r.CreatedAt,
+	}
+	if r.DecidedBy != "" {
+		resp.DecidedBy = &r.DecidedBy
+	}
+	if r.DecisionNote != "" {
+		resp.DecisionNote = &r.DecisionNote
+	}
+	if !r.DecidedAt.IsZero() {
+		resp.DecidedAt = &r.DecidedAt
+	}
+	if !r.ExpiresAt.IsZero() {
+		resp.ExpiresAt = &r.ExpiresAt
+	}
+	if r.PolicyID != "" {
+		resp.PolicyId = &r.PolicyID
+	}
+	return resp
+}
+
+// DefaultMaxDuration is the longest access that can be requested if the account doesn't configure it
+const DefaultMaxDuration = 8 * time.Hour
+
+// Settings configure who can approve the access requests of an account
+type Settings struct {
+	AccountID string `gorm:"primaryKey"`
+	// ApproverRoles are the roles whose users can approve requests
+	ApproverRoles []types.UserRole `gorm:"serializer:json"`
+	// ApproverGroups are the IDs of groups whose users can approve requests
+	ApproverGroups []string `gorm:"serializer:json"`
+	// MaxDuration is the longest access that can be requested and granted
+	MaxDuration time.Duration
+	UpdatedAt   time.Time
+}
+
+// TableName returns the name of the table for the Settings model in the database.
+func (*Settings) TableName() string {
+	return "access_request_settings"
+}
+
+// NewSettings returns the settings of an account that hasn't configured access requests: owners and admins
+// approve requests of up to DefaultMaxDuration
+func NewSettings(accountID string) *Settings {
+	return &Settings{
+		AccountID:     accountID,
+		ApproverRoles: []types.UserRole{types.UserRoleOwner, types.UserRoleAdmin},
+		MaxDuration:   DefaultMaxDuration,
+	}
+}
+
+// SettingsFromAPIRequest parses the settings of an update request
+func SettingsFromAPIRequest(req *api.AccessRequestSettings) (*Settings, error) {
+	settings := &Settings{
+		ApproverGroups: req.ApproverGroups,
+	}
+	for _, role := range req.ApproverRoles {
+		userRole := types.StrRoleToUserRole(role)
+		if userRole == types.UserRoleUnknown {
+			return nil, status.Errorf(status.InvalidArgument, "unknown approver role %s", role)
+		}
+		settings.ApproverRoles = append(settings.ApproverRoles, userRole)
+	}
+
+	duration, err := time.ParseDuration(req.MaxDuration)
+	if err != nil || duration <= 0 {
+		return nil, status.Errorf(status.InvalidArgument, "invalid max duration %q", req.MaxDuration)
+	}
+	settings.MaxDuration = duration
+
+	if len(settings.ApproverRoles) == 0 && len(settings.ApproverGroups) == 0 {
+		return nil, status.Errorf(status.InvalidArgument, "at least one approver role or group is required")
+	}
+	return settings, nil
+}
+
+// IsApprover checks if the user can approve requests
+func (s *Settings) IsApprover(user *types.User) bool {
+	if slices.Contains(s.ApproverRoles, user.Role) {
+		return true
+	}
+	for _, groupID := range user.AutoGroups {
+		if slices.Contains(s.ApproverGroups, groupID) {
+			return true
+		}
+	}
+	return false
+}
+
+// EventMeta returns activity event meta related to the settings
+func (s *Settings) EventMeta() map[string]any {
+	return map[string]any{
+		"approver_roles":  s.ApproverRoles,
+		"approver_groups": s.ApproverGroups,
+		"max_duration":    s.MaxDuration.String(),
+	}
+}
+
+// ToAPIResponse converts the settings to their API representation
+func (s *Settings) ToAPIResponse() *api.AccessRequestSettings {
+	roles := make([]string, 0, len(s.ApproverRoles))
+	for _, role := range s.ApproverRoles {
+		roles = append(roles, string(role))
+	}
+	groups := s.ApproverGroups
+	if groups == nil {
+		groups = []string{}
+	}
+	return &api.AccessRequestSettings{
+		ApproverRoles:  roles,
+		ApproverGroups: groups,
+		MaxDuration:    s.MaxDuration.String(),
+	}
+}
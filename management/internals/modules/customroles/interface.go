@@ -0,0 +1,15 @@
+package customroles
+
+import (
+	"context"
+
+	"github.com/netbirdio/netbird/management/server/permissions/roles"
+)
+
+type Manager interface {
+	GetAllRoles(ctx context.Context, accountID, userID string) ([]*roles.CustomRole, error)
+	GetRole(ctx context.Context, accountID, userID, roleID string) (*roles.CustomRole, error)
+	CreateRole(ctx context.Context, accountID, userID string, role *roles.CustomRole) (*roles.CustomRole, error)
+	UpdateRole(ctx context.Context, accountID, userID string, role *roles.CustomRole) (*roles.CustomRole, error)
+	DeleteRole(ctx context.Context, accountID, userID, roleID string) error
+}
@@ -0,0 +1,174 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/netbirdio/netbird/management/internals/modules/customroles"
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/permissions/roles"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type managerImpl struct {
+	store              store.Store
+	accountManager     account.Manager
+	permissionsManager permissions.Manager
+}
+
+func NewManager(store store.Store, accountManager account.Manager, permissionsManager permissions.Manager) customroles.Manager {
+	return &managerImpl{
+		store:              store,
+		accountManager:     accountManager,
+		permissionsManager: permissionsManager,
+	}
+}
+
+func (m *managerImpl) GetAllRoles(ctx context.Context, accountID, userID string) ([]*roles.CustomRole, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetAccountCustomRoles(ctx, store.LockingStrengthNone, accountID)
+}
+
+func (m *managerImpl) GetRole(ctx context.Context, accountID, userID, roleID string) (*roles.CustomRole, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetCustomRoleByID(ctx, store.LockingStrengthNone, accountID, roleID)
+}
+
+func (m *managerImpl) CreateRole(ctx context.Context, accountID, userID string, role *roles.CustomRole) (*roles.CustomRole, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Create); err != nil {
+		return nil, err
+	}
+
+	role = roles.NewCustomRole(accountID, role.Name, role.Description, role.Permissions)
+	if err := role.Validate(); err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "%s", err.Error())
+	}
+
+	err := m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		if err := validateRoleNameUnique(ctx, transaction, role); err != nil {
+			return err
+		}
+
+		if err := transaction.SaveCustomRole(ctx, role); err != nil {
+			return fmt.Errorf("failed to create custom role: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, role.ID, accountID, activity.CustomRoleCreated, role.EventMeta())
+
+	return role, nil
+}
+
+func (m *managerImpl) UpdateRole(ctx context.Context, accountID, userID string, updatedRole *roles.CustomRole) (*roles.CustomRole, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Update); err != nil {
+		return nil, err
+	}
+
+	if err := updatedRole.Validate(); err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "%s", err.Error())
+	}
+
+	var role *roles.CustomRole
+	err := m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		var err error
+		role, err = transaction.GetCustomRoleByID(ctx, store.LockingStrengthUpdate, accountID, updatedRole.ID)
+		if err != nil {
+			return err
+		}
+
+		role.Name = updatedRole.Name
+		role.Description = updatedRole.Description
+		role.Permissions = updatedRole.Permissions
+
+		if err = validateRoleNameUnique(ctx, transaction, role); err != nil {
+			return err
+		}
+
+		if err = transaction.SaveCustomRole(ctx, role); err != nil {
+			return fmt.Errorf("failed to update custom role: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, role.ID, accountID, activity.CustomRoleUpdated, role.EventMeta())
+
+	return role, nil
+}
+
+func (m *managerImpl) DeleteRole(ctx context.Context, accountID, userID, roleID string) error {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Delete); err != nil {
+		return err
+	}
+
+	var role *roles.CustomRole
+	err := m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		var err error
+		role, err = transaction.GetCustomRoleByID(ctx, store.LockingStrengthUpdate, accountID, roleID)
+		if err != nil {
+			return err
+		}
+
+		users, err := transaction.GetAccountUsers(ctx, store.LockingStrengthNone, accountID)
+		if err != nil {
+			return fmt.Errorf("failed to get account users: %w", err)
+		}
+		for _, user := range users {
+			if user.CustomRoleID == roleID {
+				return status.Errorf(status.PreconditionFailed, "custom role %s is assigned to user %s", role.Name, user.Id)
+			}
+		}
+
+		return transaction.DeleteCustomRole(ctx, accountID, roleID)
+	})
+	if err != nil {
+		return err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, roleID, accountID, activity.CustomRoleDeleted, role.EventMeta())
+
+	return nil
+}
+
+func (m *managerImpl) validatePermissions(ctx context.Context, accountID, userID string, operation operations.Operation) error {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Roles, operation)
+	if err != nil {
+		return status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return status.NewPermissionDeniedError()
+	}
+	return nil
+}
+
+// validateRoleNameUnique checks that no other custom role of the account has the same name
+func validateRoleNameUnique(ctx context.Context, transaction store.Store, role *roles.CustomRole) error {
+	existingRoles, err := transaction.GetAccountCustomRoles(ctx, store.LockingStrengthNone, role.AccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get custom roles: %w", err)
+	}
+
+	for _, existing := range existingRoles {
+		if existing.ID != role.ID && existing.Name == role.Name {
+			return status.Errorf(status.AlreadyExists, "custom role with name %s already exists", role.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,333 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/permissions/roles"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	testAccountID = "test-account-id"
+	testUserID    = "test-user-id"
+)
+
+func setupTest(t *testing.T) (*managerImpl, store.Store, *mock_server.MockAccountManager, *permissions.MockManager, *gomock.Controller, func()) {
+	t.Helper()
+
+	ctx := context.Background()
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+
+	err = testStore.SaveAccount(ctx, &types.Account{
+		Id: testAccountID,
+		Users: map[string]*types.User{
+			testUserID: {Id: testUserID, AccountID: testAccountID, Role: types.UserRoleAdmin},
+		},
+	})
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	mockAccountManager := &mock_server.MockAccountManager{}
+	mockPermissionsManager := permissions.NewMockManager(ctrl)
+
+	manager := &managerImpl{
+		store:              testStore,
+		accountManager:     mockAccountManager,
+		permissionsManager: mockPermissionsManager,
+	}
+
+	return manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup
+}
+
+func helpdeskRole() *roles.CustomRole {
+	return &roles.CustomRole{
+		Name: "helpdesk",
+		Permissions: roles.Permissions{
+			modules.Peers: {operations.Read: true, operations.Update: true},
+		},
+	}
+}
+
+func TestManagerImpl_CreateRole(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		var events []activity.ActivityDescriber
+		mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+			events = append(events, activityID)
+		}
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Roles, operations.Create).
+			Return(true, nil)
+
+		role, err := manager.CreateRole(ctx, testAccountID, testUserID, helpdeskRole())
+		require.NoError(t, err)
+		assert.NotEmpty(t, role.ID)
+		assert.Equal(t, testAccountID, role.AccountID)
+		assert.Equal(t, []activity.ActivityDescriber{activity.CustomRoleCreated}, events)
+
+		stored, err := testStore.GetCustomRoleByID(ctx, store.LockingStrengthNone, testAccountID, role.ID)
+		require.NoError(t, err)
+		assert.Equal(t, role.Permissions, stored.Permissions)
+	})
+
+	t.Run("permissions above admin", func(t *testing.T) {
+		manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Roles, operations.Create).
+			Return(true, nil)
+
+		role := helpdeskRole()
+		role.Permissions[modules.Accounts] = map[operations.Operation]bool{operations.Delete: true}
+
+		_, err := manager.CreateRole(ctx, testAccountID, testUserID, role)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.InvalidArgument, s.Type())
+	})
+
+	t.Run("roles module", func(t *testing.T) {
+		manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Roles, operations.Create).
+			Return(true, nil)
+
+		role := helpdeskRole()
+		role.Permissions[modules.Roles] = map[operations.Operation]bool{operations.Update: true}
+
+		_, err := manager.CreateRole(ctx, testAccountID, testUserID, role)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.InvalidArgument, s.Type())
+	})
+
+	t.Run("unknown module", func(t *testing.T) {
+		manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Roles, operations.Create).
+			Return(true, nil)
+
+		role := helpdeskRole()
+		role.Permissions["unknown"] = map[operations.Operation]bool{operations.Read: true}
+
+		_, err := manager.CreateRole(ctx, testAccountID, testUserID, role)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.InvalidArgument, s.Type())
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Roles, operations.Create).
+			Return(true, nil).
+			Times(2)
+
+		_, err := manager.CreateRole(ctx, testAccountID, testUserID, helpdeskRole())
+		require.NoError(t, err)
+
+		_, err = manager.CreateRole(ctx, testAccountID, testUserID, helpdeskRole())
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.AlreadyExists, s.Type())
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Roles, operations.Create).
+			Return(false, nil)
+
+		_, err := manager.CreateRole(ctx, testAccountID, testUserID, helpdeskRole())
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.PermissionDenied, s.Type())
+	})
+}
+
+func TestManagerImpl_UpdateRole(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		var events []activity.ActivityDescriber
+		mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+			events = append(events, activityID)
+		}
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Roles, operations.Create).
+			Return(true, nil)
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Roles, operations.Update).
+			Return(true, nil)
+
+		role, err := manager.CreateRole(ctx, testAccountID, testUserID, helpdeskRole())
+		require.NoError(t, err)
+
+		update := helpdeskRole()
+		update.ID = role.ID
+		update.Name = "support"
+		update.Permissions = roles.Permissions{modules.Peers: {operations.Read: true}}
+
+		updated, err := manager.UpdateRole(ctx, testAccountID, testUserID, update)
+		require.NoError(t, err)
+		assert.Equal(t, "support", updated.Name)
+		assert.Equal(t, []activity.ActivityDescriber{activity.CustomRoleCreated, activity.CustomRoleUpdated}, events)
+
+		stored, err := testStore.GetCustomRoleByID(ctx, store.LockingStrengthNone, testAccountID, role.ID)
+		require.NoError(t, err)
+		assert.Equal(t, update.Permissions, stored.Permissions)
+	})
+
+	t.Run("rename to an existing name", func(t *testing.T) {
+		manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Roles, operations.Create).
+			Return(true, nil).
+			Times(2)
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Roles, operations.Update).
+			Return(true, nil)
+
+		_, err := manager.CreateRole(ctx, testAccountID, testUserID, helpdeskRole())
+		require.NoError(t, err)
+
+		other := helpdeskRole()
+		other.Name = "support"
+		other, err = manager.CreateRole(ctx, testAccountID, testUserID, other)
+		require.NoError(t, err)
+
+		update := helpdeskRole()
+		update.ID = other.ID
+		_, err = manager.UpdateRole(ctx, testAccountID, testUserID, update)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.AlreadyExists, s.Type())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Roles, operations.Update).
+			Return(true, nil)
+
+		update := helpdeskRole()
+		update.ID = "unknown"
+		_, err := manager.UpdateRole(ctx, testAccountID, testUserID, update)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.NotFound, s.Type())
+	})
+}
+
+func TestManagerImpl_DeleteRole(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		var events []activity.ActivityDescriber
+		mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+			events = append(events, activityID)
+		}
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Roles, operations.Create).
+			Return(true, nil)
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Roles, operations.Delete).
+			Return(true, nil)
+
+		role, err := manager.CreateRole(ctx, testAccountID, testUserID, helpdeskRole())
+		require.NoError(t, err)
+
+		err = manager.DeleteRole(ctx, testAccountID, testUserID, role.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []activity.ActivityDescriber{activity.CustomRoleCreated, activity.CustomRoleDeleted}, events)
+
+		_, err = testStore.GetCustomRoleByID(ctx, store.LockingStrengthNone, testAccountID, role.ID)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.NotFound, s.Type())
+	})
+
+	t.Run("assigned to a user", func(t *testing.T) {
+		manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Roles, operations.Create).
+			Return(true, nil)
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Roles, operations.Delete).
+			Return(true, nil)
+
+		role, err := manager.CreateRole(ctx, testAccountID, testUserID, helpdeskRole())
+		require.NoError(t, err)
+
+		user := types.NewRegularUser("helpdesk-user", "", "")
+		user.AccountID = testAccountID
+		user.CustomRoleID = role.ID
+		require.NoError(t, testStore.SaveUser(ctx, user))
+
+		err = manager.DeleteRole(ctx, testAccountID, testUserID, role.ID)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.PreconditionFailed, s.Type())
+	})
+}
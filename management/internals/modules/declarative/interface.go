@@ -0,0 +1,14 @@
+package declarative
+
+import (
+	"context"
+)
+
+type Manager interface {
+	// Plan returns the changes needed to make the account match the spec without applying them
+	Plan(ctx context.Context, accountID, userID string, spec *Spec) (*Plan, error)
+	// Apply makes the account match the spec in a single transaction and returns the applied changes
+	Apply(ctx context.Context, accountID, userID string, spec *Spec) (*Plan, error)
+	// GetDrift returns the managed objects that have been changed since they were applied
+	GetDrift(ctx context.Context, accountID, userID string) ([]*Drift, error)
+}
@@ -0,0 +1,58 @@
+package declarative
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Kind is the type of object managed by a spec
+type Kind string
+
+const (
+	KindGroup           Kind = "group"
+	KindPostureChecks   Kind = "posture_checks"
+	KindPolicy          Kind = "policy"
+	KindNetwork         Kind = "network"
+	KindNetworkResource Kind = "network_resource"
+	KindNetworkRouter   Kind = "network_router"
+	KindNameserverGroup Kind = "nameserver_group"
+	KindZone            Kind = "zone"
+)
+
+// kindOrder is the order in which objects are created and updated, objects are deleted in reverse order
+var kindOrder = []Kind{
+	KindGroup,
+	KindPostureChecks,
+	KindNetwork,
+	KindNetworkResource,
+	KindNetworkRouter,
+	KindPolicy,
+	KindNameserverGroup,
+	KindZone,
+}
+
+// ManagedObject marks an object of the account as created or updated by an apply.
+// Hash is the hash of the object as it was applied, a different hash of the current object means it has been
+// modified outside of the declarative configuration.
+type ManagedObject struct {
+	AccountID string `gorm:"primaryKey"`
+	Kind      Kind   `gorm:"primaryKey"`
+	ObjectID  string `gorm:"primaryKey"`
+	Name      string
+	Hash      string
+	AppliedAt time.Time
+}
+
+func (ManagedObject) TableName() string {
+	return "declarative_managed_objects"
+}
+
+// hashSpec returns the hash of the JSON encoding of a normalized spec object
+func hashSpec(spec any) string {
+	// specs are plain structs that can always be encoded
+	data, _ := json.Marshal(spec)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
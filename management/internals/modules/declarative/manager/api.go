@@ -0,0 +1,123 @@
+package manager
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netbirdio/netbird/management/internals/modules/declarative"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/http/util"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// maxSpecSize limits the size of a declarative configuration accepted by the API
+const maxSpecSize = 10 << 20
+
+type handler struct {
+	manager declarative.Manager
+}
+
+func RegisterEndpoints(router *mux.Router, manager declarative.Manager) {
+	h := &handler{
+		manager: manager,
+	}
+
+	router.HandleFunc("/declarative/plan", h.plan).Methods("POST", "OPTIONS")
+	router.HandleFunc("/declarative/apply", h.apply).Methods("POST", "OPTIONS")
+	router.HandleFunc("/declarative/drift", h.getDrift).Methods("GET", "OPTIONS")
+}
+
+func (h *handler) plan(w http.ResponseWriter, r *http.Request) {
+	h.handleSpec(w, r, h.manager.Plan)
+}
+
+func (h *handler) apply(w http.ResponseWriter, r *http.Request) {
+	h.handleSpec(w, r, h.manager.Apply)
+}
+
+func (h *handler) handleSpec(w http.ResponseWriter, r *http.Request, run func(ctx context.Context, accountID, userID string, spec *declarative.Spec) (*declarative.Plan, error)) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSpecSize))
+	if err != nil {
+		util.WriteErrorResponse("couldn't read request", http.StatusBadRequest, w)
+		return
+	}
+
+	spec, err := declarative.DecodeSpec(body)
+	if err != nil {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "%s", err.Error()), w)
+		return
+	}
+
+	plan, err := run(r.Context(), userAuth.AccountId, userAuth.UserId, spec)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, toPlanResponse(plan))
+}
+
+func (h *handler) getDrift(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	drift, err := h.manager.GetDrift(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, toDriftResponse(drift))
+}
+
+func toPlanResponse(plan *declarative.Plan) *api.DeclarativePlan {
+	changes := make([]api.DeclarativeChange, 0, len(plan.Changes))
+	for _, change := range plan.Changes {
+		apiChange := api.DeclarativeChange{
+			Kind:   string(change.Kind),
+			Name:   change.Name,
+			Action: string(change.Action),
+		}
+		if change.ID != "" {
+			apiChange.Id = &change.ID
+		}
+		if len(change.Fields) > 0 {
+			apiChange.Fields = &change.Fields
+		}
+		if change.Drifted {
+			apiChange.Drifted = &change.Drifted
+		}
+		changes = append(changes, apiChange)
+	}
+
+	return &api.DeclarativePlan{
+		Changes: changes,
+		Drift:   toDriftResponse(plan.Drift),
+	}
+}
+
+func toDriftResponse(drift []*declarative.Drift) []api.DeclarativeDrift {
+	result := make([]api.DeclarativeDrift, 0, len(drift))
+	for _, d := range drift {
+		result = append(result, api.DeclarativeDrift{
+			Kind:   string(d.Kind),
+			Name:   d.Name,
+			Id:     d.ID,
+			Reason: string(d.Reason),
+		})
+	}
+	return result
+}
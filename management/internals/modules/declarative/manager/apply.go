@@ -0,0 +1,439 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/xid"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+	"github.com/netbirdio/netbird/management/internals/modules/declarative"
+	"github.com/netbirdio/netbird/management/internals/modules/history"
+	"github.com/netbirdio/netbird/management/internals/modules/zones"
+	"github.com/netbirdio/netbird/management/internals/modules/zones/records"
+	"github.com/netbirdio/netbird/management/server"
+	"github.com/netbirdio/netbird/management/server/activity"
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
+	networkTypes "github.com/netbirdio/netbird/management/server/networks/types"
+	"github.com/netbirdio/netbird/management/server/posture"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+// applier writes the changes of a plan to the store. Names are resolved to IDs as objects are created,
+// so the changes have to be applied in the order of the plan.
+type applier struct {
+	ctx         context.Context
+	transaction store.Store
+	accountID   string
+	userID      string
+	storeEvent  func(targetID string, activityID activity.Activity, meta map[string]any)
+
+	groupIDs   map[string]string
+	checkIDs   map[string]string
+	networkIDs map[string]string
+
+	events []func()
+}
+
+func (m *managerImpl) newApplier(ctx context.Context, transaction store.Store, accountID, userID string, state *declarative.State) *applier {
+	a := &applier{
+		ctx:         ctx,
+		transaction: transaction,
+		accountID:   accountID,
+		userID:      userID,
+		groupIDs:    state.GroupIDs,
+		checkIDs:    state.PostureCheckIDs,
+		networkIDs:  state.NetworkIDs,
+	}
+	a.storeEvent = func(targetID string, activityID activity.Activity, meta map[string]any) {
+		meta["declarative"] = true
+		a.events = append(a.events, func() {
+			m.accountManager.StoreEvent(ctx, userID, targetID, accountID, activityID, meta)
+		})
+	}
+	return a
+}
+
+func (a *applier) apply(change *declarative.Change) error {
+	switch spec := change.Item.Spec.(type) {
+	case declarative.GroupSpec:
+		return a.applyGroup(change, spec)
+	case declarative.PostureChecksSpec:
+		return a.applyPostureChecks(change, spec)
+	case declarative.PolicySpec:
+		return a.applyPolicy(change, spec)
+	case declarative.NetworkSpec:
+		return a.applyNetwork(change, spec)
+	case declarative.NetworkResourceItem:
+		return a.applyNetworkResource(change, spec)
+	case declarative.NetworkRouterItem:
+		return a.applyNetworkRouter(change, spec)
+	case declarative.NameserverGroupSpec:
+		return a.applyNameserverGroup(change, spec)
+	case declarative.ZoneSpec:
+		return a.applyZone(change, spec)
+	default:
+		return fmt.Errorf("unsupported object %T", spec)
+	}
+}
+
+func (a *applier) applyGroup(change *declarative.Change, spec declarative.GroupSpec) error {
+	switch change.Action {
+	case declarative.ActionCreate:
+		group := &types.Group{
+			ID:        xid.New().String(),
+			AccountID: a.accountID,
+			Name:      spec.Name,
+			Issued:    types.GroupIssuedAPI,
+		}
+		if err := a.transaction.CreateGroup(a.ctx, group); err != nil {
+			return err
+		}
+		change.Item.ID = group.ID
+		a.groupIDs[group.Name] = group.ID
+		a.storeEvent(group.ID, activity.GroupCreated, group.EventMeta())
+	case declarative.ActionDelete:
+		group, err := a.transaction.GetGroupByID(a.ctx, store.LockingStrengthUpdate, a.accountID, change.ID)
+		if err != nil {
+			return err
+		}
+		// the objects of the plan that used the group have been updated or deleted already
+		if err = server.ValidateDeleteGroup(a.ctx, a.transaction, group, a.userID); err != nil {
+			return err
+		}
+		if err = a.transaction.DeleteGroup(a.ctx, a.accountID, change.ID); err != nil {
+			return err
+		}
+		a.storeEvent(change.ID, activity.GroupDeleted, map[string]any{"name": spec.Name})
+	}
+	// groups have no attributes besides their name, so they are never updated
+	return nil
+}
+
+func (a *applier) applyPostureChecks(change *declarative.Change, spec declarative.PostureChecksSpec) error {
+	if change.Action == declarative.ActionDelete {
+		if err := server.ValidateDeletePostureChecks(a.ctx, a.transaction, a.accountID, change.ID); err != nil {
+			return err
+		}
+		if err := a.transaction.DeletePostureChecks(a.ctx, a.accountID, change.ID); err != nil {
+			return err
+		}
+		a.storeEvent(change.ID, activity.PostureCheckDeleted, map[string]any{"name": spec.Name})
+		return nil
+	}
+
+	checks, err := posture.NewChecksFromAPIPostureCheck(api.PostureCheck{
+		Id:          objectID(change),
+		Name:        spec.Name,
+		Description: &spec.Description,
+		Checks:      spec.Checks,
+	})
+	if err != nil {
+		return err
+	}
+	checks.AccountID = a.accountID
+
+	if err = a.transaction.SavePostureChecks(a.ctx, checks); err != nil {
+		return err
+	}
+	change.Item.ID = checks.ID
+	a.checkIDs[checks.Name] = checks.ID
+	a.storeEvent(checks.ID, eventFor(change, activity.PostureCheckCreated, activity.PostureCheckUpdated), checks.EventMeta())
+	return nil
+}
+
+func (a *applier) applyPolicy(change *declarative.Change, spec declarative.PolicySpec) error {
+	if change.ID != "" {
+		// the policy is recreated with the same ID to replace all of its rules
+		if err := a.transaction.DeletePolicy(a.ctx, a.accountID, change.ID); err != nil {
+			return err
+		}
+	}
+	if change.Action == declarative.ActionDelete {
+		a.storeEvent(change.ID, activity.PolicyRemoved, map[string]any{"name": spec.Name})
+		return nil
+	}
+
+	policy := &types.Policy{
+		ID:                  objectID(change),
+		AccountID:           a.accountID,
+		Name:                spec.Name,
+		Description:         spec.Description,
+		Enabled:             *spec.Enabled,
+		SourcePostureChecks: a.resolve(a.checkIDs, spec.SourcePostureChecks),
+	}
+	for _, ruleSpec := range spec.Rules {
+		rule := &types.PolicyRule{
+			ID:            xid.New().String(),
+			PolicyID:      policy.ID,
+			Name:          ruleSpec.Name,
+			Description:   ruleSpec.Description,
+			Enabled:       *ruleSpec.Enabled,
+			Action:        types.PolicyTrafficActionType(ruleSpec.Action),
+			Bidirectional: ruleSpec.Bidirectional,
+			Protocol:      types.PolicyRuleProtocolType(ruleSpec.Protocol),
+			Ports:         ruleSpec.Ports,
+			Sources:       a.resolve(a.groupIDs, ruleSpec.Sources),
+			Destinations:  a.resolve(a.groupIDs, ruleSpec.Destinations),
+		}
+		for _, portRange := range ruleSpec.PortRanges {
+			rule.PortRanges = append(rule.PortRanges, types.RulePortRange{Start: portRange.Start, End: portRange.End})
+		}
+		policy.Rules = append(policy.Rules, rule)
+	}
+
+	if err := a.transaction.CreatePolicy(a.ctx, policy); err != nil {
+		return err
+	}
+	change.Item.ID = policy.ID
+	a.storeEvent(policy.ID, eventFor(change, activity.PolicyAdded, activity.PolicyUpdated), policy.EventMeta())
+	return nil
+}
+
+func (a *applier) applyNetwork(change *declarative.Change, spec declarative.NetworkSpec) error {
+	var before *networkTypes.Network
+	if change.ID != "" {
+		var err error
+		if before, err = a.transaction.GetNetworkByID(a.ctx, store.LockingStrengthUpdate, a.accountID, change.ID); err != nil {
+			return err
+		}
+	}
+
+	if change.Action == declarative.ActionDelete {
+		if err := a.transaction.DeleteNetwork(a.ctx, a.accountID, change.ID); err != nil {
+			return err
+		}
+		if err := a.recordVersion(change, history.ObjectNetwork, change.ID, before, nil); err != nil {
+			return err
+		}
+		a.storeEvent(change.ID, activity.NetworkDeleted, map[string]any{"name": spec.Name})
+		return nil
+	}
+
+	network := &networkTypes.Network{
+		ID:          objectID(change),
+		AccountID:   a.accountID,
+		Name:        spec.Name,
+		Description: spec.Description,
+	}
+	if err := a.transaction.SaveNetwork(a.ctx, network); err != nil {
+		return err
+	}
+	if err := a.recordVersion(change, history.ObjectNetwork, network.ID, before, network); err != nil {
+		return err
+	}
+	change.Item.ID = network.ID
+	a.networkIDs[network.Name] = network.ID
+	a.storeEvent(network.ID, eventFor(change, activity.NetworkCreated, activity.NetworkUpdated), network.EventMeta())
+	return nil
+}
+
+func (a *applier) applyNetworkResource(change *declarative.Change, spec declarative.NetworkResourceItem) error {
+	meta := map[string]any{"name": spec.Name, "network_name": spec.Network}
+
+	var before *resourceTypes.NetworkResource
+	if change.ID != "" {
+		var err error
+		if before, err = a.transaction.GetNetworkResourceByID(a.ctx, store.LockingStrengthUpdate, a.accountID, change.ID); err != nil {
+			return err
+		}
+		groups, err := a.transaction.GetResourceGroups(a.ctx, store.LockingStrengthUpdate, a.accountID, change.ID)
+		if err != nil {
+			return err
+		}
+		for _, group := range groups {
+			before.GroupIDs = append(before.GroupIDs, group.ID)
+			if err = a.transaction.RemoveResourceFromGroup(a.ctx, a.accountID, group.ID, change.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if change.Action == declarative.ActionDelete {
+		if err := a.transaction.DeleteNetworkResource(a.ctx, a.accountID, change.ID); err != nil {
+			return err
+		}
+		if err := a.recordVersion(change, history.ObjectNetworkResource, change.ID, before, nil); err != nil {
+			return err
+		}
+		a.storeEvent(change.ID, activity.NetworkResourceDeleted, meta)
+		return nil
+	}
+
+	networkID := a.networkIDs[spec.Network]
+	resource, err := spec.Resource(a.accountID, networkID, a.resolve(a.groupIDs, spec.Groups))
+	if err != nil {
+		return err
+	}
+	if change.ID != "" {
+		resource.ID = change.ID
+	}
+
+	if err = a.transaction.SaveNetworkResource(a.ctx, resource); err != nil {
+		return err
+	}
+	for _, groupID := range resource.GroupIDs {
+		groupResource := &types.Resource{ID: resource.ID, Type: types.ResourceType(resource.Type.String())}
+		if err = a.transaction.AddResourceToGroup(a.ctx, a.accountID, groupID, groupResource); err != nil {
+			return err
+		}
+	}
+	if err = a.recordVersion(change, history.ObjectNetworkResource, resource.ID, before, resource); err != nil {
+		return err
+	}
+
+	change.Item.ID = resource.ID
+	meta["type"] = resource.Type
+	meta["network_id"] = networkID
+	a.storeEvent(resource.ID, eventFor(change, activity.NetworkResourceCreated, activity.NetworkResourceUpdated), meta)
+	return nil
+}
+
+func (a *applier) applyNetworkRouter(change *declarative.Change, spec declarative.NetworkRouterItem) error {
+	networkID := a.networkIDs[spec.Network]
+	network := &networkTypes.Network{ID: networkID, Name: spec.Network}
+
+	var before *routerTypes.NetworkRouter
+	if change.ID != "" {
+		var err error
+		if before, err = a.transaction.GetNetworkRouterByID(a.ctx, store.LockingStrengthUpdate, a.accountID, change.ID); err != nil {
+			return err
+		}
+	}
+
+	if change.Action == declarative.ActionDelete {
+		if err := a.transaction.DeleteNetworkRouter(a.ctx, a.accountID, change.ID); err != nil {
+			return err
+		}
+		if err := a.recordVersion(change, history.ObjectNetworkRouter, change.ID, before, nil); err != nil {
+			return err
+		}
+		a.storeEvent(change.ID, activity.NetworkRouterDeleted, map[string]any{"network_name": spec.Network, "network_id": networkID, "peer": spec.Peer})
+		return nil
+	}
+
+	router := &routerTypes.NetworkRouter{ID: objectID(change), AccountID: a.accountID, NetworkID: networkID}
+	router.FromAPIRequest(spec.Request(a.resolve(a.groupIDs, spec.PeerGroups)))
+
+	if err := a.transaction.SaveNetworkRouter(a.ctx, router); err != nil {
+		return err
+	}
+	if err := a.recordVersion(change, history.ObjectNetworkRouter, router.ID, before, router); err != nil {
+		return err
+	}
+	change.Item.ID = router.ID
+	a.storeEvent(router.ID, eventFor(change, activity.NetworkRouterCreated, activity.NetworkRouterUpdated), router.EventMeta(network))
+	return nil
+}
+
+func (a *applier) applyNameserverGroup(change *declarative.Change, spec declarative.NameserverGroupSpec) error {
+	if change.Action == declarative.ActionDelete {
+		if err := a.transaction.DeleteNameServerGroup(a.ctx, a.accountID, change.ID); err != nil {
+			return err
+		}
+		a.storeEvent(change.ID, activity.NameserverGroupDeleted, map[string]any{"name": spec.Name})
+		return nil
+	}
+
+	nsGroup := &nbdns.NameServerGroup{
+		ID:                   objectID(change),
+		AccountID:            a.accountID,
+		Name:                 spec.Name,
+		Description:          spec.Description,
+		Groups:               a.resolve(a.groupIDs, spec.Groups),
+		Primary:              spec.Primary,
+		Domains:              spec.Domains,
+		Enabled:              *spec.Enabled,
+		SearchDomainsEnabled: spec.SearchDomainsEnabled,
+	}
+	for _, nsURL := range spec.Nameservers {
+		nameserver, err := nbdns.ParseNameServerURL(nsURL)
+		if err != nil {
+			return err
+		}
+		nsGroup.NameServers = append(nsGroup.NameServers, nameserver)
+	}
+
+	if err := a.transaction.SaveNameServerGroup(a.ctx, nsGroup); err != nil {
+		return err
+	}
+	change.Item.ID = nsGroup.ID
+	a.storeEvent(nsGroup.ID, eventFor(change, activity.NameserverGroupCreated, activity.NameserverGroupUpdated), nsGroup.EventMeta())
+	return nil
+}
+
+func (a *applier) applyZone(change *declarative.Change, spec declarative.ZoneSpec) error {
+	if change.ID != "" {
+		if err := a.transaction.DeleteZoneDNSRecords(a.ctx, a.accountID, change.ID); err != nil {
+			return err
+		}
+	}
+
+	if change.Action == declarative.ActionDelete {
+		if err := a.transaction.DeleteZone(a.ctx, a.accountID, change.ID); err != nil {
+			return err
+		}
+		a.storeEvent(change.ID, activity.DNSZoneDeleted, map[string]any{"name": spec.Name, "domain": spec.Domain})
+		return nil
+	}
+
+	zone := zones.NewZone(a.accountID, spec.Name, spec.Domain, *spec.Enabled, spec.EnableSearchDomain, a.resolve(a.groupIDs, spec.DistributionGroups))
+	if change.ID != "" {
+		zone.ID = change.ID
+		if err := a.transaction.UpdateZone(a.ctx, zone); err != nil {
+			return err
+		}
+	} else if err := a.transaction.CreateZone(a.ctx, zone); err != nil {
+		return err
+	}
+
+	for _, recordSpec := range spec.Records {
+		record := records.NewRecord(a.accountID, zone.ID, recordSpec.Name, records.RecordType(recordSpec.Type), recordSpec.Content, recordSpec.TTL)
+		if err := a.transaction.CreateDNSRecord(a.ctx, record); err != nil {
+			return err
+		}
+	}
+
+	change.Item.ID = zone.ID
+	a.storeEvent(zone.ID, eventFor(change, activity.DNSZoneCreated, activity.DNSZoneUpdated), zone.EventMeta())
+	return nil
+}
+
+// recordVersion stores a version of a versioned object, before is nil for created objects and after for deleted ones
+func (a *applier) recordVersion(change *declarative.Change, objectType history.ObjectType, objectID string, before, after any) error {
+	operation := history.OperationUpdated
+	switch change.Action {
+	case declarative.ActionCreate:
+		operation = history.OperationCreated
+	case declarative.ActionDelete:
+		operation = history.OperationDeleted
+	}
+	return history.Record(a.ctx, a.transaction, a.accountID, a.userID, objectType, objectID, operation, before, after)
+}
+
+// resolve translates names to the IDs of the objects, the plan has checked that all names exist
+func (a *applier) resolve(ids map[string]string, names []string) []string {
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		result = append(result, ids[name])
+	}
+	return result
+}
+
+// objectID returns the ID of an updated object or a new ID for a created one
+func objectID(change *declarative.Change) string {
+	if change.ID != "" {
+		return change.ID
+	}
+	return xid.New().String()
+}
+
+func eventFor(change *declarative.Change, created, updated activity.Activity) activity.Activity {
+	if change.Action == declarative.ActionCreate {
+		return created
+	}
+	return updated
+}
@@ -0,0 +1,242 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/netbirdio/netbird/management/internals/modules/declarative"
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// kindModules are the permission modules of the objects a spec manages
+var kindModules = map[declarative.Kind]modules.Module{
+	declarative.KindGroup:           modules.Groups,
+	declarative.KindPostureChecks:   modules.Policies,
+	declarative.KindPolicy:          modules.Policies,
+	declarative.KindNetwork:         modules.Networks,
+	declarative.KindNetworkResource: modules.Networks,
+	declarative.KindNetworkRouter:   modules.Networks,
+	declarative.KindNameserverGroup: modules.Nameservers,
+	declarative.KindZone:            modules.Dns,
+}
+
+var actionOperations = map[declarative.Action]operations.Operation{
+	declarative.ActionCreate: operations.Create,
+	declarative.ActionUpdate: operations.Update,
+	declarative.ActionDelete: operations.Delete,
+}
+
+type managerImpl struct {
+	store              store.Store
+	accountManager     account.Manager
+	permissionsManager permissions.Manager
+}
+
+func NewManager(store store.Store, accountManager account.Manager, permissionsManager permissions.Manager) declarative.Manager {
+	return &managerImpl{
+		store:              store,
+		accountManager:     accountManager,
+		permissionsManager: permissionsManager,
+	}
+}
+
+func (m *managerImpl) Plan(ctx context.Context, accountID, userID string, spec *declarative.Spec) (*declarative.Plan, error) {
+	if err := m.validateReadPermissions(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+
+	state, managed, err := observe(ctx, m.store, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return computePlan(spec, state, managed)
+}
+
+func (m *managerImpl) Apply(ctx context.Context, accountID, userID string, spec *declarative.Spec) (*declarative.Plan, error) {
+	plan, err := m.Plan(ctx, accountID, userID, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// permissions are validated outside of the transaction, the plan computed within it may only need the same ones
+	allowed := make(map[modules.Module]map[operations.Operation]struct{})
+	for _, change := range plan.Changes {
+		module, operation := kindModules[change.Kind], actionOperations[change.Action]
+		if _, ok := allowed[module][operation]; ok {
+			continue
+		}
+		if err = m.validatePermissions(ctx, accountID, userID, module, operation); err != nil {
+			return nil, err
+		}
+		if allowed[module] == nil {
+			allowed[module] = make(map[operations.Operation]struct{})
+		}
+		allowed[module][operation] = struct{}{}
+	}
+
+	var events []func()
+	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		state, managed, err := observe(ctx, transaction, store.LockingStrengthUpdate, accountID)
+		if err != nil {
+			return err
+		}
+
+		plan, err = computePlan(spec, state, managed)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range plan.Changes {
+			if _, ok := allowed[kindModules[change.Kind]][actionOperations[change.Action]]; !ok {
+				return status.Errorf(status.PreconditionFailed, "the account has been changed while applying the configuration, please retry")
+			}
+		}
+
+		a := m.newApplier(ctx, transaction, accountID, userID, state)
+		for _, change := range plan.Changes {
+			if err = a.apply(change); err != nil {
+				return fmt.Errorf("%s %s %s: %w", change.Action, change.Kind, change.Name, err)
+			}
+		}
+		events = a.events
+
+		if err = saveManagedObjects(ctx, transaction, accountID, plan, managed); err != nil {
+			return err
+		}
+
+		if len(plan.Changes) == 0 {
+			return nil
+		}
+		return transaction.IncrementNetworkSerial(ctx, accountID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, storeEvent := range events {
+		storeEvent()
+	}
+
+	if len(plan.Changes) > 0 {
+		m.accountManager.StoreEvent(ctx, userID, accountID, accountID, activity.DeclarativeConfigApplied, planEventMeta(plan))
+		m.accountManager.UpdateAccountPeers(ctx, accountID)
+	}
+
+	return plan, nil
+}
+
+func (m *managerImpl) GetDrift(ctx context.Context, accountID, userID string) ([]*declarative.Drift, error) {
+	if err := m.validateReadPermissions(ctx, accountID, userID); err != nil {
+		return nil, err
+	}
+
+	state, managed, err := observe(ctx, m.store, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return declarative.FindDrift(state, managed), nil
+}
+
+// validateReadPermissions checks that the user can read all objects a spec manages
+func (m *managerImpl) validateReadPermissions(ctx context.Context, accountID, userID string) error {
+	checked := make(map[modules.Module]struct{}, len(kindModules))
+	for _, module := range kindModules {
+		if _, ok := checked[module]; ok {
+			continue
+		}
+		checked[module] = struct{}{}
+		if err := m.validatePermissions(ctx, accountID, userID, module, operations.Read); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *managerImpl) validatePermissions(ctx context.Context, accountID, userID string, module modules.Module, operation operations.Operation) error {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, module, operation)
+	if err != nil {
+		return status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return status.NewPermissionDeniedError()
+	}
+	return nil
+}
+
+// observe reads the account and the markers of its managed objects
+func observe(ctx context.Context, s store.Store, lockStrength store.LockingStrength, accountID string) (*declarative.State, []*declarative.ManagedObject, error) {
+	managed, err := s.GetManagedObjects(ctx, lockStrength, accountID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	account, err := s.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accountZones, err := s.GetAccountZones(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return declarative.Observe(account, accountZones), managed, nil
+}
+
+func computePlan(spec *declarative.Spec, state *declarative.State, managed []*declarative.ManagedObject) (*declarative.Plan, error) {
+	plan, err := declarative.ComputePlan(spec, state, managed)
+	if err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "%s", err.Error())
+	}
+	return plan, nil
+}
+
+// saveManagedObjects marks all objects of the spec as managed with their applied hash and removes the markers
+// of objects that are not part of the spec anymore
+func saveManagedObjects(ctx context.Context, transaction store.Store, accountID string, plan *declarative.Plan, previous []*declarative.ManagedObject) error {
+	now := time.Now().UTC()
+
+	objects := make([]*declarative.ManagedObject, 0, len(plan.Managed))
+	current := make(map[string]struct{}, len(plan.Managed))
+	for _, item := range plan.Managed {
+		object := declarative.NewManagedObject(accountID, item)
+		object.AppliedAt = now
+		objects = append(objects, object)
+		current[string(object.Kind)+"/"+object.ObjectID] = struct{}{}
+	}
+
+	for _, object := range previous {
+		if _, ok := current[string(object.Kind)+"/"+object.ObjectID]; ok {
+			continue
+		}
+		if err := transaction.DeleteManagedObject(ctx, accountID, object.Kind, object.ObjectID); err != nil {
+			return err
+		}
+	}
+
+	return transaction.SaveManagedObjects(ctx, objects)
+}
+
+func planEventMeta(plan *declarative.Plan) map[string]any {
+	var created, updated, deleted int
+	for _, change := range plan.Changes {
+		switch change.Action {
+		case declarative.ActionCreate:
+			created++
+		case declarative.ActionUpdate:
+			updated++
+		case declarative.ActionDelete:
+			deleted++
+		}
+	}
+	return map[string]any{"created": created, "updated": updated, "deleted": deleted}
+}
@@ -0,0 +1,422 @@
+package manager
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/declarative"
+	"github.com/netbirdio/netbird/management/internals/modules/history"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/route"
+)
+
+const (
+	testAccountID = "test-account-id"
+	testUserID    = "test-user-id"
+	testPeerID    = "test-peer-id"
+)
+
+const testSpec = `
+groups:
+  - name: engineering
+  - name: servers
+policies:
+  - name: engineering to servers
+    rules:
+      - name: ssh
+        protocol: tcp
+        ports: ["22"]
+        sources: [engineering]
+        destinations: [servers]
+networks:
+  - name: office
+    resources:
+      - name: lan
+        address: 10.10.0.0/16
+        groups: [servers]
+    routers:
+      - peer: test-peer-id
+nameserver_groups:
+  - name: office dns
+    nameservers: [udp://10.10.0.53:53]
+    groups: [engineering]
+    domains: [office.example.com]
+zones:
+  - name: internal
+    domain: internal.example.com
+    distribution_groups: [engineering]
+    records:
+      - name: git.internal.example.com
+        type: A
+        content: 10.10.0.20
+`
+
+func setupTest(t *testing.T) (*managerImpl, store.Store, *mock_server.MockAccountManager, *permissions.MockManager, *gomock.Controller, func()) {
+	t.Helper()
+
+	ctx := context.Background()
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+
+	err = testStore.SaveAccount(ctx, &types.Account{
+		Id: testAccountID,
+		Users: map[string]*types.User{
+			testUserID: {Id: testUserID, AccountID: testAccountID, Role: types.UserRoleAdmin},
+		},
+		Groups: map[string]*types.Group{
+			"all-group-id": {ID: "all-group-id", AccountID: testAccountID, Name: "All", Issued: types.GroupIssuedAPI},
+		},
+		Peers: map[string]*nbpeer.Peer{
+			testPeerID: {
+				ID:        testPeerID,
+				AccountID: testAccountID,
+				Key:       "peer-key",
+				Name:      "router",
+				DNSLabel:  "router",
+				IP:        net.IP{100, 64, 0, 1},
+				Status:    &nbpeer.PeerStatus{},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	mockAccountManager := &mock_server.MockAccountManager{}
+	mockPermissionsManager := permissions.NewMockManager(ctrl)
+
+	manager := &managerImpl{
+		store:              testStore,
+		accountManager:     mockAccountManager,
+		permissionsManager: mockPermissionsManager,
+	}
+
+	return manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup
+}
+
+func parseSpec(t *testing.T, data string) *declarative.Spec {
+	t.Helper()
+	spec, err := declarative.ParseSpec([]byte(data))
+	require.NoError(t, err)
+	return spec
+}
+
+func TestManagerImpl_Apply(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, gomock.Any(), gomock.Any()).
+		Return(true, nil).
+		AnyTimes()
+
+	var events []activity.ActivityDescriber
+	mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+		events = append(events, activityID)
+	}
+
+	plan, err := manager.Apply(ctx, testAccountID, testUserID, parseSpec(t, testSpec))
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 8)
+	for _, change := range plan.Changes {
+		assert.Equal(t, declarative.ActionCreate, change.Action, "%s %s", change.Kind, change.Name)
+	}
+	assert.Contains(t, events, activity.DeclarativeConfigApplied)
+
+	account, err := testStore.GetAccount(ctx, testAccountID)
+	require.NoError(t, err)
+	assert.Len(t, account.Groups, 3)
+	assert.Len(t, account.Policies, 1)
+	assert.Len(t, account.Networks, 1)
+	assert.Len(t, account.NetworkResources, 1)
+	assert.Len(t, account.NetworkRouters, 1)
+	assert.Len(t, account.NameServerGroups, 1)
+
+	accountZones, err := testStore.GetAccountZones(ctx, store.LockingStrengthNone, testAccountID)
+	require.NoError(t, err)
+	require.Len(t, accountZones, 1)
+	assert.Len(t, accountZones[0].Records, 1)
+
+	plan, err = manager.Plan(ctx, testAccountID, testUserID, parseSpec(t, testSpec))
+	require.NoError(t, err)
+	assert.Empty(t, plan.Changes, "applying the same spec twice shouldn't change anything")
+	assert.Empty(t, plan.Drift)
+}
+
+func TestManagerImpl_Drift(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, gomock.Any(), gomock.Any()).
+		Return(true, nil).
+		AnyTimes()
+
+	_, err := manager.Apply(ctx, testAccountID, testUserID, parseSpec(t, testSpec))
+	require.NoError(t, err)
+
+	policies, err := testStore.GetAccountPolicies(ctx, store.LockingStrengthNone, testAccountID)
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	policies[0].Rules[0].Ports = []string{"2222"}
+	require.NoError(t, testStore.SavePolicy(ctx, policies[0]))
+
+	drift, err := manager.GetDrift(ctx, testAccountID, testUserID)
+	require.NoError(t, err)
+	require.Len(t, drift, 1)
+	assert.Equal(t, declarative.KindPolicy, drift[0].Kind)
+	assert.Equal(t, declarative.DriftModified, drift[0].Reason)
+
+	plan, err := manager.Plan(ctx, testAccountID, testUserID, parseSpec(t, testSpec))
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	assert.Equal(t, declarative.ActionUpdate, plan.Changes[0].Action)
+	assert.Equal(t, []string{"rules"}, plan.Changes[0].Fields)
+	assert.True(t, plan.Changes[0].Drifted)
+
+	_, err = manager.Apply(ctx, testAccountID, testUserID, parseSpec(t, testSpec))
+	require.NoError(t, err)
+
+	drift, err = manager.GetDrift(ctx, testAccountID, testUserID)
+	require.NoError(t, err)
+	assert.Empty(t, drift, "applying the spec should revert the drift")
+}
+
+func TestManagerImpl_ApplyNetworkResourceNetMap(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, gomock.Any(), gomock.Any()).
+		Return(true, nil).
+		AnyTimes()
+
+	spec := `
+networks:
+  - name: office
+    resources:
+      - name: lan
+        address: 10.10.0.0/24
+        netmap:
+          virtual: 172.16.10.0/24
+`
+	_, err := manager.Apply(ctx, testAccountID, testUserID, parseSpec(t, spec))
+	require.NoError(t, err)
+
+	resources, err := testStore.GetNetworkResourcesByAccountID(ctx, store.LockingStrengthNone, testAccountID)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	require.NotNil(t, resources[0].NetMap)
+	assert.Equal(t, netip.MustParsePrefix("10.10.0.0/24"), resources[0].NetMap.Real, "the real prefix should default to the resource prefix")
+	assert.Equal(t, netip.MustParsePrefix("172.16.10.0/24"), resources[0].NetMap.Virtual)
+
+	plan, err := manager.Plan(ctx, testAccountID, testUserID, parseSpec(t, spec))
+	require.NoError(t, err)
+	assert.Empty(t, plan.Changes, "the defaults of the netmap shouldn't be reported as changes")
+}
+
+func TestManagerImpl_ApplyNetworkRouterSettings(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, gomock.Any(), gomock.Any()).
+		Return(true, nil).
+		AnyTimes()
+
+	spec := `
+networks:
+  - name: office
+    routers:
+      - peer: test-peer-id
+        health_check:
+          type: tcp
+          target: 10.10.0.1:22
+        load_balance: true
+        bgp:
+          local_asn: 65001
+          neighbors:
+            - address: 10.10.0.254
+              asn: 65000
+        multicast:
+          mdns: true
+          groups: [239.1.1.1:5004]
+`
+	_, err := manager.Apply(ctx, testAccountID, testUserID, parseSpec(t, spec))
+	require.NoError(t, err)
+
+	routers, err := testStore.GetNetworkRoutersByAccountID(ctx, store.LockingStrengthNone, testAccountID)
+	require.NoError(t, err)
+	require.Len(t, routers, 1)
+	require.NotNil(t, routers[0].HealthCheck)
+	assert.Equal(t, route.HealthCheckTCP, routers[0].HealthCheck.Type)
+	assert.Equal(t, route.DefaultHealthCheckInterval, routers[0].HealthCheck.Interval)
+	assert.True(t, routers[0].LoadBalance)
+	require.NotNil(t, routers[0].BGP)
+	assert.Equal(t, uint32(65001), routers[0].BGP.LocalASN)
+	assert.Len(t, routers[0].BGP.Neighbors, 1)
+	require.NotNil(t, routers[0].Multicast)
+	assert.True(t, routers[0].Multicast.MDNS)
+	assert.Equal(t, []netip.AddrPort{netip.MustParseAddrPort("239.1.1.1:5004")}, routers[0].Multicast.Groups)
+
+	plan, err := manager.Plan(ctx, testAccountID, testUserID, parseSpec(t, spec))
+	require.NoError(t, err)
+	assert.Empty(t, plan.Changes, "the defaults of the settings shouldn't be reported as changes")
+}
+
+func TestManagerImpl_ApplyDeletesManagedObjects(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, gomock.Any(), gomock.Any()).
+		Return(true, nil).
+		AnyTimes()
+
+	_, err := manager.Apply(ctx, testAccountID, testUserID, parseSpec(t, testSpec))
+	require.NoError(t, err)
+
+	unmanaged := &types.Group{ID: "unmanaged-group-id", AccountID: testAccountID, Name: "unmanaged"}
+	require.NoError(t, testStore.CreateGroup(ctx, unmanaged))
+
+	plan, err := manager.Apply(ctx, testAccountID, testUserID, parseSpec(t, "groups:\n  - name: engineering\n"))
+	require.NoError(t, err)
+
+	deleted := make(map[declarative.Kind]int)
+	for _, change := range plan.Changes {
+		assert.Equal(t, declarative.ActionDelete, change.Action)
+		deleted[change.Kind]++
+	}
+	assert.Equal(t, 1, deleted[declarative.KindGroup], "only the managed servers group should be deleted")
+	assert.Equal(t, 1, deleted[declarative.KindNetwork])
+
+	account, err := testStore.GetAccount(ctx, testAccountID)
+	require.NoError(t, err)
+	assert.Empty(t, account.Policies)
+	assert.Empty(t, account.Networks)
+	assert.Empty(t, account.NetworkResources)
+	assert.Empty(t, account.NetworkRouters)
+	assert.Contains(t, account.Groups, unmanaged.ID)
+	assert.Len(t, account.Groups, 3)
+}
+
+func TestManagerImpl_ApplyRecordsVersions(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, gomock.Any(), gomock.Any()).
+		Return(true, nil).
+		AnyTimes()
+
+	updatedSpec := strings.NewReplacer(
+		"  - name: office\n", "  - name: office\n    description: branch office\n",
+		"address: 10.10.0.0/16", "address: 10.20.0.0/16",
+		"      - peer: test-peer-id\n", "      - peer: test-peer-id\n        metric: 100\n",
+	).Replace(testSpec)
+
+	for _, spec := range []string{testSpec, updatedSpec, "groups:\n  - name: engineering\n"} {
+		_, err := manager.Apply(ctx, testAccountID, testUserID, parseSpec(t, spec))
+		require.NoError(t, err)
+	}
+
+	expected := []history.Operation{history.OperationCreated, history.OperationUpdated, history.OperationDeleted}
+	for _, objectType := range []history.ObjectType{history.ObjectNetwork, history.ObjectNetworkResource, history.ObjectNetworkRouter} {
+		versions, err := testStore.GetObjectVersionsByType(ctx, store.LockingStrengthNone, testAccountID, objectType)
+		require.NoError(t, err)
+		require.Len(t, versions, len(expected), objectType)
+		for i, version := range versions {
+			assert.Equal(t, expected[i], version.Operation, objectType)
+			assert.Equal(t, i+1, version.Version, objectType)
+			assert.Equal(t, testUserID, version.UserID, objectType)
+			if i > 0 {
+				// the chain is complete when every version starts where the previous one ended
+				assert.JSONEq(t, string(versions[i-1].After), string(version.Before), objectType)
+			}
+		}
+		assert.NotEqual(t, string(versions[0].After), string(versions[1].After), objectType)
+	}
+}
+
+func TestManagerImpl_PlanRejectsDeletingUsedGroup(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, gomock.Any(), gomock.Any()).
+		Return(true, nil).
+		AnyTimes()
+
+	_, err := manager.Apply(ctx, testAccountID, testUserID, parseSpec(t, "groups:\n  - name: engineering\n"))
+	require.NoError(t, err)
+
+	groups, err := testStore.GetAccountGroups(ctx, store.LockingStrengthNone, testAccountID)
+	require.NoError(t, err)
+	var groupID string
+	for _, group := range groups {
+		if group.Name == "engineering" {
+			groupID = group.ID
+		}
+	}
+	setupKey := &types.SetupKey{Id: "setup-key-id", AccountID: testAccountID, Name: "office", Key: "hashed", AutoGroups: []string{groupID}}
+	require.NoError(t, testStore.SaveSetupKey(ctx, setupKey))
+
+	_, err = manager.Plan(ctx, testAccountID, testUserID, &declarative.Spec{})
+	assert.ErrorContains(t, err, "used by setup key office")
+}
+
+func TestManagerImpl_ApplyRejectsDeletingLinkedGroup(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, gomock.Any(), gomock.Any()).
+		Return(true, nil).
+		AnyTimes()
+
+	_, err := manager.Apply(ctx, testAccountID, testUserID, parseSpec(t, "groups:\n  - name: engineering\n"))
+	require.NoError(t, err)
+
+	group, err := testStore.GetGroupByName(ctx, store.LockingStrengthNone, testAccountID, "engineering")
+	require.NoError(t, err)
+
+	// the account settings aren't part of the plan, the link is only found when the group is deleted
+	settings, err := testStore.GetAccountSettings(ctx, store.LockingStrengthNone, testAccountID)
+	require.NoError(t, err)
+	settings.Extra = &types.ExtraSettings{IntegratedValidatorGroups: []string{group.ID}}
+	require.NoError(t, testStore.SaveAccountSettings(ctx, testAccountID, settings))
+
+	_, err = manager.Apply(ctx, testAccountID, testUserID, &declarative.Spec{})
+	assert.ErrorContains(t, err, "group has been linked to integrated validator: engineering")
+
+	groups, err := testStore.GetAccountGroups(ctx, store.LockingStrengthNone, testAccountID)
+	require.NoError(t, err)
+	assert.Len(t, groups, 2, "a failed apply shouldn't delete anything")
+}
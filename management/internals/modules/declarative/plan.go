@@ -0,0 +1,361 @@
+package declarative
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Action is the change of a single object
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// DriftReason tells how a managed object has been changed outside of the declarative configuration
+type DriftReason string
+
+const (
+	DriftModified DriftReason = "modified"
+	DriftDeleted  DriftReason = "deleted"
+)
+
+// Change is a single object that differs between the spec and the account
+type Change struct {
+	Kind   Kind   `json:"kind"`
+	Name   string `json:"name"`
+	Action Action `json:"action"`
+	// ID of the object in the store, empty for objects that are created
+	ID string `json:"id,omitempty"`
+	// Fields are the fields of an updated object that differ from the spec
+	Fields []string `json:"fields,omitempty"`
+	// Drifted is set if the object has been changed since it was applied
+	Drifted bool `json:"drifted,omitempty"`
+
+	// Item is the desired object for creates and updates and the current object for deletes
+	Item *Item `json:"-"`
+}
+
+// Drift is a managed object that has been changed since it was applied
+type Drift struct {
+	Kind   Kind        `json:"kind"`
+	Name   string      `json:"name"`
+	ID     string      `json:"id"`
+	Reason DriftReason `json:"reason"`
+}
+
+// Plan holds the changes needed to make the account match a spec
+type Plan struct {
+	Changes []*Change `json:"changes"`
+	Drift   []*Drift  `json:"drift"`
+
+	// Managed are the objects of the spec. They are marked as managed when the plan is applied.
+	Managed []*Item `json:"-"`
+}
+
+// ComputePlan compares the spec with the state of the account. Objects of the account that are not part of the spec
+// are deleted only if they are managed, i.e., have been created or updated by a previous apply.
+func ComputePlan(spec *Spec, state *State, managed []*ManagedObject) (*Plan, error) {
+	plan := &Plan{Changes: []*Change{}, Drift: FindDrift(state, managed)}
+
+	drifted := make(map[Kind]map[string]struct{})
+	for _, drift := range plan.Drift {
+		if drifted[drift.Kind] == nil {
+			drifted[drift.Kind] = make(map[string]struct{})
+		}
+		drifted[drift.Kind][drift.ID] = struct{}{}
+	}
+	isDrifted := func(kind Kind, id string) bool {
+		_, ok := drifted[kind][id]
+		return ok
+	}
+
+	desired := make(map[Kind]map[string]struct{})
+	for _, item := range spec.items() {
+		if desired[item.Kind] == nil {
+			desired[item.Kind] = make(map[string]struct{})
+		}
+		desired[item.Kind][item.Key] = struct{}{}
+		plan.Managed = append(plan.Managed, item)
+
+		current, ok := state.items[item.Kind][item.Key]
+		if !ok {
+			change := &Change{Kind: item.Kind, Name: item.Key, Action: ActionCreate, Item: item}
+			for _, object := range managed {
+				if object.Kind == item.Kind && object.Name == item.Key {
+					change.Drifted = isDrifted(object.Kind, object.ObjectID)
+				}
+			}
+			plan.Changes = append(plan.Changes, change)
+			continue
+		}
+
+		item.ID = current.ID
+		if fields := diffFields(current.Spec, item.Spec); len(fields) > 0 {
+			plan.Changes = append(plan.Changes, &Change{
+				Kind:    item.Kind,
+				Name:    item.Key,
+				Action:  ActionUpdate,
+				ID:      current.ID,
+				Fields:  fields,
+				Drifted: isDrifted(item.Kind, current.ID),
+				Item:    item,
+			})
+		}
+	}
+
+	deleted := make(map[Kind]map[string]struct{})
+	markDeleted := func(current *Item) {
+		if deleted[current.Kind] == nil {
+			deleted[current.Kind] = make(map[string]struct{})
+		}
+		if _, ok := deleted[current.Kind][current.Key]; ok {
+			return
+		}
+		deleted[current.Kind][current.Key] = struct{}{}
+		plan.Changes = append(plan.Changes, &Change{
+			Kind:    current.Kind,
+			Name:    current.Key,
+			Action:  ActionDelete,
+			ID:      current.ID,
+			Drifted: isDrifted(current.Kind, current.ID),
+			Item:    current,
+		})
+	}
+
+	for _, object := range managed {
+		current := state.itemByID(object.Kind, object.ObjectID)
+		if current == nil {
+			continue
+		}
+		if _, ok := desired[current.Kind][current.Key]; ok {
+			continue
+		}
+		markDeleted(current)
+	}
+
+	// deleting a network deletes its resources and routers
+	for key := range deleted[KindNetwork] {
+		for _, kind := range []Kind{KindNetworkResource, KindNetworkRouter} {
+			for childKey, child := range state.items[kind] {
+				if strings.HasPrefix(childKey, key+"/") {
+					markDeleted(child)
+				}
+			}
+		}
+	}
+
+	if err := checkReferences(spec, state, desired, deleted); err != nil {
+		return nil, err
+	}
+
+	sortChanges(plan.Changes)
+	return plan, nil
+}
+
+// FindDrift returns the managed objects that have been modified or deleted since they were applied
+func FindDrift(state *State, managed []*ManagedObject) []*Drift {
+	drift := []*Drift{}
+	for _, object := range managed {
+		current := state.itemByID(object.Kind, object.ObjectID)
+		switch {
+		case current == nil:
+			drift = append(drift, &Drift{Kind: object.Kind, Name: object.Name, ID: object.ObjectID, Reason: DriftDeleted})
+		case hashSpec(current.Spec) != object.Hash:
+			drift = append(drift, &Drift{Kind: object.Kind, Name: current.Key, ID: object.ObjectID, Reason: DriftModified})
+		}
+	}
+
+	sort.Slice(drift, func(i, j int) bool {
+		if drift[i].Kind != drift[j].Kind {
+			return kindIndex(drift[i].Kind) < kindIndex(drift[j].Kind)
+		}
+		return drift[i].Name < drift[j].Name
+	})
+	return drift
+}
+
+// NewManagedObject returns the marker of an applied object
+func NewManagedObject(accountID string, item *Item) *ManagedObject {
+	return &ManagedObject{
+		AccountID: accountID,
+		Kind:      item.Kind,
+		ObjectID:  item.ID,
+		Name:      item.Key,
+		Hash:      hashSpec(item.Spec),
+	}
+}
+
+// checkReferences makes sure that all groups, posture checks and peers referenced by the spec exist after the plan
+// is applied, and that deleted groups and posture checks are not used anymore
+func checkReferences(spec *Spec, state *State, desired, deleted map[Kind]map[string]struct{}) error {
+	var errs []error
+
+	exists := func(kind Kind, ids map[string]string, name string) bool {
+		if _, ok := desired[kind][name]; ok {
+			return true
+		}
+		if _, ok := deleted[kind][name]; ok {
+			return false
+		}
+		_, ok := ids[name]
+		return ok
+	}
+
+	for _, item := range spec.items() {
+		groups, checks := itemRefs(item)
+		for _, name := range groups {
+			if !exists(KindGroup, state.GroupIDs, name) {
+				errs = append(errs, fmt.Errorf("%s %s: group %s doesn't exist", item.Kind, item.Key, name))
+			}
+		}
+		for _, name := range checks {
+			if !exists(KindPostureChecks, state.PostureCheckIDs, name) {
+				errs = append(errs, fmt.Errorf("%s %s: posture checks %s don't exist", item.Kind, item.Key, name))
+			}
+		}
+		if router, ok := item.Spec.(NetworkRouterItem); ok && router.Peer != "" {
+			if _, ok := state.peers[router.Peer]; !ok {
+				errs = append(errs, fmt.Errorf("%s %s: peer %s doesn't exist", item.Kind, item.Key, router.Peer))
+			}
+		}
+	}
+
+	// the objects of the account after the plan is applied
+	remaining := spec.items()
+	for _, kind := range kindOrder {
+		for key, item := range state.items[kind] {
+			_, isDesired := desired[kind][key]
+			_, isDeleted := deleted[kind][key]
+			if !isDesired && !isDeleted {
+				remaining = append(remaining, item)
+			}
+		}
+	}
+
+	usedGroups := make(map[string]string)
+	usedChecks := make(map[string]string)
+	for _, item := range remaining {
+		groups, checks := itemRefs(item)
+		for _, name := range groups {
+			if _, ok := usedGroups[name]; !ok {
+				usedGroups[name] = fmt.Sprintf("%s %s", item.Kind, item.Key)
+			}
+		}
+		for _, name := range checks {
+			if _, ok := usedChecks[name]; !ok {
+				usedChecks[name] = fmt.Sprintf("%s %s", item.Kind, item.Key)
+			}
+		}
+	}
+
+	for name := range deleted[KindGroup] {
+		user, ok := usedGroups[name]
+		if !ok {
+			user, ok = state.externalRefs[state.GroupIDs[name]]
+		}
+		if ok {
+			errs = append(errs, fmt.Errorf("group %s can't be deleted, it is used by %s", name, user))
+		}
+	}
+	for name := range deleted[KindPostureChecks] {
+		user, ok := usedChecks[name]
+		if !ok {
+			user, ok = state.externalRefs[state.PostureCheckIDs[name]]
+		}
+		if ok {
+			errs = append(errs, fmt.Errorf("posture checks %s can't be deleted, they are used by %s", name, user))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// itemRefs returns the names of the groups and posture checks an item references
+func itemRefs(item *Item) (groups []string, checks []string) {
+	switch spec := item.Spec.(type) {
+	case PolicySpec:
+		for _, rule := range spec.Rules {
+			groups = append(groups, rule.Sources...)
+			groups = append(groups, rule.Destinations...)
+		}
+		checks = spec.SourcePostureChecks
+	case NetworkResourceItem:
+		groups = spec.Groups
+	case NetworkRouterItem:
+		groups = spec.PeerGroups
+	case NameserverGroupSpec:
+		groups = spec.Groups
+	case ZoneSpec:
+		groups = spec.DistributionGroups
+	}
+	return groups, checks
+}
+
+func (s *State) itemByID(kind Kind, id string) *Item {
+	for _, item := range s.items[kind] {
+		if item.ID == id {
+			return item
+		}
+	}
+	return nil
+}
+
+// diffFields returns the names of the top level JSON fields that differ between two spec objects
+func diffFields(current, desired any) []string {
+	currentFields, desiredFields := jsonFields(current), jsonFields(desired)
+
+	var fields []string
+	for name, value := range desiredFields {
+		if !reflect.DeepEqual(currentFields[name], value) {
+			fields = append(fields, name)
+		}
+	}
+	for name := range currentFields {
+		if _, ok := desiredFields[name]; !ok {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func jsonFields(spec any) map[string]any {
+	data, _ := json.Marshal(spec)
+	var fields map[string]any
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
+
+// sortChanges orders creates and updates by dependency followed by deletes in reverse order
+func sortChanges(changes []*Change) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		aDelete, bDelete := a.Action == ActionDelete, b.Action == ActionDelete
+		if aDelete != bDelete {
+			return !aDelete
+		}
+		if a.Kind != b.Kind {
+			if aDelete {
+				return kindIndex(a.Kind) > kindIndex(b.Kind)
+			}
+			return kindIndex(a.Kind) < kindIndex(b.Kind)
+		}
+		return a.Name < b.Name
+	})
+}
+
+func kindIndex(kind Kind) int {
+	for i, k := range kindOrder {
+		if k == kind {
+			return i
+		}
+	}
+	return len(kindOrder)
+}
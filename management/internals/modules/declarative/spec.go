@@ -0,0 +1,521 @@
+package declarative
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+	"github.com/netbirdio/netbird/management/internals/modules/zones"
+	"github.com/netbirdio/netbird/management/internals/modules/zones/records"
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
+	"github.com/netbirdio/netbird/management/server/posture"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+const (
+	defaultRouterMetric = 9999
+	defaultRecordTTL    = 300
+	allGroupName        = "All"
+)
+
+// Spec is the declarative description of the objects of an account. Objects reference each other by name.
+// Objects of the account that are not part of the spec are kept unless they have been created by a previous apply.
+type Spec struct {
+	Groups           []GroupSpec           `json:"groups,omitempty"`
+	PostureChecks    []PostureChecksSpec   `json:"posture_checks,omitempty"`
+	Policies         []PolicySpec          `json:"policies,omitempty"`
+	Networks         []NetworkSpec         `json:"networks,omitempty"`
+	NameserverGroups []NameserverGroupSpec `json:"nameserver_groups,omitempty"`
+	Zones            []ZoneSpec            `json:"zones,omitempty"`
+}
+
+type GroupSpec struct {
+	Name string `json:"name"`
+}
+
+type PostureChecksSpec struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Checks      api.Checks `json:"checks"`
+}
+
+type PolicySpec struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Enabled     *bool            `json:"enabled,omitempty"`
+	Rules       []PolicyRuleSpec `json:"rules"`
+	// SourcePostureChecks are names of posture checks
+	SourcePostureChecks []string `json:"source_posture_checks,omitempty"`
+}
+
+type PolicyRuleSpec struct {
+	Name          string          `json:"name"`
+	Description   string          `json:"description,omitempty"`
+	Enabled       *bool           `json:"enabled,omitempty"`
+	Action        string          `json:"action,omitempty"`
+	Bidirectional bool            `json:"bidirectional,omitempty"`
+	Protocol      string          `json:"protocol,omitempty"`
+	Ports         []string        `json:"ports,omitempty"`
+	PortRanges    []PortRangeSpec `json:"port_ranges,omitempty"`
+	// Sources and Destinations are names of groups
+	Sources      []string `json:"sources,omitempty"`
+	Destinations []string `json:"destinations,omitempty"`
+}
+
+type PortRangeSpec struct {
+	Start uint16 `json:"start"`
+	End   uint16 `json:"end"`
+}
+
+type NetworkSpec struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+	Resources   []NetworkResourceSpec `json:"resources,omitempty"`
+	Routers     []NetworkRouterSpec   `json:"routers,omitempty"`
+}
+
+type NetworkResourceSpec struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Address is a host IP, a prefix or a domain
+	Address string `json:"address"`
+	// Groups are names of the groups the resource belongs to
+	Groups  []string `json:"groups,omitempty"`
+	Enabled *bool    `json:"enabled,omitempty"`
+	// NetMap maps the resource onto a virtual prefix, the real prefix defaults to the resource prefix
+	NetMap *api.RouteNetMap `json:"netmap,omitempty"`
+}
+
+// NetworkRouterSpec routes a network through a single peer or through the peers of groups.
+// Routers don't have names, they are identified by their peer or peer groups.
+type NetworkRouterSpec struct {
+	// Peer is the ID of the routing peer
+	Peer string `json:"peer,omitempty"`
+	// PeerGroups are names of groups of routing peers
+	PeerGroups []string `json:"peer_groups,omitempty"`
+	Masquerade *bool    `json:"masquerade,omitempty"`
+	Metric     int      `json:"metric,omitempty"`
+	Enabled    *bool    `json:"enabled,omitempty"`
+	// HealthCheck is run by the routing peers against a target in the network
+	HealthCheck *api.RouteHealthCheck `json:"health_check,omitempty"`
+	// LoadBalance distributes the traffic to the network across all available routing peers
+	LoadBalance bool `json:"load_balance,omitempty"`
+	// BGP announces the NetBird network range to the LAN of the routing peers
+	BGP *api.RouteBGP `json:"bgp,omitempty"`
+	// Multicast relays multicast and broadcast traffic between the LAN of the routing peers and the peers
+	Multicast *api.RouteMulticast `json:"multicast,omitempty"`
+}
+
+type NameserverGroupSpec struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Nameservers are URLs of the nameservers, e.g., udp://1.1.1.1:53
+	Nameservers []string `json:"nameservers"`
+	// Groups are names of the groups the nameservers are distributed to
+	Groups               []string `json:"groups"`
+	Primary              bool     `json:"primary,omitempty"`
+	Domains              []string `json:"domains,omitempty"`
+	SearchDomainsEnabled bool     `json:"search_domains_enabled,omitempty"`
+	Enabled              *bool    `json:"enabled,omitempty"`
+}
+
+type ZoneSpec struct {
+	Name               string `json:"name"`
+	Domain             string `json:"domain"`
+	Enabled            *bool  `json:"enabled,omitempty"`
+	EnableSearchDomain bool   `json:"enable_search_domain,omitempty"`
+	// DistributionGroups are names of groups
+	DistributionGroups []string     `json:"distribution_groups"`
+	Records            []RecordSpec `json:"records,omitempty"`
+}
+
+type RecordSpec struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	TTL     int    `json:"ttl,omitempty"`
+}
+
+// ParseSpec reads a spec written as YAML or JSON. Unknown fields are rejected to catch typos.
+func ParseSpec(data []byte) (*Spec, error) {
+	var document any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+
+	// YAML is converted to JSON so that the spec shares the field names of the API
+	jsonData, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+
+	return DecodeSpec(jsonData)
+}
+
+// DecodeSpec reads a spec written as JSON and validates it
+func DecodeSpec(data []byte) (*Spec, error) {
+	var spec Spec
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+
+	spec.Normalize()
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// Normalize sets the defaults of omitted fields and sorts lists whose order has no meaning,
+// so that a spec and the spec observed from the store can be compared
+func (s *Spec) Normalize() {
+	for i := range s.PostureChecks {
+		checks := &s.PostureChecks[i]
+		// the checks are passed through the model to get the representation returned by the store
+		if model, err := posture.NewChecksFromAPIPostureCheck(api.PostureCheck{Name: checks.Name, Checks: checks.Checks}); err == nil {
+			checks.Checks = model.ToAPIResponse().Checks
+		}
+	}
+
+	for i := range s.Policies {
+		policy := &s.Policies[i]
+		policy.Enabled = defaultTrue(policy.Enabled)
+		policy.SourcePostureChecks = sortedSet(policy.SourcePostureChecks)
+		for j := range policy.Rules {
+			policy.Rules[j].normalize()
+		}
+	}
+
+	for i := range s.Networks {
+		network := &s.Networks[i]
+		for j := range network.Resources {
+			resource := &network.Resources[j]
+			resource.Enabled = defaultTrue(resource.Enabled)
+			resource.Groups = sortedSet(resource.Groups)
+			if resourceType, _, prefix, err := resourceTypes.GetResourceType(resource.Address); err == nil && resourceType != resourceTypes.Domain {
+				resource.Address = prefix.String()
+			}
+			if model, err := resource.Resource("", "", nil); err == nil {
+				resource.NetMap = model.ToAPIResponse(nil).Netmap
+			}
+		}
+		for j := range network.Routers {
+			router := &network.Routers[j]
+			router.Masquerade = defaultTrue(router.Masquerade)
+			router.Enabled = defaultTrue(router.Enabled)
+			router.PeerGroups = sortedSet(router.PeerGroups)
+			if router.Metric == 0 {
+				router.Metric = defaultRouterMetric
+			}
+			// the settings are passed through the model to get the representation returned by the store
+			observed := router.model().ToAPIResponse()
+			router.HealthCheck = observed.HealthCheck
+			router.BGP = observed.Bgp
+			router.Multicast = observed.Multicast
+		}
+	}
+
+	for i := range s.NameserverGroups {
+		ns := &s.NameserverGroups[i]
+		ns.Enabled = defaultTrue(ns.Enabled)
+		ns.Groups = sortedSet(ns.Groups)
+		for j, nsURL := range ns.Nameservers {
+			if nameserver, err := nbdns.ParseNameServerURL(nsURL); err == nil {
+				ns.Nameservers[j] = nameserverURL(nameserver)
+			}
+		}
+	}
+
+	for i := range s.Zones {
+		zone := &s.Zones[i]
+		zone.Enabled = defaultTrue(zone.Enabled)
+		zone.Domain = strings.ToLower(zone.Domain)
+		zone.DistributionGroups = sortedSet(zone.DistributionGroups)
+		for j := range zone.Records {
+			record := &zone.Records[j]
+			record.Type = strings.ToUpper(record.Type)
+			if record.TTL == 0 {
+				record.TTL = defaultRecordTTL
+			}
+		}
+		sort.Slice(zone.Records, func(a, b int) bool {
+			return zone.Records[a].key() < zone.Records[b].key()
+		})
+	}
+}
+
+func (r *PolicyRuleSpec) normalize() {
+	r.Enabled = defaultTrue(r.Enabled)
+	if r.Action == "" {
+		r.Action = string(types.PolicyTrafficActionAccept)
+	}
+	if r.Protocol == "" {
+		r.Protocol = string(types.PolicyRuleProtocolALL)
+	}
+	r.Sources = sortedSet(r.Sources)
+	r.Destinations = sortedSet(r.Destinations)
+}
+
+// Validate checks that names are set and unique. References are checked against the account when planning.
+func (s *Spec) Validate() error {
+	var errs []error
+
+	groups := make(map[string]struct{}, len(s.Groups))
+	for _, group := range s.Groups {
+		switch {
+		case group.Name == "":
+			errs = append(errs, errors.New("group name is required"))
+		case group.Name == allGroupName:
+			errs = append(errs, fmt.Errorf("group %s is managed by the account and can't be declared", allGroupName))
+		}
+		errs = append(errs, checkUnique(groups, KindGroup, group.Name))
+	}
+
+	postureChecks := make(map[string]struct{}, len(s.PostureChecks))
+	for _, checks := range s.PostureChecks {
+		errs = append(errs, checkName(KindPostureChecks, checks.Name), checkUnique(postureChecks, KindPostureChecks, checks.Name))
+		model, err := posture.NewChecksFromAPIPostureCheck(api.PostureCheck{Name: checks.Name, Checks: checks.Checks})
+		if err == nil {
+			err = model.Validate()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("posture checks %s: %w", checks.Name, err))
+		}
+	}
+
+	policies := make(map[string]struct{}, len(s.Policies))
+	for _, policy := range s.Policies {
+		errs = append(errs, checkName(KindPolicy, policy.Name), checkUnique(policies, KindPolicy, policy.Name))
+		if len(policy.Rules) == 0 {
+			errs = append(errs, fmt.Errorf("policy %s: at least one rule is required", policy.Name))
+		}
+		for _, rule := range policy.Rules {
+			if err := rule.validate(); err != nil {
+				errs = append(errs, fmt.Errorf("policy %s: %w", policy.Name, err))
+			}
+		}
+	}
+
+	networks := make(map[string]struct{}, len(s.Networks))
+	for _, network := range s.Networks {
+		errs = append(errs, checkName(KindNetwork, network.Name), checkUnique(networks, KindNetwork, network.Name))
+
+		resources := make(map[string]struct{}, len(network.Resources))
+		for _, resource := range network.Resources {
+			errs = append(errs, checkName(KindNetworkResource, resource.Name), checkUnique(resources, KindNetworkResource, resource.Name))
+			if _, err := resource.Resource("", "", nil); err != nil {
+				errs = append(errs, fmt.Errorf("network %s: resource %s: %w", network.Name, resource.Name, err))
+			}
+		}
+
+		routers := make(map[string]struct{}, len(network.Routers))
+		for _, router := range network.Routers {
+			if (router.Peer == "") == (len(router.PeerGroups) == 0) {
+				errs = append(errs, fmt.Errorf("network %s: a router requires either a peer or peer groups", network.Name))
+				continue
+			}
+			errs = append(errs, checkUnique(routers, KindNetworkRouter, router.key()))
+			if err := router.validate(); err != nil {
+				errs = append(errs, fmt.Errorf("network %s: router %s: %w", network.Name, router.key(), err))
+			}
+		}
+	}
+
+	nsGroups := make(map[string]struct{}, len(s.NameserverGroups))
+	for _, ns := range s.NameserverGroups {
+		errs = append(errs, checkName(KindNameserverGroup, ns.Name), checkUnique(nsGroups, KindNameserverGroup, ns.Name))
+		if err := ns.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("nameserver group %s: %w", ns.Name, err))
+		}
+	}
+
+	zones := make(map[string]struct{}, len(s.Zones))
+	for _, zone := range s.Zones {
+		errs = append(errs, checkUnique(zones, KindZone, zone.Domain))
+		if err := zone.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("zone %s: %w", zone.Domain, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *PolicyRuleSpec) validate() error {
+	switch types.PolicyTrafficActionType(r.Action) {
+	case types.PolicyTrafficActionAccept, types.PolicyTrafficActionDrop:
+	default:
+		return fmt.Errorf("rule %s: invalid action %q", r.Name, r.Action)
+	}
+	switch types.PolicyRuleProtocolType(r.Protocol) {
+	case types.PolicyRuleProtocolALL, types.PolicyRuleProtocolTCP, types.PolicyRuleProtocolUDP,
+		types.PolicyRuleProtocolICMP, types.PolicyRuleProtocolNetbirdSSH:
+	default:
+		return fmt.Errorf("rule %s: invalid protocol %q", r.Name, r.Protocol)
+	}
+	if len(r.Sources) == 0 || len(r.Destinations) == 0 {
+		return fmt.Errorf("rule %s: sources and destinations are required", r.Name)
+	}
+	return nil
+}
+
+func (ns *NameserverGroupSpec) validate() error {
+	if len(ns.Nameservers) == 0 {
+		return errors.New("at least one nameserver is required")
+	}
+	for _, nsURL := range ns.Nameservers {
+		if _, err := nbdns.ParseNameServerURL(nsURL); err != nil {
+			return err
+		}
+	}
+	if len(ns.Groups) == 0 {
+		return errors.New("at least one group is required")
+	}
+	if ns.Primary == (len(ns.Domains) > 0) {
+		return errors.New("either primary or match domains have to be set")
+	}
+	return nil
+}
+
+func (z *ZoneSpec) validate() error {
+	zone := zones.Zone{Name: z.Name, Domain: z.Domain, DistributionGroups: z.DistributionGroups}
+	if err := zone.Validate(); err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{}, len(z.Records))
+	for _, r := range z.Records {
+		record := records.Record{Name: r.Name, Type: records.RecordType(r.Type), Content: r.Content, TTL: r.TTL}
+		if err := record.Validate(); err != nil {
+			return fmt.Errorf("record %s: %w", r.Name, err)
+		}
+		if err := checkUnique(seen, "record", r.key()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Resource returns the validated network resource, with the groups resolved to IDs
+func (r *NetworkResourceSpec) Resource(accountID, networkID string, groupIDs []string) (*resourceTypes.NetworkResource, error) {
+	resource, err := resourceTypes.NewNetworkResource(accountID, networkID, r.Name, r.Description, r.Address, groupIDs, *defaultTrue(r.Enabled))
+	if err != nil {
+		return nil, err
+	}
+	resource.FromAPIRequest(&api.NetworkResourceRequest{
+		Name:        resource.Name,
+		Description: &resource.Description,
+		Address:     resource.Address,
+		Groups:      resource.GroupIDs,
+		Enabled:     resource.Enabled,
+		Netmap:      r.NetMap,
+	})
+	if err = resource.ValidateNetMap(); err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// Request returns the API request of the router, with the peer groups resolved to IDs
+func (r *NetworkRouterSpec) Request(peerGroups []string) *api.NetworkRouterRequest {
+	return &api.NetworkRouterRequest{
+		Peer:        &r.Peer,
+		PeerGroups:  &peerGroups,
+		Masquerade:  *defaultTrue(r.Masquerade),
+		Metric:      r.Metric,
+		Enabled:     *defaultTrue(r.Enabled),
+		HealthCheck: r.HealthCheck,
+		LoadBalance: &r.LoadBalance,
+		Bgp:         r.BGP,
+		Multicast:   r.Multicast,
+	}
+}
+
+func (r *NetworkRouterSpec) model() *routerTypes.NetworkRouter {
+	router := &routerTypes.NetworkRouter{}
+	router.FromAPIRequest(r.Request(nil))
+	return router
+}
+
+func (r *NetworkRouterSpec) validate() error {
+	router := r.model()
+	if router.HealthCheck != nil {
+		if err := router.HealthCheck.Validate(); err != nil {
+			return err
+		}
+	}
+	if router.BGP != nil {
+		if err := router.BGP.Validate(); err != nil {
+			return err
+		}
+	}
+	if router.Multicast != nil {
+		if err := router.Multicast.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// key identifies a router within its network
+func (r *NetworkRouterSpec) key() string {
+	if r.Peer != "" {
+		return "peer:" + r.Peer
+	}
+	return "groups:" + strings.Join(r.PeerGroups, ",")
+}
+
+// key identifies a record within its zone
+func (r *RecordSpec) key() string {
+	return r.Name + "/" + r.Type + "/" + r.Content
+}
+
+func checkName(kind Kind, name string) error {
+	if name == "" {
+		return fmt.Errorf("%s name is required", kind)
+	}
+	return nil
+}
+
+func checkUnique(seen map[string]struct{}, kind Kind, name string) error {
+	if _, ok := seen[name]; ok {
+		return fmt.Errorf("%s %s is declared more than once", kind, name)
+	}
+	seen[name] = struct{}{}
+	return nil
+}
+
+func defaultTrue(value *bool) *bool {
+	if value != nil {
+		return value
+	}
+	enabled := true
+	return &enabled
+}
+
+// sortedSet returns the sorted unique values of a list
+func sortedSet(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		result = append(result, value)
+	}
+	sort.Strings(result)
+	return result
+}
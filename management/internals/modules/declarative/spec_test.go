@@ -0,0 +1,98 @@
+package declarative
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpecYAML = `
+groups:
+  - name: engineering
+  - name: routers
+policies:
+  - name: engineering to office
+    rules:
+      - name: ssh
+        protocol: tcp
+        ports: ["22"]
+        sources: [engineering]
+        destinations: [routers, engineering]
+networks:
+  - name: office
+    resources:
+      - name: lan
+        address: 10.0.0.1
+        groups: [engineering]
+    routers:
+      - peer_groups: [routers]
+zones:
+  - name: Office
+    domain: Office.Example.com
+    distribution_groups: [engineering]
+    records:
+      - name: printer.office.example.com
+        type: a
+        content: 10.0.0.10
+`
+
+func TestParseSpec(t *testing.T) {
+	spec, err := ParseSpec([]byte(testSpecYAML))
+	require.NoError(t, err)
+
+	require.Len(t, spec.Policies, 1)
+	policy := spec.Policies[0]
+	require.NotNil(t, policy.Enabled)
+	assert.True(t, *policy.Enabled)
+	assert.Equal(t, "accept", policy.Rules[0].Action)
+	assert.Equal(t, []string{"engineering", "routers"}, policy.Rules[0].Destinations, "groups should be sorted")
+
+	require.Len(t, spec.Networks, 1)
+	assert.Equal(t, "10.0.0.1/32", spec.Networks[0].Resources[0].Address)
+	assert.Equal(t, defaultRouterMetric, spec.Networks[0].Routers[0].Metric)
+	assert.True(t, *spec.Networks[0].Routers[0].Masquerade)
+
+	require.Len(t, spec.Zones, 1)
+	assert.Equal(t, "office.example.com", spec.Zones[0].Domain)
+	assert.Equal(t, "A", spec.Zones[0].Records[0].Type)
+	assert.Equal(t, defaultRecordTTL, spec.Zones[0].Records[0].TTL)
+}
+
+func TestParseSpec_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{name: "unknown field", spec: "groups:\n  - name: a\n    peers: [x]\n"},
+		{name: "duplicate group", spec: "groups:\n  - name: a\n  - name: a\n"},
+		{name: "all group", spec: "groups:\n  - name: All\n"},
+		{name: "rule without sources", spec: "policies:\n  - name: p\n    rules:\n      - name: r\n        destinations: [a]\n"},
+		{name: "invalid resource address", spec: "networks:\n  - name: n\n    resources:\n      - name: r\n        address: \"-\"\n"},
+		{name: "router with peer and groups", spec: "networks:\n  - name: n\n    routers:\n      - peer: p\n        peer_groups: [a]\n"},
+		{name: "invalid resource netmap", spec: "networks:\n  - name: n\n    resources:\n      - name: r\n        address: 10.0.0.0/24\n        netmap:\n          virtual: 172.16.0.0/16\n"},
+		{name: "router bgp without neighbors", spec: "networks:\n  - name: n\n    routers:\n      - peer: p\n        bgp:\n          local_asn: 65001\n"},
+		{name: "invalid router multicast group", spec: "networks:\n  - name: n\n    routers:\n      - peer: p\n        multicast:\n          groups: [10.0.0.1:5004]\n"},
+		{name: "invalid router health check", spec: "networks:\n  - name: n\n    routers:\n      - peer: p\n        health_check:\n          type: icmp\n          target: host\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSpec([]byte(tt.spec))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestComputePlan_References(t *testing.T) {
+	spec, err := ParseSpec([]byte("policies:\n  - name: p\n    rules:\n      - name: r\n        sources: [missing]\n        destinations: [missing]\n"))
+	require.NoError(t, err)
+
+	state := &State{
+		items:        map[Kind]map[string]*Item{},
+		GroupIDs:     map[string]string{},
+		peers:        map[string]struct{}{},
+		externalRefs: map[string]string{},
+	}
+	_, err = ComputePlan(spec, state, nil)
+	assert.ErrorContains(t, err, "group missing doesn't exist")
+}
@@ -0,0 +1,308 @@
+package declarative
+
+import (
+	"fmt"
+	"sort"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+	"github.com/netbirdio/netbird/management/internals/modules/zones"
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+// NetworkResourceItem is a network resource together with the name of its network
+type NetworkResourceItem struct {
+	Network string `json:"network"`
+	NetworkResourceSpec
+}
+
+// NetworkRouterItem is a network router together with the name of its network
+type NetworkRouterItem struct {
+	Network string `json:"network"`
+	NetworkRouterSpec
+}
+
+// Item is a single object of a spec or of the account
+type Item struct {
+	Kind Kind
+	// Key identifies the object within its kind, e.g., the name of a group or the domain of a zone
+	Key string
+	// ID is the ID of the object in the store, empty for objects that don't exist yet
+	ID string
+	// Spec is one of GroupSpec, PostureChecksSpec, PolicySpec, NetworkSpec, NetworkResourceItem, NetworkRouterItem,
+	// NameserverGroupSpec or ZoneSpec. Resources and routers of a NetworkSpec are separate items.
+	Spec any
+}
+
+// items flattens the spec into one item per object
+func (s *Spec) items() []*Item {
+	var items []*Item
+	for _, group := range s.Groups {
+		items = append(items, &Item{Kind: KindGroup, Key: group.Name, Spec: group})
+	}
+	for _, checks := range s.PostureChecks {
+		items = append(items, &Item{Kind: KindPostureChecks, Key: checks.Name, Spec: checks})
+	}
+	for _, policy := range s.Policies {
+		items = append(items, &Item{Kind: KindPolicy, Key: policy.Name, Spec: policy})
+	}
+	for _, network := range s.Networks {
+		items = append(items, &Item{Kind: KindNetwork, Key: network.Name, Spec: NetworkSpec{Name: network.Name, Description: network.Description}})
+		for _, resource := range network.Resources {
+			item := NetworkResourceItem{Network: network.Name, NetworkResourceSpec: resource}
+			items = append(items, &Item{Kind: KindNetworkResource, Key: network.Name + "/" + resource.Name, Spec: item})
+		}
+		for _, router := range network.Routers {
+			item := NetworkRouterItem{Network: network.Name, NetworkRouterSpec: router}
+			items = append(items, &Item{Kind: KindNetworkRouter, Key: network.Name + "/" + router.key(), Spec: item})
+		}
+	}
+	for _, ns := range s.NameserverGroups {
+		items = append(items, &Item{Kind: KindNameserverGroup, Key: ns.Name, Spec: ns})
+	}
+	for _, zone := range s.Zones {
+		items = append(items, &Item{Kind: KindZone, Key: zone.Domain, Spec: zone})
+	}
+	return items
+}
+
+// State is the account described in the terms of a spec
+type State struct {
+	items map[Kind]map[string]*Item
+
+	// GroupIDs, PostureCheckIDs and NetworkIDs map the names of all objects of the account to their IDs
+	GroupIDs        map[string]string
+	PostureCheckIDs map[string]string
+	NetworkIDs      map[string]string
+
+	peers map[string]struct{}
+	// externalRefs maps the IDs of groups and posture checks to an object that uses them and can't be declared
+	externalRefs map[string]string
+}
+
+// Observe describes the objects of the account as spec items
+func Observe(account *types.Account, accountZones []*zones.Zone) *State {
+	state := &State{
+		items:           make(map[Kind]map[string]*Item, len(kindOrder)),
+		GroupIDs:        make(map[string]string, len(account.Groups)),
+		PostureCheckIDs: make(map[string]string, len(account.PostureChecks)),
+		NetworkIDs:      make(map[string]string, len(account.Networks)),
+		peers:           make(map[string]struct{}, len(account.Peers)),
+		externalRefs:    make(map[string]string),
+	}
+	for _, kind := range kindOrder {
+		state.items[kind] = make(map[string]*Item)
+	}
+
+	groupIDs := make([]string, 0, len(account.Groups))
+	for id := range account.Groups {
+		groupIDs = append(groupIDs, id)
+	}
+	sort.Strings(groupIDs)
+
+	groupNames := make(map[string]string, len(account.Groups))
+	resourceGroups := make(map[string][]string)
+	for _, id := range groupIDs {
+		group := account.Groups[id]
+		groupNames[id] = group.Name
+		for _, resource := range group.Resources {
+			resourceGroups[resource.ID] = append(resourceGroups[resource.ID], group.Name)
+		}
+		if _, ok := state.GroupIDs[group.Name]; ok {
+			continue
+		}
+		state.GroupIDs[group.Name] = id
+		if group.Name != allGroupName {
+			state.add(KindGroup, group.Name, id, GroupSpec{Name: group.Name})
+		}
+	}
+	names := func(ids []string) []string {
+		return sortedSet(mapIDs(ids, groupNames))
+	}
+
+	checkNames := make(map[string]string, len(account.PostureChecks))
+	for _, checks := range account.PostureChecks {
+		checkNames[checks.ID] = checks.Name
+		state.PostureCheckIDs[checks.Name] = checks.ID
+		state.add(KindPostureChecks, checks.Name, checks.ID, PostureChecksSpec{
+			Name:        checks.Name,
+			Description: checks.Description,
+			Checks:      checks.ToAPIResponse().Checks,
+		})
+	}
+
+	for _, policy := range account.Policies {
+		spec := PolicySpec{
+			Name:                policy.Name,
+			Description:         policy.Description,
+			Enabled:             boolPtr(policy.Enabled),
+			SourcePostureChecks: sortedSet(mapIDs(policy.SourcePostureChecks, checkNames)),
+		}
+		for _, rule := range policy.Rules {
+			ruleSpec := PolicyRuleSpec{
+				Name:          rule.Name,
+				Description:   rule.Description,
+				Enabled:       boolPtr(rule.Enabled),
+				Action:        string(rule.Action),
+				Bidirectional: rule.Bidirectional,
+				Protocol:      string(rule.Protocol),
+				Ports:         rule.Ports,
+				Sources:       names(rule.Sources),
+				Destinations:  names(rule.Destinations),
+			}
+			if len(ruleSpec.Ports) == 0 {
+				ruleSpec.Ports = nil
+			}
+			for _, portRange := range rule.PortRanges {
+				ruleSpec.PortRanges = append(ruleSpec.PortRanges, PortRangeSpec{Start: portRange.Start, End: portRange.End})
+			}
+			spec.Rules = append(spec.Rules, ruleSpec)
+		}
+		state.add(KindPolicy, policy.Name, policy.ID, spec)
+	}
+
+	networkNames := make(map[string]string, len(account.Networks))
+	for _, network := range account.Networks {
+		networkNames[network.ID] = network.Name
+		state.NetworkIDs[network.Name] = network.ID
+		state.add(KindNetwork, network.Name, network.ID, NetworkSpec{Name: network.Name, Description: network.Description})
+	}
+
+	for _, resource := range account.NetworkResources {
+		address := resource.Prefix.String()
+		if resource.Type == resourceTypes.Domain {
+			address = resource.Domain
+		}
+		item := NetworkResourceItem{
+			Network: networkNames[resource.NetworkID],
+			NetworkResourceSpec: NetworkResourceSpec{
+				Name:        resource.Name,
+				Description: resource.Description,
+				Address:     address,
+				Groups:      sortedSet(resourceGroups[resource.ID]),
+				Enabled:     boolPtr(resource.Enabled),
+				NetMap:      resource.ToAPIResponse(nil).Netmap,
+			},
+		}
+		state.add(KindNetworkResource, item.Network+"/"+resource.Name, resource.ID, item)
+	}
+
+	for _, router := range account.NetworkRouters {
+		observed := router.ToAPIResponse()
+		item := NetworkRouterItem{
+			Network: networkNames[router.NetworkID],
+			NetworkRouterSpec: NetworkRouterSpec{
+				Peer:        router.Peer,
+				PeerGroups:  names(router.PeerGroups),
+				Masquerade:  boolPtr(router.Masquerade),
+				Metric:      router.Metric,
+				Enabled:     boolPtr(router.Enabled),
+				HealthCheck: observed.HealthCheck,
+				LoadBalance: router.LoadBalance,
+				BGP:         observed.Bgp,
+				Multicast:   observed.Multicast,
+			},
+		}
+		state.add(KindNetworkRouter, item.Network+"/"+item.key(), router.ID, item)
+	}
+
+	for _, ns := range account.NameServerGroups {
+		spec := NameserverGroupSpec{
+			Name:                 ns.Name,
+			Description:          ns.Description,
+			Groups:               names(ns.Groups),
+			Primary:              ns.Primary,
+			Domains:              ns.Domains,
+			SearchDomainsEnabled: ns.SearchDomainsEnabled,
+			Enabled:              boolPtr(ns.Enabled),
+		}
+		if len(spec.Domains) == 0 {
+			spec.Domains = nil
+		}
+		for _, nameserver := range ns.NameServers {
+			spec.Nameservers = append(spec.Nameservers, nameserverURL(nameserver))
+		}
+		state.add(KindNameserverGroup, ns.Name, ns.ID, spec)
+	}
+
+	for _, zone := range accountZones {
+		spec := ZoneSpec{
+			Name:               zone.Name,
+			Domain:             zone.Domain,
+			Enabled:            boolPtr(zone.Enabled),
+			EnableSearchDomain: zone.EnableSearchDomain,
+			DistributionGroups: names(zone.DistributionGroups),
+		}
+		for _, record := range zone.Records {
+			spec.Records = append(spec.Records, RecordSpec{Name: record.Name, Type: string(record.Type), Content: record.Content, TTL: record.TTL})
+		}
+		sort.Slice(spec.Records, func(a, b int) bool {
+			return spec.Records[a].key() < spec.Records[b].key()
+		})
+		state.add(KindZone, zone.Domain, zone.ID, spec)
+	}
+
+	for id := range account.Peers {
+		state.peers[id] = struct{}{}
+	}
+
+	state.observeExternalRefs(account)
+
+	return state
+}
+
+// observeExternalRefs records the groups and posture checks used by objects that can't be declared in a spec
+func (s *State) observeExternalRefs(account *types.Account) {
+	addRefs := func(ids []string, description string) {
+		for _, id := range ids {
+			if _, ok := s.externalRefs[id]; !ok {
+				s.externalRefs[id] = description
+			}
+		}
+	}
+
+	for _, r := range account.Routes {
+		addRefs(r.Groups, fmt.Sprintf("route %s", r.NetID))
+		addRefs(r.PeerGroups, fmt.Sprintf("route %s", r.NetID))
+		addRefs(r.AccessControlGroups, fmt.Sprintf("route %s", r.NetID))
+	}
+	for _, key := range account.SetupKeys {
+		addRefs(key.AutoGroups, fmt.Sprintf("setup key %s", key.Name))
+		if key.Constraints != nil {
+			addRefs(key.Constraints.PostureChecks, fmt.Sprintf("setup key %s", key.Name))
+		}
+	}
+	for _, user := range account.Users {
+		addRefs(user.AutoGroups, fmt.Sprintf("user %s", user.Id))
+	}
+	addRefs(account.DNSSettings.DisabledManagementGroups, "DNS settings")
+}
+
+func (s *State) add(kind Kind, key, id string, spec any) {
+	if _, ok := s.items[kind][key]; ok {
+		// objects that share a name can't be told apart by a spec, the first one is used
+		return
+	}
+	s.items[kind][key] = &Item{Kind: kind, Key: key, ID: id, Spec: spec}
+}
+
+func nameserverURL(ns nbdns.NameServer) string {
+	return fmt.Sprintf("%s://%s", ns.NSType.String(), ns.AddrPort().String())
+}
+
+// mapIDs translates IDs to names, unknown IDs are kept
+func mapIDs(ids []string, names map[string]string) []string {
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if name, ok := names[id]; ok {
+			result = append(result, name)
+			continue
+		}
+		result = append(result, id)
+	}
+	return result
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
@@ -0,0 +1,12 @@
+package history
+
+import (
+	"context"
+)
+
+type Manager interface {
+	GetVersions(ctx context.Context, accountID, userID string, objectType ObjectType, objectID string) ([]*Version, error)
+	GetVersion(ctx context.Context, accountID, userID string, objectType ObjectType, objectID string, version int) (*Version, error)
+	RollbackRoute(ctx context.Context, accountID, userID, routeID string, version int) (*Version, error)
+	RollbackNetwork(ctx context.Context, accountID, userID, networkID string, version int) (*Version, error)
+}
@@ -0,0 +1,128 @@
+package manager
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netbirdio/netbird/management/internals/modules/history"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/http/util"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type handler struct {
+	manager history.Manager
+}
+
+func RegisterEndpoints(router *mux.Router, manager history.Manager) {
+	h := &handler{
+		manager: manager,
+	}
+
+	router.HandleFunc("/routes/{routeId}/versions", h.getVersions(history.ObjectRoute, "routeId")).Methods("GET", "OPTIONS")
+	router.HandleFunc("/routes/{routeId}/versions/{version}", h.getVersion(history.ObjectRoute, "routeId")).Methods("GET", "OPTIONS")
+	router.HandleFunc("/routes/{routeId}/versions/{version}/rollback", h.rollback(history.ObjectRoute, "routeId", manager.RollbackRoute)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/networks/{networkId}/versions", h.getVersions(history.ObjectNetwork, "networkId")).Methods("GET", "OPTIONS")
+	router.HandleFunc("/networks/{networkId}/versions/{version}", h.getVersion(history.ObjectNetwork, "networkId")).Methods("GET", "OPTIONS")
+	router.HandleFunc("/networks/{networkId}/versions/{version}/rollback", h.rollback(history.ObjectNetwork, "networkId", manager.RollbackNetwork)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/networks/{networkId}/resources/{resourceId}/versions", h.getVersions(history.ObjectNetworkResource, "resourceId")).Methods("GET", "OPTIONS")
+	router.HandleFunc("/networks/{networkId}/routers/{routerId}/versions", h.getVersions(history.ObjectNetworkRouter, "routerId")).Methods("GET", "OPTIONS")
+}
+
+func (h *handler) getVersions(objectType history.ObjectType, idVar string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+
+		objectID := mux.Vars(r)[idVar]
+		if objectID == "" {
+			util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "%s ID is required", objectType), w)
+			return
+		}
+
+		versions, err := h.manager.GetVersions(r.Context(), userAuth.AccountId, userAuth.UserId, objectType, objectID)
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+
+		apiVersions := make([]*api.ObjectVersion, 0, len(versions))
+		for _, version := range versions {
+			apiVersions = append(apiVersions, version.ToAPIResponse())
+		}
+
+		util.WriteJSONObject(r.Context(), w, apiVersions)
+	}
+}
+
+func (h *handler) getVersion(objectType history.ObjectType, idVar string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+
+		objectID, version, err := parseVersionVars(r, objectType, idVar)
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+
+		objectVersion, err := h.manager.GetVersion(r.Context(), userAuth.AccountId, userAuth.UserId, objectType, objectID, version)
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+
+		util.WriteJSONObject(r.Context(), w, objectVersion.ToAPIResponse())
+	}
+}
+
+type rollbackFunc func(ctx context.Context, accountID, userID, objectID string, version int) (*history.Version, error)
+
+func (h *handler) rollback(objectType history.ObjectType, idVar string, rollback rollbackFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+
+		objectID, version, err := parseVersionVars(r, objectType, idVar)
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+
+		rolledBack, err := rollback(r.Context(), userAuth.AccountId, userAuth.UserId, objectID, version)
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+
+		util.WriteJSONObject(r.Context(), w, rolledBack.ToAPIResponse())
+	}
+}
+
+func parseVersionVars(r *http.Request, objectType history.ObjectType, idVar string) (string, int, error) {
+	vars := mux.Vars(r)
+	objectID := vars[idVar]
+	if objectID == "" {
+		return "", 0, status.Errorf(status.InvalidArgument, "%s ID is required", objectType)
+	}
+
+	version, err := strconv.Atoi(vars["version"])
+	if err != nil || version < 1 {
+		return "", 0, status.Errorf(status.InvalidArgument, "invalid version %q", vars["version"])
+	}
+
+	return objectID, version, nil
+}
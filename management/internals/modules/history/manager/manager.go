@@ -0,0 +1,420 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/netbirdio/netbird/management/internals/modules/history"
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/management/server/activity"
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
+	networkTypes "github.com/netbirdio/netbird/management/server/networks/types"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/management/server/util"
+	"github.com/netbirdio/netbird/route"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type managerImpl struct {
+	store              store.Store
+	accountManager     account.Manager
+	permissionsManager permissions.Manager
+}
+
+func NewManager(store store.Store, accountManager account.Manager, permissionsManager permissions.Manager) history.Manager {
+	return &managerImpl{
+		store:              store,
+		accountManager:     accountManager,
+		permissionsManager: permissionsManager,
+	}
+}
+
+func (m *managerImpl) GetVersions(ctx context.Context, accountID, userID string, objectType history.ObjectType, objectID string) ([]*history.Version, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, objectType, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetObjectVersions(ctx, store.LockingStrengthNone, accountID, objectType, objectID)
+}
+
+func (m *managerImpl) GetVersion(ctx context.Context, accountID, userID string, objectType history.ObjectType, objectID string, version int) (*history.Version, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, objectType, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetObjectVersion(ctx, store.LockingStrengthNone, accountID, objectType, objectID, version)
+}
+
+// RollbackRoute restores the route to its state after the given version, recreating it if it was deleted since
+func (m *managerImpl) RollbackRoute(ctx context.Context, accountID, userID, routeID string, version int) (*history.Version, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, history.ObjectRoute, operations.Update); err != nil {
+		return nil, err
+	}
+
+	restored := &route.Route{}
+	var rolledBack *history.Version
+	err := m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		target, err := transaction.GetObjectVersion(ctx, store.LockingStrengthNone, accountID, history.ObjectRoute, routeID, version)
+		if err != nil {
+			return err
+		}
+
+		if err = target.Restore(restored); err != nil {
+			return err
+		}
+		restored.ID = route.ID(routeID)
+		restored.AccountID = accountID
+
+		current, err := transaction.GetRouteByID(ctx, store.LockingStrengthUpdate, accountID, routeID)
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+
+		if err = validateReferences(ctx, transaction, accountID, restored.Peer, restored.PeerGroups, restored.Groups, restored.AccessControlGroups); err != nil {
+			return err
+		}
+
+		if err = transaction.SaveRoute(ctx, restored); err != nil {
+			return fmt.Errorf("failed to restore route: %w", err)
+		}
+
+		rolledBack, err = saveRollback(ctx, transaction, accountID, userID, history.ObjectRoute, routeID, current, restored)
+		if err != nil {
+			return err
+		}
+
+		return transaction.IncrementNetworkSerial(ctx, accountID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	meta := restored.EventMeta()
+	meta["version"] = version
+	m.accountManager.StoreEvent(ctx, userID, routeID, accountID, activity.RouteRolledBack, meta)
+
+	m.accountManager.UpdateAccountPeers(ctx, accountID)
+
+	return rolledBack, nil
+}
+
+// RollbackNetwork restores the network with its resources and routers to their state after the given version of the
+// network. Resources and routers created since are deleted, the ones without history are left as they are.
+func (m *managerImpl) RollbackNetwork(ctx context.Context, accountID, userID, networkID string, version int) (*history.Version, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, history.ObjectNetwork, operations.Update); err != nil {
+		return nil, err
+	}
+
+	restored := &networkTypes.Network{}
+	var rolledBack *history.Version
+	err := m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		target, err := transaction.GetObjectVersion(ctx, store.LockingStrengthNone, accountID, history.ObjectNetwork, networkID, version)
+		if err != nil {
+			return err
+		}
+
+		if err = target.Restore(restored); err != nil {
+			return err
+		}
+		restored.ID = networkID
+		restored.AccountID = accountID
+
+		current, err := transaction.GetNetworkByID(ctx, store.LockingStrengthUpdate, accountID, networkID)
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+
+		if err = transaction.SaveNetwork(ctx, restored); err != nil {
+			return fmt.Errorf("failed to restore network: %w", err)
+		}
+
+		if err = rollbackResources(ctx, transaction, accountID, userID, networkID, target.Seq); err != nil {
+			return err
+		}
+
+		if err = rollbackRouters(ctx, transaction, accountID, userID, networkID, target.Seq); err != nil {
+			return err
+		}
+
+		rolledBack, err = saveRollback(ctx, transaction, accountID, userID, history.ObjectNetwork, networkID, current, restored)
+		if err != nil {
+			return err
+		}
+
+		return transaction.IncrementNetworkSerial(ctx, accountID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	meta := restored.EventMeta()
+	meta["version"] = version
+	m.accountManager.StoreEvent(ctx, userID, networkID, accountID, activity.NetworkRolledBack, meta)
+
+	m.accountManager.UpdateAccountPeers(ctx, accountID)
+
+	return rolledBack, nil
+}
+
+func rollbackResources(ctx context.Context, transaction store.Store, accountID, userID, networkID string, seq uint64) error {
+	versions, err := transaction.GetObjectVersionsByType(ctx, store.LockingStrengthNone, accountID, history.ObjectNetworkResource)
+	if err != nil {
+		return err
+	}
+
+	resources, err := transaction.GetNetworkResourcesByNetID(ctx, store.LockingStrengthUpdate, accountID, networkID)
+	if err != nil {
+		return fmt.Errorf("failed to get resources in network: %w", err)
+	}
+	currentResources := make(map[string]*resourceTypes.NetworkResource, len(resources))
+	for _, resource := range resources {
+		currentResources[resource.ID] = resource
+	}
+
+	states := statesAt(versions, seq)
+	for _, resourceID := range slices.Sorted(maps.Keys(states)) {
+		var restored *resourceTypes.NetworkResource
+		if state := states[resourceID]; state != nil && len(state.After) > 0 {
+			restored = &resourceTypes.NetworkResource{}
+			if err = state.Restore(restored); err != nil {
+				return err
+			}
+			if restored.NetworkID != networkID {
+				restored = nil
+			}
+		}
+
+		current := currentResources[resourceID]
+		if restored == nil && current == nil {
+			continue
+		}
+
+		groups, err := transaction.GetResourceGroups(ctx, store.LockingStrengthUpdate, accountID, resourceID)
+		if err != nil {
+			return err
+		}
+		currentGroupIDs := make([]string, 0, len(groups))
+		for _, group := range groups {
+			currentGroupIDs = append(currentGroupIDs, group.ID)
+		}
+		if current != nil {
+			current.GroupIDs = currentGroupIDs
+		}
+
+		version, err := history.NewVersion(accountID, userID, history.ObjectNetworkResource, resourceID, history.OperationRolledBack, current, restored)
+		if err != nil {
+			return err
+		}
+		if current != nil && restored != nil && len(version.Changes()) == 0 {
+			continue
+		}
+
+		if restored == nil {
+			if err = deleteResource(ctx, transaction, accountID, current); err != nil {
+				return err
+			}
+		} else if err = restoreResource(ctx, transaction, accountID, restored, currentGroupIDs); err != nil {
+			return err
+		}
+
+		if err = transaction.SaveObjectVersion(ctx, version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func restoreResource(ctx context.Context, transaction store.Store, accountID string, resource *resourceTypes.NetworkResource, currentGroupIDs []string) error {
+	existing, err := transaction.GetNetworkResourceByName(ctx, store.LockingStrengthNone, accountID, resource.Name)
+	if err == nil && existing.ID != resource.ID {
+		return status.Errorf(status.InvalidArgument, "can't restore resource %s, another resource with its name exists", resource.Name)
+	}
+
+	if err = validateReferences(ctx, transaction, accountID, "", resource.GroupIDs); err != nil {
+		return err
+	}
+
+	resource.AccountID = accountID
+	if err = transaction.SaveNetworkResource(ctx, resource); err != nil {
+		return fmt.Errorf("failed to restore network resource: %w", err)
+	}
+
+	res := &types.Resource{
+		ID:   resource.ID,
+		Type: types.ResourceType(resource.Type.String()),
+	}
+	for _, groupID := range util.Difference(resource.GroupIDs, currentGroupIDs) {
+		if err = transaction.AddResourceToGroup(ctx, accountID, groupID, res); err != nil {
+			return fmt.Errorf("failed to add resource to group: %w", err)
+		}
+	}
+	for _, groupID := range util.Difference(currentGroupIDs, resource.GroupIDs) {
+		if err = transaction.RemoveResourceFromGroup(ctx, accountID, groupID, resource.ID); err != nil {
+			return fmt.Errorf("failed to remove resource from group: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func deleteResource(ctx context.Context, transaction store.Store, accountID string, resource *resourceTypes.NetworkResource) error {
+	for _, groupID := range resource.GroupIDs {
+		if err := transaction.RemoveResourceFromGroup(ctx, accountID, groupID, resource.ID); err != nil {
+			return fmt.Errorf("failed to remove resource from group: %w", err)
+		}
+	}
+
+	if err := transaction.DeleteNetworkResource(ctx, accountID, resource.ID); err != nil {
+		return fmt.Errorf("failed to delete network resource: %w", err)
+	}
+
+	return nil
+}
+
+func rollbackRouters(ctx context.Context, transaction store.Store, accountID, userID, networkID string, seq uint64) error {
+	versions, err := transaction.GetObjectVersionsByType(ctx, store.LockingStrengthNone, accountID, history.ObjectNetworkRouter)
+	if err != nil {
+		return err
+	}
+
+	routers, err := transaction.GetNetworkRoutersByNetID(ctx, store.LockingStrengthUpdate, accountID, networkID)
+	if err != nil {
+		return fmt.Errorf("failed to get routers in network: %w", err)
+	}
+	currentRouters := make(map[string]*routerTypes.NetworkRouter, len(routers))
+	for _, router := range routers {
+		currentRouters[router.ID] = router
+	}
+
+	states := statesAt(versions, seq)
+	for _, routerID := range slices.Sorted(maps.Keys(states)) {
+		var restored *routerTypes.NetworkRouter
+		if state := states[routerID]; state != nil && len(state.After) > 0 {
+			restored = &routerTypes.NetworkRouter{}
+			if err = state.Restore(restored); err != nil {
+				return err
+			}
+			if restored.NetworkID != networkID {
+				restored = nil
+			}
+		}
+
+		current := currentRouters[routerID]
+		if restored == nil && current == nil {
+			continue
+		}
+
+		version, err := history.NewVersion(accountID, userID, history.ObjectNetworkRouter, routerID, history.OperationRolledBack, current, restored)
+		if err != nil {
+			return err
+		}
+		if current != nil && restored != nil && len(version.Changes()) == 0 {
+			continue
+		}
+
+		if restored == nil {
+			if err = transaction.DeleteNetworkRouter(ctx, accountID, routerID); err != nil {
+				return fmt.Errorf("failed to delete network router: %w", err)
+			}
+		} else {
+			if err = validateReferences(ctx, transaction, accountID, restored.Peer, restored.PeerGroups); err != nil {
+				return err
+			}
+			restored.AccountID = accountID
+			if err = transaction.SaveNetworkRouter(ctx, restored); err != nil {
+				return fmt.Errorf("failed to restore network router: %w", err)
+			}
+		}
+
+		if err = transaction.SaveObjectVersion(ctx, version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// statesAt returns the last version up to seq of every object in versions. Objects that were created after seq map to
+// nil. The versions have to be ordered by their sequence.
+func statesAt(versions []*history.Version, seq uint64) map[string]*history.Version {
+	states := make(map[string]*history.Version)
+	for _, version := range versions {
+		if version.Seq <= seq {
+			states[version.ObjectID] = version
+			continue
+		}
+		if _, ok := states[version.ObjectID]; !ok {
+			states[version.ObjectID] = nil
+		}
+	}
+	return states
+}
+
+func saveRollback(ctx context.Context, transaction store.Store, accountID, userID string, objectType history.ObjectType, objectID string, before, after any) (*history.Version, error) {
+	version, err := history.NewVersion(accountID, userID, objectType, objectID, history.OperationRolledBack, before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = transaction.SaveObjectVersion(ctx, version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// validateReferences checks that the peer and the groups a restored object refers to still exist
+func validateReferences(ctx context.Context, transaction store.Store, accountID, peerID string, groupIDLists ...[]string) error {
+	if peerID != "" {
+		if _, err := transaction.GetPeerByID(ctx, store.LockingStrengthNone, accountID, peerID); err != nil {
+			return status.Errorf(status.InvalidArgument, "can't restore the version, peer %s no longer exists", peerID)
+		}
+	}
+
+	var groupIDs []string
+	for _, ids := range groupIDLists {
+		groupIDs = append(groupIDs, ids...)
+	}
+	if len(groupIDs) == 0 {
+		return nil
+	}
+
+	groups, err := transaction.GetGroupsByIDs(ctx, store.LockingStrengthNone, accountID, groupIDs)
+	if err != nil {
+		return err
+	}
+	for _, groupID := range groupIDs {
+		if _, ok := groups[groupID]; !ok {
+			return status.Errorf(status.InvalidArgument, "can't restore the version, group %s no longer exists", groupID)
+		}
+	}
+	return nil
+}
+
+func isNotFound(err error) bool {
+	sErr, ok := status.FromError(err)
+	return ok && sErr.Type() == status.NotFound
+}
+
+func (m *managerImpl) validatePermissions(ctx context.Context, accountID, userID string, objectType history.ObjectType, operation operations.Operation) error {
+	module := modules.Networks
+	if objectType == history.ObjectRoute {
+		module = modules.Routes
+	}
+
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, module, operation)
+	if err != nil {
+		return status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return status.NewPermissionDeniedError()
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+package manager
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/history"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/groups"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	"github.com/netbirdio/netbird/management/server/networks"
+	"github.com/netbirdio/netbird/management/server/networks/resources"
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	"github.com/netbirdio/netbird/management/server/networks/routers"
+	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
+	networkTypes "github.com/netbirdio/netbird/management/server/networks/types"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/route"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	testAccountID = "account-id"
+	adminID       = "admin-id"
+	regularUserID = "user-id"
+	routerPeerID  = "router-peer-id"
+)
+
+func setupTest(t *testing.T) (*managerImpl, store.Store, *mock_server.MockAccountManager, permissions.Manager, func()) {
+	t.Helper()
+
+	ctx := context.Background()
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+
+	err = testStore.SaveAccount(ctx, &types.Account{
+		Id:      testAccountID,
+		Network: types.NewNetwork(),
+		Users: map[string]*types.User{
+			adminID:       {Id: adminID, AccountID: testAccountID, Role: types.UserRoleAdmin},
+			regularUserID: {Id: regularUserID, AccountID: testAccountID, Role: types.UserRoleUser},
+		},
+		Peers: map[string]*nbpeer.Peer{
+			routerPeerID: {ID: routerPeerID, AccountID: testAccountID, Key: "router-key", IP: net.IP{100, 64, 0, 1}, DNSLabel: "router", Status: &nbpeer.PeerStatus{}},
+		},
+		Groups: map[string]*types.Group{
+			"all":     {ID: "all", AccountID: testAccountID, Name: "All", Peers: []string{routerPeerID}},
+			"servers": {ID: "servers", AccountID: testAccountID, Name: "Servers"},
+		},
+		Settings:    &types.Settings{},
+		DNSSettings: types.DNSSettings{DisabledManagementGroups: []string{}},
+	})
+	require.NoError(t, err)
+
+	mockAccountManager := &mock_server.MockAccountManager{}
+	permissionsManager := permissions.NewManager(testStore)
+
+	manager := &managerImpl{
+		store:              testStore,
+		accountManager:     mockAccountManager,
+		permissionsManager: permissionsManager,
+	}
+
+	return manager, testStore, mockAccountManager, permissionsManager, cleanup
+}
+
+// saveRoute changes the route the way the account manager does, storing the route with its version in a transaction
+func saveRoute(t *testing.T, s store.Store, operation history.Operation, before, after *route.Route) {
+	t.Helper()
+
+	ctx := context.Background()
+	err := s.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		objectID := ""
+		if after != nil {
+			objectID = string(after.ID)
+			if err := transaction.SaveRoute(ctx, after); err != nil {
+				return err
+			}
+		} else {
+			objectID = string(before.ID)
+			if err := transaction.DeleteRoute(ctx, testAccountID, objectID); err != nil {
+				return err
+			}
+		}
+		return history.Record(ctx, transaction, testAccountID, adminID, history.ObjectRoute, objectID, operation, before, after)
+	})
+	require.NoError(t, err)
+}
+
+func TestManagerImpl_RollbackRoute(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, mockAccountManager, _, cleanup := setupTest(t)
+	defer cleanup()
+
+	var events []activity.ActivityDescriber
+	mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+		events = append(events, activityID)
+	}
+
+	var peerUpdates []string
+	mockAccountManager.UpdateAccountPeersFunc = func(_ context.Context, accountID string) {
+		peerUpdates = append(peerUpdates, accountID)
+	}
+
+	original := &route.Route{
+		ID:          "route-id",
+		AccountID:   testAccountID,
+		Network:     netip.MustParsePrefix("192.168.1.0/24"),
+		NetID:       "office",
+		Peer:        routerPeerID,
+		NetworkType: route.IPv4Network,
+		Metric:      9999,
+		Enabled:     true,
+		Groups:      []string{"all"},
+	}
+	saveRoute(t, testStore, history.OperationCreated, nil, original)
+
+	updated := original.Copy()
+	updated.Metric = 100
+	updated.Groups = []string{"servers"}
+	saveRoute(t, testStore, history.OperationUpdated, original, updated)
+	saveRoute(t, testStore, history.OperationDeleted, updated, nil)
+
+	versions, err := manager.GetVersions(ctx, testAccountID, adminID, history.ObjectRoute, "route-id")
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{versions[0].Version, versions[1].Version, versions[2].Version})
+	assert.Equal(t, history.OperationDeleted, versions[2].Operation)
+
+	changes := versions[1].Changes()
+	require.Len(t, changes, 2)
+	assert.Equal(t, "Groups", changes[0].Field)
+	assert.Equal(t, history.Change{Field: "Metric", Before: float64(9999), After: float64(100)}, changes[1])
+
+	_, err = manager.RollbackRoute(ctx, testAccountID, regularUserID, "route-id", 1)
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+
+	_, err = manager.RollbackRoute(ctx, testAccountID, adminID, "route-id", 3)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.InvalidArgument, s.Type())
+
+	_, err = manager.RollbackRoute(ctx, testAccountID, adminID, "route-id", 7)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.NotFound, s.Type())
+
+	rolledBack, err := manager.RollbackRoute(ctx, testAccountID, adminID, "route-id", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 4, rolledBack.Version)
+	assert.Equal(t, history.OperationRolledBack, rolledBack.Operation)
+	assert.Empty(t, rolledBack.Before, "the route was deleted before the rollback")
+	assert.Contains(t, events, activity.RouteRolledBack)
+	assert.Equal(t, []string{testAccountID}, peerUpdates, "the peers should be updated after the rollback")
+
+	restored, err := testStore.GetRouteByID(ctx, store.LockingStrengthNone, testAccountID, "route-id")
+	require.NoError(t, err)
+	assert.Equal(t, 9999, restored.Metric)
+	assert.Equal(t, []string{"all"}, restored.Groups)
+	assert.Equal(t, original.Network, restored.Network)
+}
+
+func TestManagerImpl_RollbackRouteMissingReferences(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, _, _, cleanup := setupTest(t)
+	defer cleanup()
+
+	original := &route.Route{
+		ID:          "route-id",
+		AccountID:   testAccountID,
+		Network:     netip.MustParsePrefix("192.168.1.0/24"),
+		NetID:       "office",
+		Peer:        routerPeerID,
+		NetworkType: route.IPv4Network,
+		Metric:      9999,
+		Enabled:     true,
+		Groups:      []string{"deleted-group"},
+	}
+	saveRoute(t, testStore, history.OperationCreated, nil, original)
+
+	_, err := manager.RollbackRoute(ctx, testAccountID, adminID, "route-id", 1)
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.InvalidArgument, s.Type())
+
+	versions, err := manager.GetVersions(ctx, testAccountID, adminID, history.ObjectRoute, "route-id")
+	require.NoError(t, err)
+	assert.Len(t, versions, 1, "a failed rollback doesn't create a version")
+}
+
+func TestManagerImpl_RollbackNetwork(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, mockAccountManager, permissionsManager, cleanup := setupTest(t)
+	defer cleanup()
+
+	var events []activity.ActivityDescriber
+	mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+		events = append(events, activityID)
+	}
+
+	groupsManager := groups.NewManager(testStore, permissionsManager, mockAccountManager)
+	resourcesManager := resources.NewManager(testStore, permissionsManager, groupsManager, mockAccountManager)
+	routersManager := routers.NewManager(testStore, permissionsManager, mockAccountManager)
+	networksManager := networks.NewManager(testStore, permissionsManager, resourcesManager, routersManager, mockAccountManager)
+
+	network, err := networksManager.CreateNetwork(ctx, adminID, &networkTypes.Network{AccountID: testAccountID, Name: "office"})
+	require.NoError(t, err)
+
+	nas, err := resourcesManager.CreateResource(ctx, adminID, &resourceTypes.NetworkResource{
+		AccountID: testAccountID, NetworkID: network.ID, Name: "nas", Address: "192.168.1.20", GroupIDs: []string{"all"}, Enabled: true,
+	})
+	require.NoError(t, err)
+
+	router, err := routersManager.CreateRouter(ctx, adminID, &routerTypes.NetworkRouter{
+		AccountID: testAccountID, NetworkID: network.ID, Peer: routerPeerID, Metric: 100, Enabled: true,
+	})
+	require.NoError(t, err)
+
+	_, err = networksManager.UpdateNetwork(ctx, adminID, &networkTypes.Network{ID: network.ID, AccountID: testAccountID, Name: "office-v2"})
+	require.NoError(t, err)
+
+	// changes after version 2 of the network that the rollback reverts
+	printer, err := resourcesManager.CreateResource(ctx, adminID, &resourceTypes.NetworkResource{
+		AccountID: testAccountID, NetworkID: network.ID, Name: "printer", Address: "192.168.1.30", GroupIDs: []string{"all"}, Enabled: true,
+	})
+	require.NoError(t, err)
+
+	nasUpdate := *nas
+	nasUpdate.Address = "192.168.1.21"
+	nasUpdate.GroupIDs = []string{"servers"}
+	_, err = resourcesManager.UpdateResource(ctx, adminID, &nasUpdate)
+	require.NoError(t, err)
+
+	require.NoError(t, routersManager.DeleteRouter(ctx, testAccountID, adminID, network.ID, router.ID))
+
+	_, err = networksManager.UpdateNetwork(ctx, adminID, &networkTypes.Network{ID: network.ID, AccountID: testAccountID, Name: "office-v3"})
+	require.NoError(t, err)
+
+	_, err = manager.RollbackNetwork(ctx, testAccountID, regularUserID, network.ID, 2)
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+
+	rolledBack, err := manager.RollbackNetwork(ctx, testAccountID, adminID, network.ID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 4, rolledBack.Version)
+	assert.Equal(t, []history.Change{{Field: "Name", Before: "office-v3", After: "office-v2"}}, rolledBack.Changes())
+	assert.Contains(t, events, activity.NetworkRolledBack)
+
+	restoredNetwork, err := testStore.GetNetworkByID(ctx, store.LockingStrengthNone, testAccountID, network.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "office-v2", restoredNetwork.Name)
+
+	_, err = testStore.GetNetworkResourceByID(ctx, store.LockingStrengthNone, testAccountID, printer.ID)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.NotFound, s.Type())
+
+	restoredNas, err := testStore.GetNetworkResourceByID(ctx, store.LockingStrengthNone, testAccountID, nas.ID)
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParsePrefix("192.168.1.20/32"), restoredNas.Prefix)
+
+	nasGroups, err := testStore.GetResourceGroups(ctx, store.LockingStrengthNone, testAccountID, nas.ID)
+	require.NoError(t, err)
+	require.Len(t, nasGroups, 1)
+	assert.Equal(t, "all", nasGroups[0].ID)
+
+	restoredRouter, err := testStore.GetNetworkRouterByID(ctx, store.LockingStrengthNone, testAccountID, router.ID)
+	require.NoError(t, err)
+	assert.Equal(t, routerPeerID, restoredRouter.Peer)
+	assert.Equal(t, 100, restoredRouter.Metric)
+
+	routerVersions, err := manager.GetVersions(ctx, testAccountID, adminID, history.ObjectNetworkRouter, router.ID)
+	require.NoError(t, err)
+	require.Len(t, routerVersions, 3)
+	assert.Equal(t, history.OperationRolledBack, routerVersions[2].Operation)
+}
+
+func TestStatesAt(t *testing.T) {
+	versions := []*history.Version{
+		{Seq: 1, ObjectID: "a", Version: 1},
+		{Seq: 2, ObjectID: "b", Version: 1},
+		{Seq: 3, ObjectID: "a", Version: 2},
+		{Seq: 4, ObjectID: "c", Version: 1},
+		{Seq: 5, ObjectID: "b", Version: 2},
+	}
+
+	states := statesAt(versions, 3)
+	require.Len(t, states, 3)
+	assert.Equal(t, 2, states["a"].Version)
+	assert.Equal(t, 1, states["b"].Version, "later versions are ignored")
+	assert.Nil(t, states["c"], "objects created later have no state")
+}
@@ -0,0 +1,61 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Recorder stores the versions, it's implemented by the store and its transactions
+type Recorder interface {
+	SaveObjectVersion(ctx context.Context, version *Version) error
+}
+
+// Record stores a version of an object with the snapshots before and after the change. It should be called in the
+// transaction changing the object, before is nil for creations and after is nil for deletions.
+func Record(ctx context.Context, recorder Recorder, accountID, userID string, objectType ObjectType, objectID string, operation Operation, before, after any) error {
+	version, err := NewVersion(accountID, userID, objectType, objectID, operation, before, after)
+	if err != nil {
+		return err
+	}
+
+	return recorder.SaveObjectVersion(ctx, version)
+}
+
+// NewVersion creates a version of an object with the snapshots before and after the change, the version number is
+// assigned when the version is saved
+func NewVersion(accountID, userID string, objectType ObjectType, objectID string, operation Operation, before, after any) (*Version, error) {
+	version := &Version{
+		AccountID:  accountID,
+		ObjectType: objectType,
+		ObjectID:   objectID,
+		Operation:  operation,
+		UserID:     userID,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	var err error
+	if version.Before, err = snapshot(before); err != nil {
+		return nil, fmt.Errorf("snapshot %s %s: %w", objectType, objectID, err)
+	}
+	if version.After, err = snapshot(after); err != nil {
+		return nil, fmt.Errorf("snapshot %s %s: %w", objectType, objectID, err)
+	}
+
+	return version, nil
+}
+
+func snapshot(object any) ([]byte, error) {
+	if object == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	if string(b) == "null" {
+		return nil, nil
+	}
+	return b, nil
+}
@@ -0,0 +1,133 @@
+package history
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// ObjectType is the type of a versioned object
+type ObjectType string
+
+const (
+	ObjectRoute           ObjectType = "route"
+	ObjectNetwork         ObjectType = "network"
+	ObjectNetworkResource ObjectType = "network_resource"
+	ObjectNetworkRouter   ObjectType = "network_router"
+)
+
+// ObjectTypes are all the versioned object types
+var ObjectTypes = []ObjectType{ObjectRoute, ObjectNetwork, ObjectNetworkResource, ObjectNetworkRouter}
+
+// Operation is the change that created a version
+type Operation string
+
+const (
+	OperationCreated    Operation = "created"
+	OperationUpdated    Operation = "updated"
+	OperationDeleted    Operation = "deleted"
+	OperationRolledBack Operation = "rolled_back"
+)
+
+// Version is a change of a versioned object with the full snapshots of the object before and after the change. The
+// snapshot before a creation and after a deletion is empty.
+type Version struct {
+	// Seq orders the versions of all objects of an account
+	Seq        uint64     `gorm:"primaryKey;autoIncrement"`
+	AccountID  string     `gorm:"index:idx_object_versions_object"`
+	ObjectType ObjectType `gorm:"index:idx_object_versions_object"`
+	ObjectID   string     `gorm:"index:idx_object_versions_object"`
+	// Version numbers the versions of an object starting at 1
+	Version   int
+	Operation Operation
+	UserID    string
+	CreatedAt time.Time
+	Before    []byte
+	After     []byte
+}
+
+// TableName returns the name of the table for the Version model in the database.
+func (*Version) TableName() string {
+	return "object_versions"
+}
+
+// Change is a top level field of the object that differs between the snapshots of a version
+type Change struct {
+	Field  string
+	Before any
+	After  any
+}
+
+// Changes returns the fields that differ between the snapshots, sorted by name
+func (v *Version) Changes() []Change {
+	before := snapshotFields(v.Before)
+	after := snapshotFields(v.After)
+
+	fields := make([]string, 0, len(before)+len(after))
+	for field := range before {
+		fields = append(fields, field)
+	}
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	var changes []Change
+	for _, field := range fields {
+		if reflect.DeepEqual(before[field], after[field]) {
+			continue
+		}
+		changes = append(changes, Change{Field: field, Before: before[field], After: after[field]})
+	}
+	return changes
+}
+
+// Restore decodes the snapshot after the change into object. Versions that deleted the object can't be restored.
+func (v *Version) Restore(object any) error {
+	if len(v.After) == 0 {
+		return status.Errorf(status.InvalidArgument, "version %d of %s %s deleted it and can't be restored", v.Version, v.ObjectType, v.ObjectID)
+	}
+	if err := json.Unmarshal(v.After, object); err != nil {
+		return status.Errorf(status.Internal, "failed to decode version %d of %s %s", v.Version, v.ObjectType, v.ObjectID)
+	}
+	return nil
+}
+
+func snapshotFields(snapshot []byte) map[string]any {
+	fields := make(map[string]any)
+	if len(snapshot) > 0 {
+		_ = json.Unmarshal(snapshot, &fields)
+	}
+	return fields
+}
+
+func (v *Version) ToAPIResponse() *api.ObjectVersion {
+	changes := make([]api.ObjectVersionChange, 0)
+	for _, change := range v.Changes() {
+		changes = append(changes, api.ObjectVersionChange{Field: change.Field, Before: change.Before, After: change.After})
+	}
+
+	resp := &api.ObjectVersion{
+		Version:   v.Version,
+		ObjectId:  v.ObjectID,
+		Operation: api.ObjectVersionOperation(v.Operation),
+		UserId:    v.UserID,
+		CreatedAt: v.CreatedAt,
+		Changes:   changes,
+	}
+	if len(v.Before) > 0 {
+		before := snapshotFields(v.Before)
+		resp.Before = &before
+	}
+	if len(v.After) > 0 {
+		after := snapshotFields(v.After)
+		resp.After = &after
+	}
+	return resp
+}
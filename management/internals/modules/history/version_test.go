@@ -0,0 +1,61 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+type object struct {
+	Name    string
+	Metric  int
+	Groups  []string
+	Enabled bool
+}
+
+func TestNewVersion(t *testing.T) {
+	var deleted *object
+	version, err := NewVersion("account", "user", ObjectRoute, "id", OperationCreated, deleted, &object{Name: "office", Metric: 10})
+	require.NoError(t, err)
+	assert.Empty(t, version.Before, "a nil object has no snapshot")
+	assert.JSONEq(t, `{"Name":"office","Metric":10,"Groups":null,"Enabled":false}`, string(version.After))
+}
+
+func TestVersion_Changes(t *testing.T) {
+	before := &object{Name: "office", Metric: 10, Groups: []string{"a"}, Enabled: true}
+	after := &object{Name: "office", Metric: 20, Groups: []string{"a", "b"}, Enabled: true}
+
+	version, err := NewVersion("account", "user", ObjectRoute, "id", OperationUpdated, before, after)
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Field: "Groups", Before: []any{"a"}, After: []any{"a", "b"}},
+		{Field: "Metric", Before: float64(10), After: float64(20)},
+	}, version.Changes())
+
+	version, err = NewVersion("account", "user", ObjectRoute, "id", OperationDeleted, before, nil)
+	require.NoError(t, err)
+	assert.Len(t, version.Changes(), 4, "every field of a deleted object changes")
+
+	resp := version.ToAPIResponse()
+	assert.Equal(t, api.ObjectVersionOperationDeleted, resp.Operation)
+	assert.NotNil(t, resp.Before)
+	assert.Nil(t, resp.After)
+}
+
+func TestVersion_Restore(t *testing.T) {
+	original := &object{Name: "office", Metric: 10, Groups: []string{"a"}, Enabled: true}
+
+	version, err := NewVersion("account", "user", ObjectRoute, "id", OperationCreated, nil, original)
+	require.NoError(t, err)
+
+	restored := &object{}
+	require.NoError(t, version.Restore(restored))
+	assert.Equal(t, original, restored)
+
+	version, err = NewVersion("account", "user", ObjectRoute, "id", OperationDeleted, original, nil)
+	require.NoError(t, err)
+	assert.Error(t, version.Restore(&object{}), "a deletion can't be restored")
+}
@@ -0,0 +1,303 @@
+package peerapproval
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/util"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// Config is the native peer approval configuration of an account.
+// New peers joining one of the required groups stay pending until an administrator approves them,
+// unless they match one of the auto-approval rules.
+type Config struct {
+	AccountID string `gorm:"primaryKey"`
+	// RequiredGroups is a list of group IDs whose new peers have to be approved
+	RequiredGroups []string `gorm:"serializer:json"`
+	// AutoApprovalRules approve new peers that match any of the rules
+	AutoApprovalRules []AutoApprovalRule `gorm:"serializer:json"`
+	// WebhookURL receives a notification for every new pending peer
+	WebhookURL string
+	// WebhookSecret signs the webhook payload if set
+	WebhookSecret string
+	UpdatedAt     time.Time
+}
+
+// AutoApprovalRule matches a peer when all the set conditions match
+type AutoApprovalRule struct {
+	Name string
+	// OperatingSystems limits the peer operating system, matched against the peer GoOS
+	OperatingSystems []string
+	// HostnamePattern is a regular expression the whole peer hostname has to match
+	HostnamePattern string
+	// SerialNumbers is a list of allowed system serial numbers
+	SerialNumbers []string
+	// Manufacturers is a list of allowed system manufacturers
+	Manufacturers []string
+	// SourceRanges limits the public address the peer registers from
+	SourceRanges []netip.Prefix
+}
+
+// TableName returns the name of the table for the Config model in the database.
+func (*Config) TableName() string {
+	return "peer_approval_configs"
+}
+
+// NewConfig returns an empty configuration that doesn't require approval for any peer
+func NewConfig(accountID string) *Config {
+	return &Config{AccountID: accountID}
+}
+
+// Copy returns a deep copy of the configuration
+func (c *Config) Copy() *Config {
+	rules := make([]AutoApprovalRule, 0, len(c.AutoApprovalRules))
+	for _, rule := range c.AutoApprovalRules {
+		rules = append(rules, rule.Copy())
+	}
+	return &Config{
+		AccountID:         c.AccountID,
+		RequiredGroups:    slices.Clone(c.RequiredGroups),
+		AutoApprovalRules: rules,
+		WebhookURL:        c.WebhookURL,
+		WebhookSecret:     c.WebhookSecret,
+		UpdatedAt:         c.UpdatedAt,
+	}
+}
+
+// EventMeta returns activity event meta related to the configuration
+func (c *Config) EventMeta() map[string]any {
+	return map[string]any{
+		"required_groups":     c.RequiredGroups,
+		"auto_approval_rules": len(c.AutoApprovalRules),
+		"webhook":             c.WebhookURL != "",
+	}
+}
+
+// Validate checks that the configuration is well-formed
+func (c *Config) Validate() error {
+	for _, groupID := range c.RequiredGroups {
+		if groupID == "" {
+			return errors.New("required group ID can't be empty")
+		}
+	}
+
+	names := make(map[string]struct{}, len(c.AutoApprovalRules))
+	for _, rule := range c.AutoApprovalRules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("invalid auto-approval rule %s: %w", rule.Name, err)
+		}
+		if _, ok := names[rule.Name]; ok {
+			return fmt.Errorf("duplicate auto-approval rule name %s", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+	}
+
+	if c.WebhookURL != "" {
+		u, err := url.Parse(c.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook URL %s", c.WebhookURL)
+		}
+	}
+
+	return nil
+}
+
+// RequiresApproval returns true if any of the groups of a new peer requires approval
+func (c *Config) RequiresApproval(peerGroups []string) bool {
+	for _, groupID := range c.RequiredGroups {
+		if slices.Contains(peerGroups, groupID) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAutoApprovalRule returns the first auto-approval rule that matches the peer or nil
+func (c *Config) MatchAutoApprovalRule(peer *nbpeer.Peer) *AutoApprovalRule {
+	for i := range c.AutoApprovalRules {
+		if c.AutoApprovalRules[i].Matches(peer) {
+			return &c.AutoApprovalRules[i]
+		}
+	}
+	return nil
+}
+
+// Copy returns a deep copy of the rule
+func (r AutoApprovalRule) Copy() AutoApprovalRule {
+	return AutoApprovalRule{
+		Name:             r.Name,
+		OperatingSystems: slices.Clone(r.OperatingSystems),
+		HostnamePattern:  r.HostnamePattern,
+		SerialNumbers:    slices.Clone(r.SerialNumbers),
+		Manufacturers:    slices.Clone(r.Manufacturers),
+		SourceRanges:     slices.Clone(r.SourceRanges),
+	}
+}
+
+// Validate checks that the rule has a name and at least one well-formed condition
+func (r AutoApprovalRule) Validate() error {
+	if r.Name == "" {
+		return errors.New("name can't be empty")
+	}
+
+	if len(r.OperatingSystems) == 0 && r.HostnamePattern == "" && len(r.SerialNumbers) == 0 &&
+		len(r.Manufacturers) == 0 && len(r.SourceRanges) == 0 {
+		return errors.New("at least one condition is required")
+	}
+
+	if r.HostnamePattern != "" {
+		if _, err := compileHostnamePattern(r.HostnamePattern); err != nil {
+			return fmt.Errorf("invalid hostname pattern: %w", err)
+		}
+	}
+
+	for _, prefix := range r.SourceRanges {
+		if !prefix.IsValid() {
+			return fmt.Errorf("invalid source range %s", prefix)
+		}
+	}
+
+	return nil
+}
+
+// Matches returns true if the peer satisfies all the set conditions of the rule
+func (r AutoApprovalRule) Matches(peer *nbpeer.Peer) bool {
+	if len(r.OperatingSystems) > 0 && !slices.Contains(r.OperatingSystems, strings.ToLower(peer.Meta.GoOS)) {
+		return false
+	}
+
+	if r.HostnamePattern != "" {
+		re, err := compileHostnamePattern(r.HostnamePattern)
+		if err != nil || !re.MatchString(peer.Meta.Hostname) {
+			return false
+		}
+	}
+
+	if len(r.SerialNumbers) > 0 && !slices.Contains(r.SerialNumbers, peer.Meta.SystemSerialNumber) {
+		return false
+	}
+
+	if len(r.Manufacturers) > 0 && !slices.ContainsFunc(r.Manufacturers, func(manufacturer string) bool {
+		return strings.EqualFold(manufacturer, peer.Meta.SystemManufacturer)
+	}) {
+		return false
+	}
+
+	if len(r.SourceRanges) > 0 && !containsAddr(r.SourceRanges, peer.Location.ConnectionIP) {
+		return false
+	}
+
+	return true
+}
+
+func containsAddr(prefixes []netip.Prefix, ip net.IP) bool {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+
+	return slices.ContainsFunc(prefixes, func(prefix netip.Prefix) bool {
+		return prefix.Contains(addr)
+	})
+}
+
+// ToAPIResponse converts the configuration to its API representation. The webhook secret is never returned.
+func (c *Config) ToAPIResponse() *api.PeerApprovalSettings {
+	rules := make([]api.PeerAutoApprovalRule, 0, len(c.AutoApprovalRules))
+	for _, rule := range c.AutoApprovalRules {
+		apiRule := api.PeerAutoApprovalRule{Name: rule.Name}
+		if len(rule.OperatingSystems) > 0 {
+			apiRule.OperatingSystems = util.ToPtr(slices.Clone(rule.OperatingSystems))
+		}
+		if rule.HostnamePattern != "" {
+			apiRule.HostnamePattern = util.ToPtr(rule.HostnamePattern)
+		}
+		if len(rule.SerialNumbers) > 0 {
+			apiRule.SerialNumbers = util.ToPtr(slices.Clone(rule.SerialNumbers))
+		}
+		if len(rule.Manufacturers) > 0 {
+			apiRule.Manufacturers = util.ToPtr(slices.Clone(rule.Manufacturers))
+		}
+		if len(rule.SourceRanges) > 0 {
+			sourceRanges := make([]string, 0, len(rule.SourceRanges))
+			for _, prefix := range rule.SourceRanges {
+				sourceRanges = append(sourceRanges, prefix.String())
+			}
+			apiRule.SourceRanges = &sourceRanges
+		}
+		rules = append(rules, apiRule)
+	}
+
+	requiredGroups := slices.Clone(c.RequiredGroups)
+	if requiredGroups == nil {
+		requiredGroups = []string{}
+	}
+
+	return &api.PeerApprovalSettings{
+		RequiredGroups:    requiredGroups,
+		AutoApprovalRules: rules,
+		WebhookUrl:        c.WebhookURL,
+		WebhookSecretSet:  c.WebhookSecret != "",
+	}
+}
+
+// ConfigFromAPIRequest converts an API request to a configuration. A missing webhook secret is left empty.
+func ConfigFromAPIRequest(req *api.PeerApprovalSettingsRequest) (*Config, error) {
+	config := &Config{
+		RequiredGroups: slices.Clone(req.RequiredGroups),
+	}
+
+	if req.WebhookUrl != nil {
+		config.WebhookURL = *req.WebhookUrl
+	}
+
+	if req.WebhookSecret != nil {
+		config.WebhookSecret = *req.WebhookSecret
+	}
+
+	if req.AutoApprovalRules != nil {
+		for _, apiRule := range *req.AutoApprovalRules {
+			rule := AutoApprovalRule{Name: apiRule.Name}
+			if apiRule.OperatingSystems != nil {
+				rule.OperatingSystems = *apiRule.OperatingSystems
+			}
+			if apiRule.HostnamePattern != nil {
+				rule.HostnamePattern = *apiRule.HostnamePattern
+			}
+			if apiRule.SerialNumbers != nil {
+				rule.SerialNumbers = *apiRule.SerialNumbers
+			}
+			if apiRule.Manufacturers != nil {
+				rule.Manufacturers = *apiRule.Manufacturers
+			}
+			if apiRule.SourceRanges != nil {
+				for _, sourceRange := range *apiRule.SourceRanges {
+					prefix, err := netip.ParsePrefix(sourceRange)
+					if err != nil {
+						return nil, status.Errorf(status.InvalidArgument, "invalid source range %s", sourceRange)
+					}
+					rule.SourceRanges = append(rule.SourceRanges, prefix.Masked())
+				}
+			}
+			config.AutoApprovalRules = append(config.AutoApprovalRules, rule)
+		}
+	}
+
+	return config, nil
+}
+
+// compileHostnamePattern compiles the hostname pattern anchored, so it has to match the whole hostname
+func compileHostnamePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
@@ -0,0 +1,126 @@
+package peerapproval
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+)
+
+func TestAutoApprovalRule_Matches(t *testing.T) {
+	peer := &nbpeer.Peer{
+		Meta: nbpeer.PeerSystemMeta{
+			Hostname:           "corp-laptop1",
+			GoOS:               "darwin",
+			SystemSerialNumber: "C02XL0GZJGH5",
+			SystemManufacturer: "Apple Inc.",
+		},
+		Location: nbpeer.Location{ConnectionIP: net.ParseIP("203.0.113.10")},
+	}
+
+	tests := []struct {
+		name    string
+		rule    AutoApprovalRule
+		matches bool
+	}{
+		{
+			name: "all conditions match",
+			rule: AutoApprovalRule{
+				OperatingSystems: []string{"darwin", "windows"},
+				HostnamePattern:  "^corp-[a-z0-9]+$",
+				SerialNumbers:    []string{"C02XL0GZJGH5"},
+				Manufacturers:    []string{"apple inc."},
+				SourceRanges:     []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")},
+			},
+			matches: true,
+		},
+		{name: "operating system", rule: AutoApprovalRule{OperatingSystems: []string{"linux"}}},
+		{name: "hostname", rule: AutoApprovalRule{HostnamePattern: "^srv-"}},
+		{name: "partial hostname", rule: AutoApprovalRule{HostnamePattern: "laptop[0-9]"}},
+		{name: "whole hostname", rule: AutoApprovalRule{HostnamePattern: "corp-laptop[0-9]"}, matches: true},
+		{name: "serial number", rule: AutoApprovalRule{SerialNumbers: []string{"other"}}},
+		{name: "manufacturer", rule: AutoApprovalRule{Manufacturers: []string{"Lenovo"}}},
+		{name: "source range", rule: AutoApprovalRule{SourceRanges: []netip.Prefix{netip.MustParsePrefix("198.51.100.0/24")}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.matches, tt.rule.Matches(peer))
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{name: "empty", config: Config{}},
+		{name: "rule without conditions", config: Config{AutoApprovalRules: []AutoApprovalRule{{Name: "any"}}}, wantErr: true},
+		{name: "rule without name", config: Config{AutoApprovalRules: []AutoApprovalRule{{OperatingSystems: []string{"linux"}}}}, wantErr: true},
+		{name: "invalid hostname pattern", config: Config{AutoApprovalRules: []AutoApprovalRule{{Name: "r", HostnamePattern: "("}}}, wantErr: true},
+		{
+			name: "duplicate rule names",
+			config: Config{AutoApprovalRules: []AutoApprovalRule{
+				{Name: "r", OperatingSystems: []string{"linux"}},
+				{Name: "r", OperatingSystems: []string{"darwin"}},
+			}},
+			wantErr: true,
+		},
+		{name: "invalid webhook URL", config: Config{WebhookURL: "hooks.example.com"}, wantErr: true},
+		{name: "valid webhook URL", config: Config{WebhookURL: "https://hooks.example.com/netbird"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWebhookNotifier_NotifyPendingPeer(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer server.Close()
+
+	config := &Config{AccountID: "account", WebhookURL: server.URL, WebhookSecret: "secret"}
+	peer := &nbpeer.Peer{ID: "peer", Name: "laptop", Meta: nbpeer.PeerSystemMeta{Hostname: "laptop", SystemSerialNumber: "C02XL0GZJGH5"}}
+
+	NewWebhookNotifier().NotifyPendingPeer(context.Background(), config, peer)
+
+	var req *http.Request
+	select {
+	case req = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook wasn't called")
+	}
+	body := <-bodies
+
+	assert.Equal(t, Sign("secret", body), req.Header.Get(SignatureHeader))
+
+	var payload WebhookPayload
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, PendingPeerEvent, payload.Event)
+	assert.Equal(t, "account", payload.AccountID)
+	assert.Equal(t, "peer", payload.Peer.ID)
+	assert.Equal(t, "C02XL0GZJGH5", payload.Peer.SerialNumber)
+}
@@ -0,0 +1,15 @@
+package peerapproval
+
+import (
+	"context"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+)
+
+type Manager interface {
+	GetConfig(ctx context.Context, accountID, userID string) (*Config, error)
+	UpdateConfig(ctx context.Context, accountID, userID string, config *Config) (*Config, error)
+	ListPendingPeers(ctx context.Context, accountID, userID string) ([]*nbpeer.Peer, error)
+	ApprovePeer(ctx context.Context, accountID, userID, peerID string) error
+	RejectPeer(ctx context.Context, accountID, userID, peerID string) error
+}
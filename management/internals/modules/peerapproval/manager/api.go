@@ -0,0 +1,174 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/http/util"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type handler struct {
+	manager peerapproval.Manager
+}
+
+func RegisterEndpoints(router *mux.Router, manager peerapproval.Manager) {
+	h := &handler{
+		manager: manager,
+	}
+
+	router.HandleFunc("/peer-approvals", h.listPendingPeers).Methods("GET", "OPTIONS")
+	router.HandleFunc("/peer-approvals/settings", h.getSettings).Methods("GET", "OPTIONS")
+	router.HandleFunc("/peer-approvals/settings", h.updateSettings).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/peer-approvals/{peerId}/approve", h.approvePeer).Methods("POST", "OPTIONS")
+	router.HandleFunc("/peer-approvals/{peerId}/reject", h.rejectPeer).Methods("POST", "OPTIONS")
+}
+
+func (h *handler) listPendingPeers(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	peers, err := h.manager.ListPendingPeers(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	apiPeers := make([]*api.PendingPeer, 0, len(peers))
+	for _, peer := range peers {
+		apiPeers = append(apiPeers, toPendingPeerResponse(peer))
+	}
+
+	util.WriteJSONObject(r.Context(), w, apiPeers)
+}
+
+func (h *handler) getSettings(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	config, err := h.manager.GetConfig(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, config.ToAPIResponse())
+}
+
+func (h *handler) updateSettings(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var req api.PutApiPeerApprovalsSettingsJSONRequestBody
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	config, err := peerapproval.ConfigFromAPIRequest(&req)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	// an omitted secret keeps the current one
+	if req.WebhookSecret == nil {
+		current, err := h.manager.GetConfig(r.Context(), userAuth.AccountId, userAuth.UserId)
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+		config.WebhookSecret = current.WebhookSecret
+	}
+
+	updated, err := h.manager.UpdateConfig(r.Context(), userAuth.AccountId, userAuth.UserId, config)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, updated.ToAPIResponse())
+}
+
+func (h *handler) approvePeer(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	peerID := mux.Vars(r)["peerId"]
+	if peerID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "peer ID is required"), w)
+		return
+	}
+
+	if err = h.manager.ApprovePeer(r.Context(), userAuth.AccountId, userAuth.UserId, peerID); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
+
+func (h *handler) rejectPeer(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	peerID := mux.Vars(r)["peerId"]
+	if peerID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "peer ID is required"), w)
+		return
+	}
+
+	if err = h.manager.RejectPeer(r.Context(), userAuth.AccountId, userAuth.UserId, peerID); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
+
+func toPendingPeerResponse(peer *nbpeer.Peer) *api.PendingPeer {
+	var connectionIP string
+	if peer.Location.ConnectionIP != nil {
+		connectionIP = peer.Location.ConnectionIP.String()
+	}
+
+	return &api.PendingPeer{
+		Id:            peer.ID,
+		Name:          peer.Name,
+		Ip:            peer.IP.String(),
+		UserId:        peer.UserID,
+		CreatedAt:     peer.CreatedAt,
+		ConnectionIp:  connectionIP,
+		CountryCode:   peer.Location.CountryCode,
+		CityName:      peer.Location.CityName,
+		Hostname:      peer.Meta.Hostname,
+		Os:            fmt.Sprintf("%s %s", peer.Meta.OS, peer.Meta.OSVersion),
+		KernelVersion: peer.Meta.KernelVersion,
+		Version:       peer.Meta.WtVersion,
+		SerialNumber:  peer.Meta.SystemSerialNumber,
+		ProductName:   peer.Meta.SystemProductName,
+		Manufacturer:  peer.Meta.SystemManufacturer,
+	}
+}
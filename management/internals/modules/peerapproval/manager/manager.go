@@ -0,0 +1,189 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/management/server/activity"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type managerImpl struct {
+	store              store.Store
+	accountManager     account.Manager
+	permissionsManager permissions.Manager
+}
+
+func NewManager(store store.Store, accountManager account.Manager, permissionsManager permissions.Manager) peerapproval.Manager {
+	return &managerImpl{
+		store:              store,
+		accountManager:     accountManager,
+		permissionsManager: permissionsManager,
+	}
+}
+
+func (m *managerImpl) GetConfig(ctx context.Context, accountID, userID string) (*peerapproval.Config, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, modules.Settings, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return getConfig(ctx, m.store, store.LockingStrengthNone, accountID)
+}
+
+func (m *managerImpl) UpdateConfig(ctx context.Context, accountID, userID string, config *peerapproval.Config) (*peerapproval.Config, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, modules.Settings, operations.Update); err != nil {
+		return nil, err
+	}
+
+	config = config.Copy()
+	config.AccountID = accountID
+	config.UpdatedAt = time.Now().UTC()
+	if err := config.Validate(); err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "%s", err.Error())
+	}
+
+	err := m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		if len(config.RequiredGroups) > 0 {
+			groups, err := transaction.GetGroupsByIDs(ctx, store.LockingStrengthNone, accountID, config.RequiredGroups)
+			if err != nil {
+				return fmt.Errorf("failed to get groups: %w", err)
+			}
+			for _, groupID := range config.RequiredGroups {
+				if _, ok := groups[groupID]; !ok {
+					return status.Errorf(status.InvalidArgument, "group %s doesn't exist", groupID)
+				}
+			}
+		}
+
+		if err := transaction.SavePeerApprovalConfig(ctx, config); err != nil {
+			return fmt.Errorf("failed to save peer approval config: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, accountID, accountID, activity.PeerApprovalSettingsUpdated, config.EventMeta())
+
+	return config, nil
+}
+
+func (m *managerImpl) ListPendingPeers(ctx context.Context, accountID, userID string) ([]*nbpeer.Peer, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, modules.Peers, operations.Read); err != nil {
+		return nil, err
+	}
+
+	peers, err := m.store.GetAccountPeers(ctx, store.LockingStrengthNone, accountID, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account peers: %w", err)
+	}
+
+	peersInScope, err := m.permissionsManager.GetPeersInScope(ctx, accountID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get peers in token scope: %w", err)
+	}
+
+	pending := make([]*nbpeer.Peer, 0)
+	for _, peer := range peers {
+		if _, ok := peersInScope[peer.ID]; peersInScope != nil && !ok {
+			continue
+		}
+		if peer.Status != nil && peer.Status.RequiresApproval {
+			pending = append(pending, peer)
+		}
+	}
+	return pending, nil
+}
+
+// ApprovePeer clears the approval flag of a pending peer. The account manager records the approval and
+// sends the updated network maps.
+func (m *managerImpl) ApprovePeer(ctx context.Context, accountID, userID, peerID string) error {
+	peer, err := m.getPendingPeer(ctx, accountID, userID, peerID, operations.Update)
+	if err != nil {
+		return err
+	}
+
+	update := peer.Copy()
+	update.Status.RequiresApproval = false
+
+	_, err = m.accountManager.UpdatePeer(ctx, accountID, userID, update)
+	return err
+}
+
+// RejectPeer removes a pending peer from the account
+func (m *managerImpl) RejectPeer(ctx context.Context, accountID, userID, peerID string) error {
+	peer, err := m.getPendingPeer(ctx, accountID, userID, peerID, operations.Delete)
+	if err != nil {
+		return err
+	}
+
+	if err = m.accountManager.DeletePeer(ctx, accountID, peerID, userID); err != nil {
+		return err
+	}
+
+	meta := map[string]any{
+		"name":     peer.Name,
+		"hostname": peer.Meta.Hostname,
+		"os":       peer.Meta.GoOS,
+		"serial":   peer.Meta.SystemSerialNumber,
+	}
+	m.accountManager.StoreEvent(ctx, userID, peerID, accountID, activity.PeerRejected, meta)
+
+	return nil
+}
+
+func (m *managerImpl) getPendingPeer(ctx context.Context, accountID, userID, peerID string, operation operations.Operation) (*nbpeer.Peer, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, modules.Peers, operation); err != nil {
+		return nil, err
+	}
+
+	peersInScope, err := m.permissionsManager.GetPeersInScope(ctx, accountID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get peers in token scope: %w", err)
+	}
+	if _, ok := peersInScope[peerID]; peersInScope != nil && !ok {
+		return nil, status.NewPeerNotFoundError(peerID)
+	}
+
+	peer, err := m.store.GetPeerByID(ctx, store.LockingStrengthNone, accountID, peerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if peer.Status == nil || !peer.Status.RequiresApproval {
+		return nil, status.Errorf(status.PreconditionFailed, "peer %s is not pending approval", peerID)
+	}
+	return peer, nil
+}
+
+func (m *managerImpl) validatePermissions(ctx context.Context, accountID, userID string, module modules.Module, operation operations.Operation) error {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, module, operation)
+	if err != nil {
+		return status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return status.NewPermissionDeniedError()
+	}
+	return nil
+}
+
+// getConfig returns the stored configuration of the account or an empty one if the account has none
+func getConfig(ctx context.Context, s store.Store, lockStrength store.LockingStrength, accountID string) (*peerapproval.Config, error) {
+	config, err := s.GetPeerApprovalConfig(ctx, lockStrength, accountID)
+	if err != nil {
+		if sErr, ok := status.FromError(err); ok && sErr.Type() == status.NotFound {
+			return peerapproval.NewConfig(accountID), nil
+		}
+		return nil, err
+	}
+	return config, nil
+}
@@ -0,0 +1,386 @@
+package manager
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	testAccountID     = "test-account-id"
+	testUserID        = "test-user-id"
+	testGroupID       = "test-group-id"
+	testPendingPeerID = "pending-peer-id"
+	testActivePeerID  = "active-peer-id"
+)
+
+func setupTest(t *testing.T) (*managerImpl, store.Store, *mock_server.MockAccountManager, *permissions.MockManager, *gomock.Controller, func()) {
+	t.Helper()
+
+	ctx := context.Background()
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+
+	err = testStore.SaveAccount(ctx, &types.Account{
+		Id: testAccountID,
+		Users: map[string]*types.User{
+			testUserID: {Id: testUserID, AccountID: testAccountID, Role: types.UserRoleAdmin},
+		},
+		Groups: map[string]*types.Group{
+			testGroupID: {ID: testGroupID, AccountID: testAccountID, Name: "contractors"},
+		},
+		Peers: map[string]*nbpeer.Peer{
+			testPendingPeerID: {
+				ID:        testPendingPeerID,
+				AccountID: testAccountID,
+				Key:       "pending-key",
+				Name:      "pending",
+				DNSLabel:  "pending",
+				IP:        net.IP{100, 64, 0, 1},
+				Status:    &nbpeer.PeerStatus{RequiresApproval: true},
+				Meta:      nbpeer.PeerSystemMeta{Hostname: "pending", SystemSerialNumber: "C02XJ0J0JGH7"},
+			},
+			testActivePeerID: {
+				ID:        testActivePeerID,
+				AccountID: testAccountID,
+				Key:       "active-key",
+				Name:      "active",
+				DNSLabel:  "active",
+				IP:        net.IP{100, 64, 0, 2},
+				Status:    &nbpeer.PeerStatus{},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	mockAccountManager := &mock_server.MockAccountManager{}
+	mockPermissionsManager := permissions.NewMockManager(ctrl)
+
+	manager := &managerImpl{
+		store:              testStore,
+		accountManager:     mockAccountManager,
+		permissionsManager: mockPermissionsManager,
+	}
+
+	return manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup
+}
+
+// scopePeers limits the user to the given peers as if the request was authenticated with a personal access token
+// scoped to peer groups, nil peers aren't limited
+func scopePeers(mockPermissionsManager *permissions.MockManager, peerIDs ...string) {
+	var peers map[string]struct{}
+	if peerIDs != nil {
+		peers = make(map[string]struct{}, len(peerIDs))
+		for _, peerID := range peerIDs {
+			peers[peerID] = struct{}{}
+		}
+	}
+	mockPermissionsManager.EXPECT().
+		GetPeersInScope(gomock.Any(), testAccountID, testUserID).
+		Return(peers, nil)
+}
+
+func TestManagerImpl_GetConfig(t *testing.T) {
+	manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+	defer cleanup()
+	defer ctrl.Finish()
+
+	mockPermissionsManager.EXPECT().
+		ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Settings, operations.Read).
+		Return(true, nil)
+
+	config, err := manager.GetConfig(context.Background(), testAccountID, testUserID)
+	require.NoError(t, err)
+	assert.Equal(t, testAccountID, config.AccountID)
+	assert.Empty(t, config.RequiredGroups, "accounts without a configuration shouldn't require approval")
+}
+
+func TestManagerImpl_UpdateConfig(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		var events []activity.ActivityDescriber
+		mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+			events = append(events, activityID)
+		}
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Settings, operations.Update).
+			Return(true, nil)
+
+		config := &peerapproval.Config{
+			RequiredGroups: []string{testGroupID},
+			AutoApprovalRules: []peerapproval.AutoApprovalRule{
+				{Name: "office", SourceRanges: []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")}},
+			},
+			WebhookURL:    "https://hooks.example.com/netbird",
+			WebhookSecret: "secret",
+		}
+		_, err := manager.UpdateConfig(ctx, testAccountID, testUserID, config)
+		require.NoError(t, err)
+		assert.Equal(t, []activity.ActivityDescriber{activity.PeerApprovalSettingsUpdated}, events)
+
+		stored, err := testStore.GetPeerApprovalConfig(ctx, store.LockingStrengthNone, testAccountID)
+		require.NoError(t, err)
+		assert.Equal(t, config.RequiredGroups, stored.RequiredGroups)
+		assert.Equal(t, config.AutoApprovalRules, stored.AutoApprovalRules)
+		assert.Equal(t, "secret", stored.WebhookSecret)
+	})
+
+	t.Run("unknown group", func(t *testing.T) {
+		manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Settings, operations.Update).
+			Return(true, nil)
+
+		_, err := manager.UpdateConfig(ctx, testAccountID, testUserID, &peerapproval.Config{RequiredGroups: []string{"unknown"}})
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.InvalidArgument, s.Type())
+	})
+
+	t.Run("invalid webhook URL", func(t *testing.T) {
+		manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Settings, operations.Update).
+			Return(true, nil)
+
+		_, err := manager.UpdateConfig(ctx, testAccountID, testUserID, &peerapproval.Config{WebhookURL: "ftp://example.com"})
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.InvalidArgument, s.Type())
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Settings, operations.Update).
+			Return(false, nil)
+
+		_, err := manager.UpdateConfig(ctx, testAccountID, testUserID, &peerapproval.Config{})
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.PermissionDenied, s.Type())
+	})
+}
+
+func TestManagerImpl_ListPendingPeers(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Peers, operations.Read).
+			Return(true, nil)
+		scopePeers(mockPermissionsManager)
+
+		peers, err := manager.ListPendingPeers(context.Background(), testAccountID, testUserID)
+		require.NoError(t, err)
+		require.Len(t, peers, 1)
+		assert.Equal(t, testPendingPeerID, peers[0].ID)
+		assert.Equal(t, "C02XJ0J0JGH7", peers[0].Meta.SystemSerialNumber)
+	})
+
+	t.Run("peers outside the token scope", func(t *testing.T) {
+		manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Peers, operations.Read).
+			Return(true, nil)
+		scopePeers(mockPermissionsManager, testActivePeerID)
+
+		peers, err := manager.ListPendingPeers(context.Background(), testAccountID, testUserID)
+		require.NoError(t, err)
+		assert.Empty(t, peers)
+	})
+}
+
+func TestManagerImpl_ApprovePeer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		manager, _, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		var updatedPeers []*nbpeer.Peer
+		mockAccountManager.UpdatePeerFunc = func(_ context.Context, _, _ string, peer *nbpeer.Peer) (*nbpeer.Peer, error) {
+			updatedPeers = append(updatedPeers, peer)
+			return peer, nil
+		}
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Peers, operations.Update).
+			Return(true, nil)
+		scopePeers(mockPermissionsManager)
+
+		err := manager.ApprovePeer(ctx, testAccountID, testUserID, testPendingPeerID)
+		require.NoError(t, err)
+		require.Len(t, updatedPeers, 1)
+		assert.Equal(t, testPendingPeerID, updatedPeers[0].ID)
+		assert.False(t, updatedPeers[0].Status.RequiresApproval)
+	})
+
+	t.Run("peer not pending", func(t *testing.T) {
+		manager, _, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		var updatedPeers []*nbpeer.Peer
+		mockAccountManager.UpdatePeerFunc = func(_ context.Context, _, _ string, peer *nbpeer.Peer) (*nbpeer.Peer, error) {
+			updatedPeers = append(updatedPeers, peer)
+			return peer, nil
+		}
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Peers, operations.Update).
+			Return(true, nil)
+		scopePeers(mockPermissionsManager)
+
+		err := manager.ApprovePeer(ctx, testAccountID, testUserID, testActivePeerID)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.PreconditionFailed, s.Type())
+		assert.Empty(t, updatedPeers)
+	})
+
+	t.Run("peer outside the token scope", func(t *testing.T) {
+		manager, _, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		var updatedPeers []*nbpeer.Peer
+		mockAccountManager.UpdatePeerFunc = func(_ context.Context, _, _ string, peer *nbpeer.Peer) (*nbpeer.Peer, error) {
+			updatedPeers = append(updatedPeers, peer)
+			return peer, nil
+		}
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Peers, operations.Update).
+			Return(true, nil)
+		scopePeers(mockPermissionsManager, testActivePeerID)
+
+		err := manager.ApprovePeer(ctx, testAccountID, testUserID, testPendingPeerID)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.NotFound, s.Type())
+		assert.Empty(t, updatedPeers)
+	})
+}
+
+func TestManagerImpl_RejectPeer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		manager, _, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		var deletedPeers []string
+		mockAccountManager.DeletePeerFunc = func(_ context.Context, _, peerID, _ string) error {
+			deletedPeers = append(deletedPeers, peerID)
+			return nil
+		}
+
+		var events []activity.ActivityDescriber
+		mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+			events = append(events, activityID)
+		}
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Peers, operations.Delete).
+			Return(true, nil)
+		scopePeers(mockPermissionsManager)
+
+		err := manager.RejectPeer(ctx, testAccountID, testUserID, testPendingPeerID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{testPendingPeerID}, deletedPeers)
+		assert.Equal(t, []activity.ActivityDescriber{activity.PeerRejected}, events)
+	})
+
+	t.Run("peer not pending", func(t *testing.T) {
+		manager, _, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		var deletedPeers []string
+		mockAccountManager.DeletePeerFunc = func(_ context.Context, _, peerID, _ string) error {
+			deletedPeers = append(deletedPeers, peerID)
+			return nil
+		}
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Peers, operations.Delete).
+			Return(true, nil)
+		scopePeers(mockPermissionsManager)
+
+		err := manager.RejectPeer(ctx, testAccountID, testUserID, testActivePeerID)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.PreconditionFailed, s.Type())
+		assert.Empty(t, deletedPeers)
+	})
+
+	t.Run("peer outside the token scope", func(t *testing.T) {
+		manager, _, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		var deletedPeers []string
+		mockAccountManager.DeletePeerFunc = func(_ context.Context, _, peerID, _ string) error {
+			deletedPeers = append(deletedPeers, peerID)
+			return nil
+		}
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Peers, operations.Delete).
+			Return(true, nil)
+		scopePeers(mockPermissionsManager, testActivePeerID)
+
+		err := manager.RejectPeer(ctx, testAccountID, testUserID, testPendingPeerID)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.NotFound, s.Type())
+		assert.Empty(t, deletedPeers)
+	})
+}
@@ -0,0 +1,137 @@
+package peerapproval
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+)
+
+const (
+	// PendingPeerEvent is the event type of the webhook sent for a new pending peer
+	PendingPeerEvent = "peer.approval.pending"
+	// SignatureHeader carries the hex encoded HMAC-SHA256 of the payload when a webhook secret is configured
+	SignatureHeader = "X-NetBird-Signature"
+
+	webhookTimeout = 10 * time.Second
+)
+
+// Notifier informs external systems about peers waiting for approval
+type Notifier interface {
+	NotifyPendingPeer(ctx context.Context, config *Config, peer *nbpeer.Peer)
+}
+
+// WebhookPayload is the body of the webhook request
+type WebhookPayload struct {
+	Event     string      `json:"event"`
+	AccountID string      `json:"account_id"`
+	Timestamp time.Time   `json:"timestamp"`
+	Peer      PendingPeer `json:"peer"`
+}
+
+// PendingPeer describes a peer waiting for approval in the webhook payload
+type PendingPeer struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	UserID       string `json:"user_id,omitempty"`
+	Hostname     string `json:"hostname"`
+	OS           string `json:"os"`
+	Version      string `json:"version"`
+	SerialNumber string `json:"serial_number,omitempty"`
+	Manufacturer string `json:"manufacturer,omitempty"`
+	ConnectionIP string `json:"connection_ip,omitempty"`
+}
+
+type webhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhookNotifier returns a Notifier that posts a JSON payload to the webhook URL of the account configuration.
+// Requests are sent in the background, failures are only logged.
+func NewWebhookNotifier() Notifier {
+	return &webhookNotifier{
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (n *webhookNotifier) NotifyPendingPeer(ctx context.Context, config *Config, peer *nbpeer.Peer) {
+	if config == nil || config.WebhookURL == "" {
+		return
+	}
+
+	payload := WebhookPayload{
+		Event:     PendingPeerEvent,
+		AccountID: config.AccountID,
+		Timestamp: time.Now().UTC(),
+		Peer:      newPendingPeer(peer),
+	}
+
+	go func() {
+		if err := n.send(context.WithoutCancel(ctx), config.WebhookURL, config.WebhookSecret, payload); err != nil {
+			log.WithContext(ctx).Warnf("failed to notify pending peer %s: %v", peer.ID, err)
+		}
+	}()
+}
+
+func (n *webhookNotifier) send(ctx context.Context, url, secret string, payload WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the body with the given secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newPendingPeer(peer *nbpeer.Peer) PendingPeer {
+	pending := PendingPeer{
+		ID:           peer.ID,
+		Name:         peer.Name,
+		UserID:       peer.UserID,
+		Hostname:     peer.Meta.Hostname,
+		OS:           fmt.Sprintf("%s %s", peer.Meta.OS, peer.Meta.OSVersion),
+		Version:      peer.Meta.WtVersion,
+		SerialNumber: peer.Meta.SystemSerialNumber,
+		Manufacturer: peer.Meta.SystemManufacturer,
+	}
+	if peer.Location.ConnectionIP != nil {
+		pending.ConnectionIP = peer.Location.ConnectionIP.String()
+	}
+	return pending
+}
@@ -0,0 +1,13 @@
+package portforwards
+
+import (
+	"context"
+)
+
+type Manager interface {
+	GetAllPortForwards(ctx context.Context, accountID, userID string) ([]*PortForward, error)
+	GetPortForward(ctx context.Context, accountID, userID, portForwardID string) (*PortForward, error)
+	CreatePortForward(ctx context.Context, accountID, userID string, forward *PortForward) (*PortForward, error)
+	UpdatePortForward(ctx context.Context, accountID, userID string, forward *PortForward) (*PortForward, error)
+	DeletePortForward(ctx context.Context, accountID, userID, portForwardID string) error
+}
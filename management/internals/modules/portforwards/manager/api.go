@@ -0,0 +1,151 @@
+package manager
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netbirdio/netbird/management/internals/modules/portforwards"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/http/util"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type handler struct {
+	manager portforwards.Manager
+}
+
+func RegisterEndpoints(router *mux.Router, manager portforwards.Manager) {
+	h := &handler{
+		manager: manager,
+	}
+
+	router.HandleFunc("/port-forwards", h.getAllPortForwards).Methods("GET", "OPTIONS")
+	router.HandleFunc("/port-forwards", h.createPortForward).Methods("POST", "OPTIONS")
+	router.HandleFunc("/port-forwards/{portForwardId}", h.getPortForward).Methods("GET", "OPTIONS")
+	router.HandleFunc("/port-forwards/{portForwardId}", h.updatePortForward).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/port-forwards/{portForwardId}", h.deletePortForward).Methods("DELETE", "OPTIONS")
+}
+
+func (h *handler) getAllPortForwards(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	forwards, err := h.manager.GetAllPortForwards(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	apiForwards := make([]*api.PortForward, 0, len(forwards))
+	for _, forward := range forwards {
+		apiForwards = append(apiForwards, forward.ToAPIResponse())
+	}
+
+	util.WriteJSONObject(r.Context(), w, apiForwards)
+}
+
+func (h *handler) createPortForward(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var req api.PostApiPortForwardsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	forward := new(portforwards.PortForward)
+	forward.FromAPIRequest(&req)
+
+	createdForward, err := h.manager.CreatePortForward(r.Context(), userAuth.AccountId, userAuth.UserId, forward)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, createdForward.ToAPIResponse())
+}
+
+func (h *handler) getPortForward(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	portForwardID := mux.Vars(r)["portForwardId"]
+	if portForwardID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "port forward ID is required"), w)
+		return
+	}
+
+	forward, err := h.manager.GetPortForward(r.Context(), userAuth.AccountId, userAuth.UserId, portForwardID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, forward.ToAPIResponse())
+}
+
+func (h *handler) updatePortForward(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	portForwardID := mux.Vars(r)["portForwardId"]
+	if portForwardID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "port forward ID is required"), w)
+		return
+	}
+
+	var req api.PutApiPortForwardsPortForwardIdJSONRequestBody
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	forward := new(portforwards.PortForward)
+	forward.FromAPIRequest(&req)
+	forward.ID = portForwardID
+
+	updatedForward, err := h.manager.UpdatePortForward(r.Context(), userAuth.AccountId, userAuth.UserId, forward)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, updatedForward.ToAPIResponse())
+}
+
+func (h *handler) deletePortForward(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	portForwardID := mux.Vars(r)["portForwardId"]
+	if portForwardID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "port forward ID is required"), w)
+		return
+	}
+
+	if err = h.manager.DeletePortForward(r.Context(), userAuth.AccountId, userAuth.UserId, portForwardID); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
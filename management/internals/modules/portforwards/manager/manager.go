@@ -0,0 +1,243 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/rs/xid"
+
+	"github.com/netbirdio/netbird/management/internals/modules/portforwards"
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/management/server/activity"
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type managerImpl struct {
+	store              store.Store
+	accountManager     account.Manager
+	permissionsManager permissions.Manager
+}
+
+func NewManager(store store.Store, accountManager account.Manager, permissionsManager permissions.Manager) portforwards.Manager {
+	return &managerImpl{
+		store:              store,
+		accountManager:     accountManager,
+		permissionsManager: permissionsManager,
+	}
+}
+
+func (m *managerImpl) GetAllPortForwards(ctx context.Context, accountID, userID string) ([]*portforwards.PortForward, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetAccountPortForwards(ctx, store.LockingStrengthNone, accountID)
+}
+
+func (m *managerImpl) GetPortForward(ctx context.Context, accountID, userID, portForwardID string) (*portforwards.PortForward, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetPortForwardByID(ctx, store.LockingStrengthNone, accountID, portForwardID)
+}
+
+func (m *managerImpl) CreatePortForward(ctx context.Context, accountID, userID string, forward *portforwards.PortForward) (*portforwards.PortForward, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Create); err != nil {
+		return nil, err
+	}
+
+	newForward := forward.Copy()
+	newForward.ID = xid.New().String()
+	newForward.AccountID = accountID
+	if err := newForward.Validate(); err != nil {
+		return nil, err
+	}
+
+	err := m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		if err := validateReferences(ctx, transaction, newForward); err != nil {
+			return err
+		}
+
+		if err := transaction.SavePortForward(ctx, newForward); err != nil {
+			return fmt.Errorf("failed to create port forward: %w", err)
+		}
+
+		return transaction.IncrementNetworkSerial(ctx, accountID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, newForward.ID, accountID, activity.PortForwardCreated, newForward.EventMeta())
+
+	if newForward.Enabled {
+		m.accountManager.UpdateAccountPeers(ctx, accountID)
+	}
+
+	return newForward, nil
+}
+
+func (m *managerImpl) UpdatePortForward(ctx context.Context, accountID, userID string, forward *portforwards.PortForward) (*portforwards.PortForward, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Update); err != nil {
+		return nil, err
+	}
+
+	updatedForward := forward.Copy()
+	updatedForward.AccountID = accountID
+	if err := updatedForward.Validate(); err != nil {
+		return nil, err
+	}
+
+	var wasEnabled bool
+	err := m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		existing, err := transaction.GetPortForwardByID(ctx, store.LockingStrengthUpdate, accountID, updatedForward.ID)
+		if err != nil {
+			return err
+		}
+		wasEnabled = existing.Enabled
+
+		if err = validateReferences(ctx, transaction, updatedForward); err != nil {
+			return err
+		}
+
+		if err = transaction.SavePortForward(ctx, updatedForward); err != nil {
+			return fmt.Errorf("failed to update port forward: %w", err)
+		}
+
+		return transaction.IncrementNetworkSerial(ctx, accountID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, updatedForward.ID, accountID, activity.PortForwardUpdated, updatedForward.EventMeta())
+
+	if wasEnabled || updatedForward.Enabled {
+		m.accountManager.UpdateAccountPeers(ctx, accountID)
+	}
+
+	return updatedForward, nil
+}
+
+func (m *managerImpl) DeletePortForward(ctx context.Context, accountID, userID, portForwardID string) error {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Delete); err != nil {
+		return err
+	}
+
+	var forward *portforwards.PortForward
+	err := m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		var err error
+		forward, err = transaction.GetPortForwardByID(ctx, store.LockingStrengthUpdate, accountID, portForwardID)
+		if err != nil {
+			return err
+		}
+
+		if err = transaction.DeletePortForward(ctx, accountID, portForwardID); err != nil {
+			return fmt.Errorf("failed to delete port forward: %w", err)
+		}
+
+		return transaction.IncrementNetworkSerial(ctx, accountID)
+	})
+	if err != nil {
+		return err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, portForwardID, accountID, activity.PortForwardDeleted, forward.EventMeta())
+
+	if forward.Enabled {
+		m.accountManager.UpdateAccountPeers(ctx, accountID)
+	}
+
+	return nil
+}
+
+// validateReferences checks that the ingress peer and the target exist. A target resource has to be a host and the
+// ingress peer one of the routing peers of its network, as the resource is reached on the local network of the peer.
+func validateReferences(ctx context.Context, transaction store.Store, forward *portforwards.PortForward) error {
+	ingressPeer, err := transaction.GetPeerByID(ctx, store.LockingStrengthNone, forward.AccountID, forward.PeerID)
+	if err != nil {
+		return status.Errorf(status.InvalidArgument, "ingress peer %s not found", forward.PeerID)
+	}
+	if !supportsPortForwards(ingressPeer) {
+		return status.Errorf(status.InvalidArgument, "ingress peer %s can't publish ports, port forwards require a Linux peer with firewall management enabled", ingressPeer.Name)
+	}
+
+	if forward.TargetPeerID != "" {
+		if forward.TargetPeerID == forward.PeerID {
+			return status.Errorf(status.InvalidArgument, "target peer must be different from the ingress peer")
+		}
+		if _, err := transaction.GetPeerByID(ctx, store.LockingStrengthNone, forward.AccountID, forward.TargetPeerID); err != nil {
+			return status.Errorf(status.InvalidArgument, "target peer %s not found", forward.TargetPeerID)
+		}
+		return nil
+	}
+
+	resource, err := transaction.GetNetworkResourceByID(ctx, store.LockingStrengthNone, forward.AccountID, forward.TargetResourceID)
+	if err != nil {
+		return status.Errorf(status.InvalidArgument, "target resource %s not found", forward.TargetResourceID)
+	}
+	if resource.Type != resourceTypes.Host {
+		return status.Errorf(status.InvalidArgument, "target resource %s must be a host, got %s", resource.Name, resource.Type)
+	}
+
+	isRouter, err := isRoutingPeer(ctx, transaction, forward.AccountID, resource.NetworkID, forward.PeerID)
+	if err != nil {
+		return err
+	}
+	if !isRouter {
+		return status.Errorf(status.InvalidArgument, "ingress peer %s is not a routing peer of the network of resource %s", forward.PeerID, resource.Name)
+	}
+	return nil
+}
+
+// supportsPortForwards reports whether the peer can DNAT traffic arriving on its public or LAN addresses. The
+// userspace firewall only sees the traffic of the WireGuard interface, so the rules need the native firewall of
+// Linux peers.
+func supportsPortForwards(peer *nbpeer.Peer) bool {
+	return peer.Meta.GoOS == "linux" && !peer.Meta.Flags.DisableFirewall
+}
+
+func isRoutingPeer(ctx context.Context, transaction store.Store, accountID, networkID, peerID string) (bool, error) {
+	routers, err := transaction.GetNetworkRoutersByNetID(ctx, store.LockingStrengthNone, accountID, networkID)
+	if err != nil {
+		return false, err
+	}
+
+	var groupIDs []string
+	for _, router := range routers {
+		if router.Peer == peerID {
+			return true, nil
+		}
+		groupIDs = append(groupIDs, router.PeerGroups...)
+	}
+
+	groups, err := transaction.GetGroupsByIDs(ctx, store.LockingStrengthNone, accountID, groupIDs)
+	if err != nil {
+		return false, err
+	}
+	for _, group := range groups {
+		if slices.Contains(group.Peers, peerID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *managerImpl) validatePermissions(ctx context.Context, accountID, userID string, operation operations.Operation) error {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Networks, operation)
+	if err != nil {
+		return status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return status.NewPermissionDeniedError()
+	}
+	return nil
+}
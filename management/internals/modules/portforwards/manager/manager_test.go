@@ -0,0 +1,217 @@
+package manager
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/portforwards"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
+	networkTypes "github.com/netbirdio/netbird/management/server/networks/types"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	testAccountID = "account-id"
+	adminID       = "admin-id"
+	regularUserID = "user-id"
+	networkID     = "network-id"
+	routerPeerID  = "router-peer-id"
+	targetPeerID  = "target-peer-id"
+	macPeerID     = "mac-peer-id"
+	hostID        = "host-id"
+	subnetID      = "subnet-id"
+)
+
+func setupTest(t *testing.T) (*managerImpl, store.Store, *mock_server.MockAccountManager, func()) {
+	t.Helper()
+
+	ctx := context.Background()
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+
+	err = testStore.SaveAccount(ctx, &types.Account{
+		Id:      testAccountID,
+		Network: types.NewNetwork(),
+		Users: map[string]*types.User{
+			adminID:       {Id: adminID, AccountID: testAccountID, Role: types.UserRoleAdmin},
+			regularUserID: {Id: regularUserID, AccountID: testAccountID, Role: types.UserRoleUser},
+		},
+		Peers: map[string]*nbpeer.Peer{
+			routerPeerID: {ID: routerPeerID, AccountID: testAccountID, Key: "router-key", IP: net.IP{100, 64, 0, 1}, DNSLabel: "router", Status: &nbpeer.PeerStatus{}, Meta: nbpeer.PeerSystemMeta{GoOS: "linux"}},
+			targetPeerID: {ID: targetPeerID, AccountID: testAccountID, Key: "target-key", IP: net.IP{100, 64, 0, 2}, DNSLabel: "target", Status: &nbpeer.PeerStatus{}, Meta: nbpeer.PeerSystemMeta{GoOS: "linux"}},
+			macPeerID:    {ID: macPeerID, AccountID: testAccountID, Key: "mac-key", IP: net.IP{100, 64, 0, 3}, DNSLabel: "mac", Status: &nbpeer.PeerStatus{}, Meta: nbpeer.PeerSystemMeta{GoOS: "darwin"}},
+		},
+		Groups: map[string]*types.Group{
+			"routers": {ID: "routers", AccountID: testAccountID, Name: "Routers", Peers: []string{routerPeerID}},
+		},
+		Networks: []*networkTypes.Network{
+			{ID: networkID, AccountID: testAccountID, Name: "office"},
+		},
+		NetworkRouters: []*routerTypes.NetworkRouter{
+			{ID: "router-id", NetworkID: networkID, AccountID: testAccountID, PeerGroups: []string{"routers"}, Enabled: true},
+		},
+		NetworkResources: []*resourceTypes.NetworkResource{
+			{ID: hostID, NetworkID: networkID, AccountID: testAccountID, Name: "nas", Type: resourceTypes.Host, Address: "192.168.1.20", Prefix: netip.MustParsePrefix("192.168.1.20/32"), Enabled: true},
+			{ID: subnetID, NetworkID: networkID, AccountID: testAccountID, Name: "lan", Type: resourceTypes.Subnet, Address: "192.168.1.0/24", Prefix: netip.MustParsePrefix("192.168.1.0/24"), Enabled: true},
+		},
+		Settings:    &types.Settings{},
+		DNSSettings: types.DNSSettings{DisabledManagementGroups: []string{}},
+	})
+	require.NoError(t, err)
+
+	mockAccountManager := &mock_server.MockAccountManager{}
+
+	manager := &managerImpl{
+		store:              testStore,
+		accountManager:     mockAccountManager,
+		permissionsManager: permissions.NewManager(testStore),
+	}
+
+	return manager, testStore, mockAccountManager, cleanup
+}
+
+func newPortForward() *portforwards.PortForward {
+	return &portforwards.PortForward{
+		Name:         "web",
+		Enabled:      true,
+		PeerID:       routerPeerID,
+		ListenPort:   8443,
+		Protocol:     portforwards.ProtocolTCP,
+		TargetPeerID: targetPeerID,
+		TargetPort:   443,
+		SourceRanges: []netip.Prefix{netip.MustParsePrefix("198.51.100.0/24")},
+	}
+}
+
+func TestManagerImpl_PortForwardLifecycle(t *testing.T) {
+	ctx := context.Background()
+	manager, _, mockAccountManager, cleanup := setupTest(t)
+	defer cleanup()
+
+	var events []activity.ActivityDescriber
+	mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+		events = append(events, activityID)
+	}
+
+	_, err := manager.CreatePortForward(ctx, testAccountID, regularUserID, newPortForward())
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+
+	created, err := manager.CreatePortForward(ctx, testAccountID, adminID, newPortForward())
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+	assert.Contains(t, events, activity.PortForwardCreated)
+
+	stored, err := manager.GetPortForward(ctx, testAccountID, adminID, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.SourceRanges, stored.SourceRanges)
+
+	update := created.Copy()
+	update.TargetPeerID = ""
+	update.TargetResourceID = hostID
+	update.ListenAddress = netip.MustParseAddr("203.0.113.10")
+	updated, err := manager.UpdatePortForward(ctx, testAccountID, adminID, update)
+	require.NoError(t, err)
+	assert.Equal(t, hostID, updated.TargetResourceID)
+	assert.Contains(t, events, activity.PortForwardUpdated)
+
+	stored, err = manager.GetPortForward(ctx, testAccountID, adminID, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParseAddr("203.0.113.10"), stored.ListenAddress)
+
+	require.NoError(t, manager.DeletePortForward(ctx, testAccountID, adminID, created.ID))
+	assert.Contains(t, events, activity.PortForwardDeleted)
+
+	_, err = manager.GetPortForward(ctx, testAccountID, adminID, created.ID)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.NotFound, s.Type())
+
+	forwards, err := manager.GetAllPortForwards(ctx, testAccountID, adminID)
+	require.NoError(t, err)
+	assert.Empty(t, forwards)
+}
+
+func TestManagerImpl_CreatePortForwardValidation(t *testing.T) {
+	ctx := context.Background()
+	manager, _, _, cleanup := setupTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name   string
+		modify func(f *portforwards.PortForward)
+	}{
+		{
+			name:   "missing source ranges",
+			modify: func(f *portforwards.PortForward) { f.SourceRanges = nil },
+		},
+		{
+			name: "target peer and resource",
+			modify: func(f *portforwards.PortForward) {
+				f.TargetResourceID = hostID
+			},
+		},
+		{
+			name:   "target peer is the ingress peer",
+			modify: func(f *portforwards.PortForward) { f.TargetPeerID = routerPeerID },
+		},
+		{
+			name:   "unknown ingress peer",
+			modify: func(f *portforwards.PortForward) { f.PeerID = "unknown" },
+		},
+		{
+			name: "subnet resource",
+			modify: func(f *portforwards.PortForward) {
+				f.TargetPeerID = ""
+				f.TargetResourceID = subnetID
+			},
+		},
+		{
+			name: "ingress peer is not a routing peer of the resource",
+			modify: func(f *portforwards.PortForward) {
+				f.PeerID = targetPeerID
+				f.TargetPeerID = ""
+				f.TargetResourceID = hostID
+			},
+		},
+		{
+			name:   "ingress peer without a native firewall",
+			modify: func(f *portforwards.PortForward) { f.PeerID = macPeerID },
+		},
+		{
+			name:   "invalid protocol",
+			modify: func(f *portforwards.PortForward) { f.Protocol = "icmp" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forward := newPortForward()
+			tt.modify(forward)
+			_, err := manager.CreatePortForward(ctx, testAccountID, adminID, forward)
+			require.Error(t, err)
+			s, ok := status.FromError(err)
+			assert.True(t, ok)
+			assert.Equal(t, status.InvalidArgument, s.Type())
+		})
+	}
+
+	forwards, err := manager.GetAllPortForwards(ctx, testAccountID, adminID)
+	require.NoError(t, err)
+	assert.Empty(t, forwards)
+}
@@ -0,0 +1,113 @@
+package portforwards
+
+import (
+	"net"
+	"strconv"
+
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+// NetworkMaps returns the parts of the network maps the port forwards add per peer. They are merged into the
+// network maps of the peers like the proxy network maps.
+//
+// The ingress peer gets the forwarding rule and, for a peer target, the target peer. A target peer gets the ingress
+// peer and a firewall rule accepting the forwarded traffic, which is masqueraded to the address of the ingress peer.
+// Port forwards with unknown or unvalidated peers and resources are skipped.
+func NetworkMaps(account *types.Account, forwards []*PortForward, validatedPeers map[string]struct{}) map[string]*types.NetworkMap {
+	networkMaps := make(map[string]*types.NetworkMap)
+	getNetworkMap := func(peerID string) *types.NetworkMap {
+		nm, ok := networkMaps[peerID]
+		if !ok {
+			nm = &types.NetworkMap{}
+			networkMaps[peerID] = nm
+		}
+		return nm
+	}
+
+	var routers map[string]map[string]*routerTypes.NetworkRouter
+	for _, forward := range forwards {
+		if !forward.Enabled {
+			continue
+		}
+
+		ingress := validatedPeer(account, forward.PeerID, validatedPeers)
+		if ingress == nil {
+			continue
+		}
+
+		var translatedAddress net.IP
+		if forward.TargetPeerID != "" {
+			target := validatedPeer(account, forward.TargetPeerID, validatedPeers)
+			if target == nil || target.ID == ingress.ID {
+				continue
+			}
+			translatedAddress = target.IP
+
+			ingressMap := getNetworkMap(ingress.ID)
+			ingressMap.Peers = append(ingressMap.Peers, target)
+			ingressMap.FirewallRules = append(ingressMap.FirewallRules, forward.firewallRule(target.IP, types.FirewallRuleDirectionOUT))
+
+			targetMap := getNetworkMap(target.ID)
+			targetMap.Peers = append(targetMap.Peers, ingress)
+			targetMap.FirewallRules = append(targetMap.FirewallRules, forward.firewallRule(ingress.IP, types.FirewallRuleDirectionIN))
+		} else {
+			if routers == nil {
+				routers = account.GetResourceRoutersMap()
+			}
+			resource := findResource(account, forward.TargetResourceID)
+			if resource == nil || !resource.Enabled || resource.Type != resourceTypes.Host {
+				continue
+			}
+			if _, ok := routers[resource.NetworkID][ingress.ID]; !ok {
+				continue
+			}
+			translatedAddress = resource.Prefix.Addr().AsSlice()
+		}
+
+		rule := &types.ForwardingRule{
+			RuleProtocol:      string(forward.Protocol),
+			DestinationPorts:  types.RulePortRange{Start: forward.ListenPort, End: forward.ListenPort},
+			SourceRanges:      forward.SourceRanges,
+			TranslatedAddress: translatedAddress,
+			TranslatedPorts:   types.RulePortRange{Start: forward.TargetPort, End: forward.TargetPort},
+		}
+		if forward.ListenAddress.IsValid() {
+			rule.DestinationAddress = forward.ListenAddress.AsSlice()
+		}
+
+		ingressMap := getNetworkMap(ingress.ID)
+		ingressMap.ForwardingRules = append(ingressMap.ForwardingRules, rule)
+	}
+
+	return networkMaps
+}
+
+func (f *PortForward) firewallRule(peerIP net.IP, direction int) *types.FirewallRule {
+	return &types.FirewallRule{
+		PolicyID:  f.ID,
+		PeerIP:    peerIP.String(),
+		Direction: direction,
+		Action:    string(types.PolicyTrafficActionAccept),
+		Protocol:  string(f.Protocol),
+		Port:      strconv.Itoa(int(f.TargetPort)),
+	}
+}
+
+func validatedPeer(account *types.Account, peerID string, validatedPeers map[string]struct{}) *nbpeer.Peer {
+	if _, ok := validatedPeers[peerID]; !ok {
+		return nil
+	}
+	return account.GetPeer(peerID)
+}
+
+func findResource(account *types.Account, resourceID string) *resourceTypes.NetworkResource {
+	for _, resource := range account.NetworkResources {
+		if resource.ID == resourceID {
+			return resource
+		}
+	}
+	return nil
+}
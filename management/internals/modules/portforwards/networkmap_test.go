@@ -0,0 +1,68 @@
+package portforwards
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+func TestNetworkMaps(t *testing.T) {
+	account := &types.Account{
+		Peers: map[string]*nbpeer.Peer{
+			"router": {ID: "router", IP: net.IP{100, 64, 0, 1}},
+			"target": {ID: "target", IP: net.IP{100, 64, 0, 2}},
+		},
+		NetworkRouters: []*routerTypes.NetworkRouter{
+			{ID: "router-id", NetworkID: "net", Peer: "router", Enabled: true},
+		},
+		NetworkResources: []*resourceTypes.NetworkResource{
+			{ID: "nas", NetworkID: "net", Type: resourceTypes.Host, Prefix: netip.MustParsePrefix("192.168.1.20/32"), Enabled: true},
+		},
+	}
+	validated := map[string]struct{}{"router": {}, "target": {}}
+	sources := []netip.Prefix{netip.MustParsePrefix("198.51.100.0/24")}
+
+	forwards := []*PortForward{
+		{ID: "to-peer", Enabled: true, PeerID: "router", ListenAddress: netip.MustParseAddr("203.0.113.10"), ListenPort: 8443, Protocol: ProtocolTCP, TargetPeerID: "target", TargetPort: 443, SourceRanges: sources},
+		{ID: "to-resource", Enabled: true, PeerID: "router", ListenPort: 2222, Protocol: ProtocolTCP, TargetResourceID: "nas", TargetPort: 22, SourceRanges: sources},
+		{ID: "disabled", Enabled: false, PeerID: "router", ListenPort: 80, Protocol: ProtocolTCP, TargetPeerID: "target", TargetPort: 80, SourceRanges: sources},
+		{ID: "not-a-router", Enabled: true, PeerID: "target", ListenPort: 2223, Protocol: ProtocolTCP, TargetResourceID: "nas", TargetPort: 22, SourceRanges: sources},
+	}
+
+	networkMaps := NetworkMaps(account, forwards, validated)
+
+	ingress := networkMaps["router"]
+	require.NotNil(t, ingress)
+	require.Len(t, ingress.ForwardingRules, 2)
+	assert.Equal(t, net.IP{100, 64, 0, 2}, ingress.ForwardingRules[0].TranslatedAddress)
+	assert.Equal(t, net.IP{203, 0, 113, 10}, ingress.ForwardingRules[0].DestinationAddress)
+	assert.Equal(t, sources, ingress.ForwardingRules[0].SourceRanges)
+	assert.Equal(t, net.IP{192, 168, 1, 20}, ingress.ForwardingRules[1].TranslatedAddress)
+	assert.Nil(t, ingress.ForwardingRules[1].DestinationAddress)
+	require.Len(t, ingress.Peers, 1)
+	assert.Equal(t, "target", ingress.Peers[0].ID)
+	require.Len(t, ingress.FirewallRules, 1)
+	assert.Equal(t, types.FirewallRuleDirectionOUT, ingress.FirewallRules[0].Direction)
+
+	target := networkMaps["target"]
+	require.NotNil(t, target)
+	assert.Empty(t, target.ForwardingRules)
+	require.Len(t, target.Peers, 1)
+	assert.Equal(t, "router", target.Peers[0].ID)
+	require.Len(t, target.FirewallRules, 1)
+	assert.Equal(t, types.FirewallRuleDirectionIN, target.FirewallRules[0].Direction)
+	assert.Equal(t, "100.64.0.1", target.FirewallRules[0].PeerIP)
+	assert.Equal(t, "443", target.FirewallRules[0].Port)
+
+	delete(validated, "target")
+	networkMaps = NetworkMaps(account, forwards[:1], validated)
+	assert.Empty(t, networkMaps, "forwards to unvalidated peers should be skipped")
+}
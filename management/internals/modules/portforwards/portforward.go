@@ -0,0 +1,182 @@
+package portforwards
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// Protocol is the transport protocol of a port forward
+type Protocol string
+
+const (
+	ProtocolTCP Protocol = "tcp"
+	ProtocolUDP Protocol = "udp"
+)
+
+// PortForward publishes a port on an address of an ingress peer and DNATs the traffic to a peer or a network
+// resource. Only the source ranges are allowed to connect.
+type PortForward struct {
+	ID          string `gorm:"primaryKey"`
+	AccountID   string `gorm:"index"`
+	Name        string
+	Description string
+	Enabled     bool
+	// PeerID is the ingress peer that publishes the port
+	PeerID string
+	// ListenAddress is the public or LAN address of the ingress peer the port is published on, all addresses if invalid
+	ListenAddress netip.Addr `gorm:"serializer:json"`
+	ListenPort    uint16
+	Protocol      Protocol
+	// TargetPeerID is the peer the traffic is forwarded to, mutually exclusive with TargetResourceID
+	TargetPeerID string
+	// TargetResourceID is the host network resource the traffic is forwarded to. The ingress peer has to be a
+	// routing peer of the network of the resource.
+	TargetResourceID string
+	TargetPort       uint16
+	SourceRanges     []netip.Prefix `gorm:"serializer:json"`
+}
+
+// TableName returns the name of the table for the PortForward model in the database.
+func (*PortForward) TableName() string {
+	return "port_forwards"
+}
+
+// Validate checks the fields of the port forward that don't depend on other account objects
+func (f *PortForward) Validate() error {
+	if f.Name == "" {
+		return status.Errorf(status.InvalidArgument, "port forward name shouldn't be empty")
+	}
+	if f.PeerID == "" {
+		return status.Errorf(status.InvalidArgument, "port forward requires an ingress peer")
+	}
+	if f.Protocol != ProtocolTCP && f.Protocol != ProtocolUDP {
+		return status.Errorf(status.InvalidArgument, "invalid port forward protocol %q, must be %q or %q", f.Protocol, ProtocolTCP, ProtocolUDP)
+	}
+	if f.ListenPort == 0 || f.TargetPort == 0 {
+		return status.Errorf(status.InvalidArgument, "port forward ports must be between 1 and 65535")
+	}
+	if f.ListenAddress.IsValid() && !f.ListenAddress.Is4() {
+		return status.Errorf(status.InvalidArgument, "port forward listen address must be an IPv4 address")
+	}
+	if (f.TargetPeerID == "") == (f.TargetResourceID == "") {
+		return status.Errorf(status.InvalidArgument, "port forward requires either a target peer or a target resource")
+	}
+	if err := validateSourceRanges(f.SourceRanges); err != nil {
+		return status.Errorf(status.InvalidArgument, "invalid port forward source ranges: %v", err)
+	}
+	return nil
+}
+
+func validateSourceRanges(ranges []netip.Prefix) error {
+	if len(ranges) == 0 {
+		return errors.New("at least one source range is required, use 0.0.0.0/0 to allow all sources")
+	}
+	for _, prefix := range ranges {
+		if !prefix.IsValid() || !prefix.Addr().Is4() {
+			return fmt.Errorf("%s is not an IPv4 prefix", prefix)
+		}
+	}
+	return nil
+}
+
+// FromAPIRequest sets the fields of the port forward from the API request. Unparsable addresses are left invalid
+// and rejected by Validate.
+func (f *PortForward) FromAPIRequest(req *api.PortForwardRequest) {
+	f.Name = req.Name
+	f.Description = ""
+	if req.Description != nil {
+		f.Description = *req.Description
+	}
+	f.Enabled = req.Enabled
+	f.PeerID = req.PeerId
+	f.ListenAddress = netip.Addr{}
+	if req.ListenAddress != nil && *req.ListenAddress != "" {
+		f.ListenAddress, _ = netip.ParseAddr(*req.ListenAddress)
+		if !f.ListenAddress.IsValid() {
+			// keep the address invalid but distinguishable from an unset one
+			f.ListenAddress = netip.IPv6Unspecified()
+		}
+	}
+	f.ListenPort = uint16(req.ListenPort)
+	f.Protocol = Protocol(req.Protocol)
+	f.TargetPeerID = ""
+	if req.TargetPeerId != nil {
+		f.TargetPeerID = *req.TargetPeerId
+	}
+	f.TargetResourceID = ""
+	if req.TargetResourceId != nil {
+		f.TargetResourceID = *req.TargetResourceId
+	}
+	f.TargetPort = uint16(req.TargetPort)
+
+	f.SourceRanges = make([]netip.Prefix, 0, len(req.SourceRanges))
+	for _, r := range req.SourceRanges {
+		prefix, _ := netip.ParsePrefix(r)
+		f.SourceRanges = append(f.SourceRanges, prefix.Masked())
+	}
+}
+
+// ToAPIResponse converts the port forward to its API representation
+func (f *PortForward) ToAPIResponse() *api.PortForward {
+	sourceRanges := make([]string, 0, len(f.SourceRanges))
+	for _, prefix := range f.SourceRanges {
+		sourceRanges = append(sourceRanges, prefix.String())
+	}
+
+	resp := &api.PortForward{
+		Id:           f.ID,
+		Name:         f.Name,
+		Description:  &f.Description,
+		Enabled:      f.Enabled,
+		PeerId:       f.PeerID,
+		ListenPort:   int(f.ListenPort),
+		Protocol:     api.PortForwardProtocol(f.Protocol),
+		TargetPort:   int(f.TargetPort),
+		SourceRanges: sourceRanges,
+	}
+	if f.ListenAddress.IsValid() {
+		listenAddress := f.ListenAddress.String()
+		resp.ListenAddress = &listenAddress
+	}
+	if f.TargetPeerID != "" {
+		resp.TargetPeerId = &f.TargetPeerID
+	}
+	if f.TargetResourceID != "" {
+		resp.TargetResourceId = &f.TargetResourceID
+	}
+	return resp
+}
+
+// Copy returns a deep copy of the port forward
+func (f *PortForward) Copy() *PortForward {
+	c := *f
+	c.SourceRanges = slices.Clone(f.SourceRanges)
+	return &c
+}
+
+// EventMeta returns activity event meta related to the port forward
+func (f *PortForward) EventMeta() map[string]any {
+	meta := map[string]any{
+		"name":          f.Name,
+		"peer_id":       f.PeerID,
+		"protocol":      f.Protocol,
+		"listen_port":   f.ListenPort,
+		"target_port":   f.TargetPort,
+		"source_ranges": f.SourceRanges,
+	}
+	if f.ListenAddress.IsValid() {
+		meta["listen_address"] = f.ListenAddress.String()
+	}
+	if f.TargetPeerID != "" {
+		meta["target_peer_id"] = f.TargetPeerID
+	}
+	if f.TargetResourceID != "" {
+		meta["target_resource_id"] = f.TargetResourceID
+	}
+	return meta
+}
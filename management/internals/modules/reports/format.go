@@ -0,0 +1,128 @@
+package reports
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+// Format of a report
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// ParseFormat returns the report format, CSV if empty
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(value)) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unknown report format %q, use %s or %s", value, FormatCSV, FormatJSON)
+	}
+}
+
+// ContentType returns the HTTP content type of the format
+func (f Format) ContentType() string {
+	if f == FormatJSON {
+		return "application/json"
+	}
+	return "text/csv"
+}
+
+var peerColumns = []string{
+	"id", "name", "hostname", "ip", "os", "version", "serial_number", "connected", "last_seen", "last_login", "login_expired",
+	"connection_ip", "country_code", "city_name", "owner_id", "owner", "groups", "approval_state", "posture_checks",
+	"failed_posture_checks", "compliant",
+}
+
+var userColumns = []string{
+	"id", "name", "email", "role", "is_service_user", "blocked", "issued", "created_at", "last_login", "groups", "peers",
+	"tokens", "tokens_last_used",
+}
+
+// WritePeers writes the peer records in the format
+func WritePeers(w io.Writer, format Format, records []*PeerRecord) error {
+	if format == FormatJSON {
+		apiRecords := make([]api.PeerInventoryRecord, 0, len(records))
+		for _, r := range records {
+			apiRecords = append(apiRecords, r.ToAPIResponse())
+		}
+		return writeJSON(w, apiRecords)
+	}
+
+	rows := make([][]string, 0, len(records))
+	for _, r := range records {
+		rows = append(rows, []string{
+			r.ID, r.Name, r.Hostname, r.IP, r.OS, r.Version, r.SerialNumber, strconv.FormatBool(r.Connected),
+			formatTime(&r.LastSeen), formatTime(r.LastLogin), strconv.FormatBool(r.LoginExpired), r.ConnectionIP,
+			r.CountryCode, r.CityName, r.OwnerID, r.Owner, joinList(r.Groups), r.ApprovalState, joinList(r.PostureChecks),
+			joinList(r.FailedPostureChecks), strconv.FormatBool(r.Compliant),
+		})
+	}
+	return writeCSV(w, peerColumns, rows)
+}
+
+// WriteUsers writes the user records in the format. The CSV lists the token names, the JSON all token details.
+func WriteUsers(w io.Writer, format Format, records []*UserRecord) error {
+	if format == FormatJSON {
+		apiRecords := make([]api.UserInventoryRecord, 0, len(records))
+		for _, r := range records {
+			apiRecords = append(apiRecords, r.ToAPIResponse())
+		}
+		return writeJSON(w, apiRecords)
+	}
+
+	rows := make([][]string, 0, len(records))
+	for _, r := range records {
+		tokens := make([]string, 0, len(r.Tokens))
+		for _, token := range r.Tokens {
+			tokens = append(tokens, token.Name)
+		}
+		rows = append(rows, []string{
+			r.ID, r.Name, r.Email, r.Role, strconv.FormatBool(r.IsServiceUser), strconv.FormatBool(r.Blocked), r.Issued,
+			formatTime(&r.CreatedAt), formatTime(r.LastLogin), joinList(r.Groups), strconv.Itoa(r.Peers), joinList(tokens),
+			formatTime(r.TokensLastUsed()),
+		})
+	}
+	return writeCSV(w, userColumns, rows)
+}
+
+func writeJSON(w io.Writer, records any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// joinList joins list values of a CSV cell
+func joinList(values []string) string {
+	return strings.Join(values, ";")
+}
@@ -0,0 +1,16 @@
+package reports
+
+import (
+	"context"
+	"time"
+)
+
+type Manager interface {
+	GetPeerReport(ctx context.Context, accountID, userID string, filter PeerFilter) ([]*PeerRecord, error)
+	GetUserReport(ctx context.Context, accountID, userID string, filter UserFilter) ([]*UserRecord, error)
+
+	// WriteReports writes the peer and user reports of all accounts to the directory, one subdirectory per account
+	WriteReports(ctx context.Context, dir string, format Format) error
+	// Start writes the reports periodically until the context is done
+	Start(ctx context.Context, dir string, interval time.Duration, format Format)
+}
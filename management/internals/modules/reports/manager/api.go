@@ -0,0 +1,154 @@
+package manager
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netbirdio/netbird/management/internals/modules/reports"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/shared/management/http/util"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type handler struct {
+	manager reports.Manager
+}
+
+func RegisterEndpoints(router *mux.Router, manager reports.Manager) {
+	h := &handler{
+		manager: manager,
+	}
+
+	router.HandleFunc("/reports/peers", h.getPeerReport).Methods("GET", "OPTIONS")
+	router.HandleFunc("/reports/users", h.getUserReport).Methods("GET", "OPTIONS")
+}
+
+func (h *handler) getPeerReport(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	query := r.URL.Query()
+	format, err := reports.ParseFormat(query.Get("format"))
+	if err != nil {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "%s", err), w)
+		return
+	}
+
+	filter := reports.PeerFilter{
+		GroupIDs:      query["group"],
+		OS:            query.Get("os"),
+		UserID:        query.Get("user_id"),
+		ApprovalState: query.Get("approval_state"),
+	}
+	if filter.Connected, err = parseBoolParam(query, "connected"); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+	if filter.Compliant, err = parseBoolParam(query, "compliant"); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+	if filter.LastSeenBefore, err = parseTimeParam(query, "last_seen_before"); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	records, err := h.manager.GetPeerReport(r.Context(), userAuth.AccountId, userAuth.UserId, filter)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = reports.WritePeers(&buf, format, records); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+	writeReport(w, "peers", format, buf.Bytes())
+}
+
+func (h *handler) getUserReport(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	query := r.URL.Query()
+	format, err := reports.ParseFormat(query.Get("format"))
+	if err != nil {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "%s", err), w)
+		return
+	}
+
+	filter := reports.UserFilter{
+		Role: query.Get("role"),
+	}
+	if filter.ServiceUser, err = parseBoolParam(query, "service_user"); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+	if filter.Blocked, err = parseBoolParam(query, "blocked"); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+	if filter.InactiveSince, err = parseTimeParam(query, "inactive_since"); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	records, err := h.manager.GetUserReport(r.Context(), userAuth.AccountId, userAuth.UserId, filter)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = reports.WriteUsers(&buf, format, records); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+	writeReport(w, "users", format, buf.Bytes())
+}
+
+func writeReport(w http.ResponseWriter, name string, format reports.Format, content []byte) {
+	w.Header().Set("Content-Type", format.ContentType()+"; charset=UTF-8")
+	if format == reports.FormatCSV {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".csv"))
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(content)
+}
+
+func parseBoolParam(query url.Values, name string) (*bool, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "invalid %s parameter %q", name, value)
+	}
+	return &parsed, nil
+}
+
+func parseTimeParam(query url.Values, name string) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, status.Errorf(status.InvalidArgument, "invalid %s parameter %q, expected RFC 3339 time", name, value)
+	}
+	return parsed, nil
+}
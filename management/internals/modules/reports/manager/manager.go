@@ -0,0 +1,169 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/internals/modules/reports"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// reportTimeFormat is used in the names of the scheduled report files
+const reportTimeFormat = "20060102T150405Z"
+
+type managerImpl struct {
+	store              store.Store
+	permissionsManager permissions.Manager
+}
+
+func NewManager(store store.Store, permissionsManager permissions.Manager) reports.Manager {
+	return &managerImpl{
+		store:              store,
+		permissionsManager: permissionsManager,
+	}
+}
+
+func (m *managerImpl) GetPeerReport(ctx context.Context, accountID, userID string, filter reports.PeerFilter) ([]*reports.PeerRecord, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, modules.Peers); err != nil {
+		return nil, err
+	}
+
+	account, err := m.store.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	peersInScope, err := m.permissionsManager.GetPeersInScope(ctx, accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	records := reports.BuildPeerReport(ctx, account, filter)
+	if peersInScope == nil {
+		return records, nil
+	}
+
+	return slices.DeleteFunc(records, func(record *reports.PeerRecord) bool {
+		_, ok := peersInScope[record.ID]
+		return !ok
+	}), nil
+}
+
+func (m *managerImpl) GetUserReport(ctx context.Context, accountID, userID string, filter reports.UserFilter) ([]*reports.UserRecord, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, modules.Users); err != nil {
+		return nil, err
+	}
+
+	account, err := m.store.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return reports.BuildUserReport(account, filter), nil
+}
+
+// WriteReports writes <dir>/<account ID>/peers-<time>.<format> and users-<time>.<format> for every account.
+// Accounts that fail are logged and skipped.
+func (m *managerImpl) WriteReports(ctx context.Context, dir string, format reports.Format) error {
+	accountIDs, err := m.store.GetAllAccountIDs(ctx, store.LockingStrengthNone)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Format(reportTimeFormat)
+	for _, accountID := range accountIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		account, err := m.store.GetAccount(ctx, accountID)
+		if err != nil {
+			log.WithContext(ctx).Errorf("failed to load account %s for inventory reports: %v", accountID, err)
+			continue
+		}
+
+		if err = writeAccountReports(ctx, filepath.Join(dir, accountID), now, format, account); err != nil {
+			log.WithContext(ctx).Errorf("failed to write inventory reports of account %s: %v", accountID, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *managerImpl) Start(ctx context.Context, dir string, interval time.Duration, format reports.Format) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := m.WriteReports(ctx, dir, format); err != nil {
+					log.WithContext(ctx).Errorf("failed to write inventory reports: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func writeAccountReports(ctx context.Context, dir, timestamp string, format reports.Format, account *types.Account) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	peers := reports.BuildPeerReport(ctx, account, reports.PeerFilter{})
+	err := writeReportFile(filepath.Join(dir, fmt.Sprintf("peers-%s.%s", timestamp, format)), func(w io.Writer) error {
+		return reports.WritePeers(w, format, peers)
+	})
+	if err != nil {
+		return err
+	}
+
+	users := reports.BuildUserReport(account, reports.UserFilter{})
+	return writeReportFile(filepath.Join(dir, fmt.Sprintf("users-%s.%s", timestamp, format)), func(w io.Writer) error {
+		return reports.WriteUsers(w, format, users)
+	})
+}
+
+// writeReportFile writes the file through a temporary file so that readers never see a partial report
+func writeReportFile(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".report-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint
+
+	if err = write(tmp); err != nil {
+		tmp.Close() //nolint
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (m *managerImpl) validatePermissions(ctx context.Context, accountID, userID string, module modules.Module) error {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, module, operations.Read)
+	if err != nil {
+		return status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return status.NewPermissionDeniedError()
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/reports"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/posture"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/auth"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	testAccountID = "account-id"
+	adminID       = "admin-id"
+	regularUserID = "regular-user-id"
+	serviceUserID = "service-user-id"
+	laptopsGroup  = "laptops-group-id"
+	serversGroup  = "servers-group-id"
+	laptopPeer    = "laptop-peer-id"
+	oldLaptopPeer = "old-laptop-peer-id"
+	serverPeer    = "server-peer-id"
+	versionCheck  = "version-check-id"
+)
+
+var testNow = time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+func setupTest(t *testing.T) (*managerImpl, store.Store) {
+	t.Helper()
+
+	ctx := context.Background()
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	adminLogin := testNow.Add(-time.Hour)
+	userLogin := testNow.Add(-90 * 24 * time.Hour)
+	tokenUsed := testNow.Add(-2 * time.Hour)
+
+	require.NoError(t, testStore.SaveAccount(ctx, &types.Account{
+		Id:      testAccountID,
+		Network: types.NewNetwork(),
+		Users: map[string]*types.User{
+			adminID: {
+				Id: adminID, AccountID: testAccountID, Role: types.UserRoleAdmin, LastLogin: &adminLogin, CreatedAt: testNow.Add(-365 * 24 * time.Hour),
+				PATs: map[string]*types.PersonalAccessToken{
+					"pat-id": {ID: "pat-id", UserID: adminID, Name: "automation", HashedToken: "hashed", CreatedAt: testNow.Add(-24 * time.Hour), LastUsed: &tokenUsed},
+				},
+			},
+			regularUserID: {Id: regularUserID, AccountID: testAccountID, Role: types.UserRoleUser, LastLogin: &userLogin, AutoGroups: []string{laptopsGroup}},
+			serviceUserID: {Id: serviceUserID, AccountID: testAccountID, Role: types.UserRoleUser, IsServiceUser: true, ServiceUserName: "ci"},
+		},
+		Peers: map[string]*nbpeer.Peer{
+			laptopPeer: {
+				ID: laptopPeer, AccountID: testAccountID, Key: "laptop-key", Name: "laptop", IP: net.IP{100, 64, 0, 1}, DNSLabel: "laptop", UserID: regularUserID,
+				Meta:   nbpeer.PeerSystemMeta{Hostname: "laptop", OS: "darwin", OSVersion: "14.5", WtVersion: "0.40.0"},
+				Status: &nbpeer.PeerStatus{Connected: true, LastSeen: testNow},
+			},
+			oldLaptopPeer: {
+				ID: oldLaptopPeer, AccountID: testAccountID, Key: "old-laptop-key", Name: "old-laptop", IP: net.IP{100, 64, 0, 2}, DNSLabel: "old-laptop", UserID: regularUserID,
+				Meta:   nbpeer.PeerSystemMeta{Hostname: "old-laptop", OS: "windows", WtVersion: "0.20.0"},
+				Status: &nbpeer.PeerStatus{LastSeen: testNow.Add(-60 * 24 * time.Hour), RequiresApproval: true},
+			},
+			serverPeer: {
+				ID: serverPeer, AccountID: testAccountID, Key: "server-key", Name: "server", IP: net.IP{100, 64, 0, 3}, DNSLabel: "server",
+				Meta:   nbpeer.PeerSystemMeta{Hostname: "server", OS: "linux", WtVersion: "0.20.0"},
+				Status: &nbpeer.PeerStatus{Connected: true, LastSeen: testNow},
+			},
+		},
+		Groups: map[string]*types.Group{
+			laptopsGroup: {ID: laptopsGroup, AccountID: testAccountID, Name: "laptops", Peers: []string{laptopPeer, oldLaptopPeer}},
+			serversGroup: {ID: serversGroup, AccountID: testAccountID, Name: "servers", Peers: []string{serverPeer}},
+		},
+		Policies: []*types.Policy{
+			{
+				ID: "policy-id", AccountID: testAccountID, Name: "laptops to servers", Enabled: true, SourcePostureChecks: []string{versionCheck},
+				Rules: []*types.PolicyRule{
+					{ID: "rule-id", PolicyID: "policy-id", Enabled: true, Action: types.PolicyTrafficActionAccept, Sources: []string{laptopsGroup}, Destinations: []string{serversGroup}},
+				},
+			},
+		},
+		PostureChecks: []*posture.Checks{
+			{ID: versionCheck, AccountID: testAccountID, Name: "min version", Checks: posture.ChecksDefinition{NBVersionCheck: &posture.NBVersionCheck{MinVersion: "0.30.0"}}},
+		},
+		Settings:    &types.Settings{},
+		DNSSettings: types.DNSSettings{DisabledManagementGroups: []string{}},
+	}))
+
+	return &managerImpl{
+		store:              testStore,
+		permissionsManager: permissions.NewManager(testStore),
+	}, testStore
+}
+
+func peerIDs(records []*reports.PeerRecord) []string {
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	return ids
+}
+
+func TestGetPeerReport(t *testing.T) {
+	m, _ := setupTest(t)
+	ctx := context.Background()
+	connected := true
+	compliant := false
+
+	tests := []struct {
+		name     string
+		filter   reports.PeerFilter
+		expected []string
+	}{
+		{name: "all peers", filter: reports.PeerFilter{}, expected: []string{laptopPeer, oldLaptopPeer, serverPeer}},
+		{name: "by group", filter: reports.PeerFilter{GroupIDs: []string{serversGroup}}, expected: []string{serverPeer}},
+		{name: "by os", filter: reports.PeerFilter{OS: "Darwin"}, expected: []string{laptopPeer}},
+		{name: "by owner", filter: reports.PeerFilter{UserID: regularUserID}, expected: []string{laptopPeer, oldLaptopPeer}},
+		{name: "connected", filter: reports.PeerFilter{Connected: &connected}, expected: []string{laptopPeer, serverPeer}},
+		{name: "not compliant", filter: reports.PeerFilter{Compliant: &compliant}, expected: []string{oldLaptopPeer}},
+		{name: "pending approval", filter: reports.PeerFilter{ApprovalState: reports.ApprovalStatePending}, expected: []string{oldLaptopPeer}},
+		{name: "stale", filter: reports.PeerFilter{LastSeenBefore: testNow.Add(-30 * 24 * time.Hour)}, expected: []string{oldLaptopPeer}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := m.GetPeerReport(ctx, testAccountID, adminID, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, peerIDs(records))
+		})
+	}
+
+	records, err := m.GetPeerReport(ctx, testAccountID, adminID, reports.PeerFilter{})
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	laptop := records[0]
+	assert.Equal(t, "darwin 14.5", laptop.OS)
+	assert.Equal(t, []string{"laptops"}, laptop.Groups)
+	assert.Equal(t, []string{"min version"}, laptop.PostureChecks)
+	assert.Empty(t, laptop.FailedPostureChecks)
+	assert.True(t, laptop.Compliant)
+	assert.Equal(t, reports.ApprovalStateApproved, laptop.ApprovalState)
+
+	oldLaptop := records[1]
+	assert.Equal(t, []string{"min version"}, oldLaptop.FailedPostureChecks)
+	assert.False(t, oldLaptop.Compliant)
+
+	server := records[2]
+	assert.Empty(t, server.PostureChecks, "posture checks only apply to policy sources")
+	assert.True(t, server.Compliant)
+
+	_, err = m.GetPeerReport(ctx, testAccountID, regularUserID, reports.PeerFilter{})
+	require.Error(t, err)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, sErr.Type())
+}
+
+func TestGetPeerReport_PATScope(t *testing.T) {
+	m, _ := setupTest(t)
+
+	ctx := nbcontext.SetUserAuthInContext(context.Background(), auth.UserAuth{UserId: adminID, AccountId: testAccountID, IsPAT: true})
+	ctx = types.SetPATScopeInContext(ctx, &types.PATScope{PeerGroups: []string{serversGroup}})
+
+	records, err := m.GetPeerReport(ctx, testAccountID, adminID, reports.PeerFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{serverPeer}, peerIDs(records), "a token scoped to peer groups only reports their peers")
+}
+
+func TestGetUserReport(t *testing.T) {
+	m, _ := setupTest(t)
+	ctx := context.Background()
+	serviceUser := false
+
+	records, err := m.GetUserReport(ctx, testAccountID, adminID, reports.UserFilter{})
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	var admin *reports.UserRecord
+	for _, record := range records {
+		if record.ID == adminID {
+			admin = record
+		}
+	}
+	require.NotNil(t, admin)
+	require.Len(t, admin.Tokens, 1)
+	assert.Equal(t, "automation", admin.Tokens[0].Name)
+	require.NotNil(t, admin.TokensLastUsed())
+	assert.True(t, admin.TokensLastUsed().Equal(testNow.Add(-2*time.Hour)))
+
+	records, err = m.GetUserReport(ctx, testAccountID, adminID, reports.UserFilter{ServiceUser: &serviceUser, InactiveSince: testNow.Add(-30 * 24 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, regularUserID, records[0].ID)
+	assert.Equal(t, []string{"laptops"}, records[0].Groups)
+	assert.Equal(t, 2, records[0].Peers)
+
+	_, err = m.GetUserReport(ctx, testAccountID, regularUserID, reports.UserFilter{})
+	require.Error(t, err)
+}
+
+func TestWritePeersFormats(t *testing.T) {
+	m, _ := setupTest(t)
+	records, err := m.GetPeerReport(context.Background(), testAccountID, adminID, reports.PeerFilter{})
+	require.NoError(t, err)
+
+	var csvOut bytes.Buffer
+	require.NoError(t, reports.WritePeers(&csvOut, reports.FormatCSV, records))
+	rows, err := csv.NewReader(&csvOut).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	assert.Equal(t, "id", rows[0][0])
+	assert.Equal(t, laptopPeer, rows[1][0])
+
+	var jsonOut bytes.Buffer
+	require.NoError(t, reports.WritePeers(&jsonOut, reports.FormatJSON, records))
+	var parsed []api.PeerInventoryRecord
+	require.NoError(t, json.Unmarshal(jsonOut.Bytes(), &parsed))
+	require.Len(t, parsed, 3)
+	assert.Equal(t, api.PeerInventoryRecordApprovalStatePending, parsed[1].ApprovalState)
+
+	_, err = reports.ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestWriteReports(t *testing.T) {
+	m, _ := setupTest(t)
+	dir := t.TempDir()
+
+	require.NoError(t, m.WriteReports(context.Background(), dir, reports.FormatJSON))
+
+	entries, err := os.ReadDir(filepath.Join(dir, testAccountID))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	peersFiles, err := filepath.Glob(filepath.Join(dir, testAccountID, "peers-*.json"))
+	require.NoError(t, err)
+	require.Len(t, peersFiles, 1)
+
+	content, err := os.ReadFile(peersFiles[0])
+	require.NoError(t, err)
+	var parsed []api.PeerInventoryRecord
+	require.NoError(t, json.Unmarshal(content, &parsed))
+	assert.Len(t, parsed, 3)
+}
@@ -0,0 +1,419 @@
+package reports
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+// ApprovalState of a peer in the inventory
+const (
+	ApprovalStateApproved = "approved"
+	ApprovalStatePending  = "pending"
+)
+
+// PeerRecord is a row of the peer inventory
+type PeerRecord struct {
+	ID            string
+	Name          string
+	Hostname      string
+	IP            string
+	OS            string
+	Version       string
+	SerialNumber  string
+	Connected     bool
+	LastSeen      time.Time
+	LastLogin     *time.Time
+	LoginExpired  bool
+	ConnectionIP  string
+	CountryCode   string
+	CityName      string
+	OwnerID       string
+	Owner         string
+	Groups        []string
+	ApprovalState string
+	// PostureChecks are the names of the posture checks applied to the peer by policies
+	PostureChecks []string
+	// FailedPostureChecks are the names of the applied posture checks the peer doesn't pass
+	FailedPostureChecks []string
+	Compliant           bool
+}
+
+// TokenRecord is a personal access token of a user in the inventory
+type TokenRecord struct {
+	ID             string
+	Name           string
+	CreatedAt      time.Time
+	ExpirationDate *time.Time
+	LastUsed       *time.Time
+}
+
+// UserRecord is a row of the user inventory
+type UserRecord struct {
+	ID            string
+	Name          string
+	Email         string
+	Role          string
+	IsServiceUser bool
+	Blocked       bool
+	Issued        string
+	CreatedAt     time.Time
+	LastLogin     *time.Time
+	Groups        []string
+	Peers         int
+	Tokens        []TokenRecord
+}
+
+// TokensLastUsed returns the last time any token of the user was used
+func (r *UserRecord) TokensLastUsed() *time.Time {
+	var lastUsed *time.Time
+	for _, token := range r.Tokens {
+		if token.LastUsed != nil && (lastUsed == nil || token.LastUsed.After(*lastUsed)) {
+			lastUsed = token.LastUsed
+		}
+	}
+	return lastUsed
+}
+
+// ToAPIResponse converts the record to its API representation
+func (r *PeerRecord) ToAPIResponse() api.PeerInventoryRecord {
+	return api.PeerInventoryRecord{
+		Id:                  r.ID,
+		Name:                r.Name,
+		Hostname:            r.Hostname,
+		Ip:                  r.IP,
+		Os:                  r.OS,
+		Version:             r.Version,
+		SerialNumber:        r.SerialNumber,
+		Connected:           r.Connected,
+		LastSeen:            r.LastSeen,
+		LastLogin:           r.LastLogin,
+		LoginExpired:        r.LoginExpired,
+		ConnectionIp:        r.ConnectionIP,
+		CountryCode:         r.CountryCode,
+		CityName:            r.CityName,
+		OwnerId:             r.OwnerID,
+		Owner:               r.Owner,
+		Groups:              r.Groups,
+		ApprovalState:       api.PeerInventoryRecordApprovalState(r.ApprovalState),
+		PostureChecks:       r.PostureChecks,
+		FailedPostureChecks: r.FailedPostureChecks,
+		Compliant:           r.Compliant,
+	}
+}
+
+// ToAPIResponse converts the record to its API representation
+func (r *UserRecord) ToAPIResponse() api.UserInventoryRecord {
+	tokens := make([]api.UserInventoryToken, 0, len(r.Tokens))
+	for _, token := range r.Tokens {
+		tokens = append(tokens, api.UserInventoryToken{
+			Id:             token.ID,
+			Name:           token.Name,
+			CreatedAt:      token.CreatedAt,
+			ExpirationDate: token.ExpirationDate,
+			LastUsed:       token.LastUsed,
+		})
+	}
+	return api.UserInventoryRecord{
+		Id:            r.ID,
+		Name:          r.Name,
+		Email:         r.Email,
+		Role:          r.Role,
+		IsServiceUser: r.IsServiceUser,
+		Blocked:       r.Blocked,
+		Issued:        r.Issued,
+		CreatedAt:     r.CreatedAt,
+		LastLogin:     r.LastLogin,
+		Groups:        r.Groups,
+		Peers:         r.Peers,
+		Tokens:        tokens,
+	}
+}
+
+// PeerFilter selects the peers of a report. Zero values match all peers.
+type PeerFilter struct {
+	// GroupIDs match peers in any of the groups
+	GroupIDs []string
+	// OS matches peers whose operating system contains the value, case-insensitive
+	OS            string
+	UserID        string
+	Connected     *bool
+	Compliant     *bool
+	ApprovalState string
+	// LastSeenBefore matches peers that haven't been seen since the time, e.g. to find stale peers
+	LastSeenBefore time.Time
+}
+
+// UserFilter selects the users of a report. Zero values match all users.
+type UserFilter struct {
+	Role        string
+	ServiceUser *bool
+	Blocked     *bool
+	// InactiveSince matches users that haven't logged in since the time
+	InactiveSince time.Time
+}
+
+// BuildPeerReport returns the inventory of the account peers matching the filter ordered by name
+func BuildPeerReport(ctx context.Context, account *types.Account, filter PeerFilter) []*PeerRecord {
+	peerGroups := make(map[string][]*types.Group)
+	for _, group := range account.Groups {
+		for _, peerID := range group.Peers {
+			peerGroups[peerID] = append(peerGroups[peerID], group)
+		}
+	}
+
+	records := make([]*PeerRecord, 0, len(account.Peers))
+	for _, peer := range account.Peers {
+		if !matchesGroups(peerGroups[peer.ID], filter.GroupIDs) {
+			continue
+		}
+
+		record := newPeerRecord(ctx, account, peer, peerGroups[peer.ID])
+		if !filter.matches(record) {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Name != records[j].Name {
+			return records[i].Name < records[j].Name
+		}
+		return records[i].ID < records[j].ID
+	})
+	return records
+}
+
+func newPeerRecord(ctx context.Context, account *types.Account, peer *nbpeer.Peer, groups []*types.Group) *PeerRecord {
+	record := &PeerRecord{
+		ID:            peer.ID,
+		Name:          peer.Name,
+		Hostname:      peer.Meta.Hostname,
+		IP:            peer.IP.String(),
+		OS:            strings.TrimSpace(peer.Meta.OS + " " + peer.Meta.OSVersion),
+		Version:       peer.Meta.WtVersion,
+		SerialNumber:  peer.Meta.SystemSerialNumber,
+		LastLogin:     peer.LastLogin,
+		CountryCode:   peer.Location.CountryCode,
+		CityName:      peer.Location.CityName,
+		OwnerID:       peer.UserID,
+		Groups:        make([]string, 0, len(groups)),
+		ApprovalState: ApprovalStateApproved,
+	}
+	if peer.Location.ConnectionIP != nil {
+		record.ConnectionIP = peer.Location.ConnectionIP.String()
+	}
+	if peer.Status != nil {
+		record.Connected = peer.Status.Connected
+		record.LastSeen = peer.Status.LastSeen
+		record.LoginExpired = peer.Status.LoginExpired
+		if peer.Status.RequiresApproval {
+			record.ApprovalState = ApprovalStatePending
+		}
+	}
+	if owner, ok := account.Users[peer.UserID]; ok {
+		record.Owner = userDisplayName(owner)
+	}
+	for _, group := range groups {
+		record.Groups = append(record.Groups, group.Name)
+	}
+	sort.Strings(record.Groups)
+
+	record.PostureChecks, record.FailedPostureChecks = evaluatePostureChecks(ctx, account, peer, groups)
+	record.Compliant = len(record.FailedPostureChecks) == 0
+
+	return record
+}
+
+// evaluatePostureChecks runs the posture checks of the enabled policies that have the peer as a source
+func evaluatePostureChecks(ctx context.Context, account *types.Account, peer *nbpeer.Peer, groups []*types.Group) ([]string, []string) {
+	applied := make([]string, 0)
+	failed := make([]string, 0)
+
+	seen := make(map[string]struct{})
+	for _, policy := range account.Policies {
+		if !policy.Enabled || len(policy.SourcePostureChecks) == 0 || !isPolicySource(policy, groups) {
+			continue
+		}
+
+		for _, checksID := range policy.SourcePostureChecks {
+			if _, ok := seen[checksID]; ok {
+				continue
+			}
+			seen[checksID] = struct{}{}
+
+			checks := account.GetPostureChecks(checksID)
+			if checks == nil {
+				continue
+			}
+			applied = append(applied, checks.Name)
+
+			for _, check := range checks.GetChecks() {
+				valid, err := check.Check(ctx, *peer)
+				if err != nil {
+					log.WithContext(ctx).Debugf("failed to run check %s on peer %s: %v", check.Name(), peer.ID, err)
+				}
+				if !valid {
+					failed = append(failed, checks.Name)
+					break
+				}
+			}
+		}
+	}
+
+	sort.Strings(applied)
+	sort.Strings(failed)
+	return applied, failed
+}
+
+func isPolicySource(policy *types.Policy, groups []*types.Group) bool {
+	for _, rule := range policy.Rules {
+		if !rule.Enabled {
+			continue
+		}
+		for _, group := range groups {
+			if slices.Contains(rule.Sources, group.ID) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesGroups(groups []*types.Group, groupIDs []string) bool {
+	if len(groupIDs) == 0 {
+		return true
+	}
+	for _, group := range groups {
+		if slices.Contains(groupIDs, group.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f PeerFilter) matches(record *PeerRecord) bool {
+	if f.OS != "" && !strings.Contains(strings.ToLower(record.OS), strings.ToLower(f.OS)) {
+		return false
+	}
+	if f.UserID != "" && record.OwnerID != f.UserID {
+		return false
+	}
+	if f.Connected != nil && record.Connected != *f.Connected {
+		return false
+	}
+	if f.Compliant != nil && record.Compliant != *f.Compliant {
+		return false
+	}
+	if f.ApprovalState != "" && record.ApprovalState != f.ApprovalState {
+		return false
+	}
+	if !f.LastSeenBefore.IsZero() && !record.LastSeen.Before(f.LastSeenBefore) {
+		return false
+	}
+	return true
+}
+
+// BuildUserReport returns the inventory of the account users matching the filter ordered by name
+func BuildUserReport(account *types.Account, filter UserFilter) []*UserRecord {
+	userPeers := make(map[string]int)
+	for _, peer := range account.Peers {
+		userPeers[peer.UserID]++
+	}
+
+	records := make([]*UserRecord, 0, len(account.Users))
+	for _, user := range account.Users {
+		record := newUserRecord(account, user, userPeers[user.Id])
+		if !filter.matches(record) {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Name != records[j].Name {
+			return records[i].Name < records[j].Name
+		}
+		return records[i].ID < records[j].ID
+	})
+	return records
+}
+
+func newUserRecord(account *types.Account, user *types.User, peers int) *UserRecord {
+	record := &UserRecord{
+		ID:            user.Id,
+		Name:          userDisplayName(user),
+		Email:         user.Email,
+		Role:          string(user.Role),
+		IsServiceUser: user.IsServiceUser,
+		Blocked:       user.Blocked,
+		Issued:        user.Issued,
+		CreatedAt:     user.CreatedAt,
+		LastLogin:     user.LastLogin,
+		Groups:        make([]string, 0, len(user.AutoGroups)),
+		Peers:         peers,
+		Tokens:        make([]TokenRecord, 0, len(user.PATs)),
+	}
+	if user.LastLogin != nil && user.LastLogin.IsZero() {
+		record.LastLogin = nil
+	}
+
+	for _, groupID := range user.AutoGroups {
+		if group, ok := account.Groups[groupID]; ok {
+			record.Groups = append(record.Groups, group.Name)
+		}
+	}
+	sort.Strings(record.Groups)
+
+	for _, pat := range user.PATs {
+		record.Tokens = append(record.Tokens, TokenRecord{
+			ID:             pat.ID,
+			Name:           pat.Name,
+			CreatedAt:      pat.CreatedAt,
+			ExpirationDate: pat.ExpirationDate,
+			LastUsed:       pat.LastUsed,
+		})
+	}
+	sort.Slice(record.Tokens, func(i, j int) bool {
+		return record.Tokens[i].Name < record.Tokens[j].Name
+	})
+
+	return record
+}
+
+func (f UserFilter) matches(record *UserRecord) bool {
+	if f.Role != "" && record.Role != f.Role {
+		return false
+	}
+	if f.ServiceUser != nil && record.IsServiceUser != *f.ServiceUser {
+		return false
+	}
+	if f.Blocked != nil && record.Blocked != *f.Blocked {
+		return false
+	}
+	if !f.InactiveSince.IsZero() && record.LastLogin != nil && !record.LastLogin.Before(f.InactiveSince) {
+		return false
+	}
+	return true
+}
+
+func userDisplayName(user *types.User) string {
+	switch {
+	case user.IsServiceUser && user.ServiceUserName != "":
+		return user.ServiceUserName
+	case user.Name != "":
+		return user.Name
+	case user.Email != "":
+		return user.Email
+	default:
+		return user.Id
+	}
+}
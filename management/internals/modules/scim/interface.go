@@ -0,0 +1,16 @@
+package scim
+
+import (
+	"context"
+)
+
+// BasePath is the path the SCIM 2.0 endpoint is served on
+const BasePath = "/scim/v2"
+
+type Manager interface {
+	GetToken(ctx context.Context, accountID, userID string) (*Token, error)
+	CreateToken(ctx context.Context, accountID, userID string) (*TokenGenerated, error)
+	DeleteToken(ctx context.Context, accountID, userID string) error
+	// Authenticate validates a plain token of a provisioning request and returns the token it belongs to
+	Authenticate(ctx context.Context, plainToken string) (*Token, error)
+}
@@ -0,0 +1,166 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// serviceUserName is the name of the service user the provisioning requests are executed as
+const serviceUserName = "SCIM provisioning"
+
+type managerImpl struct {
+	store              store.Store
+	accountManager     account.Manager
+	permissionsManager permissions.Manager
+}
+
+func NewManager(store store.Store, accountManager account.Manager, permissionsManager permissions.Manager) scim.Manager {
+	return &managerImpl{
+		store:              store,
+		accountManager:     accountManager,
+		permissionsManager: permissionsManager,
+	}
+}
+
+func (m *managerImpl) GetToken(ctx context.Context, accountID, userID string) (*scim.Token, error) {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Users, operations.Read)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	return m.store.GetAccountSCIMToken(ctx, store.LockingStrengthNone, accountID)
+}
+
+// CreateToken creates a new token and its service user. An existing token is replaced and its service user deleted.
+func (m *managerImpl) CreateToken(ctx context.Context, accountID, userID string) (*scim.TokenGenerated, error) {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Users, operations.Create)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	oldToken, err := m.store.GetAccountSCIMToken(ctx, store.LockingStrengthNone, accountID)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	serviceUser, err := m.accountManager.CreateUser(ctx, accountID, userID, &types.UserInfo{
+		Name:          serviceUserName,
+		Role:          string(types.UserRoleAdmin),
+		IsServiceUser: true,
+		AutoGroups:    []string{},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create service user: %w", err)
+	}
+
+	token, err := scim.NewToken(accountID, serviceUser.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("generate token: %w", err)
+	}
+
+	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		if err := transaction.DeleteSCIMToken(ctx, accountID); err != nil && !isNotFound(err) {
+			return err
+		}
+		return transaction.SaveSCIMToken(ctx, &token.Token)
+	})
+	if err != nil {
+		m.deleteServiceUser(ctx, accountID, userID, serviceUser.ID)
+		return nil, err
+	}
+
+	if oldToken != nil {
+		m.deleteServiceUser(ctx, accountID, userID, oldToken.UserID)
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, token.ID, accountID, activity.SCIMTokenCreated, nil)
+
+	return token, nil
+}
+
+// DeleteToken deletes the token and its service user, which disables provisioning for the account
+func (m *managerImpl) DeleteToken(ctx context.Context, accountID, userID string) error {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Users, operations.Delete)
+	if err != nil {
+		return status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return status.NewPermissionDeniedError()
+	}
+
+	token, err := m.store.GetAccountSCIMToken(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return err
+	}
+
+	if err := m.store.DeleteSCIMToken(ctx, accountID); err != nil {
+		return err
+	}
+
+	m.deleteServiceUser(ctx, accountID, userID, token.UserID)
+
+	m.accountManager.StoreEvent(ctx, userID, token.ID, accountID, activity.SCIMTokenDeleted, nil)
+
+	return nil
+}
+
+func (m *managerImpl) Authenticate(ctx context.Context, plainToken string) (*scim.Token, error) {
+	if err := scim.ValidateTokenFormat(plainToken); err != nil {
+		return nil, status.Errorf(status.Unauthorized, "invalid SCIM token: %v", err)
+	}
+
+	token, err := m.store.GetSCIMTokenByHashedToken(ctx, store.LockingStrengthNone, scim.HashToken(plainToken))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, status.Errorf(status.Unauthorized, "invalid SCIM token")
+		}
+		return nil, err
+	}
+
+	serviceUser, err := m.store.GetUserByUserID(ctx, store.LockingStrengthNone, token.UserID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, status.Errorf(status.Unauthorized, "the service user of the SCIM token was deleted")
+		}
+		return nil, err
+	}
+	if serviceUser.AccountID != token.AccountID || serviceUser.IsBlocked() {
+		return nil, status.Errorf(status.Unauthorized, "the service user of the SCIM token is blocked")
+	}
+
+	if err := m.store.MarkSCIMTokenUsed(ctx, token.ID); err != nil {
+		log.WithContext(ctx).Warnf("failed to mark SCIM token %s as used: %v", token.ID, err)
+	}
+
+	return token, nil
+}
+
+// deleteServiceUser removes the service user of a replaced or deleted token. Failures leave an unused service user behind and are only logged.
+func (m *managerImpl) deleteServiceUser(ctx context.Context, accountID, userID, serviceUserID string) {
+	if err := m.accountManager.DeleteUser(ctx, accountID, userID, serviceUserID); err != nil && !isNotFound(err) {
+		log.WithContext(ctx).Errorf("failed to delete SCIM service user %s: %v", serviceUserID, err)
+	}
+}
+
+func isNotFound(err error) bool {
+	sErr, ok := status.FromError(err)
+	return ok && sErr.Type() == status.NotFound
+}
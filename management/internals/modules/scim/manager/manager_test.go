@@ -0,0 +1,288 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	testAccountID = "test-account-id"
+	testUserID    = "test-user-id"
+)
+
+func setupTest(t *testing.T) (*managerImpl, store.Store, *mock_server.MockAccountManager, *permissions.MockManager, *gomock.Controller, func()) {
+	t.Helper()
+
+	ctx := context.Background()
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+
+	err = testStore.SaveAccount(ctx, &types.Account{
+		Id: testAccountID,
+		Users: map[string]*types.User{
+			testUserID: {Id: testUserID, AccountID: testAccountID, Role: types.UserRoleAdmin},
+		},
+	})
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	mockPermissionsManager := permissions.NewMockManager(ctrl)
+
+	serviceUsers := 0
+	mockAccountManager := &mock_server.MockAccountManager{
+		CreateUserFunc: func(ctx context.Context, accountID, userID string, info *types.UserInfo) (*types.UserInfo, error) {
+			serviceUsers++
+			user := &types.User{
+				Id:              fmt.Sprintf("service-user-%d", serviceUsers),
+				AccountID:       accountID,
+				Role:            types.UserRole(info.Role),
+				IsServiceUser:   info.IsServiceUser,
+				ServiceUserName: info.Name,
+			}
+			if err := testStore.SaveUser(ctx, user); err != nil {
+				return nil, err
+			}
+			return &types.UserInfo{ID: user.Id, Name: info.Name, IsServiceUser: true}, nil
+		},
+		DeleteUserFunc: func(ctx context.Context, accountID, initiatorUserID, targetUserID string) error {
+			return testStore.DeleteUser(ctx, accountID, targetUserID)
+		},
+	}
+
+	manager := &managerImpl{
+		store:              testStore,
+		accountManager:     mockAccountManager,
+		permissionsManager: mockPermissionsManager,
+	}
+
+	return manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup
+}
+
+func TestManagerImpl_CreateToken(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		var events []activity.ActivityDescriber
+		mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+			events = append(events, activityID)
+		}
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Users, operations.Create).
+			Return(true, nil)
+
+		token, err := manager.CreateToken(ctx, testAccountID, testUserID)
+		require.NoError(t, err)
+		assert.NoError(t, scim.ValidateTokenFormat(token.PlainToken))
+		assert.Equal(t, testUserID, token.CreatedBy)
+		assert.Equal(t, []activity.ActivityDescriber{activity.SCIMTokenCreated}, events)
+
+		serviceUser, err := testStore.GetUserByUserID(ctx, store.LockingStrengthNone, token.UserID)
+		require.NoError(t, err)
+		assert.True(t, serviceUser.IsServiceUser)
+		assert.Equal(t, types.UserRoleAdmin, serviceUser.Role)
+
+		stored, err := testStore.GetAccountSCIMToken(ctx, store.LockingStrengthNone, testAccountID)
+		require.NoError(t, err)
+		assert.Equal(t, token.ID, stored.ID)
+		assert.Equal(t, scim.HashToken(token.PlainToken), stored.HashedToken)
+	})
+
+	t.Run("rotation replaces the token and its service user", func(t *testing.T) {
+		manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		var deletedUsers []string
+		mockAccountManager.DeleteUserFunc = func(ctx context.Context, accountID, _, targetUserID string) error {
+			deletedUsers = append(deletedUsers, targetUserID)
+			return testStore.DeleteUser(ctx, accountID, targetUserID)
+		}
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Users, operations.Create).
+			Return(true, nil).
+			Times(2)
+
+		oldToken, err := manager.CreateToken(ctx, testAccountID, testUserID)
+		require.NoError(t, err)
+		newToken, err := manager.CreateToken(ctx, testAccountID, testUserID)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, oldToken.UserID, newToken.UserID)
+		assert.Equal(t, []string{oldToken.UserID}, deletedUsers)
+
+		_, err = manager.Authenticate(ctx, oldToken.PlainToken)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.Unauthorized, s.Type())
+
+		token, err := manager.Authenticate(ctx, newToken.PlainToken)
+		require.NoError(t, err)
+		assert.Equal(t, newToken.ID, token.ID)
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Users, operations.Create).
+			Return(false, nil)
+
+		_, err := manager.CreateToken(ctx, testAccountID, testUserID)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.PermissionDenied, s.Type())
+	})
+}
+
+func TestManagerImpl_DeleteToken(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		manager, testStore, mockAccountManager, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		var deletedUsers []string
+		mockAccountManager.DeleteUserFunc = func(ctx context.Context, accountID, _, targetUserID string) error {
+			deletedUsers = append(deletedUsers, targetUserID)
+			return testStore.DeleteUser(ctx, accountID, targetUserID)
+		}
+
+		var events []activity.ActivityDescriber
+		mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+			events = append(events, activityID)
+		}
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Users, operations.Create).
+			Return(true, nil)
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Users, operations.Delete).
+			Return(true, nil)
+
+		token, err := manager.CreateToken(ctx, testAccountID, testUserID)
+		require.NoError(t, err)
+
+		err = manager.DeleteToken(ctx, testAccountID, testUserID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{token.UserID}, deletedUsers)
+		assert.Equal(t, []activity.ActivityDescriber{activity.SCIMTokenCreated, activity.SCIMTokenDeleted}, events)
+
+		_, err = testStore.GetAccountSCIMToken(ctx, store.LockingStrengthNone, testAccountID)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.NotFound, s.Type())
+
+		_, err = manager.Authenticate(ctx, token.PlainToken)
+		require.Error(t, err)
+		s, ok = status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.Unauthorized, s.Type())
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		manager, _, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Users, operations.Delete).
+			Return(true, nil)
+
+		err := manager.DeleteToken(ctx, testAccountID, testUserID)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.NotFound, s.Type())
+	})
+}
+
+func TestManagerImpl_Authenticate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("marks the token as used", func(t *testing.T) {
+		manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Users, operations.Create).
+			Return(true, nil)
+
+		generated, err := manager.CreateToken(ctx, testAccountID, testUserID)
+		require.NoError(t, err)
+
+		token, err := manager.Authenticate(ctx, generated.PlainToken)
+		require.NoError(t, err)
+		assert.Equal(t, testAccountID, token.AccountID)
+		assert.Equal(t, generated.UserID, token.UserID)
+
+		stored, err := testStore.GetAccountSCIMToken(ctx, store.LockingStrengthNone, testAccountID)
+		require.NoError(t, err)
+		assert.NotNil(t, stored.LastUsed)
+	})
+
+	t.Run("blocked service user", func(t *testing.T) {
+		manager, testStore, _, mockPermissionsManager, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		mockPermissionsManager.EXPECT().
+			ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, modules.Users, operations.Create).
+			Return(true, nil)
+
+		generated, err := manager.CreateToken(ctx, testAccountID, testUserID)
+		require.NoError(t, err)
+
+		serviceUser, err := testStore.GetUserByUserID(ctx, store.LockingStrengthNone, generated.UserID)
+		require.NoError(t, err)
+		serviceUser.Blocked = true
+		require.NoError(t, testStore.SaveUser(ctx, serviceUser))
+
+		_, err = manager.Authenticate(ctx, generated.PlainToken)
+		require.Error(t, err)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, status.Unauthorized, s.Type())
+	})
+
+	t.Run("invalid tokens", func(t *testing.T) {
+		manager, _, _, _, ctrl, cleanup := setupTest(t)
+		defer cleanup()
+		defer ctrl.Finish()
+
+		for _, plainToken := range []string{"", "nbp_abcdefghijklmnopqrstuvwxyz0123456789", "nbs_abcdefghijklmnopqrstuvwxyz0123456789"} {
+			_, err := manager.Authenticate(ctx, plainToken)
+			require.Error(t, err)
+			s, ok := status.FromError(err)
+			assert.True(t, ok)
+			assert.Equal(t, status.Unauthorized, s.Type())
+		}
+	})
+}
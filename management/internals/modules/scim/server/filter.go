@@ -0,0 +1,84 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+)
+
+// filter is a single equality filter, the only kind identity providers use to look up resources before provisioning them
+type filter struct {
+	// attribute is lowercased, attribute names are case-insensitive
+	attribute string
+	value     string
+}
+
+// parseFilter parses a filter of the form `attribute eq "value"`. An empty expression returns a nil filter.
+func parseFilter(expression string) (*filter, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return nil, nil
+	}
+
+	attribute, rest, ok := strings.Cut(expression, " ")
+	if !ok {
+		return nil, invalidValue("unsupported filter %q, only 'attribute eq \"value\"' is supported", expression)
+	}
+	operator, value, ok := strings.Cut(strings.TrimSpace(rest), " ")
+	if !ok || !strings.EqualFold(operator, "eq") {
+		return nil, invalidValue("unsupported filter %q, only 'attribute eq \"value\"' is supported", expression)
+	}
+
+	value = strings.TrimSpace(value)
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	return &filter{
+		attribute: strings.ToLower(attribute),
+		value:     value,
+	}, nil
+}
+
+// matches reports whether any of the values of the filtered attribute equals the filter value. Values are compared
+// case-insensitively, the attributes NetBird stores are either generated IDs or emails and names.
+func (f *filter) matches(attributes map[string][]string) (bool, error) {
+	if f == nil {
+		return true, nil
+	}
+
+	values, ok := attributes[f.attribute]
+	if !ok {
+		return false, invalidValue("filtering by attribute %q is not supported", f.attribute)
+	}
+	for _, value := range values {
+		if strings.EqualFold(value, f.value) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// userFilterAttributes returns the filterable attributes of a user resource keyed by their lowercased names
+func userFilterAttributes(u *user) map[string][]string {
+	emails := make([]string, 0, len(u.Emails))
+	for _, e := range u.Emails {
+		emails = append(emails, e.Value)
+	}
+	return map[string][]string{
+		"id":           {u.ID},
+		"externalid":   {u.ExternalID},
+		"username":     {u.UserName},
+		"displayname":  {u.DisplayName},
+		"emails.value": emails,
+		"emails":       emails,
+	}
+}
+
+// groupFilterAttributes returns the filterable attributes of a group resource keyed by their lowercased names
+func groupFilterAttributes(g *group) map[string][]string {
+	return map[string][]string{
+		"id":          {g.ID},
+		"externalid":  {g.ExternalID},
+		"displayname": {g.DisplayName},
+	}
+}
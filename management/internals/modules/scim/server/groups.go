@@ -0,0 +1,433 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/xid"
+
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+func (h *handler) listGroups(w http.ResponseWriter, r *http.Request) {
+	p := principalFromContext(r.Context())
+
+	pg, err := parsePage(r)
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+	f, err := parseFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+	withMembers := !strings.Contains(strings.ToLower(r.URL.Query().Get("excludedAttributes")), "members")
+
+	groups, err := h.managedGroups(r.Context(), p)
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+	users, err := h.provisionableUsers(r.Context(), p)
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	resources := make([]*group, 0, len(groups))
+	for _, g := range groups {
+		resource := toGroupResource(g, groupMembers(users, g.ID), withMembers)
+		ok, err := f.matches(groupFilterAttributes(resource))
+		if err != nil {
+			writeError(r.Context(), w, err)
+			return
+		}
+		if ok {
+			resources = append(resources, resource)
+		}
+	}
+
+	writeList(r.Context(), w, pg, resources)
+}
+
+func (h *handler) getGroup(w http.ResponseWriter, r *http.Request) {
+	p := principalFromContext(r.Context())
+
+	g, err := h.getManagedGroup(r.Context(), p, mux.Vars(r)["id"])
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	h.writeGroup(r.Context(), w, p, http.StatusOK, g)
+}
+
+func (h *handler) createGroup(w http.ResponseWriter, r *http.Request) {
+	p := principalFromContext(r.Context())
+
+	req := &group{}
+	if err := decodeBody(r, req); err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+	if req.DisplayName == "" {
+		writeError(r.Context(), w, invalidValue("displayName is required"))
+		return
+	}
+	if err := h.validateGroupName(r.Context(), p, "", req.DisplayName); err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	users, err := h.provisionableUsers(r.Context(), p)
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+	// unknown members are rejected before the group is created
+	if _, err := membershipUpdates(users, "", memberIDs(req.Members)); err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	g := &types.Group{
+		ID:                   xid.New().String(),
+		AccountID:            p.accountID,
+		Name:                 req.DisplayName,
+		Issued:               types.GroupIssuedIntegration,
+		IntegrationReference: integrationReference,
+		Peers:                []string{},
+	}
+	if err := h.accountManager.CreateGroup(r.Context(), p.accountID, p.userID, g); err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	if err := h.setMembers(r.Context(), p, g.ID, memberIDs(req.Members)); err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	w.Header().Set("Location", toGroupResource(g, nil, false).Meta.Location)
+	h.writeGroup(r.Context(), w, p, http.StatusCreated, g)
+}
+
+func (h *handler) replaceGroup(w http.ResponseWriter, r *http.Request) {
+	p := principalFromContext(r.Context())
+
+	g, err := h.getManagedGroup(r.Context(), p, mux.Vars(r)["id"])
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	req := &group{}
+	if err := decodeBody(r, req); err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	h.saveGroup(w, r, p, g, req.DisplayName, memberIDs(req.Members))
+}
+
+func (h *handler) patchGroup(w http.ResponseWriter, r *http.Request) {
+	p := principalFromContext(r.Context())
+
+	g, err := h.getManagedGroup(r.Context(), p, mux.Vars(r)["id"])
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	req := &patchRequest{}
+	if err := decodeBody(r, req); err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	users, err := h.provisionableUsers(r.Context(), p)
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	resource := toGroupResource(g, groupMembers(users, g.ID), true)
+	for _, op := range req.Operations {
+		if err := patchGroupResource(resource, op); err != nil {
+			writeError(r.Context(), w, err)
+			return
+		}
+	}
+
+	h.saveGroup(w, r, p, g, resource.DisplayName, memberIDs(resource.Members))
+}
+
+func (h *handler) saveGroup(w http.ResponseWriter, r *http.Request, p principal, g *types.Group, displayName string, members []string) {
+	if displayName != "" && displayName != g.Name {
+		if err := h.validateGroupName(r.Context(), p, g.ID, displayName); err != nil {
+			writeError(r.Context(), w, err)
+			return
+		}
+
+		update := g.Copy()
+		update.Name = displayName
+		if err := h.accountManager.UpdateGroup(r.Context(), p.accountID, p.userID, update); err != nil {
+			writeError(r.Context(), w, err)
+			return
+		}
+		g = update
+	}
+
+	if err := h.setMembers(r.Context(), p, g.ID, members); err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	h.writeGroup(r.Context(), w, p, http.StatusOK, g)
+}
+
+func (h *handler) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	p := principalFromContext(r.Context())
+
+	g, err := h.getManagedGroup(r.Context(), p, mux.Vars(r)["id"])
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	// a group can't be deleted while it is still an auto group of users
+	if err := h.setMembers(r.Context(), p, g.ID, nil); err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	if err := h.accountManager.DeleteGroup(r.Context(), p.accountID, p.userID, g.ID); err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) writeGroup(ctx context.Context, w http.ResponseWriter, p principal, code int, g *types.Group) {
+	users, err := h.provisionableUsers(ctx, p)
+	if err != nil {
+		writeError(ctx, w, err)
+		return
+	}
+	writeJSON(ctx, w, code, toGroupResource(g, groupMembers(users, g.ID), true))
+}
+
+// setMembers makes the group an auto group of exactly the given users. Peers of the users join and leave the group
+// through the regular user update when group propagation is enabled for the account.
+func (h *handler) setMembers(ctx context.Context, p principal, groupID string, members []string) error {
+	users, err := h.provisionableUsers(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	updates, err := membershipUpdates(users, groupID, members)
+	if err != nil {
+		return err
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	_, err = h.accountManager.SaveOrAddUsers(ctx, p.accountID, p.userID, updates, false)
+	return err
+}
+
+// membershipUpdates returns the user updates adding the group to the auto groups of the members and removing it from
+// the other users
+func membershipUpdates(users []*types.User, groupID string, members []string) ([]*types.User, error) {
+	wanted := make(map[string]struct{}, len(members))
+	for _, member := range members {
+		wanted[member] = struct{}{}
+	}
+
+	var updates []*types.User
+	for _, u := range users {
+		_, want := wanted[u.Id]
+		delete(wanted, u.Id)
+
+		if want == slices.Contains(u.AutoGroups, groupID) {
+			continue
+		}
+
+		update := u.Copy()
+		if want {
+			update.AutoGroups = append(update.AutoGroups, groupID)
+		} else {
+			update.AutoGroups = slices.DeleteFunc(update.AutoGroups, func(id string) bool { return id == groupID })
+		}
+		updates = append(updates, update)
+	}
+
+	for _, member := range members {
+		if _, unknown := wanted[member]; unknown {
+			return nil, invalidValue("member %s is not a user of the account", member)
+		}
+	}
+
+	return updates, nil
+}
+
+// validateGroupName rejects names of other groups, SCIM groups are looked up by their display name
+func (h *handler) validateGroupName(ctx context.Context, p principal, groupID, displayName string) error {
+	groups, err := h.accountManager.GetAllGroups(ctx, p.accountID, p.userID)
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		if g.ID != groupID && strings.EqualFold(g.Name, displayName) {
+			return status.Errorf(status.AlreadyExists, "group with name %s already exists", displayName)
+		}
+	}
+	return nil
+}
+
+// managedGroups returns the groups created through SCIM, sorted by ID for stable paging
+func (h *handler) managedGroups(ctx context.Context, p principal) ([]*types.Group, error) {
+	groups, err := h.accountManager.GetAllGroups(ctx, p.accountID, p.userID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups = slices.DeleteFunc(groups, func(g *types.Group) bool {
+		return !isManaged(g)
+	})
+	slices.SortFunc(groups, func(a, b *types.Group) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return groups, nil
+}
+
+func (h *handler) managedGroupsMap(ctx context.Context, p principal) (map[string]*types.Group, error) {
+	groups, err := h.managedGroups(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	groupsMap := make(map[string]*types.Group, len(groups))
+	for _, g := range groups {
+		groupsMap[g.ID] = g
+	}
+	return groupsMap, nil
+}
+
+func (h *handler) getManagedGroup(ctx context.Context, p principal, groupID string) (*types.Group, error) {
+	g, err := h.accountManager.GetGroup(ctx, p.accountID, groupID, p.userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isManaged(g) {
+		return nil, status.NewGroupNotFoundError(groupID)
+	}
+	return g, nil
+}
+
+func groupMembers(users []*types.User, groupID string) []*types.User {
+	var members []*types.User
+	for _, u := range users {
+		if slices.Contains(u.AutoGroups, groupID) {
+			members = append(members, u)
+		}
+	}
+	return members
+}
+
+func memberIDs(members []reference) []string {
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.Value)
+	}
+	return ids
+}
+
+// patchGroupResource applies a PATCH operation to a group resource. Attributes NetBird doesn't store are ignored.
+func patchGroupResource(g *group, op patchOperation) error {
+	operation := strings.ToLower(op.Op)
+	if operation != "add" && operation != "replace" && operation != "remove" {
+		return invalidValue("unsupported patch operation %q", op.Op)
+	}
+
+	if op.Path == "" {
+		if operation == "remove" {
+			return invalidValue("remove operations require a path")
+		}
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &values); err != nil {
+			return invalidValue("patch operations without a path require an object value")
+		}
+		for path, value := range values {
+			if err := patchGroupResource(g, patchOperation{Op: op.Op, Path: path, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	path := strings.ToLower(op.Path)
+
+	switch {
+	case path == "displayname":
+		if operation == "remove" {
+			return invalidValue("displayName can't be removed")
+		}
+		if err := json.Unmarshal(op.Value, &g.DisplayName); err != nil {
+			return invalidValue("expected a string value: %v", err)
+		}
+	case path == "members":
+		var members []reference
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &members); err != nil {
+				return invalidValue("invalid members: %v", err)
+			}
+		}
+
+		switch operation {
+		case "add":
+			for _, member := range members {
+				if !slices.ContainsFunc(g.Members, func(m reference) bool { return m.Value == member.Value }) {
+					g.Members = append(g.Members, member)
+				}
+			}
+		case "replace":
+			g.Members = members
+		case "remove":
+			// removing without a value removes all members
+			if len(op.Value) == 0 {
+				g.Members = nil
+			}
+			removeMembers(g, memberIDs(members))
+		}
+	case strings.HasPrefix(path, "members[") && strings.HasSuffix(path, "]"):
+		if operation != "remove" {
+			return invalidValue("unsupported patch path %q", op.Path)
+		}
+		// e.g. members[value eq "user-id"]
+		f, err := parseFilter(op.Path[len("members[") : len(op.Path)-1])
+		if err != nil {
+			return err
+		}
+		if f.attribute != "value" {
+			return invalidValue("unsupported patch path %q", op.Path)
+		}
+		removeMembers(g, []string{f.value})
+	}
+
+	return nil
+}
+
+func removeMembers(g *group, ids []string) {
+	g.Members = slices.DeleteFunc(g.Members, func(m reference) bool {
+		return slices.Contains(ids, m.Value)
+	})
+}
@@ -0,0 +1,241 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	"github.com/netbirdio/netbird/management/server/integration_reference"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+const (
+	schemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	schemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	schemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	schemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	schemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	schemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+
+	resourceTypeUser  = "User"
+	resourceTypeGroup = "Group"
+)
+
+// integrationReference marks the users and groups managed through SCIM
+var integrationReference = integration_reference.IntegrationReference{IntegrationType: scim.IntegrationType}
+
+type meta struct {
+	ResourceType string     `json:"resourceType"`
+	Location     string     `json:"location,omitempty"`
+	Created      *time.Time `json:"created,omitempty"`
+}
+
+type name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type reference struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+type user struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	UserName    string      `json:"userName"`
+	Name        *name       `json:"name,omitempty"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Emails      []email     `json:"emails,omitempty"`
+	Active      *bool       `json:"active,omitempty"`
+	Groups      []reference `json:"groups,omitempty"`
+	Meta        *meta       `json:"meta,omitempty"`
+
+	// storedName is set when the name attributes hold the single name NetBird stores, which a patch of the name
+	// components must replace instead of being combined with
+	storedName bool
+}
+
+// resetStoredName drops the stored name before the name components are patched
+func (u *user) resetStoredName() {
+	if !u.storedName {
+		return
+	}
+	if u.Name != nil && u.DisplayName == u.Name.Formatted {
+		u.DisplayName = ""
+	}
+	u.Name = nil
+	u.storedName = false
+}
+
+type group struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	DisplayName string      `json:"displayName"`
+	Members     []reference `json:"members,omitempty"`
+	Meta        *meta       `json:"meta,omitempty"`
+}
+
+type listResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+type patchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []patchOperation `json:"Operations"`
+}
+
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type errorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// userAttributes are the attributes of a SCIM user that are stored on a NetBird user
+type userAttributes struct {
+	Name   string
+	Email  string
+	Active bool
+}
+
+// attributes extracts the stored attributes of a user resource
+func (u *user) attributes() userAttributes {
+	attrs := userAttributes{
+		Name:   u.DisplayName,
+		Active: u.Active == nil || *u.Active,
+	}
+	if attrs.Name == "" && u.Name != nil {
+		attrs.Name = u.Name.Formatted
+		if attrs.Name == "" {
+			attrs.Name = strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+		}
+	}
+
+	for _, e := range u.Emails {
+		if e.Primary || attrs.Email == "" {
+			attrs.Email = e.Value
+		}
+	}
+	if attrs.Email == "" && strings.Contains(u.UserName, "@") {
+		attrs.Email = u.UserName
+	}
+	return attrs
+}
+
+// apply sets the attributes on a user and marks it as managed through SCIM
+func (a userAttributes) apply(u *types.User) {
+	if a.Name != "" {
+		u.Name = a.Name
+	}
+	if a.Email != "" {
+		u.Email = a.Email
+	}
+	u.Blocked = !a.Active
+	u.Issued = types.UserIssuedIntegration
+	u.IntegrationReference = integrationReference
+}
+
+func toUserResource(u *types.User, groups map[string]*types.Group) *user {
+	active := !u.Blocked
+	resource := &user{
+		Schemas:     []string{schemaUser},
+		ID:          u.Id,
+		ExternalID:  u.Id,
+		UserName:    u.Email,
+		DisplayName: u.Name,
+		Active:      &active,
+		Meta: &meta{
+			ResourceType: resourceTypeUser,
+			Location:     fmt.Sprintf("%s/Users/%s", scim.BasePath, u.Id),
+		},
+	}
+	if resource.UserName == "" {
+		resource.UserName = u.Id
+	}
+	if u.Name != "" {
+		resource.Name = &name{Formatted: u.Name}
+		resource.storedName = true
+	}
+	if u.Email != "" {
+		resource.Emails = []email{{Value: u.Email, Type: "work", Primary: true}}
+	}
+	if !u.CreatedAt.IsZero() {
+		created := u.CreatedAt.UTC()
+		resource.Meta.Created = &created
+	}
+
+	for _, groupID := range u.AutoGroups {
+		g, ok := groups[groupID]
+		if !ok {
+			continue
+		}
+		resource.Groups = append(resource.Groups, reference{
+			Value:   g.ID,
+			Display: g.Name,
+			Ref:     fmt.Sprintf("%s/Groups/%s", scim.BasePath, g.ID),
+		})
+	}
+	return resource
+}
+
+func toGroupResource(g *types.Group, members []*types.User, withMembers bool) *group {
+	resource := &group{
+		Schemas:     []string{schemaGroup},
+		ID:          g.ID,
+		DisplayName: g.Name,
+		Meta: &meta{
+			ResourceType: resourceTypeGroup,
+			Location:     fmt.Sprintf("%s/Groups/%s", scim.BasePath, g.ID),
+		},
+	}
+	if !withMembers {
+		return resource
+	}
+
+	resource.Members = make([]reference, 0, len(members))
+	for _, member := range members {
+		display := member.Name
+		if display == "" {
+			display = member.Email
+		}
+		resource.Members = append(resource.Members, reference{
+			Value:   member.Id,
+			Display: display,
+			Ref:     fmt.Sprintf("%s/Users/%s", scim.BasePath, member.Id),
+		})
+	}
+	return resource
+}
+
+// isManaged reports whether a group was created through SCIM. Other groups are not exposed to the identity provider.
+func isManaged(g *types.Group) bool {
+	return g.Issued == types.GroupIssuedIntegration && g.IntegrationReference.IntegrationType == scim.IntegrationType
+}
+
+// isProvisionable reports whether a user can be managed through SCIM
+func isProvisionable(u *types.User) bool {
+	return !u.IsServiceUser
+}
@@ -0,0 +1,249 @@
+// Package server implements the SCIM 2.0 protocol (RFC 7643, RFC 7644) identity providers use to provision
+// users and group memberships into an account.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	contentType = "application/scim+json"
+	// maxResults is the maximum number of resources returned in a single list response
+	maxResults = 1000
+)
+
+type contextKey struct{}
+
+// principal is the account and service user a provisioning request is executed as
+type principal struct {
+	accountID string
+	userID    string
+}
+
+type handler struct {
+	manager        scim.Manager
+	accountManager account.Manager
+}
+
+// RegisterEndpoints registers the SCIM resources on a router mounted at scim.BasePath. Requests are authenticated
+// with the SCIM token of the account, the router must not use the regular API authentication.
+func RegisterEndpoints(router *mux.Router, manager scim.Manager, accountManager account.Manager) {
+	h := &handler{
+		manager:        manager,
+		accountManager: accountManager,
+	}
+
+	router.Use(h.authenticate)
+
+	router.HandleFunc("/ServiceProviderConfig", h.getServiceProviderConfig).Methods("GET")
+	router.HandleFunc("/ResourceTypes", h.getResourceTypes).Methods("GET")
+
+	router.HandleFunc("/Users", h.listUsers).Methods("GET")
+	router.HandleFunc("/Users", h.createUser).Methods("POST")
+	router.HandleFunc("/Users/{id}", h.getUser).Methods("GET")
+	router.HandleFunc("/Users/{id}", h.replaceUser).Methods("PUT")
+	router.HandleFunc("/Users/{id}", h.patchUser).Methods("PATCH")
+	router.HandleFunc("/Users/{id}", h.deleteUser).Methods("DELETE")
+
+	router.HandleFunc("/Groups", h.listGroups).Methods("GET")
+	router.HandleFunc("/Groups", h.createGroup).Methods("POST")
+	router.HandleFunc("/Groups/{id}", h.getGroup).Methods("GET")
+	router.HandleFunc("/Groups/{id}", h.replaceGroup).Methods("PUT")
+	router.HandleFunc("/Groups/{id}", h.patchGroup).Methods("PATCH")
+	router.HandleFunc("/Groups/{id}", h.deleteGroup).Methods("DELETE")
+}
+
+func (h *handler) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		plainToken, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || plainToken == "" {
+			writeError(r.Context(), w, status.Errorf(status.Unauthorized, "missing bearer token"))
+			return
+		}
+
+		token, err := h.manager.Authenticate(r.Context(), strings.TrimSpace(plainToken))
+		if err != nil {
+			writeError(r.Context(), w, err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), contextKey{}, principal{accountID: token.AccountID, userID: token.UserID})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func principalFromContext(ctx context.Context) principal {
+	p, _ := ctx.Value(contextKey{}).(principal)
+	return p
+}
+
+func (h *handler) getServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	writeJSON(r.Context(), w, http.StatusOK, map[string]any{
+		"schemas":        []string{schemaServiceProviderConfig},
+		"patch":          map[string]any{"supported": true},
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": maxResults},
+		"changePassword": map[string]any{"supported": false},
+		"sort":           map[string]any{"supported": false},
+		"etag":           map[string]any{"supported": false},
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication with the SCIM token of the account",
+			"primary":     true,
+		}},
+	})
+}
+
+func (h *handler) getResourceTypes(w http.ResponseWriter, r *http.Request) {
+	resourceTypes := []any{
+		map[string]any{
+			"schemas":  []string{schemaResourceType},
+			"id":       resourceTypeUser,
+			"name":     resourceTypeUser,
+			"endpoint": "/Users",
+			"schema":   schemaUser,
+		},
+		map[string]any{
+			"schemas":  []string{schemaResourceType},
+			"id":       resourceTypeGroup,
+			"name":     resourceTypeGroup,
+			"endpoint": "/Groups",
+			"schema":   schemaGroup,
+		},
+	}
+	writeJSON(r.Context(), w, http.StatusOK, &listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: len(resourceTypes),
+		StartIndex:   1,
+		ItemsPerPage: len(resourceTypes),
+		Resources:    resourceTypes,
+	})
+}
+
+// page is the 1-based startIndex and count of a list request
+type page struct {
+	startIndex int
+	count      int
+}
+
+func parsePage(r *http.Request) (page, error) {
+	p := page{startIndex: 1, count: maxResults}
+
+	if v := r.URL.Query().Get("startIndex"); v != "" {
+		startIndex, err := strconv.Atoi(v)
+		if err != nil {
+			return p, status.Errorf(status.InvalidArgument, "invalid startIndex: %s", v)
+		}
+		p.startIndex = max(startIndex, 1)
+	}
+
+	if v := r.URL.Query().Get("count"); v != "" {
+		count, err := strconv.Atoi(v)
+		if err != nil {
+			return p, status.Errorf(status.InvalidArgument, "invalid count: %s", v)
+		}
+		p.count = min(max(count, 0), maxResults)
+	}
+
+	return p, nil
+}
+
+func writeList[T any](ctx context.Context, w http.ResponseWriter, p page, resources []T) {
+	start := min(p.startIndex-1, len(resources))
+	end := min(start+p.count, len(resources))
+
+	items := make([]any, 0, end-start)
+	for _, resource := range resources[start:end] {
+		items = append(items, resource)
+	}
+
+	writeJSON(ctx, w, http.StatusOK, &listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: len(resources),
+		StartIndex:   p.startIndex,
+		ItemsPerPage: len(items),
+		Resources:    items,
+	})
+}
+
+func decodeBody(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return status.Errorf(status.InvalidArgument, "couldn't parse JSON request: %v", err)
+	}
+	return nil
+}
+
+func writeJSON(ctx context.Context, w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.WithContext(ctx).Errorf("failed to encode SCIM response: %v", err)
+	}
+}
+
+// writeError writes an error in the SCIM error format, mapping the status types the same way the REST API does
+func writeError(ctx context.Context, w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	var scimType string
+	detail := "internal server error"
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok && len(joined.Unwrap()) > 0 {
+		err = joined.Unwrap()[0]
+		code = http.StatusBadRequest
+		detail = err.Error()
+	}
+
+	if sErr, ok := status.FromError(err); ok {
+		detail = sErr.Message
+		switch sErr.Type() {
+		case status.NotFound:
+			code = http.StatusNotFound
+		case status.AlreadyExists:
+			code = http.StatusConflict
+			scimType = "uniqueness"
+		case status.InvalidArgument, status.BadRequest, status.PreconditionFailed:
+			code = http.StatusBadRequest
+			scimType = "invalidValue"
+		case status.PermissionDenied:
+			code = http.StatusForbidden
+		case status.Unauthorized:
+			code = http.StatusUnauthorized
+		}
+	}
+
+	if code == http.StatusInternalServerError {
+		log.WithContext(ctx).Errorf("failed to handle SCIM request: %v", err)
+		detail = "internal server error"
+	}
+
+	writeJSON(ctx, w, code, &errorResponse{
+		Schemas:  []string{schemaError},
+		Status:   strconv.Itoa(code),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+func invalidValue(format string, a ...any) error {
+	return status.Errorf(status.InvalidArgument, format, a...)
+}
+
+// isNotFound reports whether err is a status error of type NotFound
+func isNotFound(err error) bool {
+	var sErr *status.Error
+	return errors.As(err, &sErr) && sErr.Type() == status.NotFound
+}
@@ -0,0 +1,420 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	testAccountID     = "test-account-id"
+	testServiceUserID = "scim-service-user"
+	testToken         = "test-token"
+)
+
+type fakeManager struct {
+	scim.Manager
+}
+
+func (m *fakeManager) Authenticate(_ context.Context, plainToken string) (*scim.Token, error) {
+	if plainToken != testToken {
+		return nil, status.Errorf(status.Unauthorized, "invalid SCIM token")
+	}
+	return &scim.Token{ID: "token-id", AccountID: testAccountID, UserID: testServiceUserID}, nil
+}
+
+// testAccount keeps the users and groups the SCIM handlers change through the account manager
+type testAccount struct {
+	users         map[string]*types.User
+	groups        map[string]*types.Group
+	deletedGroups []string
+}
+
+func newTestAccount() *testAccount {
+	return &testAccount{
+		users: map[string]*types.User{
+			testServiceUserID: {Id: testServiceUserID, AccountID: testAccountID, IsServiceUser: true, Role: types.UserRoleAdmin},
+			"existing":        {Id: "existing", AccountID: testAccountID, Role: types.UserRoleUser, Issued: types.UserIssuedAPI, AutoGroups: []string{}},
+			"other-account":   {Id: "other-account", AccountID: "other", Role: types.UserRoleUser},
+		},
+		groups: map[string]*types.Group{
+			"api-group": {ID: "api-group", AccountID: testAccountID, Name: "Engineering", Issued: types.GroupIssuedAPI},
+		},
+	}
+}
+
+func (a *testAccount) accountManager() *mock_server.MockAccountManager {
+	saveUser := func(update *types.User, addIfNotExists bool) error {
+		if _, ok := a.users[update.Id]; !ok && !addIfNotExists {
+			return status.NewUserNotFoundError(update.Id)
+		}
+		for _, groupID := range update.AutoGroups {
+			if _, ok := a.groups[groupID]; !ok {
+				return status.Errorf(status.InvalidArgument, "group %s doesn't exist", groupID)
+			}
+		}
+		a.users[update.Id] = update.Copy()
+		return nil
+	}
+
+	return &mock_server.MockAccountManager{
+		ListUsersFunc: func(_ context.Context, accountID string) ([]*types.User, error) {
+			var users []*types.User
+			for _, u := range a.users {
+				if u.AccountID == accountID {
+					users = append(users, u.Copy())
+				}
+			}
+			return users, nil
+		},
+		GetUserByIDFunc: func(_ context.Context, id string) (*types.User, error) {
+			u, ok := a.users[id]
+			if !ok {
+				return nil, status.NewUserNotFoundError(id)
+			}
+			return u.Copy(), nil
+		},
+		SaveOrAddUserFunc: func(_ context.Context, accountID, initiatorUserID string, update *types.User, addIfNotExists bool) (*types.UserInfo, error) {
+			if initiatorUserID != testServiceUserID {
+				return nil, status.NewPermissionDeniedError()
+			}
+			return &types.UserInfo{ID: update.Id}, saveUser(update, addIfNotExists)
+		},
+		SaveOrAddUsersFunc: func(_ context.Context, accountID, initiatorUserID string, updates []*types.User, addIfNotExists bool) ([]*types.UserInfo, error) {
+			for _, update := range updates {
+				if err := saveUser(update, addIfNotExists); err != nil {
+					return nil, err
+				}
+			}
+			return nil, nil
+		},
+		DeleteUserFunc: func(_ context.Context, accountID, initiatorUserID, targetUserID string) error {
+			delete(a.users, targetUserID)
+			return nil
+		},
+		GetAllGroupsFunc: func(_ context.Context, accountID, userID string) ([]*types.Group, error) {
+			var groups []*types.Group
+			for _, g := range a.groups {
+				groups = append(groups, g.Copy())
+			}
+			return groups, nil
+		},
+		GetGroupFunc: func(_ context.Context, accountID, groupID, userID string) (*types.Group, error) {
+			g, ok := a.groups[groupID]
+			if !ok {
+				return nil, status.NewGroupNotFoundError(groupID)
+			}
+			return g.Copy(), nil
+		},
+		SaveGroupFunc: func(_ context.Context, accountID, userID string, group *types.Group, create bool) error {
+			a.groups[group.ID] = group.Copy()
+			return nil
+		},
+		DeleteGroupFunc: func(_ context.Context, accountID, userID, groupID string) error {
+			for _, u := range a.users {
+				if slices.Contains(u.AutoGroups, groupID) {
+					return status.Errorf(status.PreconditionFailed, "group %s is linked to users", groupID)
+				}
+			}
+			delete(a.groups, groupID)
+			a.deletedGroups = append(a.deletedGroups, groupID)
+			return nil
+		},
+	}
+}
+
+func setupRouter(account *testAccount) *mux.Router {
+	router := mux.NewRouter()
+	RegisterEndpoints(router.PathPrefix(scim.BasePath).Subrouter(), &fakeManager{}, account.accountManager())
+	return router
+}
+
+func doRequest(t *testing.T, router *mux.Router, method, path string, body any) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, scim.BasePath+path, reader)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", contentType)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	var resp map[string]any
+	if recorder.Body.Len() > 0 {
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	}
+	return recorder, resp
+}
+
+func TestAuthentication(t *testing.T) {
+	router := setupRouter(newTestAccount())
+
+	for _, header := range []string{"", "Bearer wrong-token", "Basic " + testToken} {
+		req := httptest.NewRequest(http.MethodGet, scim.BasePath+"/Users", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, header)
+		assert.Equal(t, contentType, recorder.Header().Get("Content-Type"))
+
+		var resp errorResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		assert.Equal(t, []string{schemaError}, resp.Schemas)
+		assert.Equal(t, "401", resp.Status)
+	}
+}
+
+func TestUsers(t *testing.T) {
+	account := newTestAccount()
+	router := setupRouter(account)
+
+	t.Run("create", func(t *testing.T) {
+		recorder, resp := doRequest(t, router, http.MethodPost, "/Users", map[string]any{
+			"schemas":    []string{schemaUser},
+			"externalId": "idp-user-1",
+			"userName":   "alice@example.com",
+			"name":       map[string]any{"givenName": "Alice", "familyName": "Smith"},
+			"active":     true,
+		})
+		require.Equal(t, http.StatusCreated, recorder.Code, resp)
+		assert.Equal(t, "idp-user-1", resp["id"])
+		assert.Equal(t, scim.BasePath+"/Users/idp-user-1", recorder.Header().Get("Location"))
+
+		created := account.users["idp-user-1"]
+		require.NotNil(t, created)
+		assert.Equal(t, testAccountID, created.AccountID)
+		assert.Equal(t, types.UserRoleUser, created.Role)
+		assert.Equal(t, "Alice Smith", created.Name)
+		assert.Equal(t, "alice@example.com", created.Email)
+		assert.Equal(t, types.UserIssuedIntegration, created.Issued)
+		assert.Equal(t, scim.IntegrationType, created.IntegrationReference.IntegrationType)
+		assert.False(t, created.Blocked)
+	})
+
+	t.Run("create requires externalId", func(t *testing.T) {
+		recorder, resp := doRequest(t, router, http.MethodPost, "/Users", map[string]any{"userName": "bob@example.com"})
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, "invalidValue", resp["scimType"])
+	})
+
+	t.Run("create conflicts", func(t *testing.T) {
+		for _, id := range []string{"idp-user-1", "other-account", testServiceUserID} {
+			recorder, resp := doRequest(t, router, http.MethodPost, "/Users", map[string]any{"externalId": id, "userName": id})
+			assert.Equal(t, http.StatusConflict, recorder.Code, id)
+			assert.Equal(t, "uniqueness", resp["scimType"])
+		}
+	})
+
+	t.Run("create takes over an existing user", func(t *testing.T) {
+		recorder, _ := doRequest(t, router, http.MethodPost, "/Users", map[string]any{
+			"externalId": "existing",
+			"userName":   "existing@example.com",
+		})
+		require.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Equal(t, types.UserIssuedIntegration, account.users["existing"].Issued)
+		assert.Equal(t, "existing@example.com", account.users["existing"].Email)
+	})
+
+	t.Run("list with filter", func(t *testing.T) {
+		recorder, resp := doRequest(t, router, http.MethodGet, `/Users?filter=userName+eq+"ALICE@example.com"`, nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, float64(1), resp["totalResults"])
+		resources := resp["Resources"].([]any)
+		require.Len(t, resources, 1)
+		assert.Equal(t, "idp-user-1", resources[0].(map[string]any)["id"])
+
+		// service users and users of other accounts are not exposed
+		_, resp = doRequest(t, router, http.MethodGet, "/Users", nil)
+		assert.Equal(t, float64(2), resp["totalResults"])
+
+		recorder, _ = doRequest(t, router, http.MethodGet, "/Users/"+testServiceUserID, nil)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("patch deactivates", func(t *testing.T) {
+		recorder, resp := doRequest(t, router, http.MethodPatch, "/Users/idp-user-1", map[string]any{
+			"schemas": []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+			"Operations": []map[string]any{
+				{"op": "Replace", "path": "active", "value": "False"},
+				{"op": "replace", "value": map[string]any{"displayName": "Alice S."}},
+			},
+		})
+		require.Equal(t, http.StatusOK, recorder.Code, resp)
+		assert.Equal(t, false, resp["active"])
+		assert.True(t, account.users["idp-user-1"].Blocked)
+		assert.Equal(t, "Alice S.", account.users["idp-user-1"].Name)
+	})
+
+	t.Run("replace reactivates", func(t *testing.T) {
+		recorder, _ := doRequest(t, router, http.MethodPut, "/Users/idp-user-1", map[string]any{
+			"externalId": "idp-user-1",
+			"userName":   "alice@example.com",
+			"active":     true,
+		})
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.False(t, account.users["idp-user-1"].Blocked)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		recorder, _ := doRequest(t, router, http.MethodDelete, "/Users/idp-user-1", nil)
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.NotContains(t, account.users, "idp-user-1")
+
+		recorder, _ = doRequest(t, router, http.MethodDelete, "/Users/other-account", nil)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestGroups(t *testing.T) {
+	account := newTestAccount()
+	account.users["alice"] = &types.User{Id: "alice", AccountID: testAccountID, AutoGroups: []string{"api-group"}}
+	account.users["bob"] = &types.User{Id: "bob", AccountID: testAccountID, AutoGroups: []string{}}
+	router := setupRouter(account)
+
+	var groupID string
+
+	t.Run("create", func(t *testing.T) {
+		recorder, resp := doRequest(t, router, http.MethodPost, "/Groups", map[string]any{
+			"displayName": "Developers",
+			"members":     []map[string]any{{"value": "alice"}, {"value": "bob"}},
+		})
+		require.Equal(t, http.StatusCreated, recorder.Code, resp)
+		groupID = resp["id"].(string)
+		assert.Len(t, resp["members"], 2)
+
+		g := account.groups[groupID]
+		require.NotNil(t, g)
+		assert.Equal(t, types.GroupIssuedIntegration, g.Issued)
+		assert.Equal(t, scim.IntegrationType, g.IntegrationReference.IntegrationType)
+		assert.ElementsMatch(t, []string{"api-group", groupID}, account.users["alice"].AutoGroups)
+		assert.Equal(t, []string{groupID}, account.users["bob"].AutoGroups)
+	})
+
+	t.Run("create rejects existing names and unknown members", func(t *testing.T) {
+		recorder, _ := doRequest(t, router, http.MethodPost, "/Groups", map[string]any{"displayName": "engineering"})
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+
+		groups := len(account.groups)
+		recorder, _ = doRequest(t, router, http.MethodPost, "/Groups", map[string]any{
+			"displayName": "Ops",
+			"members":     []map[string]any{{"value": "unknown"}},
+		})
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Len(t, account.groups, groups)
+	})
+
+	t.Run("list only exposes SCIM groups", func(t *testing.T) {
+		_, resp := doRequest(t, router, http.MethodGet, "/Groups?excludedAttributes=members", nil)
+		assert.Equal(t, float64(1), resp["totalResults"])
+		group := resp["Resources"].([]any)[0].(map[string]any)
+		assert.Equal(t, groupID, group["id"])
+		assert.NotContains(t, group, "members")
+
+		recorder, _ := doRequest(t, router, http.MethodGet, "/Groups/api-group", nil)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("patch members", func(t *testing.T) {
+		recorder, resp := doRequest(t, router, http.MethodPatch, "/Groups/"+groupID, map[string]any{
+			"Operations": []map[string]any{
+				{"op": "remove", "path": `members[value eq "alice"]`},
+				{"op": "replace", "path": "displayName", "value": "Developers EU"},
+			},
+		})
+		require.Equal(t, http.StatusOK, recorder.Code, resp)
+		assert.Equal(t, "Developers EU", account.groups[groupID].Name)
+		assert.Equal(t, []string{"api-group"}, account.users["alice"].AutoGroups)
+		assert.Equal(t, []string{groupID}, account.users["bob"].AutoGroups)
+
+		recorder, _ = doRequest(t, router, http.MethodPatch, "/Groups/"+groupID, map[string]any{
+			"Operations": []map[string]any{{"op": "add", "path": "members", "value": []map[string]any{{"value": "alice"}}}},
+		})
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.ElementsMatch(t, []string{"api-group", groupID}, account.users["alice"].AutoGroups)
+	})
+
+	t.Run("delete removes the group from the users", func(t *testing.T) {
+		recorder, _ := doRequest(t, router, http.MethodDelete, "/Groups/"+groupID, nil)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.Equal(t, []string{groupID}, account.deletedGroups)
+		assert.Equal(t, []string{"api-group"}, account.users["alice"].AutoGroups)
+		assert.Empty(t, account.users["bob"].AutoGroups)
+	})
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   *filter
+		wantErr    bool
+	}{
+		{expression: "", expected: nil},
+		{expression: `userName eq "alice@example.com"`, expected: &filter{attribute: "username", value: "alice@example.com"}},
+		{expression: `externalId EQ "a b"`, expected: &filter{attribute: "externalid", value: "a b"}},
+		{expression: `displayName co "dev"`, wantErr: true},
+		{expression: "userName", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			f, err := parseFilter(tt.expression)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, f)
+		})
+	}
+}
+
+func TestPatchUserResource(t *testing.T) {
+	stored := &types.User{Id: "user", Name: "Alice Smith", Email: "alice@example.com"}
+
+	t.Run("name components replace the stored name", func(t *testing.T) {
+		resource := toUserResource(stored, nil)
+		require.NoError(t, patchUserResource(resource, patchOperation{Op: "replace", Path: "name.givenName", Value: json.RawMessage(`"Alicia"`)}))
+		require.NoError(t, patchUserResource(resource, patchOperation{Op: "replace", Path: "name.familyName", Value: json.RawMessage(`"Jones"`)}))
+		assert.Equal(t, "Alicia Jones", resource.attributes().Name)
+	})
+
+	t.Run("user name updates the derived email", func(t *testing.T) {
+		resource := toUserResource(stored, nil)
+		require.NoError(t, patchUserResource(resource, patchOperation{Op: "replace", Path: "userName", Value: json.RawMessage(`"alice@example.org"`)}))
+		assert.Equal(t, "alice@example.org", resource.attributes().Email)
+	})
+
+	t.Run("externalId can't change", func(t *testing.T) {
+		resource := toUserResource(stored, nil)
+		assert.Error(t, patchUserResource(resource, patchOperation{Op: "replace", Path: "externalId", Value: json.RawMessage(`"other"`)}))
+	})
+
+	t.Run("unsupported operation", func(t *testing.T) {
+		resource := toUserResource(stored, nil)
+		assert.Error(t, patchUserResource(resource, patchOperation{Op: "move", Path: "active", Value: json.RawMessage(`true`)}))
+	})
+}
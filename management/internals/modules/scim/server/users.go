@@ -0,0 +1,393 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+func (h *handler) listUsers(w http.ResponseWriter, r *http.Request) {
+	p := principalFromContext(r.Context())
+
+	pg, err := parsePage(r)
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+	f, err := parseFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	users, err := h.provisionableUsers(r.Context(), p)
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+	groups, err := h.managedGroupsMap(r.Context(), p)
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	resources := make([]*user, 0, len(users))
+	for _, u := range users {
+		resource := toUserResource(u, groups)
+		ok, err := f.matches(userFilterAttributes(resource))
+		if err != nil {
+			writeError(r.Context(), w, err)
+			return
+		}
+		if ok {
+			resources = append(resources, resource)
+		}
+	}
+
+	writeList(r.Context(), w, pg, resources)
+}
+
+func (h *handler) getUser(w http.ResponseWriter, r *http.Request) {
+	p := principalFromContext(r.Context())
+
+	u, err := h.getProvisionableUser(r.Context(), p, mux.Vars(r)["id"])
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	h.writeUser(r.Context(), w, p, http.StatusOK, u)
+}
+
+// createUser provisions a user. The externalId must be the user ID the identity provider issues in its tokens, so the
+// provisioned user is matched when they log in. An existing user of the account that is not yet managed through SCIM
+// is taken over.
+func (h *handler) createUser(w http.ResponseWriter, r *http.Request) {
+	p := principalFromContext(r.Context())
+
+	req := &user{}
+	if err := decodeBody(r, req); err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+	if req.ExternalID == "" {
+		writeError(r.Context(), w, invalidValue("externalId is required and must be the user ID issued by the identity provider"))
+		return
+	}
+
+	update, addIfNotExists, err := h.prepareNewUser(r.Context(), p, req)
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	if _, err := h.accountManager.SaveOrAddUser(r.Context(), p.accountID, p.userID, update, addIfNotExists); err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	u, err := h.getProvisionableUser(r.Context(), p, update.Id)
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	w.Header().Set("Location", toUserResource(u, nil).Meta.Location)
+	h.writeUser(r.Context(), w, p, http.StatusCreated, u)
+}
+
+func (h *handler) prepareNewUser(ctx context.Context, p principal, req *user) (*types.User, bool, error) {
+	attrs := req.attributes()
+
+	existing, err := h.accountManager.GetUserByID(ctx, req.ExternalID)
+	if err != nil && !isNotFound(err) {
+		return nil, false, err
+	}
+
+	if existing == nil {
+		update := &types.User{
+			Id:         req.ExternalID,
+			AccountID:  p.accountID,
+			Role:       types.UserRoleUser,
+			AutoGroups: []string{},
+			CreatedAt:  time.Now().UTC(),
+		}
+		attrs.apply(update)
+		return update, true, nil
+	}
+
+	if existing.AccountID != p.accountID || !isProvisionable(existing) {
+		return nil, false, status.Errorf(status.AlreadyExists, "user %s already exists", req.ExternalID)
+	}
+	if existing.Issued == types.UserIssuedIntegration && existing.IntegrationReference == integrationReference {
+		return nil, false, status.Errorf(status.AlreadyExists, "user %s is already provisioned", req.ExternalID)
+	}
+
+	update := existing.Copy()
+	attrs.apply(update)
+	return update, false, nil
+}
+
+func (h *handler) replaceUser(w http.ResponseWriter, r *http.Request) {
+	p := principalFromContext(r.Context())
+
+	existing, err := h.getProvisionableUser(r.Context(), p, mux.Vars(r)["id"])
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	req := &user{}
+	if err := decodeBody(r, req); err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+	if req.ExternalID != "" && req.ExternalID != existing.Id {
+		writeError(r.Context(), w, invalidValue("externalId can't be changed"))
+		return
+	}
+
+	h.saveUser(w, r, p, existing, req.attributes())
+}
+
+func (h *handler) patchUser(w http.ResponseWriter, r *http.Request) {
+	p := principalFromContext(r.Context())
+
+	existing, err := h.getProvisionableUser(r.Context(), p, mux.Vars(r)["id"])
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	req := &patchRequest{}
+	if err := decodeBody(r, req); err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	resource := toUserResource(existing, nil)
+	for _, op := range req.Operations {
+		if err := patchUserResource(resource, op); err != nil {
+			writeError(r.Context(), w, err)
+			return
+		}
+	}
+
+	h.saveUser(w, r, p, existing, resource.attributes())
+}
+
+func (h *handler) saveUser(w http.ResponseWriter, r *http.Request, p principal, existing *types.User, attrs userAttributes) {
+	update := existing.Copy()
+	attrs.apply(update)
+
+	// blocking the user expires their peers through the regular user update
+	if _, err := h.accountManager.SaveOrAddUser(r.Context(), p.accountID, p.userID, update, false); err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	u, err := h.getProvisionableUser(r.Context(), p, existing.Id)
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	h.writeUser(r.Context(), w, p, http.StatusOK, u)
+}
+
+func (h *handler) deleteUser(w http.ResponseWriter, r *http.Request) {
+	p := principalFromContext(r.Context())
+
+	u, err := h.getProvisionableUser(r.Context(), p, mux.Vars(r)["id"])
+	if err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	if err := h.accountManager.DeleteUser(r.Context(), p.accountID, p.userID, u.Id); err != nil {
+		writeError(r.Context(), w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) writeUser(ctx context.Context, w http.ResponseWriter, p principal, code int, u *types.User) {
+	groups, err := h.managedGroupsMap(ctx, p)
+	if err != nil {
+		writeError(ctx, w, err)
+		return
+	}
+	writeJSON(ctx, w, code, toUserResource(u, groups))
+}
+
+// provisionableUsers returns the users of the account that can be managed through SCIM, sorted by ID for stable paging
+func (h *handler) provisionableUsers(ctx context.Context, p principal) ([]*types.User, error) {
+	users, err := h.accountManager.ListUsers(ctx, p.accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	users = slices.DeleteFunc(users, func(u *types.User) bool {
+		return !isProvisionable(u)
+	})
+	slices.SortFunc(users, func(a, b *types.User) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+	return users, nil
+}
+
+func (h *handler) getProvisionableUser(ctx context.Context, p principal, userID string) (*types.User, error) {
+	u, err := h.accountManager.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.AccountID != p.accountID || !isProvisionable(u) {
+		return nil, status.NewUserNotFoundError(userID)
+	}
+	return u, nil
+}
+
+// patchUserResource applies a PATCH operation to a user resource. Attributes NetBird doesn't store are ignored.
+func patchUserResource(u *user, op patchOperation) error {
+	operation := strings.ToLower(op.Op)
+	if operation != "add" && operation != "replace" && operation != "remove" {
+		return invalidValue("unsupported patch operation %q", op.Op)
+	}
+
+	if op.Path == "" {
+		if operation == "remove" {
+			return invalidValue("remove operations require a path")
+		}
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &values); err != nil {
+			return invalidValue("patch operations without a path require an object value")
+		}
+		for path, value := range values {
+			if err := patchUserResource(u, patchOperation{Op: op.Op, Path: path, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	path := strings.ToLower(op.Path)
+	remove := operation == "remove"
+
+	switch {
+	case path == "active":
+		if remove {
+			return invalidValue("active can't be removed")
+		}
+		active, err := parseBool(op.Value)
+		if err != nil {
+			return err
+		}
+		u.Active = &active
+	case path == "username":
+		value, err := patchString(op.Value, remove)
+		if err != nil {
+			return err
+		}
+		// the email is derived from the user name when no emails are provisioned separately
+		if len(u.Emails) == 1 && u.Emails[0].Value == u.UserName {
+			u.Emails = nil
+		}
+		u.UserName = value
+	case path == "displayname":
+		value, err := patchString(op.Value, remove)
+		if err != nil {
+			return err
+		}
+		u.DisplayName = value
+	case path == "name":
+		u.resetStoredName()
+		u.Name = nil
+		if !remove {
+			u.Name = &name{}
+			if err := json.Unmarshal(op.Value, u.Name); err != nil {
+				return invalidValue("invalid name: %v", err)
+			}
+		}
+	case strings.HasPrefix(path, "name."):
+		value, err := patchString(op.Value, remove)
+		if err != nil {
+			return err
+		}
+		u.resetStoredName()
+		if u.Name == nil {
+			u.Name = &name{}
+		}
+		switch strings.TrimPrefix(path, "name.") {
+		case "formatted":
+			u.Name.Formatted = value
+		case "givenname":
+			u.Name.GivenName = value
+		case "familyname":
+			u.Name.FamilyName = value
+		}
+	case path == "emails":
+		u.Emails = nil
+		if !remove {
+			if err := json.Unmarshal(op.Value, &u.Emails); err != nil {
+				return invalidValue("invalid emails: %v", err)
+			}
+		}
+	case strings.HasPrefix(path, "emails[") && strings.HasSuffix(path, "].value"):
+		// e.g. emails[type eq "work"].value, NetBird stores a single email
+		value, err := patchString(op.Value, remove)
+		if err != nil {
+			return err
+		}
+		u.Emails = nil
+		if value != "" {
+			u.Emails = []email{{Value: value, Primary: true}}
+		}
+	case path == "externalid":
+		value, err := patchString(op.Value, remove)
+		if err != nil {
+			return err
+		}
+		if value != u.ExternalID {
+			return invalidValue("externalId can't be changed")
+		}
+	}
+
+	return nil
+}
+
+func patchString(value json.RawMessage, remove bool) (string, error) {
+	if remove {
+		return "", nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return "", invalidValue("expected a string value: %v", err)
+	}
+	return s, nil
+}
+
+// parseBool parses a boolean patch value. Some identity providers send booleans as strings, e.g. "False".
+func parseBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(strings.ToLower(s)); err == nil {
+			return b, nil
+		}
+	}
+	return false, invalidValue("expected a boolean value: %s", string(value))
+}
@@ -0,0 +1,107 @@
+package scim
+
+import (
+	"crypto/sha256"
+	b64 "encoding/base64"
+	"fmt"
+	"hash/crc32"
+	"time"
+
+	b "github.com/hashicorp/go-secure-stdlib/base62"
+	"github.com/rs/xid"
+
+	"github.com/netbirdio/netbird/base62"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+const (
+	// TokenPrefix is the prefix of SCIM provisioning tokens, distinguishing them from personal access tokens
+	TokenPrefix = "nbs_"
+	// TokenSecretLength number of characters used for the secret inside the token
+	TokenSecretLength = 30
+	// TokenChecksumLength number of characters used for the encoded checksum of the secret inside the token
+	TokenChecksumLength = 6
+	// TokenLength total number of characters used for the token
+	TokenLength = 40
+
+	// IntegrationType is the integration type set on users and groups provisioned through SCIM
+	IntegrationType = "scim"
+)
+
+// Token authenticates the SCIM provisioning requests of an account. Each account has at most one token.
+// Changes made through SCIM are executed as the dedicated service user the token belongs to.
+type Token struct {
+	ID        string `gorm:"primaryKey"`
+	AccountID string `gorm:"uniqueIndex"`
+	// UserID is the service user the provisioning requests are executed as
+	UserID      string
+	HashedToken string `gorm:"index"`
+	CreatedBy   string
+	CreatedAt   time.Time
+	LastUsed    *time.Time
+}
+
+// TokenGenerated holds a new Token and the plain text version of it
+type TokenGenerated struct {
+	PlainToken string
+	Token
+}
+
+// NewToken generates a new token for the given service user. The plain token is returned only once, only the hash is stored.
+func NewToken(accountID, userID, createdBy string) (*TokenGenerated, error) {
+	secret, err := b.Random(TokenSecretLength)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := crc32.ChecksumIEEE([]byte(secret))
+	plainToken := TokenPrefix + secret + fmt.Sprintf("%06s", base62.Encode(checksum))
+
+	return &TokenGenerated{
+		PlainToken: plainToken,
+		Token: Token{
+			ID:          xid.New().String(),
+			AccountID:   accountID,
+			UserID:      userID,
+			HashedToken: HashToken(plainToken),
+			CreatedBy:   createdBy,
+			CreatedAt:   time.Now().UTC(),
+		},
+	}, nil
+}
+
+// HashToken returns the hash of a plain token as stored in the database
+func HashToken(plainToken string) string {
+	hashedToken := sha256.Sum256([]byte(plainToken))
+	return b64.StdEncoding.EncodeToString(hashedToken[:])
+}
+
+// ValidateTokenFormat checks the prefix, length and checksum of a plain token before it is looked up
+func ValidateTokenFormat(plainToken string) error {
+	if len(plainToken) != TokenLength {
+		return fmt.Errorf("token has an invalid length")
+	}
+	if plainToken[:len(TokenPrefix)] != TokenPrefix {
+		return fmt.Errorf("token has an invalid prefix")
+	}
+
+	secret := plainToken[len(TokenPrefix) : len(TokenPrefix)+TokenSecretLength]
+	checksum, err := base62.Decode(plainToken[len(TokenPrefix)+TokenSecretLength:])
+	if err != nil {
+		return fmt.Errorf("token checksum decoding failed: %w", err)
+	}
+	if checksum != crc32.ChecksumIEEE([]byte(secret)) {
+		return fmt.Errorf("token checksum does not match")
+	}
+	return nil
+}
+
+func (t *Token) ToAPIResponse() *api.SCIMToken {
+	return &api.SCIMToken{
+		Id:        t.ID,
+		UserId:    t.UserID,
+		CreatedBy: t.CreatedBy,
+		CreatedAt: t.CreatedAt,
+		LastUsed:  t.LastUsed,
+	}
+}
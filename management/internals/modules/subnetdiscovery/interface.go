@@ -0,0 +1,18 @@
+package subnetdiscovery
+
+import (
+	"context"
+)
+
+type Manager interface {
+	ListSubnets(ctx context.Context, accountID, userID string) ([]*DiscoveredSubnet, error)
+	// ApproveSubnet adds a pending subnet as a resource of its network. An empty name defaults to the subnet.
+	ApproveSubnet(ctx context.Context, accountID, userID, subnetID, name string, groupIDs []string) (*DiscoveredSubnet, error)
+	// DismissSubnet stops suggesting a pending subnet
+	DismissSubnet(ctx context.Context, accountID, userID, subnetID string) (*DiscoveredSubnet, error)
+
+	// DiscoverSubnets updates the discovered subnets of all accounts with subnet discovery enabled
+	DiscoverSubnets(ctx context.Context) error
+	// Start discovers subnets periodically until the context is done
+	Start(ctx context.Context)
+}
@@ -0,0 +1,111 @@
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netbirdio/netbird/management/internals/modules/subnetdiscovery"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/http/util"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type handler struct {
+	manager subnetdiscovery.Manager
+}
+
+func RegisterEndpoints(router *mux.Router, manager subnetdiscovery.Manager) {
+	h := &handler{
+		manager: manager,
+	}
+
+	router.HandleFunc("/discovered-subnets", h.listSubnets).Methods("GET", "OPTIONS")
+	router.HandleFunc("/discovered-subnets/{subnetId}/approve", h.approveSubnet).Methods("POST", "OPTIONS")
+	router.HandleFunc("/discovered-subnets/{subnetId}/dismiss", h.dismissSubnet).Methods("POST", "OPTIONS")
+}
+
+func (h *handler) listSubnets(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	subnets, err := h.manager.ListSubnets(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	apiSubnets := make([]*api.DiscoveredSubnet, 0, len(subnets))
+	for _, subnet := range subnets {
+		apiSubnets = append(apiSubnets, subnet.ToAPIResponse())
+	}
+
+	util.WriteJSONObject(r.Context(), w, apiSubnets)
+}
+
+func (h *handler) approveSubnet(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	subnetID := mux.Vars(r)["subnetId"]
+	if subnetID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "subnet ID is required"), w)
+		return
+	}
+
+	// the request body is optional
+	var req api.PostApiDiscoveredSubnetsSubnetIdApproveJSONRequestBody
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	var name string
+	if req.Name != nil {
+		name = *req.Name
+	}
+	var groupIDs []string
+	if req.Groups != nil {
+		groupIDs = *req.Groups
+	}
+
+	subnet, err := h.manager.ApproveSubnet(r.Context(), userAuth.AccountId, userAuth.UserId, subnetID, name, groupIDs)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, subnet.ToAPIResponse())
+}
+
+func (h *handler) dismissSubnet(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	subnetID := mux.Vars(r)["subnetId"]
+	if subnetID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "subnet ID is required"), w)
+		return
+	}
+
+	subnet, err := h.manager.DismissSubnet(r.Context(), userAuth.AccountId, userAuth.UserId, subnetID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, subnet.ToAPIResponse())
+}
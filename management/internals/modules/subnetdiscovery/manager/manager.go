@@ -0,0 +1,310 @@
+package manager
+
+import (
+	"context"
+	"net/netip"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/internals/modules/subnetdiscovery"
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/networks/resources"
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// discoveryInterval is how often the subnets reported by the routing peers are checked
+const discoveryInterval = 5 * time.Minute
+
+// subnetKey identifies a discovered subnet within an account
+type subnetKey struct {
+	networkID string
+	prefix    netip.Prefix
+}
+
+type managerImpl struct {
+	store              store.Store
+	accountManager     account.Manager
+	permissionsManager permissions.Manager
+	resourcesManager   resources.Manager
+}
+
+func NewManager(store store.Store, accountManager account.Manager, permissionsManager permissions.Manager, resourcesManager resources.Manager) subnetdiscovery.Manager {
+	return &managerImpl{
+		store:              store,
+		accountManager:     accountManager,
+		permissionsManager: permissionsManager,
+		resourcesManager:   resourcesManager,
+	}
+}
+
+func (m *managerImpl) ListSubnets(ctx context.Context, accountID, userID string) ([]*subnetdiscovery.DiscoveredSubnet, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetDiscoveredSubnets(ctx, store.LockingStrengthNone, accountID)
+}
+
+func (m *managerImpl) ApproveSubnet(ctx context.Context, accountID, userID, subnetID, name string, groupIDs []string) (*subnetdiscovery.DiscoveredSubnet, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Create); err != nil {
+		return nil, err
+	}
+
+	subnet, err := m.getPendingSubnet(ctx, accountID, subnetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = m.approve(ctx, subnet, userID, name, groupIDs); err != nil {
+		return nil, err
+	}
+	return subnet, nil
+}
+
+func (m *managerImpl) DismissSubnet(ctx context.Context, accountID, userID, subnetID string) (*subnetdiscovery.DiscoveredSubnet, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Update); err != nil {
+		return nil, err
+	}
+
+	subnet, err := m.getPendingSubnet(ctx, accountID, subnetID)
+	if err != nil {
+		return nil, err
+	}
+
+	subnet.Status = subnetdiscovery.StatusDismissed
+	subnet.DecidedBy = userID
+	if err = m.store.SaveDiscoveredSubnet(ctx, subnet); err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, subnet.ID, accountID, activity.DiscoveredSubnetDismissed, subnet.EventMeta())
+
+	return subnet, nil
+}
+
+// DiscoverSubnets updates the discovered subnets of every account separately, so a failing account doesn't block
+// the others
+func (m *managerImpl) DiscoverSubnets(ctx context.Context) error {
+	accountIDs, err := m.store.GetSubnetDiscoveryAccountIDs(ctx, store.LockingStrengthNone)
+	if err != nil {
+		return err
+	}
+
+	for _, accountID := range accountIDs {
+		if err = m.discoverAccountSubnets(ctx, accountID); err != nil {
+			log.WithContext(ctx).Errorf("failed to discover subnets of account %s: %v", accountID, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *managerImpl) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(discoveryInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := m.DiscoverSubnets(ctx); err != nil {
+				log.WithContext(ctx).Errorf("failed to discover subnets: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// discoverAccountSubnets stores the subnets reported by the routing peers of the account. New subnets are
+// approved right away if the account auto-approves them. Pending subnets that are no longer reported are removed,
+// approved and dismissed ones are kept.
+func (m *managerImpl) discoverAccountSubnets(ctx context.Context, accountID string) error {
+	settings, err := m.store.GetAccountSettings(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return err
+	}
+
+	network, err := m.store.GetAccountNetwork(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return err
+	}
+	accountNetwork, _ := netip.ParsePrefix(network.Net.String())
+
+	routers, err := m.store.GetNetworkRoutersByAccountID(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return err
+	}
+
+	networkResources, err := m.store.GetNetworkResourcesByAccountID(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return err
+	}
+
+	routingPeers, err := m.getRoutingPeers(ctx, accountID, routers)
+	if err != nil {
+		return err
+	}
+
+	candidates := subnetdiscovery.FindCandidates(routers, routingPeers, networkResources, settings.GetSubnetDiscoveryAllowlist(), accountNetwork)
+
+	subnets, err := m.store.GetDiscoveredSubnets(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[subnetKey]*subnetdiscovery.DiscoveredSubnet, len(subnets))
+	for _, subnet := range subnets {
+		known[subnetKey{subnet.NetworkID, subnet.Prefix}] = subnet
+	}
+
+	now := time.Now().UTC()
+	reported := make(map[subnetKey]struct{}, len(candidates))
+	for _, candidate := range candidates {
+		key := subnetKey{candidate.NetworkID, candidate.Prefix}
+		reported[key] = struct{}{}
+
+		subnet, ok := known[key]
+		if !ok {
+			subnet = subnetdiscovery.NewDiscoveredSubnet(accountID, candidate, now)
+			if settings.SubnetDiscoveryAutoApprove {
+				if err = m.approve(ctx, subnet, activity.SystemInitiator, "", nil); err != nil {
+					log.WithContext(ctx).Errorf("failed to approve discovered subnet %s of account %s: %v", subnet.Prefix, accountID, err)
+				}
+				continue
+			}
+		} else {
+			subnet.PeerID = candidate.PeerID
+			subnet.LastSeenAt = now
+		}
+
+		if err = m.store.SaveDiscoveredSubnet(ctx, subnet); err != nil {
+			return err
+		}
+	}
+
+	for key, subnet := range known {
+		if _, ok := reported[key]; ok || subnet.Status != subnetdiscovery.StatusPending {
+			continue
+		}
+		if err = m.store.DeleteDiscoveredSubnet(ctx, accountID, subnet.ID); err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getRoutingPeers returns the peers of each network router, either the router peer or the peers of its groups
+func (m *managerImpl) getRoutingPeers(ctx context.Context, accountID string, routers []*routerTypes.NetworkRouter) (map[string][]*nbpeer.Peer, error) {
+	var groupIDs []string
+	for _, router := range routers {
+		groupIDs = append(groupIDs, router.PeerGroups...)
+	}
+
+	groups, err := m.store.GetGroupsByIDs(ctx, store.LockingStrengthNone, accountID, groupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	routerPeerIDs := make(map[string][]string, len(routers))
+	var peerIDs []string
+	for _, router := range routers {
+		ids := routerPeerIDs[router.ID]
+		if router.Peer != "" {
+			ids = append(ids, router.Peer)
+		}
+		for _, groupID := range router.PeerGroups {
+			if group, ok := groups[groupID]; ok {
+				ids = append(ids, group.Peers...)
+			}
+		}
+		routerPeerIDs[router.ID] = ids
+		peerIDs = append(peerIDs, ids...)
+	}
+
+	peers, err := m.store.GetPeersByIDs(ctx, store.LockingStrengthNone, accountID, peerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	routingPeers := make(map[string][]*nbpeer.Peer, len(routers))
+	for routerID, ids := range routerPeerIDs {
+		for _, id := range ids {
+			if peer, ok := peers[id]; ok {
+				routingPeers[routerID] = append(routingPeers[routerID], peer)
+			}
+		}
+	}
+	return routingPeers, nil
+}
+
+// approve creates a network resource for the subnet and stores it as approved
+func (m *managerImpl) approve(ctx context.Context, subnet *subnetdiscovery.DiscoveredSubnet, userID, name string, groupIDs []string) error {
+	if name == "" {
+		name = subnet.ResourceName()
+	}
+
+	resource, err := m.resourcesManager.CreateResource(ctx, userID, &resourceTypes.NetworkResource{
+		AccountID: subnet.AccountID,
+		NetworkID: subnet.NetworkID,
+		Name:      name,
+		Address:   subnet.Prefix.String(),
+		GroupIDs:  groupIDs,
+		Enabled:   true,
+	})
+	if err != nil {
+		return err
+	}
+
+	subnet.Status = subnetdiscovery.StatusApproved
+	subnet.ResourceID = resource.ID
+	if userID != activity.SystemInitiator {
+		subnet.DecidedBy = userID
+	}
+	if err = m.store.SaveDiscoveredSubnet(ctx, subnet); err != nil {
+		return err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, subnet.ID, subnet.AccountID, activity.DiscoveredSubnetApproved, subnet.EventMeta())
+
+	return nil
+}
+
+func (m *managerImpl) getPendingSubnet(ctx context.Context, accountID, subnetID string) (*subnetdiscovery.DiscoveredSubnet, error) {
+	subnet, err := m.store.GetDiscoveredSubnetByID(ctx, store.LockingStrengthNone, accountID, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	if subnet.Status != subnetdiscovery.StatusPending {
+		return nil, status.Errorf(status.PreconditionFailed, "discovered subnet %s is %s", subnetID, subnet.Status)
+	}
+	return subnet, nil
+}
+
+func (m *managerImpl) validatePermissions(ctx context.Context, accountID, userID string, operation operations.Operation) error {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Networks, operation)
+	if err != nil {
+		return status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return status.NewPermissionDeniedError()
+	}
+	return nil
+}
+
+func isNotFound(err error) bool {
+	sErr, ok := status.FromError(err)
+	return ok && sErr.Type() == status.NotFound
+}
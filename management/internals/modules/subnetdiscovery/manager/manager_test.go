@@ -0,0 +1,228 @@
+package manager
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/subnetdiscovery"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/groups"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	"github.com/netbirdio/netbird/management/server/networks/resources"
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
+	networkTypes "github.com/netbirdio/netbird/management/server/networks/types"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	testAccountID = "account-id"
+	adminID       = "admin-id"
+	regularUserID = "user-id"
+	networkID     = "network-id"
+	routerPeerID  = "router-peer-id"
+	otherPeerID   = "other-peer-id"
+)
+
+func setupTest(t *testing.T, autoApprove bool) (*managerImpl, store.Store, *mock_server.MockAccountManager, func()) {
+	t.Helper()
+
+	ctx := context.Background()
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+
+	err = testStore.SaveAccount(ctx, &types.Account{
+		Id:      testAccountID,
+		Network: types.NewNetwork(),
+		Users: map[string]*types.User{
+			adminID:       {Id: adminID, AccountID: testAccountID, Role: types.UserRoleAdmin},
+			regularUserID: {Id: regularUserID, AccountID: testAccountID, Role: types.UserRoleUser},
+		},
+		Peers: map[string]*nbpeer.Peer{
+			routerPeerID: {
+				ID: routerPeerID, AccountID: testAccountID, Key: "router-key", IP: net.IP{100, 64, 0, 1}, DNSLabel: "router", Status: &nbpeer.PeerStatus{},
+				Meta: nbpeer.PeerSystemMeta{
+					NetworkAddresses: []nbpeer.NetworkAddress{
+						{NetIP: netip.MustParsePrefix("192.168.1.10/24")},
+						{NetIP: netip.MustParsePrefix("203.0.113.10/24")},
+					},
+					DiscoveredSubnets: []string{"10.20.0.0/16", "10.30.0.0/16"},
+				},
+			},
+			otherPeerID: {
+				ID: otherPeerID, AccountID: testAccountID, Key: "other-key", IP: net.IP{100, 64, 0, 2}, DNSLabel: "other", Status: &nbpeer.PeerStatus{},
+				Meta: nbpeer.PeerSystemMeta{DiscoveredSubnets: []string{"10.40.0.0/16"}},
+			},
+		},
+		Groups: map[string]*types.Group{
+			"all": {ID: "all", AccountID: testAccountID, Name: "All", Peers: []string{routerPeerID, otherPeerID}},
+		},
+		Networks: []*networkTypes.Network{
+			{ID: networkID, AccountID: testAccountID, Name: "office"},
+		},
+		NetworkRouters: []*routerTypes.NetworkRouter{
+			{ID: "router-id", NetworkID: networkID, AccountID: testAccountID, Peer: routerPeerID, Enabled: true},
+		},
+		NetworkResources: []*resourceTypes.NetworkResource{
+			{ID: "existing-id", NetworkID: networkID, AccountID: testAccountID, Name: "servers", Type: resourceTypes.Subnet, Address: "10.30.0.0/16", Prefix: netip.MustParsePrefix("10.30.0.0/16"), Enabled: true},
+		},
+		Settings: &types.Settings{
+			SubnetDiscoveryEnabled:     true,
+			SubnetDiscoveryAutoApprove: autoApprove,
+		},
+		DNSSettings: types.DNSSettings{DisabledManagementGroups: []string{}},
+	})
+	require.NoError(t, err)
+
+	mockAccountManager := &mock_server.MockAccountManager{}
+	permissionsManager := permissions.NewManager(testStore)
+	groupsManager := groups.NewManager(testStore, permissionsManager, mockAccountManager)
+
+	manager := &managerImpl{
+		store:              testStore,
+		accountManager:     mockAccountManager,
+		permissionsManager: permissionsManager,
+		resourcesManager:   resources.NewManager(testStore, permissionsManager, groupsManager, mockAccountManager),
+	}
+
+	return manager, testStore, mockAccountManager, cleanup
+}
+
+func subnetsByPrefix(t *testing.T, manager *managerImpl) map[string]*subnetdiscovery.DiscoveredSubnet {
+	t.Helper()
+	subnets, err := manager.ListSubnets(context.Background(), testAccountID, adminID)
+	require.NoError(t, err)
+
+	byPrefix := make(map[string]*subnetdiscovery.DiscoveredSubnet, len(subnets))
+	for _, subnet := range subnets {
+		byPrefix[subnet.Prefix.String()] = subnet
+	}
+	return byPrefix
+}
+
+func TestManagerImpl_DiscoverSubnets(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, mockAccountManager, cleanup := setupTest(t, false)
+	defer cleanup()
+
+	var events []activity.ActivityDescriber
+	mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+		events = append(events, activityID)
+	}
+
+	require.NoError(t, manager.DiscoverSubnets(ctx))
+
+	subnets := subnetsByPrefix(t, manager)
+	require.Len(t, subnets, 2, "only allowed subnets of routing peers that aren't resources should be suggested")
+	require.Contains(t, subnets, "192.168.1.0/24")
+	require.Contains(t, subnets, "10.20.0.0/16")
+	assert.Equal(t, subnetdiscovery.StatusPending, subnets["10.20.0.0/16"].Status)
+	assert.Equal(t, routerPeerID, subnets["10.20.0.0/16"].PeerID)
+	assert.Equal(t, networkID, subnets["10.20.0.0/16"].NetworkID)
+
+	_, err := manager.ListSubnets(ctx, testAccountID, regularUserID)
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+
+	_, err = manager.DismissSubnet(ctx, testAccountID, regularUserID, subnets["192.168.1.0/24"].ID)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+
+	dismissed, err := manager.DismissSubnet(ctx, testAccountID, adminID, subnets["192.168.1.0/24"].ID)
+	require.NoError(t, err)
+	assert.Equal(t, subnetdiscovery.StatusDismissed, dismissed.Status)
+	assert.Contains(t, events, activity.DiscoveredSubnetDismissed)
+
+	peer, err := testStore.GetPeerByID(ctx, store.LockingStrengthNone, testAccountID, routerPeerID)
+	require.NoError(t, err)
+	peer.Meta.NetworkAddresses = nil
+	peer.Meta.DiscoveredSubnets = nil
+	require.NoError(t, testStore.SavePeer(ctx, testAccountID, peer))
+
+	require.NoError(t, manager.DiscoverSubnets(ctx))
+
+	subnets = subnetsByPrefix(t, manager)
+	assert.Len(t, subnets, 1, "pending subnets that are no longer reported should be removed")
+	assert.Equal(t, subnetdiscovery.StatusDismissed, subnets["192.168.1.0/24"].Status, "dismissed subnets should be kept")
+}
+
+func TestManagerImpl_ApproveSubnet(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, mockAccountManager, cleanup := setupTest(t, false)
+	defer cleanup()
+
+	var events []activity.ActivityDescriber
+	mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+		events = append(events, activityID)
+	}
+
+	require.NoError(t, manager.DiscoverSubnets(ctx))
+	subnet := subnetsByPrefix(t, manager)["10.20.0.0/16"]
+
+	_, err := manager.ApproveSubnet(ctx, testAccountID, regularUserID, subnet.ID, "", nil)
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+
+	approved, err := manager.ApproveSubnet(ctx, testAccountID, adminID, subnet.ID, "lab", []string{"all"})
+	require.NoError(t, err)
+	assert.Equal(t, subnetdiscovery.StatusApproved, approved.Status)
+	assert.Equal(t, adminID, approved.DecidedBy)
+	assert.Contains(t, events, activity.DiscoveredSubnetApproved)
+
+	resource, err := testStore.GetNetworkResourceByID(ctx, store.LockingStrengthNone, testAccountID, approved.ResourceID)
+	require.NoError(t, err)
+	assert.Equal(t, "lab", resource.Name)
+	assert.Equal(t, netip.MustParsePrefix("10.20.0.0/16"), resource.Prefix)
+	assert.Equal(t, networkID, resource.NetworkID)
+
+	_, err = manager.ApproveSubnet(ctx, testAccountID, adminID, subnet.ID, "", nil)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PreconditionFailed, s.Type())
+
+	_, err = manager.ApproveSubnet(ctx, testAccountID, adminID, "unknown", "", nil)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.NotFound, s.Type())
+
+	require.NoError(t, manager.DiscoverSubnets(ctx))
+	subnets := subnetsByPrefix(t, manager)
+	assert.Len(t, subnets, 2)
+	assert.Equal(t, subnetdiscovery.StatusApproved, subnets["10.20.0.0/16"].Status, "approved subnets should be kept")
+}
+
+func TestManagerImpl_AutoApprove(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, _, cleanup := setupTest(t, true)
+	defer cleanup()
+
+	require.NoError(t, manager.DiscoverSubnets(ctx))
+
+	subnets := subnetsByPrefix(t, manager)
+	require.Len(t, subnets, 2)
+	for _, subnet := range subnets {
+		assert.Equal(t, subnetdiscovery.StatusApproved, subnet.Status)
+		assert.Empty(t, subnet.DecidedBy)
+
+		resource, err := testStore.GetNetworkResourceByID(ctx, store.LockingStrengthNone, testAccountID, subnet.ResourceID)
+		require.NoError(t, err)
+		assert.Equal(t, subnet.Prefix.String(), resource.Name)
+	}
+}
@@ -0,0 +1,171 @@
+package subnetdiscovery
+
+import (
+	"net/netip"
+	"slices"
+	"time"
+
+	"github.com/rs/xid"
+
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+// Status is the state of a discovered subnet
+type Status string
+
+const (
+	// StatusPending subnets are suggested to the admins
+	StatusPending Status = "pending"
+	// StatusApproved subnets were added as network resources
+	StatusApproved Status = "approved"
+	// StatusDismissed subnets were rejected by an admin and aren't suggested again
+	StatusDismissed Status = "dismissed"
+)
+
+// DiscoveredSubnet is a subnet reported by a routing peer of a network that isn't a resource of the network yet
+type DiscoveredSubnet struct {
+	ID        string `gorm:"primaryKey"`
+	AccountID string `gorm:"index"`
+	NetworkID string
+	// PeerID is the routing peer that reported the subnet last
+	PeerID string
+	Prefix netip.Prefix `gorm:"serializer:json"`
+	Status Status
+	// ResourceID is the network resource created for an approved subnet
+	ResourceID string
+	// DecidedBy is the ID of the user that approved or dismissed the subnet, empty if it was approved automatically
+	DecidedBy   string
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
+
+// TableName returns the name of the table for the DiscoveredSubnet model in the database.
+func (*DiscoveredSubnet) TableName() string {
+	return "discovered_subnets"
+}
+
+// NewDiscoveredSubnet returns a pending subnet of the candidate
+func NewDiscoveredSubnet(accountID string, candidate Candidate, now time.Time) *DiscoveredSubnet {
+	return &DiscoveredSubnet{
+		ID:          xid.New().String(),
+		AccountID:   accountID,
+		NetworkID:   candidate.NetworkID,
+		PeerID:      candidate.PeerID,
+		Prefix:      candidate.Prefix,
+		Status:      StatusPending,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}
+}
+
+// ResourceName returns the name of the network resource created for the subnet
+func (s *DiscoveredSubnet) ResourceName() string {
+	return s.Prefix.String()
+}
+
+// EventMeta returns activity event meta related to the subnet
+func (s *DiscoveredSubnet) EventMeta() map[string]any {
+	meta := map[string]any{
+		"network_id": s.NetworkID,
+		"peer_id":    s.PeerID,
+		"subnet":     s.Prefix.String(),
+		"status":     s.Status,
+	}
+	if s.ResourceID != "" {
+		meta["resource_id"] = s.ResourceID
+	}
+	return meta
+}
+
+// ToAPIResponse converts the subnet to its API representation
+func (s *DiscoveredSubnet) ToAPIResponse() *api.DiscoveredSubnet {
+	resp := &api.DiscoveredSubnet{
+		Id:          s.ID,
+		NetworkId:   s.NetworkID,
+		PeerId:      s.PeerID,
+		Subnet:      s.Prefix.String(),
+		Status:      api.DiscoveredSubnetStatus(s.Status),
+		FirstSeenAt: s.FirstSeenAt,
+		LastSeenAt:  s.LastSeenAt,
+	}
+	if s.ResourceID != "" {
+		resp.ResourceId = &s.ResourceID
+	}
+	return resp
+}
+
+// Candidate is a subnet reported by a routing peer of a network
+type Candidate struct {
+	NetworkID string
+	PeerID    string
+	Prefix    netip.Prefix
+}
+
+// FindCandidates returns the subnets reported by the routing peers of the networks that are part of the allowlist.
+// The subnets are taken from the interface addresses and the discovered routing table entries of the peers.
+// Subnets overlapping the account network and subnets that are already resources of the network are skipped.
+func FindCandidates(routers []*routerTypes.NetworkRouter, routingPeers map[string][]*nbpeer.Peer, resources []*resourceTypes.NetworkResource, allowlist []netip.Prefix, accountNetwork netip.Prefix) []Candidate {
+	existing := make(map[string][]netip.Prefix)
+	for _, resource := range resources {
+		if resource.Prefix.IsValid() {
+			existing[resource.NetworkID] = append(existing[resource.NetworkID], resource.Prefix.Masked())
+		}
+	}
+
+	var candidates []Candidate
+	for _, router := range routers {
+		if !router.Enabled {
+			continue
+		}
+
+		for _, peer := range routingPeers[router.ID] {
+			for _, prefix := range peerSubnets(peer) {
+				if !isAllowed(prefix, allowlist) || (accountNetwork.IsValid() && prefix.Overlaps(accountNetwork)) {
+					continue
+				}
+				if slices.Contains(existing[router.NetworkID], prefix) {
+					continue
+				}
+				existing[router.NetworkID] = append(existing[router.NetworkID], prefix)
+				candidates = append(candidates, Candidate{NetworkID: router.NetworkID, PeerID: peer.ID, Prefix: prefix})
+			}
+		}
+	}
+	return candidates
+}
+
+// peerSubnets returns the masked subnets of the interface addresses and discovered routing table entries of the peer
+func peerSubnets(peer *nbpeer.Peer) []netip.Prefix {
+	var subnets []netip.Prefix
+	for _, addr := range peer.Meta.NetworkAddresses {
+		if addr.NetIP.Addr().IsLoopback() || addr.NetIP.Addr().IsLinkLocalUnicast() {
+			continue
+		}
+		subnets = append(subnets, addr.NetIP.Masked())
+	}
+	for _, entry := range peer.Meta.DiscoveredSubnets {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			continue
+		}
+		subnets = append(subnets, prefix.Masked())
+	}
+	return subnets
+}
+
+// isAllowed returns true if the subnet is a network, not a single host, that is fully contained in one of the
+// allowlist prefixes
+func isAllowed(subnet netip.Prefix, allowlist []netip.Prefix) bool {
+	if !subnet.IsValid() || subnet.IsSingleIP() || subnet.Bits() == 0 {
+		return false
+	}
+	for _, allowed := range allowlist {
+		if allowed.Bits() <= subnet.Bits() && allowed.Contains(subnet.Addr()) {
+			return true
+		}
+	}
+	return false
+}
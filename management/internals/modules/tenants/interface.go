@@ -0,0 +1,17 @@
+package tenants
+
+import (
+	"context"
+)
+
+type Manager interface {
+	ListTenants(ctx context.Context, accountID, userID string) ([]*Tenant, error)
+	CreateTenant(ctx context.Context, accountID, userID, name, templateID string, adminGroups []string) (*Tenant, error)
+	GetTenantsHealth(ctx context.Context, accountID, userID string) ([]*Health, error)
+
+	ListTemplates(ctx context.Context, accountID, userID string) ([]*Template, error)
+	GetTemplate(ctx context.Context, accountID, userID, templateID string) (*Template, error)
+	CreateTemplate(ctx context.Context, accountID, userID string, template *Template) (*Template, error)
+	UpdateTemplate(ctx context.Context, accountID, userID string, template *Template) (*Template, error)
+	DeleteTemplate(ctx context.Context, accountID, userID, templateID string) error
+}
@@ -0,0 +1,229 @@
+package manager
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netbirdio/netbird/management/internals/modules/tenants"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/http/util"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type handler struct {
+	manager tenants.Manager
+}
+
+func RegisterEndpoints(router *mux.Router, manager tenants.Manager) {
+	h := &handler{
+		manager: manager,
+	}
+
+	router.HandleFunc("/tenants", h.listTenants).Methods("GET", "OPTIONS")
+	router.HandleFunc("/tenants", h.createTenant).Methods("POST", "OPTIONS")
+	router.HandleFunc("/tenants/health", h.getTenantsHealth).Methods("GET", "OPTIONS")
+	router.HandleFunc("/tenants/templates", h.listTemplates).Methods("GET", "OPTIONS")
+	router.HandleFunc("/tenants/templates", h.createTemplate).Methods("POST", "OPTIONS")
+	router.HandleFunc("/tenants/templates/{templateId}", h.getTemplate).Methods("GET", "OPTIONS")
+	router.HandleFunc("/tenants/templates/{templateId}", h.updateTemplate).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/tenants/templates/{templateId}", h.deleteTemplate).Methods("DELETE", "OPTIONS")
+}
+
+func (h *handler) listTenants(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	managed, err := h.manager.ListTenants(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	apiTenants := make([]*api.Tenant, 0, len(managed))
+	for _, tenant := range managed {
+		apiTenants = append(apiTenants, tenant.ToAPIResponse())
+	}
+
+	util.WriteJSONObject(r.Context(), w, apiTenants)
+}
+
+func (h *handler) createTenant(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var req api.PostApiTenantsJSONRequestBody
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	var templateID string
+	if req.TemplateId != nil {
+		templateID = *req.TemplateId
+	}
+
+	tenant, err := h.manager.CreateTenant(r.Context(), userAuth.AccountId, userAuth.UserId, req.Name, templateID, req.AdminGroups)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, tenant.ToAPIResponse())
+}
+
+func (h *handler) getTenantsHealth(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	health, err := h.manager.GetTenantsHealth(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	apiHealth := make([]*api.TenantHealth, 0, len(health))
+	for _, tenantHealth := range health {
+		apiHealth = append(apiHealth, tenantHealth.ToAPIResponse())
+	}
+
+	util.WriteJSONObject(r.Context(), w, apiHealth)
+}
+
+func (h *handler) listTemplates(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	templates, err := h.manager.ListTemplates(r.Context(), userAuth.AccountId, userAuth.UserId)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	apiTemplates := make([]*api.TenantTemplate, 0, len(templates))
+	for _, template := range templates {
+		apiTemplates = append(apiTemplates, template.ToAPIResponse())
+	}
+
+	util.WriteJSONObject(r.Context(), w, apiTemplates)
+}
+
+func (h *handler) getTemplate(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	templateID := mux.Vars(r)["templateId"]
+	if templateID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "template ID is required"), w)
+		return
+	}
+
+	template, err := h.manager.GetTemplate(r.Context(), userAuth.AccountId, userAuth.UserId, templateID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, template.ToAPIResponse())
+}
+
+func (h *handler) createTemplate(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var req api.PostApiTenantsTemplatesJSONRequestBody
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	template, err := tenants.TemplateFromAPIRequest(&req)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	created, err := h.manager.CreateTemplate(r.Context(), userAuth.AccountId, userAuth.UserId, template)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, created.ToAPIResponse())
+}
+
+func (h *handler) updateTemplate(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	templateID := mux.Vars(r)["templateId"]
+	if templateID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "template ID is required"), w)
+		return
+	}
+
+	var req api.PutApiTenantsTemplatesTemplateIdJSONRequestBody
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	template, err := tenants.TemplateFromAPIRequest(&req)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+	template.ID = templateID
+
+	updated, err := h.manager.UpdateTemplate(r.Context(), userAuth.AccountId, userAuth.UserId, template)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, updated.ToAPIResponse())
+}
+
+func (h *handler) deleteTemplate(w http.ResponseWriter, r *http.Request) {
+	userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	templateID := mux.Vars(r)["templateId"]
+	if templateID == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "template ID is required"), w)
+		return
+	}
+
+	if err = h.manager.DeleteTemplate(r.Context(), userAuth.AccountId, userAuth.UserId, templateID); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
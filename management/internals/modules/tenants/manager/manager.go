@@ -0,0 +1,313 @@
+package manager
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/internals/modules/declarative"
+	"github.com/netbirdio/netbird/management/internals/modules/tenants"
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+type managerImpl struct {
+	store              store.Store
+	accountManager     account.Manager
+	permissionsManager permissions.Manager
+	declarativeManager declarative.Manager
+}
+
+func NewManager(store store.Store, accountManager account.Manager, permissionsManager permissions.Manager, declarativeManager declarative.Manager) tenants.Manager {
+	return &managerImpl{
+		store:              store,
+		accountManager:     accountManager,
+		permissionsManager: permissionsManager,
+		declarativeManager: declarativeManager,
+	}
+}
+
+func (m *managerImpl) ListTenants(ctx context.Context, accountID, userID string) ([]*tenants.Tenant, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetManagedTenants(ctx, store.LockingStrengthNone, accountID)
+}
+
+// CreateTenant creates a new account managed by the account of the user. The tenant account is owned by a service
+// user, the users in the admin groups of the managing account access it as tenant admins. If a template is given, its
+// configuration is applied to the new account.
+func (m *managerImpl) CreateTenant(ctx context.Context, accountID, userID, name, templateID string, adminGroups []string) (*tenants.Tenant, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Create); err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, status.Errorf(status.InvalidArgument, "tenant name is required")
+	}
+
+	if _, err := m.store.GetTenant(ctx, store.LockingStrengthNone, accountID); err == nil {
+		return nil, status.Errorf(status.PreconditionFailed, "tenant accounts can't manage other tenants")
+	}
+
+	if len(adminGroups) == 0 {
+		return nil, status.Errorf(status.InvalidArgument, "at least one admin group is required")
+	}
+	for _, groupID := range adminGroups {
+		if _, err := m.store.GetGroupByID(ctx, store.LockingStrengthNone, accountID, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	// the user creating the tenant has to be a tenant admin, otherwise it couldn't apply the template
+	user, err := m.store.GetUserByUserID(ctx, store.LockingStrengthNone, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !(&tenants.Tenant{ManagedBy: accountID, AdminGroups: adminGroups}).IsAdmin(user) {
+		return nil, status.Errorf(status.InvalidArgument, "the admin groups have to include a group of the user creating the tenant")
+	}
+
+	var template *tenants.Template
+	if templateID != "" {
+		template, err = m.store.GetTenantTemplateByID(ctx, store.LockingStrengthNone, accountID, templateID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ownerID := xid.New().String()
+	tenantAccount, err := m.accountManager.NewAccount(ctx, ownerID, "", "", name)
+	if err != nil {
+		return nil, err
+	}
+	owner := tenantAccount.Users[ownerID]
+	owner.IsServiceUser = true
+	owner.ServiceUserName = name + " owner"
+	owner.Issued = types.UserIssuedAPI
+
+	tenant := &tenants.Tenant{
+		AccountID:   tenantAccount.Id,
+		ManagedBy:   accountID,
+		Name:        name,
+		TemplateID:  templateID,
+		AdminGroups: adminGroups,
+		CreatedBy:   userID,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		if err := transaction.SaveAccount(ctx, tenantAccount); err != nil {
+			return err
+		}
+		return transaction.SaveTenant(ctx, tenant)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if template != nil {
+		spec := template.Spec
+		if _, err = m.declarativeManager.Apply(ctx, tenant.AccountID, userID, &spec); err != nil {
+			log.WithContext(ctx).Errorf("failed to apply template %s to tenant %s: %v", template.ID, tenant.AccountID, err)
+			if err := m.deleteTenant(ctx, tenant.AccountID); err != nil {
+				log.WithContext(ctx).Errorf("failed to delete tenant %s after the template failed: %v", tenant.AccountID, err)
+			}
+			return nil, status.Errorf(status.Internal, "template %s couldn't be applied: %v", template.Name, err)
+		}
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, tenant.AccountID, accountID, activity.TenantCreated, tenant.EventMeta())
+
+	return tenant, nil
+}
+
+// deleteTenant removes a tenant together with its account, so a tenant whose template failed isn't left behind
+// half-provisioned
+func (m *managerImpl) deleteTenant(ctx context.Context, tenantAccountID string) error {
+	return m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		if err := transaction.DeleteTenant(ctx, tenantAccountID); err != nil {
+			return err
+		}
+
+		tenantAccount, err := transaction.GetAccount(ctx, tenantAccountID)
+		if err != nil {
+			return err
+		}
+		return transaction.DeleteAccount(ctx, tenantAccount)
+	})
+}
+
+// GetTenantsHealth summarizes the peers and users of all tenants managed by the account
+func (m *managerImpl) GetTenantsHealth(ctx context.Context, accountID, userID string) ([]*tenants.Health, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Read); err != nil {
+		return nil, err
+	}
+
+	managed, err := m.store.GetManagedTenants(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*tenants.Health, 0, len(managed))
+	for _, tenant := range managed {
+		health, err := m.getTenantHealth(ctx, tenant)
+		if err != nil {
+			if sErr, ok := status.FromError(err); ok && sErr.Type() == status.NotFound {
+				// the tenant account has been deleted
+				continue
+			}
+			return nil, err
+		}
+		result = append(result, health)
+	}
+
+	return result, nil
+}
+
+func (m *managerImpl) getTenantHealth(ctx context.Context, tenant *tenants.Tenant) (*tenants.Health, error) {
+	exists, err := m.store.AccountExists(ctx, store.LockingStrengthNone, tenant.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, status.NewAccountNotFoundError(tenant.AccountID)
+	}
+
+	peers, err := m.store.GetAccountPeers(ctx, store.LockingStrengthNone, tenant.AccountID, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := m.store.GetAccountUsers(ctx, store.LockingStrengthNone, tenant.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	health := &tenants.Health{
+		AccountID: tenant.AccountID,
+		Name:      tenant.Name,
+		Peers:     len(peers),
+		Users:     len(users),
+	}
+	for _, peer := range peers {
+		if peer.Status == nil {
+			continue
+		}
+		if peer.Status.Connected {
+			health.ConnectedPeers++
+		}
+		if peer.Status.LoginExpired {
+			health.LoginExpiredPeers++
+		}
+		if peer.Status.RequiresApproval {
+			health.PendingApprovalPeers++
+		}
+		if peer.Status.LastSeen.After(health.LastSeen) {
+			health.LastSeen = peer.Status.LastSeen
+		}
+	}
+
+	return health, nil
+}
+
+func (m *managerImpl) ListTemplates(ctx context.Context, accountID, userID string) ([]*tenants.Template, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetTenantTemplates(ctx, store.LockingStrengthNone, accountID)
+}
+
+func (m *managerImpl) GetTemplate(ctx context.Context, accountID, userID, templateID string) (*tenants.Template, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Read); err != nil {
+		return nil, err
+	}
+
+	return m.store.GetTenantTemplateByID(ctx, store.LockingStrengthNone, accountID, templateID)
+}
+
+func (m *managerImpl) CreateTemplate(ctx context.Context, accountID, userID string, template *tenants.Template) (*tenants.Template, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Create); err != nil {
+		return nil, err
+	}
+
+	template = tenants.NewTemplate(accountID, template.Name, template.Description, template.Spec)
+	if err := template.Validate(); err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "%s", err.Error())
+	}
+	template.UpdatedAt = time.Now().UTC()
+
+	if err := m.store.SaveTenantTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, template.ID, accountID, activity.TenantTemplateCreated, template.EventMeta())
+
+	return template, nil
+}
+
+func (m *managerImpl) UpdateTemplate(ctx context.Context, accountID, userID string, template *tenants.Template) (*tenants.Template, error) {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Update); err != nil {
+		return nil, err
+	}
+
+	if _, err := m.store.GetTenantTemplateByID(ctx, store.LockingStrengthNone, accountID, template.ID); err != nil {
+		return nil, err
+	}
+
+	template.AccountID = accountID
+	if err := template.Validate(); err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "%s", err.Error())
+	}
+	template.UpdatedAt = time.Now().UTC()
+
+	if err := m.store.SaveTenantTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, template.ID, accountID, activity.TenantTemplateUpdated, template.EventMeta())
+
+	return template, nil
+}
+
+func (m *managerImpl) DeleteTemplate(ctx context.Context, accountID, userID, templateID string) error {
+	if err := m.validatePermissions(ctx, accountID, userID, operations.Delete); err != nil {
+		return err
+	}
+
+	template, err := m.store.GetTenantTemplateByID(ctx, store.LockingStrengthNone, accountID, templateID)
+	if err != nil {
+		return err
+	}
+
+	if err = m.store.DeleteTenantTemplate(ctx, accountID, templateID); err != nil {
+		return err
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, templateID, accountID, activity.TenantTemplateDeleted, template.EventMeta())
+
+	return nil
+}
+
+func (m *managerImpl) validatePermissions(ctx context.Context, accountID, userID string, operation operations.Operation) error {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Tenants, operation)
+	if err != nil {
+		return status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return status.NewPermissionDeniedError()
+	}
+	return nil
+}
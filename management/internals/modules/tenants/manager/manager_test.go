@@ -0,0 +1,346 @@
+package manager
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/declarative"
+	declarativeManager "github.com/netbirdio/netbird/management/internals/modules/declarative/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/tenants"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	mspAccountID   = "msp-account-id"
+	mspAdminID     = "msp-admin-id"
+	mspUserID      = "msp-user-id"
+	mspAuditorID   = "msp-auditor-id"
+	mspOtherID     = "msp-other-admin-id"
+	mspAdminsGroup = "msp-admins-group"
+	otherAccountID = "other-account-id"
+	otherAdminID   = "other-admin-id"
+)
+
+const testTemplateSpec = `
+groups:
+  - name: developers
+policies:
+  - name: developers
+    rules:
+      - name: all
+        sources: [developers]
+        destinations: [developers]
+`
+
+func setupTest(t *testing.T) (*managerImpl, store.Store, *mock_server.MockAccountManager, permissions.Manager, func()) {
+	t.Helper()
+
+	ctx := context.Background()
+	testStore, cleanup, err := store.NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+
+	err = testStore.SaveAccount(ctx, &types.Account{
+		Id: mspAccountID,
+		Users: map[string]*types.User{
+			mspAdminID:   {Id: mspAdminID, AccountID: mspAccountID, Role: types.UserRoleAdmin, AutoGroups: []string{mspAdminsGroup}},
+			mspUserID:    {Id: mspUserID, AccountID: mspAccountID, Role: types.UserRoleUser, AutoGroups: []string{mspAdminsGroup}},
+			mspAuditorID: {Id: mspAuditorID, AccountID: mspAccountID, Role: types.UserRoleAuditor},
+			mspOtherID:   {Id: mspOtherID, AccountID: mspAccountID, Role: types.UserRoleAdmin},
+		},
+		Groups: map[string]*types.Group{
+			mspAdminsGroup: {ID: mspAdminsGroup, AccountID: mspAccountID, Name: "msp admins"},
+		},
+	})
+	require.NoError(t, err)
+
+	err = testStore.SaveAccount(ctx, &types.Account{
+		Id: otherAccountID,
+		Users: map[string]*types.User{
+			otherAdminID: {Id: otherAdminID, AccountID: otherAccountID, Role: types.UserRoleAdmin},
+		},
+	})
+	require.NoError(t, err)
+
+	mockAccountManager := &mock_server.MockAccountManager{
+		NewAccountFunc: func(_ context.Context, userID, _, email, name string) (*types.Account, error) {
+			return newTestAccount(userID, email, name), nil
+		},
+	}
+	permissionsManager := permissions.NewManager(testStore)
+
+	manager := &managerImpl{
+		store:              testStore,
+		accountManager:     mockAccountManager,
+		permissionsManager: permissionsManager,
+		declarativeManager: declarativeManager.NewManager(testStore, mockAccountManager, permissionsManager),
+	}
+
+	return manager, testStore, mockAccountManager, permissionsManager, cleanup
+}
+
+func newTestAccount(userID, email, name string) *types.Account {
+	owner := types.NewOwnerUser(userID, email, name)
+	account := &types.Account{
+		Id:          "tenant-" + userID,
+		CreatedBy:   userID,
+		Network:     types.NewNetwork(),
+		Users:       map[string]*types.User{userID: owner},
+		Settings:    &types.Settings{},
+		DNSSettings: types.DNSSettings{DisabledManagementGroups: []string{}},
+	}
+	owner.AccountID = account.Id
+	_ = account.AddAllGroup(false)
+	return account
+}
+
+func createTemplate(t *testing.T, manager *managerImpl) *tenants.Template {
+	t.Helper()
+	spec, err := declarative.ParseSpec([]byte(testTemplateSpec))
+	require.NoError(t, err)
+
+	template, err := manager.CreateTemplate(context.Background(), mspAccountID, mspAdminID, &tenants.Template{Name: "office", Spec: *spec})
+	require.NoError(t, err)
+	return template
+}
+
+func TestManagerImpl_CreateTenant(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, mockAccountManager, _, cleanup := setupTest(t)
+	defer cleanup()
+
+	var events []activity.ActivityDescriber
+	mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+		events = append(events, activityID)
+	}
+
+	template := createTemplate(t, manager)
+
+	tenant, err := manager.CreateTenant(ctx, mspAccountID, mspAdminID, "Acme Corp", template.ID, []string{mspAdminsGroup})
+	require.NoError(t, err)
+	assert.Equal(t, mspAccountID, tenant.ManagedBy)
+	assert.Contains(t, events, activity.TenantCreated)
+
+	account, err := testStore.GetAccount(ctx, tenant.AccountID)
+	require.NoError(t, err)
+	owner, err := account.FindUser(account.CreatedBy)
+	require.NoError(t, err)
+	assert.True(t, owner.IsServiceUser, "tenant accounts should be owned by a service user")
+	assert.Equal(t, types.UserRoleOwner, owner.Role)
+
+	groupNames := make([]string, 0, len(account.Groups))
+	for _, group := range account.Groups {
+		groupNames = append(groupNames, group.Name)
+	}
+	assert.ElementsMatch(t, []string{"All", "developers"}, groupNames, "the template should be applied")
+
+	listed, err := manager.ListTenants(ctx, mspAccountID, mspAdminID)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, tenant.AccountID, listed[0].AccountID)
+
+	_, err = manager.CreateTenant(ctx, tenant.AccountID, mspAdminID, "nested", "", []string{mspAdminsGroup})
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PreconditionFailed, s.Type())
+
+	_, err = manager.CreateTenant(ctx, mspAccountID, mspAdminID, "Acme Corp", "unknown", []string{mspAdminsGroup})
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.NotFound, s.Type())
+
+	_, err = manager.CreateTenant(ctx, mspAccountID, mspAdminID, "Acme Corp", "", nil)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.InvalidArgument, s.Type())
+
+	_, err = manager.CreateTenant(ctx, mspAccountID, mspAdminID, "Acme Corp", "", []string{"unknown"})
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.NotFound, s.Type())
+
+	_, err = manager.CreateTenant(ctx, mspAccountID, mspOtherID, "Acme Corp", "", []string{mspAdminsGroup})
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.InvalidArgument, s.Type())
+}
+
+func TestManagerImpl_CreateTenant_TemplateFails(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, mockAccountManager, _, cleanup := setupTest(t)
+	defer cleanup()
+
+	var events []activity.ActivityDescriber
+	mockAccountManager.StoreEventFunc = func(_ context.Context, _, _, _ string, activityID activity.ActivityDescriber, _ map[string]any) {
+		events = append(events, activityID)
+	}
+
+	spec, err := declarative.ParseSpec([]byte(`
+policies:
+  - name: missing
+    rules:
+      - name: all
+        sources: [missing]
+        destinations: [missing]
+`))
+	require.NoError(t, err)
+	template := &tenants.Template{ID: "broken-template", AccountID: mspAccountID, Name: "broken", Spec: *spec}
+	require.NoError(t, testStore.SaveTenantTemplate(ctx, template))
+
+	var tenantAccountID string
+	mockAccountManager.NewAccountFunc = func(_ context.Context, userID, _, email, name string) (*types.Account, error) {
+		account := newTestAccount(userID, email, name)
+		tenantAccountID = account.Id
+		return account, nil
+	}
+
+	_, err = manager.CreateTenant(ctx, mspAccountID, mspAdminID, "Acme Corp", template.ID, []string{mspAdminsGroup})
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.Internal, s.Type())
+	assert.NotContains(t, events, activity.TenantCreated)
+
+	listed, err := manager.ListTenants(ctx, mspAccountID, mspAdminID)
+	require.NoError(t, err)
+	assert.Empty(t, listed, "the tenant should be removed when its template fails")
+
+	_, err = testStore.GetAccount(ctx, tenantAccountID)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.NotFound, s.Type())
+}
+
+func TestManagerImpl_TenantAccess(t *testing.T) {
+	ctx := context.Background()
+	manager, _, _, permissionsManager, cleanup := setupTest(t)
+	defer cleanup()
+
+	tenant, err := manager.CreateTenant(ctx, mspAccountID, mspAdminID, "Acme Corp", "", []string{mspAdminsGroup})
+	require.NoError(t, err)
+
+	allowed, err := permissionsManager.ValidateUserPermissions(ctx, tenant.AccountID, mspAdminID, modules.Peers, operations.Update)
+	require.NoError(t, err)
+	assert.True(t, allowed, "admins in the admin groups of the tenant should act in the tenant account")
+
+	_, err = permissionsManager.ValidateUserPermissions(ctx, tenant.AccountID, mspOtherID, modules.Peers, operations.Read)
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+
+	_, err = permissionsManager.ValidateUserPermissions(ctx, tenant.AccountID, mspAuditorID, modules.Peers, operations.Read)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+
+	_, err = permissionsManager.ValidateUserPermissions(ctx, tenant.AccountID, mspUserID, modules.Peers, operations.Read)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+
+	_, err = permissionsManager.ValidateUserPermissions(ctx, tenant.AccountID, otherAdminID, modules.Peers, operations.Read)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+
+	_, err = permissionsManager.ValidateUserPermissions(ctx, mspAccountID, otherAdminID, modules.Peers, operations.Read)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, s.Type())
+
+	_, err = manager.ListTenants(ctx, otherAccountID, otherAdminID)
+	require.NoError(t, err)
+	_, err = manager.ListTenants(ctx, mspAccountID, otherAdminID)
+	assert.Error(t, err, "tenants of an unrelated account shouldn't be readable")
+	_, err = manager.ListTenants(ctx, mspAccountID, mspUserID)
+	assert.Error(t, err)
+}
+
+func TestManagerImpl_GetTenantsHealth(t *testing.T) {
+	ctx := context.Background()
+	manager, testStore, _, _, cleanup := setupTest(t)
+	defer cleanup()
+
+	tenant, err := manager.CreateTenant(ctx, mspAccountID, mspAdminID, "Acme Corp", "", []string{mspAdminsGroup})
+	require.NoError(t, err)
+
+	lastSeen := time.Now().UTC().Truncate(time.Second)
+	peers := []*nbpeer.Peer{
+		{ID: "connected", Key: "connected", IP: net.IP{100, 64, 0, 1}, DNSLabel: "connected", Status: &nbpeer.PeerStatus{Connected: true, LastSeen: lastSeen}},
+		{ID: "expired", Key: "expired", IP: net.IP{100, 64, 0, 2}, DNSLabel: "expired", Status: &nbpeer.PeerStatus{LoginExpired: true, LastSeen: lastSeen.Add(-time.Hour)}},
+		{ID: "pending", Key: "pending", IP: net.IP{100, 64, 0, 3}, DNSLabel: "pending", Status: &nbpeer.PeerStatus{RequiresApproval: true}},
+	}
+	for _, peer := range peers {
+		peer.AccountID = tenant.AccountID
+		require.NoError(t, testStore.AddPeerToAccount(ctx, peer))
+	}
+
+	health, err := manager.GetTenantsHealth(ctx, mspAccountID, mspAdminID)
+	require.NoError(t, err)
+	require.Len(t, health, 1)
+	assert.Equal(t, "Acme Corp", health[0].Name)
+	assert.Equal(t, 3, health[0].Peers)
+	assert.Equal(t, 1, health[0].ConnectedPeers)
+	assert.Equal(t, 1, health[0].LoginExpiredPeers)
+	assert.Equal(t, 1, health[0].PendingApprovalPeers)
+	assert.Equal(t, 1, health[0].Users)
+	assert.True(t, lastSeen.Equal(health[0].LastSeen))
+}
+
+func TestManagerImpl_Templates(t *testing.T) {
+	ctx := context.Background()
+	manager, _, _, _, cleanup := setupTest(t)
+	defer cleanup()
+	template := createTemplate(t, manager)
+
+	invalid, err := declarative.ParseSpec([]byte("policies:\n  - name: p\n    rules:\n      - name: r\n        sources: [missing]\n        destinations: [missing]\n"))
+	require.NoError(t, err)
+	_, err = manager.CreateTemplate(ctx, mspAccountID, mspAdminID, &tenants.Template{Name: "invalid", Spec: *invalid})
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.InvalidArgument, s.Type())
+
+	template.Description = "updated"
+	_, err = manager.UpdateTemplate(ctx, mspAccountID, mspAdminID, template)
+	require.NoError(t, err)
+
+	stored, err := manager.GetTemplate(ctx, mspAccountID, mspAdminID, template.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "updated", stored.Description)
+	assert.Len(t, stored.Spec.Policies, 1)
+
+	_, err = manager.GetTemplate(ctx, otherAccountID, otherAdminID, template.ID)
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.NotFound, s.Type())
+
+	require.NoError(t, manager.DeleteTemplate(ctx, mspAccountID, mspAdminID, template.ID))
+	templates, err := manager.ListTemplates(ctx, mspAccountID, mspAdminID)
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+}
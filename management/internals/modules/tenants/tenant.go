@@ -0,0 +1,183 @@
+package tenants
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+
+	"github.com/netbirdio/netbird/management/internals/modules/declarative"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// Tenant is an account managed by the users of another account, e.g., a customer account of a managed service provider.
+// Users of the managing account in one of the admin groups of the tenant can access the tenant account with the
+// permissions of their role if the role allows access to the tenants module.
+type Tenant struct {
+	// AccountID is the ID of the tenant account
+	AccountID string `gorm:"primaryKey"`
+	// ManagedBy is the ID of the account that manages the tenant
+	ManagedBy  string `gorm:"index"`
+	Name       string
+	TemplateID string
+	// AdminGroups are the IDs of the groups of the managing account whose users can access the tenant account
+	AdminGroups []string `gorm:"serializer:json"`
+	CreatedBy   string
+	CreatedAt   time.Time
+}
+
+// TableName returns the name of the table for the Tenant model in the database.
+func (*Tenant) TableName() string {
+	return "tenants"
+}
+
+// IsAdmin checks if the user of the managing account is in one of the admin groups of the tenant
+func (t *Tenant) IsAdmin(user *types.User) bool {
+	if user.AccountID != t.ManagedBy {
+		return false
+	}
+	for _, groupID := range user.AutoGroups {
+		if slices.Contains(t.AdminGroups, groupID) {
+			return true
+		}
+	}
+	return false
+}
+
+// EventMeta returns activity event meta related to the tenant
+func (t *Tenant) EventMeta() map[string]any {
+	return map[string]any{"name": t.Name, "tenant_account_id": t.AccountID, "template_id": t.TemplateID, "admin_groups": t.AdminGroups}
+}
+
+// ToAPIResponse converts the tenant to its API representation
+func (t *Tenant) ToAPIResponse() *api.Tenant {
+	tenant := &api.Tenant{
+		AccountId:   t.AccountID,
+		Name:        t.Name,
+		AdminGroups: t.AdminGroups,
+		CreatedAt:   t.CreatedAt,
+	}
+	if t.TemplateID != "" {
+		tenant.TemplateId = &t.TemplateID
+	}
+	return tenant
+}
+
+// Template holds the objects every new tenant account is created with
+type Template struct {
+	ID          string `gorm:"primaryKey"`
+	AccountID   string `gorm:"index"`
+	Name        string
+	Description string
+	// Spec is the declarative configuration of the groups, policies, networks and DNS settings applied to new tenants
+	Spec      declarative.Spec `gorm:"serializer:json"`
+	UpdatedAt time.Time
+}
+
+// TableName returns the name of the table for the Template model in the database.
+func (*Template) TableName() string {
+	return "tenant_templates"
+}
+
+// NewTemplate returns a template with a new ID
+func NewTemplate(accountID, name, description string, spec declarative.Spec) *Template {
+	return &Template{
+		ID:          xid.New().String(),
+		AccountID:   accountID,
+		Name:        name,
+		Description: description,
+		Spec:        spec,
+	}
+}
+
+// TemplateFromAPIRequest parses the template of a create or update request
+func TemplateFromAPIRequest(req *api.TenantTemplateRequest) (*Template, error) {
+	data, err := json.Marshal(req.Config)
+	if err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "invalid template configuration: %s", err)
+	}
+	spec, err := declarative.DecodeSpec(data)
+	if err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "invalid template configuration: %s", err)
+	}
+
+	template := &Template{Name: req.Name, Spec: *spec}
+	if req.Description != nil {
+		template.Description = *req.Description
+	}
+	return template, nil
+}
+
+// Validate checks that the template can be applied to a new account
+func (t *Template) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("template name is required")
+	}
+
+	// a new account only has the All group, so everything else has to be part of the template
+	account := &types.Account{
+		Groups: map[string]*types.Group{
+			"all": {ID: "all", Name: "All"},
+		},
+	}
+	if _, err := declarative.ComputePlan(&t.Spec, declarative.Observe(account, nil), nil); err != nil {
+		return fmt.Errorf("template %s can't be applied to a new account: %w", t.Name, err)
+	}
+	return nil
+}
+
+// EventMeta returns activity event meta related to the template
+func (t *Template) EventMeta() map[string]any {
+	return map[string]any{"name": t.Name}
+}
+
+// ToAPIResponse converts the template to its API representation
+func (t *Template) ToAPIResponse() *api.TenantTemplate {
+	var config api.DeclarativeConfig
+	data, _ := json.Marshal(t.Spec)
+	_ = json.Unmarshal(data, &config)
+
+	return &api.TenantTemplate{
+		Id:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Config:      config,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+// Health summarizes the state of the peers of a tenant account
+type Health struct {
+	AccountID            string
+	Name                 string
+	Peers                int
+	ConnectedPeers       int
+	LoginExpiredPeers    int
+	PendingApprovalPeers int
+	Users                int
+	// LastSeen is the last time any peer of the tenant was connected
+	LastSeen time.Time
+}
+
+// ToAPIResponse converts the health summary to its API representation
+func (h *Health) ToAPIResponse() *api.TenantHealth {
+	health := &api.TenantHealth{
+		AccountId:            h.AccountID,
+		Name:                 h.Name,
+		Peers:                h.Peers,
+		ConnectedPeers:       h.ConnectedPeers,
+		LoginExpiredPeers:    h.LoginExpiredPeers,
+		PendingApprovalPeers: h.PendingApprovalPeers,
+		Users:                h.Users,
+	}
+	if !h.LastSeen.IsZero() {
+		health.LastSeen = &h.LastSeen
+	}
+	return health
+}
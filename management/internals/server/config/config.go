@@ -58,6 +58,9 @@ type Config struct {
 	// disable default all-to-all policy
 	DisableDefaultPolicy bool
 
+	// Reports enables periodic peer and user inventory reports written to a directory
+	Reports *ReportsConfig
+
 	// EmbeddedIdP contains configuration for the embedded Dex OIDC provider.
 	// When set, Dex will be embedded in the management server and serve requests at /oauth2/
 	EmbeddedIdP *idp.EmbeddedIdPConfig
@@ -177,6 +180,16 @@ type StoreConfig struct {
 	Engine types.Engine
 }
 
+// ReportsConfig contains the configuration of the scheduled inventory reports
+type ReportsConfig struct {
+	// Directory the reports are written to, with one subdirectory per account
+	Directory string
+	// Interval between two reports
+	Interval util.Duration
+	// Format of the report files, csv or json. Defaults to csv
+	Format string
+}
+
 // ReverseProxy contains reverse proxy configuration in front of management.
 type ReverseProxy struct {
 	// TrustedHTTPProxies represents a list of trusted HTTP proxies by their IP prefixes.
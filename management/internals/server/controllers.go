@@ -42,7 +42,7 @@ func (s *BaseServer) IntegratedValidator() integrated_validator.IntegratedValida
 		if err != nil {
 			log.Errorf("failed to create integrated peer validator: %v", err)
 		}
-		return integratedPeerValidator
+		return integrated_validator.WithPeerApproval(integratedPeerValidator)
 	})
 }
 
@@ -7,7 +7,27 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/management-integrations/integrations"
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	accessRequestsManager "github.com/netbirdio/netbird/management/internals/modules/accessrequests/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/customroles"
+	customRolesManager "github.com/netbirdio/netbird/management/internals/modules/customroles/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/declarative"
+	declarativeManager "github.com/netbirdio/netbird/management/internals/modules/declarative/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/history"
+	historyManager "github.com/netbirdio/netbird/management/internals/modules/history/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	peerApprovalManager "github.com/netbirdio/netbird/management/internals/modules/peerapproval/manager"
 	"github.com/netbirdio/netbird/management/internals/modules/peers"
+	"github.com/netbirdio/netbird/management/internals/modules/portforwards"
+	portForwardsManager "github.com/netbirdio/netbird/management/internals/modules/portforwards/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/reports"
+	reportsManager "github.com/netbirdio/netbird/management/internals/modules/reports/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	scimManager "github.com/netbirdio/netbird/management/internals/modules/scim/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/subnetdiscovery"
+	subnetDiscoveryManager "github.com/netbirdio/netbird/management/internals/modules/subnetdiscovery/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/tenants"
+	tenantsManager "github.com/netbirdio/netbird/management/internals/modules/tenants/manager"
 	"github.com/netbirdio/netbird/management/internals/modules/zones"
 	zonesManager "github.com/netbirdio/netbird/management/internals/modules/zones/manager"
 	"github.com/netbirdio/netbird/management/internals/modules/zones/records"
@@ -174,3 +194,63 @@ func (s *BaseServer) RecordsManager() records.Manager {
 		return recordsManager.NewManager(s.Store(), s.AccountManager(), s.PermissionsManager())
 	})
 }
+
+func (s *BaseServer) SCIMManager() scim.Manager {
+	return Create(s, func() scim.Manager {
+		return scimManager.NewManager(s.Store(), s.AccountManager(), s.PermissionsManager())
+	})
+}
+
+func (s *BaseServer) CustomRolesManager() customroles.Manager {
+	return Create(s, func() customroles.Manager {
+		return customRolesManager.NewManager(s.Store(), s.AccountManager(), s.PermissionsManager())
+	})
+}
+
+func (s *BaseServer) PeerApprovalManager() peerapproval.Manager {
+	return Create(s, func() peerapproval.Manager {
+		return peerApprovalManager.NewManager(s.Store(), s.AccountManager(), s.PermissionsManager())
+	})
+}
+
+func (s *BaseServer) DeclarativeManager() declarative.Manager {
+	return Create(s, func() declarative.Manager {
+		return declarativeManager.NewManager(s.Store(), s.AccountManager(), s.PermissionsManager())
+	})
+}
+
+func (s *BaseServer) TenantsManager() tenants.Manager {
+	return Create(s, func() tenants.Manager {
+		return tenantsManager.NewManager(s.Store(), s.AccountManager(), s.PermissionsManager(), s.DeclarativeManager())
+	})
+}
+
+func (s *BaseServer) AccessRequestsManager() accessrequests.Manager {
+	return Create(s, func() accessrequests.Manager {
+		return accessRequestsManager.NewManager(s.Store(), s.AccountManager(), s.PermissionsManager())
+	})
+}
+
+func (s *BaseServer) SubnetDiscoveryManager() subnetdiscovery.Manager {
+	return Create(s, func() subnetdiscovery.Manager {
+		return subnetDiscoveryManager.NewManager(s.Store(), s.AccountManager(), s.PermissionsManager(), s.ResourcesManager())
+	})
+}
+
+func (s *BaseServer) PortForwardsManager() portforwards.Manager {
+	return Create(s, func() portforwards.Manager {
+		return portForwardsManager.NewManager(s.Store(), s.AccountManager(), s.PermissionsManager())
+	})
+}
+
+func (s *BaseServer) HistoryManager() history.Manager {
+	return Create(s, func() history.Manager {
+		return historyManager.NewManager(s.Store(), s.AccountManager(), s.PermissionsManager())
+	})
+}
+
+func (s *BaseServer) ReportsManager() reports.Manager {
+	return Create(s, func() reports.Manager {
+		return reportsManager.NewManager(s.Store(), s.PermissionsManager())
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/netbirdio/netbird/encryption"
+	"github.com/netbirdio/netbird/management/internals/modules/reports"
 	nbconfig "github.com/netbirdio/netbird/management/internals/server/config"
 	"github.com/netbirdio/netbird/management/server/metrics"
 	"github.com/netbirdio/netbird/management/server/store"
@@ -86,6 +87,26 @@ func (s *BaseServer) AfterInit(fn func(s *BaseServer)) {
 	s.afterInit = append(s.afterInit, fn)
 }
 
+// startScheduledReports writes the inventory reports periodically if a reports directory is configured
+func (s *BaseServer) startScheduledReports(ctx context.Context) error {
+	reportsConfig := s.Config.Reports
+	if reportsConfig == nil || reportsConfig.Directory == "" {
+		return nil
+	}
+
+	format, err := reports.ParseFormat(reportsConfig.Format)
+	if err != nil {
+		return fmt.Errorf("invalid reports config: %v", err)
+	}
+	if reportsConfig.Interval.Duration <= 0 {
+		return fmt.Errorf("invalid reports config: interval must be positive")
+	}
+
+	log.WithContext(ctx).Infof("writing inventory reports to %s every %s", reportsConfig.Directory, reportsConfig.Interval.Duration)
+	s.ReportsManager().Start(ctx, reportsConfig.Directory, reportsConfig.Interval.Duration, format)
+	return nil
+}
+
 // Start begins listening for HTTP requests on the configured address
 func (s *BaseServer) Start(ctx context.Context) error {
 	srvCtx, cancel := context.WithCancel(ctx)
@@ -100,6 +121,11 @@ func (s *BaseServer) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to expose metrics: %v", err)
 	}
 	s.EphemeralManager().LoadInitialPeers(srvCtx)
+	s.AccessRequestsManager().Start(srvCtx)
+	s.SubnetDiscoveryManager().Start(srvCtx)
+	if err = s.startScheduledReports(srvCtx); err != nil {
+		return err
+	}
 
 	var tlsConfig *tls.Config
 	tlsEnabled := false
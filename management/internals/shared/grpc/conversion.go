@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	integrationsConfig "github.com/netbirdio/management-integrations/integrations/config"
 	"github.com/netbirdio/netbird/client/ssh/auth"
@@ -88,7 +89,7 @@ func toNetbirdConfig(config *nbconfig.Config, turnCredentials *Token, relayToken
 	return nbConfig
 }
 
-func toPeerConfig(peer *nbpeer.Peer, network *types.Network, dnsName string, settings *types.Settings, httpConfig *nbconfig.HttpServerConfig, deviceFlowConfig *nbconfig.DeviceAuthorizationFlow, enableSSH bool) *proto.PeerConfig {
+func toPeerConfig(peer *nbpeer.Peer, network *types.Network, dnsName string, settings *types.Settings, httpConfig *nbconfig.HttpServerConfig, deviceFlowConfig *nbconfig.DeviceAuthorizationFlow, enableSSH bool, routingPeer bool) *proto.PeerConfig {
 	netmask, _ := network.Net.Mask.Size()
 	fqdn := peer.FQDN(dnsName)
 
@@ -100,7 +101,7 @@ func toPeerConfig(peer *nbpeer.Peer, network *types.Network, dnsName string, set
 		sshConfig.JwtConfig = buildJWTConfig(httpConfig, deviceFlowConfig)
 	}
 
-	return &proto.PeerConfig{
+	peerConfig := &proto.PeerConfig{
 		Address:                         fmt.Sprintf("%s/%d", peer.IP.String(), netmask),
 		SshConfig:                       sshConfig,
 		Fqdn:                            fqdn,
@@ -110,16 +111,27 @@ func toPeerConfig(peer *nbpeer.Peer, network *types.Network, dnsName string, set
 			Version: settings.AutoUpdateVersion,
 		},
 	}
+
+	// only the routing peers of networks report subnets
+	if settings.SubnetDiscoveryEnabled && routingPeer {
+		allowlist := settings.GetSubnetDiscoveryAllowlist()
+		peerConfig.SubnetDiscovery = &proto.SubnetDiscovery{Allowlist: make([]string, 0, len(allowlist))}
+		for _, prefix := range allowlist {
+			peerConfig.SubnetDiscovery.Allowlist = append(peerConfig.SubnetDiscovery.Allowlist, prefix.String())
+		}
+	}
+
+	return peerConfig
 }
 
-func ToSyncResponse(ctx context.Context, config *nbconfig.Config, httpConfig *nbconfig.HttpServerConfig, deviceFlowConfig *nbconfig.DeviceAuthorizationFlow, peer *nbpeer.Peer, turnCredentials *Token, relayCredentials *Token, networkMap *types.NetworkMap, dnsName string, checks []*posture.Checks, dnsCache *cache.DNSConfigCache, settings *types.Settings, extraSettings *types.ExtraSettings, peerGroups []string, dnsFwdPort int64) *proto.SyncResponse {
+func ToSyncResponse(ctx context.Context, config *nbconfig.Config, httpConfig *nbconfig.HttpServerConfig, deviceFlowConfig *nbconfig.DeviceAuthorizationFlow, peer *nbpeer.Peer, turnCredentials *Token, relayCredentials *Token, networkMap *types.NetworkMap, dnsName string, checks []*posture.Checks, dnsCache *cache.DNSConfigCache, settings *types.Settings, extraSettings *types.ExtraSettings, peerGroups []string, routingPeer bool, dnsFwdPort int64) *proto.SyncResponse {
 	response := &proto.SyncResponse{
-		PeerConfig: toPeerConfig(peer, networkMap.Network, dnsName, settings, httpConfig, deviceFlowConfig, networkMap.EnableSSH),
+		PeerConfig: toPeerConfig(peer, networkMap.Network, dnsName, settings, httpConfig, deviceFlowConfig, networkMap.EnableSSH, routingPeer),
 		NetworkMap: &proto.NetworkMap{
 			Serial:     networkMap.Network.CurrentSerial(),
-			Routes:     toProtocolRoutes(networkMap.Routes),
+			Routes:     toProtocolRoutes(networkMap.Routes, unhealthyRoutes(networkMap)),
 			DNSConfig:  toProtocolDNSConfig(networkMap.DNSConfig, dnsCache, dnsFwdPort),
-			PeerConfig: toPeerConfig(peer, networkMap.Network, dnsName, settings, httpConfig, deviceFlowConfig, networkMap.EnableSSH),
+			PeerConfig: toPeerConfig(peer, networkMap.Network, dnsName, settings, httpConfig, deviceFlowConfig, networkMap.EnableSSH, routingPeer),
 		},
 		Checks: toProtocolChecks(ctx, checks),
 	}
@@ -252,10 +264,27 @@ func ToResponseProto(configProto nbconfig.Protocol) proto.HostConfig_Protocol {
 	}
 }
 
-func toProtocolRoutes(routes []*route.Route) []*proto.Route {
+// unhealthyRoutes returns the routes of the network map whose routing peers report failing health checks
+func unhealthyRoutes(networkMap *types.NetworkMap) map[string]struct{} {
+	unhealthy := make(map[string]struct{})
+	for _, peers := range [][]*nbpeer.Peer{networkMap.Peers, networkMap.OfflinePeers} {
+		for _, peer := range peers {
+			for _, routeID := range peer.Meta.UnhealthyRoutes {
+				unhealthy[routeID] = struct{}{}
+			}
+		}
+	}
+	return unhealthy
+}
+
+func toProtocolRoutes(routes []*route.Route, unhealthy map[string]struct{}) []*proto.Route {
 	protoRoutes := make([]*proto.Route, 0, len(routes))
 	for _, r := range routes {
-		protoRoutes = append(protoRoutes, toProtocolRoute(r))
+		protoRoute := toProtocolRoute(r)
+		if _, ok := unhealthy[protoRoute.ID]; ok {
+			protoRoute.Unhealthy = true
+		}
+		protoRoutes = append(protoRoutes, protoRoute)
 	}
 	return protoRoutes
 }
@@ -272,6 +301,63 @@ func toProtocolRoute(route *route.Route) *proto.Route {
 		Masquerade:    route.Masquerade,
 		KeepRoute:     route.KeepRoute,
 		SkipAutoApply: route.SkipAutoApply,
+		HealthCheck:   toProtocolRouteHealthCheck(route.HealthCheck),
+		Unhealthy:     route.Unhealthy,
+		NetMap:        toProtocolRouteNetMap(route.NetMap),
+		LoadBalance:   route.LoadBalance,
+		Bgp:           toProtocolRouteBGP(route.BGP),
+		Multicast:     toProtocolRouteMulticast(route.Multicast),
+	}
+}
+
+func toProtocolRouteBGP(bgp *route.BGP) *proto.RouteBGP {
+	if bgp == nil {
+		return nil
+	}
+	protoBGP := &proto.RouteBGP{LocalASN: bgp.LocalASN}
+	for _, neighbor := range bgp.Neighbors {
+		protoBGP.Neighbors = append(protoBGP.Neighbors, &proto.RouteBGPNeighbor{
+			Address: neighbor.Address.String(),
+			Asn:     neighbor.ASN,
+		})
+	}
+	return protoBGP
+}
+
+func toProtocolRouteMulticast(multicast *route.Multicast) *proto.RouteMulticast {
+	if multicast == nil {
+		return nil
+	}
+	protoMulticast := &proto.RouteMulticast{
+		Mdns:      multicast.MDNS,
+		Ssdp:      multicast.SSDP,
+		Broadcast: multicast.Broadcast,
+	}
+	for _, group := range multicast.Groups {
+		protoMulticast.Groups = append(protoMulticast.Groups, group.String())
+	}
+	return protoMulticast
+}
+
+func toProtocolRouteNetMap(nm *route.NetMap) *proto.RouteNetMap {
+	if nm == nil {
+		return nil
+	}
+	return &proto.RouteNetMap{
+		Real:    nm.Real.String(),
+		Virtual: nm.Virtual.String(),
+	}
+}
+
+func toProtocolRouteHealthCheck(hc *route.HealthCheck) *proto.RouteHealthCheck {
+	if hc == nil {
+		return nil
+	}
+	return &proto.RouteHealthCheck{
+		Type:     string(hc.Type),
+		Target:   hc.Target,
+		Interval: durationpb.New(hc.Interval),
+		Timeout:  durationpb.New(hc.Timeout),
 	}
 }
 
@@ -12,6 +12,9 @@ import (
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map"
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map/controller/cache"
 	nbconfig "github.com/netbirdio/netbird/management/internals/server/config"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/route"
 )
 
 func TestToProtocolDNSConfigWithCache(t *testing.T) {
@@ -200,3 +203,82 @@ func TestBuildJWTConfig_Audiences(t *testing.T) {
 		})
 	}
 }
+
+func TestToProtocolRoutesMarksUnhealthyRoutes(t *testing.T) {
+	healthCheck := route.NewHealthCheck(route.HealthCheckTCP, "10.0.0.10:443", 0, 0)
+	networkMap := &types.NetworkMap{
+		Routes: []*route.Route{
+			{ID: "route-a", NetID: "office", Network: netip.MustParsePrefix("10.0.0.0/24"), Peer: "key-a", HealthCheck: healthCheck},
+			{ID: "route-b", NetID: "office", Network: netip.MustParsePrefix("10.0.0.0/24"), Peer: "key-b", HealthCheck: healthCheck},
+		},
+		Peers: []*nbpeer.Peer{
+			{ID: "peer-a", Key: "key-a", Meta: nbpeer.PeerSystemMeta{UnhealthyRoutes: []string{"route-a"}}},
+			{ID: "peer-b", Key: "key-b"},
+		},
+	}
+
+	protoRoutes := toProtocolRoutes(networkMap.Routes, unhealthyRoutes(networkMap))
+
+	assert.Len(t, protoRoutes, 2)
+	assert.True(t, protoRoutes[0].Unhealthy)
+	assert.False(t, protoRoutes[1].Unhealthy)
+	assert.Equal(t, "tcp", protoRoutes[1].HealthCheck.GetType())
+	assert.Equal(t, "10.0.0.10:443", protoRoutes[1].HealthCheck.GetTarget())
+	assert.Equal(t, route.DefaultHealthCheckInterval, protoRoutes[1].HealthCheck.GetInterval().AsDuration())
+}
+
+func TestToProtocolRoutesNetMap(t *testing.T) {
+	routes := []*route.Route{
+		{ID: "route-a", NetID: "site-a", Network: netip.MustParsePrefix("192.168.0.0/24"), Peer: "key-a",
+			NetMap: route.NewNetMap(netip.MustParsePrefix("192.168.0.0/24"), netip.MustParsePrefix("10.80.0.0/24"))},
+		{ID: "route-b", NetID: "site-b", Network: netip.MustParsePrefix("192.168.0.0/24"), Peer: "key-b"},
+	}
+
+	protoRoutes := toProtocolRoutes(routes, nil)
+
+	assert.Len(t, protoRoutes, 2)
+	assert.Equal(t, "192.168.0.0/24", protoRoutes[0].NetMap.GetReal())
+	assert.Equal(t, "10.80.0.0/24", protoRoutes[0].NetMap.GetVirtual())
+	assert.Nil(t, protoRoutes[1].NetMap)
+}
+
+func TestToProtocolRoutesBGP(t *testing.T) {
+	routes := []*route.Route{
+		{ID: "route-a", NetID: "office", Network: netip.MustParsePrefix("192.168.0.0/24"), Peer: "key-a",
+			BGP: &route.BGP{
+				LocalASN:  4200000001,
+				Neighbors: []route.BGPNeighbor{{Address: netip.MustParseAddr("192.168.0.1"), ASN: 65000}},
+			}},
+		{ID: "route-b", NetID: "office", Network: netip.MustParsePrefix("192.168.0.0/24"), Peer: "key-b"},
+	}
+
+	protoRoutes := toProtocolRoutes(routes, nil)
+
+	assert.Len(t, protoRoutes, 2)
+	assert.Equal(t, uint32(4200000001), protoRoutes[0].Bgp.GetLocalASN())
+	assert.Len(t, protoRoutes[0].Bgp.GetNeighbors(), 1)
+	assert.Equal(t, "192.168.0.1", protoRoutes[0].Bgp.GetNeighbors()[0].GetAddress())
+	assert.Equal(t, uint32(65000), protoRoutes[0].Bgp.GetNeighbors()[0].GetAsn())
+	assert.Nil(t, protoRoutes[1].Bgp)
+}
+
+func TestToProtocolRoutesMulticast(t *testing.T) {
+	routes := []*route.Route{
+		{ID: "route-a", NetID: "site", Network: netip.MustParsePrefix("192.168.0.0/24"), Peer: "key-a",
+			Multicast: &route.Multicast{
+				MDNS:      true,
+				Groups:    []netip.AddrPort{netip.MustParseAddrPort("239.1.2.3:5000")},
+				Broadcast: true,
+			}},
+		{ID: "route-b", NetID: "site", Network: netip.MustParsePrefix("192.168.0.0/24"), Peer: "key-b"},
+	}
+
+	protoRoutes := toProtocolRoutes(routes, nil)
+
+	assert.Len(t, protoRoutes, 2)
+	assert.True(t, protoRoutes[0].Multicast.GetMdns())
+	assert.False(t, protoRoutes[0].Multicast.GetSsdp())
+	assert.True(t, protoRoutes[0].Multicast.GetBroadcast())
+	assert.Equal(t, []string{"239.1.2.3:5000"}, protoRoutes[0].Multicast.GetGroups())
+	assert.Nil(t, protoRoutes[1].Multicast)
+}
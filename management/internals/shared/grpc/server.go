@@ -30,6 +30,7 @@ import (
 	"github.com/netbirdio/netbird/management/server/job"
 
 	"github.com/netbirdio/netbird/management/server/integrations/integrated_validator"
+	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
 	"github.com/netbirdio/netbird/management/server/store"
 
 	"github.com/netbirdio/netbird/encryption"
@@ -627,7 +628,9 @@ func extractPeerMeta(ctx context.Context, meta *proto.PeerSystemMeta) nbpeer.Pee
 			BlockInbound:          meta.GetFlags().GetBlockInbound(),
 			LazyConnectionEnabled: meta.GetFlags().GetLazyConnectionEnabled(),
 		},
-		Files: files,
+		Files:             files,
+		UnhealthyRoutes:   meta.GetUnhealthyRoutes(),
+		DiscoveredSubnets: meta.GetDiscoveredSubnets(),
 	}
 }
 
@@ -774,10 +777,15 @@ func (s *Server) prepareLoginResponse(ctx context.Context, peer *nbpeer.Peer, ne
 		return nil, status.Errorf(codes.Internal, "failed getting settings")
 	}
 
+	routingPeer, err := s.isRoutingPeer(ctx, settings, peer, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get network routers %s", err)
+	}
+
 	// if peer has reached this point then it has logged in
 	loginResp := &proto.LoginResponse{
 		NetbirdConfig: toNetbirdConfig(s.config, nil, relayToken, nil),
-		PeerConfig:    toPeerConfig(peer, netMap.Network, s.networkMapController.GetDNSDomain(settings), settings, s.config.HttpConfig, s.config.DeviceAuthorizationFlow, netMap.EnableSSH),
+		PeerConfig:    toPeerConfig(peer, netMap.Network, s.networkMapController.GetDNSDomain(settings), settings, s.config.HttpConfig, s.config.DeviceAuthorizationFlow, netMap.EnableSSH, routingPeer),
 		Checks:        toProtocolChecks(ctx, postureChecks),
 	}
 
@@ -844,7 +852,12 @@ func (s *Server) sendInitialSync(ctx context.Context, peerKey wgtypes.Key, peer
 		return status.Errorf(codes.Internal, "failed to get peer groups %s", err)
 	}
 
-	plainResp := ToSyncResponse(ctx, s.config, s.config.HttpConfig, s.config.DeviceAuthorizationFlow, peer, turnToken, relayToken, networkMap, s.networkMapController.GetDNSDomain(settings), postureChecks, nil, settings, settings.Extra, peerGroups, dnsFwdPort)
+	routingPeer, err := s.isRoutingPeer(ctx, settings, peer, peerGroups)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get network routers %s", err)
+	}
+
+	plainResp := ToSyncResponse(ctx, s.config, s.config.HttpConfig, s.config.DeviceAuthorizationFlow, peer, turnToken, relayToken, networkMap, s.networkMapController.GetDNSDomain(settings), postureChecks, nil, settings, settings.Extra, peerGroups, routingPeer, dnsFwdPort)
 
 	key, err := s.secretsManager.GetWGKey()
 	if err != nil {
@@ -869,6 +882,28 @@ func (s *Server) sendInitialSync(ctx context.Context, peerKey wgtypes.Key, peer
 	return nil
 }
 
+// isRoutingPeer returns true if the peer routes a network and has to report the discovered subnets. The routers, and
+// the peer groups if they aren't given, are only looked up if the subnet discovery is enabled.
+func (s *Server) isRoutingPeer(ctx context.Context, settings *types.Settings, peer *nbpeer.Peer, peerGroups []string) (bool, error) {
+	if !settings.SubnetDiscoveryEnabled {
+		return false, nil
+	}
+
+	var err error
+	if peerGroups == nil {
+		peerGroups, err = s.accountManager.GetStore().GetPeerGroupIDs(ctx, store.LockingStrengthNone, peer.AccountID, peer.ID)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	routers, err := s.accountManager.GetStore().GetNetworkRoutersByAccountID(ctx, store.LockingStrengthNone, peer.AccountID)
+	if err != nil {
+		return false, err
+	}
+	return routerTypes.IsRoutingPeer(routers, peer.ID, peerGroups), nil
+}
+
 // GetDeviceAuthorizationFlow returns a device authorization flow information
 // This is used for initiating an Oauth 2 device authorization grant flow
 // which will be used by our clients to Login
@@ -28,6 +28,7 @@ import (
 	nbdns "github.com/netbirdio/netbird/dns"
 	"github.com/netbirdio/netbird/formatter/hook"
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map"
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
 	nbconfig "github.com/netbirdio/netbird/management/internals/server/config"
 	"github.com/netbirdio/netbird/management/server/account"
 	"github.com/netbirdio/netbird/management/server/activity"
@@ -103,6 +104,9 @@ type DefaultAccountManager struct {
 
 	integratedPeerValidator integrated_validator.IntegratedValidator
 
+	// peerApprovalNotifier informs external systems about new peers pending approval
+	peerApprovalNotifier peerapproval.Notifier
+
 	metrics telemetry.AppMetrics
 
 	permissionsManager permissions.Manager
@@ -213,6 +217,7 @@ func BuildManager(
 		peerInactivityExpiry:     NewDefaultScheduler(),
 		userDeleteFromIDPEnabled: userDeleteFromIDPEnabled,
 		integratedPeerValidator:  integratedPeerValidator,
+		peerApprovalNotifier:     peerapproval.NewWebhookNotifier(),
 		metrics:                  metrics,
 		requestBuffer:            NewAccountRequestBuffer(ctx, store),
 		proxyController:          proxyController,
@@ -326,7 +331,9 @@ func (am *DefaultAccountManager) UpdateAccountSettings(ctx context.Context, acco
 		if oldSettings.RoutingPeerDNSResolutionEnabled != newSettings.RoutingPeerDNSResolutionEnabled ||
 			oldSettings.LazyConnectionEnabled != newSettings.LazyConnectionEnabled ||
 			oldSettings.DNSDomain != newSettings.DNSDomain ||
-			oldSettings.AutoUpdateVersion != newSettings.AutoUpdateVersion {
+			oldSettings.AutoUpdateVersion != newSettings.AutoUpdateVersion ||
+			oldSettings.SubnetDiscoveryEnabled != newSettings.SubnetDiscoveryEnabled ||
+			!slices.Equal(oldSettings.SubnetDiscoveryAllowlist, newSettings.SubnetDiscoveryAllowlist) {
 			updateAccountPeers = true
 		}
 
@@ -367,6 +374,7 @@ func (am *DefaultAccountManager) UpdateAccountSettings(ctx context.Context, acco
 	am.handlePeerLoginExpirationSettings(ctx, oldSettings, newSettings, userID, accountID)
 	am.handleGroupsPropagationSettings(ctx, oldSettings, newSettings, userID, accountID)
 	am.handleAutoUpdateVersionSettings(ctx, oldSettings, newSettings, userID, accountID)
+	am.handleSubnetDiscoverySettings(ctx, oldSettings, newSettings, userID, accountID)
 	if err = am.handleInactivityExpirationSettings(ctx, oldSettings, newSettings, userID, accountID); err != nil {
 		return nil, err
 	}
@@ -478,6 +486,16 @@ func (am *DefaultAccountManager) handleAutoUpdateVersionSettings(ctx context.Con
 	}
 }
 
+func (am *DefaultAccountManager) handleSubnetDiscoverySettings(ctx context.Context, oldSettings, newSettings *types.Settings, userID, accountID string) {
+	if oldSettings.SubnetDiscoveryEnabled != newSettings.SubnetDiscoveryEnabled {
+		if newSettings.SubnetDiscoveryEnabled {
+			am.StoreEvent(ctx, userID, accountID, accountID, activity.AccountSubnetDiscoveryEnabled, nil)
+		} else {
+			am.StoreEvent(ctx, userID, accountID, accountID, activity.AccountSubnetDiscoveryDisabled, nil)
+		}
+	}
+}
+
 func (am *DefaultAccountManager) handleInactivityExpirationSettings(ctx context.Context, oldSettings, newSettings *types.Settings, userID, accountID string) error {
 	if newSettings.PeerInactivityExpirationEnabled {
 		if oldSettings.PeerInactivityExpiration != newSettings.PeerInactivityExpiration {
@@ -753,6 +771,12 @@ func (am *DefaultAccountManager) AccountExists(ctx context.Context, accountID st
 	return am.Store.AccountExists(ctx, store.LockingStrengthNone, accountID)
 }
 
+// NewAccount returns a new account with a generated ID owned by the given user. It has the same default groups,
+// policy and settings as accounts created on sign up and isn't saved to the store.
+func (am *DefaultAccountManager) NewAccount(ctx context.Context, userID, domain, email, name string) (*types.Account, error) {
+	return am.newAccount(ctx, userID, domain, email, name)
+}
+
 // GetAccountIDByUserID retrieves the account ID based on the userID provided.
 // If user does have an account, it returns the user's account ID.
 // If the user doesn't have an account, it creates one using the provided domain.
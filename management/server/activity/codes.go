@@ -197,6 +197,74 @@ const (
 
 	JobCreatedByUser           Activity = 102
 
+	SCIMTokenCreated Activity = 103
+	SCIMTokenDeleted Activity = 104
+
+	// CustomRoleCreated indicates that a user created a custom role
+	CustomRoleCreated Activity = 105
+	// CustomRoleUpdated indicates that a user updated a custom role
+	CustomRoleUpdated Activity = 106
+	// CustomRoleDeleted indicates that a user deleted a custom role
+	CustomRoleDeleted Activity = 107
+
+	// SetupKeyEnrollmentRejected indicates that a peer didn't satisfy the enrollment constraints of a setup key
+	SetupKeyEnrollmentRejected Activity = 108
+
+	// PeerApprovalSettingsUpdated indicates that a user updated the peer approval settings of the account
+	PeerApprovalSettingsUpdated Activity = 109
+	// PeerApprovalPending indicates that a new peer is waiting for approval
+	PeerApprovalPending Activity = 110
+	// PeerAutoApproved indicates that a new peer has been approved by an auto-approval rule
+	PeerAutoApproved Activity = 111
+	// PeerRejected indicates that a user rejected a peer pending approval
+	PeerRejected Activity = 112
+
+	// DeclarativeConfigApplied indicates that a user applied a declarative configuration to the account
+	DeclarativeConfigApplied Activity = 113
+
+	// TenantCreated indicates that a user created an account managed by its account
+	TenantCreated Activity = 114
+	// TenantTemplateCreated indicates that a user created a tenant template
+	TenantTemplateCreated Activity = 115
+	// TenantTemplateUpdated indicates that a user updated a tenant template
+	TenantTemplateUpdated Activity = 116
+	// TenantTemplateDeleted indicates that a user deleted a tenant template
+	TenantTemplateDeleted Activity = 117
+
+	// AccessRequestCreated indicates that a user requested temporary access to a resource or group
+	AccessRequestCreated Activity = 118
+	// AccessRequestApproved indicates that a user approved an access request
+	AccessRequestApproved Activity = 119
+	// AccessRequestRejected indicates that a user rejected an access request
+	AccessRequestRejected Activity = 120
+	// AccessRequestRevoked indicates that a user cancelled an access request or revoked the granted access
+	AccessRequestRevoked Activity = 121
+	// AccessRequestExpired indicates that the access granted by a request expired
+	AccessRequestExpired Activity = 122
+	// AccessRequestSettingsUpdated indicates that a user updated the access request settings
+	AccessRequestSettingsUpdated Activity = 123
+
+	// AccountSubnetDiscoveryEnabled indicates that a user enabled the subnet discovery of routing peers
+	AccountSubnetDiscoveryEnabled Activity = 124
+	// AccountSubnetDiscoveryDisabled indicates that a user disabled the subnet discovery of routing peers
+	AccountSubnetDiscoveryDisabled Activity = 125
+	// DiscoveredSubnetApproved indicates that a discovered subnet was added as a network resource
+	DiscoveredSubnetApproved Activity = 126
+	// DiscoveredSubnetDismissed indicates that a user dismissed a discovered subnet
+	DiscoveredSubnetDismissed Activity = 127
+
+	// PortForwardCreated indicates that a user created a port forward
+	PortForwardCreated Activity = 128
+	// PortForwardUpdated indicates that a user updated a port forward
+	PortForwardUpdated Activity = 129
+	// PortForwardDeleted indicates that a user deleted a port forward
+	PortForwardDeleted Activity = 130
+
+	// RouteRolledBack indicates that a user rolled back a route to a previous version
+	RouteRolledBack Activity = 131
+	// NetworkRolledBack indicates that a user rolled back a network to a previous version
+	NetworkRolledBack Activity = 132
+
 	AccountDeleted Activity = 99999
 )
 
@@ -323,6 +391,46 @@ var activityMap = map[Activity]Code{
 	DNSRecordDeleted: {"DNS zone record deleted", "dns.zone.record.delete"},
 
 	JobCreatedByUser: {"Create Job for peer", "peer.job.create"},
+
+	SCIMTokenCreated: {"SCIM token created", "scim.token.create"},
+	SCIMTokenDeleted: {"SCIM token deleted", "scim.token.delete"},
+
+	CustomRoleCreated: {"Custom role created", "role.custom.create"},
+	CustomRoleUpdated: {"Custom role updated", "role.custom.update"},
+	CustomRoleDeleted: {"Custom role deleted", "role.custom.delete"},
+
+	SetupKeyEnrollmentRejected: {"Setup key enrollment rejected", "setupkey.enrollment.reject"},
+
+	PeerApprovalSettingsUpdated: {"Peer approval settings updated", "peer.approval.settings.update"},
+	PeerApprovalPending:         {"Peer pending approval", "peer.approval.pending"},
+	PeerAutoApproved:            {"Peer auto-approved", "peer.approval.auto"},
+	PeerRejected:                {"Peer rejected", "peer.approval.reject"},
+
+	DeclarativeConfigApplied: {"Declarative configuration applied", "declarative.apply"},
+
+	TenantCreated:         {"Tenant created", "tenant.create"},
+	TenantTemplateCreated: {"Tenant template created", "tenant.template.create"},
+	TenantTemplateUpdated: {"Tenant template updated", "tenant.template.update"},
+	TenantTemplateDeleted: {"Tenant template deleted", "tenant.template.delete"},
+
+	AccessRequestCreated:         {"Access requested", "access.request.create"},
+	AccessRequestApproved:        {"Access request approved", "access.request.approve"},
+	AccessRequestRejected:        {"Access request rejected", "access.request.reject"},
+	AccessRequestRevoked:         {"Access request revoked", "access.request.revoke"},
+	AccessRequestExpired:         {"Access request expired", "access.request.expire"},
+	AccessRequestSettingsUpdated: {"Access request settings updated", "access.request.settings.update"},
+
+	AccountSubnetDiscoveryEnabled:  {"Account subnet discovery enabled", "account.setting.subnet.discovery.enable"},
+	AccountSubnetDiscoveryDisabled: {"Account subnet discovery disabled", "account.setting.subnet.discovery.disable"},
+	DiscoveredSubnetApproved:       {"Discovered subnet approved", "discovered.subnet.approve"},
+	DiscoveredSubnetDismissed:      {"Discovered subnet dismissed", "discovered.subnet.dismiss"},
+
+	PortForwardCreated: {"Port forward created", "port.forward.create"},
+	PortForwardUpdated: {"Port forward updated", "port.forward.update"},
+	PortForwardDeleted: {"Port forward deleted", "port.forward.delete"},
+
+	RouteRolledBack:   {"Route rolled back", "route.rollback"},
+	NetworkRolledBack: {"Network rolled back", "network.rollback"},
 }
 
 // StringCode returns a string code of the activity
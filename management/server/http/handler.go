@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/netip"
 	"os"
 	"strconv"
 	"time"
@@ -15,6 +16,27 @@ import (
 
 	"github.com/netbirdio/management-integrations/integrations"
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map"
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	accessRequestsManager "github.com/netbirdio/netbird/management/internals/modules/accessrequests/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/customroles"
+	customRolesManager "github.com/netbirdio/netbird/management/internals/modules/customroles/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/declarative"
+	declarativeManager "github.com/netbirdio/netbird/management/internals/modules/declarative/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/history"
+	historyManager "github.com/netbirdio/netbird/management/internals/modules/history/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	peerApprovalManager "github.com/netbirdio/netbird/management/internals/modules/peerapproval/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/portforwards"
+	portForwardsManager "github.com/netbirdio/netbird/management/internals/modules/portforwards/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/reports"
+	reportsManager "github.com/netbirdio/netbird/management/internals/modules/reports/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	scimManager "github.com/netbirdio/netbird/management/internals/modules/scim/manager"
+	scimServer "github.com/netbirdio/netbird/management/internals/modules/scim/server"
+	"github.com/netbirdio/netbird/management/internals/modules/subnetdiscovery"
+	subnetDiscoveryManager "github.com/netbirdio/netbird/management/internals/modules/subnetdiscovery/manager"
+	"github.com/netbirdio/netbird/management/internals/modules/tenants"
+	tenantsManager "github.com/netbirdio/netbird/management/internals/modules/tenants/manager"
 	"github.com/netbirdio/netbird/management/internals/modules/zones"
 	zonesManager "github.com/netbirdio/netbird/management/internals/modules/zones/manager"
 	"github.com/netbirdio/netbird/management/internals/modules/zones/records"
@@ -35,6 +57,7 @@ import (
 	"github.com/netbirdio/netbird/management/server/http/handlers/groups"
 	"github.com/netbirdio/netbird/management/server/http/handlers/idp"
 	"github.com/netbirdio/netbird/management/server/http/handlers/instance"
+	"github.com/netbirdio/netbird/management/server/http/handlers/networkmaps"
 	"github.com/netbirdio/netbird/management/server/http/handlers/networks"
 	"github.com/netbirdio/netbird/management/server/http/handlers/peers"
 	"github.com/netbirdio/netbird/management/server/http/handlers/policies"
@@ -59,7 +82,7 @@ const (
 )
 
 // NewAPIHandler creates the Management service HTTP API handler registering all the available endpoints.
-func NewAPIHandler(ctx context.Context, accountManager account.Manager, networksManager nbnetworks.Manager, resourceManager resources.Manager, routerManager routers.Manager, groupsManager nbgroups.Manager, LocationManager geolocation.Geolocation, authManager auth.Manager, appMetrics telemetry.AppMetrics, integratedValidator integrated_validator.IntegratedValidator, proxyController port_forwarding.Controller, permissionsManager permissions.Manager, peersManager nbpeers.Manager, settingsManager settings.Manager, zManager zones.Manager, rManager records.Manager, networkMapController network_map.Controller, idpManager idpmanager.Manager) (http.Handler, error) {
+func NewAPIHandler(ctx context.Context, accountManager account.Manager, networksManager nbnetworks.Manager, resourceManager resources.Manager, routerManager routers.Manager, groupsManager nbgroups.Manager, LocationManager geolocation.Geolocation, authManager auth.Manager, appMetrics telemetry.AppMetrics, integratedValidator integrated_validator.IntegratedValidator, proxyController port_forwarding.Controller, permissionsManager permissions.Manager, peersManager nbpeers.Manager, settingsManager settings.Manager, zManager zones.Manager, rManager records.Manager, scimMgr scim.Manager, customRolesMgr customroles.Manager, peerApprovalMgr peerapproval.Manager, declarativeMgr declarative.Manager, tenantsMgr tenants.Manager, accessRequestsMgr accessrequests.Manager, subnetDiscoveryMgr subnetdiscovery.Manager, portForwardsMgr portforwards.Manager, historyMgr history.Manager, reportsMgr reports.Manager, networkMapController network_map.Controller, idpManager idpmanager.Manager, trustedHTTPProxies []netip.Prefix) (http.Handler, error) {
 
 	// Register bypass paths for unauthenticated endpoints
 	if err := bypass.AddBypassPath("/api/instance"); err != nil {
@@ -106,6 +129,7 @@ func NewAPIHandler(ctx context.Context, accountManager account.Manager, networks
 		accountManager.GetUserFromUserAuth,
 		rateLimitingConfig,
 		appMetrics.GetMeter(),
+		trustedHTTPProxies,
 	)
 
 	corsMiddleware := cors.AllowAll()
@@ -131,6 +155,7 @@ func NewAPIHandler(ctx context.Context, accountManager account.Manager, networks
 
 	accounts.AddEndpoints(accountManager, settingsManager, embeddedIdpEnabled, router)
 	peers.AddEndpoints(accountManager, router, networkMapController)
+	networkmaps.AddEndpoints(accountManager, networkMapController, permissionsManager, router)
 	users.AddEndpoints(accountManager, router)
 	setup_keys.AddEndpoints(accountManager, router)
 	policies.AddEndpoints(accountManager, LocationManager, router)
@@ -143,9 +168,24 @@ func NewAPIHandler(ctx context.Context, accountManager account.Manager, networks
 	networks.AddEndpoints(networksManager, resourceManager, routerManager, groupsManager, accountManager, router)
 	zonesManager.RegisterEndpoints(router, zManager)
 	recordsManager.RegisterEndpoints(router, rManager)
+	scimManager.RegisterEndpoints(router, scimMgr)
+	customRolesManager.RegisterEndpoints(router, customRolesMgr)
+	peerApprovalManager.RegisterEndpoints(router, peerApprovalMgr)
+	declarativeManager.RegisterEndpoints(router, declarativeMgr)
+	tenantsManager.RegisterEndpoints(router, tenantsMgr)
+	accessRequestsManager.RegisterEndpoints(router, accessRequestsMgr)
+	subnetDiscoveryManager.RegisterEndpoints(router, subnetDiscoveryMgr)
+	portForwardsManager.RegisterEndpoints(router, portForwardsMgr)
+	historyManager.RegisterEndpoints(router, historyMgr)
+	reportsManager.RegisterEndpoints(router, reportsMgr)
 	idp.AddEndpoints(accountManager, router)
 	instance.AddEndpoints(instanceManager, router)
 
+	// SCIM requests are authenticated with the SCIM token of the account instead of the API authentication
+	scimRouter := rootRouter.PathPrefix(scim.BasePath).Subrouter()
+	scimRouter.Use(metricsMiddleware.Handler)
+	scimServer.RegisterEndpoints(scimRouter, scimMgr, accountManager)
+
 	// Mount embedded IdP handler at /oauth2 path if configured
 	if embeddedIdpEnabled {
 		rootRouter.PathPrefix("/oauth2").Handler(corsMiddleware.Handler(embeddedIdP.Handler()))
@@ -220,6 +220,21 @@ func (h *handler) updateAccountRequestSettings(req api.PutApiAccountsAccountIdJS
 			return nil, fmt.Errorf("invalid AutoUpdateVersion")
 		}
 	}
+	if req.Settings.SubnetDiscoveryEnabled != nil {
+		returnSettings.SubnetDiscoveryEnabled = *req.Settings.SubnetDiscoveryEnabled
+	}
+	if req.Settings.SubnetDiscoveryAutoApprove != nil {
+		returnSettings.SubnetDiscoveryAutoApprove = *req.Settings.SubnetDiscoveryAutoApprove
+	}
+	if req.Settings.SubnetDiscoveryAllowlist != nil {
+		for _, entry := range *req.Settings.SubnetDiscoveryAllowlist {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, status.Errorf(status.InvalidArgument, "invalid subnet discovery allowlist entry %q: %v", entry, err)
+			}
+			returnSettings.SubnetDiscoveryAllowlist = append(returnSettings.SubnetDiscoveryAllowlist, prefix.Masked())
+		}
+	}
 
 	return returnSettings, nil
 }
@@ -327,6 +342,11 @@ func toAccountResponse(accountID string, settings *types.Settings, meta *types.A
 		jwtAllowGroups = []string{}
 	}
 
+	subnetDiscoveryAllowlist := make([]string, 0, len(settings.SubnetDiscoveryAllowlist))
+	for _, prefix := range settings.SubnetDiscoveryAllowlist {
+		subnetDiscoveryAllowlist = append(subnetDiscoveryAllowlist, prefix.String())
+	}
+
 	apiSettings := api.AccountSettings{
 		PeerLoginExpiration:             int(settings.PeerLoginExpiration.Seconds()),
 		PeerLoginExpirationEnabled:      settings.PeerLoginExpirationEnabled,
@@ -342,6 +362,9 @@ func toAccountResponse(accountID string, settings *types.Settings, meta *types.A
 		DnsDomain:                       &settings.DNSDomain,
 		AutoUpdateVersion:               &settings.AutoUpdateVersion,
 		EmbeddedIdpEnabled:              &embeddedIdpEnabled,
+		SubnetDiscoveryEnabled:          &settings.SubnetDiscoveryEnabled,
+		SubnetDiscoveryAllowlist:        &subnetDiscoveryAllowlist,
+		SubnetDiscoveryAutoApprove:      &settings.SubnetDiscoveryAutoApprove,
 	}
 
 	if settings.NetworkRange.IsValid() {
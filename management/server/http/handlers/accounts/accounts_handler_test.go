@@ -124,6 +124,9 @@ func TestAccounts_AccountsHandler(t *testing.T) {
 				DnsDomain:                       sr(""),
 				AutoUpdateVersion:               sr(""),
 				EmbeddedIdpEnabled:              br(false),
+				SubnetDiscoveryEnabled:          br(false),
+				SubnetDiscoveryAllowlist:        &[]string{},
+				SubnetDiscoveryAutoApprove:      br(false),
 			},
 			expectedArray: true,
 			expectedID:    accountID,
@@ -148,6 +151,9 @@ func TestAccounts_AccountsHandler(t *testing.T) {
 				DnsDomain:                       sr(""),
 				AutoUpdateVersion:               sr(""),
 				EmbeddedIdpEnabled:              br(false),
+				SubnetDiscoveryEnabled:          br(false),
+				SubnetDiscoveryAllowlist:        &[]string{},
+				SubnetDiscoveryAutoApprove:      br(false),
 			},
 			expectedArray: false,
 			expectedID:    accountID,
@@ -172,6 +178,9 @@ func TestAccounts_AccountsHandler(t *testing.T) {
 				DnsDomain:                       sr(""),
 				AutoUpdateVersion:               sr("latest"),
 				EmbeddedIdpEnabled:              br(false),
+				SubnetDiscoveryEnabled:          br(false),
+				SubnetDiscoveryAllowlist:        &[]string{},
+				SubnetDiscoveryAutoApprove:      br(false),
 			},
 			expectedArray: false,
 			expectedID:    accountID,
@@ -196,6 +205,9 @@ func TestAccounts_AccountsHandler(t *testing.T) {
 				DnsDomain:                       sr(""),
 				AutoUpdateVersion:               sr(""),
 				EmbeddedIdpEnabled:              br(false),
+				SubnetDiscoveryEnabled:          br(false),
+				SubnetDiscoveryAllowlist:        &[]string{},
+				SubnetDiscoveryAutoApprove:      br(false),
 			},
 			expectedArray: false,
 			expectedID:    accountID,
@@ -220,6 +232,9 @@ func TestAccounts_AccountsHandler(t *testing.T) {
 				DnsDomain:                       sr(""),
 				AutoUpdateVersion:               sr(""),
 				EmbeddedIdpEnabled:              br(false),
+				SubnetDiscoveryEnabled:          br(false),
+				SubnetDiscoveryAllowlist:        &[]string{},
+				SubnetDiscoveryAutoApprove:      br(false),
 			},
 			expectedArray: false,
 			expectedID:    accountID,
@@ -244,6 +259,9 @@ func TestAccounts_AccountsHandler(t *testing.T) {
 				DnsDomain:                       sr(""),
 				AutoUpdateVersion:               sr(""),
 				EmbeddedIdpEnabled:              br(false),
+				SubnetDiscoveryEnabled:          br(false),
+				SubnetDiscoveryAllowlist:        &[]string{},
+				SubnetDiscoveryAutoApprove:      br(false),
 			},
 			expectedArray: false,
 			expectedID:    accountID,
@@ -0,0 +1,328 @@
+package networkmaps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/miekg/dns"
+
+	"github.com/netbirdio/netbird/management/internals/controllers/network_map"
+	"github.com/netbirdio/netbird/management/server/account"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/http/util"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// handler returns snapshots of the network maps computed for the peers of an account
+type handler struct {
+	accountManager       account.Manager
+	networkMapController network_map.Controller
+	permissionsManager   permissions.Manager
+}
+
+func AddEndpoints(accountManager account.Manager, networkMapController network_map.Controller, permissionsManager permissions.Manager, router *mux.Router) {
+	h := newHandler(accountManager, networkMapController, permissionsManager)
+	router.HandleFunc("/peers/{peerId}/network-map", h.getPeerNetworkMap).Methods("GET", "OPTIONS")
+	router.HandleFunc("/network-maps", h.getAllNetworkMaps).Methods("GET", "OPTIONS")
+}
+
+func newHandler(accountManager account.Manager, networkMapController network_map.Controller, permissionsManager permissions.Manager) *handler {
+	return &handler{
+		accountManager:       accountManager,
+		networkMapController: networkMapController,
+		permissionsManager:   permissionsManager,
+	}
+}
+
+func (h *handler) getPeerNetworkMap(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userAuth, err := nbcontext.GetUserAuthFromContext(ctx)
+	if err != nil {
+		util.WriteError(ctx, err, w)
+		return
+	}
+
+	peerID := mux.Vars(r)["peerId"]
+	if len(peerID) == 0 {
+		util.WriteError(ctx, status.Errorf(status.InvalidArgument, "invalid peer ID"), w)
+		return
+	}
+
+	if err := h.validatePermissions(ctx, userAuth.AccountId, userAuth.UserId); err != nil {
+		util.WriteError(ctx, err, w)
+		return
+	}
+
+	// also ensures that the peer belongs to the account of the user
+	peer, err := h.accountManager.GetPeer(ctx, userAuth.AccountId, peerID, userAuth.UserId)
+	if err != nil {
+		util.WriteError(ctx, err, w)
+		return
+	}
+
+	if scope := types.PATScopeFromContext(ctx); scope.RestrictsPeers() {
+		peerGroups, err := h.accountManager.GetPeerGroups(ctx, userAuth.AccountId, peer.ID)
+		if err != nil {
+			util.WriteError(ctx, err, w)
+			return
+		}
+		groupIDs := make([]string, 0, len(peerGroups))
+		for _, group := range peerGroups {
+			groupIDs = append(groupIDs, group.ID)
+		}
+		if !scope.AllowsPeerGroups(groupIDs) {
+			util.WriteError(ctx, status.Errorf(status.NotFound, "peer not found"), w)
+			return
+		}
+	}
+
+	networkMap, err := h.networkMapController.GetNetworkMap(ctx, peer.ID)
+	if err != nil {
+		util.WriteError(ctx, err, w)
+		return
+	}
+
+	util.WriteJSONObject(ctx, w, toSnapshot(peer, networkMap, time.Now().UTC()))
+}
+
+func (h *handler) getAllNetworkMaps(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userAuth, err := nbcontext.GetUserAuthFromContext(ctx)
+	if err != nil {
+		util.WriteError(ctx, err, w)
+		return
+	}
+
+	if err := h.validatePermissions(ctx, userAuth.AccountId, userAuth.UserId); err != nil {
+		util.WriteError(ctx, err, w)
+		return
+	}
+
+	peers, err := h.accountManager.GetPeers(ctx, userAuth.AccountId, userAuth.UserId, "", "")
+	if err != nil {
+		util.WriteError(ctx, err, w)
+		return
+	}
+
+	networkMaps, err := h.networkMapController.GetAccountNetworkMaps(ctx, userAuth.AccountId)
+	if err != nil {
+		util.WriteError(ctx, err, w)
+		return
+	}
+
+	var peersInScope map[string]struct{}
+	if scope := types.PATScopeFromContext(ctx); scope.RestrictsPeers() {
+		accountGroups, err := h.accountManager.GetStore().GetAccountGroups(ctx, store.LockingStrengthNone, userAuth.AccountId)
+		if err != nil {
+			util.WriteError(ctx, err, w)
+			return
+		}
+		peersInScope = scope.PeersInScope(accountGroups)
+	}
+
+	generatedAt := time.Now().UTC()
+	snapshots := make([]*api.NetworkMapSnapshot, 0, len(peers))
+	for _, peer := range peers {
+		if _, ok := peersInScope[peer.ID]; peersInScope != nil && !ok {
+			continue
+		}
+		networkMap, ok := networkMaps[peer.ID]
+		if !ok {
+			continue
+		}
+		snapshots = append(snapshots, toSnapshot(peer, networkMap, generatedAt))
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Peer.Id < snapshots[j].Peer.Id
+	})
+
+	util.WriteJSONObject(ctx, w, snapshots)
+}
+
+// validatePermissions checks that the user may read both the peers and the policies the network maps are derived from
+func (h *handler) validatePermissions(ctx context.Context, accountID, userID string) error {
+	for _, module := range []modules.Module{modules.Peers, modules.Policies} {
+		allowed, err := h.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, module, operations.Read)
+		if err != nil {
+			return status.NewPermissionValidationError(err)
+		}
+		if !allowed {
+			return status.NewPermissionDeniedError()
+		}
+	}
+	return nil
+}
+
+// toSnapshot converts the network map of a peer. All lists are sorted to make snapshots comparable.
+func toSnapshot(peer *nbpeer.Peer, networkMap *types.NetworkMap, generatedAt time.Time) *api.NetworkMapSnapshot {
+	snapshot := &api.NetworkMapSnapshot{
+		Peer:               toSnapshotPeer(peer),
+		GeneratedAt:        generatedAt,
+		SshEnabled:         networkMap.EnableSSH,
+		Peers:              toSnapshotPeers(networkMap.Peers),
+		OfflinePeers:       toSnapshotPeers(networkMap.OfflinePeers),
+		FirewallRules:      make([]api.NetworkMapFirewallRule, 0, len(networkMap.FirewallRules)),
+		Routes:             make([]api.NetworkMapRoute, 0, len(networkMap.Routes)),
+		RouteFirewallRules: make([]api.NetworkMapRouteFirewallRule, 0, len(networkMap.RoutesFirewallRules)),
+		DnsRecords:         []api.NetworkMapDNSRecord{},
+		NameserverGroups:   make([]api.NetworkMapNameserverGroup, 0, len(networkMap.DNSConfig.NameServerGroups)),
+	}
+
+	for _, rule := range networkMap.FirewallRules {
+		direction := api.NetworkMapFirewallRuleDirectionIn
+		if rule.Direction == types.FirewallRuleDirectionOUT {
+			direction = api.NetworkMapFirewallRuleDirectionOut
+		}
+		snapshot.FirewallRules = append(snapshot.FirewallRules, api.NetworkMapFirewallRule{
+			PolicyId:  rule.PolicyID,
+			PeerIp:    rule.PeerIP,
+			Direction: direction,
+			Action:    rule.Action,
+			Protocol:  rule.Protocol,
+			Ports:     formatPorts(rule.Port, rule.PortRange),
+		})
+	}
+	sort.Slice(snapshot.FirewallRules, func(i, j int) bool {
+		return FirewallRuleKey(snapshot.FirewallRules[i]) < FirewallRuleKey(snapshot.FirewallRules[j])
+	})
+
+	for _, r := range networkMap.Routes {
+		route := api.NetworkMapRoute{
+			Id:         string(r.ID),
+			NetworkId:  string(r.NetID),
+			Domains:    r.Domains.ToPunycodeList(),
+			Peer:       r.Peer,
+			Metric:     r.Metric,
+			Masquerade: r.Masquerade,
+			KeepRoute:  r.KeepRoute,
+		}
+		if r.Network.IsValid() && !r.IsDynamic() {
+			route.Network = r.Network.String()
+		}
+		snapshot.Routes = append(snapshot.Routes, route)
+	}
+	sort.Slice(snapshot.Routes, func(i, j int) bool {
+		return snapshot.Routes[i].Id < snapshot.Routes[j].Id
+	})
+
+	for _, rule := range networkMap.RoutesFirewallRules {
+		var port string
+		if rule.Port != 0 {
+			port = strconv.Itoa(int(rule.Port))
+		}
+		sourceRanges := append([]string{}, rule.SourceRanges...)
+		sort.Strings(sourceRanges)
+		snapshot.RouteFirewallRules = append(snapshot.RouteFirewallRules, api.NetworkMapRouteFirewallRule{
+			PolicyId:     rule.PolicyID,
+			RouteId:      string(rule.RouteID),
+			SourceRanges: sourceRanges,
+			Destination:  rule.Destination,
+			Domains:      rule.Domains.ToPunycodeList(),
+			Action:       rule.Action,
+			Protocol:     rule.Protocol,
+			Ports:        formatPorts(port, rule.PortRange),
+		})
+	}
+	sort.Slice(snapshot.RouteFirewallRules, func(i, j int) bool {
+		return RouteFirewallRuleKey(snapshot.RouteFirewallRules[i]) < RouteFirewallRuleKey(snapshot.RouteFirewallRules[j])
+	})
+
+	for _, zone := range networkMap.DNSConfig.CustomZones {
+		for _, record := range zone.Records {
+			snapshot.DnsRecords = append(snapshot.DnsRecords, api.NetworkMapDNSRecord{
+				Name:  record.Name,
+				Type:  dns.TypeToString[uint16(record.Type)],
+				Ttl:   record.TTL,
+				Rdata: record.RData,
+			})
+		}
+	}
+	sort.Slice(snapshot.DnsRecords, func(i, j int) bool {
+		return DNSRecordKey(snapshot.DnsRecords[i]) < DNSRecordKey(snapshot.DnsRecords[j])
+	})
+
+	for _, group := range networkMap.DNSConfig.NameServerGroups {
+		nameservers := make([]string, 0, len(group.NameServers))
+		for _, ns := range group.NameServers {
+			nameservers = append(nameservers, fmt.Sprintf("%s://%s", ns.NSType, ns.AddrPort()))
+		}
+		snapshot.NameserverGroups = append(snapshot.NameserverGroups, api.NetworkMapNameserverGroup{
+			Id:          group.ID,
+			Name:        group.Name,
+			Primary:     group.Primary,
+			Domains:     append([]string{}, group.Domains...),
+			Nameservers: nameservers,
+		})
+	}
+	sort.Slice(snapshot.NameserverGroups, func(i, j int) bool {
+		return snapshot.NameserverGroups[i].Id < snapshot.NameserverGroups[j].Id
+	})
+
+	return snapshot
+}
+
+func toSnapshotPeer(peer *nbpeer.Peer) api.NetworkMapPeer {
+	return api.NetworkMapPeer{
+		Id:         peer.ID,
+		Name:       peer.Name,
+		Ip:         peer.IP.String(),
+		DnsLabel:   peer.DNSLabel,
+		SshEnabled: peer.SSHEnabled,
+	}
+}
+
+func toSnapshotPeers(peers []*nbpeer.Peer) []api.NetworkMapPeer {
+	result := make([]api.NetworkMapPeer, 0, len(peers))
+	for _, peer := range peers {
+		result = append(result, toSnapshotPeer(peer))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Id < result[j].Id
+	})
+	return result
+}
+
+func formatPorts(port string, portRange types.RulePortRange) string {
+	if port != "" {
+		return port
+	}
+	if portRange.Start == 0 && portRange.End == 0 {
+		return ""
+	}
+	if portRange.Start == portRange.End {
+		return strconv.Itoa(int(portRange.Start))
+	}
+	return fmt.Sprintf("%d-%d", portRange.Start, portRange.End)
+}
+
+// FirewallRuleKey identifies a firewall rule of a snapshot
+func FirewallRuleKey(rule api.NetworkMapFirewallRule) string {
+	return strings.Join([]string{string(rule.Direction), rule.PeerIp, rule.Protocol, rule.Ports, rule.Action, rule.PolicyId}, " ")
+}
+
+// RouteFirewallRuleKey identifies a route firewall rule of a snapshot
+func RouteFirewallRuleKey(rule api.NetworkMapRouteFirewallRule) string {
+	destination := rule.Destination
+	if len(rule.Domains) > 0 {
+		destination = strings.Join(rule.Domains, ",")
+	}
+	return strings.Join([]string{rule.RouteId, strings.Join(rule.SourceRanges, ","), destination, rule.Protocol, rule.Ports, rule.Action, rule.PolicyId}, " ")
+}
+
+// DNSRecordKey identifies a DNS record of a snapshot
+func DNSRecordKey(record api.NetworkMapDNSRecord) string {
+	return strings.Join([]string{record.Name, record.Type, record.Rdata}, " ")
+}
@@ -0,0 +1,210 @@
+package networkmaps
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mockgomock "go.uber.org/mock/gomock"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+	"github.com/netbirdio/netbird/management/internals/controllers/network_map"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/route"
+	"github.com/netbirdio/netbird/shared/auth"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	testAccountID = "test_account"
+	testUserID    = "test_user"
+)
+
+var (
+	peerA = &nbpeer.Peer{ID: "peer-a", Name: "peer-a", IP: net.ParseIP("100.64.0.1"), DNSLabel: "peer-a"}
+	peerB = &nbpeer.Peer{ID: "peer-b", Name: "peer-b", IP: net.ParseIP("100.64.0.2"), DNSLabel: "peer-b"}
+	peerC = &nbpeer.Peer{ID: "peer-c", Name: "peer-c", IP: net.ParseIP("100.64.0.3"), DNSLabel: "peer-c"}
+)
+
+func testNetworkMap() *types.NetworkMap {
+	return &types.NetworkMap{
+		Peers:        []*nbpeer.Peer{peerC, peerB},
+		OfflinePeers: []*nbpeer.Peer{},
+		EnableSSH:    true,
+		FirewallRules: []*types.FirewallRule{
+			{PolicyID: "policy", PeerIP: "100.64.0.3", Direction: types.FirewallRuleDirectionOUT, Action: "accept", Protocol: "tcp", Port: "443"},
+			{PolicyID: "policy", PeerIP: "100.64.0.2", Direction: types.FirewallRuleDirectionIN, Action: "accept", Protocol: "tcp", PortRange: types.RulePortRange{Start: 8000, End: 8080}},
+		},
+		Routes: []*route.Route{
+			{ID: "route", NetID: "office", Network: netip.MustParsePrefix("10.0.0.0/24"), Peer: "peer-b", Metric: 9999, Masquerade: true},
+		},
+		RoutesFirewallRules: []*types.RouteFirewallRule{
+			{PolicyID: "policy", RouteID: "route", SourceRanges: []string{"100.64.0.3/32", "100.64.0.1/32"}, Destination: "10.0.0.0/24", Action: "accept", Protocol: "all"},
+		},
+		DNSConfig: nbdns.Config{
+			ServiceEnable: true,
+			CustomZones: []nbdns.CustomZone{
+				{
+					Domain: "netbird.cloud.",
+					Records: []nbdns.SimpleRecord{
+						{Name: "peer-c.netbird.cloud.", Type: 1, Class: nbdns.DefaultClass, TTL: 300, RData: "100.64.0.3"},
+						{Name: "peer-b.netbird.cloud.", Type: 1, Class: nbdns.DefaultClass, TTL: 300, RData: "100.64.0.2"},
+					},
+				},
+			},
+			NameServerGroups: []*nbdns.NameServerGroup{
+				{
+					ID:          "ns",
+					Name:        "google",
+					Primary:     true,
+					NameServers: []nbdns.NameServer{{IP: netip.MustParseAddr("8.8.8.8"), NSType: nbdns.UDPNameServerType, Port: 53}},
+				},
+			},
+		},
+	}
+}
+
+func initTestHandler(t *testing.T, allowed bool) *handler {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	permissionsManager := permissions.NewMockManager(ctrl)
+	permissionsManager.EXPECT().
+		ValidateUserPermissions(gomock.Any(), testAccountID, testUserID, gomock.Any(), operations.Read).
+		DoAndReturn(func(_ context.Context, _, _ string, module modules.Module, _ operations.Operation) (bool, error) {
+			// both modules are required, deny on the second one to cover the loop
+			return allowed || module == modules.Peers, nil
+		}).
+		AnyTimes()
+
+	// the network map controller mock is generated with the go.uber.org fork of gomock
+	networkMapController := network_map.NewMockController(mockgomock.NewController(t))
+	networkMapController.EXPECT().
+		GetNetworkMap(mockgomock.Any(), peerA.ID).
+		Return(testNetworkMap(), nil).
+		AnyTimes()
+	networkMapController.EXPECT().
+		GetAccountNetworkMaps(mockgomock.Any(), testAccountID).
+		Return(map[string]*types.NetworkMap{
+			peerA.ID: testNetworkMap(),
+			peerB.ID: {},
+		}, nil).
+		AnyTimes()
+
+	peers := []*nbpeer.Peer{peerB, peerA}
+	return newHandler(&mock_server.MockAccountManager{
+		GetPeerFunc: func(_ context.Context, accountID, peerID, userID string) (*nbpeer.Peer, error) {
+			for _, peer := range peers {
+				if peer.ID == peerID {
+					return peer, nil
+				}
+			}
+			return nil, status.Errorf(status.NotFound, "peer not found")
+		},
+		GetPeersFunc: func(_ context.Context, accountID, userID, nameFilter, ipFilter string) ([]*nbpeer.Peer, error) {
+			return peers, nil
+		},
+	}, networkMapController, permissionsManager)
+}
+
+func doRequest(t *testing.T, h *handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req = nbcontext.SetUserAuthInRequest(req, auth.UserAuth{
+		UserId:    testUserID,
+		AccountId: testAccountID,
+	})
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/peers/{peerId}/network-map", h.getPeerNetworkMap).Methods("GET")
+	router.HandleFunc("/api/network-maps", h.getAllNetworkMaps).Methods("GET")
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestGetPeerNetworkMap(t *testing.T) {
+	h := initTestHandler(t, true)
+
+	recorder := doRequest(t, h, "/api/peers/peer-a/network-map")
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var snapshot api.NetworkMapSnapshot
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &snapshot))
+
+	assert.Equal(t, "peer-a", snapshot.Peer.Id)
+	assert.Equal(t, "100.64.0.1", snapshot.Peer.Ip)
+	assert.True(t, snapshot.SshEnabled)
+	assert.WithinDuration(t, time.Now(), snapshot.GeneratedAt, time.Minute)
+
+	require.Len(t, snapshot.Peers, 2)
+	assert.Equal(t, "peer-b", snapshot.Peers[0].Id)
+	assert.Equal(t, "peer-c", snapshot.Peers[1].Id)
+	assert.Empty(t, snapshot.OfflinePeers)
+
+	assert.Equal(t, []api.NetworkMapFirewallRule{
+		{PolicyId: "policy", PeerIp: "100.64.0.2", Direction: api.NetworkMapFirewallRuleDirectionIn, Action: "accept", Protocol: "tcp", Ports: "8000-8080"},
+		{PolicyId: "policy", PeerIp: "100.64.0.3", Direction: api.NetworkMapFirewallRuleDirectionOut, Action: "accept", Protocol: "tcp", Ports: "443"},
+	}, snapshot.FirewallRules)
+
+	require.Len(t, snapshot.Routes, 1)
+	assert.Equal(t, "10.0.0.0/24", snapshot.Routes[0].Network)
+	assert.Equal(t, "office", snapshot.Routes[0].NetworkId)
+	assert.True(t, snapshot.Routes[0].Masquerade)
+
+	require.Len(t, snapshot.RouteFirewallRules, 1)
+	assert.Equal(t, []string{"100.64.0.1/32", "100.64.0.3/32"}, snapshot.RouteFirewallRules[0].SourceRanges)
+
+	assert.Equal(t, []api.NetworkMapDNSRecord{
+		{Name: "peer-b.netbird.cloud.", Type: "A", Ttl: 300, Rdata: "100.64.0.2"},
+		{Name: "peer-c.netbird.cloud.", Type: "A", Ttl: 300, Rdata: "100.64.0.3"},
+	}, snapshot.DnsRecords)
+
+	require.Len(t, snapshot.NameserverGroups, 1)
+	assert.Equal(t, []string{"udp://8.8.8.8:53"}, snapshot.NameserverGroups[0].Nameservers)
+}
+
+func TestGetPeerNetworkMap_Errors(t *testing.T) {
+	recorder := doRequest(t, initTestHandler(t, true), "/api/peers/unknown/network-map")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = doRequest(t, initTestHandler(t, false), "/api/peers/peer-a/network-map")
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestGetAllNetworkMaps(t *testing.T) {
+	h := initTestHandler(t, true)
+
+	recorder := doRequest(t, h, "/api/network-maps")
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var snapshots []api.NetworkMapSnapshot
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &snapshots))
+
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, "peer-a", snapshots[0].Peer.Id)
+	assert.Len(t, snapshots[0].FirewallRules, 2)
+	assert.Equal(t, "peer-b", snapshots[1].Peer.Id)
+	assert.Empty(t, snapshots[1].FirewallRules)
+	assert.NotNil(t, snapshots[1].Peers)
+
+	recorder = doRequest(t, initTestHandler(t, false), "/api/network-maps")
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
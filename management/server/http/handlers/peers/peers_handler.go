@@ -17,6 +17,7 @@ import (
 	nbcontext "github.com/netbirdio/netbird/management/server/context"
 	"github.com/netbirdio/netbird/management/server/groups"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/store"
 	"github.com/netbirdio/netbird/management/server/types"
 	"github.com/netbirdio/netbird/shared/management/http/api"
 	"github.com/netbirdio/netbird/shared/management/http/util"
@@ -32,13 +33,70 @@ type Handler struct {
 func AddEndpoints(accountManager account.Manager, router *mux.Router, networkMapController network_map.Controller) {
 	peersHandler := NewHandler(accountManager, networkMapController)
 	router.HandleFunc("/peers", peersHandler.GetAllPeers).Methods("GET", "OPTIONS")
-	router.HandleFunc("/peers/{peerId}", peersHandler.HandlePeer).
+	router.HandleFunc("/peers/{peerId}", peersHandler.withPeerScope(peersHandler.HandlePeer)).
 		Methods("GET", "PUT", "DELETE", "OPTIONS")
-	router.HandleFunc("/peers/{peerId}/accessible-peers", peersHandler.GetAccessiblePeers).Methods("GET", "OPTIONS")
-	router.HandleFunc("/peers/{peerId}/temporary-access", peersHandler.CreateTemporaryAccess).Methods("POST", "OPTIONS")
-	router.HandleFunc("/peers/{peerId}/jobs", peersHandler.ListJobs).Methods("GET", "OPTIONS")
-	router.HandleFunc("/peers/{peerId}/jobs", peersHandler.CreateJob).Methods("POST", "OPTIONS")
-	router.HandleFunc("/peers/{peerId}/jobs/{jobId}", peersHandler.GetJob).Methods("GET", "OPTIONS")
+	router.HandleFunc("/peers/{peerId}/accessible-peers", peersHandler.withPeerScope(peersHandler.GetAccessiblePeers)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/peers/{peerId}/temporary-access", peersHandler.withPeerScope(peersHandler.CreateTemporaryAccess)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/peers/{peerId}/jobs", peersHandler.withPeerScope(peersHandler.ListJobs)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/peers/{peerId}/jobs", peersHandler.withPeerScope(peersHandler.CreateJob)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/peers/{peerId}/jobs/{jobId}", peersHandler.withPeerScope(peersHandler.GetJob)).Methods("GET", "OPTIONS")
+}
+
+// withPeerScope rejects requests for peers outside the peer groups of the personal access token scope
+func (h *Handler) withPeerScope(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		scope := types.PATScopeFromContext(r.Context())
+		if !scope.RestrictsPeers() {
+			next(w, r)
+			return
+		}
+
+		userAuth, err := nbcontext.GetUserAuthFromContext(r.Context())
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+
+		peerGroups, err := h.accountManager.GetPeerGroups(r.Context(), userAuth.AccountId, mux.Vars(r)["peerId"])
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+
+		groupIDs := make([]string, 0, len(peerGroups))
+		for _, group := range peerGroups {
+			groupIDs = append(groupIDs, group.ID)
+		}
+
+		if !scope.AllowsPeerGroups(groupIDs) {
+			util.WriteError(r.Context(), status.Errorf(status.NotFound, "peer not found"), w)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// filterPeersInScope drops the peers outside the peer groups of the personal access token scope
+func (h *Handler) filterPeersInScope(ctx context.Context, accountID string, peers []*nbpeer.Peer) ([]*nbpeer.Peer, error) {
+	scope := types.PATScopeFromContext(ctx)
+	if !scope.RestrictsPeers() {
+		return peers, nil
+	}
+
+	accountGroups, err := h.accountManager.GetStore().GetAccountGroups(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	peersInScope := scope.PeersInScope(accountGroups)
+	filtered := make([]*nbpeer.Peer, 0, len(peersInScope))
+	for _, peer := range peers {
+		if _, ok := peersInScope[peer.ID]; ok {
+			filtered = append(filtered, peer)
+		}
+	}
+	return filtered, nil
 }
 
 // NewHandler creates a new peers Handler
@@ -304,6 +362,12 @@ func (h *Handler) GetAllPeers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	peers, err = h.filterPeersInScope(r.Context(), accountID, peers)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
 	settings, err := h.accountManager.GetAccountSettings(r.Context(), accountID, activity.SystemInitiator)
 	if err != nil {
 		util.WriteError(r.Context(), err, w)
@@ -533,3 +533,58 @@ func TestPeersHandlerUpdatePeerIP(t *testing.T) {
 		})
 	}
 }
+
+func TestPeersHandlerPATScope(t *testing.T) {
+	peer := &nbpeer.Peer{
+		ID:     testPeerID,
+		Key:    "key",
+		IP:     net.ParseIP("100.64.0.1"),
+		Status: &nbpeer.PeerStatus{Connected: true},
+		Name:   "PeerName",
+	}
+
+	p := initTestMetaData(t, peer)
+
+	tt := []struct {
+		name           string
+		scope          *types.PATScope
+		expectedStatus int
+	}{
+		{
+			name:           "unscoped token",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "peer in scope",
+			scope:          &types.PATScope{PeerGroups: []string{"group1"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "peer out of scope",
+			scope:          &types.PATScope{PeerGroups: []string{"other"}},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/peers/"+testPeerID, nil)
+			req = nbcontext.SetUserAuthInRequest(req, auth.UserAuth{
+				UserId:    adminUser,
+				Domain:    "hotmail.com",
+				AccountId: "test_id",
+				IsPAT:     true,
+			})
+			if tc.scope != nil {
+				req = req.WithContext(types.SetPATScopeInContext(req.Context(), tc.scope))
+			}
+
+			router := mux.NewRouter()
+			router.HandleFunc("/api/peers/{peerId}", p.withPeerScope(p.HandlePeer)).Methods("GET")
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
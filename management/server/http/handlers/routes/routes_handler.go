@@ -2,8 +2,10 @@ package routes
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
 	"net/netip"
+	"time"
 	"unicode/utf8"
 
 	"github.com/gorilla/mux"
@@ -134,8 +136,33 @@ func (h *handler) createRoute(w http.ResponseWriter, r *http.Request) {
 		skipAutoApply = false
 	}
 
+	netMap, err := toNetMap(req.Netmap, newPrefix)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	bgp, err := toBGP(req.Bgp)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	multicast, err := toMulticast(req.Multicast)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
 	newRoute, err := h.accountManager.CreateRoute(r.Context(), accountID, newPrefix, networkType, domains, peerId, peerGroupIds,
-		req.Description, route.NetID(req.NetworkId), req.Masquerade, req.Metric, req.Groups, accessControlGroupIds, req.Enabled, userID, req.KeepRoute, skipAutoApply)
+		req.Description, route.NetID(req.NetworkId), req.Masquerade, req.Metric, req.Groups, accessControlGroupIds, req.Enabled, userID, req.KeepRoute, skipAutoApply,
+		&route.Options{
+			HealthCheck: toHealthCheck(req.HealthCheck),
+			NetMap:      netMap,
+			LoadBalance: req.LoadBalance != nil && *req.LoadBalance,
+			BGP:         bgp,
+			Multicast:   multicast,
+		})
 
 	if err != nil {
 		util.WriteError(r.Context(), err, w)
@@ -241,6 +268,8 @@ func (h *handler) updateRoute(w http.ResponseWriter, r *http.Request) {
 		Groups:        req.Groups,
 		KeepRoute:     req.KeepRoute,
 		SkipAutoApply: skipAutoApply,
+		HealthCheck:   toHealthCheck(req.HealthCheck),
+		LoadBalance:   req.LoadBalance != nil && *req.LoadBalance,
 	}
 
 	if req.Domains != nil {
@@ -259,6 +288,24 @@ func (h *handler) updateRoute(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	newRoute.NetMap, err = toNetMap(req.Netmap, newRoute.Network)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	newRoute.BGP, err = toBGP(req.Bgp)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	newRoute.Multicast, err = toMulticast(req.Multicast)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
 	if req.Peer != nil {
 		newRoute.Peer = peerID
 	}
@@ -361,6 +408,11 @@ func toRouteResponse(serverRoute *route.Route) (*api.Route, error) {
 		Groups:        serverRoute.Groups,
 		KeepRoute:     serverRoute.KeepRoute,
 		SkipAutoApply: &serverRoute.SkipAutoApply,
+		HealthCheck:   toHealthCheckResponse(serverRoute.HealthCheck),
+		Netmap:        toNetMapResponse(serverRoute.NetMap),
+		LoadBalance:   &serverRoute.LoadBalance,
+		Bgp:           toBGPResponse(serverRoute.BGP),
+		Multicast:     toMulticastResponse(serverRoute.Multicast),
 	}
 
 	if len(serverRoute.PeerGroups) > 0 {
@@ -371,3 +423,142 @@ func toRouteResponse(serverRoute *route.Route) (*api.Route, error) {
 	}
 	return route, nil
 }
+
+func toHealthCheck(req *api.RouteHealthCheck) *route.HealthCheck {
+	if req == nil {
+		return nil
+	}
+
+	var interval, timeout time.Duration
+	if req.Interval != nil {
+		interval = time.Duration(*req.Interval) * time.Second
+	}
+	if req.Timeout != nil {
+		timeout = time.Duration(*req.Timeout) * time.Second
+	}
+	return route.NewHealthCheck(route.HealthCheckType(req.Type), req.Target, interval, timeout)
+}
+
+func toHealthCheckResponse(hc *route.HealthCheck) *api.RouteHealthCheck {
+	if hc == nil {
+		return nil
+	}
+
+	interval := int(hc.Interval.Seconds())
+	timeout := int(hc.Timeout.Seconds())
+	return &api.RouteHealthCheck{
+		Type:     api.RouteHealthCheckType(hc.Type),
+		Target:   hc.Target,
+		Interval: &interval,
+		Timeout:  &timeout,
+	}
+}
+
+// toNetMap parses the requested netmap, the real prefix defaults to the route network
+func toNetMap(req *api.RouteNetMap, network netip.Prefix) (*route.NetMap, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	virtualPrefix, err := netip.ParsePrefix(req.Virtual)
+	if err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "invalid netmap virtual prefix %s: %v", req.Virtual, err)
+	}
+
+	realPrefix := network
+	if req.Real != nil {
+		realPrefix, err = netip.ParsePrefix(*req.Real)
+		if err != nil {
+			return nil, status.Errorf(status.InvalidArgument, "invalid netmap real prefix %s: %v", *req.Real, err)
+		}
+	}
+
+	return route.NewNetMap(realPrefix, virtualPrefix), nil
+}
+
+func toNetMapResponse(netMap *route.NetMap) *api.RouteNetMap {
+	if netMap == nil {
+		return nil
+	}
+
+	realPrefix := netMap.Real.String()
+	return &api.RouteNetMap{
+		Real:    &realPrefix,
+		Virtual: netMap.Virtual.String(),
+	}
+}
+
+// toBGP parses the requested BGP configuration
+func toBGP(req *api.RouteBGP) (*route.BGP, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	if req.LocalAsn < 0 || req.LocalAsn > math.MaxUint32 {
+		return nil, status.Errorf(status.InvalidArgument, "invalid BGP local AS number %d", req.LocalAsn)
+	}
+
+	bgp := &route.BGP{LocalASN: uint32(req.LocalAsn)}
+	for _, neighbor := range req.Neighbors {
+		addr, err := netip.ParseAddr(neighbor.Address)
+		if err != nil {
+			return nil, status.Errorf(status.InvalidArgument, "invalid BGP neighbor address %s: %v", neighbor.Address, err)
+		}
+		if neighbor.Asn < 0 || neighbor.Asn > math.MaxUint32 {
+			return nil, status.Errorf(status.InvalidArgument, "invalid AS number %d of BGP neighbor %s", neighbor.Asn, neighbor.Address)
+		}
+		bgp.Neighbors = append(bgp.Neighbors, route.BGPNeighbor{Address: addr, ASN: uint32(neighbor.Asn)})
+	}
+	return bgp, nil
+}
+
+func toBGPResponse(bgp *route.BGP) *api.RouteBGP {
+	if bgp == nil {
+		return nil
+	}
+
+	resp := &api.RouteBGP{
+		LocalAsn:  int64(bgp.LocalASN),
+		Neighbors: make([]api.RouteBGPNeighbor, 0, len(bgp.Neighbors)),
+	}
+	for _, neighbor := range bgp.Neighbors {
+		resp.Neighbors = append(resp.Neighbors, api.RouteBGPNeighbor{Address: neighbor.Address.String(), Asn: int64(neighbor.ASN)})
+	}
+	return resp
+}
+
+// toMulticast parses the requested multicast configuration
+func toMulticast(req *api.RouteMulticast) (*route.Multicast, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	multicast := &route.Multicast{MDNS: req.Mdns, SSDP: req.Ssdp, Broadcast: req.Broadcast}
+	if req.Groups != nil {
+		for _, group := range *req.Groups {
+			addrPort, err := netip.ParseAddrPort(group)
+			if err != nil {
+				return nil, status.Errorf(status.InvalidArgument, "invalid multicast group %s: %v", group, err)
+			}
+			multicast.Groups = append(multicast.Groups, addrPort)
+		}
+	}
+	return multicast, nil
+}
+
+func toMulticastResponse(multicast *route.Multicast) *api.RouteMulticast {
+	if multicast == nil {
+		return nil
+	}
+
+	groups := make([]string, 0, len(multicast.Groups))
+	for _, group := range multicast.Groups {
+		groups = append(groups, group.String())
+	}
+	return &api.RouteMulticast{
+		Mdns:      multicast.MDNS,
+		Ssdp:      multicast.SSDP,
+		Groups:    &groups,
+		Broadcast: multicast.Broadcast,
+	}
+}
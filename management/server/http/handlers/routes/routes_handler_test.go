@@ -78,7 +78,7 @@ func initRoutesTestData() *handler {
 					return nil, status.Errorf(status.NotFound, "route with ID %s not found", routeID)
 				}
 			},
-			CreateRouteFunc: func(_ context.Context, accountID string, prefix netip.Prefix, networkType route.NetworkType, domains domain.List, peerID string, peerGroups []string, description string, netID route.NetID, masquerade bool, metric int, groups, accessControlGroups []string, enabled bool, _ string, keepRoute bool, skipAutoApply bool) (*route.Route, error) {
+			CreateRouteFunc: func(_ context.Context, accountID string, prefix netip.Prefix, networkType route.NetworkType, domains domain.List, peerID string, peerGroups []string, description string, netID route.NetID, masquerade bool, metric int, groups, accessControlGroups []string, enabled bool, _ string, keepRoute bool, skipAutoApply bool, options *route.Options) (*route.Route, error) {
 				if peerID == notFoundPeerID {
 					return nil, status.Errorf(status.InvalidArgument, "peer with ID %s not found", peerID)
 				}
@@ -106,6 +106,11 @@ func initRoutesTestData() *handler {
 					KeepRoute:           keepRoute,
 					AccessControlGroups: accessControlGroups,
 					SkipAutoApply:       skipAutoApply,
+					HealthCheck:         options.HealthCheck,
+					NetMap:              options.NetMap,
+					LoadBalance:         options.LoadBalance,
+					BGP:                 options.BGP,
+					Multicast:           options.Multicast,
 				}, nil
 			},
 			SaveRouteFunc: func(_ context.Context, _, _ string, r *route.Route) error {
@@ -207,6 +212,7 @@ func TestRoutesHandlers(t *testing.T) {
 				Enabled:       false,
 				Groups:        []string{existingGroupID},
 				SkipAutoApply: util.ToPtr(false),
+				LoadBalance:   util.ToPtr(false),
 			},
 		},
 		{
@@ -230,6 +236,7 @@ func TestRoutesHandlers(t *testing.T) {
 				Enabled:       false,
 				Groups:        []string{existingGroupID},
 				SkipAutoApply: util.ToPtr(false),
+				LoadBalance:   util.ToPtr(false),
 			},
 		},
 		{
@@ -252,8 +259,67 @@ func TestRoutesHandlers(t *testing.T) {
 				Groups:              []string{existingGroupID},
 				AccessControlGroups: &[]string{existingGroupID},
 				SkipAutoApply:       util.ToPtr(false),
+				LoadBalance:         util.ToPtr(false),
 			},
 		},
+		{
+			name:        "POST OK With Health Check",
+			requestType: http.MethodPost,
+			requestPath: "/api/routes",
+			requestBody: bytes.NewBuffer(
+				[]byte(fmt.Sprintf("{\"Description\":\"Post\",\"Network\":\"192.168.0.0/16\",\"network_id\":\"awesomeNet\",\"Peer\":\"%s\",\"groups\":[\"%s\"],\"health_check\":{\"type\":\"tcp\",\"target\":\"192.168.0.10:443\"}}", existingPeerID, existingGroupID))),
+			expectedStatus: http.StatusOK,
+			expectedBody:   true,
+			expectedRoute: &api.Route{
+				Id:            existingRouteID,
+				Description:   "Post",
+				NetworkId:     "awesomeNet",
+				Network:       util.ToPtr("192.168.0.0/16"),
+				Peer:          &existingPeerID,
+				NetworkType:   route.IPv4NetworkString,
+				Groups:        []string{existingGroupID},
+				SkipAutoApply: util.ToPtr(false),
+				LoadBalance:   util.ToPtr(false),
+				HealthCheck: &api.RouteHealthCheck{
+					Type:     api.RouteHealthCheckTypeTcp,
+					Target:   "192.168.0.10:443",
+					Interval: util.ToPtr(10),
+					Timeout:  util.ToPtr(3),
+				},
+			},
+		},
+		{
+			name:        "POST OK With NetMap",
+			requestType: http.MethodPost,
+			requestPath: "/api/routes",
+			requestBody: bytes.NewBuffer(
+				[]byte(fmt.Sprintf("{\"Description\":\"Post\",\"Network\":\"192.168.0.0/16\",\"network_id\":\"awesomeNet\",\"Peer\":\"%s\",\"groups\":[\"%s\"],\"netmap\":{\"virtual\":\"10.80.0.0/16\"}}", existingPeerID, existingGroupID))),
+			expectedStatus: http.StatusOK,
+			expectedBody:   true,
+			expectedRoute: &api.Route{
+				Id:            existingRouteID,
+				Description:   "Post",
+				NetworkId:     "awesomeNet",
+				Network:       util.ToPtr("192.168.0.0/16"),
+				Peer:          &existingPeerID,
+				NetworkType:   route.IPv4NetworkString,
+				Groups:        []string{existingGroupID},
+				SkipAutoApply: util.ToPtr(false),
+				LoadBalance:   util.ToPtr(false),
+				Netmap: &api.RouteNetMap{
+					Real:    util.ToPtr("192.168.0.0/16"),
+					Virtual: "10.80.0.0/16",
+				},
+			},
+		},
+		{
+			name:           "POST Invalid NetMap",
+			requestType:    http.MethodPost,
+			requestPath:    "/api/routes",
+			requestBody:    bytes.NewBufferString(fmt.Sprintf("{\"Description\":\"Post\",\"Network\":\"192.168.0.0/16\",\"network_id\":\"awesomeNet\",\"Peer\":\"%s\",\"groups\":[\"%s\"],\"netmap\":{\"virtual\":\"invalid\"}}", existingPeerID, existingGroupID)),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   false,
+		},
 		{
 			name:           "POST Non Linux Peer",
 			requestType:    http.MethodPost,
@@ -356,6 +422,7 @@ func TestRoutesHandlers(t *testing.T) {
 				Enabled:       false,
 				Groups:        []string{existingGroupID},
 				SkipAutoApply: util.ToPtr(false),
+				LoadBalance:   util.ToPtr(false),
 			},
 		},
 		{
@@ -378,6 +445,7 @@ func TestRoutesHandlers(t *testing.T) {
 				Groups:        []string{existingGroupID},
 				KeepRoute:     true,
 				SkipAutoApply: util.ToPtr(false),
+				LoadBalance:   util.ToPtr(false),
 			},
 		},
 		{
@@ -399,6 +467,7 @@ func TestRoutesHandlers(t *testing.T) {
 				Enabled:       false,
 				Groups:        []string{existingGroupID},
 				SkipAutoApply: util.ToPtr(false),
+				LoadBalance:   util.ToPtr(false),
 			},
 		},
 		{
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/netip"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -85,8 +86,19 @@ func (h *handler) createSetupKey(w http.ResponseWriter, r *http.Request) {
 		allowExtraDNSLabels = *req.AllowExtraDnsLabels
 	}
 
+	var requiresApproval bool
+	if req.RequiresApproval != nil {
+		requiresApproval = *req.RequiresApproval
+	}
+
+	constraints, err := toSetupKeyConstraints(req.Constraints)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
 	setupKey, err := h.accountManager.CreateSetupKey(r.Context(), accountID, req.Name, types.SetupKeyType(req.Type), expiresIn,
-		req.AutoGroups, req.UsageLimit, userID, ephemeral, allowExtraDNSLabels)
+		req.AutoGroups, req.UsageLimit, userID, ephemeral, allowExtraDNSLabels, constraints, requiresApproval)
 	if err != nil {
 		util.WriteError(r.Context(), err, w)
 		return
@@ -157,6 +169,29 @@ func (h *handler) updateSetupKey(w http.ResponseWriter, r *http.Request) {
 	newKey.Revoked = req.Revoked
 	newKey.Id = keyID
 
+	// omitted enrollment restrictions are kept as they are
+	if req.Constraints == nil || req.RequiresApproval == nil {
+		currentKey, err := h.accountManager.GetSetupKey(r.Context(), accountID, userID, keyID)
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+		newKey.Constraints = currentKey.Constraints
+		newKey.RequiresApproval = currentKey.RequiresApproval
+	}
+
+	if req.Constraints != nil {
+		newKey.Constraints, err = toSetupKeyConstraints(req.Constraints)
+		if err != nil {
+			util.WriteError(r.Context(), err, w)
+			return
+		}
+	}
+
+	if req.RequiresApproval != nil {
+		newKey.RequiresApproval = *req.RequiresApproval
+	}
+
 	newKey, err = h.accountManager.SaveSetupKey(r.Context(), accountID, newKey, userID)
 	if err != nil {
 		util.WriteError(r.Context(), err, w)
@@ -251,5 +286,67 @@ func ToResponseBody(key *types.SetupKey) *api.SetupKey {
 		UsageLimit:          key.UsageLimit,
 		Ephemeral:           key.Ephemeral,
 		AllowExtraDnsLabels: key.AllowExtraDNSLabels,
+		Constraints:         toSetupKeyConstraintsResponse(key.Constraints),
+		RequiresApproval:    key.RequiresApproval,
+	}
+}
+
+func toSetupKeyConstraints(req *api.SetupKeyConstraints) (*types.SetupKeyConstraints, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	constraints := &types.SetupKeyConstraints{}
+
+	if req.SourceRanges != nil {
+		for _, sourceRange := range *req.SourceRanges {
+			prefix, err := netip.ParsePrefix(sourceRange)
+			if err != nil {
+				return nil, status.Errorf(status.InvalidArgument, "invalid source range %s", sourceRange)
+			}
+			constraints.SourceRanges = append(constraints.SourceRanges, prefix.Masked())
+		}
+	}
+
+	if req.OperatingSystems != nil {
+		for _, os := range *req.OperatingSystems {
+			constraints.OperatingSystems = append(constraints.OperatingSystems, string(os))
+		}
+	}
+
+	if req.HostnamePattern != nil {
+		constraints.HostnamePattern = *req.HostnamePattern
+	}
+
+	if req.PostureChecks != nil {
+		constraints.PostureChecks = *req.PostureChecks
+	}
+
+	return constraints, nil
+}
+
+func toSetupKeyConstraintsResponse(constraints *types.SetupKeyConstraints) *api.SetupKeyConstraints {
+	if constraints.IsEmpty() {
+		return nil
+	}
+
+	sourceRanges := make([]string, 0, len(constraints.SourceRanges))
+	for _, prefix := range constraints.SourceRanges {
+		sourceRanges = append(sourceRanges, prefix.String())
+	}
+
+	operatingSystems := make([]api.SetupKeyConstraintsOperatingSystems, 0, len(constraints.OperatingSystems))
+	for _, os := range constraints.OperatingSystems {
+		operatingSystems = append(operatingSystems, api.SetupKeyConstraintsOperatingSystems(os))
+	}
+
+	postureChecks := make([]string, len(constraints.PostureChecks))
+	copy(postureChecks, constraints.PostureChecks)
+
+	return &api.SetupKeyConstraints{
+		SourceRanges:     &sourceRanges,
+		OperatingSystems: &operatingSystems,
+		HostnamePattern:  &constraints.HostnamePattern,
+		PostureChecks:    &postureChecks,
 	}
 }
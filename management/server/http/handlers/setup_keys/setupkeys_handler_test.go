@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"testing"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	nbcontext "github.com/netbirdio/netbird/management/server/context"
 	"github.com/netbirdio/netbird/management/server/mock_server"
 	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/management/server/util"
 	"github.com/netbirdio/netbird/shared/auth"
 	"github.com/netbirdio/netbird/shared/management/http/api"
 	"github.com/netbirdio/netbird/shared/management/status"
@@ -33,12 +35,14 @@ func initSetupKeysTestMetaData(defaultKey *types.SetupKey, newKey *types.SetupKe
 	return &handler{
 		accountManager: &mock_server.MockAccountManager{
 			CreateSetupKeyFunc: func(_ context.Context, _ string, keyName string, typ types.SetupKeyType, _ time.Duration, _ []string,
-				_ int, _ string, ephemeral bool, allowExtraDNSLabels bool,
+				_ int, _ string, ephemeral bool, allowExtraDNSLabels bool, constraints *types.SetupKeyConstraints, requiresApproval bool,
 			) (*types.SetupKey, error) {
 				if keyName == newKey.Name || typ != newKey.Type {
 					nk := newKey.Copy()
 					nk.Ephemeral = ephemeral
 					nk.AllowExtraDNSLabels = allowExtraDNSLabels
+					nk.Constraints = constraints
+					nk.RequiresApproval = requiresApproval
 					return nk, nil
 				}
 				return nil, fmt.Errorf("failed creating setup key")
@@ -232,3 +236,23 @@ func assertKeys(t *testing.T, got *api.SetupKey, expected *api.SetupKey) {
 	assert.ElementsMatch(t, got.AutoGroups, expected.AutoGroups)
 	assert.Equal(t, got.Ephemeral, expected.Ephemeral)
 }
+
+func TestToSetupKeyConstraints(t *testing.T) {
+	constraints, err := toSetupKeyConstraints(&api.SetupKeyConstraints{
+		SourceRanges:     &[]string{"203.0.113.7/24"},
+		OperatingSystems: &[]api.SetupKeyConstraintsOperatingSystems{api.SetupKeyConstraintsOperatingSystemsLinux},
+		HostnamePattern:  util.ToPtr("^k8s-node-[0-9]+$"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")}, constraints.SourceRanges)
+	assert.Equal(t, []string{"linux"}, constraints.OperatingSystems)
+
+	response := toSetupKeyConstraintsResponse(constraints)
+	assert.Equal(t, []string{"203.0.113.0/24"}, *response.SourceRanges)
+	assert.Equal(t, "^k8s-node-[0-9]+$", *response.HostnamePattern)
+
+	_, err = toSetupKeyConstraints(&api.SetupKeyConstraints{SourceRanges: &[]string{"not-a-cidr"}})
+	assert.Error(t, err)
+
+	assert.Nil(t, toSetupKeyConstraintsResponse(&types.SetupKeyConstraints{}))
+}
@@ -3,11 +3,14 @@ package users
 import (
 	"encoding/json"
 	"net/http"
+	"net/netip"
 
 	"github.com/gorilla/mux"
 
 	"github.com/netbirdio/netbird/management/server/account"
 	nbcontext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
 	"github.com/netbirdio/netbird/management/server/types"
 	"github.com/netbirdio/netbird/shared/management/http/api"
 	"github.com/netbirdio/netbird/shared/management/http/util"
@@ -118,7 +121,13 @@ func (h *patHandler) createToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pat, err := h.accountManager.CreatePAT(r.Context(), accountID, userID, targetUserID, req.Name, req.ExpiresIn)
+	scope, err := toPATScope(req.Scope)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	pat, err := h.accountManager.CreatePAT(r.Context(), accountID, userID, targetUserID, req.Name, req.ExpiresIn, scope)
 	if err != nil {
 		util.WriteError(r.Context(), err, w)
 		return
@@ -166,9 +175,69 @@ func toPATResponse(pat *types.PersonalAccessToken) *api.PersonalAccessToken {
 		ExpirationDate: pat.GetExpirationDate(),
 		Id:             pat.ID,
 		LastUsed:       pat.LastUsed,
+		Scope:          toPATScopeResponse(pat.Scope),
 	}
 }
 
+func toPATScope(req *api.PersonalAccessTokenScope) (*types.PATScope, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	scope := &types.PATScope{}
+	if req.Permissions != nil {
+		scope.Permissions = make(map[modules.Module]map[operations.Operation]bool, len(*req.Permissions))
+		for module, ops := range *req.Permissions {
+			scope.Permissions[modules.Module(module)] = make(map[operations.Operation]bool, len(ops))
+			for operation, allowed := range ops {
+				scope.Permissions[modules.Module(module)][operations.Operation(operation)] = allowed
+			}
+		}
+	}
+	if req.PeerGroups != nil {
+		scope.PeerGroups = *req.PeerGroups
+	}
+	if req.SourceRanges != nil {
+		for _, sourceRange := range *req.SourceRanges {
+			prefix, err := netip.ParsePrefix(sourceRange)
+			if err != nil {
+				return nil, status.Errorf(status.InvalidArgument, "invalid source range %s", sourceRange)
+			}
+			scope.SourceRanges = append(scope.SourceRanges, prefix.Masked())
+		}
+	}
+	return scope, nil
+}
+
+func toPATScopeResponse(scope *types.PATScope) *api.PersonalAccessTokenScope {
+	if scope == nil {
+		return nil
+	}
+
+	resp := &api.PersonalAccessTokenScope{}
+	if len(scope.Permissions) > 0 {
+		permissions := make(map[string]map[string]bool, len(scope.Permissions))
+		for module, ops := range scope.Permissions {
+			permissions[string(module)] = make(map[string]bool, len(ops))
+			for operation, allowed := range ops {
+				permissions[string(module)][string(operation)] = allowed
+			}
+		}
+		resp.Permissions = &permissions
+	}
+	if len(scope.PeerGroups) > 0 {
+		resp.PeerGroups = &scope.PeerGroups
+	}
+	if len(scope.SourceRanges) > 0 {
+		sourceRanges := make([]string, 0, len(scope.SourceRanges))
+		for _, prefix := range scope.SourceRanges {
+			sourceRanges = append(sourceRanges, prefix.String())
+		}
+		resp.SourceRanges = &sourceRanges
+	}
+	return resp
+}
+
 func toPATGeneratedResponse(pat *types.PersonalAccessTokenGenerated) *api.PersonalAccessTokenGenerated {
 	return &api.PersonalAccessTokenGenerated{
 		PlainToken:          pat.PlainToken,
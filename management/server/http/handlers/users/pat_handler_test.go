@@ -13,6 +13,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/netbirdio/netbird/management/server/util"
 
@@ -67,7 +68,7 @@ var testAccount = &types.Account{
 func initPATTestData() *patHandler {
 	return &patHandler{
 		accountManager: &mock_server.MockAccountManager{
-			CreatePATFunc: func(_ context.Context, accountID string, initiatorUserID string, targetUserID string, tokenName string, expiresIn int) (*types.PersonalAccessTokenGenerated, error) {
+			CreatePATFunc: func(_ context.Context, accountID string, initiatorUserID string, targetUserID string, tokenName string, expiresIn int, scope *types.PATScope) (*types.PersonalAccessTokenGenerated, error) {
 				if accountID != existingAccountID {
 					return nil, status.Errorf(status.NotFound, "account with ID %s not found", accountID)
 				}
@@ -76,7 +77,7 @@ func initPATTestData() *patHandler {
 				}
 				return &types.PersonalAccessTokenGenerated{
 					PlainToken:          "nbp_z1pvsg2wP3EzmEou4S679KyTNhov632eyrXe",
-					PersonalAccessToken: types.PersonalAccessToken{},
+					PersonalAccessToken: types.PersonalAccessToken{Scope: scope},
 				}, nil
 			},
 			DeletePATFunc: func(_ context.Context, accountID string, initiatorUserID string, targetUserID string, tokenID string) error {
@@ -166,6 +167,23 @@ func TestTokenHandlers(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedBody:   true,
 		},
+		{
+			name:        "POST with scope",
+			requestType: http.MethodPost,
+			requestPath: "/api/users/" + existingUserID + "/tokens",
+			requestBody: bytes.NewBuffer(
+				[]byte("{\"name\":\"name\",\"expires_in\":7,\"scope\":{\"permissions\":{\"setup_keys\":{\"create\":true}},\"source_ranges\":[\"10.1.2.3/8\"]}}")),
+			expectedStatus: http.StatusOK,
+			expectedBody:   true,
+		},
+		{
+			name:        "POST with invalid source range",
+			requestType: http.MethodPost,
+			requestPath: "/api/users/" + existingUserID + "/tokens",
+			requestBody: bytes.NewBuffer(
+				[]byte("{\"name\":\"name\",\"expires_in\":7,\"scope\":{\"source_ranges\":[\"10.1.2.3\"]}}")),
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
 	}
 
 	p := initPATTestData()
@@ -213,6 +231,15 @@ func TestTokenHandlers(t *testing.T) {
 				}
 				assert.NotEmpty(t, got.PlainToken)
 				assert.Equal(t, types.PATLength, len(got.PlainToken))
+			case "POST with scope":
+				got := &api.PersonalAccessTokenGenerated{}
+				if err = json.Unmarshal(content, &got); err != nil {
+					t.Fatalf("Sent content is not in correct json format; %v", err)
+				}
+				require.NotNil(t, got.PersonalAccessToken.Scope)
+				assert.Equal(t, map[string]map[string]bool{"setup_keys": {"create": true}}, *got.PersonalAccessToken.Scope.Permissions)
+				assert.Equal(t, []string{"10.0.0.0/8"}, *got.PersonalAccessToken.Scope.SourceRanges)
+				assert.Nil(t, got.PersonalAccessToken.Scope.PeerGroups)
 			case "Get All Tokens":
 				expectedTokens := []api.PersonalAccessToken{
 					toTokenResponse(*testAccount.Users[existingUserID].PATs[existingTokenID]),
@@ -91,6 +91,7 @@ func (h *handler) updateUser(w http.ResponseWriter, r *http.Request) {
 	newUser, err := h.accountManager.SaveUser(r.Context(), accountID, userID, &types.User{
 		Id:                   targetUserID,
 		Role:                 userRole,
+		CustomRoleID:         customRoleID(req.CustomRoleId),
 		AutoGroups:           req.AutoGroups,
 		Blocked:              req.IsBlocked,
 		Issued:               existingUser.Issued,
@@ -174,6 +175,7 @@ func (h *handler) createUser(w http.ResponseWriter, r *http.Request) {
 		Email:         email,
 		Name:          name,
 		Role:          req.Role,
+		CustomRoleID:  customRoleID(req.CustomRoleId),
 		AutoGroups:    req.AutoGroups,
 		IsServiceUser: req.IsServiceUser,
 		Issued:        types.UserIssuedAPI,
@@ -336,11 +338,17 @@ func toUserResponse(user *types.UserInfo, currenUserID string) *api.User {
 		idpID = &user.IdPID
 	}
 
+	var customRole *string
+	if user.CustomRoleID != "" {
+		customRole = &user.CustomRoleID
+	}
+
 	return &api.User{
 		Id:              user.ID,
 		Name:            user.Name,
 		Email:           user.Email,
 		Role:            user.Role,
+		CustomRoleId:    customRole,
 		AutoGroups:      autoGroups,
 		Status:          userStatus,
 		IsCurrent:       &isCurrent,
@@ -354,6 +362,14 @@ func toUserResponse(user *types.UserInfo, currenUserID string) *api.User {
 	}
 }
 
+// customRoleID returns the custom role of a user request, users without it get the permissions of their role
+func customRoleID(id *string) string {
+	if id == nil {
+		return ""
+	}
+	return *id
+}
+
 // approveUser is a POST request to approve a user that is pending approval
 func (h *handler) approveUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/netip"
+	"slices"
 	"strings"
 	"time"
 
@@ -33,6 +35,7 @@ type AuthMiddleware struct {
 	syncUserJWTGroups   SyncUserJWTGroupsFunc
 	rateLimiter         *APIRateLimiter
 	patUsageTracker     *PATUsageTracker
+	trustedHTTPProxies  []netip.Prefix
 }
 
 // NewAuthMiddleware instance constructor
@@ -43,6 +46,7 @@ func NewAuthMiddleware(
 	getUserFromUserAuth GetUserFromUserAuthFunc,
 	rateLimiterConfig *RateLimiterConfig,
 	meter metric.Meter,
+	trustedHTTPProxies []netip.Prefix,
 ) *AuthMiddleware {
 	var rateLimiter *APIRateLimiter
 	if rateLimiterConfig != nil {
@@ -65,6 +69,7 @@ func NewAuthMiddleware(
 		getUserFromUserAuth: getUserFromUserAuth,
 		rateLimiter:         rateLimiter,
 		patUsageTracker:     patUsageTracker,
+		trustedHTTPProxies:  trustedHTTPProxies,
 	}
 }
 
@@ -193,6 +198,16 @@ func (m *AuthMiddleware) checkPATFromRequest(r *http.Request, authHeaderParts []
 		return r, fmt.Errorf("token expired")
 	}
 
+	if pat.Scope != nil && len(pat.Scope.SourceRanges) > 0 {
+		addr, err := clientAddr(r, m.trustedHTTPProxies)
+		if err != nil {
+			return r, err
+		}
+		if !pat.Scope.AllowsSource(addr) {
+			return r, status.Errorf(status.PermissionDenied, "token can't be used from %s", addr)
+		}
+	}
+
 	err = m.authManager.MarkPATUsed(ctx, pat.ID)
 	if err != nil {
 		return r, err
@@ -211,7 +226,46 @@ func (m *AuthMiddleware) checkPATFromRequest(r *http.Request, authHeaderParts []
 		userAuth.IsChild = ok
 	}
 
-	return nbcontext.SetUserAuthInRequest(r, userAuth), nil
+	r = nbcontext.SetUserAuthInRequest(r, userAuth)
+	if pat.Scope != nil {
+		r = r.WithContext(types.SetPATScopeInContext(r.Context(), pat.Scope))
+	}
+
+	return r, nil
+}
+
+// clientAddr returns the address the request originates from. X-Forwarded-For is only taken into account for
+// requests coming through a trusted proxy, hops added by trusted proxies are skipped.
+func clientAddr(r *http.Request, trustedProxies []netip.Prefix) (netip.Addr, error) {
+	addrPort, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("parse remote address %s: %w", r.RemoteAddr, err)
+	}
+
+	addr := addrPort.Addr().Unmap()
+	isTrusted := func(addr netip.Addr) bool {
+		return slices.ContainsFunc(trustedProxies, func(prefix netip.Prefix) bool {
+			return prefix.Contains(addr)
+		})
+	}
+
+	forwarded := strings.Join(r.Header.Values("X-Forwarded-For"), ",")
+	if !isTrusted(addr) || forwarded == "" {
+		return addr, nil
+	}
+
+	hops := strings.Split(forwarded, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("parse forwarded address %s: %w", hops[i], err)
+		}
+		addr = hop.Unmap()
+		if !isTrusted(addr) {
+			break
+		}
+	}
+	return addr, nil
 }
 
 func isTerraformRequest(r *http.Request) bool {
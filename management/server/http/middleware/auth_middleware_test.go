@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"testing"
 	"time"
 
@@ -14,6 +15,8 @@ import (
 	"github.com/netbirdio/netbird/management/server/auth"
 	nbcontext "github.com/netbirdio/netbird/management/server/context"
 	"github.com/netbirdio/netbird/management/server/http/middleware/bypass"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
 	"github.com/netbirdio/netbird/management/server/types"
 	"github.com/netbirdio/netbird/management/server/util"
 	nbauth "github.com/netbirdio/netbird/shared/auth"
@@ -209,6 +212,7 @@ func TestAuthMiddleware_Handler(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 	)
 
 	handlerToTest := authMiddleware.Handler(nextHandler)
@@ -268,6 +272,7 @@ func TestAuthMiddleware_RateLimiting(t *testing.T) {
 			},
 			rateLimitConfig,
 			nil,
+			nil,
 		)
 
 		handler := authMiddleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -320,6 +325,7 @@ func TestAuthMiddleware_RateLimiting(t *testing.T) {
 			},
 			rateLimitConfig,
 			nil,
+			nil,
 		)
 
 		handler := authMiddleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -363,6 +369,7 @@ func TestAuthMiddleware_RateLimiting(t *testing.T) {
 			},
 			rateLimitConfig,
 			nil,
+			nil,
 		)
 
 		handler := authMiddleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -407,6 +414,7 @@ func TestAuthMiddleware_RateLimiting(t *testing.T) {
 			},
 			rateLimitConfig,
 			nil,
+			nil,
 		)
 
 		handler := authMiddleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -471,6 +479,7 @@ func TestAuthMiddleware_RateLimiting(t *testing.T) {
 			},
 			rateLimitConfig,
 			nil,
+			nil,
 		)
 
 		handler := authMiddleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -530,6 +539,7 @@ func TestAuthMiddleware_RateLimiting(t *testing.T) {
 			},
 			rateLimitConfig,
 			nil,
+			nil,
 		)
 
 		handler := authMiddleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -585,6 +595,7 @@ func TestAuthMiddleware_RateLimiting(t *testing.T) {
 			},
 			rateLimitConfig,
 			nil,
+			nil,
 		)
 
 		handler := authMiddleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -685,6 +696,7 @@ func TestAuthMiddleware_Handler_Child(t *testing.T) {
 		},
 		nil,
 		nil,
+		nil,
 	)
 
 	for _, tc := range tt {
@@ -717,3 +729,93 @@ func TestAuthMiddleware_Handler_Child(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthMiddleware_PATScope(t *testing.T) {
+	scope := &types.PATScope{
+		Permissions:  map[modules.Module]map[operations.Operation]bool{modules.SetupKeys: {operations.Create: true}},
+		SourceRanges: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	}
+	scopedPAT := &types.PersonalAccessToken{
+		ID:             tokenID,
+		ExpirationDate: util.ToPtr(time.Now().UTC().AddDate(0, 0, 7)),
+		Scope:          scope,
+	}
+
+	mockAuth := &auth.MockManager{
+		MarkPATUsedFunc: mockMarkPATUsed,
+		GetPATInfoFunc: func(_ context.Context, token string) (*types.User, *types.PersonalAccessToken, string, string, error) {
+			return testAccount.Users[userID], scopedPAT, testAccount.Domain, testAccount.DomainCategory, nil
+		},
+	}
+
+	authMiddleware := NewAuthMiddleware(
+		mockAuth,
+		func(ctx context.Context, userAuth nbauth.UserAuth) (string, string, error) {
+			return userAuth.AccountId, userAuth.UserId, nil
+		},
+		func(ctx context.Context, userAuth nbauth.UserAuth) error {
+			return nil
+		},
+		func(ctx context.Context, userAuth nbauth.UserAuth) (*types.User, error) {
+			return &types.User{}, nil
+		},
+		nil,
+		nil,
+		[]netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")},
+	)
+
+	tt := []struct {
+		name               string
+		remoteAddr         string
+		forwardedFor       string
+		expectedStatusCode int
+	}{
+		{
+			name:               "Allowed source",
+			remoteAddr:         "10.1.2.3:4321",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Denied source",
+			remoteAddr:         "203.0.113.1:4321",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Allowed source through trusted proxy",
+			remoteAddr:         "192.0.2.10:4321",
+			forwardedFor:       "10.1.2.3, 192.0.2.11",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Spoofed source through trusted proxy",
+			remoteAddr:         "192.0.2.10:4321",
+			forwardedFor:       "10.1.2.3, 203.0.113.1",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Forwarded header from untrusted client",
+			remoteAddr:         "203.0.113.1:4321",
+			forwardedFor:       "10.1.2.3",
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			handlerToTest := authMiddleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, scope, types.PATScopeFromContext(r.Context()))
+			}))
+
+			req := httptest.NewRequest("GET", "http://testing/test", nil)
+			req.RemoteAddr = tc.remoteAddr
+			req.Header.Set("Authorization", "Token "+PAT)
+			if tc.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tc.forwardedFor)
+			}
+			rec := httptest.NewRecorder()
+
+			handlerToTest.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedStatusCode, rec.Code)
+		})
+	}
+}
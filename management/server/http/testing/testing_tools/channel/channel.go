@@ -11,6 +11,16 @@ import (
 
 	"github.com/netbirdio/management-integrations/integrations"
 
+	accessRequestsManager "github.com/netbirdio/netbird/management/internals/modules/accessrequests/manager"
+	customRolesManager "github.com/netbirdio/netbird/management/internals/modules/customroles/manager"
+	declarativeManager "github.com/netbirdio/netbird/management/internals/modules/declarative/manager"
+	historyManager "github.com/netbirdio/netbird/management/internals/modules/history/manager"
+	peerApprovalManager "github.com/netbirdio/netbird/management/internals/modules/peerapproval/manager"
+	portForwardsManager "github.com/netbirdio/netbird/management/internals/modules/portforwards/manager"
+	reportsManager "github.com/netbirdio/netbird/management/internals/modules/reports/manager"
+	scimManager "github.com/netbirdio/netbird/management/internals/modules/scim/manager"
+	subnetDiscoveryManager "github.com/netbirdio/netbird/management/internals/modules/subnetdiscovery/manager"
+	tenantsManager "github.com/netbirdio/netbird/management/internals/modules/tenants/manager"
 	zonesManager "github.com/netbirdio/netbird/management/internals/modules/zones/manager"
 	recordsManager "github.com/netbirdio/netbird/management/internals/modules/zones/records/manager"
 	"github.com/netbirdio/netbird/management/internals/server/config"
@@ -101,8 +111,18 @@ func BuildApiBlackBoxWithDBState(t testing_tools.TB, sqlFile string, expectedPee
 	groupsManagerMock := groups.NewManagerMock()
 	customZonesManager := zonesManager.NewManager(store, am, permissionsManager, "")
 	zoneRecordsManager := recordsManager.NewManager(store, am, permissionsManager)
-
-	apiHandler, err := http2.NewAPIHandler(context.Background(), am, networksManagerMock, resourcesManagerMock, routersManagerMock, groupsManagerMock, geoMock, authManagerMock, metrics, validatorMock, proxyController, permissionsManager, peersManager, settingsManager, customZonesManager, zoneRecordsManager, networkMapController, nil)
+	scimTokenManager := scimManager.NewManager(store, am, permissionsManager)
+	customRolesMgr := customRolesManager.NewManager(store, am, permissionsManager)
+	peerApprovalMgr := peerApprovalManager.NewManager(store, am, permissionsManager)
+	declarativeMgr := declarativeManager.NewManager(store, am, permissionsManager)
+	tenantsMgr := tenantsManager.NewManager(store, am, permissionsManager, declarativeMgr)
+	accessRequestsMgr := accessRequestsManager.NewManager(store, am, permissionsManager)
+	subnetDiscoveryMgr := subnetDiscoveryManager.NewManager(store, am, permissionsManager, resourcesManagerMock)
+	portForwardsMgr := portForwardsManager.NewManager(store, am, permissionsManager)
+	historyMgr := historyManager.NewManager(store, am, permissionsManager)
+	reportsMgr := reportsManager.NewManager(store, permissionsManager)
+
+	apiHandler, err := http2.NewAPIHandler(context.Background(), am, networksManagerMock, resourcesManagerMock, routersManagerMock, groupsManagerMock, geoMock, authManagerMock, metrics, validatorMock, proxyController, permissionsManager, peersManager, settingsManager, customZonesManager, zoneRecordsManager, scimTokenManager, customRolesMgr, peerApprovalMgr, declarativeMgr, tenantsMgr, accessRequestsMgr, subnetDiscoveryMgr, portForwardsMgr, historyMgr, reportsMgr, networkMapController, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to create API handler: %v", err)
 	}
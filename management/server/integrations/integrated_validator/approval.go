@@ -0,0 +1,47 @@
+package integrated_validator
+
+import (
+	"context"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+// approvalValidator wraps an IntegratedValidator and additionally treats peers that are marked
+// as requiring approval (e.g., registered with a setup key that requires approval) as not valid.
+type approvalValidator struct {
+	IntegratedValidator
+}
+
+// WithPeerApproval returns an IntegratedValidator that doesn't validate peers pending approval,
+// on top of the checks of the given validator.
+func WithPeerApproval(validator IntegratedValidator) IntegratedValidator {
+	return &approvalValidator{IntegratedValidator: validator}
+}
+
+func (v *approvalValidator) IsNotValidPeer(ctx context.Context, accountID string, peer *nbpeer.Peer, peersGroup []string, extraSettings *types.ExtraSettings) (bool, bool, error) {
+	notValid, statusChanged, err := v.IntegratedValidator.IsNotValidPeer(ctx, accountID, peer, peersGroup, extraSettings)
+	if err != nil {
+		return false, false, err
+	}
+	return notValid || requiresApproval(peer), statusChanged, nil
+}
+
+func (v *approvalValidator) GetValidatedPeers(ctx context.Context, accountID string, groups []*types.Group, peers []*nbpeer.Peer, extraSettings *types.ExtraSettings) (map[string]struct{}, error) {
+	validatedPeers, err := v.IntegratedValidator.GetValidatedPeers(ctx, accountID, groups, peers, extraSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, peer := range peers {
+		if requiresApproval(peer) {
+			delete(validatedPeers, peer.ID)
+		}
+	}
+
+	return validatedPeers, nil
+}
+
+func requiresApproval(peer *nbpeer.Peer) bool {
+	return peer.Status != nil && peer.Status.RequiresApproval
+}
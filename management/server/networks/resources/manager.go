@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/netbirdio/netbird/management/internals/modules/history"
 	"github.com/netbirdio/netbird/management/server/account"
 	"github.com/netbirdio/netbird/management/server/activity"
 	"github.com/netbirdio/netbird/management/server/groups"
@@ -103,11 +104,17 @@ func (m *managerImpl) CreateResource(ctx context.Context, userID string, resourc
 		return nil, status.NewPermissionDeniedError()
 	}
 
+	netMap := resource.NetMap
 	resource, err = types.NewNetworkResource(resource.AccountID, resource.NetworkID, resource.Name, resource.Description, resource.Address, resource.GroupIDs, resource.Enabled)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new network resource: %w", err)
 	}
 
+	resource.NetMap = netMap
+	if err = resource.ValidateNetMap(); err != nil {
+		return nil, err
+	}
+
 	var eventsToStore []func()
 	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
 		_, err = transaction.GetNetworkResourceByName(ctx, store.LockingStrengthNone, resource.AccountID, resource.Name)
@@ -142,6 +149,11 @@ func (m *managerImpl) CreateResource(ctx context.Context, userID string, resourc
 			eventsToStore = append(eventsToStore, event)
 		}
 
+		err = history.Record(ctx, transaction, resource.AccountID, userID, history.ObjectNetworkResource, resource.ID, history.OperationCreated, nil, resource)
+		if err != nil {
+			return err
+		}
+
 		err = transaction.IncrementNetworkSerial(ctx, resource.AccountID)
 		if err != nil {
 			return fmt.Errorf("failed to increment network serial: %w", err)
@@ -201,6 +213,10 @@ func (m *managerImpl) UpdateResource(ctx context.Context, userID string, resourc
 	resource.Domain = domain
 	resource.Prefix = prefix
 
+	if err = resource.ValidateNetMap(); err != nil {
+		return nil, err
+	}
+
 	var eventsToStore []func()
 	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
 		network, err := transaction.GetNetworkByID(ctx, store.LockingStrengthUpdate, resource.AccountID, resource.NetworkID)
@@ -242,6 +258,11 @@ func (m *managerImpl) UpdateResource(ctx context.Context, userID string, resourc
 			m.accountManager.StoreEvent(ctx, userID, resource.ID, resource.AccountID, activity.NetworkResourceUpdated, resource.EventMeta(network))
 		})
 
+		err = history.Record(ctx, transaction, resource.AccountID, userID, history.ObjectNetworkResource, resource.ID, history.OperationUpdated, oldResource, resource)
+		if err != nil {
+			return err
+		}
+
 		err = transaction.IncrementNetworkSerial(ctx, resource.AccountID)
 		if err != nil {
 			return fmt.Errorf("failed to increment network serial: %w", err)
@@ -277,6 +298,7 @@ func (m *managerImpl) updateResourceGroups(ctx context.Context, transaction stor
 	for _, group := range oldResourceGroups {
 		oldGroupsIds = append(oldGroupsIds, group.ID)
 	}
+	oldResource.GroupIDs = oldGroupsIds
 
 	var eventsToStore []func()
 	groupsToAdd := util.Difference(newResource.GroupIDs, oldGroupsIds)
@@ -358,6 +380,11 @@ func (m *managerImpl) DeleteResourceInTransaction(ctx context.Context, transacti
 
 	var eventsToStore []func()
 
+	resource.GroupIDs = make([]string, 0, len(groups))
+	for _, group := range groups {
+		resource.GroupIDs = append(resource.GroupIDs, group.ID)
+	}
+
 	for _, group := range groups {
 		event, err := m.groupsManager.RemoveResourceFromGroupInTransaction(ctx, transaction, accountID, userID, group.ID, resourceID)
 		if err != nil {
@@ -371,6 +398,11 @@ func (m *managerImpl) DeleteResourceInTransaction(ctx context.Context, transacti
 		return nil, fmt.Errorf("failed to delete network resource: %w", err)
 	}
 
+	err = history.Record(ctx, transaction, accountID, userID, history.ObjectNetworkResource, resourceID, history.OperationDeleted, resource, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	eventsToStore = append(eventsToStore, func() {
 		m.accountManager.StoreEvent(ctx, userID, resourceID, accountID, activity.NetworkResourceDeleted, resource.EventMeta(network))
 	})
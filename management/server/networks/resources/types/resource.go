@@ -13,6 +13,7 @@ import (
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
 	"github.com/netbirdio/netbird/route"
 	nbDomain "github.com/netbirdio/netbird/shared/management/domain"
+	"github.com/netbirdio/netbird/shared/management/status"
 
 	"github.com/netbirdio/netbird/shared/management/http/api"
 )
@@ -36,11 +37,13 @@ type NetworkResource struct {
 	Name        string
 	Description string
 	Type        NetworkResourceType
-	Address     string   `gorm:"-"`
+	Address     string   `gorm:"-" json:"-"`
 	GroupIDs    []string `gorm:"-"`
 	Domain      string
 	Prefix      netip.Prefix `gorm:"serializer:json"`
 	Enabled     bool
+	// NetMap maps the resource onto a virtual prefix, the real prefix defaults to the resource prefix
+	NetMap *route.NetMap `gorm:"serializer:json"`
 }
 
 func NewNetworkResource(accountID, networkID, name, description, address string, groupIDs []string, enabled bool) (*NetworkResource, error) {
@@ -78,6 +81,19 @@ func (n *NetworkResource) ToAPIResponse(groups []api.GroupMinimum) *api.NetworkR
 		Address:     addr,
 		Groups:      groups,
 		Enabled:     n.Enabled,
+		Netmap:      n.netMapToAPIResponse(),
+	}
+}
+
+func (n *NetworkResource) netMapToAPIResponse() *api.RouteNetMap {
+	if n.NetMap == nil {
+		return nil
+	}
+
+	realPrefix := n.NetMap.Real.String()
+	return &api.RouteNetMap{
+		Real:    &realPrefix,
+		Virtual: n.NetMap.Virtual.String(),
 	}
 }
 
@@ -90,6 +106,35 @@ func (n *NetworkResource) FromAPIRequest(req *api.NetworkResourceRequest) {
 	n.Address = req.Address
 	n.GroupIDs = req.Groups
 	n.Enabled = req.Enabled
+
+	if req.Netmap != nil {
+		// unparsable prefixes are left invalid and rejected by ValidateNetMap
+		n.NetMap = &route.NetMap{}
+		n.NetMap.Virtual, _ = netip.ParsePrefix(req.Netmap.Virtual)
+		if req.Netmap.Real != nil {
+			n.NetMap.Real, _ = netip.ParsePrefix(*req.Netmap.Real)
+		}
+	}
+}
+
+// ValidateNetMap defaults the real prefix of the netmap to the resource prefix and validates it.
+// The type and prefix of the resource have to be set.
+func (n *NetworkResource) ValidateNetMap() error {
+	if n.NetMap == nil {
+		return nil
+	}
+
+	if n.Type != Domain {
+		if !n.NetMap.Real.IsValid() {
+			n.NetMap.Real = n.Prefix.Masked()
+		}
+		if n.NetMap.Real != n.Prefix.Masked() {
+			return status.Errorf(status.InvalidArgument, "netmap real prefix %s should match the resource prefix %s", n.NetMap.Real, n.Prefix)
+		}
+	}
+
+	n.NetMap = route.NewNetMap(n.NetMap.Real, n.NetMap.Virtual)
+	return n.NetMap.Validate()
 }
 
 func (n *NetworkResource) Copy() *NetworkResource {
@@ -105,6 +150,7 @@ func (n *NetworkResource) Copy() *NetworkResource {
 		Prefix:      n.Prefix,
 		GroupIDs:    n.GroupIDs,
 		Enabled:     n.Enabled,
+		NetMap:      n.NetMap.Copy(),
 	}
 }
 
@@ -123,6 +169,11 @@ func (n *NetworkResource) ToRoute(peer *nbpeer.Peer, router *routerTypes.Network
 		Enabled:             n.Enabled,
 		Groups:              nil,
 		AccessControlGroups: nil,
+		HealthCheck:         router.HealthCheck.Copy(),
+		LoadBalance:         router.LoadBalance,
+		BGP:                 router.BGP.Copy(),
+		Multicast:           router.Multicast.Copy(),
+		NetMap:              n.NetMap.Copy(),
 	}
 
 	if n.Type == Host || n.Type == Subnet {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/rs/xid"
 
+	"github.com/netbirdio/netbird/management/internals/modules/history"
 	"github.com/netbirdio/netbird/management/server/account"
 	"github.com/netbirdio/netbird/management/server/activity"
 	"github.com/netbirdio/netbird/management/server/networks/routers/types"
@@ -88,6 +89,24 @@ func (m *managerImpl) CreateRouter(ctx context.Context, userID string, router *t
 		return nil, status.NewPermissionDeniedError()
 	}
 
+	if router.HealthCheck != nil {
+		if err = router.HealthCheck.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if router.BGP != nil {
+		if err = router.BGP.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if router.Multicast != nil {
+		if err = router.Multicast.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	var network *networkTypes.Network
 	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
 		network, err = transaction.GetNetworkByID(ctx, store.LockingStrengthNone, router.AccountID, router.NetworkID)
@@ -106,6 +125,11 @@ func (m *managerImpl) CreateRouter(ctx context.Context, userID string, router *t
 			return fmt.Errorf("failed to create network router: %w", err)
 		}
 
+		err = history.Record(ctx, transaction, router.AccountID, userID, history.ObjectNetworkRouter, router.ID, history.OperationCreated, nil, router)
+		if err != nil {
+			return err
+		}
+
 		err = transaction.IncrementNetworkSerial(ctx, router.AccountID)
 		if err != nil {
 			return fmt.Errorf("failed to increment network serial: %w", err)
@@ -154,6 +178,24 @@ func (m *managerImpl) UpdateRouter(ctx context.Context, userID string, router *t
 		return nil, status.NewPermissionDeniedError()
 	}
 
+	if router.HealthCheck != nil {
+		if err = router.HealthCheck.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if router.BGP != nil {
+		if err = router.BGP.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if router.Multicast != nil {
+		if err = router.Multicast.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	var network *networkTypes.Network
 	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
 		network, err = transaction.GetNetworkByID(ctx, store.LockingStrengthNone, router.AccountID, router.NetworkID)
@@ -165,11 +207,26 @@ func (m *managerImpl) UpdateRouter(ctx context.Context, userID string, router *t
 			return status.NewRouterNotPartOfNetworkError(router.ID, router.NetworkID)
 		}
 
+		// the router is saved even if it doesn't exist yet, its version is then recorded as a creation
+		operation := history.OperationUpdated
+		oldRouter, err := transaction.GetNetworkRouterByID(ctx, store.LockingStrengthUpdate, router.AccountID, router.ID)
+		if err != nil {
+			if sErr, ok := status.FromError(err); !ok || sErr.Type() != status.NotFound {
+				return fmt.Errorf("failed to get network router: %w", err)
+			}
+			operation = history.OperationCreated
+		}
+
 		err = transaction.SaveNetworkRouter(ctx, router)
 		if err != nil {
 			return fmt.Errorf("failed to update network router: %w", err)
 		}
 
+		err = history.Record(ctx, transaction, router.AccountID, userID, history.ObjectNetworkRouter, router.ID, operation, oldRouter, router)
+		if err != nil {
+			return err
+		}
+
 		err = transaction.IncrementNetworkSerial(ctx, router.AccountID)
 		if err != nil {
 			return fmt.Errorf("failed to increment network serial: %w", err)
@@ -242,6 +299,11 @@ func (m *managerImpl) DeleteRouterInTransaction(ctx context.Context, transaction
 		return nil, fmt.Errorf("failed to delete network router: %w", err)
 	}
 
+	err = history.Record(ctx, transaction, accountID, userID, history.ObjectNetworkRouter, routerID, history.OperationDeleted, router, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	event := func() {
 		m.accountManager.StoreEvent(ctx, userID, routerID, accountID, activity.NetworkRouterDeleted, router.EventMeta(network))
 	}
@@ -2,10 +2,15 @@ package types
 
 import (
 	"errors"
+	"math"
+	"net/netip"
+	"slices"
+	"time"
 
 	"github.com/rs/xid"
 
 	"github.com/netbirdio/netbird/management/server/networks/types"
+	"github.com/netbirdio/netbird/route"
 	"github.com/netbirdio/netbird/shared/management/http/api"
 )
 
@@ -18,6 +23,14 @@ type NetworkRouter struct {
 	Masquerade bool
 	Metric     int
 	Enabled    bool
+	// HealthCheck is run by the routing peers against a target in the network
+	HealthCheck *route.HealthCheck `gorm:"serializer:json"`
+	// LoadBalance distributes the traffic to the network across all available routing peers
+	LoadBalance bool
+	// BGP announces the NetBird network range to the LAN of the routing peers
+	BGP *route.BGP `gorm:"serializer:json"`
+	// Multicast relays multicast and broadcast traffic between the LAN of the routing peers and the peers
+	Multicast *route.Multicast `gorm:"serializer:json"`
 }
 
 func NewNetworkRouter(accountID string, networkID string, peer string, peerGroups []string, masquerade bool, metric int, enabled bool) (*NetworkRouter, error) {
@@ -39,12 +52,16 @@ func NewNetworkRouter(accountID string, networkID string, peer string, peerGroup
 
 func (n *NetworkRouter) ToAPIResponse() *api.NetworkRouter {
 	return &api.NetworkRouter{
-		Id:         n.ID,
-		Peer:       &n.Peer,
-		PeerGroups: &n.PeerGroups,
-		Masquerade: n.Masquerade,
-		Metric:     n.Metric,
-		Enabled:    n.Enabled,
+		Id:          n.ID,
+		Peer:        &n.Peer,
+		PeerGroups:  &n.PeerGroups,
+		Masquerade:  n.Masquerade,
+		Metric:      n.Metric,
+		Enabled:     n.Enabled,
+		HealthCheck: healthCheckToAPIResponse(n.HealthCheck),
+		LoadBalance: &n.LoadBalance,
+		Bgp:         bgpToAPIResponse(n.BGP),
+		Multicast:   multicastToAPIResponse(n.Multicast),
 	}
 }
 
@@ -60,19 +77,143 @@ func (n *NetworkRouter) FromAPIRequest(req *api.NetworkRouterRequest) {
 	n.Masquerade = req.Masquerade
 	n.Metric = req.Metric
 	n.Enabled = req.Enabled
+	n.HealthCheck = healthCheckFromAPIRequest(req.HealthCheck)
+	n.LoadBalance = req.LoadBalance != nil && *req.LoadBalance
+	n.BGP = bgpFromAPIRequest(req.Bgp)
+	n.Multicast = multicastFromAPIRequest(req.Multicast)
+}
+
+func healthCheckFromAPIRequest(req *api.RouteHealthCheck) *route.HealthCheck {
+	if req == nil {
+		return nil
+	}
+
+	var interval, timeout time.Duration
+	if req.Interval != nil {
+		interval = time.Duration(*req.Interval) * time.Second
+	}
+	if req.Timeout != nil {
+		timeout = time.Duration(*req.Timeout) * time.Second
+	}
+	return route.NewHealthCheck(route.HealthCheckType(req.Type), req.Target, interval, timeout)
+}
+
+func healthCheckToAPIResponse(hc *route.HealthCheck) *api.RouteHealthCheck {
+	if hc == nil {
+		return nil
+	}
+
+	interval := int(hc.Interval.Seconds())
+	timeout := int(hc.Timeout.Seconds())
+	return &api.RouteHealthCheck{
+		Type:     api.RouteHealthCheckType(hc.Type),
+		Target:   hc.Target,
+		Interval: &interval,
+		Timeout:  &timeout,
+	}
+}
+
+// bgpFromAPIRequest converts the requested BGP configuration, invalid addresses and AS numbers are left unset and
+// rejected by the validation
+func bgpFromAPIRequest(req *api.RouteBGP) *route.BGP {
+	if req == nil {
+		return nil
+	}
+
+	bgp := &route.BGP{LocalASN: toASN(req.LocalAsn)}
+	for _, neighbor := range req.Neighbors {
+		addr, _ := netip.ParseAddr(neighbor.Address)
+		bgp.Neighbors = append(bgp.Neighbors, route.BGPNeighbor{Address: addr, ASN: toASN(neighbor.Asn)})
+	}
+	return bgp
+}
+
+func bgpToAPIResponse(bgp *route.BGP) *api.RouteBGP {
+	if bgp == nil {
+		return nil
+	}
+
+	resp := &api.RouteBGP{
+		LocalAsn:  int64(bgp.LocalASN),
+		Neighbors: make([]api.RouteBGPNeighbor, 0, len(bgp.Neighbors)),
+	}
+	for _, neighbor := range bgp.Neighbors {
+		resp.Neighbors = append(resp.Neighbors, api.RouteBGPNeighbor{Address: neighbor.Address.String(), Asn: int64(neighbor.ASN)})
+	}
+	return resp
+}
+
+// multicastFromAPIRequest converts the requested multicast configuration, invalid groups are left unset and rejected by
+// the validation
+func multicastFromAPIRequest(req *api.RouteMulticast) *route.Multicast {
+	if req == nil {
+		return nil
+	}
+
+	multicast := &route.Multicast{MDNS: req.Mdns, SSDP: req.Ssdp, Broadcast: req.Broadcast}
+	if req.Groups != nil {
+		for _, group := range *req.Groups {
+			addrPort, _ := netip.ParseAddrPort(group)
+			multicast.Groups = append(multicast.Groups, addrPort)
+		}
+	}
+	return multicast
+}
+
+func multicastToAPIResponse(multicast *route.Multicast) *api.RouteMulticast {
+	if multicast == nil {
+		return nil
+	}
+
+	groups := make([]string, 0, len(multicast.Groups))
+	for _, group := range multicast.Groups {
+		groups = append(groups, group.String())
+	}
+	return &api.RouteMulticast{
+		Mdns:      multicast.MDNS,
+		Ssdp:      multicast.SSDP,
+		Groups:    &groups,
+		Broadcast: multicast.Broadcast,
+	}
+}
+
+func toASN(asn int64) uint32 {
+	if asn < 0 || asn > math.MaxUint32 {
+		return 0
+	}
+	return uint32(asn)
 }
 
 func (n *NetworkRouter) Copy() *NetworkRouter {
 	return &NetworkRouter{
-		ID:         n.ID,
-		NetworkID:  n.NetworkID,
-		AccountID:  n.AccountID,
-		Peer:       n.Peer,
-		PeerGroups: n.PeerGroups,
-		Masquerade: n.Masquerade,
-		Metric:     n.Metric,
-		Enabled:    n.Enabled,
+		ID:          n.ID,
+		NetworkID:   n.NetworkID,
+		AccountID:   n.AccountID,
+		Peer:        n.Peer,
+		PeerGroups:  n.PeerGroups,
+		Masquerade:  n.Masquerade,
+		Metric:      n.Metric,
+		Enabled:     n.Enabled,
+		HealthCheck: n.HealthCheck.Copy(),
+		LoadBalance: n.LoadBalance,
+		BGP:         n.BGP.Copy(),
+		Multicast:   n.Multicast.Copy(),
+	}
+}
+
+// IsRoutingPeer returns true if the peer, or one of its groups, is an enabled router of a network
+func IsRoutingPeer(routers []*NetworkRouter, peerID string, peerGroups []string) bool {
+	for _, router := range routers {
+		if !router.Enabled {
+			continue
+		}
+		if router.Peer == peerID || slices.ContainsFunc(router.PeerGroups, func(groupID string) bool {
+			return slices.Contains(peerGroups, groupID)
+		}) {
+			return true
+		}
 	}
+	return false
 }
 
 func (n *NetworkRouter) EventMeta(network *types.Network) map[string]any {
@@ -107,3 +107,31 @@ func TestNewNetworkRouter(t *testing.T) {
 		})
 	}
 }
+
+func TestIsRoutingPeer(t *testing.T) {
+	routers := []*NetworkRouter{
+		{ID: "router-1", Peer: "peer-1", Enabled: true},
+		{ID: "router-2", PeerGroups: []string{"group-1"}, Enabled: true},
+		{ID: "router-3", Peer: "peer-3", PeerGroups: nil, Enabled: false},
+	}
+
+	tests := []struct {
+		name       string
+		peerID     string
+		peerGroups []string
+		expected   bool
+	}{
+		{name: "router peer", peerID: "peer-1", expected: true},
+		{name: "peer of a router group", peerID: "peer-2", peerGroups: []string{"group-2", "group-1"}, expected: true},
+		{name: "peer of a disabled router", peerID: "peer-3", expected: false},
+		{name: "no router", peerID: "peer-4", peerGroups: []string{"group-2"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRoutingPeer(routers, tt.peerID, tt.peerGroups); got != tt.expected {
+				t.Errorf("IsRoutingPeer() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
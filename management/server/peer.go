@@ -15,6 +15,7 @@ import (
 	"golang.org/x/exp/maps"
 
 	nbdns "github.com/netbirdio/netbird/dns"
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
 	"github.com/netbirdio/netbird/management/server/geolocation"
 	"github.com/netbirdio/netbird/management/server/idp"
 	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
@@ -201,6 +202,7 @@ func (am *DefaultAccountManager) UpdatePeer(ctx context.Context, accountID, user
 	var sshChanged bool
 	var loginExpirationChanged bool
 	var inactivityExpirationChanged bool
+	var approved bool
 	var dnsDomain string
 
 	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
@@ -226,6 +228,11 @@ func (am *DefaultAccountManager) UpdatePeer(ctx context.Context, accountID, user
 			return err
 		}
 
+		if update.Status != nil && peer.Status.RequiresApproval && !update.Status.RequiresApproval {
+			peer.Status.RequiresApproval = false
+			approved = true
+		}
+
 		if peer.Name != update.Name {
 			var newLabel string
 
@@ -293,6 +300,10 @@ func (am *DefaultAccountManager) UpdatePeer(ctx context.Context, accountID, user
 		am.StoreEvent(ctx, userID, peer.ID, accountID, activity.PeerRenamed, peer.EventMeta(dnsDomain))
 	}
 
+	if approved {
+		am.StoreEvent(ctx, userID, peer.ID, accountID, activity.PeerApproved, peer.EventMeta(dnsDomain))
+	}
+
 	if loginExpirationChanged {
 		event := activity.PeerLoginExpirationEnabled
 		if !peer.LoginExpirationEnabled {
@@ -576,6 +587,8 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, accountID, setupKe
 	var ephemeral bool
 	var groupsToAdd []string
 	var allowExtraDNSLabels bool
+	var keyToCheck *types.SetupKey
+	var requiresApproval bool
 	if addedByUser {
 		user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthNone, userID)
 		if err != nil {
@@ -619,6 +632,8 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, accountID, setupKe
 		setupKeyName = sk.Name
 		allowExtraDNSLabels = sk.AllowExtraDNSLabels
 		accountID = sk.AccountID
+		requiresApproval = sk.RequiresApproval
+		keyToCheck = sk
 		if !sk.AllowExtraDNSLabels && len(peer.ExtraDNSLabels) > 0 {
 			return nil, nil, nil, status.Errorf(status.PreconditionFailed, "couldn't add peer: setup key doesn't allow extra DNS labels")
 		}
@@ -678,7 +693,34 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, accountID, setupKe
 		}
 	}
 
+	if keyToCheck != nil {
+		// checked after the geo lookup so that location based posture checks can be evaluated
+		if err = checkSetupKeyConstraints(ctx, am.Store, keyToCheck, newPeer); err != nil {
+			meta := map[string]any{
+				"name":      keyToCheck.Name,
+				"hostname":  peer.Meta.Hostname,
+				"os":        peer.Meta.GoOS,
+				"source_ip": newPeer.Location.ConnectionIP.String(),
+				"reason":    err.Error(),
+			}
+			am.StoreEvent(ctx, keyToCheck.Id, keyToCheck.Id, accountID, activity.SetupKeyEnrollmentRejected, meta)
+			return nil, nil, nil, status.Errorf(status.PermissionDenied, "couldn't add peer: %v", err)
+		}
+	}
+
 	newPeer = am.integratedPeerValidator.PreparePeer(ctx, accountID, newPeer, groupsToAdd, settings.Extra, temporary)
+	if requiresApproval {
+		newPeer.Status.RequiresApproval = true
+	}
+
+	var approvalConfig *peerapproval.Config
+	var autoApprovalRule *peerapproval.AutoApprovalRule
+	if !temporary {
+		approvalConfig, autoApprovalRule, err = am.checkPeerApproval(ctx, accountID, newPeer, groupsToAdd)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
 
 	network, err := am.Store.GetAccountNetwork(ctx, store.LockingStrengthNone, accountID)
 	if err != nil {
@@ -784,12 +826,13 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, accountID, setupKe
 	}
 
 	am.StoreEvent(ctx, opEvent.InitiatorID, opEvent.TargetID, opEvent.AccountID, opEvent.Activity, opEvent.Meta)
+	am.storePeerApprovalEvents(ctx, opEvent.InitiatorID, newPeer, approvalConfig, autoApprovalRule, opEvent.Meta)
 
 	if err := am.networkMapController.OnPeersAdded(ctx, accountID, []string{newPeer.ID}); err != nil {
 		log.WithContext(ctx).Errorf("failed to update network map cache for peer %s: %v", newPeer.ID, err)
 	}
 
-	p, nmap, pc, _, err := am.networkMapController.GetValidatedPeerWithMap(ctx, false, accountID, newPeer)
+	p, nmap, pc, _, err := am.networkMapController.GetValidatedPeerWithMap(ctx, newPeer.Status.RequiresApproval, accountID, newPeer)
 	return p, nmap, pc, err
 }
 
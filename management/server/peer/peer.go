@@ -130,6 +130,10 @@ type PeerSystemMeta struct { //nolint:revive
 	Environment        Environment `gorm:"serializer:json"`
 	Flags              Flags       `gorm:"serializer:json"`
 	Files              []File      `gorm:"serializer:json"`
+	// UnhealthyRoutes are the IDs of the routes served by the peer whose health checks fail
+	UnhealthyRoutes []string `gorm:"serializer:json"`
+	// DiscoveredSubnets are the routing table entries of the peer matching the subnet discovery allowlist
+	DiscoveredSubnets []string `gorm:"serializer:json"`
 }
 
 func (p PeerSystemMeta) isEqual(other PeerSystemMeta) bool {
@@ -159,6 +163,18 @@ func (p PeerSystemMeta) isEqual(other PeerSystemMeta) bool {
 		return false
 	}
 
+	slices.Sort(p.UnhealthyRoutes)
+	slices.Sort(other.UnhealthyRoutes)
+	if !slices.Equal(p.UnhealthyRoutes, other.UnhealthyRoutes) {
+		return false
+	}
+
+	slices.Sort(p.DiscoveredSubnets)
+	slices.Sort(other.DiscoveredSubnets)
+	if !slices.Equal(p.DiscoveredSubnets, other.DiscoveredSubnets) {
+		return false
+	}
+
 	return p.Hostname == other.Hostname &&
 		p.GoOS == other.GoOS &&
 		p.Kernel == other.Kernel &&
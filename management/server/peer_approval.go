@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	"github.com/netbirdio/netbird/management/server/activity"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// checkPeerApproval evaluates the peer approval configuration of the account for a new peer.
+// The peer is marked as pending if one of its groups requires approval and no auto-approval rule matches.
+// It returns the configuration, nil if the account has none, and the matching auto-approval rule, if any.
+func (am *DefaultAccountManager) checkPeerApproval(ctx context.Context, accountID string, peer *nbpeer.Peer, groupsToAdd []string) (*peerapproval.Config, *peerapproval.AutoApprovalRule, error) {
+	config, err := am.Store.GetPeerApprovalConfig(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		if sErr, ok := status.FromError(err); ok && sErr.Type() == status.NotFound {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to get peer approval config: %w", err)
+	}
+
+	// peers already pending, e.g., because of their setup key, can't be approved automatically
+	if peer.Status.RequiresApproval || len(config.RequiredGroups) == 0 {
+		return config, nil, nil
+	}
+
+	allGroup, err := am.Store.GetGroupByName(ctx, store.LockingStrengthNone, accountID, "All")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get All group: %w", err)
+	}
+
+	peerGroups := append(slices.Clone(groupsToAdd), allGroup.ID)
+	if !config.RequiresApproval(peerGroups) {
+		return config, nil, nil
+	}
+
+	if rule := config.MatchAutoApprovalRule(peer); rule != nil {
+		return config, rule, nil
+	}
+
+	peer.Status.RequiresApproval = true
+	return config, nil, nil
+}
+
+// storePeerApprovalEvents records how the approval of a new peer was resolved and notifies about pending peers
+func (am *DefaultAccountManager) storePeerApprovalEvents(ctx context.Context, initiatorID string, peer *nbpeer.Peer, config *peerapproval.Config, rule *peerapproval.AutoApprovalRule, meta map[string]any) {
+	if rule != nil {
+		ruleMeta := maps.Clone(meta)
+		ruleMeta["rule"] = rule.Name
+		am.StoreEvent(ctx, initiatorID, peer.ID, peer.AccountID, activity.PeerAutoApproved, ruleMeta)
+		return
+	}
+
+	if !peer.Status.RequiresApproval {
+		return
+	}
+
+	am.StoreEvent(ctx, initiatorID, peer.ID, peer.AccountID, activity.PeerApprovalPending, meta)
+	if am.peerApprovalNotifier != nil {
+		am.peerApprovalNotifier.NotifyPendingPeer(ctx, config, peer)
+	}
+}
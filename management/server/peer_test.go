@@ -28,11 +28,13 @@ import (
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map/controller"
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map/controller/cache"
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map/update_channel"
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
 	"github.com/netbirdio/netbird/management/internals/modules/peers"
 	ephemeral_manager "github.com/netbirdio/netbird/management/internals/modules/peers/ephemeral/manager"
 	"github.com/netbirdio/netbird/management/internals/server/config"
 	"github.com/netbirdio/netbird/management/internals/shared/grpc"
 	"github.com/netbirdio/netbird/management/server/http/testing/testing_tools"
+	"github.com/netbirdio/netbird/management/server/integrations/integrated_validator"
 	"github.com/netbirdio/netbird/management/server/integrations/port_forwarding"
 	"github.com/netbirdio/netbird/management/server/job"
 	"github.com/netbirdio/netbird/management/server/permissions"
@@ -196,7 +198,7 @@ func testGetNetworkMapGeneral(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userId, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userId, false, false, nil, false)
 	if err != nil {
 		t.Fatal("error creating setup key")
 		return
@@ -445,7 +447,7 @@ func TestAccountManager_GetPeerNetwork(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userId, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userId, false, false, nil, false)
 	if err != nil {
 		t.Fatal("error creating setup key")
 		return
@@ -517,7 +519,7 @@ func TestDefaultAccountManager_GetPeer(t *testing.T) {
 	}
 
 	// two peers one added by a regular user and one with a setup key
-	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, adminUser, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), account.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, adminUser, false, false, nil, false)
 	if err != nil {
 		t.Fatal("error creating setup key")
 		return
@@ -1180,7 +1182,7 @@ func TestToSyncResponse(t *testing.T) {
 	}
 	dnsCache := &cache.DNSConfigCache{}
 	accountSettings := &types.Settings{RoutingPeerDNSResolutionEnabled: true}
-	response := grpc.ToSyncResponse(context.Background(), config, config.HttpConfig, config.DeviceAuthorizationFlow, peer, turnRelayToken, turnRelayToken, networkMap, dnsName, checks, dnsCache, accountSettings, nil, []string{}, int64(dnsForwarderPort))
+	response := grpc.ToSyncResponse(context.Background(), config, config.HttpConfig, config.DeviceAuthorizationFlow, peer, turnRelayToken, turnRelayToken, networkMap, dnsName, checks, dnsCache, accountSettings, nil, []string{}, false, int64(dnsForwarderPort))
 
 	assert.NotNil(t, response)
 	// assert peer config
@@ -2017,7 +2019,7 @@ func TestPeerAccountPeersUpdate(t *testing.T) {
 		_, err := manager.CreateRoute(
 			context.Background(), account.Id, route.Network, route.NetworkType, route.Domains, route.Peer,
 			route.PeerGroups, route.Description, route.NetID, route.Masquerade, route.Metric,
-			route.Groups, []string{}, true, userID, route.KeepRoute, route.SkipAutoApply,
+			route.Groups, []string{}, true, userID, route.KeepRoute, route.SkipAutoApply, nil,
 		)
 		require.NoError(t, err)
 
@@ -2234,7 +2236,7 @@ func Test_AddPeer(t *testing.T) {
 		return
 	}
 
-	setupKey, err := manager.CreateSetupKey(context.Background(), accountID, "test-key", types.SetupKeyReusable, time.Hour, nil, 10000, userID, false, false)
+	setupKey, err := manager.CreateSetupKey(context.Background(), accountID, "test-key", types.SetupKeyReusable, time.Hour, nil, 10000, userID, false, false, nil, false)
 	if err != nil {
 		t.Fatal("error creating setup key")
 		return
@@ -2489,3 +2491,169 @@ func TestLoginPeer_ApprovedUserCanLogin(t *testing.T) {
 	_, _, _, err = manager.LoginPeer(context.Background(), login)
 	require.NoError(t, err, "Regular user should be able to login peers")
 }
+
+func TestAddPeer_SetupKeyConstraints(t *testing.T) {
+	manager, _, err := createManager(t)
+	require.NoError(t, err)
+
+	accountID := "testaccount"
+	userID := "testuser"
+	_, err = createAccount(manager, accountID, userID, "domain.com")
+	require.NoError(t, err)
+
+	postureCheck, err := manager.SavePostureChecks(context.Background(), accountID, userID, &posture.Checks{
+		Name: "min version",
+		Checks: posture.ChecksDefinition{
+			NBVersionCheck: &posture.NBVersionCheck{MinVersion: "0.50.0"},
+		},
+	}, true)
+	require.NoError(t, err)
+
+	constraints := &types.SetupKeyConstraints{
+		SourceRanges:     []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")},
+		OperatingSystems: []string{"linux"},
+		HostnamePattern:  "^k8s-node-[0-9]+$",
+		PostureChecks:    []string{postureCheck.ID},
+	}
+	setupKey, err := manager.CreateSetupKey(context.Background(), accountID, "k8s", types.SetupKeyReusable, time.Hour, nil,
+		types.SetupKeyUnlimitedUsage, userID, false, false, constraints, false)
+	require.NoError(t, err)
+
+	newPeer := func(ip, goos, hostname, version string) *nbpeer.Peer {
+		key, err := wgtypes.GeneratePrivateKey()
+		require.NoError(t, err)
+		return &nbpeer.Peer{
+			Key:      key.PublicKey().String(),
+			Meta:     nbpeer.PeerSystemMeta{Hostname: hostname, GoOS: goos, WtVersion: version},
+			Location: nbpeer.Location{ConnectionIP: net.ParseIP(ip)},
+		}
+	}
+
+	tests := []struct {
+		name string
+		peer *nbpeer.Peer
+	}{
+		{name: "source not allowed", peer: newPeer("198.51.100.10", "linux", "k8s-node-1", "0.60.0")},
+		{name: "operating system not allowed", peer: newPeer("203.0.113.10", "darwin", "k8s-node-1", "0.60.0")},
+		{name: "hostname not allowed", peer: newPeer("203.0.113.10", "linux", "laptop", "0.60.0")},
+		{name: "posture check failed", peer: newPeer("203.0.113.10", "linux", "k8s-node-1", "0.40.0")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := manager.AddPeer(context.Background(), "", setupKey.Key, "", tt.peer, false)
+			require.Error(t, err)
+			sErr, ok := status.FromError(err)
+			require.True(t, ok)
+			assert.Equal(t, status.PermissionDenied, sErr.Type())
+		})
+	}
+
+	peer, _, _, err := manager.AddPeer(context.Background(), "", setupKey.Key, "", newPeer("203.0.113.10", "linux", "k8s-node-1", "0.60.0"), false)
+	require.NoError(t, err)
+	assert.False(t, peer.Status.RequiresApproval)
+
+	storedKey, err := manager.Store.GetSetupKeyByID(context.Background(), store.LockingStrengthNone, accountID, setupKey.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 1, storedKey.UsedTimes, "rejected registrations shouldn't count as key usage")
+}
+
+func TestAddPeer_SetupKeyRequiresApproval(t *testing.T) {
+	manager, _, err := createManager(t)
+	require.NoError(t, err)
+	manager.integratedPeerValidator = integrated_validator.WithPeerApproval(MockIntegratedValidator{})
+
+	accountID := "testaccount"
+	userID := "testuser"
+	_, err = createAccount(manager, accountID, userID, "domain.com")
+	require.NoError(t, err)
+
+	setupKey, err := manager.CreateSetupKey(context.Background(), accountID, "approval", types.SetupKeyReusable, time.Hour, nil,
+		types.SetupKeyUnlimitedUsage, userID, false, false, nil, true)
+	require.NoError(t, err)
+
+	key, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+	peer, networkMap, _, err := manager.AddPeer(context.Background(), "", setupKey.Key, "", &nbpeer.Peer{
+		Key:  key.PublicKey().String(),
+		Meta: nbpeer.PeerSystemMeta{Hostname: "k8s-node-1", GoOS: "linux"},
+	}, false)
+	require.NoError(t, err)
+	assert.True(t, peer.Status.RequiresApproval)
+	assert.Empty(t, networkMap.Peers, "peer pending approval shouldn't receive a network map")
+
+	validPeers, _, err := manager.GetValidatedPeers(context.Background(), accountID)
+	require.NoError(t, err)
+	assert.NotContains(t, validPeers, peer.ID)
+
+	update := peer.Copy()
+	update.Status.RequiresApproval = false
+	_, err = manager.UpdatePeer(context.Background(), accountID, userID, update)
+	require.NoError(t, err)
+
+	stored, err := manager.Store.GetPeerByID(context.Background(), store.LockingStrengthNone, accountID, peer.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.Status.RequiresApproval)
+
+	validPeers, _, err = manager.GetValidatedPeers(context.Background(), accountID)
+	require.NoError(t, err)
+	assert.Contains(t, validPeers, peer.ID)
+}
+
+type pendingPeerNotifierMock struct {
+	peers []string
+}
+
+func (n *pendingPeerNotifierMock) NotifyPendingPeer(_ context.Context, _ *peerapproval.Config, peer *nbpeer.Peer) {
+	n.peers = append(n.peers, peer.ID)
+}
+
+func TestAddPeer_PeerApprovalConfig(t *testing.T) {
+	manager, _, err := createManager(t)
+	require.NoError(t, err)
+	notifier := &pendingPeerNotifierMock{}
+	manager.peerApprovalNotifier = notifier
+
+	accountID := "testaccount"
+	userID := "testuser"
+	_, err = createAccount(manager, accountID, userID, "domain.com")
+	require.NoError(t, err)
+
+	group := &types.Group{ID: "contractors", Name: "contractors"}
+	require.NoError(t, manager.CreateGroup(context.Background(), accountID, userID, group))
+
+	config := peerapproval.NewConfig(accountID)
+	config.RequiredGroups = []string{group.ID}
+	config.AutoApprovalRules = []peerapproval.AutoApprovalRule{
+		{Name: "corporate", Manufacturers: []string{"Apple Inc."}},
+	}
+	require.NoError(t, manager.Store.SavePeerApprovalConfig(context.Background(), config))
+
+	contractorKey, err := manager.CreateSetupKey(context.Background(), accountID, "contractors", types.SetupKeyReusable, time.Hour,
+		[]string{group.ID}, types.SetupKeyUnlimitedUsage, userID, false, false, nil, false)
+	require.NoError(t, err)
+	otherKey, err := manager.CreateSetupKey(context.Background(), accountID, "other", types.SetupKeyReusable, time.Hour,
+		nil, types.SetupKeyUnlimitedUsage, userID, false, false, nil, false)
+	require.NoError(t, err)
+
+	addPeer := func(setupKey, manufacturer string) *nbpeer.Peer {
+		key, err := wgtypes.GeneratePrivateKey()
+		require.NoError(t, err)
+		peer, _, _, err := manager.AddPeer(context.Background(), "", setupKey, "", &nbpeer.Peer{
+			Key:  key.PublicKey().String(),
+			Meta: nbpeer.PeerSystemMeta{Hostname: "host", GoOS: "darwin", SystemManufacturer: manufacturer},
+		}, false)
+		require.NoError(t, err)
+		return peer
+	}
+
+	pending := addPeer(contractorKey.Key, "Lenovo")
+	assert.True(t, pending.Status.RequiresApproval, "peer in a required group should be pending")
+
+	autoApproved := addPeer(contractorKey.Key, "apple inc.")
+	assert.False(t, autoApproved.Status.RequiresApproval, "peer matching an auto-approval rule should be approved")
+
+	other := addPeer(otherKey.Key, "Lenovo")
+	assert.False(t, other.Status.RequiresApproval, "peer outside of the required groups shouldn't be pending")
+
+	assert.Equal(t, []string{pending.ID}, notifier.peers)
+}
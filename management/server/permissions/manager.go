@@ -9,6 +9,7 @@ import (
 
 	"github.com/netbirdio/netbird/management/server/account"
 	"github.com/netbirdio/netbird/management/server/activity"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
 	"github.com/netbirdio/netbird/management/server/permissions/modules"
 	"github.com/netbirdio/netbird/management/server/permissions/operations"
 	"github.com/netbirdio/netbird/management/server/permissions/roles"
@@ -21,8 +22,10 @@ type Manager interface {
 	ValidateUserPermissions(ctx context.Context, accountID, userID string, module modules.Module, operation operations.Operation) (bool, error)
 	ValidateRoleModuleAccess(ctx context.Context, accountID string, role roles.RolePermissions, module modules.Module, operation operations.Operation) bool
 	ValidateAccountAccess(ctx context.Context, accountID string, user *types.User, allowOwnerAndAdmin bool) error
+	GetPeersInScope(ctx context.Context, accountID, userID string) (map[string]struct{}, error)
 
 	GetPermissionsByRole(ctx context.Context, role types.UserRole) (roles.Permissions, error)
+	GetUserPermissions(ctx context.Context, user *types.User) (roles.Permissions, error)
 	SetAccountManager(accountManager account.Manager)
 }
 
@@ -68,18 +71,39 @@ func (m *managerImpl) ValidateUserPermissions(
 		return false, err
 	}
 
-	if operation == operations.Read && user.IsServiceUser {
+	if scope := requestPATScope(ctx, userID); !scope.AllowsOperation(module, operation) {
+		return false, nil
+	}
+
+	if operation == operations.Read && user.IsServiceUser && !user.HasCustomRole() {
 		return true, nil // this should be replaced by proper granular access role
 	}
 
-	role, ok := roles.RolesMap[user.Role]
-	if !ok {
-		return false, status.NewUserRoleNotFoundError(string(user.Role))
+	role, err := m.getUserRole(ctx, user)
+	if err != nil {
+		return false, err
 	}
 
 	return m.ValidateRoleModuleAccess(ctx, accountID, role, module, operation), nil
 }
 
+// getUserRole returns the custom role of the user or its built-in role
+func (m *managerImpl) getUserRole(ctx context.Context, user *types.User) (roles.RolePermissions, error) {
+	if user.HasCustomRole() {
+		customRole, err := m.store.GetCustomRoleByID(ctx, store.LockingStrengthNone, user.AccountID, user.CustomRoleID)
+		if err != nil {
+			return roles.RolePermissions{}, err
+		}
+		return customRole.RolePermissions(), nil
+	}
+
+	role, ok := roles.RolesMap[user.Role]
+	if !ok {
+		return roles.RolePermissions{}, status.NewUserRoleNotFoundError(string(user.Role))
+	}
+	return role, nil
+}
+
 func (m *managerImpl) ValidateRoleModuleAccess(
 	ctx context.Context,
 	accountID string,
@@ -99,29 +123,132 @@ func (m *managerImpl) ValidateRoleModuleAccess(
 }
 
 func (m *managerImpl) ValidateAccountAccess(ctx context.Context, accountID string, user *types.User, allowOwnerAndAdmin bool) error {
-	if user.AccountID != accountID {
+	if user.AccountID == accountID {
+		return nil
+	}
+
+	if !m.isTenantAdmin(ctx, accountID, user) {
 		return status.NewUserNotPartOfAccountError()
 	}
 	return nil
 }
 
+// isTenantAdmin checks if the user is in one of the admin groups the managing account granted access to the tenant
+// account and its role allows access to the tenants of its account. Tenant admins act in the tenant account with the
+// permissions of their role.
+func (m *managerImpl) isTenantAdmin(ctx context.Context, accountID string, user *types.User) bool {
+	tenant, err := m.store.GetTenant(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		if sErr, ok := status.FromError(err); !ok || sErr.Type() != status.NotFound {
+			log.WithContext(ctx).Errorf("failed to get tenant %s: %v", accountID, err)
+		}
+		return false
+	}
+
+	if !tenant.IsAdmin(user) {
+		return false
+	}
+
+	if scope := requestPATScope(ctx, user.Id); !scope.AllowsOperation(modules.Tenants, operations.Read) {
+		return false
+	}
+
+	role, err := m.getUserRole(ctx, user)
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to get role of user %s: %v", user.Id, err)
+		return false
+	}
+
+	return m.ValidateRoleModuleAccess(ctx, user.AccountID, role, modules.Tenants, operations.Read)
+}
+
 func (m *managerImpl) GetPermissionsByRole(ctx context.Context, role types.UserRole) (roles.Permissions, error) {
 	roleMap, ok := roles.RolesMap[role]
 	if !ok {
 		return roles.Permissions{}, status.NewUserRoleNotFoundError(string(role))
 	}
 
+	return modulePermissions(roleMap), nil
+}
+
+// GetUserPermissions returns the permissions of the user, taking a custom role of the user and the scope of the
+// personal access token the request was authenticated with into account
+func (m *managerImpl) GetUserPermissions(ctx context.Context, user *types.User) (roles.Permissions, error) {
+	var permissions roles.Permissions
+	if user.HasCustomRole() {
+		customRole, err := m.store.GetCustomRoleByID(ctx, store.LockingStrengthNone, user.AccountID, user.CustomRoleID)
+		if err != nil {
+			return roles.Permissions{}, err
+		}
+		permissions = modulePermissions(customRole.RolePermissions())
+	} else {
+		var err error
+		permissions, err = m.GetPermissionsByRole(ctx, user.Role)
+		if err != nil {
+			return roles.Permissions{}, err
+		}
+	}
+
+	scope := requestPATScope(ctx, user.Id)
+	if scope == nil {
+		return permissions, nil
+	}
+
+	// the role permissions are shared, so the scoped permissions are built from scratch
+	scoped := make(roles.Permissions, len(permissions))
+	for module, ops := range permissions {
+		scoped[module] = make(map[operations.Operation]bool, len(ops))
+		for operation, allowed := range ops {
+			scoped[module][operation] = allowed && scope.AllowsOperation(module, operation)
+		}
+	}
+
+	return scoped, nil
+}
+
+// GetPeersInScope returns the IDs of the peers the personal access token of the request limits the user to, nil
+// when the user isn't limited to the peers of specific groups
+func (m *managerImpl) GetPeersInScope(ctx context.Context, accountID, userID string) (map[string]struct{}, error) {
+	scope := requestPATScope(ctx, userID)
+	if !scope.RestrictsPeers() {
+		return nil, nil
+	}
+
+	groups, err := m.store.GetAccountGroups(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return scope.PeersInScope(groups), nil
+}
+
+// requestPATScope returns the scope of the personal access token the request was authenticated with if the
+// request was made by the given user
+func requestPATScope(ctx context.Context, userID string) *types.PATScope {
+	scope := types.PATScopeFromContext(ctx)
+	if scope == nil {
+		return nil
+	}
+
+	userAuth, err := nbcontext.GetUserAuthFromContext(ctx)
+	if err != nil || userAuth.UserId != userID {
+		return nil
+	}
+	return scope
+}
+
+// modulePermissions returns the permissions of the role for every module
+func modulePermissions(role roles.RolePermissions) roles.Permissions {
 	permissions := roles.Permissions{}
 
 	for k := range modules.All {
-		if rolePermissions, ok := roleMap.Permissions[k]; ok {
+		if rolePermissions, ok := role.Permissions[k]; ok {
 			permissions[k] = rolePermissions
 			continue
 		}
-		permissions[k] = roleMap.AutoAllowNew
+		permissions[k] = role.AutoAllowNew
 	}
 
-	return permissions, nil
+	return permissions
 }
 
 func (m *managerImpl) SetAccountManager(accountManager account.Manager) {
@@ -17,7 +17,9 @@ const (
 	Users       Module = "users"
 	SetupKeys   Module = "setup_keys"
 	Pats        Module = "pats"
+	Roles       Module = "roles"
 	IdentityProviders Module = "identity_providers"
+	Tenants     Module = "tenants"
 )
 
 var All = map[Module]struct{}{
@@ -35,5 +37,7 @@ var All = map[Module]struct{}{
 	Users:       {},
 	SetupKeys:   {},
 	Pats:        {},
+	Roles:       {},
 	IdentityProviders: {},
+	Tenants:     {},
 }
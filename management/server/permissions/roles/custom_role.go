@@ -0,0 +1,141 @@
+package roles
+
+import (
+	"fmt"
+
+	"github.com/rs/xid"
+
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+// CustomRole is an account defined set of permissions. Users with the user role that are assigned a custom role get
+// its permissions instead of the permissions of the user role.
+type CustomRole struct {
+	ID          string `gorm:"primaryKey"`
+	AccountID   string `gorm:"index"`
+	Name        string
+	Description string
+	// Permissions lists the allowed operations per module, everything else is denied
+	Permissions Permissions `gorm:"serializer:json"`
+}
+
+func NewCustomRole(accountID, name, description string, permissions Permissions) *CustomRole {
+	return &CustomRole{
+		ID:          xid.New().String(),
+		AccountID:   accountID,
+		Name:        name,
+		Description: description,
+		Permissions: permissions,
+	}
+}
+
+// RolePermissions returns the permissions of the custom role in the form of the built-in roles
+func (r *CustomRole) RolePermissions() RolePermissions {
+	return RolePermissions{
+		Role:        types.UserRole(r.ID),
+		Permissions: r.Permissions,
+		AutoAllowNew: map[operations.Operation]bool{
+			operations.Read:   false,
+			operations.Create: false,
+			operations.Update: false,
+			operations.Delete: false,
+		},
+	}
+}
+
+// Validate checks the name and permissions of the custom role. Custom roles can't grant more than the admin role,
+// otherwise admins could create roles above their own, and can't grant access to the custom roles, otherwise their
+// holders could raise their own permissions.
+func (r *CustomRole) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("role name can't be empty")
+	}
+
+	for module, ops := range r.Permissions {
+		if _, ok := modules.All[module]; !ok {
+			return fmt.Errorf("unknown module %s", module)
+		}
+		for operation, allowed := range ops {
+			if !isOperation(operation) {
+				return fmt.Errorf("unknown operation %s on module %s", operation, module)
+			}
+			if allowed && module == modules.Roles {
+				return fmt.Errorf("module %s can't be granted to custom roles", module)
+			}
+			if allowed && !Admin.allows(module, operation) {
+				return fmt.Errorf("operation %s on module %s can't be granted to custom roles", operation, module)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *CustomRole) Copy() *CustomRole {
+	permissions := make(Permissions, len(r.Permissions))
+	for module, ops := range r.Permissions {
+		permissions[module] = make(map[operations.Operation]bool, len(ops))
+		for operation, allowed := range ops {
+			permissions[module][operation] = allowed
+		}
+	}
+
+	return &CustomRole{
+		ID:          r.ID,
+		AccountID:   r.AccountID,
+		Name:        r.Name,
+		Description: r.Description,
+		Permissions: permissions,
+	}
+}
+
+func (r *CustomRole) ToAPIResponse() *api.CustomRole {
+	permissions := make(map[string]map[string]bool, len(r.Permissions))
+	for module, ops := range r.Permissions {
+		permissions[string(module)] = make(map[string]bool, len(ops))
+		for operation, allowed := range ops {
+			permissions[string(module)][string(operation)] = allowed
+		}
+	}
+
+	return &api.CustomRole{
+		Id:          r.ID,
+		Name:        r.Name,
+		Description: &r.Description,
+		Permissions: permissions,
+	}
+}
+
+func (r *CustomRole) EventMeta() map[string]any {
+	return map[string]any{"name": r.Name}
+}
+
+// PermissionsFromAPI converts the permissions of an API request
+func PermissionsFromAPI(permissions map[string]map[string]bool) Permissions {
+	result := make(Permissions, len(permissions))
+	for module, ops := range permissions {
+		result[modules.Module(module)] = make(map[operations.Operation]bool, len(ops))
+		for operation, allowed := range ops {
+			result[modules.Module(module)][operations.Operation(operation)] = allowed
+		}
+	}
+	return result
+}
+
+// allows reports whether the role allows the operation on the module
+func (r RolePermissions) allows(module modules.Module, operation operations.Operation) bool {
+	if ops, ok := r.Permissions[module]; ok {
+		return ops[operation]
+	}
+	return r.AutoAllowNew[operation]
+}
+
+func isOperation(operation operations.Operation) bool {
+	switch operation {
+	case operations.Read, operations.Create, operations.Update, operations.Delete:
+		return true
+	}
+	return false
+}
@@ -100,7 +100,7 @@ func (am *DefaultAccountManager) DeletePostureChecks(ctx context.Context, accoun
 			return err
 		}
 
-		if err = isPostureCheckLinkedToPolicy(ctx, transaction, postureChecksID, accountID); err != nil {
+		if err = ValidateDeletePostureChecks(ctx, transaction, accountID, postureChecksID); err != nil {
 			return err
 		}
 
@@ -186,6 +186,16 @@ func validatePostureChecks(ctx context.Context, transaction store.Store, account
 	return nil
 }
 
+// ValidateDeletePostureChecks returns an error if the posture checks can't be deleted because they are still linked
+// to a policy or a setup key.
+func ValidateDeletePostureChecks(ctx context.Context, transaction store.Store, accountID, postureChecksID string) error {
+	if err := isPostureCheckLinkedToPolicy(ctx, transaction, postureChecksID, accountID); err != nil {
+		return err
+	}
+
+	return isPostureCheckLinkedToSetupKey(ctx, transaction, postureChecksID, accountID)
+}
+
 // isPostureCheckLinkedToPolicy checks whether the posture check is linked to any account policy.
 func isPostureCheckLinkedToPolicy(ctx context.Context, transaction store.Store, postureChecksID, accountID string) error {
 	policies, err := transaction.GetAccountPolicies(ctx, store.LockingStrengthNone, accountID)
@@ -201,3 +211,19 @@ func isPostureCheckLinkedToPolicy(ctx context.Context, transaction store.Store,
 
 	return nil
 }
+
+// isPostureCheckLinkedToSetupKey checks whether the posture check is required by any setup key constraints.
+func isPostureCheckLinkedToSetupKey(ctx context.Context, transaction store.Store, postureChecksID, accountID string) error {
+	setupKeys, err := transaction.GetAccountSetupKeys(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range setupKeys {
+		if key.Constraints != nil && slices.Contains(key.Constraints.PostureChecks, postureChecksID) {
+			return status.Errorf(status.PreconditionFailed, "posture checks have been linked to setup key: %s", key.Name)
+		}
+	}
+
+	return nil
+}
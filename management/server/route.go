@@ -9,6 +9,7 @@ import (
 
 	"github.com/rs/xid"
 
+	"github.com/netbirdio/netbird/management/internals/modules/history"
 	"github.com/netbirdio/netbird/management/server/activity"
 	"github.com/netbirdio/netbird/management/server/permissions/modules"
 	"github.com/netbirdio/netbird/management/server/permissions/operations"
@@ -133,7 +134,7 @@ func getRouteDescriptor(prefix netip.Prefix, domains domain.List) string {
 }
 
 // CreateRoute creates and saves a new route
-func (am *DefaultAccountManager) CreateRoute(ctx context.Context, accountID string, prefix netip.Prefix, networkType route.NetworkType, domains domain.List, peerID string, peerGroupIDs []string, description string, netID route.NetID, masquerade bool, metric int, groups, accessControlGroupIDs []string, enabled bool, userID string, keepRoute bool, skipAutoApply bool) (*route.Route, error) {
+func (am *DefaultAccountManager) CreateRoute(ctx context.Context, accountID string, prefix netip.Prefix, networkType route.NetworkType, domains domain.List, peerID string, peerGroupIDs []string, description string, netID route.NetID, masquerade bool, metric int, groups, accessControlGroupIDs []string, enabled bool, userID string, keepRoute bool, skipAutoApply bool, options *route.Options) (*route.Route, error) {
 	allowed, err := am.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.Routes, operations.Create)
 	if err != nil {
 		return nil, status.NewPermissionValidationError(err)
@@ -168,6 +169,13 @@ func (am *DefaultAccountManager) CreateRoute(ctx context.Context, accountID stri
 			AccessControlGroups: accessControlGroupIDs,
 			SkipAutoApply:       skipAutoApply,
 		}
+		if options != nil {
+			newRoute.HealthCheck = options.HealthCheck
+			newRoute.NetMap = options.NetMap
+			newRoute.LoadBalance = options.LoadBalance
+			newRoute.BGP = options.BGP
+			newRoute.Multicast = options.Multicast
+		}
 
 		if err = validateRoute(ctx, transaction, accountID, newRoute); err != nil {
 			return err
@@ -182,6 +190,10 @@ func (am *DefaultAccountManager) CreateRoute(ctx context.Context, accountID stri
 			return err
 		}
 
+		if err = history.Record(ctx, transaction, accountID, userID, history.ObjectRoute, string(newRoute.ID), history.OperationCreated, nil, newRoute); err != nil {
+			return err
+		}
+
 		return transaction.IncrementNetworkSerial(ctx, accountID)
 	})
 	if err != nil {
@@ -236,6 +248,10 @@ func (am *DefaultAccountManager) SaveRoute(ctx context.Context, accountID, userI
 			return err
 		}
 
+		if err = history.Record(ctx, transaction, accountID, userID, history.ObjectRoute, string(routeToSave.ID), history.OperationUpdated, oldRoute, routeToSave); err != nil {
+			return err
+		}
+
 		return transaction.IncrementNetworkSerial(ctx, accountID)
 	})
 	if err != nil {
@@ -279,6 +295,10 @@ func (am *DefaultAccountManager) DeleteRoute(ctx context.Context, accountID stri
 			return err
 		}
 
+		if err = history.Record(ctx, transaction, accountID, userID, history.ObjectRoute, string(routeID), history.OperationDeleted, route, nil); err != nil {
+			return err
+		}
+
 		return transaction.IncrementNetworkSerial(ctx, accountID)
 	})
 	if err != nil {
@@ -336,6 +356,33 @@ func validateRoute(ctx context.Context, transaction store.Store, accountID strin
 		return status.Errorf(status.InvalidArgument, "peer with ID and peer groups should not be provided at the same time")
 	}
 
+	if routeToSave.HealthCheck != nil {
+		if err := routeToSave.HealthCheck.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if routeToSave.NetMap != nil {
+		if err := validateRouteNetMap(routeToSave); err != nil {
+			return err
+		}
+	}
+
+	if routeToSave.BGP != nil {
+		if err := routeToSave.BGP.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if routeToSave.Multicast != nil {
+		if routeToSave.IsDynamic() || !routeToSave.Network.Addr().Is4() || routeToSave.Network.Bits() == 0 {
+			return status.Errorf(status.InvalidArgument, "multicast is only supported for IPv4 network routes that aren't exit nodes")
+		}
+		if err := routeToSave.Multicast.Validate(); err != nil {
+			return err
+		}
+	}
+
 	groupsMap, err := validateRouteGroups(ctx, transaction, accountID, routeToSave)
 	if err != nil {
 		return err
@@ -344,6 +391,19 @@ func validateRoute(ctx context.Context, transaction store.Store, accountID strin
 	return checkRoutePrefixOrDomainsExistForPeers(ctx, transaction, accountID, routeToSave, groupsMap)
 }
 
+// validateRouteNetMap validates the netmap of the route, the real prefix of network routes has to match the route network.
+func validateRouteNetMap(routeToSave *route.Route) error {
+	if err := routeToSave.NetMap.Validate(); err != nil {
+		return err
+	}
+
+	if !routeToSave.IsDynamic() && routeToSave.NetMap.Real != routeToSave.Network.Masked() {
+		return status.Errorf(status.InvalidArgument, "netmap real prefix %s should match the route network %s", routeToSave.NetMap.Real, routeToSave.Network)
+	}
+
+	return nil
+}
+
 // validateRouteGroups validates the route groups and returns the validated groups map.
 func validateRouteGroups(ctx context.Context, transaction store.Store, accountID string, routeToSave *route.Route) (map[string]*types.Group, error) {
 	groupsToValidate := slices.Concat(routeToSave.Groups, routeToSave.PeerGroups, routeToSave.AccessControlGroups)
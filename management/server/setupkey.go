@@ -2,12 +2,15 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"slices"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/netbird/management/server/activity"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
 	"github.com/netbirdio/netbird/management/server/permissions/modules"
 	"github.com/netbirdio/netbird/management/server/permissions/operations"
 	"github.com/netbirdio/netbird/management/server/store"
@@ -53,8 +56,11 @@ type SetupKeyUpdateOperation struct {
 
 // CreateSetupKey generates a new setup key with a given name, type, list of groups IDs to auto-assign to peers registered with this key,
 // and adds it to the specified account. A list of autoGroups IDs can be empty.
+// Optional enrollment constraints are checked when a peer registers with the key, and requiresApproval
+// marks peers registered with the key as pending approval.
 func (am *DefaultAccountManager) CreateSetupKey(ctx context.Context, accountID string, keyName string, keyType types.SetupKeyType,
-	expiresIn time.Duration, autoGroups []string, usageLimit int, userID string, ephemeral bool, allowExtraDNSLabels bool) (*types.SetupKey, error) {
+	expiresIn time.Duration, autoGroups []string, usageLimit int, userID string, ephemeral bool, allowExtraDNSLabels bool,
+	constraints *types.SetupKeyConstraints, requiresApproval bool) (*types.SetupKey, error) {
 
 	allowed, err := am.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, modules.SetupKeys, operations.Create)
 	if err != nil {
@@ -73,8 +79,16 @@ func (am *DefaultAccountManager) CreateSetupKey(ctx context.Context, accountID s
 			return status.Errorf(status.InvalidArgument, "invalid auto groups: %v", err)
 		}
 
+		if err = validateSetupKeyConstraints(ctx, transaction, accountID, constraints); err != nil {
+			return err
+		}
+
 		setupKey, plainKey = types.GenerateSetupKey(keyName, keyType, expiresIn, autoGroups, usageLimit, ephemeral, allowExtraDNSLabels)
 		setupKey.AccountID = accountID
+		setupKey.RequiresApproval = requiresApproval
+		if !constraints.IsEmpty() {
+			setupKey.Constraints = constraints.Copy()
+		}
 
 		events := am.prepareSetupKeyEvents(ctx, transaction, accountID, userID, autoGroups, nil, setupKey)
 		eventsToStore = append(eventsToStore, events...)
@@ -99,7 +113,8 @@ func (am *DefaultAccountManager) CreateSetupKey(ctx context.Context, accountID s
 // SaveSetupKey saves the provided SetupKey to the database overriding the existing one.
 // Due to the unique nature of a SetupKey certain properties must not be overwritten
 // (e.g. the key itself, creation date, ID, etc).
-// These properties are overwritten: AutoGroups, Revoked (only from false to true), and the UpdatedAt. The rest is copied from the existing key.
+// These properties are overwritten: AutoGroups, Revoked (only from false to true), Constraints, RequiresApproval and the UpdatedAt.
+// The rest is copied from the existing key.
 func (am *DefaultAccountManager) SaveSetupKey(ctx context.Context, accountID string, keyToSave *types.SetupKey, userID string) (*types.SetupKey, error) {
 	if keyToSave == nil {
 		return nil, status.Errorf(status.InvalidArgument, "provided setup key to update is nil")
@@ -115,6 +130,7 @@ func (am *DefaultAccountManager) SaveSetupKey(ctx context.Context, accountID str
 
 	var oldKey *types.SetupKey
 	var newKey *types.SetupKey
+	var restrictionsChanged bool
 	var eventsToStore []func()
 
 	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
@@ -131,12 +147,23 @@ func (am *DefaultAccountManager) SaveSetupKey(ctx context.Context, accountID str
 			return status.Errorf(status.InvalidArgument, "can't un-revoke a revoked setup key")
 		}
 
-		// only auto groups, revoked status (from false to true) can be updated
+		if err = validateSetupKeyConstraints(ctx, transaction, accountID, keyToSave.Constraints); err != nil {
+			return err
+		}
+
+		// only auto groups, revoked status (from false to true) and enrollment restrictions can be updated
 		newKey = oldKey.Copy()
 		newKey.AutoGroups = keyToSave.AutoGroups
 		newKey.Revoked = keyToSave.Revoked
+		newKey.Constraints = nil
+		if !keyToSave.Constraints.IsEmpty() {
+			newKey.Constraints = keyToSave.Constraints.Copy()
+		}
+		newKey.RequiresApproval = keyToSave.RequiresApproval
 		newKey.UpdatedAt = time.Now().UTC()
 
+		restrictionsChanged = !reflect.DeepEqual(oldKey.Constraints, newKey.Constraints) || oldKey.RequiresApproval != newKey.RequiresApproval
+
 		addedGroups := util.Difference(newKey.AutoGroups, oldKey.AutoGroups)
 		removedGroups := util.Difference(oldKey.AutoGroups, newKey.AutoGroups)
 
@@ -153,6 +180,10 @@ func (am *DefaultAccountManager) SaveSetupKey(ctx context.Context, accountID str
 		am.StoreEvent(ctx, userID, newKey.Id, accountID, activity.SetupKeyRevoked, newKey.EventMeta())
 	}
 
+	if restrictionsChanged {
+		am.StoreEvent(ctx, userID, newKey.Id, accountID, activity.SetupKeyUpdated, newKey.EventMeta())
+	}
+
 	for _, storeEvent := range eventsToStore {
 		storeEvent()
 	}
@@ -245,6 +276,73 @@ func validateSetupKeyAutoGroups(ctx context.Context, transaction store.Store, ac
 	return nil
 }
 
+// validateSetupKeyConstraints checks that the constraints are well-formed and reference existing posture checks.
+func validateSetupKeyConstraints(ctx context.Context, transaction store.Store, accountID string, constraints *types.SetupKeyConstraints) error {
+	if constraints == nil {
+		return nil
+	}
+
+	if err := constraints.Validate(); err != nil {
+		return status.Errorf(status.InvalidArgument, "invalid setup key constraints: %v", err)
+	}
+
+	if len(constraints.PostureChecks) == 0 {
+		return nil
+	}
+
+	postureChecks, err := transaction.GetPostureChecksByIDs(ctx, store.LockingStrengthNone, accountID, constraints.PostureChecks)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range constraints.PostureChecks {
+		if _, ok := postureChecks[id]; !ok {
+			return status.Errorf(status.InvalidArgument, "posture checks not found: %s", id)
+		}
+	}
+
+	return nil
+}
+
+// checkSetupKeyConstraints verifies that a registering peer satisfies the enrollment constraints of a setup key.
+func checkSetupKeyConstraints(ctx context.Context, transaction store.Store, key *types.SetupKey, peer *nbpeer.Peer) error {
+	if key.Constraints.IsEmpty() {
+		return nil
+	}
+
+	if err := key.Constraints.CheckPeer(peer); err != nil {
+		return err
+	}
+
+	if len(key.Constraints.PostureChecks) == 0 {
+		return nil
+	}
+
+	postureChecks, err := transaction.GetPostureChecksByIDs(ctx, store.LockingStrengthNone, key.AccountID, key.Constraints.PostureChecks)
+	if err != nil {
+		return fmt.Errorf("failed to get posture checks: %w", err)
+	}
+
+	for _, id := range key.Constraints.PostureChecks {
+		postureCheck, ok := postureChecks[id]
+		if !ok {
+			return fmt.Errorf("posture checks %s not found", id)
+		}
+
+		for _, check := range postureCheck.GetChecks() {
+			passed, err := check.Check(ctx, *peer)
+			if err != nil {
+				return fmt.Errorf("posture check %s failed: %w", postureCheck.Name, err)
+			}
+			if !passed {
+				return fmt.Errorf("posture check %s failed: %s", postureCheck.Name, check.Name())
+			}
+		}
+	}
+
+	return nil
+}
+
 // prepareSetupKeyEvents prepares a list of event functions to be stored.
 func (am *DefaultAccountManager) prepareSetupKeyEvents(ctx context.Context, transaction store.Store, accountID, userID string, addedGroups, removedGroups []string, key *types.SetupKey) []func() {
 	var eventsToStore []func()
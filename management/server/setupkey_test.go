@@ -5,6 +5,8 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"net"
+	"net/netip"
 	"strings"
 	"testing"
 	"time"
@@ -14,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/netbirdio/netbird/management/server/activity"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
 	"github.com/netbirdio/netbird/management/server/types"
 	"github.com/netbirdio/netbird/shared/auth"
 )
@@ -50,7 +53,7 @@ func TestDefaultAccountManager_SaveSetupKey(t *testing.T) {
 	keyName := "my-test-key"
 
 	key, err := manager.CreateSetupKey(context.Background(), account.Id, keyName, types.SetupKeyReusable, expiresIn, []string{},
-		types.SetupKeyUnlimitedUsage, userID, false, false)
+		types.SetupKeyUnlimitedUsage, userID, false, false, nil, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -168,7 +171,7 @@ func TestDefaultAccountManager_CreateSetupKey(t *testing.T) {
 	for _, tCase := range []testCase{testCase1, testCase2, testCase3} {
 		t.Run(tCase.name, func(t *testing.T) {
 			key, err := manager.CreateSetupKey(context.Background(), account.Id, tCase.expectedKeyName, types.SetupKeyReusable, expiresIn,
-				tCase.expectedGroups, types.SetupKeyUnlimitedUsage, userID, false, false)
+				tCase.expectedGroups, types.SetupKeyUnlimitedUsage, userID, false, false, nil, false)
 
 			if tCase.expectedFailure {
 				if err == nil {
@@ -210,7 +213,7 @@ func TestGetSetupKeys(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	plainKey, err := manager.CreateSetupKey(context.Background(), account.Id, "key1", types.SetupKeyReusable, time.Hour, nil, types.SetupKeyUnlimitedUsage, userID, false, false)
+	plainKey, err := manager.CreateSetupKey(context.Background(), account.Id, "key1", types.SetupKeyReusable, time.Hour, nil, types.SetupKeyUnlimitedUsage, userID, false, false, nil, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -436,7 +439,7 @@ func TestSetupKeyAccountPeersUpdate(t *testing.T) {
 			close(done)
 		}()
 
-		setupKey, err = manager.CreateSetupKey(context.Background(), account.Id, "key1", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false)
+		setupKey, err = manager.CreateSetupKey(context.Background(), account.Id, "key1", types.SetupKeyReusable, time.Hour, nil, 999, userID, false, false, nil, false)
 		assert.NoError(t, err)
 
 		select {
@@ -477,7 +480,7 @@ func TestDefaultAccountManager_CreateSetupKey_ShouldNotAllowToUpdateRevokedKey(t
 		t.Fatal(err)
 	}
 
-	key, err := manager.CreateSetupKey(context.Background(), account.Id, "testName", types.SetupKeyReusable, time.Hour, nil, types.SetupKeyUnlimitedUsage, userID, false, false)
+	key, err := manager.CreateSetupKey(context.Background(), account.Id, "testName", types.SetupKeyReusable, time.Hour, nil, types.SetupKeyUnlimitedUsage, userID, false, false, nil, false)
 	assert.NoError(t, err)
 
 	// revoke the key
@@ -492,3 +495,97 @@ func TestDefaultAccountManager_CreateSetupKey_ShouldNotAllowToUpdateRevokedKey(t
 	assert.Error(t, err, "should not allow to update revoked key")
 
 }
+
+func TestSetupKeyConstraints_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints *types.SetupKeyConstraints
+		wantErr     bool
+	}{
+		{name: "nil", constraints: nil},
+		{
+			name: "valid",
+			constraints: &types.SetupKeyConstraints{
+				SourceRanges:     []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")},
+				OperatingSystems: []string{"linux"},
+				HostnamePattern:  "^k8s-node-[0-9]+$",
+				PostureChecks:    []string{"check"},
+			},
+		},
+		{name: "unknown operating system", constraints: &types.SetupKeyConstraints{OperatingSystems: []string{"plan9"}}, wantErr: true},
+		{name: "invalid hostname pattern", constraints: &types.SetupKeyConstraints{HostnamePattern: "k8s-("}, wantErr: true},
+		{name: "empty posture check", constraints: &types.SetupKeyConstraints{PostureChecks: []string{""}}, wantErr: true},
+		{name: "invalid source range", constraints: &types.SetupKeyConstraints{SourceRanges: []netip.Prefix{{}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.constraints.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSetupKeyConstraints_CheckPeer(t *testing.T) {
+	constraints := &types.SetupKeyConstraints{
+		SourceRanges:     []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")},
+		OperatingSystems: []string{"linux"},
+		HostnamePattern:  "^k8s-node-[0-9]+$",
+	}
+
+	newPeer := func(ip, goos, hostname string) *nbpeer.Peer {
+		return &nbpeer.Peer{
+			Meta:     nbpeer.PeerSystemMeta{GoOS: goos, Hostname: hostname},
+			Location: nbpeer.Location{ConnectionIP: net.ParseIP(ip)},
+		}
+	}
+
+	assert.NoError(t, constraints.CheckPeer(newPeer("203.0.113.10", "linux", "k8s-node-1")))
+	assert.NoError(t, constraints.CheckPeer(newPeer("::ffff:203.0.113.10", "linux", "k8s-node-1")), "IPv4-mapped address should match")
+	assert.Error(t, constraints.CheckPeer(newPeer("198.51.100.10", "linux", "k8s-node-1")), "source outside of the allowed ranges")
+	assert.Error(t, constraints.CheckPeer(newPeer("", "linux", "k8s-node-1")), "unknown source")
+	assert.Error(t, constraints.CheckPeer(newPeer("203.0.113.10", "darwin", "k8s-node-1")), "operating system not allowed")
+	assert.Error(t, constraints.CheckPeer(newPeer("203.0.113.10", "linux", "laptop")), "hostname doesn't match")
+
+	unanchored := &types.SetupKeyConstraints{HostnamePattern: "k8s-node-.*"}
+	assert.NoError(t, unanchored.CheckPeer(newPeer("203.0.113.10", "linux", "k8s-node-1")))
+	assert.Error(t, unanchored.CheckPeer(newPeer("203.0.113.10", "linux", "laptop-k8s-node-1")), "the pattern has to match the whole hostname")
+
+	var noConstraints *types.SetupKeyConstraints
+	assert.NoError(t, noConstraints.CheckPeer(newPeer("198.51.100.10", "darwin", "laptop")))
+}
+
+func TestDefaultAccountManager_SaveSetupKey_Constraints(t *testing.T) {
+	manager, _, err := createManager(t)
+	require.NoError(t, err)
+
+	userID := "testingUser"
+	account, err := manager.GetOrCreateAccountByUser(context.Background(), auth.UserAuth{UserId: userID})
+	require.NoError(t, err)
+
+	_, err = manager.CreateSetupKey(context.Background(), account.Id, "testName", types.SetupKeyReusable, time.Hour, nil,
+		types.SetupKeyUnlimitedUsage, userID, false, false, &types.SetupKeyConstraints{PostureChecks: []string{"unknown"}}, false)
+	assert.Error(t, err, "should not allow unknown posture checks")
+
+	key, err := manager.CreateSetupKey(context.Background(), account.Id, "testName", types.SetupKeyReusable, time.Hour, nil,
+		types.SetupKeyUnlimitedUsage, userID, false, false, &types.SetupKeyConstraints{OperatingSystems: []string{"linux"}}, true)
+	require.NoError(t, err)
+	assert.True(t, key.RequiresApproval)
+	assert.Equal(t, []string{"linux"}, key.Constraints.OperatingSystems)
+
+	updateKey := key.Copy()
+	updateKey.Constraints = &types.SetupKeyConstraints{HostnamePattern: "k8s-("}
+	_, err = manager.SaveSetupKey(context.Background(), account.Id, updateKey, userID)
+	assert.Error(t, err, "should not allow an invalid hostname pattern")
+
+	updateKey.Constraints = &types.SetupKeyConstraints{}
+	updateKey.RequiresApproval = false
+	updated, err := manager.SaveSetupKey(context.Background(), account.Id, updateKey, userID)
+	require.NoError(t, err)
+	assert.Nil(t, updated.Constraints)
+	assert.False(t, updated.RequiresApproval)
+}
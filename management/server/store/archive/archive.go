@@ -0,0 +1,192 @@
+// Package archive exports a single account with everything it owns to a versioned JSON archive
+// and imports such an archive into another store, e.g., to move an account between management servers.
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	"github.com/netbirdio/netbird/management/internals/modules/declarative"
+	"github.com/netbirdio/netbird/management/internals/modules/history"
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	"github.com/netbirdio/netbird/management/internals/modules/portforwards"
+	"github.com/netbirdio/netbird/management/internals/modules/subnetdiscovery"
+	"github.com/netbirdio/netbird/management/internals/modules/tenants"
+	"github.com/netbirdio/netbird/management/internals/modules/zones"
+	"github.com/netbirdio/netbird/management/server/permissions/roles"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// Version is the format version of the archives written by Export.
+// It has to be increased whenever a change of the archive content can't be read by older versions.
+const Version = 1
+
+// Archive holds an account and the account scoped objects that are not part of types.Account.
+// Setup keys and personal access tokens are exported as hashes, the plain secrets can't be recovered.
+type Archive struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	// Account contains settings, users, groups, policies, posture checks, routes, networks, DNS settings,
+	// nameserver groups, setup keys and peers
+	Account      *types.Account              `json:"account"`
+	Zones        []*zones.Zone               `json:"zones,omitempty"`
+	CustomRoles  []*roles.CustomRole         `json:"custom_roles,omitempty"`
+	PeerApproval *peerapproval.Config        `json:"peer_approval,omitempty"`
+	PortForwards []*portforwards.PortForward `json:"port_forwards,omitempty"`
+	// ManagedObjects are the objects created or updated by the declarative configuration
+	ManagedObjects []*declarative.ManagedObject `json:"managed_objects,omitempty"`
+	// Tenant is set if the account is managed by another account, the managing account is not part of the archive
+	Tenant *tenants.Tenant `json:"tenant,omitempty"`
+	// ManagedTenants link the account to the accounts it manages, the tenant accounts are exported separately
+	ManagedTenants  []*tenants.Tenant   `json:"managed_tenants,omitempty"`
+	TenantTemplates []*tenants.Template `json:"tenant_templates,omitempty"`
+	// AccessRequests include the approved requests, whose temporary groups and policies are part of the account
+	AccessRequests        []*accessrequests.AccessRequest `json:"access_requests,omitempty"`
+	AccessRequestSettings *accessrequests.Settings        `json:"access_request_settings,omitempty"`
+	// DiscoveredSubnets keep the dismissed subnets from being suggested again after the import
+	DiscoveredSubnets []*subnetdiscovery.DiscoveredSubnet `json:"discovered_subnets,omitempty"`
+	// ObjectVersions are ordered from the oldest, so the import reproduces the version numbers
+	ObjectVersions []*history.Version `json:"object_versions,omitempty"`
+}
+
+// Export reads the account with the given ID and everything it owns from the store
+func Export(ctx context.Context, s store.Store, accountID string) (*Archive, error) {
+	account, err := s.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("get account: %w", err)
+	}
+
+	// group memberships are exported with Group.Peers, the join rows are recreated on import
+	for _, group := range account.Groups {
+		group.GroupPeers = nil
+	}
+
+	accountZones, err := s.GetAccountZones(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("get zones: %w", err)
+	}
+
+	customRoles, err := s.GetAccountCustomRoles(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("get custom roles: %w", err)
+	}
+
+	peerApproval, err := s.GetPeerApprovalConfig(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		if sErr, ok := status.FromError(err); !ok || sErr.Type() != status.NotFound {
+			return nil, fmt.Errorf("get peer approval config: %w", err)
+		}
+		peerApproval = nil
+	}
+
+	portForwards, err := s.GetAccountPortForwards(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("get port forwards: %w", err)
+	}
+
+	managedObjects, err := s.GetManagedObjects(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("get managed objects: %w", err)
+	}
+
+	tenant, err := s.GetTenant(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		if sErr, ok := status.FromError(err); !ok || sErr.Type() != status.NotFound {
+			return nil, fmt.Errorf("get tenant: %w", err)
+		}
+		tenant = nil
+	}
+
+	managedTenants, err := s.GetManagedTenants(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("get managed tenants: %w", err)
+	}
+
+	tenantTemplates, err := s.GetTenantTemplates(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("get tenant templates: %w", err)
+	}
+
+	accessRequests, err := s.GetAccessRequests(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("get access requests: %w", err)
+	}
+
+	accessRequestSettings, err := s.GetAccessRequestSettings(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		if sErr, ok := status.FromError(err); !ok || sErr.Type() != status.NotFound {
+			return nil, fmt.Errorf("get access request settings: %w", err)
+		}
+		accessRequestSettings = nil
+	}
+
+	discoveredSubnets, err := s.GetDiscoveredSubnets(ctx, store.LockingStrengthNone, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("get discovered subnets: %w", err)
+	}
+
+	var objectVersions []*history.Version
+	for _, objectType := range history.ObjectTypes {
+		versions, err := s.GetObjectVersionsByType(ctx, store.LockingStrengthNone, accountID, objectType)
+		if err != nil {
+			return nil, fmt.Errorf("get %s versions: %w", objectType, err)
+		}
+		objectVersions = append(objectVersions, versions...)
+	}
+	sort.Slice(objectVersions, func(i, j int) bool {
+		return objectVersions[i].Seq < objectVersions[j].Seq
+	})
+
+	return &Archive{
+		Version:               Version,
+		ExportedAt:            time.Now().UTC(),
+		Account:               account,
+		Zones:                 accountZones,
+		CustomRoles:           customRoles,
+		PeerApproval:          peerApproval,
+		PortForwards:          portForwards,
+		ManagedObjects:        managedObjects,
+		Tenant:                tenant,
+		ManagedTenants:        managedTenants,
+		TenantTemplates:       tenantTemplates,
+		AccessRequests:        accessRequests,
+		AccessRequestSettings: accessRequestSettings,
+		DiscoveredSubnets:     discoveredSubnets,
+		ObjectVersions:        objectVersions,
+	}, nil
+}
+
+// Write encodes the archive as indented JSON
+func (a *Archive) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(a); err != nil {
+		return fmt.Errorf("encode archive: %w", err)
+	}
+	return nil
+}
+
+// Read decodes an archive and checks that its version is supported
+func Read(r io.Reader) (*Archive, error) {
+	var archive Archive
+	if err := json.NewDecoder(r).Decode(&archive); err != nil {
+		return nil, fmt.Errorf("decode archive: %w", err)
+	}
+
+	if archive.Version < 1 || archive.Version > Version {
+		return nil, fmt.Errorf("unsupported archive version %d, supported versions are 1 to %d", archive.Version, Version)
+	}
+
+	if archive.Account == nil || archive.Account.Id == "" {
+		return nil, fmt.Errorf("archive doesn't contain an account")
+	}
+
+	return &archive, nil
+}
@@ -0,0 +1,306 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	"github.com/netbirdio/netbird/management/internals/modules/declarative"
+	"github.com/netbirdio/netbird/management/internals/modules/history"
+	"github.com/netbirdio/netbird/management/internals/modules/subnetdiscovery"
+	"github.com/netbirdio/netbird/management/internals/modules/tenants"
+	"github.com/netbirdio/netbird/management/internals/modules/zones"
+	"github.com/netbirdio/netbird/management/internals/modules/zones/records"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
+	"github.com/netbirdio/netbird/management/server/permissions/roles"
+	"github.com/netbirdio/netbird/management/server/store"
+)
+
+const (
+	testAccountID = "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+	testTenantID  = "tenant-account"
+)
+
+func newSourceStore(t *testing.T) store.Store {
+	t.Helper()
+
+	ctx := context.Background()
+	s, cleanup, err := store.NewTestStoreFromSQL(ctx, "../../testdata/store.sql", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	account, err := s.GetAccount(ctx, testAccountID)
+	require.NoError(t, err)
+
+	var groupID string
+	for id := range account.Groups {
+		groupID = id
+		break
+	}
+
+	zone := zones.NewZone(testAccountID, "internal", "internal.example.com", true, false, []string{groupID})
+	zone.Records = []*records.Record{records.NewRecord(testAccountID, zone.ID, "db.internal.example.com", records.RecordTypeA, "10.0.0.10", 300)}
+	require.NoError(t, s.CreateZone(ctx, zone))
+
+	role := roles.NewCustomRole(testAccountID, "helpdesk", "", roles.Permissions{modules.Peers: {operations.Read: true}})
+	require.NoError(t, s.SaveCustomRole(ctx, role))
+
+	require.NoError(t, s.SaveManagedObjects(ctx, []*declarative.ManagedObject{
+		{AccountID: testAccountID, Kind: declarative.KindGroup, ObjectID: groupID, Name: "managed", Hash: "hash"},
+	}))
+
+	template := tenants.NewTemplate(testAccountID, "default", "", declarative.Spec{})
+	require.NoError(t, s.SaveTenantTemplate(ctx, template))
+	require.NoError(t, s.SaveTenant(ctx, &tenants.Tenant{AccountID: testTenantID, ManagedBy: testAccountID, Name: "tenant", TemplateID: template.ID, AdminGroups: []string{groupID}}))
+
+	request := accessrequests.NewAccessRequest(testAccountID, account.CreatedBy, accessrequests.TargetGroup, groupID, "debugging", time.Hour)
+	require.NoError(t, s.SaveAccessRequest(ctx, request))
+	require.NoError(t, s.SaveAccessRequestSettings(ctx, &accessrequests.Settings{AccountID: testAccountID, ApproverGroups: []string{groupID}, MaxDuration: time.Hour}))
+
+	router := account.NetworkRouters[0]
+	subnet := subnetdiscovery.NewDiscoveredSubnet(testAccountID, subnetdiscovery.Candidate{NetworkID: router.NetworkID, PeerID: router.Peer, Prefix: netip.MustParsePrefix("192.168.50.0/24")}, time.Now().UTC())
+	subnet.Status = subnetdiscovery.StatusDismissed
+	require.NoError(t, s.SaveDiscoveredSubnet(ctx, subnet))
+
+	require.NoError(t, history.Record(ctx, s, testAccountID, account.CreatedBy, history.ObjectNetworkRouter, router.ID, history.OperationCreated, nil, router))
+	require.NoError(t, history.Record(ctx, s, testAccountID, account.CreatedBy, history.ObjectNetworkRouter, router.ID, history.OperationUpdated, router, router))
+
+	return s
+}
+
+func newEmptyStore(t *testing.T) store.Store {
+	t.Helper()
+
+	s, cleanup, err := store.NewTestStoreFromSQL(context.Background(), "", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+	return s
+}
+
+// exportArchive exports the test account and passes it through the JSON encoding
+func exportArchive(t *testing.T, s store.Store) *Archive {
+	t.Helper()
+
+	exported, err := Export(context.Background(), s, testAccountID)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, exported.Write(&buf))
+
+	archive, err := Read(&buf)
+	require.NoError(t, err)
+	return archive
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	source := newSourceStore(t)
+	archive := exportArchive(t, source)
+
+	target := newEmptyStore(t)
+	result, err := Import(ctx, target, archive, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, testAccountID, result.AccountID)
+	assert.Nil(t, result.IDs)
+
+	expected, err := source.GetAccount(ctx, testAccountID)
+	require.NoError(t, err)
+	imported, err := target.GetAccount(ctx, testAccountID)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected.Domain, imported.Domain)
+	assert.Equal(t, expected.Settings, imported.Settings)
+	assert.Equal(t, expected.Network.Net.String(), imported.Network.Net.String())
+	assert.ElementsMatch(t, keys(expected.Users), keys(imported.Users))
+	assert.ElementsMatch(t, keys(expected.Peers), keys(imported.Peers))
+	assert.ElementsMatch(t, keys(expected.SetupKeys), keys(imported.SetupKeys))
+	assert.Len(t, imported.Policies, len(expected.Policies))
+	assert.Len(t, imported.Networks, len(expected.Networks))
+	assert.Len(t, imported.NetworkResources, len(expected.NetworkResources))
+	for id, group := range expected.Groups {
+		require.Contains(t, imported.Groups, id)
+		assert.ElementsMatch(t, group.Peers, imported.Groups[id].Peers)
+	}
+	for id, user := range expected.Users {
+		assert.ElementsMatch(t, keys(user.PATs), keys(imported.Users[id].PATs))
+	}
+
+	importedZones, err := target.GetAccountZones(ctx, store.LockingStrengthNone, testAccountID)
+	require.NoError(t, err)
+	require.Len(t, importedZones, 1)
+	assert.Len(t, importedZones[0].Records, 1)
+
+	importedRoles, err := target.GetAccountCustomRoles(ctx, store.LockingStrengthNone, testAccountID)
+	require.NoError(t, err)
+	require.Len(t, importedRoles, 1)
+	assert.Equal(t, "helpdesk", importedRoles[0].Name)
+
+	importedObjects, err := target.GetManagedObjects(ctx, store.LockingStrengthNone, testAccountID)
+	require.NoError(t, err)
+	require.Len(t, importedObjects, 1)
+	assert.Contains(t, imported.Groups, importedObjects[0].ObjectID)
+
+	importedTemplates, err := target.GetTenantTemplates(ctx, store.LockingStrengthNone, testAccountID)
+	require.NoError(t, err)
+	require.Len(t, importedTemplates, 1)
+	importedTenants, err := target.GetManagedTenants(ctx, store.LockingStrengthNone, testAccountID)
+	require.NoError(t, err)
+	require.Len(t, importedTenants, 1)
+	assert.Equal(t, testTenantID, importedTenants[0].AccountID)
+	assert.Equal(t, importedTemplates[0].ID, importedTenants[0].TemplateID)
+
+	importedRequests, err := target.GetAccessRequests(ctx, store.LockingStrengthNone, testAccountID)
+	require.NoError(t, err)
+	require.Len(t, importedRequests, 1)
+	importedSettings, err := target.GetAccessRequestSettings(ctx, store.LockingStrengthNone, testAccountID)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, importedSettings.MaxDuration)
+
+	importedSubnets, err := target.GetDiscoveredSubnets(ctx, store.LockingStrengthNone, testAccountID)
+	require.NoError(t, err)
+	require.Len(t, importedSubnets, 1)
+	assert.Equal(t, subnetdiscovery.StatusDismissed, importedSubnets[0].Status)
+
+	importedVersions, err := target.GetObjectVersionsByType(ctx, store.LockingStrengthNone, testAccountID, history.ObjectNetworkRouter)
+	require.NoError(t, err)
+	require.Len(t, importedVersions, 2)
+	assert.Equal(t, history.OperationCreated, importedVersions[0].Operation)
+	assert.Equal(t, 2, importedVersions[1].Version)
+}
+
+func TestImport_Conflicts(t *testing.T) {
+	ctx := context.Background()
+	source := newSourceStore(t)
+
+	for _, opts := range []Options{{}, {RemapIDs: true}} {
+		archive := exportArchive(t, source)
+
+		_, err := Import(ctx, source, archive, opts)
+		var conflictErr *ConflictError
+		require.True(t, errors.As(err, &conflictErr), "expected a conflict error, got %v", err)
+
+		kinds := make(map[string]struct{})
+		for _, conflict := range conflictErr.Conflicts {
+			kinds[conflict.Kind] = struct{}{}
+		}
+		assert.Contains(t, kinds, "user")
+		assert.Contains(t, kinds, "peer")
+		assert.Contains(t, kinds, "setup key")
+		if opts.RemapIDs {
+			assert.Contains(t, kinds, "tenant", "a tenant can't be managed by two accounts")
+		}
+		if opts.RemapIDs {
+			assert.NotContains(t, kinds, "account")
+		} else {
+			assert.Contains(t, kinds, "account")
+		}
+	}
+}
+
+func TestImport_RemapIDs(t *testing.T) {
+	ctx := context.Background()
+	source := newSourceStore(t)
+	archive := exportArchive(t, source)
+
+	target := newEmptyStore(t)
+	result, err := Import(ctx, target, archive, Options{RemapIDs: true})
+	require.NoError(t, err)
+	assert.NotEqual(t, testAccountID, result.AccountID)
+	assert.Equal(t, result.AccountID, result.IDs[testAccountID])
+
+	expected, err := source.GetAccount(ctx, testAccountID)
+	require.NoError(t, err)
+	imported, err := target.GetAccount(ctx, result.AccountID)
+	require.NoError(t, err)
+
+	for id := range expected.Peers {
+		assert.Contains(t, imported.Peers, result.IDs[id])
+	}
+	for _, policy := range imported.Policies {
+		for _, rule := range policy.Rules {
+			for _, groupID := range append(rule.Sources, rule.Destinations...) {
+				assert.Contains(t, imported.Groups, groupID, "policy rule should reference the remapped group")
+			}
+		}
+	}
+	for _, router := range imported.NetworkRouters {
+		assert.Equal(t, result.IDs[expected.NetworkRouters[0].NetworkID], router.NetworkID)
+	}
+
+	importedZones, err := target.GetAccountZones(ctx, store.LockingStrengthNone, result.AccountID)
+	require.NoError(t, err)
+	require.Len(t, importedZones, 1)
+	for _, groupID := range importedZones[0].DistributionGroups {
+		assert.Contains(t, imported.Groups, groupID)
+	}
+
+	importedObjects, err := target.GetManagedObjects(ctx, store.LockingStrengthNone, result.AccountID)
+	require.NoError(t, err)
+	require.Len(t, importedObjects, 1)
+	assert.Contains(t, imported.Groups, importedObjects[0].ObjectID, "managed objects should reference the remapped objects")
+
+	importedTemplates, err := target.GetTenantTemplates(ctx, store.LockingStrengthNone, result.AccountID)
+	require.NoError(t, err)
+	require.Len(t, importedTemplates, 1)
+	importedTenants, err := target.GetManagedTenants(ctx, store.LockingStrengthNone, result.AccountID)
+	require.NoError(t, err)
+	require.Len(t, importedTenants, 1)
+	assert.Equal(t, importedTemplates[0].ID, importedTenants[0].TemplateID, "tenants should reference the remapped template")
+	require.Len(t, importedTenants[0].AdminGroups, 1)
+	assert.Contains(t, imported.Groups, importedTenants[0].AdminGroups[0], "tenants should reference the remapped admin groups")
+
+	importedRequests, err := target.GetAccessRequests(ctx, store.LockingStrengthNone, result.AccountID)
+	require.NoError(t, err)
+	require.Len(t, importedRequests, 1)
+	assert.Contains(t, imported.Groups, importedRequests[0].TargetID, "access requests should reference the remapped target")
+	importedSettings, err := target.GetAccessRequestSettings(ctx, store.LockingStrengthNone, result.AccountID)
+	require.NoError(t, err)
+	require.Len(t, importedSettings.ApproverGroups, 1)
+	assert.Contains(t, imported.Groups, importedSettings.ApproverGroups[0], "approver groups should be remapped")
+
+	importedSubnets, err := target.GetDiscoveredSubnets(ctx, store.LockingStrengthNone, result.AccountID)
+	require.NoError(t, err)
+	require.Len(t, importedSubnets, 1)
+	assert.Equal(t, result.IDs[expected.NetworkRouters[0].NetworkID], importedSubnets[0].NetworkID, "discovered subnets should reference the remapped network")
+
+	routerID := result.IDs[expected.NetworkRouters[0].ID]
+	importedVersions, err := target.GetObjectVersions(ctx, store.LockingStrengthNone, result.AccountID, history.ObjectNetworkRouter, routerID)
+	require.NoError(t, err)
+	require.Len(t, importedVersions, 2)
+	assert.Contains(t, string(importedVersions[1].After), routerID, "snapshots should reference the remapped objects")
+	assert.NotContains(t, string(importedVersions[1].After), expected.NetworkRouters[0].ID)
+}
+
+func TestImport_DryRun(t *testing.T) {
+	ctx := context.Background()
+	archive := exportArchive(t, newSourceStore(t))
+
+	target := newEmptyStore(t)
+	_, err := Import(ctx, target, archive, Options{DryRun: true})
+	require.NoError(t, err)
+
+	exists, err := target.AccountExists(ctx, store.LockingStrengthNone, testAccountID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestRead_UnsupportedVersion(t *testing.T) {
+	_, err := Read(bytes.NewBufferString(`{"version": 99, "account": {"Id": "account"}}`))
+	assert.Error(t, err)
+}
+
+func keys[K comparable, V any](m map[K]V) []K {
+	result := make([]K, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
@@ -0,0 +1,346 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// Options controls how an archive is imported
+type Options struct {
+	// RemapIDs assigns new IDs to the account and all objects it owns, e.g., to import a copy of an account
+	// into the store it was exported from. User IDs are kept because they are issued by the identity provider.
+	RemapIDs bool
+	// DryRun only checks the archive for conflicts without writing to the store
+	DryRun bool
+}
+
+// Result describes an import
+type Result struct {
+	AccountID string
+	// IDs maps the IDs of the archive to the IDs in the store, set only if the IDs were remapped
+	IDs map[string]string
+}
+
+// Conflict is an object of the archive that can't be imported because it already exists in the store
+type Conflict struct {
+	Kind   string
+	ID     string
+	Reason string
+}
+
+// ConflictError is returned when the archive conflicts with objects of the store
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	conflicts := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		conflicts = append(conflicts, fmt.Sprintf("%s %s: %s", c.Kind, c.ID, c.Reason))
+	}
+	return fmt.Sprintf("archive conflicts with the store: %s", strings.Join(conflicts, "; "))
+}
+
+// Import recreates the account of the archive in the store. The archive is modified in place.
+// Nothing is written if any object of the archive conflicts with the store.
+func Import(ctx context.Context, s store.Store, archive *Archive, opts Options) (*Result, error) {
+	result := &Result{}
+	if opts.RemapIDs {
+		result.IDs = remapIDs(archive)
+	}
+	result.AccountID = archive.Account.Id
+
+	setAccountID(archive)
+
+	conflicts, err := findConflicts(ctx, s, archive)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return nil, &ConflictError{Conflicts: conflicts}
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	err = s.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		if err := transaction.SaveAccount(ctx, archive.Account); err != nil {
+			return fmt.Errorf("save account: %w", err)
+		}
+
+		for _, zone := range archive.Zones {
+			if err := transaction.CreateZone(ctx, zone); err != nil {
+				return fmt.Errorf("create zone %s: %w", zone.Domain, err)
+			}
+		}
+
+		for _, role := range archive.CustomRoles {
+			if err := transaction.SaveCustomRole(ctx, role); err != nil {
+				return fmt.Errorf("save custom role %s: %w", role.Name, err)
+			}
+		}
+
+		if archive.PeerApproval != nil {
+			if err := transaction.SavePeerApprovalConfig(ctx, archive.PeerApproval); err != nil {
+				return fmt.Errorf("save peer approval config: %w", err)
+			}
+		}
+
+		for _, forward := range archive.PortForwards {
+			if err := transaction.SavePortForward(ctx, forward); err != nil {
+				return fmt.Errorf("save port forward %s: %w", forward.Name, err)
+			}
+		}
+
+		if err := transaction.SaveManagedObjects(ctx, archive.ManagedObjects); err != nil {
+			return fmt.Errorf("save managed objects: %w", err)
+		}
+
+		if archive.Tenant != nil {
+			if err := transaction.SaveTenant(ctx, archive.Tenant); err != nil {
+				return fmt.Errorf("save tenant: %w", err)
+			}
+		}
+
+		for _, tenant := range archive.ManagedTenants {
+			if err := transaction.SaveTenant(ctx, tenant); err != nil {
+				return fmt.Errorf("save managed tenant %s: %w", tenant.AccountID, err)
+			}
+		}
+
+		for _, template := range archive.TenantTemplates {
+			if err := transaction.SaveTenantTemplate(ctx, template); err != nil {
+				return fmt.Errorf("save tenant template %s: %w", template.Name, err)
+			}
+		}
+
+		for _, request := range archive.AccessRequests {
+			if err := transaction.SaveAccessRequest(ctx, request); err != nil {
+				return fmt.Errorf("save access request %s: %w", request.ID, err)
+			}
+		}
+
+		if archive.AccessRequestSettings != nil {
+			if err := transaction.SaveAccessRequestSettings(ctx, archive.AccessRequestSettings); err != nil {
+				return fmt.Errorf("save access request settings: %w", err)
+			}
+		}
+
+		for _, subnet := range archive.DiscoveredSubnets {
+			if err := transaction.SaveDiscoveredSubnet(ctx, subnet); err != nil {
+				return fmt.Errorf("save discovered subnet %s: %w", subnet.Prefix, err)
+			}
+		}
+
+		for _, version := range archive.ObjectVersions {
+			// the sequence is assigned by the store
+			version.Seq = 0
+			if err := transaction.SaveObjectVersion(ctx, version); err != nil {
+				return fmt.Errorf("save %s %s version: %w", version.ObjectType, version.ObjectID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// setAccountID sets the account reference of all objects, some of them are not part of the JSON encoding
+func setAccountID(archive *Archive) {
+	account := archive.Account
+	accountID := account.Id
+
+	account.Onboarding.AccountID = accountID
+	for _, key := range account.SetupKeys {
+		key.AccountID = accountID
+	}
+	for _, peer := range account.Peers {
+		peer.AccountID = accountID
+	}
+	for _, user := range account.Users {
+		user.AccountID = accountID
+	}
+	for _, group := range account.Groups {
+		group.AccountID = accountID
+	}
+	for _, policy := range account.Policies {
+		policy.AccountID = accountID
+		for _, rule := range policy.Rules {
+			rule.PolicyID = policy.ID
+		}
+	}
+	for _, r := range account.Routes {
+		r.AccountID = accountID
+	}
+	for _, ns := range account.NameServerGroups {
+		ns.AccountID = accountID
+	}
+	for _, checks := range account.PostureChecks {
+		checks.AccountID = accountID
+	}
+	for _, network := range account.Networks {
+		network.AccountID = accountID
+	}
+	for _, router := range account.NetworkRouters {
+		router.AccountID = accountID
+	}
+	for _, resource := range account.NetworkResources {
+		resource.AccountID = accountID
+	}
+	for _, zone := range archive.Zones {
+		zone.AccountID = accountID
+		for _, record := range zone.Records {
+			record.AccountID = accountID
+			record.ZoneID = zone.ID
+		}
+	}
+	for _, role := range archive.CustomRoles {
+		role.AccountID = accountID
+	}
+	if archive.PeerApproval != nil {
+		archive.PeerApproval.AccountID = accountID
+	}
+	for _, forward := range archive.PortForwards {
+		forward.AccountID = accountID
+	}
+	for _, object := range archive.ManagedObjects {
+		object.AccountID = accountID
+	}
+	if archive.Tenant != nil {
+		archive.Tenant.AccountID = accountID
+	}
+	for _, tenant := range archive.ManagedTenants {
+		tenant.ManagedBy = accountID
+	}
+	for _, template := range archive.TenantTemplates {
+		template.AccountID = accountID
+	}
+	for _, request := range archive.AccessRequests {
+		request.AccountID = accountID
+	}
+	if archive.AccessRequestSettings != nil {
+		archive.AccessRequestSettings.AccountID = accountID
+	}
+	for _, subnet := range archive.DiscoveredSubnets {
+		subnet.AccountID = accountID
+	}
+	for _, version := range archive.ObjectVersions {
+		version.AccountID = accountID
+	}
+}
+
+// findConflicts checks the objects that are unique across accounts. Other objects use random IDs
+// that can only conflict if the same account is imported twice, which is detected by the account ID.
+func findConflicts(ctx context.Context, s store.Store, archive *Archive) ([]Conflict, error) {
+	var conflicts []Conflict
+	account := archive.Account
+
+	exists, err := s.AccountExists(ctx, store.LockingStrengthNone, account.Id)
+	if err != nil {
+		return nil, fmt.Errorf("check account: %w", err)
+	}
+	if exists {
+		conflicts = append(conflicts, Conflict{Kind: "account", ID: account.Id, Reason: "account already exists"})
+	}
+
+	if account.IsDomainPrimaryAccount {
+		primaryID, err := s.GetAccountIDByPrivateDomain(ctx, store.LockingStrengthNone, account.Domain)
+		if err = ignoreNotFound(err); err != nil {
+			return nil, fmt.Errorf("check domain: %w", err)
+		}
+		if primaryID != "" && primaryID != account.Id {
+			conflicts = append(conflicts, Conflict{Kind: "domain", ID: account.Domain, Reason: fmt.Sprintf("account %s is the primary account of the domain", primaryID)})
+		}
+	}
+
+	for userID, user := range account.Users {
+		existing, err := s.GetUserByUserID(ctx, store.LockingStrengthNone, userID)
+		if err = ignoreNotFound(err); err != nil {
+			return nil, fmt.Errorf("check user %s: %w", userID, err)
+		}
+		if existing != nil {
+			conflicts = append(conflicts, Conflict{Kind: "user", ID: userID, Reason: fmt.Sprintf("user belongs to account %s", existing.AccountID)})
+		}
+
+		for _, pat := range user.PATs {
+			existingPAT, err := s.GetPATByHashedToken(ctx, store.LockingStrengthNone, pat.HashedToken)
+			if err = ignoreNotFound(err); err != nil {
+				return nil, fmt.Errorf("check personal access token %s: %w", pat.ID, err)
+			}
+			if existingPAT != nil {
+				conflicts = append(conflicts, Conflict{Kind: "personal access token", ID: pat.ID, Reason: "token is already in use"})
+			}
+		}
+	}
+
+	for peerID, peer := range account.Peers {
+		accountID, err := s.GetAccountIDByPeerPubKey(ctx, peer.Key)
+		if err = ignoreNotFound(err); err != nil {
+			return nil, fmt.Errorf("check peer %s: %w", peerID, err)
+		}
+		if accountID != "" {
+			conflicts = append(conflicts, Conflict{Kind: "peer", ID: peerID, Reason: fmt.Sprintf("peer key is registered in account %s", accountID)})
+			continue
+		}
+
+		accountID, err = s.GetAccountIDByPeerID(ctx, store.LockingStrengthNone, peerID)
+		if err = ignoreNotFound(err); err != nil {
+			return nil, fmt.Errorf("check peer %s: %w", peerID, err)
+		}
+		if accountID != "" {
+			conflicts = append(conflicts, Conflict{Kind: "peer", ID: peerID, Reason: fmt.Sprintf("peer ID is used in account %s", accountID)})
+		}
+	}
+
+	for _, key := range account.SetupKeys {
+		existing, err := s.GetSetupKeyBySecret(ctx, store.LockingStrengthNone, key.Key)
+		// the store reports unknown setup keys as a failed precondition
+		if sErr, ok := status.FromError(err); ok && sErr != nil && sErr.Type() == status.PreconditionFailed {
+			err = nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("check setup key %s: %w", key.Id, err)
+		}
+		if existing != nil {
+			conflicts = append(conflicts, Conflict{Kind: "setup key", ID: key.Id, Reason: fmt.Sprintf("setup key is used in account %s", existing.AccountID)})
+		}
+	}
+
+	for _, tenant := range archive.ManagedTenants {
+		existing, err := s.GetTenant(ctx, store.LockingStrengthNone, tenant.AccountID)
+		if err = ignoreNotFound(err); err != nil {
+			return nil, fmt.Errorf("check tenant %s: %w", tenant.AccountID, err)
+		}
+		if existing != nil && existing.ManagedBy != account.Id {
+			conflicts = append(conflicts, Conflict{Kind: "tenant", ID: tenant.AccountID, Reason: fmt.Sprintf("tenant is managed by account %s", existing.ManagedBy)})
+		}
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Kind != conflicts[j].Kind {
+			return conflicts[i].Kind < conflicts[j].Kind
+		}
+		return conflicts[i].ID < conflicts[j].ID
+	})
+
+	return conflicts, nil
+}
+
+func ignoreNotFound(err error) error {
+	if err == nil {
+		return nil
+	}
+	if sErr, ok := status.FromError(err); ok && sErr.Type() == status.NotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,322 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/rs/xid"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/route"
+)
+
+// idMap assigns new IDs to the objects of an archive and translates references to them
+type idMap map[string]string
+
+// assign returns a new ID for an object of the archive
+func (m idMap) assign(id string) string {
+	if id == "" {
+		return ""
+	}
+	if newID, ok := m[id]; ok {
+		return newID
+	}
+	newID := xid.New().String()
+	m[id] = newID
+	return newID
+}
+
+// ref translates a reference. References to objects outside of the archive are kept.
+func (m idMap) ref(id string) string {
+	if newID, ok := m[id]; ok {
+		return newID
+	}
+	return id
+}
+
+// refsJSON translates the IDs in the string values of a JSON document, e.g., an object snapshot. A document that
+// can't be parsed is returned unchanged.
+func (m idMap) refsJSON(data []byte) []byte {
+	if len(data) == 0 {
+		return data
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return data
+	}
+
+	translated, err := json.Marshal(m.refsValue(value))
+	if err != nil {
+		return data
+	}
+	return translated
+}
+
+func (m idMap) refsValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return m.ref(v)
+	case []any:
+		for i := range v {
+			v[i] = m.refsValue(v[i])
+		}
+	case map[string]any:
+		for key, field := range v {
+			v[key] = m.refsValue(field)
+		}
+	}
+	return value
+}
+
+func (m idMap) refs(ids []string) []string {
+	if ids == nil {
+		return nil
+	}
+	translated := make([]string, 0, len(ids))
+	for _, id := range ids {
+		translated = append(translated, m.ref(id))
+	}
+	return translated
+}
+
+// remapIDs replaces the IDs of the account and of all objects it owns with new ones and returns the mapping.
+// All IDs are assigned first so that references can be translated regardless of the order of the objects.
+func remapIDs(archive *Archive) map[string]string {
+	ids := idMap{}
+	account := archive.Account
+
+	account.Id = ids.assign(account.Id)
+	assignIDs(ids, archive)
+
+	// setup keys are indexed by their hashed secret that can't change
+	for _, key := range account.SetupKeys {
+		key.Id = ids.ref(key.Id)
+		key.AutoGroups = ids.refs(key.AutoGroups)
+		if key.Constraints != nil {
+			key.Constraints.PostureChecks = ids.refs(key.Constraints.PostureChecks)
+		}
+	}
+
+	peers := make(map[string]*nbpeer.Peer, len(account.Peers))
+	for id, peer := range account.Peers {
+		peer.ID = ids.ref(id)
+		peers[peer.ID] = peer
+	}
+	account.Peers = peers
+
+	for _, user := range account.Users {
+		user.AutoGroups = ids.refs(user.AutoGroups)
+		user.CustomRoleID = ids.ref(user.CustomRoleID)
+
+		pats := make(map[string]*types.PersonalAccessToken, len(user.PATs))
+		for id, pat := range user.PATs {
+			pat.ID = ids.ref(id)
+			if pat.Scope != nil {
+				pat.Scope.PeerGroups = ids.refs(pat.Scope.PeerGroups)
+			}
+			pats[pat.ID] = pat
+		}
+		user.PATs = pats
+	}
+
+	groups := make(map[string]*types.Group, len(account.Groups))
+	for id, group := range account.Groups {
+		group.ID = ids.ref(id)
+		group.Peers = ids.refs(group.Peers)
+		for i := range group.Resources {
+			group.Resources[i].ID = ids.ref(group.Resources[i].ID)
+		}
+		groups[group.ID] = group
+	}
+	account.Groups = groups
+
+	for _, policy := range account.Policies {
+		policy.ID = ids.ref(policy.ID)
+		policy.SourcePostureChecks = ids.refs(policy.SourcePostureChecks)
+		for _, rule := range policy.Rules {
+			rule.ID = ids.ref(rule.ID)
+			rule.Sources = ids.refs(rule.Sources)
+			rule.Destinations = ids.refs(rule.Destinations)
+			rule.SourceResource.ID = ids.ref(rule.SourceResource.ID)
+			rule.DestinationResource.ID = ids.ref(rule.DestinationResource.ID)
+			if rule.AuthorizedGroups != nil {
+				authorizedGroups := make(map[string][]string, len(rule.AuthorizedGroups))
+				for groupID, users := range rule.AuthorizedGroups {
+					authorizedGroups[ids.ref(groupID)] = users
+				}
+				rule.AuthorizedGroups = authorizedGroups
+			}
+		}
+	}
+
+	routes := make(map[route.ID]*route.Route, len(account.Routes))
+	for id, r := range account.Routes {
+		r.ID = route.ID(ids.ref(string(id)))
+		r.Peer = ids.ref(r.Peer)
+		r.PeerID = ids.ref(r.PeerID)
+		r.PeerGroups = ids.refs(r.PeerGroups)
+		r.Groups = ids.refs(r.Groups)
+		r.AccessControlGroups = ids.refs(r.AccessControlGroups)
+		routes[r.ID] = r
+	}
+	account.Routes = routes
+
+	nsGroups := make(map[string]*nbdns.NameServerGroup, len(account.NameServerGroups))
+	for id, ns := range account.NameServerGroups {
+		ns.ID = ids.ref(id)
+		ns.Groups = ids.refs(ns.Groups)
+		nsGroups[ns.ID] = ns
+	}
+	account.NameServerGroups = nsGroups
+
+	account.DNSSettings.DisabledManagementGroups = ids.refs(account.DNSSettings.DisabledManagementGroups)
+
+	for _, checks := range account.PostureChecks {
+		checks.ID = ids.ref(checks.ID)
+	}
+
+	for _, network := range account.Networks {
+		network.ID = ids.ref(network.ID)
+	}
+	for _, router := range account.NetworkRouters {
+		router.ID = ids.ref(router.ID)
+		router.NetworkID = ids.ref(router.NetworkID)
+		router.Peer = ids.ref(router.Peer)
+		router.PeerGroups = ids.refs(router.PeerGroups)
+	}
+	for _, resource := range account.NetworkResources {
+		resource.ID = ids.ref(resource.ID)
+		resource.NetworkID = ids.ref(resource.NetworkID)
+	}
+
+	for _, zone := range archive.Zones {
+		zone.ID = ids.ref(zone.ID)
+		zone.DistributionGroups = ids.refs(zone.DistributionGroups)
+		for _, record := range zone.Records {
+			record.ID = ids.ref(record.ID)
+		}
+	}
+
+	for _, role := range archive.CustomRoles {
+		role.ID = ids.ref(role.ID)
+	}
+
+	if archive.PeerApproval != nil {
+		archive.PeerApproval.RequiredGroups = ids.refs(archive.PeerApproval.RequiredGroups)
+	}
+
+	for _, forward := range archive.PortForwards {
+		forward.ID = ids.ref(forward.ID)
+		forward.PeerID = ids.ref(forward.PeerID)
+		forward.TargetPeerID = ids.ref(forward.TargetPeerID)
+		forward.TargetResourceID = ids.ref(forward.TargetResourceID)
+	}
+
+	for _, object := range archive.ManagedObjects {
+		object.ObjectID = ids.ref(object.ObjectID)
+	}
+
+	// the tenant accounts and the managing account are not part of the archive and keep their IDs
+	for _, tenant := range archive.ManagedTenants {
+		tenant.TemplateID = ids.ref(tenant.TemplateID)
+		tenant.AdminGroups = ids.refs(tenant.AdminGroups)
+	}
+	for _, template := range archive.TenantTemplates {
+		template.ID = ids.ref(template.ID)
+	}
+
+	for _, request := range archive.AccessRequests {
+		request.ID = ids.ref(request.ID)
+		request.TargetID = ids.ref(request.TargetID)
+		request.GroupID = ids.ref(request.GroupID)
+		request.PolicyID = ids.ref(request.PolicyID)
+	}
+	if archive.AccessRequestSettings != nil {
+		archive.AccessRequestSettings.ApproverGroups = ids.refs(archive.AccessRequestSettings.ApproverGroups)
+	}
+
+	for _, subnet := range archive.DiscoveredSubnets {
+		subnet.ID = ids.ref(subnet.ID)
+		subnet.NetworkID = ids.ref(subnet.NetworkID)
+		subnet.PeerID = ids.ref(subnet.PeerID)
+		subnet.ResourceID = ids.ref(subnet.ResourceID)
+	}
+
+	for _, version := range archive.ObjectVersions {
+		version.ObjectID = ids.ref(version.ObjectID)
+		version.Before = ids.refsJSON(version.Before)
+		version.After = ids.refsJSON(version.After)
+	}
+
+	return ids
+}
+
+// assignIDs assigns a new ID to every object of the archive except users, whose IDs are issued by the identity provider
+func assignIDs(ids idMap, archive *Archive) {
+	account := archive.Account
+
+	for _, key := range account.SetupKeys {
+		ids.assign(key.Id)
+	}
+	for id := range account.Peers {
+		ids.assign(id)
+	}
+	for _, user := range account.Users {
+		for id := range user.PATs {
+			ids.assign(id)
+		}
+	}
+	for id := range account.Groups {
+		ids.assign(id)
+	}
+	for _, policy := range account.Policies {
+		ids.assign(policy.ID)
+		for _, rule := range policy.Rules {
+			ids.assign(rule.ID)
+		}
+	}
+	for id := range account.Routes {
+		ids.assign(string(id))
+	}
+	for id := range account.NameServerGroups {
+		ids.assign(id)
+	}
+	for _, checks := range account.PostureChecks {
+		ids.assign(checks.ID)
+	}
+	for _, network := range account.Networks {
+		ids.assign(network.ID)
+	}
+	for _, router := range account.NetworkRouters {
+		ids.assign(router.ID)
+	}
+	for _, resource := range account.NetworkResources {
+		ids.assign(resource.ID)
+	}
+	for _, zone := range archive.Zones {
+		ids.assign(zone.ID)
+		for _, record := range zone.Records {
+			ids.assign(record.ID)
+		}
+	}
+	for _, role := range archive.CustomRoles {
+		ids.assign(role.ID)
+	}
+	for _, forward := range archive.PortForwards {
+		ids.assign(forward.ID)
+	}
+	for _, template := range archive.TenantTemplates {
+		ids.assign(template.ID)
+	}
+	for _, request := range archive.AccessRequests {
+		ids.assign(request.ID)
+	}
+	for _, subnet := range archive.DiscoveredSubnets {
+		ids.assign(subnet.ID)
+	}
+}
@@ -27,12 +27,21 @@ import (
 	"gorm.io/gorm/logger"
 
 	nbdns "github.com/netbirdio/netbird/dns"
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	"github.com/netbirdio/netbird/management/internals/modules/declarative"
+	"github.com/netbirdio/netbird/management/internals/modules/history"
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	"github.com/netbirdio/netbird/management/internals/modules/portforwards"
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	"github.com/netbirdio/netbird/management/internals/modules/subnetdiscovery"
+	"github.com/netbirdio/netbird/management/internals/modules/tenants"
 	"github.com/netbirdio/netbird/management/internals/modules/zones"
 	"github.com/netbirdio/netbird/management/internals/modules/zones/records"
 	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
 	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
 	networkTypes "github.com/netbirdio/netbird/management/server/networks/types"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions/roles"
 	"github.com/netbirdio/netbird/management/server/posture"
 	"github.com/netbirdio/netbird/management/server/telemetry"
 	"github.com/netbirdio/netbird/management/server/types"
@@ -126,7 +135,11 @@ func NewSqlStore(ctx context.Context, db *gorm.DB, storeEngine types.Engine, met
 		&types.Account{}, &types.Policy{}, &types.PolicyRule{}, &route.Route{}, &nbdns.NameServerGroup{},
 		&installation{}, &types.ExtraSettings{}, &posture.Checks{}, &nbpeer.NetworkAddress{},
 		&networkTypes.Network{}, &routerTypes.NetworkRouter{}, &resourceTypes.NetworkResource{}, &types.AccountOnboarding{},
-		&types.Job{}, &zones.Zone{}, &records.Record{},
+		&types.Job{}, &zones.Zone{}, &records.Record{}, &scim.Token{}, &roles.CustomRole{},
+		&peerapproval.Config{}, &declarative.ManagedObject{}, &tenants.Tenant{}, &tenants.Template{},
+		&accessrequests.AccessRequest{}, &accessrequests.Settings{}, &subnetdiscovery.DiscoveredSubnet{},
+		&portforwards.PortForward{},
+		&history.Version{},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("auto migratePreAuto: %w", err)
@@ -893,6 +906,22 @@ func (s *SqlStore) GetAllAccounts(ctx context.Context) (all []*types.Account) {
 	return all
 }
 
+func (s *SqlStore) GetAllAccountIDs(ctx context.Context, lockStrength LockingStrength) ([]string, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var accountIDs []string
+	result := tx.Model(&types.Account{}).Order("id").Pluck("id", &accountIDs)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get account IDs from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get account IDs from store")
+	}
+
+	return accountIDs, nil
+}
+
 func (s *SqlStore) GetAccountMeta(ctx context.Context, lockStrength LockingStrength, accountID string) (*types.AccountMeta, error) {
 	tx := s.db
 	if lockStrength != LockingStrengthNone {
@@ -1353,6 +1382,7 @@ func (s *SqlStore) getAccount(ctx context.Context, accountID string) (*types.Acc
 			settings_jwt_groups_enabled, settings_jwt_groups_claim_name, settings_jwt_allow_groups,
 			settings_routing_peer_dns_resolution_enabled, settings_dns_domain, settings_network_range,
 			settings_lazy_connection_enabled,
+			settings_subnet_discovery_enabled, settings_subnet_discovery_allowlist,
 			-- Embedded ExtraSettings
 			settings_extra_peer_approval_enabled, settings_extra_user_approval_required,
 			settings_extra_integrated_validator, settings_extra_integrated_validator_groups
@@ -1372,6 +1402,8 @@ func (s *SqlStore) getAccount(ctx context.Context, accountID string) (*types.Acc
 		sDNSDomain                       sql.NullString
 		sNetworkRange                    sql.NullString
 		sLazyConnectionEnabled           sql.NullBool
+		sSubnetDiscoveryEnabled          sql.NullBool
+		sSubnetDiscoveryAllowlist        sql.NullString
 		sExtraPeerApprovalEnabled        sql.NullBool
 		sExtraUserApprovalRequired       sql.NullBool
 		sExtraIntegratedValidator        sql.NullString
@@ -1393,6 +1425,7 @@ func (s *SqlStore) getAccount(ctx context.Context, accountID string) (*types.Acc
 		&sJWTGroupsEnabled, &sJWTGroupsClaimName, &sJWTAllowGroups,
 		&sRoutingPeerDNSResolutionEnabled, &sDNSDomain, &sNetworkRange,
 		&sLazyConnectionEnabled,
+		&sSubnetDiscoveryEnabled, &sSubnetDiscoveryAllowlist,
 		&sExtraPeerApprovalEnabled, &sExtraUserApprovalRequired,
 		&sExtraIntegratedValidator, &sExtraIntegratedValidatorGroups,
 	)
@@ -1455,6 +1488,12 @@ func (s *SqlStore) getAccount(ctx context.Context, accountID string) (*types.Acc
 	if sLazyConnectionEnabled.Valid {
 		account.Settings.LazyConnectionEnabled = sLazyConnectionEnabled.Bool
 	}
+	if sSubnetDiscoveryEnabled.Valid {
+		account.Settings.SubnetDiscoveryEnabled = sSubnetDiscoveryEnabled.Bool
+	}
+	if sSubnetDiscoveryAllowlist.Valid {
+		_ = json.Unmarshal([]byte(sSubnetDiscoveryAllowlist.String), &account.Settings.SubnetDiscoveryAllowlist)
+	}
 	if sJWTAllowGroups.Valid {
 		_ = json.Unmarshal([]byte(sJWTAllowGroups.String), &account.Settings.JWTAllowGroups)
 	}
@@ -1546,7 +1585,7 @@ func (s *SqlStore) getPeers(ctx context.Context, accountID string) ([]nbpeer.Pee
 	inactivity_expiration_enabled, last_login, created_at, ephemeral, extra_dns_labels, allow_extra_dns_labels, meta_hostname, 
 	meta_go_os, meta_kernel, meta_core, meta_platform, meta_os, meta_os_version, meta_wt_version, meta_ui_version, 
 	meta_kernel_version, meta_network_addresses, meta_system_serial_number, meta_system_product_name, meta_system_manufacturer,
-	meta_environment, meta_flags, meta_files, peer_status_last_seen, peer_status_connected, peer_status_login_expired, 
+	meta_environment, meta_flags, meta_files, meta_unhealthy_routes, peer_status_last_seen, peer_status_connected, peer_status_login_expired, 
 	peer_status_requires_approval, location_connection_ip, location_country_code, location_city_name, 
 	location_geo_name_id FROM peers WHERE account_id = $1`
 	rows, err := s.pool.Query(ctx, query, accountID)
@@ -1562,7 +1601,7 @@ func (s *SqlStore) getPeers(ctx context.Context, accountID string) ([]nbpeer.Pee
 			sshEnabled, loginExpirationEnabled, inactivityExpirationEnabled, ephemeral, allowExtraDNSLabels sql.NullBool
 			peerStatusLastSeen                                                                              sql.NullTime
 			peerStatusConnected, peerStatusLoginExpired, peerStatusRequiresApproval                         sql.NullBool
-			ip, extraDNS, netAddr, env, flags, files, connIP                                                []byte
+			ip, extraDNS, netAddr, env, flags, files, unhealthyRoutes, connIP                               []byte
 			metaHostname, metaGoOS, metaKernel, metaCore, metaPlatform                                      sql.NullString
 			metaOS, metaOSVersion, metaWtVersion, metaUIVersion, metaKernelVersion                          sql.NullString
 			metaSystemSerialNumber, metaSystemProductName, metaSystemManufacturer                           sql.NullString
@@ -1575,7 +1614,7 @@ func (s *SqlStore) getPeers(ctx context.Context, accountID string) ([]nbpeer.Pee
 			&allowExtraDNSLabels, &metaHostname, &metaGoOS, &metaKernel, &metaCore, &metaPlatform,
 			&metaOS, &metaOSVersion, &metaWtVersion, &metaUIVersion, &metaKernelVersion, &netAddr,
 			&metaSystemSerialNumber, &metaSystemProductName, &metaSystemManufacturer, &env, &flags, &files,
-			&peerStatusLastSeen, &peerStatusConnected, &peerStatusLoginExpired, &peerStatusRequiresApproval, &connIP,
+			&unhealthyRoutes, &peerStatusLastSeen, &peerStatusConnected, &peerStatusLoginExpired, &peerStatusRequiresApproval, &connIP,
 			&locationCountryCode, &locationCityName, &locationGeoNameID)
 
 		if err == nil {
@@ -1678,6 +1717,9 @@ func (s *SqlStore) getPeers(ctx context.Context, accountID string) ([]nbpeer.Pee
 			if files != nil {
 				_ = json.Unmarshal(files, &p.Meta.Files)
 			}
+			if unhealthyRoutes != nil {
+				_ = json.Unmarshal(unhealthyRoutes, &p.Meta.UnhealthyRoutes)
+			}
 			if connIP != nil {
 				_ = json.Unmarshal(connIP, &p.Location.ConnectionIP)
 			}
@@ -1798,17 +1840,17 @@ func (s *SqlStore) getPolicies(ctx context.Context, accountID string) ([]*types.
 }
 
 func (s *SqlStore) getRoutes(ctx context.Context, accountID string) ([]route.Route, error) {
-	const query = `SELECT id, account_id, network, domains, keep_route, net_id, description, peer, peer_groups, network_type, masquerade, metric, enabled, groups, access_control_groups, skip_auto_apply FROM routes WHERE account_id = $1`
+	const query = `SELECT id, account_id, network, domains, keep_route, net_id, description, peer, peer_groups, network_type, masquerade, metric, enabled, groups, access_control_groups, skip_auto_apply, health_check, net_map, load_balance, bgp, multicast FROM routes WHERE account_id = $1`
 	rows, err := s.pool.Query(ctx, query, accountID)
 	if err != nil {
 		return nil, err
 	}
 	routes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (route.Route, error) {
 		var r route.Route
-		var network, domains, peerGroups, groups, accessGroups []byte
-		var keepRoute, masquerade, enabled, skipAutoApply sql.NullBool
+		var network, domains, peerGroups, groups, accessGroups, healthCheck, netMap, bgp, multicast []byte
+		var keepRoute, masquerade, enabled, skipAutoApply, loadBalance sql.NullBool
 		var metric sql.NullInt64
-		err := row.Scan(&r.ID, &r.AccountID, &network, &domains, &keepRoute, &r.NetID, &r.Description, &r.Peer, &peerGroups, &r.NetworkType, &masquerade, &metric, &enabled, &groups, &accessGroups, &skipAutoApply)
+		err := row.Scan(&r.ID, &r.AccountID, &network, &domains, &keepRoute, &r.NetID, &r.Description, &r.Peer, &peerGroups, &r.NetworkType, &masquerade, &metric, &enabled, &groups, &accessGroups, &skipAutoApply, &healthCheck, &netMap, &loadBalance, &bgp, &multicast)
 		if err == nil {
 			if keepRoute.Valid {
 				r.KeepRoute = keepRoute.Bool
@@ -1822,6 +1864,9 @@ func (s *SqlStore) getRoutes(ctx context.Context, accountID string) ([]route.Rou
 			if skipAutoApply.Valid {
 				r.SkipAutoApply = skipAutoApply.Bool
 			}
+			if loadBalance.Valid {
+				r.LoadBalance = loadBalance.Bool
+			}
 			if metric.Valid {
 				r.Metric = int(metric.Int64)
 			}
@@ -1840,6 +1885,18 @@ func (s *SqlStore) getRoutes(ctx context.Context, accountID string) ([]route.Rou
 			if accessGroups != nil {
 				_ = json.Unmarshal(accessGroups, &r.AccessControlGroups)
 			}
+			if healthCheck != nil {
+				_ = json.Unmarshal(healthCheck, &r.HealthCheck)
+			}
+			if netMap != nil {
+				_ = json.Unmarshal(netMap, &r.NetMap)
+			}
+			if bgp != nil {
+				_ = json.Unmarshal(bgp, &r.BGP)
+			}
+			if multicast != nil {
+				_ = json.Unmarshal(multicast, &r.Multicast)
+			}
 		}
 		return r, err
 	})
@@ -1933,17 +1990,17 @@ func (s *SqlStore) getNetworks(ctx context.Context, accountID string) ([]*networ
 }
 
 func (s *SqlStore) getNetworkRouters(ctx context.Context, accountID string) ([]*routerTypes.NetworkRouter, error) {
-	const query = `SELECT id, network_id, account_id, peer, peer_groups, masquerade, metric, enabled FROM network_routers WHERE account_id = $1`
+	const query = `SELECT id, network_id, account_id, peer, peer_groups, masquerade, metric, enabled, health_check, load_balance, bgp, multicast FROM network_routers WHERE account_id = $1`
 	rows, err := s.pool.Query(ctx, query, accountID)
 	if err != nil {
 		return nil, err
 	}
 	routers, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (routerTypes.NetworkRouter, error) {
 		var r routerTypes.NetworkRouter
-		var peerGroups []byte
-		var masquerade, enabled sql.NullBool
+		var peerGroups, healthCheck, bgp, multicast []byte
+		var masquerade, enabled, loadBalance sql.NullBool
 		var metric sql.NullInt64
-		err := row.Scan(&r.ID, &r.NetworkID, &r.AccountID, &r.Peer, &peerGroups, &masquerade, &metric, &enabled)
+		err := row.Scan(&r.ID, &r.NetworkID, &r.AccountID, &r.Peer, &peerGroups, &masquerade, &metric, &enabled, &healthCheck, &loadBalance, &bgp, &multicast)
 		if err == nil {
 			if masquerade.Valid {
 				r.Masquerade = masquerade.Bool
@@ -1951,12 +2008,24 @@ func (s *SqlStore) getNetworkRouters(ctx context.Context, accountID string) ([]*
 			if enabled.Valid {
 				r.Enabled = enabled.Bool
 			}
+			if loadBalance.Valid {
+				r.LoadBalance = loadBalance.Bool
+			}
 			if metric.Valid {
 				r.Metric = int(metric.Int64)
 			}
 			if peerGroups != nil {
 				_ = json.Unmarshal(peerGroups, &r.PeerGroups)
 			}
+			if healthCheck != nil {
+				_ = json.Unmarshal(healthCheck, &r.HealthCheck)
+			}
+			if bgp != nil {
+				_ = json.Unmarshal(bgp, &r.BGP)
+			}
+			if multicast != nil {
+				_ = json.Unmarshal(multicast, &r.Multicast)
+			}
 		}
 		return r, err
 	})
@@ -1971,16 +2040,16 @@ func (s *SqlStore) getNetworkRouters(ctx context.Context, accountID string) ([]*
 }
 
 func (s *SqlStore) getNetworkResources(ctx context.Context, accountID string) ([]*resourceTypes.NetworkResource, error) {
-	const query = `SELECT id, network_id, account_id, name, description, type, domain, prefix, enabled FROM network_resources WHERE account_id = $1`
+	const query = `SELECT id, network_id, account_id, name, description, type, domain, prefix, enabled, net_map FROM network_resources WHERE account_id = $1`
 	rows, err := s.pool.Query(ctx, query, accountID)
 	if err != nil {
 		return nil, err
 	}
 	resources, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (resourceTypes.NetworkResource, error) {
 		var r resourceTypes.NetworkResource
-		var prefix []byte
+		var prefix, netMap []byte
 		var enabled sql.NullBool
-		err := row.Scan(&r.ID, &r.NetworkID, &r.AccountID, &r.Name, &r.Description, &r.Type, &r.Domain, &prefix, &enabled)
+		err := row.Scan(&r.ID, &r.NetworkID, &r.AccountID, &r.Name, &r.Description, &r.Type, &r.Domain, &prefix, &enabled, &netMap)
 		if err == nil {
 			if enabled.Valid {
 				r.Enabled = enabled.Bool
@@ -1988,6 +2057,9 @@ func (s *SqlStore) getNetworkResources(ctx context.Context, accountID string) ([
 			if prefix != nil {
 				_ = json.Unmarshal(prefix, &r.Prefix)
 			}
+			if netMap != nil {
+				_ = json.Unmarshal(netMap, &r.NetMap)
+			}
 		}
 		return r, err
 	})
@@ -4475,3 +4547,623 @@ func (s *SqlStore) GetPeerIDByKey(ctx context.Context, lockStrength LockingStren
 
 	return peerID, nil
 }
+
+func (s *SqlStore) GetAccountSCIMToken(ctx context.Context, lockStrength LockingStrength, accountID string) (*scim.Token, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var token scim.Token
+	result := tx.Take(&token, accountIDCondition, accountID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewSCIMTokenNotFoundError()
+		}
+		log.WithContext(ctx).Errorf("failed to get SCIM token from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get SCIM token from store")
+	}
+
+	return &token, nil
+}
+
+func (s *SqlStore) GetSCIMTokenByHashedToken(ctx context.Context, lockStrength LockingStrength, hashedToken string) (*scim.Token, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var token scim.Token
+	result := tx.Take(&token, "hashed_token = ?", hashedToken)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewSCIMTokenNotFoundError()
+		}
+		log.WithContext(ctx).Errorf("failed to get SCIM token by hash from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get SCIM token by hash from store")
+	}
+
+	return &token, nil
+}
+
+func (s *SqlStore) SaveSCIMToken(ctx context.Context, token *scim.Token) error {
+	result := s.db.Save(token)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save SCIM token to store: %s", result.Error)
+		return status.Errorf(status.Internal, "failed to save SCIM token to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) DeleteSCIMToken(ctx context.Context, accountID string) error {
+	result := s.db.Delete(&scim.Token{}, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to delete SCIM token from store: %s", result.Error)
+		return status.Errorf(status.Internal, "failed to delete SCIM token from store")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewSCIMTokenNotFoundError()
+	}
+
+	return nil
+}
+
+func (s *SqlStore) MarkSCIMTokenUsed(ctx context.Context, tokenID string) error {
+	result := s.db.Model(&scim.Token{}).
+		Where(idQueryCondition, tokenID).
+		Update("last_used", time.Now().UTC())
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to mark SCIM token as used: %s", result.Error)
+		return status.Errorf(status.Internal, "failed to mark SCIM token as used")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewSCIMTokenNotFoundError()
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetAccountCustomRoles(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*roles.CustomRole, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var customRoles []*roles.CustomRole
+	result := tx.Find(&customRoles, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get custom roles from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get custom roles from store")
+	}
+
+	return customRoles, nil
+}
+
+func (s *SqlStore) GetCustomRoleByID(ctx context.Context, lockStrength LockingStrength, accountID, roleID string) (*roles.CustomRole, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var customRole roles.CustomRole
+	result := tx.Take(&customRole, accountAndIDQueryCondition, accountID, roleID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewCustomRoleNotFoundError(roleID)
+		}
+		log.WithContext(ctx).Errorf("failed to get custom role from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get custom role from store")
+	}
+
+	return &customRole, nil
+}
+
+func (s *SqlStore) SaveCustomRole(ctx context.Context, role *roles.CustomRole) error {
+	result := s.db.Save(role)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save custom role to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to save custom role to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) DeleteCustomRole(ctx context.Context, accountID, roleID string) error {
+	result := s.db.Delete(&roles.CustomRole{}, accountAndIDQueryCondition, accountID, roleID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to delete custom role from store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to delete custom role from store")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewCustomRoleNotFoundError(roleID)
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetPeerApprovalConfig(ctx context.Context, lockStrength LockingStrength, accountID string) (*peerapproval.Config, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var config peerapproval.Config
+	result := tx.Take(&config, accountIDCondition, accountID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewPeerApprovalConfigNotFoundError()
+		}
+		log.WithContext(ctx).Errorf("failed to get peer approval config from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get peer approval config from store")
+	}
+
+	return &config, nil
+}
+
+func (s *SqlStore) SavePeerApprovalConfig(ctx context.Context, config *peerapproval.Config) error {
+	result := s.db.Save(config)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save peer approval config to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to save peer approval config to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetManagedObjects(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*declarative.ManagedObject, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var objects []*declarative.ManagedObject
+	result := tx.Find(&objects, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get managed objects from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get managed objects from store")
+	}
+
+	return objects, nil
+}
+
+func (s *SqlStore) SaveManagedObjects(ctx context.Context, objects []*declarative.ManagedObject) error {
+	if len(objects) == 0 {
+		return nil
+	}
+
+	result := s.db.Save(objects)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save managed objects to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to save managed objects to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) DeleteManagedObject(ctx context.Context, accountID string, kind declarative.Kind, objectID string) error {
+	result := s.db.Delete(&declarative.ManagedObject{}, "account_id = ? AND kind = ? AND object_id = ?", accountID, kind, objectID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to delete managed object from store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to delete managed object from store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetTenant(ctx context.Context, lockStrength LockingStrength, accountID string) (*tenants.Tenant, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var tenant tenants.Tenant
+	result := tx.Take(&tenant, accountIDCondition, accountID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewTenantNotFoundError(accountID)
+		}
+		log.WithContext(ctx).Errorf("failed to get tenant from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get tenant from store")
+	}
+
+	return &tenant, nil
+}
+
+func (s *SqlStore) GetManagedTenants(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*tenants.Tenant, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var managed []*tenants.Tenant
+	result := tx.Order("name").Find(&managed, "managed_by = ?", accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get tenants from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get tenants from store")
+	}
+
+	return managed, nil
+}
+
+func (s *SqlStore) SaveTenant(ctx context.Context, tenant *tenants.Tenant) error {
+	result := s.db.Save(tenant)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save tenant to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to save tenant to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) DeleteTenant(ctx context.Context, accountID string) error {
+	result := s.db.Delete(&tenants.Tenant{}, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to delete tenant from store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to delete tenant from store")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewTenantNotFoundError(accountID)
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetTenantTemplates(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*tenants.Template, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var templates []*tenants.Template
+	result := tx.Find(&templates, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get tenant templates from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get tenant templates from store")
+	}
+
+	return templates, nil
+}
+
+func (s *SqlStore) GetTenantTemplateByID(ctx context.Context, lockStrength LockingStrength, accountID, templateID string) (*tenants.Template, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var template tenants.Template
+	result := tx.Take(&template, accountAndIDQueryCondition, accountID, templateID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewTenantTemplateNotFoundError(templateID)
+		}
+		log.WithContext(ctx).Errorf("failed to get tenant template from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get tenant template from store")
+	}
+
+	return &template, nil
+}
+
+func (s *SqlStore) SaveTenantTemplate(ctx context.Context, template *tenants.Template) error {
+	result := s.db.Save(template)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save tenant template to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to save tenant template to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) DeleteTenantTemplate(ctx context.Context, accountID, templateID string) error {
+	result := s.db.Delete(&tenants.Template{}, accountAndIDQueryCondition, accountID, templateID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to delete tenant template from store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to delete tenant template from store")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewTenantTemplateNotFoundError(templateID)
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetAccessRequests(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*accessrequests.AccessRequest, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var requests []*accessrequests.AccessRequest
+	result := tx.Order("created_at desc").Find(&requests, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get access requests from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get access requests from store")
+	}
+
+	return requests, nil
+}
+
+func (s *SqlStore) GetAccessRequestByID(ctx context.Context, lockStrength LockingStrength, accountID, requestID string) (*accessrequests.AccessRequest, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var request accessrequests.AccessRequest
+	result := tx.Take(&request, accountAndIDQueryCondition, accountID, requestID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewAccessRequestNotFoundError(requestID)
+		}
+		log.WithContext(ctx).Errorf("failed to get access request from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get access request from store")
+	}
+
+	return &request, nil
+}
+
+func (s *SqlStore) GetExpiredAccessRequests(ctx context.Context, lockStrength LockingStrength, before time.Time) ([]*accessrequests.AccessRequest, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var requests []*accessrequests.AccessRequest
+	result := tx.Find(&requests, "status = ? and expires_at <= ?", accessrequests.StatusApproved, before)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get expired access requests from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get expired access requests from store")
+	}
+
+	return requests, nil
+}
+
+func (s *SqlStore) SaveAccessRequest(ctx context.Context, request *accessrequests.AccessRequest) error {
+	result := s.db.Save(request)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save access request to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to save access request to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetAccessRequestSettings(ctx context.Context, lockStrength LockingStrength, accountID string) (*accessrequests.Settings, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var settings accessrequests.Settings
+	result := tx.Take(&settings, accountIDCondition, accountID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewAccessRequestSettingsNotFoundError()
+		}
+		log.WithContext(ctx).Errorf("failed to get access request settings from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get access request settings from store")
+	}
+
+	return &settings, nil
+}
+
+func (s *SqlStore) SaveAccessRequestSettings(ctx context.Context, settings *accessrequests.Settings) error {
+	result := s.db.Save(settings)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save access request settings to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to save access request settings to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetSubnetDiscoveryAccountIDs(ctx context.Context, lockStrength LockingStrength) ([]string, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var accountIDs []string
+	result := tx.Model(&types.Account{}).Where("settings_subnet_discovery_enabled = ?", true).Order("id").Pluck("id", &accountIDs)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get subnet discovery account IDs from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get subnet discovery account IDs from store")
+	}
+
+	return accountIDs, nil
+}
+
+func (s *SqlStore) GetDiscoveredSubnets(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*subnetdiscovery.DiscoveredSubnet, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var subnets []*subnetdiscovery.DiscoveredSubnet
+	result := tx.Order("first_seen_at desc").Find(&subnets, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get discovered subnets from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get discovered subnets from store")
+	}
+
+	return subnets, nil
+}
+
+func (s *SqlStore) GetDiscoveredSubnetByID(ctx context.Context, lockStrength LockingStrength, accountID, subnetID string) (*subnetdiscovery.DiscoveredSubnet, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var subnet subnetdiscovery.DiscoveredSubnet
+	result := tx.Take(&subnet, accountAndIDQueryCondition, accountID, subnetID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewDiscoveredSubnetNotFoundError(subnetID)
+		}
+		log.WithContext(ctx).Errorf("failed to get discovered subnet from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get discovered subnet from store")
+	}
+
+	return &subnet, nil
+}
+
+func (s *SqlStore) SaveDiscoveredSubnet(ctx context.Context, subnet *subnetdiscovery.DiscoveredSubnet) error {
+	result := s.db.Save(subnet)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save discovered subnet to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to save discovered subnet to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) DeleteDiscoveredSubnet(ctx context.Context, accountID, subnetID string) error {
+	result := s.db.Delete(&subnetdiscovery.DiscoveredSubnet{}, accountAndIDQueryCondition, accountID, subnetID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to delete discovered subnet from store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to delete discovered subnet from store")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewDiscoveredSubnetNotFoundError(subnetID)
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetAccountPortForwards(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*portforwards.PortForward, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var forwards []*portforwards.PortForward
+	result := tx.Find(&forwards, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get port forwards from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get port forwards from store")
+	}
+
+	return forwards, nil
+}
+
+func (s *SqlStore) GetPortForwardByID(ctx context.Context, lockStrength LockingStrength, accountID, portForwardID string) (*portforwards.PortForward, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var forward portforwards.PortForward
+	result := tx.Take(&forward, accountAndIDQueryCondition, accountID, portForwardID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewPortForwardNotFoundError(portForwardID)
+		}
+		log.WithContext(ctx).Errorf("failed to get port forward from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get port forward from store")
+	}
+
+	return &forward, nil
+}
+
+func (s *SqlStore) SavePortForward(ctx context.Context, forward *portforwards.PortForward) error {
+	result := s.db.Save(forward)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save port forward to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to save port forward to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) DeletePortForward(ctx context.Context, accountID, portForwardID string) error {
+	result := s.db.Delete(&portforwards.PortForward{}, accountAndIDQueryCondition, accountID, portForwardID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to delete port forward from store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to delete port forward from store")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewPortForwardNotFoundError(portForwardID)
+	}
+
+	return nil
+}
+
+func (s *SqlStore) SaveObjectVersion(ctx context.Context, version *history.Version) error {
+	var latest int
+	result := s.db.Model(&history.Version{}).
+		Select("COALESCE(MAX(version), 0)").
+		Where("account_id = ? AND object_type = ? AND object_id = ?", version.AccountID, version.ObjectType, version.ObjectID).
+		Scan(&latest)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get latest object version from the store: %s", result.Error)
+		return status.Errorf(status.Internal, "failed to save object version to store")
+	}
+
+	version.Version = latest + 1
+	result = s.db.Create(version)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save object version to store: %v", result.Error)
+		return status.Errorf(status.Internal, "failed to save object version to store")
+	}
+
+	return nil
+}
+
+func (s *SqlStore) GetObjectVersions(ctx context.Context, lockStrength LockingStrength, accountID string, objectType history.ObjectType, objectID string) ([]*history.Version, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var versions []*history.Version
+	result := tx.Order("seq").Find(&versions, "account_id = ? AND object_type = ? AND object_id = ?", accountID, objectType, objectID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get object versions from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get object versions from store")
+	}
+
+	return versions, nil
+}
+
+func (s *SqlStore) GetObjectVersion(ctx context.Context, lockStrength LockingStrength, accountID string, objectType history.ObjectType, objectID string, version int) (*history.Version, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var objectVersion history.Version
+	result := tx.Take(&objectVersion, "account_id = ? AND object_type = ? AND object_id = ? AND version = ?", accountID, objectType, objectID, version)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewObjectVersionNotFoundError(objectID, version)
+		}
+		log.WithContext(ctx).Errorf("failed to get object version from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get object version from store")
+	}
+
+	return &objectVersion, nil
+}
+
+func (s *SqlStore) GetObjectVersionsByType(ctx context.Context, lockStrength LockingStrength, accountID string, objectType history.ObjectType) ([]*history.Version, error) {
+	tx := s.db
+	if lockStrength != LockingStrengthNone {
+		tx = tx.Clauses(clause.Locking{Strength: string(lockStrength)})
+	}
+
+	var versions []*history.Version
+	result := tx.Order("seq").Find(&versions, "account_id = ? AND object_type = ?", accountID, objectType)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get object versions from the store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get object versions from store")
+	}
+
+	return versions, nil
+}
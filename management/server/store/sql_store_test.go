@@ -22,6 +22,13 @@ import (
 	"github.com/stretchr/testify/require"
 
 	nbdns "github.com/netbirdio/netbird/dns"
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	"github.com/netbirdio/netbird/management/internals/modules/declarative"
+	"github.com/netbirdio/netbird/management/internals/modules/history"
+	"github.com/netbirdio/netbird/management/internals/modules/portforwards"
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	"github.com/netbirdio/netbird/management/internals/modules/subnetdiscovery"
+	"github.com/netbirdio/netbird/management/internals/modules/tenants"
 	"github.com/netbirdio/netbird/management/internals/modules/zones"
 	"github.com/netbirdio/netbird/management/internals/modules/zones/records"
 	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
@@ -4500,3 +4507,396 @@ func TestSqlStore_DeleteZoneDNSRecords(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 0, len(remainingRecords))
 }
+
+func TestSqlStore_SCIMToken(t *testing.T) {
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/extended-store.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+	_, err = store.GetAccountSCIMToken(context.Background(), LockingStrengthNone, accountID)
+	require.Error(t, err)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+
+	token, err := scim.NewToken(accountID, "scim-user", "creator")
+	require.NoError(t, err)
+	require.NoError(t, store.SaveSCIMToken(context.Background(), &token.Token))
+
+	saved, err := store.GetAccountSCIMToken(context.Background(), LockingStrengthNone, accountID)
+	require.NoError(t, err)
+	assert.Equal(t, token.ID, saved.ID)
+	assert.Equal(t, "scim-user", saved.UserID)
+	assert.Nil(t, saved.LastUsed)
+
+	byHash, err := store.GetSCIMTokenByHashedToken(context.Background(), LockingStrengthNone, scim.HashToken(token.PlainToken))
+	require.NoError(t, err)
+	assert.Equal(t, token.ID, byHash.ID)
+
+	require.NoError(t, store.MarkSCIMTokenUsed(context.Background(), token.ID))
+	saved, err = store.GetAccountSCIMToken(context.Background(), LockingStrengthNone, accountID)
+	require.NoError(t, err)
+	assert.NotNil(t, saved.LastUsed)
+
+	require.NoError(t, store.DeleteSCIMToken(context.Background(), accountID))
+	_, err = store.GetSCIMTokenByHashedToken(context.Background(), LockingStrengthNone, scim.HashToken(token.PlainToken))
+	assert.Error(t, err)
+	assert.Error(t, store.DeleteSCIMToken(context.Background(), accountID))
+}
+
+func TestSqlStore_Tenants(t *testing.T) {
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/extended-store.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+	_, err = store.GetTenant(context.Background(), LockingStrengthNone, "tenant-b")
+	require.Error(t, err)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+
+	for _, tenant := range []*tenants.Tenant{
+		{AccountID: "tenant-b", ManagedBy: accountID, Name: "b", AdminGroups: []string{"admins"}},
+		{AccountID: "tenant-a", ManagedBy: accountID, Name: "a", AdminGroups: []string{"admins"}},
+		{AccountID: "tenant-c", ManagedBy: "other-account", Name: "c"},
+	} {
+		require.NoError(t, store.SaveTenant(context.Background(), tenant))
+	}
+
+	tenant, err := store.GetTenant(context.Background(), LockingStrengthNone, "tenant-b")
+	require.NoError(t, err)
+	assert.Equal(t, accountID, tenant.ManagedBy)
+	assert.Equal(t, []string{"admins"}, tenant.AdminGroups)
+
+	managed, err := store.GetManagedTenants(context.Background(), LockingStrengthNone, accountID)
+	require.NoError(t, err)
+	require.Len(t, managed, 2)
+	assert.Equal(t, "tenant-a", managed[0].AccountID, "tenants should be ordered by name")
+	assert.Equal(t, "tenant-b", managed[1].AccountID)
+
+	require.NoError(t, store.DeleteTenant(context.Background(), "tenant-b"))
+	_, err = store.GetTenant(context.Background(), LockingStrengthNone, "tenant-b")
+	require.Error(t, err)
+
+	err = store.DeleteTenant(context.Background(), "tenant-b")
+	require.Error(t, err)
+	sErr, ok = status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+}
+
+func TestSqlStore_TenantTemplates(t *testing.T) {
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/extended-store.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+	spec := declarative.Spec{Groups: []declarative.GroupSpec{{Name: "developers"}}}
+	template := tenants.NewTemplate(accountID, "office", "branch office", spec)
+	require.NoError(t, store.SaveTenantTemplate(context.Background(), template))
+	require.NoError(t, store.SaveTenantTemplate(context.Background(), tenants.NewTemplate("other-account", "other", "", declarative.Spec{})))
+
+	saved, err := store.GetTenantTemplateByID(context.Background(), LockingStrengthNone, accountID, template.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "office", saved.Name)
+	assert.Equal(t, spec, saved.Spec)
+
+	_, err = store.GetTenantTemplateByID(context.Background(), LockingStrengthNone, "other-account", template.ID)
+	require.Error(t, err)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+
+	templates, err := store.GetTenantTemplates(context.Background(), LockingStrengthNone, accountID)
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, template.ID, templates[0].ID)
+
+	require.NoError(t, store.DeleteTenantTemplate(context.Background(), accountID, template.ID))
+	templates, err = store.GetTenantTemplates(context.Background(), LockingStrengthNone, accountID)
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+
+	err = store.DeleteTenantTemplate(context.Background(), accountID, template.ID)
+	require.Error(t, err)
+	sErr, ok = status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+}
+
+func TestSqlStore_AccessRequests(t *testing.T) {
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/extended-store.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+	older := accessrequests.NewAccessRequest(accountID, "user1", accessrequests.TargetGroup, "group1", "debugging", time.Hour)
+	older.CreatedAt = time.Now().UTC().Add(-time.Hour)
+	newer := accessrequests.NewAccessRequest(accountID, "user2", accessrequests.TargetResource, "resource1", "", 2*time.Hour)
+	require.NoError(t, store.SaveAccessRequest(context.Background(), older))
+	require.NoError(t, store.SaveAccessRequest(context.Background(), newer))
+	require.NoError(t, store.SaveAccessRequest(context.Background(), accessrequests.NewAccessRequest("other-account", "user3", accessrequests.TargetGroup, "group2", "", time.Hour)))
+
+	requests, err := store.GetAccessRequests(context.Background(), LockingStrengthNone, accountID)
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.Equal(t, newer.ID, requests[0].ID, "the newest request should be first")
+	assert.Equal(t, older.ID, requests[1].ID)
+
+	older.Status = accessrequests.StatusApproved
+	older.GroupID = "access-group"
+	older.PolicyID = "access-policy"
+	require.NoError(t, store.SaveAccessRequest(context.Background(), older))
+
+	saved, err := store.GetAccessRequestByID(context.Background(), LockingStrengthNone, accountID, older.ID)
+	require.NoError(t, err)
+	assert.Equal(t, accessrequests.StatusApproved, saved.Status)
+	assert.Equal(t, time.Hour, saved.Duration)
+	assert.Equal(t, "access-policy", saved.PolicyID)
+
+	_, err = store.GetAccessRequestByID(context.Background(), LockingStrengthNone, "other-account", older.ID)
+	require.Error(t, err)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+}
+
+func TestSqlStore_GetExpiredAccessRequests(t *testing.T) {
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/extended-store.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+	now := time.Now().UTC()
+
+	newRequest := func(accountID string, requestStatus accessrequests.Status, expiresAt time.Time) *accessrequests.AccessRequest {
+		request := accessrequests.NewAccessRequest(accountID, "user1", accessrequests.TargetGroup, "group1", "", time.Hour)
+		request.Status = requestStatus
+		request.ExpiresAt = expiresAt
+		require.NoError(t, store.SaveAccessRequest(context.Background(), request))
+		return request
+	}
+
+	expired := newRequest(accountID, accessrequests.StatusApproved, now.Add(-time.Minute))
+	expiresNow := newRequest(accountID, accessrequests.StatusApproved, now)
+	otherAccount := newRequest("other-account", accessrequests.StatusApproved, now.Add(-time.Hour))
+	newRequest(accountID, accessrequests.StatusApproved, now.Add(time.Minute))
+	newRequest(accountID, accessrequests.StatusPending, time.Time{})
+	newRequest(accountID, accessrequests.StatusRevoked, now.Add(-time.Minute))
+	newRequest(accountID, accessrequests.StatusExpired, now.Add(-time.Hour))
+
+	requests, err := store.GetExpiredAccessRequests(context.Background(), LockingStrengthNone, now)
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(requests))
+	for _, request := range requests {
+		ids = append(ids, request.ID)
+	}
+	assert.ElementsMatch(t, []string{expired.ID, expiresNow.ID, otherAccount.ID}, ids,
+		"only approved requests of all accounts that expired by the given time should be returned")
+}
+
+func TestSqlStore_AccessRequestSettings(t *testing.T) {
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/extended-store.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+	_, err = store.GetAccessRequestSettings(context.Background(), LockingStrengthNone, accountID)
+	require.Error(t, err)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+
+	settings := accessrequests.NewSettings(accountID)
+	settings.ApproverGroups = []string{"approvers"}
+	require.NoError(t, store.SaveAccessRequestSettings(context.Background(), settings))
+
+	saved, err := store.GetAccessRequestSettings(context.Background(), LockingStrengthNone, accountID)
+	require.NoError(t, err)
+	assert.Equal(t, []types.UserRole{types.UserRoleOwner, types.UserRoleAdmin}, saved.ApproverRoles)
+	assert.Equal(t, []string{"approvers"}, saved.ApproverGroups)
+	assert.Equal(t, accessrequests.DefaultMaxDuration, saved.MaxDuration)
+}
+
+func TestSqlStore_SaveObjectVersion(t *testing.T) {
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/extended-store.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+	saveVersion := func(accountID string, objectType history.ObjectType, objectID string, operation history.Operation) *history.Version {
+		version := &history.Version{
+			AccountID:  accountID,
+			ObjectType: objectType,
+			ObjectID:   objectID,
+			Operation:  operation,
+			UserID:     "user1",
+			CreatedAt:  time.Now().UTC(),
+			After:      []byte(`{"ID":"` + objectID + `"}`),
+		}
+		require.NoError(t, store.SaveObjectVersion(context.Background(), version))
+		return version
+	}
+
+	assert.Equal(t, 1, saveVersion(accountID, history.ObjectRoute, "route1", history.OperationCreated).Version)
+	assert.Equal(t, 1, saveVersion(accountID, history.ObjectRoute, "route2", history.OperationCreated).Version)
+	assert.Equal(t, 2, saveVersion(accountID, history.ObjectRoute, "route1", history.OperationUpdated).Version)
+	assert.Equal(t, 1, saveVersion(accountID, history.ObjectNetwork, "route1", history.OperationCreated).Version,
+		"objects of another type should be numbered separately")
+	assert.Equal(t, 1, saveVersion("other-account", history.ObjectRoute, "route1", history.OperationCreated).Version,
+		"objects of another account should be numbered separately")
+	assert.Equal(t, 3, saveVersion(accountID, history.ObjectRoute, "route1", history.OperationDeleted).Version)
+
+	versions, err := store.GetObjectVersions(context.Background(), LockingStrengthNone, accountID, history.ObjectRoute, "route1")
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	for i, version := range versions {
+		assert.Equal(t, i+1, version.Version)
+	}
+	assert.Less(t, versions[0].Seq, versions[1].Seq)
+	assert.Less(t, versions[1].Seq, versions[2].Seq)
+	assert.Equal(t, history.OperationDeleted, versions[2].Operation)
+
+	version, err := store.GetObjectVersion(context.Background(), LockingStrengthNone, accountID, history.ObjectRoute, "route1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, history.OperationUpdated, version.Operation)
+	assert.JSONEq(t, `{"ID":"route1"}`, string(version.After))
+
+	_, err = store.GetObjectVersion(context.Background(), LockingStrengthNone, accountID, history.ObjectRoute, "route1", 4)
+	require.Error(t, err)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+
+	byType, err := store.GetObjectVersionsByType(context.Background(), LockingStrengthNone, accountID, history.ObjectRoute)
+	require.NoError(t, err)
+	require.Len(t, byType, 4)
+	objectIDs := make([]string, 0, len(byType))
+	for _, version := range byType {
+		objectIDs = append(objectIDs, version.ObjectID)
+	}
+	assert.Equal(t, []string{"route1", "route2", "route1", "route1"}, objectIDs, "versions should be ordered from the oldest")
+}
+
+func TestSqlStore_DiscoveredSubnets(t *testing.T) {
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/extended-store.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+	accountIDs, err := store.GetSubnetDiscoveryAccountIDs(context.Background(), LockingStrengthNone)
+	require.NoError(t, err)
+	assert.Empty(t, accountIDs)
+
+	settings, err := store.GetAccountSettings(context.Background(), LockingStrengthNone, accountID)
+	require.NoError(t, err)
+	settings.SubnetDiscoveryEnabled = true
+	require.NoError(t, store.SaveAccountSettings(context.Background(), accountID, settings))
+
+	accountIDs, err = store.GetSubnetDiscoveryAccountIDs(context.Background(), LockingStrengthNone)
+	require.NoError(t, err)
+	assert.Equal(t, []string{accountID}, accountIDs)
+
+	now := time.Now().UTC()
+	older := subnetdiscovery.NewDiscoveredSubnet(accountID, subnetdiscovery.Candidate{
+		NetworkID: "network1",
+		PeerID:    "peer1",
+		Prefix:    netip.MustParsePrefix("10.20.0.0/16"),
+	}, now.Add(-time.Hour))
+	newer := subnetdiscovery.NewDiscoveredSubnet(accountID, subnetdiscovery.Candidate{
+		NetworkID: "network1",
+		PeerID:    "peer1",
+		Prefix:    netip.MustParsePrefix("10.30.0.0/16"),
+	}, now)
+	require.NoError(t, store.SaveDiscoveredSubnet(context.Background(), older))
+	require.NoError(t, store.SaveDiscoveredSubnet(context.Background(), newer))
+
+	subnets, err := store.GetDiscoveredSubnets(context.Background(), LockingStrengthNone, accountID)
+	require.NoError(t, err)
+	require.Len(t, subnets, 2)
+	assert.Equal(t, newer.ID, subnets[0].ID, "the latest discovered subnet should be first")
+	assert.Equal(t, older.ID, subnets[1].ID)
+
+	older.Status = subnetdiscovery.StatusApproved
+	older.ResourceID = "resource1"
+	require.NoError(t, store.SaveDiscoveredSubnet(context.Background(), older))
+
+	saved, err := store.GetDiscoveredSubnetByID(context.Background(), LockingStrengthNone, accountID, older.ID)
+	require.NoError(t, err)
+	assert.Equal(t, netip.MustParsePrefix("10.20.0.0/16"), saved.Prefix)
+	assert.Equal(t, subnetdiscovery.StatusApproved, saved.Status)
+	assert.Equal(t, "resource1", saved.ResourceID)
+
+	require.NoError(t, store.DeleteDiscoveredSubnet(context.Background(), accountID, older.ID))
+	_, err = store.GetDiscoveredSubnetByID(context.Background(), LockingStrengthNone, accountID, older.ID)
+	require.Error(t, err)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+
+	err = store.DeleteDiscoveredSubnet(context.Background(), accountID, older.ID)
+	require.Error(t, err)
+	sErr, ok = status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+}
+
+func TestSqlStore_PortForwards(t *testing.T) {
+	store, cleanup, err := NewTestStoreFromSQL(context.Background(), "../testdata/extended-store.sql", t.TempDir())
+	t.Cleanup(cleanup)
+	require.NoError(t, err)
+
+	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+	forward := &portforwards.PortForward{
+		ID:            "forward1",
+		AccountID:     accountID,
+		Name:          "web",
+		Enabled:       true,
+		PeerID:        "peer1",
+		ListenAddress: netip.MustParseAddr("203.0.113.10"),
+		ListenPort:    8443,
+		Protocol:      portforwards.ProtocolTCP,
+		TargetPeerID:  "peer2",
+		TargetPort:    443,
+		SourceRanges:  []netip.Prefix{netip.MustParsePrefix("198.51.100.0/24")},
+	}
+	require.NoError(t, store.SavePortForward(context.Background(), forward))
+	require.NoError(t, store.SavePortForward(context.Background(), &portforwards.PortForward{ID: "forward2", AccountID: "other-account", Name: "ssh"}))
+
+	forwards, err := store.GetAccountPortForwards(context.Background(), LockingStrengthNone, accountID)
+	require.NoError(t, err)
+	require.Len(t, forwards, 1)
+	assert.Equal(t, forward.ID, forwards[0].ID)
+
+	saved, err := store.GetPortForwardByID(context.Background(), LockingStrengthNone, accountID, forward.ID)
+	require.NoError(t, err)
+	assert.Equal(t, forward, saved)
+
+	_, err = store.GetPortForwardByID(context.Background(), LockingStrengthNone, accountID, "forward2")
+	require.Error(t, err)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+
+	require.NoError(t, store.DeletePortForward(context.Background(), accountID, forward.ID))
+	forwards, err = store.GetAccountPortForwards(context.Background(), LockingStrengthNone, accountID)
+	require.NoError(t, err)
+	assert.Empty(t, forwards)
+
+	err = store.DeletePortForward(context.Background(), accountID, forward.ID)
+	require.Error(t, err)
+	sErr, ok = status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+}
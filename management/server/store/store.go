@@ -23,6 +23,14 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/netbirdio/netbird/dns"
+	"github.com/netbirdio/netbird/management/internals/modules/accessrequests"
+	"github.com/netbirdio/netbird/management/internals/modules/declarative"
+	"github.com/netbirdio/netbird/management/internals/modules/history"
+	"github.com/netbirdio/netbird/management/internals/modules/peerapproval"
+	"github.com/netbirdio/netbird/management/internals/modules/portforwards"
+	"github.com/netbirdio/netbird/management/internals/modules/scim"
+	"github.com/netbirdio/netbird/management/internals/modules/subnetdiscovery"
+	"github.com/netbirdio/netbird/management/internals/modules/tenants"
 	"github.com/netbirdio/netbird/management/internals/modules/zones"
 	"github.com/netbirdio/netbird/management/internals/modules/zones/records"
 	"github.com/netbirdio/netbird/management/server/telemetry"
@@ -36,6 +44,7 @@ import (
 	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
 	networkTypes "github.com/netbirdio/netbird/management/server/networks/types"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions/roles"
 	"github.com/netbirdio/netbird/management/server/posture"
 	"github.com/netbirdio/netbird/route"
 )
@@ -53,6 +62,7 @@ const (
 type Store interface {
 	GetAccountsCounter(ctx context.Context) (int64, error)
 	GetAllAccounts(ctx context.Context) []*types.Account
+	GetAllAccountIDs(ctx context.Context, lockStrength LockingStrength) ([]string, error)
 	GetAccount(ctx context.Context, accountID string) (*types.Account, error)
 	GetAccountMeta(ctx context.Context, lockStrength LockingStrength, accountID string) (*types.AccountMeta, error)
 	GetAccountOnboarding(ctx context.Context, accountID string) (*types.AccountOnboarding, error)
@@ -233,6 +243,61 @@ type Store interface {
 	MarkPendingJobsAsFailed(ctx context.Context, accountID, peerID, jobID, reason string) error
 	MarkAllPendingJobsAsFailed(ctx context.Context, accountID, peerID, reason string) error
 	GetPeerIDByKey(ctx context.Context, lockStrength LockingStrength, key string) (string, error)
+
+	GetAccountSCIMToken(ctx context.Context, lockStrength LockingStrength, accountID string) (*scim.Token, error)
+	GetSCIMTokenByHashedToken(ctx context.Context, lockStrength LockingStrength, hashedToken string) (*scim.Token, error)
+	SaveSCIMToken(ctx context.Context, token *scim.Token) error
+	DeleteSCIMToken(ctx context.Context, accountID string) error
+	MarkSCIMTokenUsed(ctx context.Context, tokenID string) error
+
+	GetAccountCustomRoles(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*roles.CustomRole, error)
+	GetCustomRoleByID(ctx context.Context, lockStrength LockingStrength, accountID, roleID string) (*roles.CustomRole, error)
+	SaveCustomRole(ctx context.Context, role *roles.CustomRole) error
+	DeleteCustomRole(ctx context.Context, accountID, roleID string) error
+
+	GetPeerApprovalConfig(ctx context.Context, lockStrength LockingStrength, accountID string) (*peerapproval.Config, error)
+	SavePeerApprovalConfig(ctx context.Context, config *peerapproval.Config) error
+
+	GetManagedObjects(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*declarative.ManagedObject, error)
+	SaveManagedObjects(ctx context.Context, objects []*declarative.ManagedObject) error
+	DeleteManagedObject(ctx context.Context, accountID string, kind declarative.Kind, objectID string) error
+
+	GetTenant(ctx context.Context, lockStrength LockingStrength, accountID string) (*tenants.Tenant, error)
+	GetManagedTenants(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*tenants.Tenant, error)
+	SaveTenant(ctx context.Context, tenant *tenants.Tenant) error
+	DeleteTenant(ctx context.Context, accountID string) error
+	GetTenantTemplates(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*tenants.Template, error)
+	GetTenantTemplateByID(ctx context.Context, lockStrength LockingStrength, accountID, templateID string) (*tenants.Template, error)
+	SaveTenantTemplate(ctx context.Context, template *tenants.Template) error
+	DeleteTenantTemplate(ctx context.Context, accountID, templateID string) error
+
+	GetAccessRequests(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*accessrequests.AccessRequest, error)
+	GetAccessRequestByID(ctx context.Context, lockStrength LockingStrength, accountID, requestID string) (*accessrequests.AccessRequest, error)
+	// GetExpiredAccessRequests returns the approved requests of all accounts that expired before the given time
+	GetExpiredAccessRequests(ctx context.Context, lockStrength LockingStrength, before time.Time) ([]*accessrequests.AccessRequest, error)
+	SaveAccessRequest(ctx context.Context, request *accessrequests.AccessRequest) error
+	GetAccessRequestSettings(ctx context.Context, lockStrength LockingStrength, accountID string) (*accessrequests.Settings, error)
+	SaveAccessRequestSettings(ctx context.Context, settings *accessrequests.Settings) error
+
+	// GetSubnetDiscoveryAccountIDs returns the IDs of the accounts with subnet discovery enabled
+	GetSubnetDiscoveryAccountIDs(ctx context.Context, lockStrength LockingStrength) ([]string, error)
+	GetDiscoveredSubnets(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*subnetdiscovery.DiscoveredSubnet, error)
+	GetDiscoveredSubnetByID(ctx context.Context, lockStrength LockingStrength, accountID, subnetID string) (*subnetdiscovery.DiscoveredSubnet, error)
+	SaveDiscoveredSubnet(ctx context.Context, subnet *subnetdiscovery.DiscoveredSubnet) error
+	DeleteDiscoveredSubnet(ctx context.Context, accountID, subnetID string) error
+
+	GetAccountPortForwards(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*portforwards.PortForward, error)
+	GetPortForwardByID(ctx context.Context, lockStrength LockingStrength, accountID, portForwardID string) (*portforwards.PortForward, error)
+	SavePortForward(ctx context.Context, forward *portforwards.PortForward) error
+	DeletePortForward(ctx context.Context, accountID, portForwardID string) error
+
+	// SaveObjectVersion stores a new version of an object and assigns it the next version number of the object
+	SaveObjectVersion(ctx context.Context, version *history.Version) error
+	// GetObjectVersions returns the versions of an object ordered from the oldest
+	GetObjectVersions(ctx context.Context, lockStrength LockingStrength, accountID string, objectType history.ObjectType, objectID string) ([]*history.Version, error)
+	GetObjectVersion(ctx context.Context, lockStrength LockingStrength, accountID string, objectType history.ObjectType, objectID string, version int) (*history.Version, error)
+	// GetObjectVersionsByType returns the versions of all objects of a type in the account ordered from the oldest
+	GetObjectVersionsByType(ctx context.Context, lockStrength LockingStrength, accountID string, objectType history.ObjectType) ([]*history.Version, error)
 }
 
 const (
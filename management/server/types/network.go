@@ -4,6 +4,8 @@ import (
 	"encoding/binary"
 	"math/rand"
 	"net"
+	"net/netip"
+	"slices"
 	"sync"
 	"time"
 
@@ -66,8 +68,12 @@ func mergeUniquePeersByID(peers1, peers2 []*nbpeer.Peer) []*nbpeer.Peer {
 }
 
 type ForwardingRule struct {
-	RuleProtocol      string
-	DestinationPorts  RulePortRange
+	RuleProtocol     string
+	DestinationPorts RulePortRange
+	// DestinationAddress is the local address of the ingress peer the traffic has to arrive at, all addresses if nil
+	DestinationAddress net.IP
+	// SourceRanges are the prefixes allowed to use the rule, all sources if empty
+	SourceRanges      []netip.Prefix
 	TranslatedAddress net.IP
 	TranslatedPorts   RulePortRange
 }
@@ -86,17 +92,26 @@ func (f *ForwardingRule) ToProto() *proto.ForwardingRule {
 	default:
 		protocol = proto.RuleProtocol_UNKNOWN
 	}
-	return &proto.ForwardingRule{
+	rule := &proto.ForwardingRule{
 		Protocol:          protocol,
 		DestinationPort:   f.DestinationPorts.ToProto(),
 		TranslatedAddress: ipToBytes(f.TranslatedAddress),
 		TranslatedPort:    f.TranslatedPorts.ToProto(),
 	}
+	if f.DestinationAddress != nil {
+		rule.DestinationAddress = ipToBytes(f.DestinationAddress)
+	}
+	for _, prefix := range f.SourceRanges {
+		rule.SourceRanges = append(rule.SourceRanges, prefix.String())
+	}
+	return rule
 }
 
 func (f *ForwardingRule) Equal(other *ForwardingRule) bool {
 	return f.RuleProtocol == other.RuleProtocol &&
 		f.DestinationPorts.Equal(&other.DestinationPorts) &&
+		f.DestinationAddress.Equal(other.DestinationAddress) &&
+		slices.Equal(f.SourceRanges, other.SourceRanges) &&
 		f.TranslatedAddress.Equal(other.TranslatedAddress) &&
 		f.TranslatedPorts.Equal(&other.TranslatedPorts)
 }
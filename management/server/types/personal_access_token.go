@@ -1,10 +1,13 @@
 package types
 
 import (
+	"context"
 	"crypto/sha256"
 	b64 "encoding/base64"
 	"fmt"
 	"hash/crc32"
+	"net/netip"
+	"slices"
 	"time"
 
 	b "github.com/hashicorp/go-secure-stdlib/base62"
@@ -12,6 +15,8 @@ import (
 	"github.com/rs/xid"
 
 	"github.com/netbirdio/netbird/base62"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
 )
 
 const (
@@ -33,18 +38,150 @@ type PersonalAccessToken struct {
 	Name           string
 	HashedToken    string
 	ExpirationDate *time.Time
-	// scope could be added in future
+	// Scope restricts the token to a subset of the permissions of its user, nil means unrestricted
+	Scope     *PATScope `gorm:"serializer:json"`
 	CreatedBy string
 	CreatedAt time.Time
 	LastUsed  *time.Time
 }
 
+// PATScope restricts what a personal access token can do on top of the permissions of its user.
+// Empty fields don't restrict anything.
+type PATScope struct {
+	// Permissions lists the allowed operations per module, everything else is denied
+	Permissions map[modules.Module]map[operations.Operation]bool `json:",omitempty"`
+	// PeerGroups limits peer access to peers of these groups
+	PeerGroups []string `json:",omitempty"`
+	// SourceRanges limits the addresses the token can be used from
+	SourceRanges []netip.Prefix `json:",omitempty"`
+}
+
+type patScopeContextKey struct{}
+
+// SetPATScopeInContext stores the scope of the personal access token a request was authenticated with
+func SetPATScopeInContext(ctx context.Context, scope *PATScope) context.Context {
+	return context.WithValue(ctx, patScopeContextKey{}, scope)
+}
+
+// PATScopeFromContext returns the scope of the personal access token a request was authenticated with,
+// nil when the request wasn't authenticated with a scoped token
+func PATScopeFromContext(ctx context.Context) *PATScope {
+	scope, _ := ctx.Value(patScopeContextKey{}).(*PATScope)
+	return scope
+}
+
+// Validate checks that the scope only references known modules and operations
+func (s *PATScope) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	for module, ops := range s.Permissions {
+		if _, ok := modules.All[module]; !ok {
+			return fmt.Errorf("unknown module %s", module)
+		}
+		for operation := range ops {
+			switch operation {
+			case operations.Read, operations.Create, operations.Update, operations.Delete:
+			default:
+				return fmt.Errorf("unknown operation %s on module %s", operation, module)
+			}
+		}
+	}
+
+	for _, prefix := range s.SourceRanges {
+		if !prefix.IsValid() {
+			return fmt.Errorf("invalid source range %s", prefix)
+		}
+	}
+	return nil
+}
+
+// AllowsOperation reports whether the scope allows the operation on the module
+func (s *PATScope) AllowsOperation(module modules.Module, operation operations.Operation) bool {
+	if s == nil || len(s.Permissions) == 0 {
+		return true
+	}
+	return s.Permissions[module][operation]
+}
+
+// RestrictsPeers reports whether the scope limits access to peers of specific groups
+func (s *PATScope) RestrictsPeers() bool {
+	return s != nil && len(s.PeerGroups) > 0
+}
+
+// AllowsPeerGroups reports whether a peer in the given groups is accessible with the scope
+func (s *PATScope) AllowsPeerGroups(groupIDs []string) bool {
+	if !s.RestrictsPeers() {
+		return true
+	}
+	for _, groupID := range groupIDs {
+		if slices.Contains(s.PeerGroups, groupID) {
+			return true
+		}
+	}
+	return false
+}
+
+// PeersInScope returns the IDs of the peers in the given groups that are accessible with the scope. It must only be
+// called when the scope restricts peers.
+func (s *PATScope) PeersInScope(groups []*Group) map[string]struct{} {
+	peers := make(map[string]struct{})
+	for _, group := range groups {
+		if !slices.Contains(s.PeerGroups, group.ID) {
+			continue
+		}
+		for _, peerID := range group.Peers {
+			peers[peerID] = struct{}{}
+		}
+	}
+	return peers
+}
+
+// AllowsSource reports whether the token can be used from the address
+func (s *PATScope) AllowsSource(addr netip.Addr) bool {
+	if s == nil || len(s.SourceRanges) == 0 {
+		return true
+	}
+	addr = addr.Unmap()
+	for _, prefix := range s.SourceRanges {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *PATScope) Copy() *PATScope {
+	if s == nil {
+		return nil
+	}
+
+	var permissions map[modules.Module]map[operations.Operation]bool
+	if s.Permissions != nil {
+		permissions = make(map[modules.Module]map[operations.Operation]bool, len(s.Permissions))
+		for module, ops := range s.Permissions {
+			permissions[module] = make(map[operations.Operation]bool, len(ops))
+			for operation, allowed := range ops {
+				permissions[module][operation] = allowed
+			}
+		}
+	}
+
+	return &PATScope{
+		Permissions:  permissions,
+		PeerGroups:   slices.Clone(s.PeerGroups),
+		SourceRanges: slices.Clone(s.SourceRanges),
+	}
+}
+
 func (t *PersonalAccessToken) Copy() *PersonalAccessToken {
 	return &PersonalAccessToken{
 		ID:             t.ID,
 		Name:           t.Name,
 		HashedToken:    t.HashedToken,
 		ExpirationDate: t.ExpirationDate,
+		Scope:          t.Scope.Copy(),
 		CreatedBy:      t.CreatedBy,
 		CreatedAt:      t.CreatedAt,
 		LastUsed:       t.LastUsed,
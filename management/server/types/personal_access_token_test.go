@@ -5,12 +5,15 @@ import (
 	b64 "encoding/base64"
 	"hash/crc32"
 	"math/big"
+	"net/netip"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/netbirdio/netbird/base62"
+	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
 )
 
 func TestPAT_GenerateToken_Hashing(t *testing.T) {
@@ -44,3 +47,69 @@ func TestPAT_GenerateToken_Checksum(t *testing.T) {
 	}
 	assert.Equal(t, expectedChecksum, actualChecksum)
 }
+
+func TestPATScope_Validate(t *testing.T) {
+	var scope *PATScope
+	assert.NoError(t, scope.Validate())
+
+	scope = &PATScope{Permissions: map[modules.Module]map[operations.Operation]bool{
+		modules.SetupKeys: {operations.Read: true, operations.Create: true},
+	}}
+	assert.NoError(t, scope.Validate())
+
+	scope = &PATScope{Permissions: map[modules.Module]map[operations.Operation]bool{"unknown": {operations.Read: true}}}
+	assert.Error(t, scope.Validate())
+
+	scope = &PATScope{Permissions: map[modules.Module]map[operations.Operation]bool{modules.Peers: {"approve": true}}}
+	assert.Error(t, scope.Validate())
+}
+
+func TestPATScope_AllowsOperation(t *testing.T) {
+	var scope *PATScope
+	assert.True(t, scope.AllowsOperation(modules.Accounts, operations.Delete))
+	assert.True(t, (&PATScope{PeerGroups: []string{"group"}}).AllowsOperation(modules.Accounts, operations.Delete))
+
+	scope = &PATScope{Permissions: map[modules.Module]map[operations.Operation]bool{
+		modules.SetupKeys: {operations.Read: true, operations.Create: true},
+	}}
+	assert.True(t, scope.AllowsOperation(modules.SetupKeys, operations.Create))
+	assert.False(t, scope.AllowsOperation(modules.SetupKeys, operations.Delete))
+	assert.False(t, scope.AllowsOperation(modules.Accounts, operations.Delete))
+}
+
+func TestPATScope_AllowsPeerGroups(t *testing.T) {
+	var scope *PATScope
+	assert.True(t, scope.AllowsPeerGroups(nil))
+
+	scope = &PATScope{PeerGroups: []string{"ci"}}
+	assert.True(t, scope.AllowsPeerGroups([]string{"all", "ci"}))
+	assert.False(t, scope.AllowsPeerGroups([]string{"all"}))
+	assert.False(t, scope.AllowsPeerGroups(nil))
+}
+
+func TestPATScope_AllowsSource(t *testing.T) {
+	var scope *PATScope
+	assert.True(t, scope.AllowsSource(netip.MustParseAddr("203.0.113.1")))
+
+	scope = &PATScope{SourceRanges: []netip.Prefix{netip.MustParsePrefix("192.168.0.0/16"), netip.MustParsePrefix("2001:db8::/32")}}
+	assert.True(t, scope.AllowsSource(netip.MustParseAddr("192.168.1.1")))
+	assert.True(t, scope.AllowsSource(netip.MustParseAddr("::ffff:192.168.1.1")))
+	assert.True(t, scope.AllowsSource(netip.MustParseAddr("2001:db8::1")))
+	assert.False(t, scope.AllowsSource(netip.MustParseAddr("203.0.113.1")))
+}
+
+func TestPATScope_Copy(t *testing.T) {
+	scope := &PATScope{
+		Permissions:  map[modules.Module]map[operations.Operation]bool{modules.Peers: {operations.Read: true}},
+		PeerGroups:   []string{"group"},
+		SourceRanges: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	}
+
+	scopeCopy := scope.Copy()
+	assert.Equal(t, scope, scopeCopy)
+
+	scopeCopy.Permissions[modules.Peers][operations.Read] = false
+	scopeCopy.PeerGroups[0] = "other"
+	assert.True(t, scope.Permissions[modules.Peers][operations.Read])
+	assert.Equal(t, "group", scope.PeerGroups[0])
+}
@@ -55,6 +55,31 @@ type Settings struct {
 
 	// AutoUpdateVersion client auto-update version
 	AutoUpdateVersion string `gorm:"default:'disabled'"`
+
+	// SubnetDiscoveryEnabled lets peers report the routing table entries that are part of the SubnetDiscoveryAllowlist.
+	// The subnets reported by network routers are suggested as network resources.
+	SubnetDiscoveryEnabled bool `gorm:"default:false"`
+
+	// SubnetDiscoveryAllowlist limits the reported subnets, the private IPv4 ranges are used if it is empty
+	SubnetDiscoveryAllowlist []netip.Prefix `gorm:"serializer:json"`
+
+	// SubnetDiscoveryAutoApprove creates network resources for discovered subnets without an admin approval
+	SubnetDiscoveryAutoApprove bool `gorm:"default:false"`
+}
+
+// DefaultSubnetDiscoveryAllowlist is used if no subnet discovery allowlist is configured
+var DefaultSubnetDiscoveryAllowlist = []netip.Prefix{
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+}
+
+// GetSubnetDiscoveryAllowlist returns the configured subnet discovery allowlist or the default one
+func (s *Settings) GetSubnetDiscoveryAllowlist() []netip.Prefix {
+	if len(s.SubnetDiscoveryAllowlist) == 0 {
+		return DefaultSubnetDiscoveryAllowlist
+	}
+	return s.SubnetDiscoveryAllowlist
 }
 
 // Copy copies the Settings struct
@@ -76,6 +101,10 @@ func (s *Settings) Copy() *Settings {
 		DNSDomain:                       s.DNSDomain,
 		NetworkRange:                    s.NetworkRange,
 		AutoUpdateVersion:               s.AutoUpdateVersion,
+
+		SubnetDiscoveryEnabled:     s.SubnetDiscoveryEnabled,
+		SubnetDiscoveryAllowlist:   slices.Clone(s.SubnetDiscoveryAllowlist),
+		SubnetDiscoveryAutoApprove: s.SubnetDiscoveryAutoApprove,
 	}
 	if s.Extra != nil {
 		settings.Extra = s.Extra.Copy()
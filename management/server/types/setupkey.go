@@ -3,6 +3,11 @@ package types
 import (
 	"crypto/sha256"
 	b64 "encoding/base64"
+	"fmt"
+	"net"
+	"net/netip"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -10,6 +15,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/rs/xid"
 
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
 	"github.com/netbirdio/netbird/management/server/util"
 )
 
@@ -56,6 +62,125 @@ type SetupKey struct {
 	Ephemeral bool
 	// AllowExtraDNSLabels indicates if the key allows extra DNS labels
 	AllowExtraDNSLabels bool
+	// Constraints optionally restrict which machines can register with this key
+	Constraints *SetupKeyConstraints `gorm:"serializer:json"`
+	// RequiresApproval indicates that peers registered with this key have to be approved before they can connect
+	RequiresApproval bool
+}
+
+// setupKeyOperatingSystems are the peer GoOS values a setup key can be limited to
+var setupKeyOperatingSystems = []string{"linux", "windows", "darwin", "freebsd", "android", "ios"}
+
+// SetupKeyConstraints are optional checks a peer has to pass to register with a setup key
+type SetupKeyConstraints struct {
+	// SourceRanges limits the public addresses a peer can register from
+	SourceRanges []netip.Prefix
+	// OperatingSystems limits the peer operating system, matched against the peer GoOS
+	OperatingSystems []string
+	// HostnamePattern is a regular expression the whole peer hostname has to match
+	HostnamePattern string
+	// PostureChecks is a list of posture check IDs the peer has to pass at registration
+	PostureChecks []string
+}
+
+// Copy returns a deep copy of the constraints
+func (c *SetupKeyConstraints) Copy() *SetupKeyConstraints {
+	if c == nil {
+		return nil
+	}
+	return &SetupKeyConstraints{
+		SourceRanges:     slices.Clone(c.SourceRanges),
+		OperatingSystems: slices.Clone(c.OperatingSystems),
+		HostnamePattern:  c.HostnamePattern,
+		PostureChecks:    slices.Clone(c.PostureChecks),
+	}
+}
+
+// IsEmpty returns true if no constraint is set
+func (c *SetupKeyConstraints) IsEmpty() bool {
+	return c == nil || len(c.SourceRanges) == 0 && len(c.OperatingSystems) == 0 &&
+		c.HostnamePattern == "" && len(c.PostureChecks) == 0
+}
+
+// Validate checks that the constraints are well-formed
+func (c *SetupKeyConstraints) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	for _, prefix := range c.SourceRanges {
+		if !prefix.IsValid() {
+			return fmt.Errorf("invalid source range %s", prefix)
+		}
+	}
+
+	for _, os := range c.OperatingSystems {
+		if !slices.Contains(setupKeyOperatingSystems, os) {
+			return fmt.Errorf("unsupported operating system %q, expected one of %s", os, strings.Join(setupKeyOperatingSystems, ", "))
+		}
+	}
+
+	if c.HostnamePattern != "" {
+		if _, err := compileHostnamePattern(c.HostnamePattern); err != nil {
+			return fmt.Errorf("invalid hostname pattern: %w", err)
+		}
+	}
+
+	for _, id := range c.PostureChecks {
+		if id == "" {
+			return fmt.Errorf("posture check ID can't be empty")
+		}
+	}
+
+	return nil
+}
+
+// CheckPeer verifies the peer against the source range, operating system and hostname constraints.
+// Posture checks are resolved and evaluated by the caller.
+func (c *SetupKeyConstraints) CheckPeer(peer *nbpeer.Peer) error {
+	if c == nil {
+		return nil
+	}
+
+	if len(c.SourceRanges) > 0 && !c.allowsSource(peer.Location.ConnectionIP) {
+		return fmt.Errorf("source address %s is not allowed", peer.Location.ConnectionIP)
+	}
+
+	if len(c.OperatingSystems) > 0 && !slices.Contains(c.OperatingSystems, strings.ToLower(peer.Meta.GoOS)) {
+		return fmt.Errorf("operating system %q is not allowed", peer.Meta.GoOS)
+	}
+
+	if c.HostnamePattern != "" {
+		re, err := compileHostnamePattern(c.HostnamePattern)
+		if err != nil {
+			return fmt.Errorf("invalid hostname pattern: %w", err)
+		}
+		if !re.MatchString(peer.Meta.Hostname) {
+			return fmt.Errorf("hostname %q doesn't match the allowed pattern", peer.Meta.Hostname)
+		}
+	}
+
+	return nil
+}
+
+// compileHostnamePattern compiles the hostname pattern anchored, so it has to match the whole hostname
+func compileHostnamePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+func (c *SetupKeyConstraints) allowsSource(ip net.IP) bool {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range c.SourceRanges {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
 }
 
 // Copy copies SetupKey to a new object
@@ -82,6 +207,8 @@ func (key *SetupKey) Copy() *SetupKey {
 		UsageLimit:          key.UsageLimit,
 		Ephemeral:           key.Ephemeral,
 		AllowExtraDNSLabels: key.AllowExtraDNSLabels,
+		Constraints:         key.Constraints.Copy(),
+		RequiresApproval:    key.RequiresApproval,
 	}
 }
 
@@ -58,6 +58,7 @@ type UserInfo struct {
 	Email                string                                     `json:"email"`
 	Name                 string                                     `json:"name"`
 	Role                 string                                     `json:"role"`
+	CustomRoleID         string                                     `json:"custom_role_id"`
 	AutoGroups           []string                                   `json:"auto_groups"`
 	Status               string                                     `json:"-"`
 	IsServiceUser        bool                                       `json:"is_service_user"`
@@ -77,8 +78,10 @@ type UserInfo struct {
 type User struct {
 	Id string `gorm:"primaryKey"`
 	// AccountID is a reference to Account that this object belongs
-	AccountID     string `json:"-" gorm:"index"`
-	Role          UserRole
+	AccountID string `json:"-" gorm:"index"`
+	Role      UserRole
+	// CustomRoleID references the account defined role whose permissions replace the permissions of the user role
+	CustomRoleID  string
 	IsServiceUser bool
 	// NonDeletable indicates whether the service user can be deleted
 	NonDeletable bool
@@ -140,9 +143,17 @@ func (u *User) IsRegularUser() bool {
 
 // IsRestrictable checks whether a user is in a restrictable role.
 func (u *User) IsRestrictable() bool {
+	if u.HasCustomRole() {
+		return false
+	}
 	return u.Role == UserRoleUser || u.Role == UserRoleBillingAdmin
 }
 
+// HasCustomRole checks whether the permissions of the user are defined by a custom role.
+func (u *User) HasCustomRole() bool {
+	return u.CustomRoleID != "" && u.Role == UserRoleUser
+}
+
 // ToUserInfo converts a User object to a UserInfo object.
 func (u *User) ToUserInfo(userData *idp.UserData) (*UserInfo, error) {
 	autoGroups := u.AutoGroups
@@ -162,6 +173,7 @@ func (u *User) ToUserInfo(userData *idp.UserData) (*UserInfo, error) {
 			Email:           u.Email,
 			Name:            name,
 			Role:            string(u.Role),
+			CustomRoleID:    u.CustomRoleID,
 			AutoGroups:      u.AutoGroups,
 			Status:          string(UserStatusActive),
 			IsServiceUser:   u.IsServiceUser,
@@ -185,6 +197,7 @@ func (u *User) ToUserInfo(userData *idp.UserData) (*UserInfo, error) {
 		Email:           userData.Email,
 		Name:            userData.Name,
 		Role:            string(u.Role),
+		CustomRoleID:    u.CustomRoleID,
 		AutoGroups:      autoGroups,
 		Status:          string(userStatus),
 		IsServiceUser:   u.IsServiceUser,
@@ -208,6 +221,7 @@ func (u *User) Copy() *User {
 		Id:                   u.Id,
 		AccountID:            u.AccountID,
 		Role:                 u.Role,
+		CustomRoleID:         u.CustomRoleID,
 		AutoGroups:           autoGroups,
 		IsServiceUser:        u.IsServiceUser,
 		NonDeletable:         u.NonDeletable,
@@ -27,7 +27,7 @@ import (
 )
 
 // createServiceUser creates a new service user under the given account.
-func (am *DefaultAccountManager) createServiceUser(ctx context.Context, accountID string, initiatorUserID string, role types.UserRole, serviceUserName string, nonDeletable bool, autoGroups []string) (*types.UserInfo, error) {
+func (am *DefaultAccountManager) createServiceUser(ctx context.Context, accountID string, initiatorUserID string, role types.UserRole, customRoleID string, serviceUserName string, nonDeletable bool, autoGroups []string) (*types.UserInfo, error) {
 	allowed, err := am.permissionsManager.ValidateUserPermissions(ctx, accountID, initiatorUserID, modules.Users, operations.Create)
 	if err != nil {
 		return nil, status.NewPermissionValidationError(err)
@@ -40,9 +40,14 @@ func (am *DefaultAccountManager) createServiceUser(ctx context.Context, accountI
 		return nil, status.NewServiceUserRoleInvalidError()
 	}
 
+	if err = am.validateNewUserRole(ctx, accountID, initiatorUserID, role, customRoleID); err != nil {
+		return nil, err
+	}
+
 	newUserID := uuid.New().String()
 	newUser := types.NewUser(newUserID, role, true, nonDeletable, serviceUserName, autoGroups, types.UserIssuedAPI, "", "")
 	newUser.AccountID = accountID
+	newUser.CustomRoleID = customRoleID
 	log.WithContext(ctx).Debugf("New User: %v", newUser)
 
 	if err = am.Store.SaveUser(ctx, newUser); err != nil {
@@ -57,6 +62,7 @@ func (am *DefaultAccountManager) createServiceUser(ctx context.Context, accountI
 		Email:         "",
 		Name:          newUser.ServiceUserName,
 		Role:          string(newUser.Role),
+		CustomRoleID:  newUser.CustomRoleID,
 		AutoGroups:    newUser.AutoGroups,
 		Status:        string(types.UserStatusActive),
 		IsServiceUser: true,
@@ -68,7 +74,7 @@ func (am *DefaultAccountManager) createServiceUser(ctx context.Context, accountI
 // CreateUser creates a new user under the given account. Effectively this is a user invite.
 func (am *DefaultAccountManager) CreateUser(ctx context.Context, accountID, userID string, user *types.UserInfo) (*types.UserInfo, error) {
 	if user.IsServiceUser {
-		return am.createServiceUser(ctx, accountID, userID, types.StrRoleToUserRole(user.Role), user.Name, user.NonDeletable, user.AutoGroups)
+		return am.createServiceUser(ctx, accountID, userID, types.StrRoleToUserRole(user.Role), user.CustomRoleID, user.Name, user.NonDeletable, user.AutoGroups)
 	}
 	return am.inviteNewUser(ctx, accountID, userID, user)
 }
@@ -91,6 +97,10 @@ func (am *DefaultAccountManager) inviteNewUser(ctx context.Context, accountID, u
 		return nil, status.NewPermissionDeniedError()
 	}
 
+	if err = am.validateNewUserRole(ctx, accountID, userID, types.StrRoleToUserRole(invite.Role), invite.CustomRoleID); err != nil {
+		return nil, err
+	}
+
 	initiatorUser, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthNone, userID)
 	if err != nil {
 		return nil, err
@@ -119,6 +129,7 @@ func (am *DefaultAccountManager) inviteNewUser(ctx context.Context, accountID, u
 		Id:                   idpUser.ID,
 		AccountID:            accountID,
 		Role:                 types.StrRoleToUserRole(invite.Role),
+		CustomRoleID:         invite.CustomRoleID,
 		AutoGroups:           invite.AutoGroups,
 		Issued:               invite.Issued,
 		IntegrationReference: invite.IntegrationReference,
@@ -147,6 +158,21 @@ func (am *DefaultAccountManager) inviteNewUser(ctx context.Context, accountID, u
 	return newUser.ToUserInfo(idpUser)
 }
 
+// validateNewUserRole checks that the initiator can assign the role and custom role to a new user
+func (am *DefaultAccountManager) validateNewUserRole(ctx context.Context, accountID, initiatorUserID string, role types.UserRole, customRoleID string) error {
+	if initiatorUserID != activity.SystemInitiator {
+		initiatorUser, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthNone, initiatorUserID)
+		if err != nil {
+			return err
+		}
+		if err = validateRoleAssignment(initiatorUser, role, customRoleID); err != nil {
+			return err
+		}
+	}
+
+	return validateCustomRole(ctx, am.Store, accountID, role, customRoleID)
+}
+
 // createNewIdpUser validates the invite and creates a new user in the IdP
 func (am *DefaultAccountManager) createNewIdpUser(ctx context.Context, accountID string, inviterID string, invite *types.UserInfo) (*idp.UserData, error) {
 	inviter, err := am.GetUserByID(ctx, inviterID)
@@ -354,7 +380,7 @@ func (am *DefaultAccountManager) InviteUser(ctx context.Context, accountID strin
 }
 
 // CreatePAT creates a new PAT for the given user
-func (am *DefaultAccountManager) CreatePAT(ctx context.Context, accountID string, initiatorUserID string, targetUserID string, tokenName string, expiresIn int) (*types.PersonalAccessTokenGenerated, error) {
+func (am *DefaultAccountManager) CreatePAT(ctx context.Context, accountID string, initiatorUserID string, targetUserID string, tokenName string, expiresIn int, scope *types.PATScope) (*types.PersonalAccessTokenGenerated, error) {
 	if tokenName == "" {
 		return nil, status.Errorf(status.InvalidArgument, "token name can't be empty")
 	}
@@ -363,6 +389,15 @@ func (am *DefaultAccountManager) CreatePAT(ctx context.Context, accountID string
 		return nil, status.Errorf(status.InvalidArgument, "expiration has to be between 1 and 365")
 	}
 
+	if err := scope.Validate(); err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "invalid token scope: %v", err)
+	}
+
+	// a scoped token could otherwise create an unrestricted one
+	if types.PATScopeFromContext(ctx) != nil {
+		return nil, status.Errorf(status.PermissionDenied, "scoped tokens can't create tokens")
+	}
+
 	allowed, err := am.permissionsManager.ValidateUserPermissions(ctx, accountID, initiatorUserID, modules.Pats, operations.Create)
 	if err != nil {
 		return nil, status.NewPermissionValidationError(err)
@@ -386,16 +421,25 @@ func (am *DefaultAccountManager) CreatePAT(ctx context.Context, accountID string
 		return nil, status.NewAdminPermissionError()
 	}
 
+	if scope != nil {
+		for _, groupID := range scope.PeerGroups {
+			if _, err = am.Store.GetGroupByID(ctx, store.LockingStrengthNone, accountID, groupID); err != nil {
+				return nil, status.Errorf(status.InvalidArgument, "invalid token scope: %v", err)
+			}
+		}
+	}
+
 	pat, err := types.CreateNewPAT(tokenName, expiresIn, targetUserID, initiatorUser.Id)
 	if err != nil {
 		return nil, status.Errorf(status.Internal, "failed to create PAT: %v", err)
 	}
+	pat.Scope = scope
 
 	if err = am.Store.SavePAT(ctx, &pat.PersonalAccessToken); err != nil {
 		return nil, err
 	}
 
-	meta := map[string]any{"name": pat.Name, "is_service_user": targetUser.IsServiceUser, "user_name": targetUser.ServiceUserName}
+	meta := map[string]any{"name": pat.Name, "is_service_user": targetUser.IsServiceUser, "user_name": targetUser.ServiceUserName, "scoped": scope != nil}
 	am.StoreEvent(ctx, initiatorUserID, targetUserID, accountID, activity.PersonalAccessTokenCreated, meta)
 
 	return pat, nil
@@ -656,9 +700,13 @@ func (am *DefaultAccountManager) prepareUserUpdateEvents(ctx context.Context, ac
 		eventsToStore = append(eventsToStore, func() {
 			am.StoreEvent(ctx, initiatorUserID, oldUser.Id, accountID, activity.TransferredOwnerRole, nil)
 		})
-	case oldUser.Role != newUser.Role:
+	case oldUser.Role != newUser.Role || oldUser.CustomRoleID != newUser.CustomRoleID:
+		meta := map[string]any{"role": newUser.Role}
+		if newUser.CustomRoleID != "" {
+			meta["custom_role_id"] = newUser.CustomRoleID
+		}
 		eventsToStore = append(eventsToStore, func() {
-			am.StoreEvent(ctx, initiatorUserID, oldUser.Id, accountID, activity.UserRoleUpdated, map[string]any{"role": newUser.Role})
+			am.StoreEvent(ctx, initiatorUserID, oldUser.Id, accountID, activity.UserRoleUpdated, meta)
 		})
 	}
 
@@ -710,14 +758,30 @@ func (am *DefaultAccountManager) processUserUpdate(ctx context.Context, transact
 		return false, nil, nil, nil, err
 	}
 
+	if update.CustomRoleID != oldUser.CustomRoleID || update.Role != oldUser.Role {
+		if err := validateCustomRole(ctx, transaction, accountID, update.Role, update.CustomRoleID); err != nil {
+			return false, nil, nil, nil, err
+		}
+	}
+
 	// only auto groups, revoked status, and integration reference can be updated for now
 	updatedUser := oldUser.Copy()
 	updatedUser.Role = update.Role
+	updatedUser.CustomRoleID = update.CustomRoleID
 	updatedUser.Blocked = update.Blocked
 	updatedUser.AutoGroups = update.AutoGroups
 	// these two fields can't be set via API, only via direct call to the method
 	updatedUser.Issued = update.Issued
 	updatedUser.IntegrationReference = update.IntegrationReference
+	// integrations provisioning users also keep their name and email in sync
+	if update.Issued == types.UserIssuedIntegration {
+		if update.Name != "" {
+			updatedUser.Name = update.Name
+		}
+		if update.Email != "" {
+			updatedUser.Email = update.Email
+		}
+	}
 
 	var transferredOwnerRole bool
 	result, err := handleOwnerRoleTransfer(ctx, transaction, initiatorUser, update)
@@ -822,15 +886,21 @@ func validateUserUpdate(groupsMap map[string]*types.Group, initiatorUser, oldUse
 	if initiatorUser.HasAdminPower() && initiatorUser.Id == update.Id && update.Role != initiatorUser.Role {
 		return status.Errorf(status.PermissionDenied, "admins can't change their role")
 	}
-	if initiatorUser.Role == types.UserRoleAdmin && oldUser.Role == types.UserRoleOwner && update.Role != oldUser.Role {
+	// the owner guards apply to admins and to users with a custom role that grants user updates
+	if initiatorUser.Role != types.UserRoleOwner && oldUser.Role == types.UserRoleOwner && update.Role != oldUser.Role {
 		return status.Errorf(status.PermissionDenied, "only owners can remove owner role from their user")
 	}
-	if initiatorUser.Role == types.UserRoleAdmin && oldUser.Role == types.UserRoleOwner && update.IsBlocked() && !oldUser.IsBlocked() {
+	if initiatorUser.Role != types.UserRoleOwner && oldUser.Role == types.UserRoleOwner && update.IsBlocked() && !oldUser.IsBlocked() {
 		return status.Errorf(status.PermissionDenied, "unable to block owner user")
 	}
-	if initiatorUser.Role == types.UserRoleAdmin && update.Role == types.UserRoleOwner && update.Role != oldUser.Role {
+	if initiatorUser.Role != types.UserRoleOwner && update.Role == types.UserRoleOwner && update.Role != oldUser.Role {
 		return status.Errorf(status.PermissionDenied, "only owners can add owner role to other users")
 	}
+	if update.Role != oldUser.Role || update.CustomRoleID != oldUser.CustomRoleID {
+		if err := validateRoleAssignment(initiatorUser, update.Role, update.CustomRoleID); err != nil {
+			return err
+		}
+	}
 	if oldUser.IsServiceUser && update.Role == types.UserRoleOwner {
 		return status.Errorf(status.PermissionDenied, "can't update a service user with owner role")
 	}
@@ -849,6 +919,39 @@ func validateUserUpdate(groupsMap map[string]*types.Group, initiatorUser, oldUse
 	return nil
 }
 
+// validateRoleAssignment prevents users with a custom role from assigning roles, which would let them grant
+// permissions beyond their own
+func validateRoleAssignment(initiatorUser *types.User, role types.UserRole, customRoleID string) error {
+	if initiatorUser == nil || !initiatorUser.HasCustomRole() {
+		return nil
+	}
+	if role != types.UserRoleUser || customRoleID != "" {
+		return status.Errorf(status.PermissionDenied, "users with a custom role can only assign the %s role", types.UserRoleUser)
+	}
+	return nil
+}
+
+// validateCustomRole checks that a custom role exists in the account and is assigned together with the user role
+func validateCustomRole(ctx context.Context, transaction store.Store, accountID string, role types.UserRole, customRoleID string) error {
+	if customRoleID == "" {
+		return nil
+	}
+
+	if role != types.UserRoleUser {
+		return status.Errorf(status.InvalidArgument, "custom roles can only be assigned to users with the %s role", types.UserRoleUser)
+	}
+
+	_, err := transaction.GetCustomRoleByID(ctx, store.LockingStrengthNone, accountID, customRoleID)
+	if err != nil {
+		if sErr, ok := status.FromError(err); ok && sErr.Type() == status.NotFound {
+			return status.Errorf(status.InvalidArgument, "custom role %s doesn't exist", customRoleID)
+		}
+		return err
+	}
+
+	return nil
+}
+
 // GetOrCreateAccountByUser returns an existing account for a given user id or creates a new one if doesn't exist
 func (am *DefaultAccountManager) GetOrCreateAccountByUser(ctx context.Context, userAuth auth.UserAuth) (*types.Account, error) {
 	userID := userAuth.UserId
@@ -1014,6 +1117,7 @@ func (am *DefaultAccountManager) BuildUserInfosForAccount(ctx context.Context, a
 				Email:         localUser.Email,
 				Name:          name,
 				Role:          string(localUser.Role),
+				CustomRoleID:  localUser.CustomRoleID,
 				AutoGroups:    localUser.AutoGroups,
 				Status:        string(types.UserStatusActive),
 				IsServiceUser: localUser.IsServiceUser,
@@ -1328,7 +1432,7 @@ func (am *DefaultAccountManager) GetCurrentUserInfo(ctx context.Context, userAut
 		Restricted: !userAuth.IsChild && user.IsRestrictable() && settings.RegularUsersViewBlocked,
 	}
 
-	permissions, err := am.permissionsManager.GetPermissionsByRole(ctx, user.Role)
+	permissions, err := am.permissionsManager.GetUserPermissions(ctx, user)
 	if err == nil {
 		userWithPermissions.Permissions = permissions
 	}
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"net/netip"
 	"os"
 	"reflect"
 	"testing"
@@ -14,8 +15,10 @@ import (
 
 	"github.com/netbirdio/netbird/management/internals/controllers/network_map"
 	nbcache "github.com/netbirdio/netbird/management/server/cache"
+	nbcontext "github.com/netbirdio/netbird/management/server/context"
 	"github.com/netbirdio/netbird/management/server/permissions"
 	"github.com/netbirdio/netbird/management/server/permissions/modules"
+	"github.com/netbirdio/netbird/management/server/permissions/operations"
 	"github.com/netbirdio/netbird/management/server/permissions/roles"
 	"github.com/netbirdio/netbird/management/server/users"
 	"github.com/netbirdio/netbird/management/server/util"
@@ -74,7 +77,7 @@ func TestUser_CreatePAT_ForSameUser(t *testing.T) {
 		permissionsManager: permissionsManager,
 	}
 
-	pat, err := am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockTokenName, mockExpiresIn)
+	pat, err := am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockTokenName, mockExpiresIn, nil)
 	if err != nil {
 		t.Fatalf("Error when adding PAT to user: %s", err)
 	}
@@ -124,7 +127,7 @@ func TestUser_CreatePAT_ForDifferentUser(t *testing.T) {
 		permissionsManager: permissionsManager,
 	}
 
-	_, err = am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockTargetUserId, mockTokenName, mockExpiresIn)
+	_, err = am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockTargetUserId, mockTokenName, mockExpiresIn, nil)
 	assert.Errorf(t, err, "Creating PAT for different user should thorw error")
 }
 
@@ -152,7 +155,7 @@ func TestUser_CreatePAT_ForServiceUser(t *testing.T) {
 		permissionsManager: permissionsManager,
 	}
 
-	pat, err := am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockTargetUserId, mockTokenName, mockExpiresIn)
+	pat, err := am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockTargetUserId, mockTokenName, mockExpiresIn, nil)
 	if err != nil {
 		t.Fatalf("Error when adding PAT to user: %s", err)
 	}
@@ -181,7 +184,7 @@ func TestUser_CreatePAT_WithWrongExpiration(t *testing.T) {
 		permissionsManager: permissionsManager,
 	}
 
-	_, err = am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockTokenName, mockWrongExpiresIn)
+	_, err = am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockTokenName, mockWrongExpiresIn, nil)
 	assert.Errorf(t, err, "Wrong expiration should thorw error")
 }
 
@@ -206,10 +209,68 @@ func TestUser_CreatePAT_WithEmptyName(t *testing.T) {
 		permissionsManager: permissionsManager,
 	}
 
-	_, err = am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockEmptyTokenName, mockExpiresIn)
+	_, err = am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockEmptyTokenName, mockExpiresIn, nil)
 	assert.Errorf(t, err, "Wrong expiration should thorw error")
 }
 
+func TestUser_CreatePAT_WithScope(t *testing.T) {
+	s, cleanup, err := store.NewTestStoreFromSQL(context.Background(), "", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	account := newAccountWithId(context.Background(), mockAccountID, mockUserID, "", "", "", false)
+	require.NoError(t, s.SaveAccount(context.Background(), account))
+
+	am := DefaultAccountManager{
+		Store:              s,
+		eventStore:         &activity.InMemoryEventStore{},
+		permissionsManager: permissions.NewManager(s),
+	}
+
+	scope := &types.PATScope{
+		Permissions:  map[modules.Module]map[operations.Operation]bool{modules.SetupKeys: {operations.Read: true, operations.Create: true}},
+		SourceRanges: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	}
+	pat, err := am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockTokenName, mockExpiresIn, scope)
+	require.NoError(t, err)
+
+	user, err := am.Store.GetUserByPATID(context.Background(), store.LockingStrengthNone, pat.ID)
+	require.NoError(t, err)
+	storedPAT, err := am.Store.GetPATByID(context.Background(), store.LockingStrengthNone, user.Id, pat.ID)
+	require.NoError(t, err)
+	assert.Equal(t, scope, storedPAT.Scope)
+
+	// requests authenticated with the token can only do what the scope allows
+	ctx := nbcontext.SetUserAuthInContext(context.Background(), auth.UserAuth{UserId: mockUserID, AccountId: mockAccountID, IsPAT: true})
+	ctx = types.SetPATScopeInContext(ctx, storedPAT.Scope)
+
+	allowed, err := am.permissionsManager.ValidateUserPermissions(ctx, mockAccountID, mockUserID, modules.SetupKeys, operations.Create)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = am.permissionsManager.ValidateUserPermissions(ctx, mockAccountID, mockUserID, modules.Accounts, operations.Delete)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	_, err = am.CreatePAT(ctx, mockAccountID, mockUserID, mockUserID, mockTokenName, mockExpiresIn, nil)
+	require.Error(t, err)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.PermissionDenied, sErr.Type())
+
+	// invalid scopes are rejected
+	for _, invalid := range []*types.PATScope{
+		{Permissions: map[modules.Module]map[operations.Operation]bool{"unknown": {operations.Read: true}}},
+		{PeerGroups: []string{"unknown"}},
+	} {
+		_, err = am.CreatePAT(context.Background(), mockAccountID, mockUserID, mockUserID, mockTokenName, mockExpiresIn, invalid)
+		require.Error(t, err)
+		sErr, ok = status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, status.InvalidArgument, sErr.Type())
+	}
+}
+
 func TestUser_DeletePAT(t *testing.T) {
 	store, cleanup, err := store.NewTestStoreFromSQL(context.Background(), "", t.TempDir())
 	if err != nil {
@@ -364,8 +425,9 @@ func TestUser_Copy(t *testing.T) {
 			ID:              0,
 			IntegrationType: "test",
 		},
-		Email: "whatever@gmail.com",
-		Name:  "John Doe",
+		Email:        "whatever@gmail.com",
+		Name:         "John Doe",
+		CustomRoleID: "customRole",
 	}
 
 	err := validateStruct(user)
@@ -426,7 +488,7 @@ func TestUser_CreateServiceUser(t *testing.T) {
 		permissionsManager: permissionsManager,
 	}
 
-	user, err := am.createServiceUser(context.Background(), mockAccountID, mockUserID, mockRole, mockServiceUserName, false, []string{"group1", "group2"})
+	user, err := am.createServiceUser(context.Background(), mockAccountID, mockUserID, mockRole, "", mockServiceUserName, false, []string{"group1", "group2"})
 	if err != nil {
 		t.Fatalf("Error when creating service user: %s", err)
 	}
@@ -446,7 +508,7 @@ func TestUser_CreateServiceUser(t *testing.T) {
 	assert.True(t, user.IsServiceUser)
 	assert.Equal(t, "active", user.Status)
 
-	_, err = am.createServiceUser(context.Background(), mockAccountID, mockUserID, types.UserRoleOwner, mockServiceUserName, false, nil)
+	_, err = am.createServiceUser(context.Background(), mockAccountID, mockUserID, types.UserRoleOwner, "", mockServiceUserName, false, nil)
 	if err == nil {
 		t.Fatal("should return error when creating service user with owner role")
 	}
@@ -1356,6 +1418,141 @@ func TestDefaultAccountManager_SaveUser(t *testing.T) {
 	}
 }
 
+func TestDefaultAccountManager_SaveUser_IntegrationNameAndEmail(t *testing.T) {
+	manager, _, err := createManager(t)
+	require.NoError(t, err)
+
+	ownerUserID := "ownerUser"
+	regularUserID := "regularUser"
+
+	account, err := manager.GetOrCreateAccountByUser(context.Background(), auth.UserAuth{UserId: ownerUserID, Domain: "netbird.io"})
+	require.NoError(t, err)
+
+	regularUser := types.NewRegularUser(regularUserID, "stored@netbird.io", "Stored Name")
+	account.Users[regularUserID] = regularUser
+	require.NoError(t, manager.Store.SaveAccount(context.Background(), account))
+
+	// API updates don't change the name and email
+	update := regularUser.Copy()
+	update.Name = "API Name"
+	update.Email = "api@netbird.io"
+	_, err = manager.SaveUser(context.Background(), account.Id, ownerUserID, update)
+	require.NoError(t, err)
+
+	user, err := manager.Store.GetUserByUserID(context.Background(), store.LockingStrengthNone, regularUserID)
+	require.NoError(t, err)
+	assert.Equal(t, "Stored Name", user.Name)
+	assert.Equal(t, "stored@netbird.io", user.Email)
+
+	// integrations keep them in sync, empty values are ignored
+	update = regularUser.Copy()
+	update.Issued = types.UserIssuedIntegration
+	update.Name = "Provisioned Name"
+	update.Email = ""
+	_, err = manager.SaveUser(context.Background(), account.Id, ownerUserID, update)
+	require.NoError(t, err)
+
+	user, err = manager.Store.GetUserByUserID(context.Background(), store.LockingStrengthNone, regularUserID)
+	require.NoError(t, err)
+	assert.Equal(t, "Provisioned Name", user.Name)
+	assert.Equal(t, "stored@netbird.io", user.Email)
+}
+
+func TestDefaultAccountManager_SaveUser_CustomRole(t *testing.T) {
+	manager, _, err := createManager(t)
+	require.NoError(t, err)
+
+	ownerUserID := "ownerUser"
+	regularUserID := "regularUser"
+
+	account, err := manager.GetOrCreateAccountByUser(context.Background(), auth.UserAuth{UserId: ownerUserID, Domain: "netbird.io"})
+	require.NoError(t, err)
+
+	regularUser := types.NewRegularUser(regularUserID, "", "")
+	account.Users[regularUserID] = regularUser
+	require.NoError(t, manager.Store.SaveAccount(context.Background(), account))
+
+	customRole := roles.NewCustomRole(account.Id, "helpdesk", "", roles.Permissions{
+		modules.Peers: {operations.Read: true, operations.Update: true},
+		modules.Users: {operations.Read: true, operations.Create: true, operations.Update: true},
+	})
+	require.NoError(t, manager.Store.SaveCustomRole(context.Background(), customRole))
+
+	t.Run("custom roles require the user role", func(t *testing.T) {
+		update := regularUser.Copy()
+		update.Role = types.UserRoleAdmin
+		update.CustomRoleID = customRole.ID
+		_, err = manager.SaveUser(context.Background(), account.Id, ownerUserID, update)
+		require.Error(t, err)
+		sErr, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, status.InvalidArgument, sErr.Type())
+	})
+
+	t.Run("unknown custom role", func(t *testing.T) {
+		update := regularUser.Copy()
+		update.CustomRoleID = "unknown"
+		_, err = manager.SaveUser(context.Background(), account.Id, ownerUserID, update)
+		require.Error(t, err)
+		sErr, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, status.InvalidArgument, sErr.Type())
+	})
+
+	t.Run("custom role permissions are enforced", func(t *testing.T) {
+		update := regularUser.Copy()
+		update.CustomRoleID = customRole.ID
+		_, err = manager.SaveUser(context.Background(), account.Id, ownerUserID, update)
+		require.NoError(t, err)
+
+		allowed, err := manager.permissionsManager.ValidateUserPermissions(context.Background(), account.Id, regularUserID, modules.Peers, operations.Update)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+
+		allowed, err = manager.permissionsManager.ValidateUserPermissions(context.Background(), account.Id, regularUserID, modules.Networks, operations.Read)
+		require.NoError(t, err)
+		assert.False(t, allowed)
+	})
+
+	t.Run("custom role users can't demote or block the owner", func(t *testing.T) {
+		owner, err := manager.Store.GetUserByUserID(context.Background(), store.LockingStrengthNone, ownerUserID)
+		require.NoError(t, err)
+
+		update := owner.Copy()
+		update.Role = types.UserRoleUser
+		_, err = manager.SaveUser(context.Background(), account.Id, regularUserID, update)
+		require.Error(t, err)
+		sErr, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, status.PermissionDenied, sErr.Type())
+
+		update = owner.Copy()
+		update.Blocked = true
+		_, err = manager.SaveUser(context.Background(), account.Id, regularUserID, update)
+		require.Error(t, err)
+		sErr, ok = status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, status.PermissionDenied, sErr.Type())
+
+		owner, err = manager.Store.GetUserByUserID(context.Background(), store.LockingStrengthNone, ownerUserID)
+		require.NoError(t, err)
+		assert.Equal(t, types.UserRoleOwner, owner.Role)
+		assert.False(t, owner.Blocked)
+	})
+
+	t.Run("custom role users can't assign roles", func(t *testing.T) {
+		update, err := manager.Store.GetUserByUserID(context.Background(), store.LockingStrengthNone, regularUserID)
+		require.NoError(t, err)
+		update.Role = types.UserRoleAdmin
+		update.CustomRoleID = ""
+		_, err = manager.SaveUser(context.Background(), account.Id, regularUserID, update)
+		require.Error(t, err)
+		sErr, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, status.PermissionDenied, sErr.Type())
+	})
+}
+
 func TestUserAccountPeersUpdate(t *testing.T) {
 	// account groups propagation is enabled
 	manager, updateManager, account, peer1, peer2, peer3 := setupNetworkMapTest(t)
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
 	"sync"
@@ -49,6 +50,18 @@ type Config struct {
 	EnableSTUN   bool
 	STUNPorts    []int
 	STUNLogLevel string
+	// TURN server configuration, the TURN server uses the STUN UDP ports
+	EnableTURN   bool
+	TURNSecret   string
+	TURNRealm    string
+	TURNRelayIP  string
+	TURNMinPort  int
+	TURNMaxPort  int
+	TURNTCPPorts []int
+	TURNTLSPorts []int
+	// TURNAllowedPeers and TURNDeniedPeers are CIDRs the TURN clients may or may not relay to
+	TURNAllowedPeers []string
+	TURNDeniedPeers  []string
 }
 
 func (c Config) Validate() error {
@@ -77,6 +90,52 @@ func (c Config) Validate() error {
 		}
 	}
 
+	if c.EnableTURN {
+		if err := c.validateTURN(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c Config) validateTURN() error {
+	if !c.EnableSTUN {
+		return fmt.Errorf("--enable-stun is required when --enable-turn is set")
+	}
+	if c.TURNSecret == "" {
+		return fmt.Errorf("--turn-secret is required when --enable-turn is set")
+	}
+	relayIP := net.ParseIP(c.TURNRelayIP)
+	if relayIP == nil || relayIP.IsUnspecified() {
+		return fmt.Errorf("--turn-relay-ip must be the public IP address of the server when --enable-turn is set")
+	}
+	if relayIP.To4() == nil {
+		return fmt.Errorf("--turn-relay-ip must be an IPv4 address, IPv6 relay allocations are not supported")
+	}
+	if _, err := parsePrefixes(c.TURNAllowedPeers); err != nil {
+		return fmt.Errorf("invalid --turn-allowed-peers: %w", err)
+	}
+	if _, err := parsePrefixes(c.TURNDeniedPeers); err != nil {
+		return fmt.Errorf("invalid --turn-denied-peers: %w", err)
+	}
+	if c.TURNMinPort <= 0 || c.TURNMaxPort > 65535 || c.TURNMinPort > c.TURNMaxPort {
+		return fmt.Errorf("invalid TURN relay port range %d-%d", c.TURNMinPort, c.TURNMaxPort)
+	}
+	if len(c.TURNTLSPorts) > 0 && !c.HasCertConfig() && !c.HasLetsEncrypt() {
+		return fmt.Errorf("--turn-tls-ports requires a TLS certificate or Let's Encrypt")
+	}
+
+	seen := make(map[int]bool)
+	for _, port := range append(append([]int{}, c.TURNTCPPorts...), c.TURNTLSPorts...) {
+		if port <= 0 || port > 65535 {
+			return fmt.Errorf("invalid TURN port %d: must be between 1 and 65535", port)
+		}
+		if seen[port] {
+			return fmt.Errorf("duplicate TURN port %d", port)
+		}
+		seen[port] = true
+	}
 	return nil
 }
 
@@ -119,6 +178,16 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&cobraConfig.EnableSTUN, "enable-stun", false, "enable embedded STUN server")
 	rootCmd.PersistentFlags().IntSliceVar(&cobraConfig.STUNPorts, "stun-ports", []int{3478}, "ports for the embedded STUN server (can be specified multiple times or comma-separated)")
 	rootCmd.PersistentFlags().StringVar(&cobraConfig.STUNLogLevel, "stun-log-level", "info", "log level for STUN server (panic, fatal, error, warn, info, debug, trace)")
+	rootCmd.PersistentFlags().BoolVar(&cobraConfig.EnableTURN, "enable-turn", false, "enable TURN relaying on the embedded STUN server ports. Requires --enable-stun")
+	rootCmd.PersistentFlags().StringVar(&cobraConfig.TURNSecret, "turn-secret", "", "secret shared with the Management service to validate the time-based TURN credentials")
+	rootCmd.PersistentFlags().StringVar(&cobraConfig.TURNRealm, "turn-realm", stun.DefaultTURNRealm, "TURN authentication realm")
+	rootCmd.PersistentFlags().StringVar(&cobraConfig.TURNRelayIP, "turn-relay-ip", "", "public IP address of the server the TURN relay allocations are advertised on")
+	rootCmd.PersistentFlags().IntVar(&cobraConfig.TURNMinPort, "turn-min-port", stun.DefaultTURNMinPort, "lowest port of the TURN relay allocations")
+	rootCmd.PersistentFlags().IntVar(&cobraConfig.TURNMaxPort, "turn-max-port", stun.DefaultTURNMaxPort, "highest port of the TURN relay allocations")
+	rootCmd.PersistentFlags().IntSliceVar(&cobraConfig.TURNTCPPorts, "turn-tcp-ports", nil, "ports for TURN over TCP (can be specified multiple times or comma-separated)")
+	rootCmd.PersistentFlags().StringSliceVar(&cobraConfig.TURNAllowedPeers, "turn-allowed-peers", nil, "CIDRs TURN clients may relay to although private and shared (100.64.0.0/10) networks are denied by default (can be specified multiple times or comma-separated)")
+	rootCmd.PersistentFlags().StringSliceVar(&cobraConfig.TURNDeniedPeers, "turn-denied-peers", nil, "additional CIDRs TURN clients may not relay to, take precedence over --turn-allowed-peers (can be specified multiple times or comma-separated)")
+	rootCmd.PersistentFlags().IntSliceVar(&cobraConfig.TURNTLSPorts, "turn-tls-ports", nil, "ports for TURN over TLS, uses the relay TLS certificate (can be specified multiple times or comma-separated)")
 
 	setFlagsFromEnvVars(rootCmd)
 }
@@ -188,6 +257,12 @@ func execute(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	turnListeners, err := createTURNListeners(tlsConfig)
+	if err != nil {
+		cleanupSTUNListeners(stunListeners)
+		return err
+	}
+
 	hCfg := healthcheck.Config{
 		ListenAddress:  cobraConfig.HealthcheckListenAddress,
 		ServiceChecker: srv,
@@ -195,12 +270,15 @@ func execute(cmd *cobra.Command, args []string) error {
 	httpHealthcheck, err := createHealthCheck(hCfg)
 	if err != nil {
 		cleanupSTUNListeners(stunListeners)
+		cleanupTURNListeners(turnListeners)
 		return err
 	}
 
-	var stunServer *stun.Server
-	if len(stunListeners) > 0 {
-		stunServer = stun.NewServer(stunListeners, cobraConfig.STUNLogLevel)
+	stunServer, err := createSTUNServer(stunListeners, turnListeners)
+	if err != nil {
+		cleanupSTUNListeners(stunListeners)
+		cleanupTURNListeners(turnListeners)
+		return err
 	}
 
 	// Start all servers (only after all resources are successfully created)
@@ -323,6 +401,87 @@ func createSTUNListeners() ([]*net.UDPConn, error) {
 	return stunListeners, nil
 }
 
+func cleanupTURNListeners(turnListeners []net.Listener) {
+	for _, l := range turnListeners {
+		_ = l.Close()
+	}
+}
+
+func createTURNListeners(tlsConfig *tls.Config) ([]net.Listener, error) {
+	var turnListeners []net.Listener
+	if !cobraConfig.EnableTURN {
+		return turnListeners, nil
+	}
+
+	for _, port := range cobraConfig.TURNTCPPorts {
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err != nil {
+			cleanupTURNListeners(turnListeners)
+			log.Debugf("failed to create TURN TCP listener on port %d: %v", port, err)
+			return nil, fmt.Errorf("failed to create TURN TCP listener on port %d: %v", port, err)
+		}
+		turnListeners = append(turnListeners, listener)
+	}
+
+	for _, port := range cobraConfig.TURNTLSPorts {
+		listener, err := tls.Listen("tcp", fmt.Sprintf(":%d", port), tlsConfig)
+		if err != nil {
+			cleanupTURNListeners(turnListeners)
+			log.Debugf("failed to create TURN TLS listener on port %d: %v", port, err)
+			return nil, fmt.Errorf("failed to create TURN TLS listener on port %d: %v", port, err)
+		}
+		turnListeners = append(turnListeners, listener)
+	}
+	return turnListeners, nil
+}
+
+func createSTUNServer(stunListeners []*net.UDPConn, turnListeners []net.Listener) (*stun.Server, error) {
+	if len(stunListeners) == 0 {
+		return nil, nil
+	}
+
+	if !cobraConfig.EnableTURN {
+		return stun.NewServer(stunListeners, cobraConfig.STUNLogLevel), nil
+	}
+
+	allowedPeers, err := parsePrefixes(cobraConfig.TURNAllowedPeers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TURN allowed peers: %w", err)
+	}
+	deniedPeers, err := parsePrefixes(cobraConfig.TURNDeniedPeers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TURN denied peers: %w", err)
+	}
+
+	turnCfg := stun.TURNConfig{
+		Realm:        cobraConfig.TURNRealm,
+		Secret:       cobraConfig.TURNSecret,
+		RelayIP:      net.ParseIP(cobraConfig.TURNRelayIP),
+		MinPort:      uint16(cobraConfig.TURNMinPort),
+		MaxPort:      uint16(cobraConfig.TURNMaxPort),
+		Listeners:    turnListeners,
+		AllowedPeers: allowedPeers,
+		DeniedPeers:  deniedPeers,
+	}
+	stunServer, err := stun.NewTURNServer(stunListeners, turnCfg, cobraConfig.STUNLogLevel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TURN server: %w", err)
+	}
+	return stunServer, nil
+}
+
+func parsePrefixes(cidrs []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
 func handleTLSConfig(cfg *Config) (*tls.Config, bool, error) {
 	if cfg.LetsencryptAWSRoute53 {
 		log.Debugf("using Let's Encrypt DNS resolver with Route 53 support")
@@ -0,0 +1,80 @@
+package route
+
+import (
+	"net/netip"
+	"slices"
+
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+const (
+	// asTrans is reserved for speakers without 4-octet AS number support (RFC 6793) and can't be configured
+	asTrans = 23456
+	// maxBGPNeighbors limits the number of LAN routers a routing peer keeps sessions with
+	maxBGPNeighbors = 8
+)
+
+// BGP configures the routing peer to announce the NetBird network range to routers in its LAN, so they don't need static
+// routes pointing back to the routing peer. Routes that aren't masqueraded also announce the networks reachable through
+// NetBird, the return paths of site-to-site traffic. The announcements are withdrawn when the routing peer stops serving
+// the route.
+type BGP struct {
+	// LocalASN is the AS number of the routing peer
+	LocalASN uint32
+	// Neighbors are the LAN routers the routing peer opens BGP sessions to
+	Neighbors []BGPNeighbor
+}
+
+// BGPNeighbor is a LAN router the routing peer announces the routes to
+type BGPNeighbor struct {
+	Address netip.Addr
+	ASN     uint32
+}
+
+// Validate checks the AS numbers and that the neighbors are unique IPv4 addresses
+func (b *BGP) Validate() error {
+	if !isValidASN(b.LocalASN) {
+		return status.Errorf(status.InvalidArgument, "invalid BGP local AS number %d", b.LocalASN)
+	}
+	if len(b.Neighbors) == 0 || len(b.Neighbors) > maxBGPNeighbors {
+		return status.Errorf(status.InvalidArgument, "BGP requires between 1 and %d neighbors", maxBGPNeighbors)
+	}
+
+	seen := make(map[netip.Addr]struct{}, len(b.Neighbors))
+	for _, neighbor := range b.Neighbors {
+		if !neighbor.Address.Is4() || neighbor.Address.IsUnspecified() {
+			return status.Errorf(status.InvalidArgument, "BGP neighbor address should be an IPv4 address, got %s", neighbor.Address)
+		}
+		if !isValidASN(neighbor.ASN) {
+			return status.Errorf(status.InvalidArgument, "invalid AS number %d of BGP neighbor %s", neighbor.ASN, neighbor.Address)
+		}
+		if _, ok := seen[neighbor.Address]; ok {
+			return status.Errorf(status.InvalidArgument, "duplicate BGP neighbor %s", neighbor.Address)
+		}
+		seen[neighbor.Address] = struct{}{}
+	}
+	return nil
+}
+
+// Copy returns a copy of the BGP configuration
+func (b *BGP) Copy() *BGP {
+	if b == nil {
+		return nil
+	}
+	return &BGP{
+		LocalASN:  b.LocalASN,
+		Neighbors: slices.Clone(b.Neighbors),
+	}
+}
+
+// Equal compares one BGP configuration with the other
+func (b *BGP) Equal(other *BGP) bool {
+	if b == nil || other == nil {
+		return b == other
+	}
+	return b.LocalASN == other.LocalASN && slices.Equal(b.Neighbors, other.Neighbors)
+}
+
+func isValidASN(asn uint32) bool {
+	return asn != 0 && asn != asTrans && asn != 65535 && asn != 4294967295
+}
@@ -0,0 +1,102 @@
+package route
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// HealthCheckType is the kind of probe a routing peer runs against a target in the routed network
+type HealthCheckType string
+
+const (
+	// HealthCheckICMP sends ICMP echo requests to the target IP
+	HealthCheckICMP HealthCheckType = "icmp"
+	// HealthCheckTCP opens a TCP connection to the target host:port
+	HealthCheckTCP HealthCheckType = "tcp"
+	// HealthCheckHTTP sends a GET request to the target URL and expects a 2xx or 3xx response
+	HealthCheckHTTP HealthCheckType = "http"
+)
+
+const (
+	// DefaultHealthCheckInterval is used when a health check doesn't define an interval
+	DefaultHealthCheckInterval = 10 * time.Second
+	// DefaultHealthCheckTimeout is used when a health check doesn't define a timeout
+	DefaultHealthCheckTimeout = 3 * time.Second
+)
+
+// HealthCheck is executed by the routing peer to verify that it can reach the routed network.
+// Clients prefer other routing peers of the same network while the check of a routing peer fails.
+type HealthCheck struct {
+	Type HealthCheckType
+	// Target is an IP address for icmp, a host:port for tcp and a URL for http checks
+	Target   string
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+// NewHealthCheck returns a health check with the default interval and timeout applied if they are zero
+func NewHealthCheck(checkType HealthCheckType, target string, interval, timeout time.Duration) *HealthCheck {
+	hc := &HealthCheck{
+		Type:     checkType,
+		Target:   target,
+		Interval: interval,
+		Timeout:  timeout,
+	}
+	if hc.Interval == 0 {
+		hc.Interval = DefaultHealthCheckInterval
+	}
+	if hc.Timeout == 0 {
+		hc.Timeout = DefaultHealthCheckTimeout
+	}
+	return hc
+}
+
+// Validate checks that the target matches the check type and that the timings are usable
+func (h *HealthCheck) Validate() error {
+	if h.Interval < time.Second {
+		return status.Errorf(status.InvalidArgument, "health check interval should be at least 1s")
+	}
+	if h.Timeout < time.Second || h.Timeout > h.Interval {
+		return status.Errorf(status.InvalidArgument, "health check timeout should be between 1s and the interval")
+	}
+
+	switch h.Type {
+	case HealthCheckICMP:
+		if _, err := netip.ParseAddr(h.Target); err != nil {
+			return status.Errorf(status.InvalidArgument, "icmp health check target should be an IP address, got %q", h.Target)
+		}
+	case HealthCheckTCP:
+		if _, _, err := net.SplitHostPort(h.Target); err != nil {
+			return status.Errorf(status.InvalidArgument, "tcp health check target should be host:port, got %q", h.Target)
+		}
+	case HealthCheckHTTP:
+		u, err := url.Parse(h.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return status.Errorf(status.InvalidArgument, "http health check target should be an http or https URL, got %q", h.Target)
+		}
+	default:
+		return status.Errorf(status.InvalidArgument, "unknown health check type %q", h.Type)
+	}
+	return nil
+}
+
+// Copy returns a copy of the health check
+func (h *HealthCheck) Copy() *HealthCheck {
+	if h == nil {
+		return nil
+	}
+	hc := *h
+	return &hc
+}
+
+// Equal compares one health check with the other
+func (h *HealthCheck) Equal(other *HealthCheck) bool {
+	if h == nil || other == nil {
+		return h == other
+	}
+	return *h == *other
+}
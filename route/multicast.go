@@ -0,0 +1,102 @@
+package route
+
+import (
+	"net/netip"
+	"slices"
+
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// maxMulticastGroups limits the number of additional multicast groups a route relays
+const maxMulticastGroups = 16
+
+var (
+	// MDNSGroup is the multicast group and port of mDNS
+	MDNSGroup = netip.MustParseAddrPort("224.0.0.251:5353")
+	// SSDPGroup is the multicast group and port of SSDP
+	SSDPGroup = netip.MustParseAddrPort("239.255.255.250:1900")
+)
+
+// Multicast configures the routing peer to relay multicast traffic between its LAN and the peers using the route and to
+// forward the directed broadcasts of the peers into the routed network. The relayed traffic is subject to the route's
+// access control.
+type Multicast struct {
+	// MDNS relays mDNS
+	MDNS bool
+	// SSDP relays SSDP
+	SSDP bool
+	// Groups are additional IPv4 multicast groups with their UDP port
+	Groups []netip.AddrPort
+	// Broadcast forwards directed broadcasts of the peers, e.g. Wake-on-LAN packets, into the routed network
+	Broadcast bool
+}
+
+// Validate checks that something is relayed and that the groups are unique IPv4 multicast addresses
+func (m *Multicast) Validate() error {
+	if !m.MDNS && !m.SSDP && !m.Broadcast && len(m.Groups) == 0 {
+		return status.Errorf(status.InvalidArgument, "multicast requires mDNS, SSDP, broadcast or at least one group")
+	}
+	if len(m.Groups) > maxMulticastGroups {
+		return status.Errorf(status.InvalidArgument, "multicast supports up to %d groups", maxMulticastGroups)
+	}
+
+	seen := make(map[netip.AddrPort]struct{}, len(m.Groups))
+	for _, group := range m.Groups {
+		if !group.Addr().Is4() || !group.Addr().IsMulticast() {
+			return status.Errorf(status.InvalidArgument, "multicast group should be an IPv4 multicast address, got %s", group.Addr())
+		}
+		if group.Port() == 0 {
+			return status.Errorf(status.InvalidArgument, "multicast group %s requires a port", group.Addr())
+		}
+		if _, ok := seen[group]; ok {
+			return status.Errorf(status.InvalidArgument, "duplicate multicast group %s", group)
+		}
+		seen[group] = struct{}{}
+	}
+	return nil
+}
+
+// RelayedGroups returns the multicast groups relayed by the route, including mDNS and SSDP
+func (m *Multicast) RelayedGroups() []netip.AddrPort {
+	if m == nil {
+		return nil
+	}
+
+	var groups []netip.AddrPort
+	if m.MDNS {
+		groups = append(groups, MDNSGroup)
+	}
+	if m.SSDP {
+		groups = append(groups, SSDPGroup)
+	}
+	for _, group := range m.Groups {
+		if !slices.Contains(groups, group) {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// Copy returns a copy of the multicast configuration
+func (m *Multicast) Copy() *Multicast {
+	if m == nil {
+		return nil
+	}
+	return &Multicast{
+		MDNS:      m.MDNS,
+		SSDP:      m.SSDP,
+		Groups:    slices.Clone(m.Groups),
+		Broadcast: m.Broadcast,
+	}
+}
+
+// Equal compares one multicast configuration with the other
+func (m *Multicast) Equal(other *Multicast) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+	return m.MDNS == other.MDNS &&
+		m.SSDP == other.SSDP &&
+		m.Broadcast == other.Broadcast &&
+		slices.Equal(m.Groups, other.Groups)
+}
@@ -0,0 +1,98 @@
+package route
+
+import (
+	"net/netip"
+
+	"github.com/netbirdio/netbird/shared/management/status"
+)
+
+// NetMap is a 1:1 mapping (netmap) of a real prefix in the routed network onto a virtual prefix of the same size.
+// Clients route the virtual prefix, the routing peer translates destinations from the virtual to the real prefix
+// and sources of traffic leaving the routed network from the real to the virtual prefix.
+// This allows routing sites that use overlapping subnets.
+type NetMap struct {
+	// Real is the prefix in the routed network, it matches the route network for network routes
+	Real netip.Prefix
+	// Virtual is the prefix clients use to reach the real prefix
+	Virtual netip.Prefix
+}
+
+// NewNetMap returns a netmap with both prefixes masked
+func NewNetMap(realPrefix, virtualPrefix netip.Prefix) *NetMap {
+	return &NetMap{
+		Real:    realPrefix.Masked(),
+		Virtual: virtualPrefix.Masked(),
+	}
+}
+
+// Validate checks that both prefixes are valid IPv4 prefixes of the same size that don't overlap
+func (n *NetMap) Validate() error {
+	if !n.Real.IsValid() || !n.Virtual.IsValid() {
+		return status.Errorf(status.InvalidArgument, "netmap requires a real and a virtual prefix")
+	}
+	if !n.Real.Addr().Is4() || !n.Virtual.Addr().Is4() {
+		return status.Errorf(status.InvalidArgument, "netmap only supports IPv4 prefixes")
+	}
+	if n.Real.Bits() != n.Virtual.Bits() {
+		return status.Errorf(status.InvalidArgument, "netmap prefixes should be of the same size, got %s and %s", n.Real, n.Virtual)
+	}
+	if n.Real.Overlaps(n.Virtual) {
+		return status.Errorf(status.InvalidArgument, "netmap virtual prefix %s should not overlap the real prefix %s", n.Virtual, n.Real)
+	}
+	return nil
+}
+
+// ToVirtual maps an address of the real prefix onto the virtual prefix.
+// It returns false if the address is not part of the real prefix.
+func (n *NetMap) ToVirtual(addr netip.Addr) (netip.Addr, bool) {
+	return mapAddr(addr.Unmap(), n.Real, n.Virtual)
+}
+
+// ToReal maps an address of the virtual prefix onto the real prefix.
+// It returns false if the address is not part of the virtual prefix.
+func (n *NetMap) ToReal(addr netip.Addr) (netip.Addr, bool) {
+	return mapAddr(addr.Unmap(), n.Virtual, n.Real)
+}
+
+// Copy returns a copy of the netmap
+func (n *NetMap) Copy() *NetMap {
+	if n == nil {
+		return nil
+	}
+	nm := *n
+	return &nm
+}
+
+// Equal compares one netmap with the other
+func (n *NetMap) Equal(other *NetMap) bool {
+	if n == nil || other == nil {
+		return n == other
+	}
+	return *n == *other
+}
+
+// mapAddr keeps the host bits of addr and replaces its network bits with the ones of the target prefix
+func mapAddr(addr netip.Addr, from, to netip.Prefix) (netip.Addr, bool) {
+	if !from.Contains(addr) || from.Bits() != to.Bits() || addr.BitLen() != to.Addr().BitLen() {
+		return addr, false
+	}
+
+	src := addr.AsSlice()
+	dst := to.Masked().Addr().AsSlice()
+	bits := to.Bits()
+	for i := range dst {
+		switch {
+		case bits >= 8:
+			bits -= 8
+		case bits > 0:
+			mask := byte(0xff) >> bits
+			dst[i] = dst[i]&^mask | src[i]&mask
+			bits = 0
+		default:
+			dst[i] = src[i]
+		}
+	}
+
+	mapped, _ := netip.AddrFromSlice(dst)
+	return mapped, true
+}
@@ -109,6 +109,29 @@ type Route struct {
 	AccessControlGroups []string `gorm:"serializer:json"`
 	// SkipAutoApply indicates if this exit node route (0.0.0.0/0) should skip auto-application for client routing
 	SkipAutoApply bool
+	// HealthCheck is run by the routing peer against a target in the routed network
+	HealthCheck *HealthCheck `gorm:"serializer:json"`
+	// Unhealthy is set in the network map when the routing peer reports a failing health check for the route
+	Unhealthy bool `gorm:"-"`
+	// NetMap maps the real prefix of the routed network 1:1 onto a virtual prefix for overlapping site subnets
+	NetMap *NetMap `gorm:"serializer:json"`
+	// LoadBalance distributes the traffic to the routed network across all available routing peers of the HA group
+	// instead of choosing a single one. The traffic is split by destination address, not per flow.
+	LoadBalance bool
+	// BGP announces the NetBird network range and, without masquerading, the networks reachable through NetBird to the
+	// LAN of the routing peer
+	BGP *BGP `gorm:"serializer:json"`
+	// Multicast relays multicast traffic between the LAN of the routing peer and the peers using the route
+	Multicast *Multicast `gorm:"serializer:json"`
+}
+
+// Options are the optional settings of a route, they are copied to the route on creation
+type Options struct {
+	HealthCheck *HealthCheck
+	NetMap      *NetMap
+	LoadBalance bool
+	BGP         *BGP
+	Multicast   *Multicast
 }
 
 // EventMeta returns activity event meta related to the route
@@ -140,6 +163,12 @@ func (r *Route) Copy() *Route {
 		Groups:              slices.Clone(r.Groups),
 		AccessControlGroups: slices.Clone(r.AccessControlGroups),
 		SkipAutoApply:       r.SkipAutoApply,
+		HealthCheck:         r.HealthCheck.Copy(),
+		Unhealthy:           r.Unhealthy,
+		NetMap:              r.NetMap.Copy(),
+		LoadBalance:         r.LoadBalance,
+		BGP:                 r.BGP.Copy(),
+		Multicast:           r.Multicast.Copy(),
 	}
 	return route
 }
@@ -167,7 +196,12 @@ func (r *Route) Equal(other *Route) bool {
 		slices.Equal(r.Groups, other.Groups) &&
 		slices.Equal(r.PeerGroups, other.PeerGroups) &&
 		slices.Equal(r.AccessControlGroups, other.AccessControlGroups) &&
-		other.SkipAutoApply == r.SkipAutoApply
+		other.SkipAutoApply == r.SkipAutoApply &&
+		r.HealthCheck.Equal(other.HealthCheck) &&
+		r.NetMap.Equal(other.NetMap) &&
+		other.LoadBalance == r.LoadBalance &&
+		r.BGP.Equal(other.BGP) &&
+		r.Multicast.Equal(other.Multicast)
 }
 
 // IsDynamic returns if the route is dynamic, i.e. has domains
@@ -347,6 +347,48 @@ func TestClient_Sync(t *testing.T) {
 	}
 }
 
+// syncRecordingClient records the Sync requests instead of opening a stream
+type syncRecordingClient struct {
+	mgmtProto.ManagementServiceClient
+	syncRequests []*mgmtProto.EncryptedMessage
+}
+
+func (c *syncRecordingClient) Sync(_ context.Context, in *mgmtProto.EncryptedMessage, _ ...grpc.CallOption) (mgmtProto.ManagementService_SyncClient, error) {
+	c.syncRequests = append(c.syncRequests, in)
+	return nil, nil
+}
+
+func TestClient_SyncStreamSendsLatestMeta(t *testing.T) {
+	s, lis, _, serverKey := startMockManagement(t)
+	defer s.GracefulStop()
+
+	testKey, err := wgtypes.GenerateKey()
+	require.NoError(t, err)
+
+	testClient, err := NewClient(context.Background(), lis.Addr().String(), testKey, false)
+	require.NoError(t, err)
+	recorder := &syncRecordingClient{ManagementServiceClient: testClient.realClient}
+	testClient.realClient = recorder
+
+	info := &system.Info{Hostname: "initial"}
+	_, err = testClient.connectToSyncStream(context.Background(), serverKey.PublicKey(), info)
+	require.NoError(t, err)
+
+	// the mock server doesn't implement SyncMeta, the meta is still used by the next stream
+	_ = testClient.SyncMeta(&system.Info{Hostname: "initial", UnhealthyRoutes: []string{"route"}, DiscoveredSubnets: []string{"192.168.1.0/24"}})
+
+	_, err = testClient.connectToSyncStream(context.Background(), serverKey.PublicKey(), info)
+	require.NoError(t, err)
+	require.Len(t, recorder.syncRequests, 2)
+
+	var first, reconnected mgmtProto.SyncRequest
+	require.NoError(t, encryption.DecryptMessage(testKey.PublicKey(), serverKey, recorder.syncRequests[0].Body, &first))
+	require.NoError(t, encryption.DecryptMessage(testKey.PublicKey(), serverKey, recorder.syncRequests[1].Body, &reconnected))
+	assert.Empty(t, first.GetMeta().GetUnhealthyRoutes())
+	assert.Equal(t, []string{"route"}, reconnected.GetMeta().GetUnhealthyRoutes(), "a reconnected stream should send the latest meta")
+	assert.Equal(t, []string{"192.168.1.0/24"}, reconnected.GetMeta().GetDiscoveredSubnets())
+}
+
 func Test_SystemMetaDataFromClient(t *testing.T) {
 	s, lis, mgmtMockServer, serverKey := startMockManagement(t)
 	defer s.GracefulStop()
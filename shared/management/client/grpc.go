@@ -46,6 +46,11 @@ type GrpcClient struct {
 	conn                  *grpc.ClientConn
 	connStateCallback     ConnStateNotifier
 	connStateCallbackLock sync.RWMutex
+
+	// syncMeta is the latest system info sent with SyncMeta, it replaces the system info of the Sync call when the
+	// stream reconnects, so management doesn't fall back to outdated meta
+	syncMeta     *system.Info
+	syncMetaLock sync.Mutex
 }
 
 // NewClient creates a new client to Management service
@@ -385,6 +390,12 @@ func (c *GrpcClient) GetNetworkMap(sysInfo *system.Info) (*proto.NetworkMap, err
 }
 
 func (c *GrpcClient) connectToSyncStream(ctx context.Context, serverPubKey wgtypes.Key, sysInfo *system.Info) (proto.ManagementService_SyncClient, error) {
+	c.syncMetaLock.Lock()
+	if c.syncMeta != nil {
+		sysInfo = c.syncMeta
+	}
+	c.syncMetaLock.Unlock()
+
 	req := &proto.SyncRequest{Meta: infoToMetaData(sysInfo)}
 
 	myPrivateKey := c.key
@@ -615,7 +626,13 @@ func (c *GrpcClient) GetPKCEAuthorizationFlow(serverKey wgtypes.Key) (*proto.PKC
 
 // SyncMeta sends updated system metadata to the Management Service.
 // It should be used if there is changes on peer posture check after initial sync.
+// The metadata is also sent when the Sync stream reconnects.
 func (c *GrpcClient) SyncMeta(sysInfo *system.Info) error {
+	// kept even if sending fails, the stream sends it when it reconnects
+	c.syncMetaLock.Lock()
+	c.syncMeta = sysInfo
+	c.syncMetaLock.Unlock()
+
 	if !c.ready() {
 		return errors.New(errMsgNoMgmtConnection)
 	}
@@ -745,5 +762,7 @@ func infoToMetaData(info *system.Info) *proto.PeerSystemMeta {
 
 			LazyConnectionEnabled: info.LazyConnectionEnabled,
 		},
+		UnhealthyRoutes:   info.UnhealthyRoutes,
+		DiscoveredSubnets: info.DiscoveredSubnets,
 	}
 }
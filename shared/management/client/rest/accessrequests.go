@@ -0,0 +1,114 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+// AccessRequestsAPI APIs for temporary access requests, do not use directly
+type AccessRequestsAPI struct {
+	c *Client
+}
+
+// List list access requests, approvers get all requests of the account
+func (a *AccessRequestsAPI) List(ctx context.Context) ([]api.AccessRequest, error) {
+	resp, err := a.c.NewRequest(ctx, "GET", "/api/access-requests", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[[]api.AccessRequest](resp)
+	return ret, err
+}
+
+// Get get access request info
+func (a *AccessRequestsAPI) Get(ctx context.Context, requestID string) (*api.AccessRequest, error) {
+	resp, err := a.c.NewRequest(ctx, "GET", "/api/access-requests/"+requestID, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[api.AccessRequest](resp)
+	return &ret, err
+}
+
+// Create request temporary access to a network resource or group
+func (a *AccessRequestsAPI) Create(ctx context.Context, request api.PostApiAccessRequestsJSONRequestBody) (*api.AccessRequest, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	return a.post(ctx, "/api/access-requests", requestBytes)
+}
+
+// Approve approve access request, grants the access for the given duration
+func (a *AccessRequestsAPI) Approve(ctx context.Context, requestID string, request api.PostApiAccessRequestsRequestIdApproveJSONRequestBody) (*api.AccessRequest, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	return a.post(ctx, "/api/access-requests/"+requestID+"/approve", requestBytes)
+}
+
+// Reject reject access request
+func (a *AccessRequestsAPI) Reject(ctx context.Context, requestID string, request api.PostApiAccessRequestsRequestIdRejectJSONRequestBody) (*api.AccessRequest, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	return a.post(ctx, "/api/access-requests/"+requestID+"/reject", requestBytes)
+}
+
+// Revoke cancel pending access request or remove granted access
+func (a *AccessRequestsAPI) Revoke(ctx context.Context, requestID string) (*api.AccessRequest, error) {
+	return a.post(ctx, "/api/access-requests/"+requestID+"/revoke", nil)
+}
+
+// GetSettings get access request settings
+func (a *AccessRequestsAPI) GetSettings(ctx context.Context) (*api.AccessRequestSettings, error) {
+	resp, err := a.c.NewRequest(ctx, "GET", "/api/access-requests/settings", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[api.AccessRequestSettings](resp)
+	return &ret, err
+}
+
+// UpdateSettings update access request settings
+func (a *AccessRequestsAPI) UpdateSettings(ctx context.Context, request api.PutApiAccessRequestsSettingsJSONRequestBody) (*api.AccessRequestSettings, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.NewRequest(ctx, "PUT", "/api/access-requests/settings", bytes.NewReader(requestBytes), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[api.AccessRequestSettings](resp)
+	return &ret, err
+}
+
+func (a *AccessRequestsAPI) post(ctx context.Context, path string, requestBytes []byte) (*api.AccessRequest, error) {
+	resp, err := a.c.NewRequest(ctx, "POST", path, bytes.NewReader(requestBytes), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[api.AccessRequest](resp)
+	return &ret, err
+}
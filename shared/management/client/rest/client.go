@@ -73,6 +73,18 @@ type Client struct {
 	// Events NetBird Events APIs
 	// see more: https://docs.netbird.io/api/resources/events
 	Events *EventsAPI
+
+	// Declarative NetBird declarative configuration APIs
+	Declarative *DeclarativeAPI
+
+	// Tenants NetBird tenant APIs
+	Tenants *TenantsAPI
+
+	// AccessRequests NetBird temporary access request APIs
+	AccessRequests *AccessRequestsAPI
+
+	// Reports NetBird peer and user inventory report APIs
+	Reports *ReportsAPI
 }
 
 // New initialize new Client instance using PAT token
@@ -120,6 +132,10 @@ func (c *Client) initialize() {
 	c.DNSZones = &DNSZonesAPI{c}
 	c.GeoLocation = &GeoLocationAPI{c}
 	c.Events = &EventsAPI{c}
+	c.Declarative = &DeclarativeAPI{c}
+	c.Tenants = &TenantsAPI{c}
+	c.AccessRequests = &AccessRequestsAPI{c}
+	c.Reports = &ReportsAPI{c}
 }
 
 // NewRequest creates and executes new management API request
@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+// DeclarativeAPI APIs for declarative configuration, do not use directly
+type DeclarativeAPI struct {
+	c *Client
+}
+
+// Plan compute the changes needed to make the account match a declarative configuration
+func (a *DeclarativeAPI) Plan(ctx context.Context, request api.PostApiDeclarativePlanJSONRequestBody) (*api.DeclarativePlan, error) {
+	return a.post(ctx, "/api/declarative/plan", request)
+}
+
+// Apply apply a declarative configuration to the account
+func (a *DeclarativeAPI) Apply(ctx context.Context, request api.PostApiDeclarativeApplyJSONRequestBody) (*api.DeclarativePlan, error) {
+	return a.post(ctx, "/api/declarative/apply", request)
+}
+
+// GetDrift list managed objects that have been changed outside of the declarative configuration
+func (a *DeclarativeAPI) GetDrift(ctx context.Context) ([]api.DeclarativeDrift, error) {
+	resp, err := a.c.NewRequest(ctx, "GET", "/api/declarative/drift", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[[]api.DeclarativeDrift](resp)
+	return ret, err
+}
+
+func (a *DeclarativeAPI) post(ctx context.Context, path string, request api.DeclarativeConfig) (*api.DeclarativePlan, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.NewRequest(ctx, "POST", path, bytes.NewReader(requestBytes), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[api.DeclarativePlan](resp)
+	return &ret, err
+}
@@ -106,3 +106,29 @@ func (a *PeersAPI) ListAccessiblePeers(ctx context.Context, peerID string) ([]ap
 	ret, err := parseResponse[[]api.Peer](resp)
 	return ret, err
 }
+
+// GetNetworkMap retrieve a snapshot of the network map computed for a peer
+func (a *PeersAPI) GetNetworkMap(ctx context.Context, peerID string) (*api.NetworkMapSnapshot, error) {
+	resp, err := a.c.NewRequest(ctx, "GET", "/api/peers/"+peerID+"/network-map", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[api.NetworkMapSnapshot](resp)
+	return &ret, err
+}
+
+// ListNetworkMaps retrieve snapshots of the network maps computed for all peers of the account
+func (a *PeersAPI) ListNetworkMaps(ctx context.Context) ([]api.NetworkMapSnapshot, error) {
+	resp, err := a.c.NewRequest(ctx, "GET", "/api/network-maps", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[[]api.NetworkMapSnapshot](resp)
+	return ret, err
+}
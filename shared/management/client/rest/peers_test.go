@@ -209,3 +209,44 @@ func TestPeers_Integration(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestPeers_GetNetworkMap_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/peers/Test/network-map", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal(api.NetworkMapSnapshot{Peer: api.NetworkMapPeer{Id: "Test"}})
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Peers.GetNetworkMap(context.Background(), "Test")
+		require.NoError(t, err)
+		assert.Equal(t, "Test", ret.Peer.Id)
+	})
+}
+
+func TestPeers_GetNetworkMap_Err(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/peers/Test/network-map", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal(util.ErrorResponse{Message: "No", Code: 400})
+			w.WriteHeader(400)
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		_, err := c.Peers.GetNetworkMap(context.Background(), "Test")
+		assert.Error(t, err)
+		assert.Equal(t, "No", err.Error())
+	})
+}
+
+func TestPeers_ListNetworkMaps_200(t *testing.T) {
+	withMockClient(func(c *rest.Client, mux *http.ServeMux) {
+		mux.HandleFunc("/api/network-maps", func(w http.ResponseWriter, r *http.Request) {
+			retBytes, _ := json.Marshal([]api.NetworkMapSnapshot{{Peer: api.NetworkMapPeer{Id: "Test"}}})
+			_, err := w.Write(retBytes)
+			require.NoError(t, err)
+		})
+		ret, err := c.Peers.ListNetworkMaps(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, ret, 1)
+		assert.Equal(t, "Test", ret[0].Peer.Id)
+	})
+}
@@ -0,0 +1,30 @@
+package rest
+
+import (
+	"context"
+	"io"
+)
+
+// ReportsAPI APIs for peer and user inventory reports, do not use directly
+type ReportsAPI struct {
+	c *Client
+}
+
+// Peers export the peer inventory, query holds the report parameters e.g. format=json or connected=false
+func (a *ReportsAPI) Peers(ctx context.Context, query map[string]string) ([]byte, error) {
+	return a.get(ctx, "/api/reports/peers", query)
+}
+
+// Users export the user inventory, query holds the report parameters e.g. format=json or role=admin
+func (a *ReportsAPI) Users(ctx context.Context, query map[string]string) ([]byte, error) {
+	return a.get(ctx, "/api/reports/users", query)
+}
+
+func (a *ReportsAPI) get(ctx context.Context, path string, query map[string]string) ([]byte, error) {
+	resp, err := a.c.NewRequest(ctx, "GET", path, nil, query)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
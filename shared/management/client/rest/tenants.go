@@ -0,0 +1,131 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/netbirdio/netbird/shared/management/http/api"
+)
+
+// TenantsAPI APIs for accounts managed by the account, do not use directly.
+// Use Client.Impersonate with the ID of a tenant account to act in it.
+type TenantsAPI struct {
+	c *Client
+}
+
+// List list all tenants
+func (a *TenantsAPI) List(ctx context.Context) ([]api.Tenant, error) {
+	resp, err := a.c.NewRequest(ctx, "GET", "/api/tenants", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[[]api.Tenant](resp)
+	return ret, err
+}
+
+// Create create new tenant account
+func (a *TenantsAPI) Create(ctx context.Context, request api.PostApiTenantsJSONRequestBody) (*api.Tenant, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.NewRequest(ctx, "POST", "/api/tenants", bytes.NewReader(requestBytes), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[api.Tenant](resp)
+	return &ret, err
+}
+
+// Health list the health summaries of all tenants
+func (a *TenantsAPI) Health(ctx context.Context) ([]api.TenantHealth, error) {
+	resp, err := a.c.NewRequest(ctx, "GET", "/api/tenants/health", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[[]api.TenantHealth](resp)
+	return ret, err
+}
+
+// ListTemplates list all tenant templates
+func (a *TenantsAPI) ListTemplates(ctx context.Context) ([]api.TenantTemplate, error) {
+	resp, err := a.c.NewRequest(ctx, "GET", "/api/tenants/templates", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[[]api.TenantTemplate](resp)
+	return ret, err
+}
+
+// GetTemplate get tenant template info
+func (a *TenantsAPI) GetTemplate(ctx context.Context, templateID string) (*api.TenantTemplate, error) {
+	resp, err := a.c.NewRequest(ctx, "GET", "/api/tenants/templates/"+templateID, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[api.TenantTemplate](resp)
+	return &ret, err
+}
+
+// CreateTemplate create new tenant template
+func (a *TenantsAPI) CreateTemplate(ctx context.Context, request api.PostApiTenantsTemplatesJSONRequestBody) (*api.TenantTemplate, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.NewRequest(ctx, "POST", "/api/tenants/templates", bytes.NewReader(requestBytes), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[api.TenantTemplate](resp)
+	return &ret, err
+}
+
+// UpdateTemplate update tenant template
+func (a *TenantsAPI) UpdateTemplate(ctx context.Context, templateID string, request api.PutApiTenantsTemplatesTemplateIdJSONRequestBody) (*api.TenantTemplate, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.c.NewRequest(ctx, "PUT", "/api/tenants/templates/"+templateID, bytes.NewReader(requestBytes), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	ret, err := parseResponse[api.TenantTemplate](resp)
+	return &ret, err
+}
+
+// DeleteTemplate delete tenant template
+func (a *TenantsAPI) DeleteTemplate(ctx context.Context, templateID string) error {
+	resp, err := a.c.NewRequest(ctx, "DELETE", "/api/tenants/templates/"+templateID, nil, nil)
+	if err != nil {
+		return err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
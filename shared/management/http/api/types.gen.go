@@ -16,6 +16,28 @@ const (
 	TokenAuthScopes  = "TokenAuth.Scopes"
 )
 
+// Defines values for AccessRequestStatus.
+const (
+	AccessRequestStatusApproved  AccessRequestStatus = "approved"
+	AccessRequestStatusCancelled AccessRequestStatus = "cancelled"
+	AccessRequestStatusExpired   AccessRequestStatus = "expired"
+	AccessRequestStatusPending   AccessRequestStatus = "pending"
+	AccessRequestStatusRejected  AccessRequestStatus = "rejected"
+	AccessRequestStatusRevoked   AccessRequestStatus = "revoked"
+)
+
+// Defines values for AccessRequestTargetType.
+const (
+	AccessRequestTargetTypeGroup    AccessRequestTargetType = "group"
+	AccessRequestTargetTypeResource AccessRequestTargetType = "resource"
+)
+
+// Defines values for AccessRequestRequestTargetType.
+const (
+	AccessRequestRequestTargetTypeGroup    AccessRequestRequestTargetType = "group"
+	AccessRequestRequestTargetTypeResource AccessRequestRequestTargetType = "resource"
+)
+
 // Defines values for DNSRecordType.
 const (
 	DNSRecordTypeA     DNSRecordType = "A"
@@ -23,6 +45,13 @@ const (
 	DNSRecordTypeCNAME DNSRecordType = "CNAME"
 )
 
+// Defines values for DiscoveredSubnetStatus.
+const (
+	DiscoveredSubnetStatusApproved  DiscoveredSubnetStatus = "approved"
+	DiscoveredSubnetStatusDismissed DiscoveredSubnetStatus = "dismissed"
+	DiscoveredSubnetStatusPending   DiscoveredSubnetStatus = "pending"
+)
+
 // Defines values for EventActivityCode.
 const (
 	EventActivityCodeAccountCreate                            EventActivityCode = "account.create"
@@ -138,6 +167,12 @@ const (
 	NameserverNsTypeUdp NameserverNsType = "udp"
 )
 
+// Defines values for NetworkMapFirewallRuleDirection.
+const (
+	NetworkMapFirewallRuleDirectionIn  NetworkMapFirewallRuleDirection = "in"
+	NetworkMapFirewallRuleDirectionOut NetworkMapFirewallRuleDirection = "out"
+)
+
 // Defines values for NetworkResourceType.
 const (
 	NetworkResourceTypeDomain NetworkResourceType = "domain"
@@ -145,6 +180,20 @@ const (
 	NetworkResourceTypeSubnet NetworkResourceType = "subnet"
 )
 
+// Defines values for ObjectVersionOperation.
+const (
+	ObjectVersionOperationCreated    ObjectVersionOperation = "created"
+	ObjectVersionOperationDeleted    ObjectVersionOperation = "deleted"
+	ObjectVersionOperationRolledBack ObjectVersionOperation = "rolled_back"
+	ObjectVersionOperationUpdated    ObjectVersionOperation = "updated"
+)
+
+// Defines values for PeerInventoryRecordApprovalState.
+const (
+	PeerInventoryRecordApprovalStateApproved PeerInventoryRecordApprovalState = "approved"
+	PeerInventoryRecordApprovalStatePending  PeerInventoryRecordApprovalState = "pending"
+)
+
 // Defines values for PeerNetworkRangeCheckAction.
 const (
 	PeerNetworkRangeCheckActionAllow PeerNetworkRangeCheckAction = "allow"
@@ -196,6 +245,12 @@ const (
 	PolicyRuleUpdateProtocolUdp        PolicyRuleUpdateProtocol = "udp"
 )
 
+// Defines values for PortForwardProtocol.
+const (
+	PortForwardProtocolTcp PortForwardProtocol = "tcp"
+	PortForwardProtocolUdp PortForwardProtocol = "udp"
+)
+
 // Defines values for ResourceType.
 const (
 	ResourceTypeDomain ResourceType = "domain"
@@ -204,6 +259,23 @@ const (
 	ResourceTypeSubnet ResourceType = "subnet"
 )
 
+// Defines values for RouteHealthCheckType.
+const (
+	RouteHealthCheckTypeHttp RouteHealthCheckType = "http"
+	RouteHealthCheckTypeIcmp RouteHealthCheckType = "icmp"
+	RouteHealthCheckTypeTcp  RouteHealthCheckType = "tcp"
+)
+
+// Defines values for SetupKeyConstraintsOperatingSystems.
+const (
+	SetupKeyConstraintsOperatingSystemsAndroid SetupKeyConstraintsOperatingSystems = "android"
+	SetupKeyConstraintsOperatingSystemsDarwin  SetupKeyConstraintsOperatingSystems = "darwin"
+	SetupKeyConstraintsOperatingSystemsFreebsd SetupKeyConstraintsOperatingSystems = "freebsd"
+	SetupKeyConstraintsOperatingSystemsIos     SetupKeyConstraintsOperatingSystems = "ios"
+	SetupKeyConstraintsOperatingSystemsLinux   SetupKeyConstraintsOperatingSystems = "linux"
+	SetupKeyConstraintsOperatingSystemsWindows SetupKeyConstraintsOperatingSystems = "windows"
+)
+
 // Defines values for UserStatus.
 const (
 	UserStatusActive  UserStatus = "active"
@@ -237,6 +309,111 @@ const (
 	GetApiEventsNetworkTrafficParamsDirectionINGRESS          GetApiEventsNetworkTrafficParamsDirection = "INGRESS"
 )
 
+// Defines values for GetApiReportsPeersParamsFormat.
+const (
+	GetApiReportsPeersParamsFormatCsv  GetApiReportsPeersParamsFormat = "csv"
+	GetApiReportsPeersParamsFormatJson GetApiReportsPeersParamsFormat = "json"
+)
+
+// Defines values for GetApiReportsPeersParamsApprovalState.
+const (
+	GetApiReportsPeersParamsApprovalStateApproved GetApiReportsPeersParamsApprovalState = "approved"
+	GetApiReportsPeersParamsApprovalStatePending  GetApiReportsPeersParamsApprovalState = "pending"
+)
+
+// Defines values for GetApiReportsUsersParamsFormat.
+const (
+	GetApiReportsUsersParamsFormatCsv  GetApiReportsUsersParamsFormat = "csv"
+	GetApiReportsUsersParamsFormatJson GetApiReportsUsersParamsFormat = "json"
+)
+
+// AccessRequest defines model for AccessRequest.
+type AccessRequest struct {
+	// CreatedAt Request creation date
+	CreatedAt time.Time `json:"created_at"`
+
+	// DecidedAt Date of the last decision on the request
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+
+	// DecidedBy ID of the user that approved, rejected or revoked the request
+	DecidedBy *string `json:"decided_by,omitempty"`
+
+	// DecisionNote Note of the approver on rejection
+	DecisionNote *string `json:"decision_note,omitempty"`
+
+	// Duration Requested duration of the access
+	Duration string `json:"duration"`
+
+	// ExpiresAt Date when the granted access expires
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// Id Access request ID
+	Id string `json:"id"`
+
+	// PolicyId ID of the temporary policy granting the access
+	PolicyId *string `json:"policy_id,omitempty"`
+
+	// Reason Why the access is needed
+	Reason string `json:"reason"`
+
+	// Status Status of the request
+	Status AccessRequestStatus `json:"status"`
+
+	// TargetId ID of the network resource or group access is requested to
+	TargetId string `json:"target_id"`
+
+	// TargetType Type of the object access is requested to
+	TargetType AccessRequestTargetType `json:"target_type"`
+
+	// UserId ID of the user requesting access
+	UserId string `json:"user_id"`
+}
+
+// AccessRequestStatus Status of the request
+type AccessRequestStatus string
+
+// AccessRequestTargetType Type of the object access is requested to
+type AccessRequestTargetType string
+
+// AccessRequestDecision defines model for AccessRequestDecision.
+type AccessRequestDecision struct {
+	// Duration Duration of the granted access, at most the requested one. Defaults to the requested duration.
+	Duration *string `json:"duration,omitempty"`
+
+	// Note Note for the requesting user
+	Note *string `json:"note,omitempty"`
+}
+
+// AccessRequestRequest defines model for AccessRequestRequest.
+type AccessRequestRequest struct {
+	// Duration Requested duration of the access as a Go duration string
+	Duration string `json:"duration"`
+
+	// Reason Why the access is needed
+	Reason *string `json:"reason,omitempty"`
+
+	// TargetId ID of the network resource or group access is requested to
+	TargetId string `json:"target_id"`
+
+	// TargetType Type of the object access is requested to
+	TargetType AccessRequestRequestTargetType `json:"target_type"`
+}
+
+// AccessRequestRequestTargetType Type of the object access is requested to
+type AccessRequestRequestTargetType string
+
+// AccessRequestSettings defines model for AccessRequestSettings.
+type AccessRequestSettings struct {
+	// ApproverGroups IDs of the groups whose users can approve access requests
+	ApproverGroups []string `json:"approver_groups"`
+
+	// ApproverRoles Roles whose users can approve access requests
+	ApproverRoles []string `json:"approver_roles"`
+
+	// MaxDuration Longest access that can be requested as a Go duration string
+	MaxDuration string `json:"max_duration"`
+}
+
 // AccessiblePeer defines model for AccessiblePeer.
 type AccessiblePeer struct {
 	// CityName Commonly used English name of the city
@@ -373,6 +550,15 @@ type AccountSettings struct {
 
 	// RoutingPeerDnsResolutionEnabled Enables or disables DNS resolution on the routing peers
 	RoutingPeerDnsResolutionEnabled *bool `json:"routing_peer_dns_resolution_enabled,omitempty"`
+
+	// SubnetDiscoveryAllowlist Ranges the discovered subnets must be part of. The private IPv4 ranges are used if empty.
+	SubnetDiscoveryAllowlist *[]string `json:"subnet_discovery_allowlist,omitempty"`
+
+	// SubnetDiscoveryAutoApprove Adds discovered subnets as network resources without an approval
+	SubnetDiscoveryAutoApprove *bool `json:"subnet_discovery_auto_approve,omitempty"`
+
+	// SubnetDiscoveryEnabled Enables or disables the discovery of the subnets behind routing peers. Discovered subnets are suggested as network resources.
+	SubnetDiscoveryEnabled *bool `json:"subnet_discovery_enabled,omitempty"`
 }
 
 // AvailablePorts defines model for AvailablePorts.
@@ -475,6 +661,9 @@ type CreateSetupKeyRequest struct {
 	// AutoGroups List of group IDs to auto-assign to peers registered with this key
 	AutoGroups []string `json:"auto_groups"`
 
+	// Constraints Checks a peer has to pass to register with a setup key. Omitted fields don't restrict anything.
+	Constraints *SetupKeyConstraints `json:"constraints,omitempty"`
+
 	// Ephemeral Indicate that the peer will be ephemeral or not
 	Ephemeral *bool `json:"ephemeral,omitempty"`
 
@@ -484,6 +673,9 @@ type CreateSetupKeyRequest struct {
 	// Name Setup Key name
 	Name string `json:"name"`
 
+	// RequiresApproval Indicates that peers registered with this key have to be approved before they can connect
+	RequiresApproval *bool `json:"requires_approval,omitempty"`
+
 	// Type Setup key type, one-off for single time usage and reusable
 	Type string `json:"type"`
 
@@ -491,6 +683,33 @@ type CreateSetupKeyRequest struct {
 	UsageLimit int `json:"usage_limit"`
 }
 
+// CustomRole defines model for CustomRole.
+type CustomRole struct {
+	// Description Custom role description
+	Description *string `json:"description,omitempty"`
+
+	// Id Custom role ID
+	Id string `json:"id"`
+
+	// Name Custom role name
+	Name string `json:"name"`
+
+	// Permissions Allowed operations per module. Modules and operations that are not listed are denied.
+	Permissions map[string]map[string]bool `json:"permissions"`
+}
+
+// CustomRoleRequest defines model for CustomRoleRequest.
+type CustomRoleRequest struct {
+	// Description Custom role description
+	Description *string `json:"description,omitempty"`
+
+	// Name Custom role name
+	Name string `json:"name"`
+
+	// Permissions Allowed operations per module. Modules and operations that are not listed are denied.
+	Permissions map[string]map[string]bool `json:"permissions"`
+}
+
 // DNSRecord defines model for DNSRecord.
 type DNSRecord struct {
 	// Content DNS record content (IP address for A/AAAA, domain for CNAME)
@@ -533,6 +752,95 @@ type DNSSettings struct {
 	DisabledManagementGroups []string `json:"disabled_management_groups"`
 }
 
+// DeclarativeChange A single object that differs between the declarative configuration and the account
+type DeclarativeChange struct {
+	// Action Change applied to the object: create, update or delete
+	Action string `json:"action"`
+
+	// Drifted Indicates that the object has been changed outside of the declarative configuration since it was applied
+	Drifted *bool `json:"drifted,omitempty"`
+
+	// Fields Fields of an updated object that differ from the configuration
+	Fields *[]string `json:"fields,omitempty"`
+
+	// Id ID of the object, not set for objects that are created
+	Id *string `json:"id,omitempty"`
+
+	// Kind Type of the object: group, posture_checks, policy, network, network_resource, network_router, nameserver_group or zone
+	Kind string `json:"kind"`
+
+	// Name Name of the object. Network resources and routers are prefixed with the name of their network, zones are identified by their domain.
+	Name string `json:"name"`
+}
+
+// DeclarativeConfig Declarative description of groups, posture_checks, policies, networks with their resources and routers, nameserver_groups
+// and zones of the account. Objects reference each other by name. Objects that are not part of the configuration are kept
+// unless they have been created or updated by a previous apply.
+type DeclarativeConfig map[string]interface{}
+
+// DeclarativeDrift A managed object that has been changed outside of the declarative configuration since it was applied
+type DeclarativeDrift struct {
+	// Id ID of the object
+	Id string `json:"id"`
+
+	// Kind Type of the object
+	Kind string `json:"kind"`
+
+	// Name Name of the object
+	Name string `json:"name"`
+
+	// Reason How the object has been changed: modified or deleted
+	Reason string `json:"reason"`
+}
+
+// DeclarativePlan Changes needed to make the account match a declarative configuration
+type DeclarativePlan struct {
+	// Changes Changes ordered as they are applied
+	Changes []DeclarativeChange `json:"changes"`
+
+	// Drift Managed objects that have been changed since they were applied
+	Drift []DeclarativeDrift `json:"drift"`
+}
+
+// DiscoveredSubnet defines model for DiscoveredSubnet.
+type DiscoveredSubnet struct {
+	// FirstSeenAt Date the subnet was first reported
+	FirstSeenAt time.Time `json:"first_seen_at"`
+
+	// Id Discovered subnet ID
+	Id string `json:"id"`
+
+	// LastSeenAt Date the subnet was last reported
+	LastSeenAt time.Time `json:"last_seen_at"`
+
+	// NetworkId ID of the network the subnet was discovered in
+	NetworkId string `json:"network_id"`
+
+	// PeerId ID of the routing peer that reported the subnet
+	PeerId string `json:"peer_id"`
+
+	// ResourceId ID of the network resource created for an approved subnet
+	ResourceId *string `json:"resource_id,omitempty"`
+
+	// Status Status of the subnet
+	Status DiscoveredSubnetStatus `json:"status"`
+
+	// Subnet Discovered subnet in CIDR notation
+	Subnet string `json:"subnet"`
+}
+
+// DiscoveredSubnetStatus Status of the subnet
+type DiscoveredSubnetStatus string
+
+// DiscoveredSubnetApproval defines model for DiscoveredSubnetApproval.
+type DiscoveredSubnetApproval struct {
+	// Groups Group IDs of the network resource
+	Groups *[]string `json:"groups,omitempty"`
+
+	// Name Name of the network resource. Defaults to the subnet.
+	Name *string `json:"name,omitempty"`
+}
+
 // Event defines model for Event.
 type Event struct {
 	// Activity The activity that occurred during the event
@@ -951,6 +1259,166 @@ type Network struct {
 	RoutingPeersCount int `json:"routing_peers_count"`
 }
 
+// NetworkMapDNSRecord defines model for NetworkMapDNSRecord.
+type NetworkMapDNSRecord struct {
+	// Name Fully qualified domain name of the record
+	Name string `json:"name"`
+
+	// Rdata Value of the record
+	Rdata string `json:"rdata"`
+
+	// Ttl Time-to-live of the record in seconds
+	Ttl int `json:"ttl"`
+
+	// Type Record type
+	Type string `json:"type"`
+}
+
+// NetworkMapFirewallRule defines model for NetworkMapFirewallRule.
+type NetworkMapFirewallRule struct {
+	// Action Action applied to the traffic
+	Action string `json:"action"`
+
+	// Direction Direction of the traffic
+	Direction NetworkMapFirewallRuleDirection `json:"direction"`
+
+	// PeerIp IP address of the remote peer
+	PeerIp string `json:"peer_ip"`
+
+	// PolicyId ID of the policy the rule is derived from
+	PolicyId string `json:"policy_id"`
+
+	// Ports Port or port range of the traffic, empty for all ports
+	Ports string `json:"ports"`
+
+	// Protocol Protocol of the traffic
+	Protocol string `json:"protocol"`
+}
+
+// NetworkMapFirewallRuleDirection Direction of the traffic
+type NetworkMapFirewallRuleDirection string
+
+// NetworkMapNameserverGroup defines model for NetworkMapNameserverGroup.
+type NetworkMapNameserverGroup struct {
+	// Domains Match domains of the group
+	Domains []string `json:"domains"`
+
+	// Id Nameserver group ID
+	Id string `json:"id"`
+
+	// Name Nameserver group name
+	Name string `json:"name"`
+
+	// Nameservers Nameservers of the group in the format protocol://ip:port
+	Nameservers []string `json:"nameservers"`
+
+	// Primary Indicates whether the group resolves all domains
+	Primary bool `json:"primary"`
+}
+
+// NetworkMapPeer defines model for NetworkMapPeer.
+type NetworkMapPeer struct {
+	// DnsLabel Peer's DNS label
+	DnsLabel string `json:"dns_label"`
+
+	// Id Peer ID
+	Id string `json:"id"`
+
+	// Ip Peer's IP address
+	Ip string `json:"ip"`
+
+	// Name Peer's hostname
+	Name string `json:"name"`
+
+	// SshEnabled Indicates whether SSH server is enabled on this peer
+	SshEnabled bool `json:"ssh_enabled"`
+}
+
+// NetworkMapRoute defines model for NetworkMapRoute.
+type NetworkMapRoute struct {
+	// Domains Domains of a domain route
+	Domains []string `json:"domains"`
+
+	// Id Route ID
+	Id string `json:"id"`
+
+	// KeepRoute Indicate if the route should be kept after a domain doesn't resolve that IP anymore
+	KeepRoute bool `json:"keep_route"`
+
+	// Masquerade Indicate if peer should masquerade traffic to this route's prefix
+	Masquerade bool `json:"masquerade"`
+
+	// Metric Route metric number. Lowest number has higher priority
+	Metric int `json:"metric"`
+
+	// Network Network range in CIDR format, empty for domain routes
+	Network string `json:"network"`
+
+	// NetworkId Route network identifier, to group HA routes
+	NetworkId string `json:"network_id"`
+
+	// Peer WireGuard public key of the routing peer
+	Peer string `json:"peer"`
+}
+
+// NetworkMapRouteFirewallRule defines model for NetworkMapRouteFirewallRule.
+type NetworkMapRouteFirewallRule struct {
+	// Action Action applied to the traffic
+	Action string `json:"action"`
+
+	// Destination Network range of the routed traffic, empty for domain routes
+	Destination string `json:"destination"`
+
+	// Domains Domains of the routed traffic
+	Domains []string `json:"domains"`
+
+	// PolicyId ID of the policy the rule is derived from
+	PolicyId string `json:"policy_id"`
+
+	// Ports Port or port range of the traffic, empty for all ports
+	Ports string `json:"ports"`
+
+	// Protocol Protocol of the traffic
+	Protocol string `json:"protocol"`
+
+	// RouteId ID of the route the rule belongs to
+	RouteId string `json:"route_id"`
+
+	// SourceRanges IP ranges the traffic originates from
+	SourceRanges []string `json:"source_ranges"`
+}
+
+// NetworkMapSnapshot Network map computed for a peer, as the peer receives it from the management service
+type NetworkMapSnapshot struct {
+	// DnsRecords DNS records resolved by the peer
+	DnsRecords []NetworkMapDNSRecord `json:"dns_records"`
+
+	// FirewallRules Firewall rules applied on the peer
+	FirewallRules []NetworkMapFirewallRule `json:"firewall_rules"`
+
+	// GeneratedAt Time the snapshot was taken
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// NameserverGroups Nameserver groups used by the peer
+	NameserverGroups []NetworkMapNameserverGroup `json:"nameserver_groups"`
+
+	// OfflinePeers Peers the peer can connect to whose login has expired
+	OfflinePeers []NetworkMapPeer `json:"offline_peers"`
+	Peer         NetworkMapPeer   `json:"peer"`
+
+	// Peers Peers the peer can connect to
+	Peers []NetworkMapPeer `json:"peers"`
+
+	// RouteFirewallRules Firewall rules applied to the traffic the peer routes
+	RouteFirewallRules []NetworkMapRouteFirewallRule `json:"route_firewall_rules"`
+
+	// Routes Routes distributed to the peer
+	Routes []NetworkMapRoute `json:"routes"`
+
+	// SshEnabled Indicates whether the SSH server of the peer is enabled by the network map
+	SshEnabled bool `json:"ssh_enabled"`
+}
+
 // NetworkRequest defines model for NetworkRequest.
 type NetworkRequest struct {
 	// Description Network description
@@ -980,6 +1448,10 @@ type NetworkResource struct {
 	// Name Network resource name
 	Name string `json:"name"`
 
+	// Netmap 1:1 NAT of the routed network onto a virtual prefix of the same size. Clients route the virtual prefix and the routing
+	// peer translates it to the real prefix, which allows routing sites with overlapping subnets. IPv4 only.
+	Netmap *RouteNetMap `json:"netmap,omitempty"`
+
 	// Type Network resource type based of the address
 	Type NetworkResourceType `json:"type"`
 }
@@ -997,6 +1469,10 @@ type NetworkResourceMinimum struct {
 
 	// Name Network resource name
 	Name string `json:"name"`
+
+	// Netmap 1:1 NAT of the routed network onto a virtual prefix of the same size. Clients route the virtual prefix and the routing
+	// peer translates it to the real prefix, which allows routing sites with overlapping subnets. IPv4 only.
+	Netmap *RouteNetMap `json:"netmap,omitempty"`
 }
 
 // NetworkResourceRequest defines model for NetworkResourceRequest.
@@ -1015,6 +1491,10 @@ type NetworkResourceRequest struct {
 
 	// Name Network resource name
 	Name string `json:"name"`
+
+	// Netmap 1:1 NAT of the routed network onto a virtual prefix of the same size. Clients route the virtual prefix and the routing
+	// peer translates it to the real prefix, which allows routing sites with overlapping subnets. IPv4 only.
+	Netmap *RouteNetMap `json:"netmap,omitempty"`
 }
 
 // NetworkResourceType Network resource type based of the address
@@ -1022,18 +1502,36 @@ type NetworkResourceType string
 
 // NetworkRouter defines model for NetworkRouter.
 type NetworkRouter struct {
+	// Bgp Embedded BGP speaker on the routing peer that announces the NetBird network range to routers in its LAN, replacing
+	// static return routes. If the route isn't masqueraded, the networks the routing peer reaches through NetBird are
+	// announced as well, as return paths of site-to-site traffic. The announcements are withdrawn when the routing peer
+	// stops serving the route. IPv4 only.
+	Bgp *RouteBGP `json:"bgp,omitempty"`
+
 	// Enabled Network router status
 	Enabled bool `json:"enabled"`
 
+	// HealthCheck Probe run by the routing peer against a target in the routed network. Clients prefer other routing peers
+	// of the same network while the check of a routing peer fails, even if its tunnel is up.
+	HealthCheck *RouteHealthCheck `json:"health_check,omitempty"`
+
 	// Id Network Router Id
 	Id string `json:"id"`
 
+	// LoadBalance Distribute the traffic across all available routing peers of the route's network instead of choosing a single one. The traffic is split by destination address, not per connection: the network is divided into up to 64 address ranges and each range is routed through one of the peers, so all connections to the same destination use the same routing peer. A network of a single address is always routed through one peer
+	LoadBalance *bool `json:"load_balance,omitempty"`
+
 	// Masquerade Indicate if peer should masquerade traffic to this route's prefix
 	Masquerade bool `json:"masquerade"`
 
 	// Metric Route metric number. Lowest number has higher priority
 	Metric int `json:"metric"`
 
+	// Multicast Relay of multicast traffic between the LAN of the routing peer and the peers using the route, e.g. for mDNS and SSDP
+	// discovery. Peers only receive and send the traffic of groups whose UDP port they are allowed to reach in the routed
+	// network. IPv4 only.
+	Multicast *RouteMulticast `json:"multicast,omitempty"`
+
 	// Peer Peer Identifier associated with route. This property can not be set together with `peer_groups`
 	Peer *string `json:"peer,omitempty"`
 
@@ -1043,15 +1541,33 @@ type NetworkRouter struct {
 
 // NetworkRouterRequest defines model for NetworkRouterRequest.
 type NetworkRouterRequest struct {
+	// Bgp Embedded BGP speaker on the routing peer that announces the NetBird network range to routers in its LAN, replacing
+	// static return routes. If the route isn't masqueraded, the networks the routing peer reaches through NetBird are
+	// announced as well, as return paths of site-to-site traffic. The announcements are withdrawn when the routing peer
+	// stops serving the route. IPv4 only.
+	Bgp *RouteBGP `json:"bgp,omitempty"`
+
 	// Enabled Network router status
 	Enabled bool `json:"enabled"`
 
+	// HealthCheck Probe run by the routing peer against a target in the routed network. Clients prefer other routing peers
+	// of the same network while the check of a routing peer fails, even if its tunnel is up.
+	HealthCheck *RouteHealthCheck `json:"health_check,omitempty"`
+
+	// LoadBalance Distribute the traffic across all available routing peers of the route's network instead of choosing a single one. The traffic is split by destination address, not per connection: the network is divided into up to 64 address ranges and each range is routed through one of the peers, so all connections to the same destination use the same routing peer. A network of a single address is always routed through one peer
+	LoadBalance *bool `json:"load_balance,omitempty"`
+
 	// Masquerade Indicate if peer should masquerade traffic to this route's prefix
 	Masquerade bool `json:"masquerade"`
 
 	// Metric Route metric number. Lowest number has higher priority
 	Metric int `json:"metric"`
 
+	// Multicast Relay of multicast traffic between the LAN of the routing peer and the peers using the route, e.g. for mDNS and SSDP
+	// discovery. Peers only receive and send the traffic of groups whose UDP port they are allowed to reach in the routed
+	// network. IPv4 only.
+	Multicast *RouteMulticast `json:"multicast,omitempty"`
+
 	// Peer Peer Identifier associated with route. This property can not be set together with `peer_groups`
 	Peer *string `json:"peer,omitempty"`
 
@@ -1201,6 +1717,48 @@ type OSVersionCheck struct {
 	Windows *MinKernelVersionCheck `json:"windows,omitempty"`
 }
 
+// ObjectVersion defines model for ObjectVersion.
+type ObjectVersion struct {
+	// After Snapshot of the object after the change, empty for deletions
+	After *map[string]interface{} `json:"after,omitempty"`
+
+	// Before Snapshot of the object before the change, empty for creations
+	Before *map[string]interface{} `json:"before,omitempty"`
+
+	// Changes Top level fields that differ between the snapshots
+	Changes []ObjectVersionChange `json:"changes"`
+
+	// CreatedAt Version creation timestamp
+	CreatedAt time.Time `json:"created_at"`
+
+	// ObjectId ID of the versioned object
+	ObjectId string `json:"object_id"`
+
+	// Operation Change that created the version
+	Operation ObjectVersionOperation `json:"operation"`
+
+	// UserId ID of the user that made the change
+	UserId string `json:"user_id"`
+
+	// Version Version number of the object, starting at 1
+	Version int `json:"version"`
+}
+
+// ObjectVersionChange defines model for ObjectVersionChange.
+type ObjectVersionChange struct {
+	// After Value of the field after the change
+	After interface{} `json:"after"`
+
+	// Before Value of the field before the change
+	Before interface{} `json:"before"`
+
+	// Field Name of the changed field
+	Field string `json:"field"`
+}
+
+// ObjectVersionOperation Change that created the version
+type ObjectVersionOperation string
+
 // Peer defines model for Peer.
 type Peer struct {
 	// ApprovalRequired (Cloud only) Indicates whether peer needs approval
@@ -1289,6 +1847,57 @@ type Peer struct {
 	Version string `json:"version"`
 }
 
+// PeerApprovalSettings defines model for PeerApprovalSettings.
+type PeerApprovalSettings struct {
+	// AutoApprovalRules Rules approving new peers automatically
+	AutoApprovalRules []PeerAutoApprovalRule `json:"auto_approval_rules"`
+
+	// RequiredGroups IDs of the groups whose new peers have to be approved. Use the All group to require approval for every new peer.
+	RequiredGroups []string `json:"required_groups"`
+
+	// WebhookSecretSet Indicates that the webhook payloads are signed with a secret
+	WebhookSecretSet bool `json:"webhook_secret_set"`
+
+	// WebhookUrl URL notified with a POST request for every new pending peer. Empty disables notifications.
+	WebhookUrl string `json:"webhook_url"`
+}
+
+// PeerApprovalSettingsRequest defines model for PeerApprovalSettingsRequest.
+type PeerApprovalSettingsRequest struct {
+	// AutoApprovalRules Rules approving new peers automatically
+	AutoApprovalRules *[]PeerAutoApprovalRule `json:"auto_approval_rules,omitempty"`
+
+	// RequiredGroups IDs of the groups whose new peers have to be approved. Use the All group to require approval for every new peer.
+	RequiredGroups []string `json:"required_groups"`
+
+	// WebhookSecret Secret used to sign webhook payloads with HMAC-SHA256 in the X-NetBird-Signature header. Omit to keep the current secret, send an empty string to remove it.
+	WebhookSecret *string `json:"webhook_secret,omitempty"`
+
+	// WebhookUrl URL notified with a POST request for every new pending peer. Empty disables notifications.
+	WebhookUrl *string `json:"webhook_url,omitempty"`
+}
+
+// PeerAutoApprovalRule Approves new peers automatically when all the set conditions match
+type PeerAutoApprovalRule struct {
+	// HostnamePattern Regular expression the whole peer hostname has to match
+	HostnamePattern *string `json:"hostname_pattern,omitempty"`
+
+	// Manufacturers System manufacturers the rule matches, case-insensitive
+	Manufacturers *[]string `json:"manufacturers,omitempty"`
+
+	// Name Unique rule name
+	Name string `json:"name"`
+
+	// OperatingSystems Operating systems the rule matches, compared to the peer GoOS
+	OperatingSystems *[]string `json:"operating_systems,omitempty"`
+
+	// SerialNumbers System serial numbers the rule matches
+	SerialNumbers *[]string `json:"serial_numbers,omitempty"`
+
+	// SourceRanges Public source IP ranges in CIDR notation the rule matches
+	SourceRanges *[]string `json:"source_ranges,omitempty"`
+}
+
 // PeerBatch defines model for PeerBatch.
 type PeerBatch struct {
 	// AccessiblePeersCount Number of accessible peers
@@ -1380,6 +1989,75 @@ type PeerBatch struct {
 	Version string `json:"version"`
 }
 
+// PeerInventoryRecord defines model for PeerInventoryRecord.
+type PeerInventoryRecord struct {
+	// ApprovalState Whether the peer has been approved
+	ApprovalState PeerInventoryRecordApprovalState `json:"approval_state"`
+
+	// CityName City of the connection IP
+	CityName string `json:"city_name"`
+
+	// Compliant Indicates whether the peer passes all applied posture checks
+	Compliant bool `json:"compliant"`
+
+	// Connected Peer connection status
+	Connected bool `json:"connected"`
+
+	// ConnectionIp Public IP the peer connected from
+	ConnectionIp string `json:"connection_ip"`
+
+	// CountryCode Country code of the connection IP
+	CountryCode string `json:"country_code"`
+
+	// FailedPostureChecks Names of the applied posture checks the peer doesn't pass
+	FailedPostureChecks []string `json:"failed_posture_checks"`
+
+	// Groups Names of the peer groups
+	Groups []string `json:"groups"`
+
+	// Hostname Hostname of the machine
+	Hostname string `json:"hostname"`
+
+	// Id Peer ID
+	Id string `json:"id"`
+
+	// Ip Peer IP address
+	Ip string `json:"ip"`
+
+	// LastLogin Last time the owner of the peer logged in with it
+	LastLogin *time.Time `json:"last_login,omitempty"`
+
+	// LastSeen Last time the peer was connected
+	LastSeen time.Time `json:"last_seen"`
+
+	// LoginExpired Indicates whether the peer login has expired
+	LoginExpired bool `json:"login_expired"`
+
+	// Name Peer name
+	Name string `json:"name"`
+
+	// Os Operating system and version
+	Os string `json:"os"`
+
+	// Owner Name of the user that enrolled the peer
+	Owner string `json:"owner"`
+
+	// OwnerId ID of the user that enrolled the peer
+	OwnerId string `json:"owner_id"`
+
+	// PostureChecks Names of the posture checks applied to the peer by policies
+	PostureChecks []string `json:"posture_checks"`
+
+	// SerialNumber System serial number
+	SerialNumber string `json:"serial_number"`
+
+	// Version NetBird client version
+	Version string `json:"version"`
+}
+
+// PeerInventoryRecordApprovalState Whether the peer has been approved
+type PeerInventoryRecordApprovalState string
+
 // PeerLocalFlags defines model for PeerLocalFlags.
 type PeerLocalFlags struct {
 	// BlockInbound Indicates whether inbound traffic is blocked on this peer
@@ -1471,6 +2149,54 @@ type PeerTemporaryAccessResponse struct {
 	Rules []string `json:"rules"`
 }
 
+// PendingPeer A peer waiting for approval with the system information it reported at registration
+type PendingPeer struct {
+	// CityName Commonly used English name of the city
+	CityName CityName `json:"city_name"`
+
+	// ConnectionIp Public IP address the peer registered from
+	ConnectionIp string `json:"connection_ip"`
+
+	// CountryCode 2-letter ISO 3166-1 alpha-2 code that represents the country
+	CountryCode CountryCode `json:"country_code"`
+
+	// CreatedAt Peer registration date
+	CreatedAt time.Time `json:"created_at"`
+
+	// Hostname Hostname reported by the peer
+	Hostname string `json:"hostname"`
+
+	// Id Peer ID
+	Id string `json:"id"`
+
+	// Ip Peer's IP address
+	Ip string `json:"ip"`
+
+	// KernelVersion Peer's operating system kernel version
+	KernelVersion string `json:"kernel_version"`
+
+	// Manufacturer System manufacturer
+	Manufacturer string `json:"manufacturer"`
+
+	// Name Peer's hostname
+	Name string `json:"name"`
+
+	// Os Peer's operating system and version
+	Os string `json:"os"`
+
+	// ProductName System product name
+	ProductName string `json:"product_name"`
+
+	// SerialNumber System serial number
+	SerialNumber string `json:"serial_number"`
+
+	// UserId User ID of the user that registered the peer, empty if registered with a setup key
+	UserId string `json:"user_id"`
+
+	// Version Peer's daemon or cli version
+	Version string `json:"version"`
+}
+
 // PersonalAccessToken defines model for PersonalAccessToken.
 type PersonalAccessToken struct {
 	// CreatedAt Date the token was created
@@ -1490,6 +2216,9 @@ type PersonalAccessToken struct {
 
 	// Name Name of the token
 	Name string `json:"name"`
+
+	// Scope Restricts a token to a subset of the permissions of its user. Omitted fields don't restrict anything.
+	Scope *PersonalAccessTokenScope `json:"scope,omitempty"`
 }
 
 // PersonalAccessTokenGenerated defines model for PersonalAccessTokenGenerated.
@@ -1507,6 +2236,21 @@ type PersonalAccessTokenRequest struct {
 
 	// Name Name of the token
 	Name string `json:"name"`
+
+	// Scope Restricts a token to a subset of the permissions of its user. Omitted fields don't restrict anything.
+	Scope *PersonalAccessTokenScope `json:"scope,omitempty"`
+}
+
+// PersonalAccessTokenScope Restricts a token to a subset of the permissions of its user. Omitted fields don't restrict anything.
+type PersonalAccessTokenScope struct {
+	// PeerGroups IDs of the groups whose peers the token can access
+	PeerGroups *[]string `json:"peer_groups,omitempty"`
+
+	// Permissions Allowed operations per module. Modules and operations that are not listed are denied.
+	Permissions *map[string]map[string]bool `json:"permissions,omitempty"`
+
+	// SourceRanges Source IP ranges in CIDR notation the token can be used from
+	SourceRanges *[]string `json:"source_ranges,omitempty"`
 }
 
 // Policy defines model for Policy.
@@ -1708,6 +2452,84 @@ type PolicyUpdate struct {
 	SourcePostureChecks *[]string `json:"source_posture_checks,omitempty"`
 }
 
+// PortForward defines model for PortForward.
+type PortForward struct {
+	// Description Port forward description
+	Description *string `json:"description,omitempty"`
+
+	// Enabled Port forward status
+	Enabled bool `json:"enabled"`
+
+	// Id Port forward ID
+	Id string `json:"id"`
+
+	// ListenAddress IPv4 address of the ingress peer the port is published on. The port is published on all addresses if not set
+	ListenAddress *string `json:"listen_address,omitempty"`
+
+	// ListenPort Port published on the ingress peer
+	ListenPort int `json:"listen_port"`
+
+	// Name Port forward name
+	Name string `json:"name"`
+
+	// PeerId ID of the ingress peer that publishes the port. The peer has to be a Linux peer with firewall management enabled
+	PeerId string `json:"peer_id"`
+
+	// Protocol Transport protocol of the forwarded port
+	Protocol PortForwardProtocol `json:"protocol"`
+
+	// SourceRanges IPv4 CIDR ranges allowed to connect to the published port. Use 0.0.0.0/0 to allow all sources
+	SourceRanges []string `json:"source_ranges"`
+
+	// TargetPeerId ID of the peer the traffic is forwarded to. This property can not be set together with `target_resource_id`
+	TargetPeerId *string `json:"target_peer_id,omitempty"`
+
+	// TargetPort Port the traffic is forwarded to on the target
+	TargetPort int `json:"target_port"`
+
+	// TargetResourceId ID of the host network resource the traffic is forwarded to. The ingress peer has to be a routing peer of the resource's network. This property can not be set together with `target_peer_id`
+	TargetResourceId *string `json:"target_resource_id,omitempty"`
+}
+
+// PortForwardProtocol Transport protocol of the forwarded port
+type PortForwardProtocol string
+
+// PortForwardRequest defines model for PortForwardRequest.
+type PortForwardRequest struct {
+	// Description Port forward description
+	Description *string `json:"description,omitempty"`
+
+	// Enabled Port forward status
+	Enabled bool `json:"enabled"`
+
+	// ListenAddress IPv4 address of the ingress peer the port is published on. The port is published on all addresses if not set
+	ListenAddress *string `json:"listen_address,omitempty"`
+
+	// ListenPort Port published on the ingress peer
+	ListenPort int `json:"listen_port"`
+
+	// Name Port forward name
+	Name string `json:"name"`
+
+	// PeerId ID of the ingress peer that publishes the port. The peer has to be a Linux peer with firewall management enabled
+	PeerId string `json:"peer_id"`
+
+	// Protocol Transport protocol of the forwarded port
+	Protocol PortForwardProtocol `json:"protocol"`
+
+	// SourceRanges IPv4 CIDR ranges allowed to connect to the published port. Use 0.0.0.0/0 to allow all sources
+	SourceRanges []string `json:"source_ranges"`
+
+	// TargetPeerId ID of the peer the traffic is forwarded to. This property can not be set together with `target_resource_id`
+	TargetPeerId *string `json:"target_peer_id,omitempty"`
+
+	// TargetPort Port the traffic is forwarded to on the target
+	TargetPort int `json:"target_port"`
+
+	// TargetResourceId ID of the host network resource the traffic is forwarded to. The ingress peer has to be a routing peer of the resource's network. This property can not be set together with `target_peer_id`
+	TargetResourceId *string `json:"target_resource_id,omitempty"`
+}
+
 // PostureCheck defines model for PostureCheck.
 type PostureCheck struct {
 	// Checks List of objects that perform the actual checks
@@ -1767,6 +2589,12 @@ type Route struct {
 	// AccessControlGroups Access control group identifier associated with route.
 	AccessControlGroups *[]string `json:"access_control_groups,omitempty"`
 
+	// Bgp Embedded BGP speaker on the routing peer that announces the NetBird network range to routers in its LAN, replacing
+	// static return routes. If the route isn't masqueraded, the networks the routing peer reaches through NetBird are
+	// announced as well, as return paths of site-to-site traffic. The announcements are withdrawn when the routing peer
+	// stops serving the route. IPv4 only.
+	Bgp *RouteBGP `json:"bgp,omitempty"`
+
 	// Description Route description
 	Description string `json:"description"`
 
@@ -1779,18 +2607,34 @@ type Route struct {
 	// Groups Group IDs containing routing peers
 	Groups []string `json:"groups"`
 
+	// HealthCheck Probe run by the routing peer against a target in the routed network. Clients prefer other routing peers
+	// of the same network while the check of a routing peer fails, even if its tunnel is up.
+	HealthCheck *RouteHealthCheck `json:"health_check,omitempty"`
+
 	// Id Route Id
 	Id string `json:"id"`
 
 	// KeepRoute Indicate if the route should be kept after a domain doesn't resolve that IP anymore
 	KeepRoute bool `json:"keep_route"`
 
+	// LoadBalance Distribute the traffic across all available routing peers of the route's network instead of choosing a single one. The traffic is split by destination address, not per connection: the network is divided into up to 64 address ranges and each range is routed through one of the peers, so all connections to the same destination use the same routing peer. A network of a single address is always routed through one peer
+	LoadBalance *bool `json:"load_balance,omitempty"`
+
 	// Masquerade Indicate if peer should masquerade traffic to this route's prefix
 	Masquerade bool `json:"masquerade"`
 
 	// Metric Route metric number. Lowest number has higher priority
 	Metric int `json:"metric"`
 
+	// Multicast Relay of multicast traffic between the LAN of the routing peer and the peers using the route, e.g. for mDNS and SSDP
+	// discovery. Peers only receive and send the traffic of groups whose UDP port they are allowed to reach in the routed
+	// network. IPv4 only.
+	Multicast *RouteMulticast `json:"multicast,omitempty"`
+
+	// Netmap 1:1 NAT of the routed network onto a virtual prefix of the same size. Clients route the virtual prefix and the routing
+	// peer translates it to the real prefix, which allows routing sites with overlapping subnets. IPv4 only.
+	Netmap *RouteNetMap `json:"netmap,omitempty"`
+
 	// Network Network range in CIDR format, Conflicts with domains
 	Network *string `json:"network,omitempty"`
 
@@ -1810,11 +2654,84 @@ type Route struct {
 	SkipAutoApply *bool `json:"skip_auto_apply,omitempty"`
 }
 
+// RouteBGP Embedded BGP speaker on the routing peer that announces the NetBird network range to routers in its LAN, replacing
+// static return routes. If the route isn't masqueraded, the networks the routing peer reaches through NetBird are
+// announced as well, as return paths of site-to-site traffic. The announcements are withdrawn when the routing peer
+// stops serving the route. IPv4 only.
+type RouteBGP struct {
+	// LocalAsn AS number of the routing peer
+	LocalAsn int64 `json:"local_asn"`
+
+	// Neighbors LAN routers the routing peer opens BGP sessions to
+	Neighbors []RouteBGPNeighbor `json:"neighbors"`
+}
+
+// RouteBGPNeighbor defines model for RouteBGPNeighbor.
+type RouteBGPNeighbor struct {
+	// Address IPv4 address of the LAN router
+	Address string `json:"address"`
+
+	// Asn AS number of the LAN router
+	Asn int64 `json:"asn"`
+}
+
+// RouteHealthCheck Probe run by the routing peer against a target in the routed network. Clients prefer other routing peers
+// of the same network while the check of a routing peer fails, even if its tunnel is up.
+type RouteHealthCheck struct {
+	// Interval Seconds between probes, defaults to 10
+	Interval *int `json:"interval,omitempty"`
+
+	// Target Probe target, an IP address for `icmp`, a host:port for `tcp` and a URL for `http`
+	Target string `json:"target"`
+
+	// Timeout Probe timeout in seconds, defaults to 3. Must not be larger than the interval
+	Timeout *int `json:"timeout,omitempty"`
+
+	// Type Probe type, `icmp` pings the target, `tcp` opens a connection and `http` expects a 2xx or 3xx response to a GET request
+	Type RouteHealthCheckType `json:"type"`
+}
+
+// RouteHealthCheckType Probe type, `icmp` pings the target, `tcp` opens a connection and `http` expects a 2xx or 3xx response to a GET request
+type RouteHealthCheckType string
+
+// RouteMulticast Relay of multicast traffic between the LAN of the routing peer and the peers using the route, e.g. for mDNS and SSDP
+// discovery. Peers only receive and send the traffic of groups whose UDP port they are allowed to reach in the routed
+// network. IPv4 only.
+type RouteMulticast struct {
+	// Broadcast Forward directed broadcasts of the peers into the routed network, e.g. Wake-on-LAN packets
+	Broadcast bool `json:"broadcast"`
+
+	// Groups Additional IPv4 multicast groups with their UDP port
+	Groups *[]string `json:"groups,omitempty"`
+
+	// Mdns Relay mDNS (224.0.0.251:5353)
+	Mdns bool `json:"mdns"`
+
+	// Ssdp Relay SSDP (239.255.255.250:1900)
+	Ssdp bool `json:"ssdp"`
+}
+
+// RouteNetMap 1:1 NAT of the routed network onto a virtual prefix of the same size. Clients route the virtual prefix and the routing
+// peer translates it to the real prefix, which allows routing sites with overlapping subnets. IPv4 only.
+type RouteNetMap struct {
+	// Real Real prefix in the routed network. Defaults to the route network and is required for domain routes
+	Real *string `json:"real,omitempty"`
+
+	// Virtual Virtual prefix used by clients, must be of the same size as the real prefix and must not overlap it
+	Virtual string `json:"virtual"`
+}
+
 // RouteRequest defines model for RouteRequest.
 type RouteRequest struct {
 	// AccessControlGroups Access control group identifier associated with route.
 	AccessControlGroups *[]string `json:"access_control_groups,omitempty"`
 
+	// Bgp Embedded BGP speaker on the routing peer that announces the NetBird network range to routers in its LAN, replacing
+	// static return routes. If the route isn't masqueraded, the networks the routing peer reaches through NetBird are
+	// announced as well, as return paths of site-to-site traffic. The announcements are withdrawn when the routing peer
+	// stops serving the route. IPv4 only.
+	Bgp *RouteBGP `json:"bgp,omitempty"`
+
 	// Description Route description
 	Description string `json:"description"`
 
@@ -1827,15 +2744,31 @@ type RouteRequest struct {
 	// Groups Group IDs containing routing peers
 	Groups []string `json:"groups"`
 
+	// HealthCheck Probe run by the routing peer against a target in the routed network. Clients prefer other routing peers
+	// of the same network while the check of a routing peer fails, even if its tunnel is up.
+	HealthCheck *RouteHealthCheck `json:"health_check,omitempty"`
+
 	// KeepRoute Indicate if the route should be kept after a domain doesn't resolve that IP anymore
 	KeepRoute bool `json:"keep_route"`
 
+	// LoadBalance Distribute the traffic across all available routing peers of the route's network instead of choosing a single one. The traffic is split by destination address, not per connection: the network is divided into up to 64 address ranges and each range is routed through one of the peers, so all connections to the same destination use the same routing peer. A network of a single address is always routed through one peer
+	LoadBalance *bool `json:"load_balance,omitempty"`
+
 	// Masquerade Indicate if peer should masquerade traffic to this route's prefix
 	Masquerade bool `json:"masquerade"`
 
 	// Metric Route metric number. Lowest number has higher priority
 	Metric int `json:"metric"`
 
+	// Multicast Relay of multicast traffic between the LAN of the routing peer and the peers using the route, e.g. for mDNS and SSDP
+	// discovery. Peers only receive and send the traffic of groups whose UDP port they are allowed to reach in the routed
+	// network. IPv4 only.
+	Multicast *RouteMulticast `json:"multicast,omitempty"`
+
+	// Netmap 1:1 NAT of the routed network onto a virtual prefix of the same size. Clients route the virtual prefix and the routing
+	// peer translates it to the real prefix, which allows routing sites with overlapping subnets. IPv4 only.
+	Netmap *RouteNetMap `json:"netmap,omitempty"`
+
 	// Network Network range in CIDR format, Conflicts with domains
 	Network *string `json:"network,omitempty"`
 
@@ -1861,6 +2794,34 @@ type RulePortRange struct {
 	Start int `json:"start"`
 }
 
+// SCIMToken defines model for SCIMToken.
+type SCIMToken struct {
+	// CreatedAt Date the token was created
+	CreatedAt time.Time `json:"created_at"`
+
+	// CreatedBy User ID of the user who created the token
+	CreatedBy string `json:"created_by"`
+
+	// Id ID of the SCIM token
+	Id string `json:"id"`
+
+	// LastUsed Date the token was last used
+	LastUsed *time.Time `json:"last_used,omitempty"`
+
+	// UserId ID of the service user the provisioning requests are executed as
+	UserId string `json:"user_id"`
+}
+
+// SCIMTokenGenerated defines model for SCIMTokenGenerated.
+type SCIMTokenGenerated struct {
+	// PlainToken Plain text representation of the generated token. It is shown only once.
+	PlainToken string `json:"plain_token"`
+
+	// ScimBaseUrl Path of the SCIM 2.0 endpoint relative to the management URL
+	ScimBaseUrl string    `json:"scim_base_url"`
+	ScimToken   SCIMToken `json:"scim_token"`
+}
+
 // SetupKey defines model for SetupKey.
 type SetupKey struct {
 	// AllowExtraDnsLabels Allow extra DNS labels to be added to the peer
@@ -1869,6 +2830,9 @@ type SetupKey struct {
 	// AutoGroups List of group IDs to auto-assign to peers registered with this key
 	AutoGroups []string `json:"auto_groups"`
 
+	// Constraints Checks a peer has to pass to register with a setup key. Omitted fields don't restrict anything.
+	Constraints *SetupKeyConstraints `json:"constraints,omitempty"`
+
 	// Ephemeral Indicate that the peer will be ephemeral or not
 	Ephemeral bool `json:"ephemeral"`
 
@@ -1887,6 +2851,9 @@ type SetupKey struct {
 	// Name Setup key name identifier
 	Name string `json:"name"`
 
+	// RequiresApproval Indicates that peers registered with this key have to be approved before they can connect
+	RequiresApproval bool `json:"requires_approval"`
+
 	// Revoked Setup key revocation status
 	Revoked bool `json:"revoked"`
 
@@ -1917,6 +2884,9 @@ type SetupKeyBase struct {
 	// AutoGroups List of group IDs to auto-assign to peers registered with this key
 	AutoGroups []string `json:"auto_groups"`
 
+	// Constraints Checks a peer has to pass to register with a setup key. Omitted fields don't restrict anything.
+	Constraints *SetupKeyConstraints `json:"constraints,omitempty"`
+
 	// Ephemeral Indicate that the peer will be ephemeral or not
 	Ephemeral bool `json:"ephemeral"`
 
@@ -1932,6 +2902,9 @@ type SetupKeyBase struct {
 	// Name Setup key name identifier
 	Name string `json:"name"`
 
+	// RequiresApproval Indicates that peers registered with this key have to be approved before they can connect
+	RequiresApproval bool `json:"requires_approval"`
+
 	// Revoked Setup key revocation status
 	Revoked bool `json:"revoked"`
 
@@ -1962,6 +2935,9 @@ type SetupKeyClear struct {
 	// AutoGroups List of group IDs to auto-assign to peers registered with this key
 	AutoGroups []string `json:"auto_groups"`
 
+	// Constraints Checks a peer has to pass to register with a setup key. Omitted fields don't restrict anything.
+	Constraints *SetupKeyConstraints `json:"constraints,omitempty"`
+
 	// Ephemeral Indicate that the peer will be ephemeral or not
 	Ephemeral bool `json:"ephemeral"`
 
@@ -1980,6 +2956,9 @@ type SetupKeyClear struct {
 	// Name Setup key name identifier
 	Name string `json:"name"`
 
+	// RequiresApproval Indicates that peers registered with this key have to be approved before they can connect
+	RequiresApproval bool `json:"requires_approval"`
+
 	// Revoked Setup key revocation status
 	Revoked bool `json:"revoked"`
 
@@ -2002,11 +2981,35 @@ type SetupKeyClear struct {
 	Valid bool `json:"valid"`
 }
 
+// SetupKeyConstraints Checks a peer has to pass to register with a setup key. Omitted fields don't restrict anything.
+type SetupKeyConstraints struct {
+	// HostnamePattern Regular expression the whole peer hostname has to match
+	HostnamePattern *string `json:"hostname_pattern,omitempty"`
+
+	// OperatingSystems Operating systems peers can register from
+	OperatingSystems *[]SetupKeyConstraintsOperatingSystems `json:"operating_systems,omitempty"`
+
+	// PostureChecks IDs of the posture checks a peer has to pass at registration
+	PostureChecks *[]string `json:"posture_checks,omitempty"`
+
+	// SourceRanges Public source IP ranges in CIDR notation peers can register from
+	SourceRanges *[]string `json:"source_ranges,omitempty"`
+}
+
+// SetupKeyConstraintsOperatingSystems defines model for SetupKeyConstraints.OperatingSystems.
+type SetupKeyConstraintsOperatingSystems string
+
 // SetupKeyRequest defines model for SetupKeyRequest.
 type SetupKeyRequest struct {
 	// AutoGroups List of group IDs to auto-assign to peers registered with this key
 	AutoGroups []string `json:"auto_groups"`
 
+	// Constraints Checks a peer has to pass to register with a setup key. Omitted fields don't restrict anything.
+	Constraints *SetupKeyConstraints `json:"constraints,omitempty"`
+
+	// RequiresApproval Indicates that peers registered with this key have to be approved before they can connect
+	RequiresApproval *bool `json:"requires_approval,omitempty"`
+
 	// Revoked Setup key revocation status
 	Revoked bool `json:"revoked"`
 }
@@ -2032,11 +3035,105 @@ type SetupResponse struct {
 	UserId string `json:"user_id"`
 }
 
+// Tenant An account managed by the users of this account
+type Tenant struct {
+	// AccountId ID of the tenant account. Pass it as the account query parameter to act in the tenant account.
+	AccountId string `json:"account_id"`
+
+	// AdminGroups IDs of the groups of this account whose users can access the tenant account
+	AdminGroups []string `json:"admin_groups"`
+
+	// CreatedAt Tenant creation date
+	CreatedAt time.Time `json:"created_at"`
+
+	// Name Name of the tenant
+	Name string `json:"name"`
+
+	// TemplateId ID of the template the tenant account was created from
+	TemplateId *string `json:"template_id,omitempty"`
+}
+
+// TenantHealth Summary of the peers and users of a tenant account
+type TenantHealth struct {
+	// AccountId ID of the tenant account
+	AccountId string `json:"account_id"`
+
+	// ConnectedPeers Number of connected peers
+	ConnectedPeers int `json:"connected_peers"`
+
+	// LastSeen Last time any peer of the tenant was connected
+	LastSeen *time.Time `json:"last_seen,omitempty"`
+
+	// LoginExpiredPeers Number of peers whose login has expired
+	LoginExpiredPeers int `json:"login_expired_peers"`
+
+	// Name Name of the tenant
+	Name string `json:"name"`
+
+	// PendingApprovalPeers Number of peers waiting for approval
+	PendingApprovalPeers int `json:"pending_approval_peers"`
+
+	// Peers Number of peers
+	Peers int `json:"peers"`
+
+	// Users Number of users
+	Users int `json:"users"`
+}
+
+// TenantRequest defines model for TenantRequest.
+type TenantRequest struct {
+	// AdminGroups IDs of the groups of this account whose users can access the tenant account. At least one group is required.
+	AdminGroups []string `json:"admin_groups"`
+
+	// Name Name of the tenant
+	Name string `json:"name"`
+
+	// TemplateId ID of a template to create the tenant account from
+	TemplateId *string `json:"template_id,omitempty"`
+}
+
+// TenantTemplate Groups, policies, networks and DNS settings new tenant accounts are created with
+type TenantTemplate struct {
+	// Config Declarative description of groups, posture_checks, policies, networks with their resources and routers, nameserver_groups
+	// and zones of the account. Objects reference each other by name. Objects that are not part of the configuration are kept
+	// unless they have been created or updated by a previous apply.
+	Config DeclarativeConfig `json:"config"`
+
+	// Description Template description
+	Description string `json:"description"`
+
+	// Id Template ID
+	Id string `json:"id"`
+
+	// Name Template name
+	Name string `json:"name"`
+
+	// UpdatedAt Last time the template was updated
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TenantTemplateRequest defines model for TenantTemplateRequest.
+type TenantTemplateRequest struct {
+	// Config Declarative description of groups, posture_checks, policies, networks with their resources and routers, nameserver_groups
+	// and zones of the account. Objects reference each other by name. Objects that are not part of the configuration are kept
+	// unless they have been created or updated by a previous apply.
+	Config DeclarativeConfig `json:"config"`
+
+	// Description Template description
+	Description *string `json:"description,omitempty"`
+
+	// Name Template name
+	Name string `json:"name"`
+}
+
 // User defines model for User.
 type User struct {
 	// AutoGroups Group IDs to auto-assign to peers registered by this user
 	AutoGroups []string `json:"auto_groups"`
 
+	// CustomRoleId ID of the custom role defining the permissions of the user. Only applies to users with the user role.
+	CustomRoleId *string `json:"custom_role_id,omitempty"`
+
 	// Email User's email address
 	Email string `json:"email"`
 
@@ -2086,6 +3183,9 @@ type UserCreateRequest struct {
 	// AutoGroups Group IDs to auto-assign to peers registered by this user
 	AutoGroups []string `json:"auto_groups"`
 
+	// CustomRoleId ID of the custom role defining the permissions of the user. Only applies to users with the user role.
+	CustomRoleId *string `json:"custom_role_id,omitempty"`
+
 	// Email User's Email to send invite to
 	Email *string `json:"email,omitempty"`
 
@@ -2099,6 +3199,63 @@ type UserCreateRequest struct {
 	Role string `json:"role"`
 }
 
+// UserInventoryRecord defines model for UserInventoryRecord.
+type UserInventoryRecord struct {
+	// Blocked Indicates whether the user is blocked
+	Blocked bool `json:"blocked"`
+
+	// CreatedAt User creation date
+	CreatedAt time.Time `json:"created_at"`
+
+	// Email User email
+	Email string `json:"email"`
+
+	// Groups Names of the groups assigned to the peers of the user
+	Groups []string `json:"groups"`
+
+	// Id User ID
+	Id string `json:"id"`
+
+	// IsServiceUser Indicates whether the user is a service user
+	IsServiceUser bool `json:"is_service_user"`
+
+	// Issued How the user was created
+	Issued string `json:"issued"`
+
+	// LastLogin Last time the user logged in
+	LastLogin *time.Time `json:"last_login,omitempty"`
+
+	// Name User name
+	Name string `json:"name"`
+
+	// Peers Number of peers of the user
+	Peers int `json:"peers"`
+
+	// Role User role
+	Role string `json:"role"`
+
+	// Tokens Personal access tokens of the user
+	Tokens []UserInventoryToken `json:"tokens"`
+}
+
+// UserInventoryToken defines model for UserInventoryToken.
+type UserInventoryToken struct {
+	// CreatedAt Token creation date
+	CreatedAt time.Time `json:"created_at"`
+
+	// ExpirationDate Token expiration date
+	ExpirationDate *time.Time `json:"expiration_date,omitempty"`
+
+	// Id Personal access token ID
+	Id string `json:"id"`
+
+	// LastUsed Last time the token was used
+	LastUsed *time.Time `json:"last_used,omitempty"`
+
+	// Name Name of the token
+	Name string `json:"name"`
+}
+
 // UserPermissions defines model for UserPermissions.
 type UserPermissions struct {
 	// IsRestricted Indicates whether this User's Peers view is restricted
@@ -2111,6 +3268,9 @@ type UserRequest struct {
 	// AutoGroups Group IDs to auto-assign to peers registered by this user
 	AutoGroups []string `json:"auto_groups"`
 
+	// CustomRoleId ID of the custom role defining the permissions of the user. Only applies to users with the user role.
+	CustomRoleId *string `json:"custom_role_id,omitempty"`
+
 	// IsBlocked If set to true then user is blocked and can't use the system
 	IsBlocked bool `json:"is_blocked"`
 
@@ -2240,15 +3400,90 @@ type GetApiPeersPeerIdIngressPortsParams struct {
 	Name *string `form:"name,omitempty" json:"name,omitempty"`
 }
 
+// GetApiReportsPeersParams defines parameters for GetApiReportsPeers.
+type GetApiReportsPeersParams struct {
+	// Format Format of the report, defaults to csv
+	Format *GetApiReportsPeersParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Group Only include peers of these group IDs
+	Group *[]string `form:"group,omitempty" json:"group,omitempty"`
+
+	// Os Only include peers whose operating system contains the value
+	Os *string `form:"os,omitempty" json:"os,omitempty"`
+
+	// UserId Only include peers of the user
+	UserId *string `form:"user_id,omitempty" json:"user_id,omitempty"`
+
+	// Connected Only include connected or disconnected peers
+	Connected *bool `form:"connected,omitempty" json:"connected,omitempty"`
+
+	// Compliant Only include peers that pass or fail their posture checks
+	Compliant *bool `form:"compliant,omitempty" json:"compliant,omitempty"`
+
+	// ApprovalState Only include approved or pending peers
+	ApprovalState *GetApiReportsPeersParamsApprovalState `form:"approval_state,omitempty" json:"approval_state,omitempty"`
+
+	// LastSeenBefore Only include peers that haven't been seen since the time
+	LastSeenBefore *time.Time `form:"last_seen_before,omitempty" json:"last_seen_before,omitempty"`
+}
+
+// GetApiReportsPeersParamsFormat defines parameters for GetApiReportsPeers.
+type GetApiReportsPeersParamsFormat string
+
+// GetApiReportsPeersParamsApprovalState defines parameters for GetApiReportsPeers.
+type GetApiReportsPeersParamsApprovalState string
+
+// GetApiReportsUsersParams defines parameters for GetApiReportsUsers.
+type GetApiReportsUsersParams struct {
+	// Format Format of the report, defaults to csv
+	Format *GetApiReportsUsersParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Role Only include users with the role
+	Role *string `form:"role,omitempty" json:"role,omitempty"`
+
+	// ServiceUser Only include service users or regular users
+	ServiceUser *bool `form:"service_user,omitempty" json:"service_user,omitempty"`
+
+	// Blocked Only include blocked or active users
+	Blocked *bool `form:"blocked,omitempty" json:"blocked,omitempty"`
+
+	// InactiveSince Only include users that haven't logged in since the time
+	InactiveSince *time.Time `form:"inactive_since,omitempty" json:"inactive_since,omitempty"`
+}
+
+// GetApiReportsUsersParamsFormat defines parameters for GetApiReportsUsers.
+type GetApiReportsUsersParamsFormat string
+
 // GetApiUsersParams defines parameters for GetApiUsers.
 type GetApiUsersParams struct {
 	// ServiceUser Filters users and returns either regular users or service users
 	ServiceUser *bool `form:"service_user,omitempty" json:"service_user,omitempty"`
 }
 
+// PostApiAccessRequestsJSONRequestBody defines body for PostApiAccessRequests for application/json ContentType.
+type PostApiAccessRequestsJSONRequestBody = AccessRequestRequest
+
+// PutApiAccessRequestsSettingsJSONRequestBody defines body for PutApiAccessRequestsSettings for application/json ContentType.
+type PutApiAccessRequestsSettingsJSONRequestBody = AccessRequestSettings
+
+// PostApiAccessRequestsRequestIdApproveJSONRequestBody defines body for PostApiAccessRequestsRequestIdApprove for application/json ContentType.
+type PostApiAccessRequestsRequestIdApproveJSONRequestBody = AccessRequestDecision
+
+// PostApiAccessRequestsRequestIdRejectJSONRequestBody defines body for PostApiAccessRequestsRequestIdReject for application/json ContentType.
+type PostApiAccessRequestsRequestIdRejectJSONRequestBody = AccessRequestDecision
+
 // PutApiAccountsAccountIdJSONRequestBody defines body for PutApiAccountsAccountId for application/json ContentType.
 type PutApiAccountsAccountIdJSONRequestBody = AccountRequest
 
+// PostApiDeclarativeApplyJSONRequestBody defines body for PostApiDeclarativeApply for application/json ContentType.
+type PostApiDeclarativeApplyJSONRequestBody = DeclarativeConfig
+
+// PostApiDeclarativePlanJSONRequestBody defines body for PostApiDeclarativePlan for application/json ContentType.
+type PostApiDeclarativePlanJSONRequestBody = DeclarativeConfig
+
+// PostApiDiscoveredSubnetsSubnetIdApproveJSONRequestBody defines body for PostApiDiscoveredSubnetsSubnetIdApprove for application/json ContentType.
+type PostApiDiscoveredSubnetsSubnetIdApproveJSONRequestBody = DiscoveredSubnetApproval
+
 // PostApiDnsNameserversJSONRequestBody defines body for PostApiDnsNameservers for application/json ContentType.
 type PostApiDnsNameserversJSONRequestBody = NameserverGroupRequest
 
@@ -2306,6 +3541,9 @@ type PostApiNetworksNetworkIdRoutersJSONRequestBody = NetworkRouterRequest
 // PutApiNetworksNetworkIdRoutersRouterIdJSONRequestBody defines body for PutApiNetworksNetworkIdRoutersRouterId for application/json ContentType.
 type PutApiNetworksNetworkIdRoutersRouterIdJSONRequestBody = NetworkRouterRequest
 
+// PutApiPeerApprovalsSettingsJSONRequestBody defines body for PutApiPeerApprovalsSettings for application/json ContentType.
+type PutApiPeerApprovalsSettingsJSONRequestBody = PeerApprovalSettingsRequest
+
 // PutApiPeersPeerIdJSONRequestBody defines body for PutApiPeersPeerId for application/json ContentType.
 type PutApiPeersPeerIdJSONRequestBody = PeerRequest
 
@@ -2327,12 +3565,24 @@ type PostApiPoliciesJSONRequestBody = PolicyUpdate
 // PutApiPoliciesPolicyIdJSONRequestBody defines body for PutApiPoliciesPolicyId for application/json ContentType.
 type PutApiPoliciesPolicyIdJSONRequestBody = PolicyCreate
 
+// PostApiPortForwardsJSONRequestBody defines body for PostApiPortForwards for application/json ContentType.
+type PostApiPortForwardsJSONRequestBody = PortForwardRequest
+
+// PutApiPortForwardsPortForwardIdJSONRequestBody defines body for PutApiPortForwardsPortForwardId for application/json ContentType.
+type PutApiPortForwardsPortForwardIdJSONRequestBody = PortForwardRequest
+
 // PostApiPostureChecksJSONRequestBody defines body for PostApiPostureChecks for application/json ContentType.
 type PostApiPostureChecksJSONRequestBody = PostureCheckUpdate
 
 // PutApiPostureChecksPostureCheckIdJSONRequestBody defines body for PutApiPostureChecksPostureCheckId for application/json ContentType.
 type PutApiPostureChecksPostureCheckIdJSONRequestBody = PostureCheckUpdate
 
+// PostApiRolesJSONRequestBody defines body for PostApiRoles for application/json ContentType.
+type PostApiRolesJSONRequestBody = CustomRoleRequest
+
+// PutApiRolesRoleIdJSONRequestBody defines body for PutApiRolesRoleId for application/json ContentType.
+type PutApiRolesRoleIdJSONRequestBody = CustomRoleRequest
+
 // PostApiRoutesJSONRequestBody defines body for PostApiRoutes for application/json ContentType.
 type PostApiRoutesJSONRequestBody = RouteRequest
 
@@ -2348,6 +3598,15 @@ type PostApiSetupKeysJSONRequestBody = CreateSetupKeyRequest
 // PutApiSetupKeysKeyIdJSONRequestBody defines body for PutApiSetupKeysKeyId for application/json ContentType.
 type PutApiSetupKeysKeyIdJSONRequestBody = SetupKeyRequest
 
+// PostApiTenantsJSONRequestBody defines body for PostApiTenants for application/json ContentType.
+type PostApiTenantsJSONRequestBody = TenantRequest
+
+// PostApiTenantsTemplatesJSONRequestBody defines body for PostApiTenantsTemplates for application/json ContentType.
+type PostApiTenantsTemplatesJSONRequestBody = TenantTemplateRequest
+
+// PutApiTenantsTemplatesTemplateIdJSONRequestBody defines body for PutApiTenantsTemplatesTemplateId for application/json ContentType.
+type PutApiTenantsTemplatesTemplateIdJSONRequestBody = TenantTemplateRequest
+
 // PostApiUsersJSONRequestBody defines body for PostApiUsers for application/json ContentType.
 type PostApiUsersJSONRequestBody = UserCreateRequest
 
@@ -245,6 +245,11 @@ func NewUserRoleNotFoundError(role string) error {
 	return Errorf(NotFound, "user role: %s not found", role)
 }
 
+// NewCustomRoleNotFoundError creates a new Error with NotFound type for a missing custom role
+func NewCustomRoleNotFoundError(roleID string) error {
+	return Errorf(NotFound, "custom role: %s not found", roleID)
+}
+
 func NewOperationNotFoundError(operation operations.Operation) error {
 	return Errorf(NotFound, "operation: %s not found", operation)
 }
@@ -258,6 +263,51 @@ func NewZoneNotFoundError(zoneID string) error {
 	return Errorf(NotFound, "zone: %s not found", zoneID)
 }
 
+// NewSCIMTokenNotFoundError creates a new Error with NotFound type for a missing SCIM token.
+func NewSCIMTokenNotFoundError() error {
+	return Errorf(NotFound, "SCIM token not found")
+}
+
+// NewPeerApprovalConfigNotFoundError creates a new Error with NotFound type for a missing peer approval configuration.
+func NewPeerApprovalConfigNotFoundError() error {
+	return Errorf(NotFound, "peer approval config not found")
+}
+
+// NewTenantNotFoundError creates a new Error with NotFound type for a missing tenant.
+func NewTenantNotFoundError(accountID string) error {
+	return Errorf(NotFound, "tenant: %s not found", accountID)
+}
+
+// NewTenantTemplateNotFoundError creates a new Error with NotFound type for a missing tenant template.
+func NewTenantTemplateNotFoundError(templateID string) error {
+	return Errorf(NotFound, "tenant template: %s not found", templateID)
+}
+
+// NewAccessRequestNotFoundError creates a new Error with NotFound type for a missing access request.
+func NewAccessRequestNotFoundError(requestID string) error {
+	return Errorf(NotFound, "access request: %s not found", requestID)
+}
+
+// NewAccessRequestSettingsNotFoundError creates a new Error with NotFound type for missing access request settings.
+func NewAccessRequestSettingsNotFoundError() error {
+	return Errorf(NotFound, "access request settings not found")
+}
+
+// NewDiscoveredSubnetNotFoundError creates a new Error with NotFound type for a missing discovered subnet.
+func NewDiscoveredSubnetNotFoundError(subnetID string) error {
+	return Errorf(NotFound, "discovered subnet: %s not found", subnetID)
+}
+
+// NewPortForwardNotFoundError creates a new Error with NotFound type for a missing port forward.
+func NewPortForwardNotFoundError(portForwardID string) error {
+	return Errorf(NotFound, "port forward: %s not found", portForwardID)
+}
+
+// NewObjectVersionNotFoundError creates a new Error with NotFound type for a missing version of a versioned object.
+func NewObjectVersionNotFoundError(objectID string, version int) error {
+	return Errorf(NotFound, "version %d of %s not found", version, objectID)
+}
+
 // NewDNSRecordNotFoundError creates a new Error with NotFound type for a missing dns record.
 func NewDNSRecordNotFoundError(recordID string) error {
 	return Errorf(NotFound, "dns record: %s not found", recordID)
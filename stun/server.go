@@ -13,6 +13,7 @@ import (
 
 	"github.com/netbirdio/netbird/formatter"
 	"github.com/pion/stun/v3"
+	"github.com/pion/turn/v3"
 )
 
 // ErrServerClosed is returned by Listen when the server is shut down gracefully.
@@ -23,11 +24,18 @@ var ErrNoListeners = errors.New("stun: no listeners configured")
 
 // Server implements a STUN server that responds to binding requests
 // with the client's reflexive transport address.
+// With a TURN config it also relays traffic for authenticated TURN clients.
 type Server struct {
 	conns    []*net.UDPConn
 	logger   *log.Entry
 	logLevel log.Level
 
+	turnCfg    *TURNConfig
+	turnServer *turn.Server
+	mu         sync.Mutex
+	closed     bool
+	done       chan struct{}
+
 	wg sync.WaitGroup
 }
 
@@ -55,6 +63,7 @@ func NewServer(conns []*net.UDPConn, logLevel string) *Server {
 		conns:    conns,
 		logger:   logger,
 		logLevel: level,
+		done:     make(chan struct{}),
 	}
 }
 
@@ -62,6 +71,10 @@ func NewServer(conns []*net.UDPConn, logLevel string) *Server {
 // Returns ErrServerClosed when shut down gracefully via Shutdown.
 // Returns ErrNoListeners if no UDP connections were provided.
 func (s *Server) Listen() error {
+	if s.turnCfg != nil {
+		return s.listenTURN()
+	}
+
 	if len(s.conns) == 0 {
 		return ErrNoListeners
 	}
@@ -158,12 +171,36 @@ func (s *Server) Shutdown() error {
 
 	var merr *multierror.Error
 
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+	}
+	turnServer := s.turnServer
+	s.mu.Unlock()
+
+	if turnServer != nil {
+		// the TURN server closes the UDP connections and the TCP listeners
+		if err := turnServer.Close(); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("close TURN server: %w", err))
+		}
+		return nberrors.FormatErrorOrNil(merr)
+	}
+
 	for _, conn := range s.conns {
 		if err := conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
 			merr = multierror.Append(merr, fmt.Errorf("close STUN UDP connection: %w", err))
 		}
 	}
 
+	if s.turnCfg != nil {
+		for _, listener := range s.turnCfg.Listeners {
+			if err := listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+				merr = multierror.Append(merr, fmt.Errorf("close TURN listener: %w", err))
+			}
+		}
+	}
+
 	// Wait for all readLoops to finish
 	s.wg.Wait()
 	return nberrors.FormatErrorOrNil(merr)
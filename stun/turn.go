@@ -0,0 +1,200 @@
+package stun
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+
+	"github.com/pion/logging"
+	"github.com/pion/transport/v3/stdnet"
+	"github.com/pion/turn/v3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultTURNRealm is the realm announced to the clients if none is configured
+	DefaultTURNRealm = "netbird"
+	// DefaultTURNMinPort and DefaultTURNMaxPort define the default port range of the relay allocations
+	DefaultTURNMinPort = 49152
+	DefaultTURNMaxPort = 65535
+)
+
+// defaultDeniedPeers are the private and shared address ranges the clients may not relay to unless allowed by
+// TURNConfig.AllowedPeers, so the relay can't be used to reach the network of the relay host or the NetBird overlay
+var defaultDeniedPeers = []netip.Prefix{
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("fc00::/7"),
+}
+
+// TURNConfig enables the TURN (RFC 8656) relay on the embedded server.
+//
+// Clients authenticate with time-limited credentials: the username is the unix expiry timestamp and the password is
+// the base64 encoded HMAC-SHA1 of the username, signed with the secret shared with the Management service
+// (TURNConfig.Secret with TimeBasedCredentials enabled).
+type TURNConfig struct {
+	// Realm is the authentication realm announced to the clients
+	Realm string
+	// Secret is the shared secret the Management service signs the TURN credentials with
+	Secret string
+	// RelayIP is the public IPv4 address the relayed transport addresses are allocated on. The relay allocates
+	// IPv4 transport addresses only.
+	RelayIP net.IP
+	// MinPort and MaxPort limit the ports of the relayed transport addresses
+	MinPort uint16
+	MaxPort uint16
+	// Listeners accept TURN over TCP or TLS in addition to the UDP connections of the server
+	Listeners []net.Listener
+	// AllowedPeers are networks the clients may relay to although they are denied by default, e.g. a private
+	// network the relay is deployed to serve. Loopback, multicast and link-local addresses are always denied.
+	AllowedPeers []netip.Prefix
+	// DeniedPeers are networks the clients may not relay to in addition to the default denied networks. They take
+	// precedence over AllowedPeers.
+	DeniedPeers []netip.Prefix
+}
+
+func (c TURNConfig) validate() error {
+	if c.Secret == "" {
+		return errors.New("TURN secret is required")
+	}
+	if c.RelayIP == nil || c.RelayIP.IsUnspecified() {
+		return errors.New("TURN relay IP is required")
+	}
+	if c.RelayIP.To4() == nil {
+		return fmt.Errorf("TURN relay IP %s is not an IPv4 address, IPv6 relay allocations are not supported", c.RelayIP)
+	}
+	if c.MinPort == 0 || c.MinPort > c.MaxPort {
+		return fmt.Errorf("invalid TURN relay port range %d-%d", c.MinPort, c.MaxPort)
+	}
+	return nil
+}
+
+// NewTURNServer creates a server that answers STUN binding requests and relays traffic for authenticated TURN
+// clients. The UDP connections and the listeners of the TURN config are closed on Shutdown.
+func NewTURNServer(conns []*net.UDPConn, cfg TURNConfig, logLevel string) (*Server, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Realm == "" {
+		cfg.Realm = DefaultTURNRealm
+	}
+
+	s := NewServer(conns, logLevel)
+	s.turnCfg = &cfg
+	return s, nil
+}
+
+// listenTURN runs the TURN server until Shutdown is called
+func (s *Server) listenTURN() error {
+	if len(s.conns) == 0 && len(s.turnCfg.Listeners) == 0 {
+		return ErrNoListeners
+	}
+
+	loggerFactory := s.pionLoggerFactory()
+	transportNet, err := stdnet.NewNet()
+	if err != nil {
+		return fmt.Errorf("create TURN network: %w", err)
+	}
+
+	relayGenerator := &turn.RelayAddressGeneratorPortRange{
+		RelayAddress: s.turnCfg.RelayIP,
+		Address:      "0.0.0.0",
+		MinPort:      s.turnCfg.MinPort,
+		MaxPort:      s.turnCfg.MaxPort,
+		Net:          transportNet,
+	}
+
+	serverCfg := turn.ServerConfig{
+		Realm:         s.turnCfg.Realm,
+		AuthHandler:   turn.NewLongTermAuthHandler(s.turnCfg.Secret, loggerFactory.NewLogger("turn-auth")),
+		LoggerFactory: loggerFactory,
+	}
+
+	for _, conn := range s.conns {
+		s.logger.Infof("TURN server listening on udp %s", conn.LocalAddr())
+		serverCfg.PacketConnConfigs = append(serverCfg.PacketConnConfigs, turn.PacketConnConfig{
+			PacketConn:            conn,
+			RelayAddressGenerator: relayGenerator,
+			PermissionHandler:     s.turnCfg.permitPeer,
+		})
+	}
+
+	for _, listener := range s.turnCfg.Listeners {
+		s.logger.Infof("TURN server listening on tcp %s", listener.Addr())
+		serverCfg.ListenerConfigs = append(serverCfg.ListenerConfigs, turn.ListenerConfig{
+			Listener:              listener,
+			RelayAddressGenerator: relayGenerator,
+			PermissionHandler:     s.turnCfg.permitPeer,
+		})
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrServerClosed
+	}
+	turnServer, err := turn.NewServer(serverCfg)
+	if err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("create TURN server: %w", err)
+	}
+	s.turnServer = turnServer
+	s.mu.Unlock()
+
+	s.logger.Infof("TURN relay allocations on %s ports %d-%d", s.turnCfg.RelayIP, s.turnCfg.MinPort, s.turnCfg.MaxPort)
+
+	<-s.done
+	return ErrServerClosed
+}
+
+func (s *Server) pionLoggerFactory() logging.LoggerFactory {
+	factory := logging.NewDefaultLoggerFactory()
+	factory.Writer = s.logger.Logger.Out
+
+	switch s.logLevel {
+	case log.TraceLevel:
+		factory.DefaultLogLevel = logging.LogLevelTrace
+	case log.DebugLevel:
+		factory.DefaultLogLevel = logging.LogLevelDebug
+	case log.InfoLevel:
+		factory.DefaultLogLevel = logging.LogLevelInfo
+	case log.WarnLevel:
+		factory.DefaultLogLevel = logging.LogLevelWarn
+	default:
+		factory.DefaultLogLevel = logging.LogLevelError
+	}
+	return factory
+}
+
+// permitPeer prevents authenticated clients from using the relay to reach the server host, addresses that are not
+// routable and the denied networks
+func (c *TURNConfig) permitPeer(_ net.Addr, peerIP net.IP) bool {
+	addr, ok := netip.AddrFromSlice(peerIP)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+
+	if addr.IsLoopback() || addr.IsUnspecified() || addr.IsMulticast() || addr.IsLinkLocalUnicast() {
+		return false
+	}
+	if containsAddr(c.DeniedPeers, addr) {
+		return false
+	}
+	if containsAddr(c.AllowedPeers, addr) {
+		return true
+	}
+	return !containsAddr(defaultDeniedPeers, addr)
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
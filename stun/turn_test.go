@@ -0,0 +1,185 @@
+package stun
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/pion/turn/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTURNSecret = "test-secret"
+
+// createTestTURNServer creates a TURN server listening on a random port and starts it.
+// The server is shut down on test cleanup.
+func createTestTURNServer(t *testing.T) *net.UDPAddr {
+	t.Helper()
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 0})
+	require.NoError(t, err)
+
+	server, err := NewTURNServer([]*net.UDPConn{conn}, TURNConfig{
+		Secret:  testTURNSecret,
+		RelayIP: net.ParseIP("127.0.0.1"),
+		MinPort: 40000,
+		MaxPort: 40100,
+	}, "debug")
+	require.NoError(t, err)
+
+	serverErrCh := make(chan error, 1)
+	go func() {
+		serverErrCh <- server.Listen()
+	}()
+	t.Cleanup(func() {
+		require.NoError(t, server.Shutdown())
+		select {
+		case err := <-serverErrCh:
+			assert.ErrorIs(t, err, ErrServerClosed)
+		case <-time.After(2 * time.Second):
+			t.Error("TURN server did not stop")
+		}
+	})
+
+	serverAddr := conn.LocalAddr().(*net.UDPAddr)
+	waitForServerReady(t, serverAddr, 2*time.Second)
+	return serverAddr
+}
+
+func createTestTURNClient(t *testing.T, serverAddr *net.UDPAddr, username, password string) *turn.Client {
+	t.Helper()
+	clientConn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientConn.Close() })
+
+	client, err := turn.NewClient(&turn.ClientConfig{
+		STUNServerAddr: serverAddr.String(),
+		TURNServerAddr: serverAddr.String(),
+		Username:       username,
+		Password:       password,
+		Realm:          DefaultTURNRealm,
+		Conn:           clientConn,
+		RTO:            100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	require.NoError(t, client.Listen())
+	return client
+}
+
+func TestTURNServer_Allocate(t *testing.T) {
+	serverAddr := createTestTURNServer(t)
+
+	username, password, err := turn.GenerateLongTermCredentials(testTURNSecret, time.Minute)
+	require.NoError(t, err)
+	client := createTestTURNClient(t, serverAddr, username, password)
+
+	relayConn, err := client.Allocate()
+	require.NoError(t, err)
+	defer relayConn.Close()
+
+	relayAddr := relayConn.LocalAddr().(*net.UDPAddr)
+	assert.True(t, relayAddr.IP.Equal(net.ParseIP("127.0.0.1")), "relay address should use the configured relay IP")
+	assert.GreaterOrEqual(t, relayAddr.Port, 40000)
+	assert.LessOrEqual(t, relayAddr.Port, 40100)
+
+	// the relay must not be usable to reach the server host itself
+	err = client.CreatePermission(&net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1234})
+	assert.Error(t, err, "permission for a loopback peer should be rejected")
+}
+
+func TestTURNServer_RejectsInvalidCredentials(t *testing.T) {
+	serverAddr := createTestTURNServer(t)
+
+	t.Run("wrong secret", func(t *testing.T) {
+		username, password, err := turn.GenerateLongTermCredentials("other-secret", time.Minute)
+		require.NoError(t, err)
+		client := createTestTURNClient(t, serverAddr, username, password)
+
+		_, err = client.Allocate()
+		assert.Error(t, err)
+	})
+
+	t.Run("expired credentials", func(t *testing.T) {
+		username, password, err := turn.GenerateLongTermCredentials(testTURNSecret, -time.Minute)
+		require.NoError(t, err)
+		client := createTestTURNClient(t, serverAddr, username, password)
+
+		_, err = client.Allocate()
+		assert.Error(t, err)
+	})
+}
+
+func TestTURNServer_ShutdownBeforeListen(t *testing.T) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 0})
+	require.NoError(t, err)
+
+	server, err := NewTURNServer([]*net.UDPConn{conn}, TURNConfig{
+		Secret:  testTURNSecret,
+		RelayIP: net.ParseIP("127.0.0.1"),
+		MinPort: DefaultTURNMinPort,
+		MaxPort: DefaultTURNMaxPort,
+	}, "info")
+	require.NoError(t, err)
+
+	require.NoError(t, server.Shutdown())
+	assert.ErrorIs(t, server.Listen(), ErrServerClosed)
+}
+
+func TestTURNConfig_Validate(t *testing.T) {
+	valid := TURNConfig{
+		Secret:  testTURNSecret,
+		RelayIP: net.ParseIP("192.0.2.1"),
+		MinPort: DefaultTURNMinPort,
+		MaxPort: DefaultTURNMaxPort,
+	}
+	require.NoError(t, valid.validate())
+
+	tests := []struct {
+		name   string
+		modify func(cfg *TURNConfig)
+	}{
+		{name: "missing secret", modify: func(cfg *TURNConfig) { cfg.Secret = "" }},
+		{name: "missing relay IP", modify: func(cfg *TURNConfig) { cfg.RelayIP = nil }},
+		{name: "unspecified relay IP", modify: func(cfg *TURNConfig) { cfg.RelayIP = net.IPv4zero }},
+		{name: "IPv6 relay IP", modify: func(cfg *TURNConfig) { cfg.RelayIP = net.ParseIP("2001:db8::1") }},
+		{name: "zero min port", modify: func(cfg *TURNConfig) { cfg.MinPort = 0 }},
+		{name: "inverted port range", modify: func(cfg *TURNConfig) { cfg.MinPort, cfg.MaxPort = 50000, 40000 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.modify(&cfg)
+			assert.Error(t, cfg.validate())
+		})
+	}
+}
+
+func TestTURNConfig_PermitPeer(t *testing.T) {
+	cfg := &TURNConfig{}
+	assert.True(t, cfg.permitPeer(nil, net.ParseIP("192.0.2.1")))
+	assert.True(t, cfg.permitPeer(nil, net.ParseIP("2001:db8::1")))
+	assert.False(t, cfg.permitPeer(nil, net.ParseIP("127.0.0.1")))
+	assert.False(t, cfg.permitPeer(nil, net.ParseIP("::1")))
+	assert.False(t, cfg.permitPeer(nil, net.ParseIP("0.0.0.0")))
+	assert.False(t, cfg.permitPeer(nil, net.ParseIP("224.0.0.1")))
+	assert.False(t, cfg.permitPeer(nil, net.ParseIP("169.254.1.1")))
+	assert.False(t, cfg.permitPeer(nil, net.ParseIP("10.1.2.3")), "private networks are denied by default")
+	assert.False(t, cfg.permitPeer(nil, net.ParseIP("172.20.0.1")))
+	assert.False(t, cfg.permitPeer(nil, net.ParseIP("192.168.1.1")))
+	assert.False(t, cfg.permitPeer(nil, net.ParseIP("100.64.0.5")), "the NetBird overlay is denied by default")
+	assert.False(t, cfg.permitPeer(nil, net.ParseIP("fd00::1")))
+	assert.False(t, cfg.permitPeer(nil, net.ParseIP("::ffff:10.1.2.3")), "mapped addresses are denied like IPv4")
+
+	cfg = &TURNConfig{
+		AllowedPeers: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/16"), netip.MustParsePrefix("127.0.0.0/8")},
+		DeniedPeers:  []netip.Prefix{netip.MustParsePrefix("10.0.1.0/24"), netip.MustParsePrefix("198.51.100.0/24")},
+	}
+	assert.True(t, cfg.permitPeer(nil, net.ParseIP("10.0.0.1")), "allowed networks override the default")
+	assert.False(t, cfg.permitPeer(nil, net.ParseIP("10.0.1.1")), "denied networks take precedence")
+	assert.False(t, cfg.permitPeer(nil, net.ParseIP("10.1.0.1")))
+	assert.False(t, cfg.permitPeer(nil, net.ParseIP("198.51.100.1")))
+	assert.False(t, cfg.permitPeer(nil, net.ParseIP("127.0.0.1")), "loopback can't be allowed")
+}